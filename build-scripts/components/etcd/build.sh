@@ -0,0 +1,10 @@
+#!/bin/bash
+
+INSTALL="${1}/bin"
+
+mkdir -p "${INSTALL}"
+
+export GOTOOLCHAIN=local
+# # export GOEXPERIMENT=opensslcrypto
+./build.sh
+cp bin/etcd "${INSTALL}/etcd"
@@ -0,0 +1,10 @@
+#!/bin/bash
+
+VERSION="v1.17.1"
+DIR=$(realpath $(dirname "${0}"))
+
+CHARTS_PATH="$DIR/../../k8s/manifests/charts"
+
+cd "$CHARTS_PATH"
+
+helm pull --repo https://charts.jetstack.io cert-manager --version $VERSION
@@ -55,6 +55,10 @@ CONTOUR_CHART_VERSION = "17.0.4"
 METALLB_REPO = "https://metallb.github.io/metallb"
 METALLB_CHART_VERSION = "0.14.8"
 
+# cert-manager Helm repository and chart version
+CERT_MANAGER_REPO = "https://charts.jetstack.io"
+CERT_MANAGER_CHART_VERSION = "v1.17.1"
+
 
 def is_valid_version(pinned_ver: None | Version) -> Callable[[None | Version], bool]:
     """filter function to check if version is valid
@@ -162,6 +166,11 @@ def pull_metallb_chart() -> None:
     util.helm_pull("metallb", METALLB_REPO, METALLB_CHART_VERSION, CHARTS)
 
 
+def pull_cert_manager_chart() -> None:
+    LOG.info("Pulling cert-manager chart @ %s", CERT_MANAGER_CHART_VERSION)
+    util.helm_pull("cert-manager", CERT_MANAGER_REPO, CERT_MANAGER_CHART_VERSION, CHARTS)
+
+
 def update_component_versions(dry_run: bool):
     for component, get_version in [
         ("kubernetes", get_kubernetes_version),
@@ -182,6 +191,7 @@ def update_component_versions(dry_run: bool):
     for component, pull_helm_chart in [
         ("bitnami/contour", pull_contour_chart),
         ("metallb", pull_metallb_chart),
+        ("cert-manager", pull_cert_manager_chart),
     ]:
         LOG.info("Updating chart for %s", component)
         if not dry_run:
@@ -0,0 +1,4 @@
+// Package apiv1alpha contains k8sd API messages that are not yet part of the stable
+// github.com/canonical/k8s-snap-api/api/v1 package. Messages in this package may change
+// without notice and will be promoted to the stable API once they have settled.
+package apiv1alpha
@@ -0,0 +1,32 @@
+package apiv1alpha
+
+// GetAPIServerProxyStatusRPC is the path for the GetAPIServerProxyStatus RPC.
+const GetAPIServerProxyStatusRPC = "k8sd/node/apiserver-proxy"
+
+// APIServerProxyEndpoint is the state of a kube-apiserver endpoint of the k8s-apiserver-proxy.
+type APIServerProxyEndpoint struct {
+	// Address is the address of the kube-apiserver endpoint.
+	Address string `json:"address" yaml:"address"`
+	// Active is true if the endpoint passed the last health check and accepts new connections.
+	Active bool `json:"active" yaml:"active"`
+	// Draining is true if the endpoint was removed and its open connections are being drained.
+	Draining bool `json:"draining,omitempty" yaml:"draining,omitempty"`
+	// Connections is the number of open connections to the endpoint.
+	Connections int `json:"connections" yaml:"connections"`
+	// BytesSent is the number of bytes forwarded to the endpoint. The bytes are counted when a connection is closed.
+	BytesSent uint64 `json:"bytes-sent" yaml:"bytes-sent"`
+	// BytesReceived is the number of bytes forwarded from the endpoint. The bytes are counted when a connection is closed.
+	BytesReceived uint64 `json:"bytes-received" yaml:"bytes-received"`
+	// DialErrors is the number of failed connection attempts to the endpoint.
+	DialErrors uint64 `json:"dial-errors" yaml:"dial-errors"`
+	// Deactivations is the number of times the endpoint was deactivated after a failed connection or health check.
+	Deactivations uint64 `json:"deactivations" yaml:"deactivations"`
+}
+
+// GetAPIServerProxyStatusResponse is the response message for the GetAPIServerProxyStatus RPC.
+type GetAPIServerProxyStatusResponse struct {
+	// Endpoints are the kube-apiserver endpoints that the k8s-apiserver-proxy of the node forwards connections to.
+	Endpoints []APIServerProxyEndpoint `json:"endpoints" yaml:"endpoints"`
+	// Refreshes is the number of times the list of endpoints was updated since the proxy started.
+	Refreshes uint64 `json:"refreshes" yaml:"refreshes"`
+}
@@ -0,0 +1,26 @@
+package apiv1alpha
+
+import apiv1 "github.com/canonical/k8s-snap-api/api/v1"
+
+// ApplyClusterConfigRPC is the path for the ApplyClusterConfig RPC.
+const ApplyClusterConfigRPC = "k8sd/cluster/config/apply"
+
+// ApplyClusterConfigRequest is the request message for the ApplyClusterConfig RPC.
+type ApplyClusterConfigRequest struct {
+	// Config is the desired cluster configuration.
+	Config apiv1.UserFacingClusterConfig `json:"config"`
+	// DryRun computes the changes without applying them.
+	DryRun bool `json:"dry-run,omitempty"`
+	// Prune resets options that are not set in Config to their default values and removes
+	// annotations that are not set in Config.
+	Prune bool `json:"prune,omitempty"`
+}
+
+// ApplyClusterConfigResponse is the response message for the ApplyClusterConfig RPC.
+type ApplyClusterConfigResponse struct {
+	// Changes is the list of options that are changed by the request, sorted by key.
+	// Old values are from the current configuration, new values from the merged configuration.
+	Changes []ClusterConfigChange `json:"changes" yaml:"changes"`
+	// Applied is true if the changes were applied.
+	Applied bool `json:"applied" yaml:"applied"`
+}
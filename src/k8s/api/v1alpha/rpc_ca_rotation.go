@@ -0,0 +1,48 @@
+package apiv1alpha
+
+import "time"
+
+// CARotationRPC is the path for the RotateCA and GetCARotation RPCs.
+const CARotationRPC = "k8sd/certificates/ca-rotation"
+
+// CARotationNode is the progress of a node in a CA rotation.
+type CARotationNode struct {
+	// Node is the name of the node.
+	Node string `json:"node" yaml:"node"`
+	// Phase is the last phase that was applied on the node. Empty if the node did not apply any phase yet.
+	Phase string `json:"phase,omitempty" yaml:"phase,omitempty"`
+	// Message contains information about the progress of the node, e.g. the error of a failed attempt to apply the phase.
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+	// UpdatedAt is the time the progress of the node was last updated.
+	UpdatedAt time.Time `json:"updated-at,omitempty" yaml:"updated-at,omitempty"`
+}
+
+// CARotation is the progress of a rotation of the Kubernetes CAs.
+type CARotation struct {
+	// Phase is the current phase of the rotation, one of "trust", "reissue", "finalize" or "completed".
+	Phase string `json:"phase" yaml:"phase"`
+	// StartedAt is the time the rotation started.
+	StartedAt time.Time `json:"started-at" yaml:"started-at"`
+	// UpdatedAt is the time the rotation moved to the current phase.
+	UpdatedAt time.Time `json:"updated-at" yaml:"updated-at"`
+	// Nodes is the progress of each cluster node, sorted by node name.
+	Nodes []CARotationNode `json:"nodes" yaml:"nodes"`
+}
+
+// RotateCARequest is the request message for the RotateCA RPC.
+type RotateCARequest struct {
+	// ExpirationSeconds is the lifetime of the new CAs in seconds.
+	ExpirationSeconds int `json:"expiration-seconds,omitempty"`
+}
+
+// RotateCAResponse is the response message for the RotateCA RPC.
+type RotateCAResponse struct {
+	// Rotation is the started rotation, or the rotation in progress if a rotation was already started.
+	Rotation CARotation `json:"rotation"`
+}
+
+// GetCARotationResponse is the response message for the GetCARotation RPC.
+type GetCARotationResponse struct {
+	// Rotation is the latest rotation of the Kubernetes CAs. Nil if the CAs were never rotated.
+	Rotation *CARotation `json:"rotation,omitempty"`
+}
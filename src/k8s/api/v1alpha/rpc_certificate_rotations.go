@@ -0,0 +1,26 @@
+package apiv1alpha
+
+import "time"
+
+// ListCertificateRotationsRPC is the path for the ListCertificateRotations RPC.
+const ListCertificateRotationsRPC = "k8sd/certificates/rotations"
+
+// CertificateRotation is the progress of the automatic certificate rotation on a node.
+type CertificateRotation struct {
+	// Node is the name of the node.
+	Node string `json:"node" yaml:"node"`
+	// State is the state of the rotation, one of "waiting", "renewing", "restarting", "completed" or "failed".
+	State string `json:"state" yaml:"state"`
+	// Certificates are the names of the certificates that are being rotated, or were rotated last.
+	Certificates []string `json:"certificates,omitempty" yaml:"certificates,omitempty"`
+	// Message contains information about the rotation, e.g. the service being restarted or the error of a failed rotation.
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+	// UpdatedAt is the time the rotation progress was last updated.
+	UpdatedAt time.Time `json:"updated-at" yaml:"updated-at"`
+}
+
+// ListCertificateRotationsResponse is the response message for the ListCertificateRotations RPC.
+type ListCertificateRotationsResponse struct {
+	// Rotations is the certificate rotation progress of each node that rotated its certificates, sorted by node name.
+	Rotations []CertificateRotation `json:"rotations" yaml:"rotations"`
+}
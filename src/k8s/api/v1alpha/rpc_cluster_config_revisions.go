@@ -0,0 +1,66 @@
+package apiv1alpha
+
+import "time"
+
+// ListClusterConfigRevisionsRPC is the path for the ListClusterConfigRevisions RPC.
+const ListClusterConfigRevisionsRPC = "k8sd/cluster/config/revisions"
+
+// DiffClusterConfigRevisionRPC is the path for the DiffClusterConfigRevision RPC.
+const DiffClusterConfigRevisionRPC = "k8sd/cluster/config/revisions/diff"
+
+// RollbackClusterConfigRevisionRPC is the path for the RollbackClusterConfigRevision RPC.
+const RollbackClusterConfigRevisionRPC = "k8sd/cluster/config/revisions/rollback"
+
+// ClusterConfigRevision is a recorded revision of the cluster configuration.
+type ClusterConfigRevision struct {
+	// Revision is the sequence number of the revision.
+	Revision int64 `json:"revision" yaml:"revision"`
+	// Timestamp is the time the revision was recorded.
+	Timestamp time.Time `json:"timestamp" yaml:"timestamp"`
+	// Identity is the identity that requested the change.
+	Identity string `json:"identity" yaml:"identity"`
+}
+
+// ClusterConfigChange is a single difference between two cluster configurations.
+type ClusterConfigChange struct {
+	// Key is the dot-separated path of the option, e.g. "dns.cluster-domain".
+	Key string `json:"key" yaml:"key"`
+	// Old is the JSON encoded value of the option before the change. Old is empty if the option was not set.
+	Old string `json:"old,omitempty" yaml:"old,omitempty"`
+	// New is the JSON encoded value of the option after the change. New is empty if the option is not set.
+	New string `json:"new,omitempty" yaml:"new,omitempty"`
+}
+
+// ListClusterConfigRevisionsRequest is the request message for the ListClusterConfigRevisions RPC.
+type ListClusterConfigRevisionsRequest struct{}
+
+// ListClusterConfigRevisionsResponse is the response message for the ListClusterConfigRevisions RPC.
+type ListClusterConfigRevisionsResponse struct {
+	// Revisions is the list of recorded revisions, oldest first.
+	Revisions []ClusterConfigRevision `json:"revisions" yaml:"revisions"`
+}
+
+// DiffClusterConfigRevisionRequest is the request message for the DiffClusterConfigRevision RPC.
+type DiffClusterConfigRevisionRequest struct {
+	// Revision is the revision to compare against the current configuration.
+	Revision int64 `json:"revision"`
+}
+
+// DiffClusterConfigRevisionResponse is the response message for the DiffClusterConfigRevision RPC.
+type DiffClusterConfigRevisionResponse struct {
+	// Changes is the list of options that changed since the revision, sorted by key.
+	// Old values are from the revision, new values from the current configuration.
+	Changes []ClusterConfigChange `json:"changes" yaml:"changes"`
+}
+
+// RollbackClusterConfigRevisionRequest is the request message for the RollbackClusterConfigRevision RPC.
+type RollbackClusterConfigRevisionRequest struct {
+	// Revision is the revision to roll back to.
+	Revision int64 `json:"revision"`
+}
+
+// RollbackClusterConfigRevisionResponse is the response message for the RollbackClusterConfigRevision RPC.
+type RollbackClusterConfigRevisionResponse struct {
+	// Revision is the new revision that was recorded for the rollback.
+	Revision int64 `json:"revision" yaml:"revision"`
+}
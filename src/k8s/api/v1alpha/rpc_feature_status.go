@@ -0,0 +1,61 @@
+package apiv1alpha
+
+import (
+	"time"
+
+	apiv1 "github.com/canonical/k8s-snap-api/api/v1"
+)
+
+// GetFeatureStatusRPC is the path for the GetFeatureStatus RPC.
+const GetFeatureStatusRPC = "k8sd/features/status"
+
+// FeatureReconcile is a recorded reconcile attempt of a feature.
+type FeatureReconcile struct {
+	// StartedAt is the time the reconcile attempt started.
+	StartedAt time.Time `json:"started-at" yaml:"started-at"`
+	// FinishedAt is the time the reconcile attempt finished.
+	FinishedAt time.Time `json:"finished-at" yaml:"finished-at"`
+	// Result is the result of the reconcile attempt, one of "enabled", "disabled", "failed" or "waiting".
+	Result string `json:"result" yaml:"result"`
+	// Error is the error of a failed reconcile attempt, or the reason a feature is waiting.
+	Error string `json:"error,omitempty" yaml:"error,omitempty"`
+	// Version is the version of the feature.
+	Version string `json:"version,omitempty" yaml:"version,omitempty"`
+	// ValuesHash is a hash of the values of the Helm charts applied during the reconcile attempt.
+	ValuesHash string `json:"values-hash,omitempty" yaml:"values-hash,omitempty"`
+}
+
+// GetFeatureStatusRequest is the request message for the GetFeatureStatus RPC.
+type GetFeatureStatusRequest struct {
+	// Feature is the name of the feature, e.g. "ingress".
+	Feature string `json:"feature"`
+	// History requests the recorded reconcile attempts of the feature.
+	History bool `json:"history,omitempty"`
+}
+
+// GetFeatureStatusResponse is the response message for the GetFeatureStatus RPC.
+type GetFeatureStatusResponse struct {
+	// Status is the current status of the feature.
+	Status apiv1.FeatureStatus `json:"status" yaml:"status"`
+	// Waiting is true if the reconciliation of the feature is blocked by the features it depends on, or by the
+	// features that depend on it when it is being disabled. The status message names the feature that is waited for.
+	Waiting bool `json:"waiting,omitempty" yaml:"waiting,omitempty"`
+	// History is the list of recorded reconcile attempts, oldest first. History is only set if requested.
+	History []FeatureReconcile `json:"history,omitempty" yaml:"history,omitempty"`
+}
+
+// GetFeatureValuesRPC is the path for the GetFeatureValues RPC.
+const GetFeatureValuesRPC = "k8sd/features/values"
+
+// GetFeatureValuesRequest is the request message for the GetFeatureValues RPC.
+type GetFeatureValuesRequest struct {
+	// Feature is the name of the feature, e.g. "network".
+	Feature string `json:"feature"`
+}
+
+// GetFeatureValuesResponse is the response message for the GetFeatureValues RPC.
+type GetFeatureValuesResponse struct {
+	// Values are the effective Helm values that were applied by the last successful reconciliation of the feature,
+	// including the values overrides, keyed by chart name.
+	Values map[string]map[string]any `json:"values" yaml:"values"`
+}
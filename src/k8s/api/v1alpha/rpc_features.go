@@ -0,0 +1,20 @@
+package apiv1alpha
+
+// ListFeaturesRPC is the path for the ListFeatures RPC.
+const ListFeaturesRPC = "k8sd/features"
+
+// Feature is a feature that is reconciled by k8sd.
+type Feature struct {
+	// Name is the name of the feature, as used by "k8s enable" and "k8s disable".
+	Name string `json:"name" yaml:"name"`
+	// Builtin is true for the built-in features. The other features are plugins that are enabled through annotations.
+	Builtin bool `json:"builtin" yaml:"builtin"`
+	// Dependencies are the features that must be enabled before the feature can be enabled.
+	Dependencies []string `json:"dependencies,omitempty" yaml:"dependencies,omitempty"`
+}
+
+// ListFeaturesResponse is the response message for the ListFeatures RPC.
+type ListFeaturesResponse struct {
+	// Features are the features that are registered in k8sd, sorted by name.
+	Features []Feature `json:"features" yaml:"features"`
+}
@@ -0,0 +1,66 @@
+package apiv1alpha
+
+import "time"
+
+// IssueKubeconfigRPC is the path for the IssueKubeconfig RPC.
+const IssueKubeconfigRPC = "k8sd/kubeconfigs/issue"
+
+// ListKubeconfigsRPC is the path for the ListKubeconfigs RPC.
+const ListKubeconfigsRPC = "k8sd/kubeconfigs"
+
+// RevokeKubeconfigRPC is the path for the RevokeKubeconfig RPC.
+const RevokeKubeconfigRPC = "k8sd/kubeconfigs/revoke"
+
+// IssuedKubeconfig is a scoped kubeconfig that was issued by k8sd.
+type IssuedKubeconfig struct {
+	// ID identifies the kubeconfig, e.g. for revocation.
+	ID int64 `json:"id" yaml:"id"`
+	// Username is the user that the kubeconfig authenticates as.
+	Username string `json:"username" yaml:"username"`
+	// Groups are the groups that the kubeconfig authenticates as.
+	Groups []string `json:"groups" yaml:"groups"`
+	// IssuedAt is the time the kubeconfig was issued.
+	IssuedAt time.Time `json:"issued-at" yaml:"issued-at"`
+	// IssuedBy is the identity that requested the kubeconfig.
+	IssuedBy string `json:"issued-by" yaml:"issued-by"`
+	// ExpiresAt is the time the kubeconfig expires.
+	ExpiresAt time.Time `json:"expires-at" yaml:"expires-at"`
+}
+
+// IssueKubeconfigRequest is the request message for the IssueKubeconfig RPC.
+type IssueKubeconfigRequest struct {
+	// Server is the address of the kube-apiserver in the kubeconfig. Defaults to the address of the node.
+	Server string `json:"server,omitempty"`
+	// Username is the user that the kubeconfig authenticates as.
+	Username string `json:"username"`
+	// Groups are the groups that the kubeconfig authenticates as.
+	Groups []string `json:"groups,omitempty"`
+	// TTL is the lifetime of the kubeconfig. Zero means the maximum lifetime that is allowed by the cluster.
+	TTL time.Duration `json:"ttl,omitempty"`
+}
+
+// IssueKubeconfigResponse is the response message for the IssueKubeconfig RPC.
+type IssueKubeconfigResponse struct {
+	// KubeConfig is the issued kubeconfig.
+	KubeConfig string `json:"kubeconfig"`
+	// Issued is the record of the issued kubeconfig.
+	Issued IssuedKubeconfig `json:"issued"`
+}
+
+// ListKubeconfigsRequest is the request message for the ListKubeconfigs RPC.
+type ListKubeconfigsRequest struct{}
+
+// ListKubeconfigsResponse is the response message for the ListKubeconfigs RPC.
+type ListKubeconfigsResponse struct {
+	// Kubeconfigs are the issued kubeconfigs that were not revoked, including expired ones, oldest first.
+	Kubeconfigs []IssuedKubeconfig `json:"kubeconfigs" yaml:"kubeconfigs"`
+}
+
+// RevokeKubeconfigRequest is the request message for the RevokeKubeconfig RPC.
+type RevokeKubeconfigRequest struct {
+	// ID is the issued kubeconfig to revoke.
+	ID int64 `json:"id"`
+}
+
+// RevokeKubeconfigResponse is the response message for the RevokeKubeconfig RPC.
+type RevokeKubeconfigResponse struct{}
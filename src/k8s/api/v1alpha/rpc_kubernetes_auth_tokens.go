@@ -0,0 +1,53 @@
+package apiv1alpha
+
+import "time"
+
+// ListKubernetesAuthTokensRPC is the path for the ListKubernetesAuthTokens RPC.
+const ListKubernetesAuthTokensRPC = "k8sd/kubernetes/auth/tokens/list"
+
+// RevokeKubernetesAuthTokensRPC is the path for the RevokeKubernetesAuthTokens RPC.
+const RevokeKubernetesAuthTokensRPC = "k8sd/kubernetes/auth/tokens/revoke"
+
+// KubernetesAuthToken is a token that authenticates with kube-apiserver through k8sd.
+// The token itself is not known to k8sd after it was created.
+type KubernetesAuthToken struct {
+	// ID identifies the token, e.g. for revocation.
+	ID int64 `json:"id" yaml:"id"`
+	// Username is the user that the token authenticates as.
+	Username string `json:"username" yaml:"username"`
+	// Groups are the groups that the token authenticates as.
+	Groups []string `json:"groups" yaml:"groups"`
+	// ExpiresAt is the time the token expires. Nil if the token does not expire.
+	ExpiresAt *time.Time `json:"expires-at,omitempty" yaml:"expires-at,omitempty"`
+	// LastUsed is the time the token was last used, with a resolution of one minute. Nil if the token was never used.
+	LastUsed *time.Time `json:"last-used,omitempty" yaml:"last-used,omitempty"`
+	// IssuedBy is the identity that issued the token with a scoped kubeconfig. Empty for other tokens.
+	IssuedBy string `json:"issued-by,omitempty" yaml:"issued-by,omitempty"`
+}
+
+// ListKubernetesAuthTokensRequest is the request message for the ListKubernetesAuthTokens RPC.
+type ListKubernetesAuthTokensRequest struct {
+	// Username only lists the tokens of this user, if set.
+	Username string `json:"username,omitempty"`
+}
+
+// ListKubernetesAuthTokensResponse is the response message for the ListKubernetesAuthTokens RPC.
+type ListKubernetesAuthTokensResponse struct {
+	// Tokens are the tokens that were not revoked or removed after expiry, oldest first.
+	Tokens []KubernetesAuthToken `json:"tokens" yaml:"tokens"`
+}
+
+// RevokeKubernetesAuthTokensRequest is the request message for the RevokeKubernetesAuthTokens RPC.
+// Exactly one of ID and Username must be set.
+type RevokeKubernetesAuthTokensRequest struct {
+	// ID is the token to revoke.
+	ID int64 `json:"id,omitempty"`
+	// Username revokes all tokens of this user.
+	Username string `json:"username,omitempty"`
+}
+
+// RevokeKubernetesAuthTokensResponse is the response message for the RevokeKubernetesAuthTokens RPC.
+type RevokeKubernetesAuthTokensResponse struct {
+	// Revoked is the number of revoked tokens.
+	Revoked int64 `json:"revoked" yaml:"revoked"`
+}
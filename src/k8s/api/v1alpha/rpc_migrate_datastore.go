@@ -0,0 +1,56 @@
+package apiv1alpha
+
+import "time"
+
+// MigrateDatastoreRPC is the path for the MigrateDatastore and GetDatastoreMigration RPCs.
+const MigrateDatastoreRPC = "k8sd/datastore/migrate"
+
+// DatastoreMigration is the progress of a datastore migration.
+type DatastoreMigration struct {
+	// Phase is the current phase of the migration, one of "copying", "cutover", "verifying", "completed" or "failed".
+	Phase string `json:"phase" yaml:"phase"`
+	// Message contains information about the progress of the migration, e.g. the error of a failed migration.
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+	// StartedAt is the time the migration started.
+	StartedAt time.Time `json:"started-at" yaml:"started-at"`
+	// UpdatedAt is the time the migration moved to the current phase.
+	UpdatedAt time.Time `json:"updated-at" yaml:"updated-at"`
+	// Keys is the number of keys that were migrated.
+	Keys int64 `json:"keys,omitempty" yaml:"keys,omitempty"`
+	// SourceRevision is the revision of the source datastore that was migrated.
+	SourceRevision int64 `json:"source-revision,omitempty" yaml:"source-revision,omitempty"`
+	// TargetRevision is the revision of the target datastore after the migration.
+	TargetRevision int64 `json:"target-revision,omitempty" yaml:"target-revision,omitempty"`
+	// CutoverSynced is the number of keys that were written to the source datastore after the migration and synced
+	// to the target datastore once kube-apiserver switched to the target datastore.
+	CutoverSynced int64 `json:"cutover-synced,omitempty" yaml:"cutover-synced,omitempty"`
+	// CutoverConflicts is the number of keys that were changed in both datastores after the migration.
+	// The target datastore keeps its version of these keys.
+	CutoverConflicts int64 `json:"cutover-conflicts,omitempty" yaml:"cutover-conflicts,omitempty"`
+}
+
+// MigrateDatastoreRequest is the request message for the MigrateDatastore RPC.
+type MigrateDatastoreRequest struct {
+	// Type is the datastore type to migrate to. Only "external" is supported.
+	Type string `json:"type"`
+	// Servers is the list of etcd servers of the target datastore.
+	Servers []string `json:"datastore-servers"`
+	// CACert is the CA certificate of the target datastore.
+	CACert string `json:"datastore-ca-crt,omitempty"`
+	// ClientCert is the client certificate for the target datastore.
+	ClientCert string `json:"datastore-client-crt,omitempty"`
+	// ClientKey is the client key for the target datastore.
+	ClientKey string `json:"datastore-client-key,omitempty"`
+}
+
+// MigrateDatastoreResponse is the response message for the MigrateDatastore RPC.
+type MigrateDatastoreResponse struct {
+	// Migration is the started migration.
+	Migration DatastoreMigration `json:"migration"`
+}
+
+// GetDatastoreMigrationResponse is the response message for the GetDatastoreMigration RPC.
+type GetDatastoreMigrationResponse struct {
+	// Migration is the latest datastore migration started on this node. Nil if no migration was started since k8sd started.
+	Migration *DatastoreMigration `json:"migration,omitempty"`
+}
@@ -14,6 +14,10 @@ func NewRootCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
 		endpointsConfigFile        string
 		refreshEndpointsInterval   time.Duration
 		refreshEndpointsKubeconfig string
+		healthCheckInterval        time.Duration
+		dialTimeout                time.Duration
+		drainTimeout               time.Duration
+		statusAddress              string
 	}
 
 	cmd := &cobra.Command{
@@ -36,6 +40,10 @@ func NewRootCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
 				EndpointsConfigFile: opts.endpointsConfigFile,
 				KubeconfigFile:      opts.refreshEndpointsKubeconfig,
 				RefreshCh:           refreshCh,
+				HealthCheckInterval: opts.healthCheckInterval,
+				DialTimeout:         opts.dialTimeout,
+				DrainTimeout:        opts.drainTimeout,
+				StatusAddress:       opts.statusAddress,
 			}
 
 			if err := p.Run(cmd.Context()); err != nil {
@@ -55,6 +63,10 @@ func NewRootCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
 	cmd.Flags().StringVar(&opts.endpointsConfigFile, "endpoints", "/etc/kubernetes/k8s-apiserver-proxy.json", "configuration file with known kube-apiserver endpoints")
 	cmd.Flags().StringVar(&opts.refreshEndpointsKubeconfig, "kubeconfig", "/etc/kubernetes/kubelet.conf", "kubeconfig file to use for updating list of known kube-apiserver endpoints")
 	cmd.Flags().DurationVar(&opts.refreshEndpointsInterval, "refresh-interval", 30*time.Second, "interval between checking for new kube-apiserver endpoints. set to 0 to disable")
+	cmd.Flags().DurationVar(&opts.healthCheckInterval, "health-check-interval", 10*time.Second, "interval between health checks of the kube-apiserver endpoints")
+	cmd.Flags().DurationVar(&opts.dialTimeout, "dial-timeout", 10*time.Second, "timeout for connecting to a kube-apiserver endpoint and for each health check")
+	cmd.Flags().DurationVar(&opts.drainTimeout, "drain-timeout", time.Minute, "time to keep open connections to removed kube-apiserver endpoints")
+	cmd.Flags().StringVar(&opts.statusAddress, "status-address", "", "address to serve metrics, status and health of the proxy on. set to empty to disable")
 
 	return cmd
 }
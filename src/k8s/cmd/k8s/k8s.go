@@ -1,10 +1,13 @@
 package k8s
 
 import (
+	"context"
 	"time"
 
 	cmdutil "github.com/canonical/k8s/cmd/util"
+	"github.com/canonical/k8s/pkg/client/k8sd"
 	"github.com/canonical/k8s/pkg/k8sd/features"
+	"github.com/canonical/k8s/pkg/k8sd/types"
 	"github.com/spf13/cobra"
 )
 
@@ -23,6 +26,38 @@ var (
 
 const minTimeout = 3 * time.Second
 
+// toggleableFeatures returns the features that can be enabled and disabled, followed by the registered plugin features.
+// cert-manager is configured through annotations, so it is not part of featureList.
+func toggleableFeatures() []string {
+	return toggleableFeaturesWith(features.PluginNames())
+}
+
+// toggleableFeaturesWith returns the features that can be enabled and disabled, followed by the given plugin features.
+func toggleableFeaturesWith(plugins []types.FeatureName) []string {
+	names := append([]string{}, featureList...)
+	names = append(names, string(features.CertManager))
+	for _, name := range plugins {
+		names = append(names, string(name))
+	}
+	return names
+}
+
+// k8sdPluginFeatures returns the plugin features that are registered in k8sd.
+// k8sd may be built with plugins that are not registered in the CLI.
+func k8sdPluginFeatures(ctx context.Context, client k8sd.Client) ([]types.FeatureName, error) {
+	response, err := client.ListFeatures(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var plugins []types.FeatureName
+	for _, feature := range response.Features {
+		if !feature.Builtin {
+			plugins = append(plugins, types.FeatureName(feature.Name))
+		}
+	}
+	return plugins, nil
+}
+
 func addCommands(root *cobra.Command, group *cobra.Group, commands ...*cobra.Command) {
 	if group != nil {
 		root.AddGroup(group)
@@ -87,9 +122,14 @@ func NewRootCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
 		newDisableCmd(env),
 		newRefreshCertsCmd(env),
 		newCertsStatusCmd(env),
+		newRotateCACmd(env),
 		newSetCmd(env),
 		newGetCmd(env),
+		newApplyCmd(env),
+		newDiffCmd(env),
 		newInspectCmd(env),
+		newBackupCmd(env),
+		newMigrateDatastoreCmd(env),
 	)
 
 	// hidden commands
@@ -103,6 +143,7 @@ func NewRootCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
 		newXSnapdConfigCmd(env),
 		newXWaitForCmd(env),
 		newXCAPICmd(env),
+		newXAuthTokensCmd(env),
 		newListImagesCmd(env),
 		newXCleanupCmd(env),
 	)
@@ -0,0 +1,191 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	apiv1 "github.com/canonical/k8s-snap-api/api/v1"
+	apiv1alpha "github.com/canonical/k8s/api/v1alpha"
+	cmdutil "github.com/canonical/k8s/cmd/util"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+const applyLong = `Apply a cluster configuration file.
+
+The file contains the full cluster configuration in the same format as the
+output of "k8s get --output-format yaml". The configuration is merged with the
+current cluster configuration using the same validation as "k8s set", and all
+changes are applied at once.
+
+Options that are not set in the file are left unchanged. With --prune, options
+that are not set in the file are reset to their default values and annotations
+that are not set in the file are removed.`
+
+const diffLong = `Compare a cluster configuration file with the current cluster configuration.
+
+The command prints the changes that "k8s apply" would make and exits with a
+non-zero exit code if there are any, so it can be used to detect configuration
+drift.`
+
+// applyResult is the result of applying a cluster configuration file.
+type applyResult struct {
+	Changes configDiff `json:"changes" yaml:"changes"`
+	Applied bool       `json:"applied" yaml:"applied"`
+}
+
+func (r applyResult) String() string {
+	switch {
+	case len(r.Changes) == 0:
+		return "No changes."
+	case r.Applied:
+		return fmt.Sprintf("%s\n\nConfiguration updated.", r.Changes)
+	default:
+		return fmt.Sprintf("%s\n\nDry run, configuration not updated.", r.Changes)
+	}
+}
+
+func newApplyCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
+	var opts struct {
+		file         string
+		dryRun       bool
+		prune        bool
+		outputFormat string
+		timeout      time.Duration
+	}
+	cmd := &cobra.Command{
+		Use:    "apply -f <file>",
+		Short:  "Apply a cluster configuration file",
+		Long:   applyLong,
+		Args:   cobra.NoArgs,
+		PreRun: chainPreRunHooks(hookRequireRoot(env), hookInitializeFormatter(env, &opts.outputFormat)),
+		Run: func(cmd *cobra.Command, args []string) {
+			response, ok := applyClusterConfigFile(cmd, env, opts.file, opts.dryRun, opts.prune, opts.timeout)
+			if !ok {
+				return
+			}
+
+			outputFormatter.Print(applyResult{Changes: response.Changes, Applied: response.Applied})
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.file, "file", "f", "", "path to the cluster configuration file. Use - to read from stdin")
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "print the changes without applying them")
+	cmd.Flags().BoolVar(&opts.prune, "prune", false, "reset options that are not set in the file to their default values")
+	cmd.Flags().StringVar(&opts.outputFormat, "output-format", "plain", "set the output format to one of plain, json or yaml")
+	cmd.Flags().DurationVar(&opts.timeout, "timeout", 90*time.Second, "the max time to wait for the command to execute")
+	cmd.MarkFlagRequired("file")
+
+	return cmd
+}
+
+func newDiffCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
+	var opts struct {
+		file         string
+		prune        bool
+		outputFormat string
+		timeout      time.Duration
+	}
+	cmd := &cobra.Command{
+		Use:    "diff -f <file>",
+		Short:  "Compare a cluster configuration file with the current cluster configuration",
+		Long:   diffLong,
+		Args:   cobra.NoArgs,
+		PreRun: chainPreRunHooks(hookRequireRoot(env), hookInitializeFormatter(env, &opts.outputFormat)),
+		Run: func(cmd *cobra.Command, args []string) {
+			response, ok := applyClusterConfigFile(cmd, env, opts.file, true, opts.prune, opts.timeout)
+			if !ok {
+				return
+			}
+
+			outputFormatter.Print(configDiff(response.Changes))
+			if len(response.Changes) > 0 {
+				env.Exit(1)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.file, "file", "f", "", "path to the cluster configuration file. Use - to read from stdin")
+	cmd.Flags().BoolVar(&opts.prune, "prune", false, "also report options that are not set in the file and differ from their default values")
+	cmd.Flags().StringVar(&opts.outputFormat, "output-format", "plain", "set the output format to one of plain, json or yaml")
+	cmd.Flags().DurationVar(&opts.timeout, "timeout", 90*time.Second, "the max time to wait for the command to execute")
+	cmd.MarkFlagRequired("file")
+
+	return cmd
+}
+
+// applyClusterConfigFile sends the cluster configuration in filePath to k8sd.
+// applyClusterConfigFile prints an error and returns false on failure.
+func applyClusterConfigFile(cmd *cobra.Command, env cmdutil.ExecutionEnvironment, filePath string, dryRun bool, prune bool, timeout time.Duration) (apiv1alpha.ApplyClusterConfigResponse, bool) {
+	if timeout < minTimeout {
+		cmd.PrintErrf("Timeout %v is less than minimum of %v. Using the minimum %v instead.\n", timeout, minTimeout, minTimeout)
+		timeout = minTimeout
+	}
+
+	config, err := getClusterConfigFromYaml(env, filePath)
+	if err != nil {
+		cmd.PrintErrf("Error: Failed to read cluster configuration from %q.\n\nThe error was: %v\n", filePath, err)
+		env.Exit(1)
+		return apiv1alpha.ApplyClusterConfigResponse{}, false
+	}
+
+	client, err := env.Snap.K8sdClient("")
+	if err != nil {
+		cmd.PrintErrf("Error: Failed to create a k8sd client. Make sure that the k8sd service is running.\n\nThe error was: %v\n", err)
+		env.Exit(1)
+		return apiv1alpha.ApplyClusterConfigResponse{}, false
+	}
+
+	if _, initialized, err := client.NodeStatus(cmd.Context()); err != nil {
+		cmd.PrintErrf("Error: Failed to check the current node status.\n\nThe error was: %v\n", err)
+		env.Exit(1)
+		return apiv1alpha.ApplyClusterConfigResponse{}, false
+	} else if !initialized {
+		cmd.PrintErrln("Error: The node is not part of a Kubernetes cluster. You can bootstrap a new cluster with:\n\n  sudo k8s bootstrap")
+		env.Exit(1)
+		return apiv1alpha.ApplyClusterConfigResponse{}, false
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+	cobra.OnFinalize(cancel)
+
+	response, err := client.ApplyClusterConfig(ctx, apiv1alpha.ApplyClusterConfigRequest{
+		Config: config,
+		DryRun: dryRun,
+		Prune:  prune,
+	})
+	if err != nil {
+		cmd.PrintErrf("Error: Failed to apply the cluster configuration.\n\nThe error was: %v\n", err)
+		env.Exit(1)
+		return apiv1alpha.ApplyClusterConfigResponse{}, false
+	}
+
+	return response, true
+}
+
+func getClusterConfigFromYaml(env cmdutil.ExecutionEnvironment, filePath string) (apiv1.UserFacingClusterConfig, error) {
+	var b []byte
+	var err error
+
+	if filePath == "-" {
+		b, err = io.ReadAll(env.Stdin)
+		if err != nil {
+			return apiv1.UserFacingClusterConfig{}, fmt.Errorf("failed to read config from stdin: %w", err)
+		}
+	} else {
+		b, err = os.ReadFile(filePath)
+		if err != nil {
+			return apiv1.UserFacingClusterConfig{}, fmt.Errorf("failed to read file: %w", err)
+		}
+	}
+
+	var config apiv1.UserFacingClusterConfig
+	if err := yaml.UnmarshalStrict(b, &config); err != nil {
+		return apiv1.UserFacingClusterConfig{}, fmt.Errorf("failed to parse YAML config file: %w", err)
+	}
+
+	return config, nil
+}
@@ -0,0 +1,125 @@
+package k8s_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	apiv1 "github.com/canonical/k8s-snap-api/api/v1"
+	apiv1alpha "github.com/canonical/k8s/api/v1alpha"
+	"github.com/canonical/k8s/cmd/k8s"
+	cmdutil "github.com/canonical/k8s/cmd/util"
+	k8sdmock "github.com/canonical/k8s/pkg/client/k8sd/mock"
+	snapmock "github.com/canonical/k8s/pkg/snap/mock"
+	"github.com/canonical/k8s/pkg/utils"
+	. "github.com/onsi/gomega"
+)
+
+func TestApplyCmd(t *testing.T) {
+	changes := []apiv1alpha.ClusterConfigChange{{Key: "dns.enabled", Old: "false", New: "true"}}
+
+	tests := []struct {
+		name           string
+		args           []string
+		config         string
+		response       apiv1alpha.ApplyClusterConfigResponse
+		expectedCall   apiv1alpha.ApplyClusterConfigRequest
+		expectedCode   int
+		expectedStdout string
+		expectedStderr string
+	}{
+		{
+			name:     "Apply",
+			args:     []string{"apply"},
+			config:   "dns:\n  enabled: true\n",
+			response: apiv1alpha.ApplyClusterConfigResponse{Changes: changes, Applied: true},
+			expectedCall: apiv1alpha.ApplyClusterConfigRequest{
+				Config: apiv1.UserFacingClusterConfig{DNS: apiv1.DNSConfig{Enabled: utils.Pointer(true)}},
+			},
+			expectedStdout: "~ dns.enabled: false -> true\n\nConfiguration updated.",
+		},
+		{
+			name:     "ApplyDryRunPrune",
+			args:     []string{"apply", "--dry-run", "--prune"},
+			config:   "dns:\n  enabled: true\n",
+			response: apiv1alpha.ApplyClusterConfigResponse{Changes: changes},
+			expectedCall: apiv1alpha.ApplyClusterConfigRequest{
+				Config: apiv1.UserFacingClusterConfig{DNS: apiv1.DNSConfig{Enabled: utils.Pointer(true)}},
+				DryRun: true,
+				Prune:  true,
+			},
+			expectedStdout: "Dry run, configuration not updated.",
+		},
+		{
+			name:           "ApplyUnknownKey",
+			args:           []string{"apply"},
+			config:         "dns:\n  unknown: true\n",
+			expectedStderr: "failed to parse YAML config file",
+			expectedCode:   1,
+		},
+		{
+			name:     "DiffNoDrift",
+			args:     []string{"diff"},
+			config:   "dns:\n  enabled: true\n",
+			response: apiv1alpha.ApplyClusterConfigResponse{},
+			expectedCall: apiv1alpha.ApplyClusterConfigRequest{
+				Config: apiv1.UserFacingClusterConfig{DNS: apiv1.DNSConfig{Enabled: utils.Pointer(true)}},
+				DryRun: true,
+			},
+			expectedStdout: "No changes.",
+		},
+		{
+			name:     "DiffDrift",
+			args:     []string{"diff"},
+			config:   "dns:\n  enabled: true\n",
+			response: apiv1alpha.ApplyClusterConfigResponse{Changes: changes},
+			expectedCall: apiv1alpha.ApplyClusterConfigRequest{
+				Config: apiv1.UserFacingClusterConfig{DNS: apiv1.DNSConfig{Enabled: utils.Pointer(true)}},
+				DryRun: true,
+			},
+			expectedStdout: "~ dns.enabled: false -> true",
+			expectedCode:   1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			file := filepath.Join(t.TempDir(), "cluster.yaml")
+			g.Expect(os.WriteFile(file, []byte(tt.config), 0o600)).To(Succeed())
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+			mockClient := &k8sdmock.Mock{
+				NodeStatusInitialized:      true,
+				ApplyClusterConfigResponse: tt.response,
+			}
+			var returnCode int
+			env := cmdutil.ExecutionEnvironment{
+				Stdout: stdout,
+				Stderr: stderr,
+				Getuid: func() int { return 0 },
+				Snap: &snapmock.Snap{
+					Mock: snapmock.Mock{
+						K8sdClient: mockClient,
+					},
+				},
+				Exit: func(rc int) { returnCode = rc },
+			}
+			cmd := k8s.NewRootCmd(env)
+
+			cmd.SetArgs(append(tt.args, "-f", file))
+			cmd.Execute()
+
+			g.Expect(stdout.String()).To(ContainSubstring(tt.expectedStdout))
+			g.Expect(stderr.String()).To(ContainSubstring(tt.expectedStderr))
+			g.Expect(returnCode).To(Equal(tt.expectedCode))
+
+			if tt.expectedCode == 0 || tt.expectedStderr == "" {
+				g.Expect(mockClient.ApplyClusterConfigCalledWith).To(Equal(tt.expectedCall))
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	cmdutil "github.com/canonical/k8s/cmd/util"
+	"github.com/canonical/k8s/pkg/k8sd/backup"
+	"github.com/spf13/cobra"
+)
+
+const backupRestoreLong = `Restore the cluster datastores from a backup.
+
+The k8sd database and, if included in the backup, the k8s-dqlite datastore are
+replaced with the contents of the backup. Both datastores are replicated, so
+the restore applies to the whole cluster and is performed on a single control
+plane node. In clusters with multiple control plane nodes, stop kube-apiserver
+on the other control plane nodes before the restore and start it again
+afterwards.
+
+The control plane services of the local node are stopped during the restore and
+started again afterwards. The replaced state is saved as a
+"pre-restore-<timestamp>" backup that can also be restored with this command.
+If the restore fails, the datastores are rolled back to the replaced state.`
+
+// backupList is a list of backups that prints as a table in plain output.
+type backupList []backup.Backup
+
+func (l backupList) String() string {
+	if len(l) == 0 {
+		return "No backups found."
+	}
+
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "NAME\tCREATED\tNODE\tK8S-DQLITE")
+	for _, b := range l {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%v\n", b.Name, b.CreatedAt.Local().Format(time.RFC3339), b.Node, b.K8sDqlite)
+	}
+	w.Flush()
+	return string(bytes.TrimRight(buf.Bytes(), "\n"))
+}
+
+func newBackupCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
+	var opts struct {
+		backupDir    string
+		outputFormat string
+		timeout      time.Duration
+	}
+
+	getBackupDir := func() string {
+		if opts.backupDir != "" {
+			return opts.backupDir
+		}
+		return env.Snap.BackupDir()
+	}
+
+	createCmd := &cobra.Command{
+		Use:    "create",
+		Short:  "Create a backup of the cluster datastores",
+		Args:   cobra.NoArgs,
+		PreRun: chainPreRunHooks(hookRequireRoot(env), hookInitializeFormatter(env, &opts.outputFormat)),
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx, cancel := context.WithTimeout(cmd.Context(), opts.timeout)
+			defer cancel()
+
+			b, err := backup.Create(ctx, env.Snap, getBackupDir())
+			if err != nil {
+				cmd.PrintErrf("Error: Failed to create backup.\n\nThe error was: %v\n", err)
+				env.Exit(1)
+				return
+			}
+
+			outputFormatter.Print(b.Name)
+		},
+	}
+
+	listCmd := &cobra.Command{
+		Use:    "list",
+		Short:  "List the available backups on the local node",
+		Args:   cobra.NoArgs,
+		PreRun: chainPreRunHooks(hookRequireRoot(env), hookInitializeFormatter(env, &opts.outputFormat)),
+		Run: func(cmd *cobra.Command, args []string) {
+			backups, err := backup.List(getBackupDir())
+			if err != nil {
+				cmd.PrintErrf("Error: Failed to list backups.\n\nThe error was: %v\n", err)
+				env.Exit(1)
+				return
+			}
+
+			outputFormatter.Print(backupList(backups))
+		},
+	}
+
+	restoreCmd := &cobra.Command{
+		Use:    "restore <name>",
+		Short:  "Restore the cluster datastores from a backup",
+		Long:   backupRestoreLong,
+		Args:   cobra.ExactArgs(1),
+		PreRun: chainPreRunHooks(hookRequireRoot(env)),
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx, cancel := context.WithTimeout(cmd.Context(), opts.timeout)
+			defer cancel()
+
+			if err := backup.Restore(ctx, env.Snap, getBackupDir(), args[0]); err != nil {
+				if errors.Is(err, backup.ErrNotFound) {
+					cmd.PrintErrf("Error: Backup %q does not exist. You can list the available backups with:\n\n  sudo k8s backup list\n", args[0])
+				} else {
+					cmd.PrintErrf("Error: Failed to restore backup %q.\n\nThe error was: %v\n", args[0], err)
+				}
+				env.Exit(1)
+				return
+			}
+
+			cmd.Printf("Restored backup %q.\n", args[0])
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:   "backup",
+		Short: "Manage backups of the cluster datastores",
+	}
+
+	cmd.PersistentFlags().StringVar(&opts.backupDir, "backup-dir", "", "directory where backups are stored (default: /var/snap/k8s/common/var/lib/k8s-backups)")
+	createCmd.Flags().StringVar(&opts.outputFormat, "output-format", "plain", "set the output format to one of plain, json or yaml")
+	createCmd.Flags().DurationVar(&opts.timeout, "timeout", 5*time.Minute, "the max time to wait for the command to execute")
+	listCmd.Flags().StringVar(&opts.outputFormat, "output-format", "plain", "set the output format to one of plain, json or yaml")
+	restoreCmd.Flags().DurationVar(&opts.timeout, "timeout", 5*time.Minute, "the max time to wait for the command to execute")
+
+	cmd.AddCommand(createCmd)
+	cmd.AddCommand(listCmd)
+	cmd.AddCommand(restoreCmd)
+
+	return cmd
+}
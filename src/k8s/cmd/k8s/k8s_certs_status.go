@@ -53,6 +53,16 @@ func newCertsStatusCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
 				env.Exit(1)
 				return
 			}
+
+			rotation, err := client.GetCARotation(ctx)
+			if err != nil {
+				cmd.PrintErrf("Error: Failed to retrieve CA rotation status.\n\nError: %v\n", err)
+				env.Exit(1)
+				return
+			}
+			if rotation.Rotation != nil {
+				fmt.Fprintf(cmd.OutOrStdout(), "\n%s\n", caRotation(*rotation.Rotation))
+			}
 		},
 	}
 	cmd.Flags().DurationVar(&opts.timeout, "timeout", 90*time.Second, "the max time to wait for the command to execute")
@@ -5,22 +5,44 @@ import (
 	"time"
 
 	apiv1 "github.com/canonical/k8s-snap-api/api/v1"
+	apiv1alpha "github.com/canonical/k8s/api/v1alpha"
 	cmdutil "github.com/canonical/k8s/cmd/util"
 	"github.com/spf13/cobra"
 )
 
+const configLong = `Generate a kubeconfig that can be used to access the Kubernetes cluster.
+
+By default, an admin kubeconfig is generated. With --user, a kubeconfig is
+issued that authenticates as the user and groups for a limited lifetime. Its
+access is determined by the RBAC rules for the user and groups. The lifetime
+cannot exceed the maximum that is configured with the
+k8sd/v1alpha1/kubeconfig/max-ttl annotation (24h by default).
+
+Issued kubeconfigs are listed with "k8s config issued" and revoked with
+"k8s config revoke".`
+
 func newKubeConfigCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
 	var opts struct {
 		server  string
+		user    string
+		groups  []string
+		ttl     time.Duration
 		timeout time.Duration
 	}
 	cmd := &cobra.Command{
 		Use:    "config",
 		Hidden: true,
 		Short:  "Generate an admin kubeconfig that can be used to access the Kubernetes cluster",
+		Long:   configLong,
 		Args:   cobra.NoArgs,
 		PreRun: chainPreRunHooks(hookRequireRoot(env)),
 		Run: func(cmd *cobra.Command, args []string) {
+			if opts.user == "" && (len(opts.groups) > 0 || opts.ttl != 0) {
+				cmd.PrintErrln("Error: --group and --ttl can only be used together with --user.")
+				env.Exit(1)
+				return
+			}
+
 			if opts.timeout < minTimeout {
 				cmd.PrintErrf("Timeout %v is less than minimum of %v. Using the minimum %v instead.\n", opts.timeout, minTimeout, minTimeout)
 				opts.timeout = minTimeout
@@ -46,6 +68,24 @@ func newKubeConfigCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
 			ctx, cancel := context.WithTimeout(cmd.Context(), opts.timeout)
 			cobra.OnFinalize(cancel)
 
+			if opts.user != "" {
+				response, err := client.IssueKubeconfig(ctx, apiv1alpha.IssueKubeconfigRequest{
+					Server:   opts.server,
+					Username: opts.user,
+					Groups:   opts.groups,
+					TTL:      opts.ttl,
+				})
+				if err != nil {
+					cmd.PrintErrf("Error: Failed to issue a kubeconfig for user %q.\n\nThe error was: %v\n", opts.user, err)
+					env.Exit(1)
+					return
+				}
+
+				cmd.PrintErrf("Issued kubeconfig %d for user %q, which expires at %s.\n", response.Issued.ID, response.Issued.Username, response.Issued.ExpiresAt.Local().Format(time.RFC3339))
+				cmd.Println(response.KubeConfig)
+				return
+			}
+
 			response, err := client.KubeConfig(ctx, apiv1.KubeConfigRequest{Server: opts.server})
 			if err != nil {
 				cmd.PrintErrf("Error: Failed to generate an admin kubeconfig for %q.\n\nThe error was: %v\n", opts.server, err)
@@ -57,6 +97,11 @@ func newKubeConfigCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
 		},
 	}
 	cmd.Flags().StringVar(&opts.server, "server", "", "custom cluster server address")
+	cmd.Flags().StringVar(&opts.user, "user", "", "issue a kubeconfig for this user instead of an admin kubeconfig")
+	cmd.Flags().StringSliceVar(&opts.groups, "group", nil, "groups of the user in the issued kubeconfig, can be repeated")
+	cmd.Flags().DurationVar(&opts.ttl, "ttl", 0, "lifetime of the issued kubeconfig, e.g. \"8h\". Defaults to the maximum lifetime allowed by the cluster")
 	cmd.Flags().DurationVar(&opts.timeout, "timeout", 90*time.Second, "the max time to wait for the command to execute")
+	cmd.AddCommand(newConfigRevisionCmds(env)...)
+	cmd.AddCommand(newConfigKubeconfigCmds(env)...)
 	return cmd
 }
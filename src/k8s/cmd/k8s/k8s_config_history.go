@@ -0,0 +1,223 @@
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	apiv1alpha "github.com/canonical/k8s/api/v1alpha"
+	cmdutil "github.com/canonical/k8s/cmd/util"
+	"github.com/canonical/k8s/pkg/client/k8sd"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/spf13/cobra"
+)
+
+const configRollbackLong = `Roll back the cluster configuration to a previous revision.
+
+The user-facing options of the revision are applied as a new revision, using
+the same validation as "k8s set". Options that cannot be changed after
+bootstrap, the datastore and the network provider keep their current values.
+Options that cannot be changed while their feature is enabled (e.g. the DNS
+service IP or the local storage path) also keep their current values.
+
+Only the latest 100 revisions are kept. Certificates and keys are not recorded
+in the revision history.
+
+Use "k8s config history" to list the available revisions and "k8s config diff"
+to review the changes before rolling back.`
+
+// configHistory is a list of cluster config revisions that prints as a table in plain output.
+type configHistory []apiv1alpha.ClusterConfigRevision
+
+func (h configHistory) String() string {
+	if len(h) == 0 {
+		return "No configuration revisions found."
+	}
+
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "REVISION\tTIMESTAMP\tIDENTITY")
+	for _, r := range h {
+		fmt.Fprintf(w, "%d\t%s\t%s\n", r.Revision, r.Timestamp.Local().Format(time.RFC3339), r.Identity)
+	}
+	w.Flush()
+	return string(bytes.TrimRight(buf.Bytes(), "\n"))
+}
+
+// configDiff is a list of cluster config changes that prints one change per line in plain output.
+type configDiff []apiv1alpha.ClusterConfigChange
+
+func (d configDiff) String() string {
+	if len(d) == 0 {
+		return "No changes."
+	}
+
+	var buf bytes.Buffer
+	for _, c := range d {
+		switch {
+		case c.Old == "":
+			fmt.Fprintf(&buf, "+ %s: %s\n", c.Key, c.New)
+		case c.New == "":
+			fmt.Fprintf(&buf, "- %s: %s\n", c.Key, c.Old)
+		default:
+			fmt.Fprintf(&buf, "~ %s: %s -> %s\n", c.Key, c.Old, c.New)
+		}
+	}
+	return string(bytes.TrimRight(buf.Bytes(), "\n"))
+}
+
+// configRollbackResult is the result of a cluster config rollback.
+type configRollbackResult struct {
+	// RolledBackTo is the revision that was restored.
+	RolledBackTo int64 `json:"rolled-back-to" yaml:"rolled-back-to"`
+	// Revision is the new revision that was recorded for the rollback.
+	Revision int64 `json:"revision" yaml:"revision"`
+}
+
+func (r configRollbackResult) String() string {
+	return fmt.Sprintf("Rolled back the cluster configuration to revision %d (new revision %d).", r.RolledBackTo, r.Revision)
+}
+
+func newConfigRevisionCmds(env cmdutil.ExecutionEnvironment) []*cobra.Command {
+	var opts struct {
+		outputFormat string
+		timeout      time.Duration
+	}
+
+	getClient := func(cmd *cobra.Command) (k8sd.Client, bool) {
+		if opts.timeout < minTimeout {
+			cmd.PrintErrf("Timeout %v is less than minimum of %v. Using the minimum %v instead.\n", opts.timeout, minTimeout, minTimeout)
+			opts.timeout = minTimeout
+		}
+
+		client, err := env.Snap.K8sdClient("")
+		if err != nil {
+			cmd.PrintErrf("Error: Failed to create a k8sd client. Make sure that the k8sd service is running.\n\nThe error was: %v\n", err)
+			env.Exit(1)
+			return nil, false
+		}
+
+		if _, initialized, err := client.NodeStatus(cmd.Context()); err != nil {
+			cmd.PrintErrf("Error: Failed to check the current node status.\n\nThe error was: %v\n", err)
+			env.Exit(1)
+			return nil, false
+		} else if !initialized {
+			cmd.PrintErrln("Error: The node is not part of a Kubernetes cluster. You can bootstrap a new cluster with:\n\n  sudo k8s bootstrap")
+			env.Exit(1)
+			return nil, false
+		}
+		return client, true
+	}
+
+	parseRevision := func(cmd *cobra.Command, arg string) (int64, bool) {
+		revision, err := strconv.ParseInt(arg, 10, 64)
+		if err != nil || revision <= 0 {
+			cmd.PrintErrf("Error: Invalid revision %q. You can list the available revisions with:\n\n  sudo k8s config history\n", arg)
+			env.Exit(1)
+			return 0, false
+		}
+		return revision, true
+	}
+
+	printRevisionErr := func(cmd *cobra.Command, action string, revision int64, err error) {
+		if api.StatusErrorCheck(err, http.StatusNotFound) {
+			cmd.PrintErrf("Error: Revision %d does not exist. You can list the available revisions with:\n\n  sudo k8s config history\n", revision)
+		} else {
+			cmd.PrintErrf("Error: Failed to %s revision %d.\n\nThe error was: %v\n", action, revision, err)
+		}
+		env.Exit(1)
+	}
+
+	historyCmd := &cobra.Command{
+		Use:    "history",
+		Short:  "List the revisions of the cluster configuration",
+		Args:   cobra.NoArgs,
+		PreRun: chainPreRunHooks(hookRequireRoot(env), hookInitializeFormatter(env, &opts.outputFormat)),
+		Run: func(cmd *cobra.Command, args []string) {
+			client, ok := getClient(cmd)
+			if !ok {
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), opts.timeout)
+			cobra.OnFinalize(cancel)
+
+			response, err := client.ListClusterConfigRevisions(ctx, apiv1alpha.ListClusterConfigRevisionsRequest{})
+			if err != nil {
+				cmd.PrintErrf("Error: Failed to list the cluster configuration revisions.\n\nThe error was: %v\n", err)
+				env.Exit(1)
+				return
+			}
+
+			outputFormatter.Print(configHistory(response.Revisions))
+		},
+	}
+
+	diffCmd := &cobra.Command{
+		Use:    "diff <revision>",
+		Short:  "Show the changes to the cluster configuration since a revision",
+		Args:   cmdutil.ExactArgs(env, 1),
+		PreRun: chainPreRunHooks(hookRequireRoot(env), hookInitializeFormatter(env, &opts.outputFormat)),
+		Run: func(cmd *cobra.Command, args []string) {
+			revision, ok := parseRevision(cmd, args[0])
+			if !ok {
+				return
+			}
+			client, ok := getClient(cmd)
+			if !ok {
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), opts.timeout)
+			cobra.OnFinalize(cancel)
+
+			response, err := client.DiffClusterConfigRevision(ctx, apiv1alpha.DiffClusterConfigRevisionRequest{Revision: revision})
+			if err != nil {
+				printRevisionErr(cmd, "compare", revision, err)
+				return
+			}
+
+			outputFormatter.Print(configDiff(response.Changes))
+		},
+	}
+
+	rollbackCmd := &cobra.Command{
+		Use:    "rollback <revision>",
+		Short:  "Roll back the cluster configuration to a previous revision",
+		Long:   configRollbackLong,
+		Args:   cmdutil.ExactArgs(env, 1),
+		PreRun: chainPreRunHooks(hookRequireRoot(env), hookInitializeFormatter(env, &opts.outputFormat)),
+		Run: func(cmd *cobra.Command, args []string) {
+			revision, ok := parseRevision(cmd, args[0])
+			if !ok {
+				return
+			}
+			client, ok := getClient(cmd)
+			if !ok {
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), opts.timeout)
+			cobra.OnFinalize(cancel)
+
+			response, err := client.RollbackClusterConfigRevision(ctx, apiv1alpha.RollbackClusterConfigRevisionRequest{Revision: revision})
+			if err != nil {
+				printRevisionErr(cmd, "roll back to", revision, err)
+				return
+			}
+
+			outputFormatter.Print(configRollbackResult{RolledBackTo: revision, Revision: response.Revision})
+		},
+	}
+
+	for _, cmd := range []*cobra.Command{historyCmd, diffCmd, rollbackCmd} {
+		cmd.Flags().StringVar(&opts.outputFormat, "output-format", "plain", "set the output format to one of plain, json or yaml")
+		cmd.Flags().DurationVar(&opts.timeout, "timeout", 90*time.Second, "the max time to wait for the command to execute")
+	}
+
+	return []*cobra.Command{historyCmd, diffCmd, rollbackCmd}
+}
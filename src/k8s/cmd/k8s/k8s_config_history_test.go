@@ -0,0 +1,90 @@
+package k8s_test
+
+import (
+	"bytes"
+	"testing"
+
+	apiv1alpha "github.com/canonical/k8s/api/v1alpha"
+	"github.com/canonical/k8s/cmd/k8s"
+	cmdutil "github.com/canonical/k8s/cmd/util"
+	k8sdmock "github.com/canonical/k8s/pkg/client/k8sd/mock"
+	snapmock "github.com/canonical/k8s/pkg/snap/mock"
+	. "github.com/onsi/gomega"
+)
+
+func TestConfigRevisionCmds(t *testing.T) {
+	tests := []struct {
+		name           string
+		args           []string
+		expectedCode   int
+		expectedStdout string
+		expectedStderr string
+	}{
+		{
+			name:           "history",
+			args:           []string{"history"},
+			expectedStdout: "REVISION",
+		},
+		{
+			name:           "diff",
+			args:           []string{"diff", "1"},
+			expectedStdout: `~ dns.cluster-domain: "cluster.local" -> "cluster.example"`,
+		},
+		{
+			name:           "diff-invalid-revision",
+			args:           []string{"diff", "first"},
+			expectedStderr: `Error: Invalid revision "first"`,
+			expectedCode:   1,
+		},
+		{
+			name:           "rollback",
+			args:           []string{"rollback", "1"},
+			expectedStdout: "Rolled back the cluster configuration to revision 1 (new revision 3).",
+		},
+		{
+			name:           "rollback-missing-revision",
+			args:           []string{"rollback"},
+			expectedStderr: "Error: accepts 1 arg(s), received 0",
+			expectedCode:   1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+			mockClient := &k8sdmock.Mock{
+				NodeStatusInitialized: true,
+				ListClusterConfigRevisionsResponse: apiv1alpha.ListClusterConfigRevisionsResponse{
+					Revisions: []apiv1alpha.ClusterConfigRevision{{Revision: 1, Identity: "k8sd/bootstrap"}, {Revision: 2, Identity: "root"}},
+				},
+				DiffClusterConfigRevisionResponse: apiv1alpha.DiffClusterConfigRevisionResponse{
+					Changes: []apiv1alpha.ClusterConfigChange{{Key: "dns.cluster-domain", Old: `"cluster.local"`, New: `"cluster.example"`}},
+				},
+				RollbackClusterConfigRevisionResponse: apiv1alpha.RollbackClusterConfigRevisionResponse{Revision: 3},
+			}
+			var returnCode int
+			env := cmdutil.ExecutionEnvironment{
+				Stdout: stdout,
+				Stderr: stderr,
+				Getuid: func() int { return 0 },
+				Snap: &snapmock.Snap{
+					Mock: snapmock.Mock{
+						K8sdClient: mockClient,
+					},
+				},
+				Exit: func(rc int) { returnCode = rc },
+			}
+			cmd := k8s.NewRootCmd(env)
+
+			cmd.SetArgs(append([]string{"config"}, tt.args...))
+			cmd.Execute()
+
+			g.Expect(stdout.String()).To(ContainSubstring(tt.expectedStdout))
+			g.Expect(stderr.String()).To(ContainSubstring(tt.expectedStderr))
+			g.Expect(returnCode).To(Equal(tt.expectedCode))
+		})
+	}
+}
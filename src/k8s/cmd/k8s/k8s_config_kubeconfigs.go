@@ -0,0 +1,142 @@
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	apiv1alpha "github.com/canonical/k8s/api/v1alpha"
+	cmdutil "github.com/canonical/k8s/cmd/util"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/spf13/cobra"
+)
+
+// issuedKubeconfigs is a list of issued kubeconfigs that prints as a table in plain output.
+type issuedKubeconfigs []apiv1alpha.IssuedKubeconfig
+
+func (k issuedKubeconfigs) String() string {
+	if len(k) == 0 {
+		return "No issued kubeconfigs found."
+	}
+
+	now := time.Now()
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "ID\tUSER\tGROUPS\tISSUED BY\tISSUED AT\tEXPIRES AT")
+	for _, c := range k {
+		groups := strings.Join(c.Groups, ",")
+		if groups == "" {
+			groups = "-"
+		}
+		expiresAt := c.ExpiresAt.Local().Format(time.RFC3339)
+		if !now.Before(c.ExpiresAt) {
+			expiresAt += " (expired)"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\n", c.ID, c.Username, groups, c.IssuedBy, c.IssuedAt.Local().Format(time.RFC3339), expiresAt)
+	}
+	w.Flush()
+	return string(bytes.TrimRight(buf.Bytes(), "\n"))
+}
+
+// kubeconfigRevokeResult is the result of revoking an issued kubeconfig.
+type kubeconfigRevokeResult struct {
+	// ID is the issued kubeconfig that was revoked.
+	ID int64 `json:"id" yaml:"id"`
+}
+
+func (r kubeconfigRevokeResult) String() string {
+	return fmt.Sprintf("Revoked kubeconfig %d.", r.ID)
+}
+
+func newConfigKubeconfigCmds(env cmdutil.ExecutionEnvironment) []*cobra.Command {
+	var opts struct {
+		outputFormat string
+		timeout      time.Duration
+	}
+
+	issuedCmd := &cobra.Command{
+		Use:    "issued",
+		Short:  "List the kubeconfigs that were issued with \"k8s config --user\"",
+		Args:   cobra.NoArgs,
+		PreRun: chainPreRunHooks(hookRequireRoot(env), hookInitializeFormatter(env, &opts.outputFormat)),
+		Run: func(cmd *cobra.Command, args []string) {
+			if opts.timeout < minTimeout {
+				cmd.PrintErrf("Timeout %v is less than minimum of %v. Using the minimum %v instead.\n", opts.timeout, minTimeout, minTimeout)
+				opts.timeout = minTimeout
+			}
+
+			client, err := env.Snap.K8sdClient("")
+			if err != nil {
+				cmd.PrintErrf("Error: Failed to create a k8sd client. Make sure that the k8sd service is running.\n\nThe error was: %v\n", err)
+				env.Exit(1)
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), opts.timeout)
+			cobra.OnFinalize(cancel)
+
+			response, err := client.ListKubeconfigs(ctx, apiv1alpha.ListKubeconfigsRequest{})
+			if err != nil {
+				cmd.PrintErrf("Error: Failed to list the issued kubeconfigs.\n\nThe error was: %v\n", err)
+				env.Exit(1)
+				return
+			}
+
+			outputFormatter.Print(issuedKubeconfigs(response.Kubeconfigs))
+		},
+	}
+
+	revokeCmd := &cobra.Command{
+		Use:    "revoke <id>",
+		Short:  "Revoke a kubeconfig that was issued with \"k8s config --user\"",
+		Args:   cmdutil.ExactArgs(env, 1),
+		PreRun: chainPreRunHooks(hookRequireRoot(env), hookInitializeFormatter(env, &opts.outputFormat)),
+		Run: func(cmd *cobra.Command, args []string) {
+			id, err := strconv.ParseInt(args[0], 10, 64)
+			if err != nil || id <= 0 {
+				cmd.PrintErrf("Error: Invalid kubeconfig ID %q. You can list the issued kubeconfigs with:\n\n  sudo k8s config issued\n", args[0])
+				env.Exit(1)
+				return
+			}
+
+			if opts.timeout < minTimeout {
+				cmd.PrintErrf("Timeout %v is less than minimum of %v. Using the minimum %v instead.\n", opts.timeout, minTimeout, minTimeout)
+				opts.timeout = minTimeout
+			}
+
+			client, err := env.Snap.K8sdClient("")
+			if err != nil {
+				cmd.PrintErrf("Error: Failed to create a k8sd client. Make sure that the k8sd service is running.\n\nThe error was: %v\n", err)
+				env.Exit(1)
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), opts.timeout)
+			cobra.OnFinalize(cancel)
+
+			if _, err := client.RevokeKubeconfig(ctx, apiv1alpha.RevokeKubeconfigRequest{ID: id}); err != nil {
+				if api.StatusErrorCheck(err, http.StatusNotFound) {
+					cmd.PrintErrf("Error: Kubeconfig %d does not exist. You can list the issued kubeconfigs with:\n\n  sudo k8s config issued\n", id)
+				} else {
+					cmd.PrintErrf("Error: Failed to revoke kubeconfig %d.\n\nThe error was: %v\n", id, err)
+				}
+				env.Exit(1)
+				return
+			}
+
+			outputFormatter.Print(kubeconfigRevokeResult{ID: id})
+		},
+	}
+
+	for _, cmd := range []*cobra.Command{issuedCmd, revokeCmd} {
+		cmd.Flags().StringVar(&opts.outputFormat, "output-format", "plain", "set the output format to one of plain, json or yaml")
+		cmd.Flags().DurationVar(&opts.timeout, "timeout", 90*time.Second, "the max time to wait for the command to execute")
+	}
+
+	return []*cobra.Command{issuedCmd, revokeCmd}
+}
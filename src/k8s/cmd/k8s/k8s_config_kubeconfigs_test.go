@@ -0,0 +1,108 @@
+package k8s_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	apiv1alpha "github.com/canonical/k8s/api/v1alpha"
+	"github.com/canonical/k8s/cmd/k8s"
+	cmdutil "github.com/canonical/k8s/cmd/util"
+	k8sdmock "github.com/canonical/k8s/pkg/client/k8sd/mock"
+	snapmock "github.com/canonical/k8s/pkg/snap/mock"
+	. "github.com/onsi/gomega"
+)
+
+func TestConfigKubeconfigCmds(t *testing.T) {
+	tests := []struct {
+		name           string
+		args           []string
+		issueErr       error
+		expectedCode   int
+		expectedStdout string
+		expectedStderr string
+		expectedIssue  apiv1alpha.IssueKubeconfigRequest
+		expectedRevoke apiv1alpha.RevokeKubeconfigRequest
+	}{
+		{
+			name:           "issue",
+			args:           []string{"--user", "alice", "--group", "dev", "--group", "view", "--ttl", "8h"},
+			expectedStdout: "token: token::abc",
+			expectedStderr: `Issued kubeconfig 3 for user "alice"`,
+			expectedIssue:  apiv1alpha.IssueKubeconfigRequest{Username: "alice", Groups: []string{"dev", "view"}, TTL: 8 * time.Hour},
+		},
+		{
+			name:           "issue-ttl-too-long",
+			args:           []string{"--user", "alice", "--ttl", "48h"},
+			issueErr:       errors.New("ttl 48h0m0s exceeds the maximum of 24h0m0s"),
+			expectedStderr: "exceeds the maximum",
+			expectedCode:   1,
+			expectedIssue:  apiv1alpha.IssueKubeconfigRequest{Username: "alice", TTL: 48 * time.Hour},
+		},
+		{
+			name:           "group-without-user",
+			args:           []string{"--group", "dev"},
+			expectedStderr: "Error: --group and --ttl can only be used together with --user.",
+			expectedCode:   1,
+		},
+		{
+			name:           "issued",
+			args:           []string{"issued"},
+			expectedStdout: "alice",
+		},
+		{
+			name:           "revoke",
+			args:           []string{"revoke", "3"},
+			expectedStdout: "Revoked kubeconfig 3.",
+			expectedRevoke: apiv1alpha.RevokeKubeconfigRequest{ID: 3},
+		},
+		{
+			name:           "revoke-invalid-id",
+			args:           []string{"revoke", "alice"},
+			expectedStderr: `Error: Invalid kubeconfig ID "alice"`,
+			expectedCode:   1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+			issued := apiv1alpha.IssuedKubeconfig{ID: 3, Username: "alice", Groups: []string{"dev"}, IssuedBy: "root", ExpiresAt: time.Now().Add(8 * time.Hour)}
+			mockClient := &k8sdmock.Mock{
+				NodeStatusInitialized: true,
+				IssueKubeconfigResponse: apiv1alpha.IssueKubeconfigResponse{
+					KubeConfig: "users:\n- name: k8s-user\n  user:\n    token: token::abc",
+					Issued:     issued,
+				},
+				IssueKubeconfigErr:      tt.issueErr,
+				ListKubeconfigsResponse: apiv1alpha.ListKubeconfigsResponse{Kubeconfigs: []apiv1alpha.IssuedKubeconfig{issued}},
+			}
+			var returnCode int
+			env := cmdutil.ExecutionEnvironment{
+				Stdout: stdout,
+				Stderr: stderr,
+				Getuid: func() int { return 0 },
+				Snap: &snapmock.Snap{
+					Mock: snapmock.Mock{
+						K8sdClient: mockClient,
+					},
+				},
+				Exit: func(rc int) { returnCode = rc },
+			}
+			cmd := k8s.NewRootCmd(env)
+
+			cmd.SetArgs(append([]string{"config"}, tt.args...))
+			cmd.Execute()
+
+			g.Expect(stdout.String()).To(ContainSubstring(tt.expectedStdout))
+			g.Expect(stderr.String()).To(ContainSubstring(tt.expectedStderr))
+			g.Expect(returnCode).To(Equal(tt.expectedCode))
+			g.Expect(mockClient.IssueKubeconfigCalledWith).To(Equal(tt.expectedIssue))
+			g.Expect(mockClient.RevokeKubeconfigCalledWith).To(Equal(tt.expectedRevoke))
+		})
+	}
+}
@@ -50,6 +50,17 @@ func TestDisableCmd(t *testing.T) {
 			},
 			expectedStdout: "disabled",
 		},
+		{
+			name:  "plugin",
+			funcs: []string{string(testPluginFeature), string(features.Gateway)},
+			expectedCall: apiv1.SetClusterConfigRequest{
+				Config: apiv1.UserFacingClusterConfig{
+					Gateway:     apiv1.GatewayConfig{Enabled: utils.Pointer(false)},
+					Annotations: map[string]string{features.PluginEnabledAnnotation(testPluginFeature): "false"},
+				},
+			},
+			expectedStdout: "disabled",
+		},
 		{
 			name:           "unknown",
 			funcs:          []string{"unknownFunc"},
@@ -66,6 +77,7 @@ func TestDisableCmd(t *testing.T) {
 			stderr := &bytes.Buffer{}
 			mockClient := &k8sdmock.Mock{
 				NodeStatusInitialized: true,
+				ListFeaturesResponse:  testListFeaturesResponse,
 			}
 			var returnCode int
 			env := cmdutil.ExecutionEnvironment{
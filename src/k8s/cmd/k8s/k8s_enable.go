@@ -3,12 +3,14 @@ package k8s
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
 	apiv1 "github.com/canonical/k8s-snap-api/api/v1"
 	cmdutil "github.com/canonical/k8s/cmd/util"
 	"github.com/canonical/k8s/pkg/k8sd/features"
+	"github.com/canonical/k8s/pkg/k8sd/types"
 	"github.com/canonical/k8s/pkg/utils"
 	"github.com/spf13/cobra"
 )
@@ -27,13 +29,14 @@ func newEnableCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
 		timeout      time.Duration
 	}
 	cmd := &cobra.Command{
-		Use:    fmt.Sprintf("enable [%s] ...", strings.Join(featureList, "|")),
+		Use:    fmt.Sprintf("enable [%s] ...", strings.Join(toggleableFeatures(), "|")),
 		Short:  "Enable core cluster features",
-		Long:   fmt.Sprintf("Enable one of %s.", strings.Join(featureList, ", ")),
+		Long:   fmt.Sprintf("Enable one of %s.", strings.Join(toggleableFeatures(), ", ")),
 		Args:   cmdutil.MinimumNArgs(env, 1),
 		PreRun: chainPreRunHooks(hookRequireRoot(env), hookInitializeFormatter(env, &opts.outputFormat)),
 		Run: func(cmd *cobra.Command, args []string) {
 			config := apiv1.UserFacingClusterConfig{}
+			var plugins []types.FeatureName
 
 			if opts.timeout < minTimeout {
 				cmd.PrintErrf("Timeout %v is less than minimum of %v. Using the minimum %v instead.\n", opts.timeout, minTimeout, minTimeout)
@@ -70,10 +73,14 @@ func newEnableCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
 					config.MetricsServer = apiv1.MetricsServerConfig{
 						Enabled: utils.Pointer(true),
 					}
+				case string(features.CertManager):
+					if config.Annotations == nil {
+						config.Annotations = map[string]string{}
+					}
+					config.Annotations[types.AnnotationCertManagerEnabled] = "true"
 				default:
-					cmd.PrintErrf("Error: Cannot enable %q, must be one of: %s\n", feature, strings.Join(featureList, ", "))
-					env.Exit(1)
-					return
+					// NOTE: Plugins are validated against the features that are registered in k8sd.
+					plugins = append(plugins, types.FeatureName(feature))
 				}
 			}
 			client, err := env.Snap.K8sdClient("")
@@ -97,6 +104,26 @@ func newEnableCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
 				return
 			}
 
+			if len(plugins) > 0 {
+				known, err := k8sdPluginFeatures(ctx, client)
+				if err != nil {
+					cmd.PrintErrf("Error: Failed to list the features of the cluster.\n\nThe error was: %v\n", err)
+					env.Exit(1)
+					return
+				}
+				for _, plugin := range plugins {
+					if !slices.Contains(known, plugin) {
+						cmd.PrintErrf("Error: Cannot enable %q, must be one of: %s\n", plugin, strings.Join(toggleableFeaturesWith(known), ", "))
+						env.Exit(1)
+						return
+					}
+					if config.Annotations == nil {
+						config.Annotations = map[string]string{}
+					}
+					config.Annotations[features.PluginEnabledAnnotation(plugin)] = "true"
+				}
+			}
+
 			if err := client.SetClusterConfig(ctx, apiv1.SetClusterConfigRequest{Config: config}); err != nil {
 				cmd.PrintErrf("Error: Failed to enable %s on the cluster.\n\nThe error was: %v\n", strings.Join(args, ", "), err)
 				env.Exit(1)
@@ -5,15 +5,28 @@ import (
 	"testing"
 
 	apiv1 "github.com/canonical/k8s-snap-api/api/v1"
+	apiv1alpha "github.com/canonical/k8s/api/v1alpha"
 	"github.com/canonical/k8s/cmd/k8s"
 	cmdutil "github.com/canonical/k8s/cmd/util"
 	k8sdmock "github.com/canonical/k8s/pkg/client/k8sd/mock"
 	"github.com/canonical/k8s/pkg/k8sd/features"
+	"github.com/canonical/k8s/pkg/k8sd/types"
 	snapmock "github.com/canonical/k8s/pkg/snap/mock"
 	"github.com/canonical/k8s/pkg/utils"
 	. "github.com/onsi/gomega"
 )
 
+// testPluginFeature is a plugin feature that is only registered in k8sd for the enable and disable tests.
+const testPluginFeature types.FeatureName = "test-plugin"
+
+// testListFeaturesResponse is the response of k8sd for the registered features in the enable and disable tests.
+var testListFeaturesResponse = apiv1alpha.ListFeaturesResponse{
+	Features: []apiv1alpha.Feature{
+		{Name: string(features.Gateway), Builtin: true},
+		{Name: string(testPluginFeature)},
+	},
+}
+
 func TestK8sEnableCmd(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -50,12 +63,39 @@ func TestK8sEnableCmd(t *testing.T) {
 			},
 			expectedStdout: "enabled",
 		},
+		{
+			name:  "plugin",
+			funcs: []string{string(testPluginFeature), string(features.Gateway)},
+			expectedCall: apiv1.SetClusterConfigRequest{
+				Config: apiv1.UserFacingClusterConfig{
+					Gateway:     apiv1.GatewayConfig{Enabled: utils.Pointer(true)},
+					Annotations: map[string]string{features.PluginEnabledAnnotation(testPluginFeature): "true"},
+				},
+			},
+			expectedStdout: "enabled",
+		},
+		{
+			name:  "cert-manager",
+			funcs: []string{string(features.CertManager)},
+			expectedCall: apiv1.SetClusterConfigRequest{
+				Config: apiv1.UserFacingClusterConfig{
+					Annotations: map[string]string{types.AnnotationCertManagerEnabled: "true"},
+				},
+			},
+			expectedStdout: "enabled",
+		},
 		{
 			name:           "unknown",
 			funcs:          []string{"unknownFunc"},
 			expectedStderr: "Error: Cannot enable",
 			expectedCode:   1,
 		},
+		{
+			name:           "unknown-plugin",
+			funcs:          []string{"unknownFunc", string(features.Gateway)},
+			expectedStderr: `Error: Cannot enable "unknownFunc", must be one of: network, dns, gateway, ingress, local-storage, load-balancer, cert-manager, test-plugin`,
+			expectedCode:   1,
+		},
 	}
 
 	for _, tt := range tests {
@@ -66,6 +106,7 @@ func TestK8sEnableCmd(t *testing.T) {
 			stderr := &bytes.Buffer{}
 			mockClient := &k8sdmock.Mock{
 				NodeStatusInitialized: true,
+				ListFeaturesResponse:  testListFeaturesResponse,
 			}
 			var returnCode int
 			env := cmdutil.ExecutionEnvironment{
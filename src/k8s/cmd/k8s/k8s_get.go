@@ -3,15 +3,33 @@ package k8s
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
 	apiv1 "github.com/canonical/k8s-snap-api/api/v1"
+	apiv1alpha "github.com/canonical/k8s/api/v1alpha"
 	cmdutil "github.com/canonical/k8s/cmd/util"
 	"github.com/canonical/k8s/pkg/k8sd/features"
+	"github.com/canonical/k8s/pkg/k8sd/types"
 	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
 )
 
+// helmValues are Helm chart values that print as YAML in plain output.
+type helmValues map[string]any
+
+func (v helmValues) String() string {
+	if len(v) == 0 {
+		return "{}"
+	}
+	b, err := yaml.Marshal(map[string]any(v))
+	if err != nil {
+		return fmt.Sprintf("%v", map[string]any(v))
+	}
+	return strings.TrimRight(string(b), "\n")
+}
+
 func newGetCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
 	var opts struct {
 		outputFormat string
@@ -20,7 +38,7 @@ func newGetCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:    "get <feature.key>",
 		Short:  "Get cluster configuration",
-		Long:   fmt.Sprintf("Show configuration of one of %s.", strings.Join(featureList, ", ")),
+		Long:   fmt.Sprintf("Show configuration of one of %s.\nUse `k8s get <feature>.values` to show the effective Helm values of a feature.", strings.Join(featureList, ", ")),
 		Args:   cmdutil.MaximumNArgs(env, 1),
 		PreRun: chainPreRunHooks(hookRequireRoot(env), hookInitializeFormatter(env, &opts.outputFormat)),
 		Run: func(cmd *cobra.Command, args []string) {
@@ -57,15 +75,43 @@ func newGetCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
 			}
 			config := response.Config
 
-			config.MetricsServer = apiv1.MetricsServerConfig{}
-			config.CloudProvider = nil
-			config.Annotations = nil
-
 			var key string
 			if len(args) == 1 {
 				key = args[0]
 			}
 
+			if feature, ok := strings.CutSuffix(key, ".values-overrides"); ok && slices.Contains(toggleableFeatures(), feature) {
+				overrides := helmValues{}
+				if value, ok := config.Annotations[types.ValuesOverridesAnnotation(types.FeatureName(feature))]; ok {
+					if err := yaml.Unmarshal([]byte(value), &overrides); err != nil {
+						cmd.PrintErrf("Error: Failed to parse the values overrides of %s.\n\nThe error was: %v\n", feature, err)
+						env.Exit(1)
+						return
+					}
+				}
+				outputFormatter.Print(overrides)
+				return
+			}
+
+			if feature, ok := strings.CutSuffix(key, ".values"); ok && slices.Contains(toggleableFeatures(), feature) {
+				response, err := client.GetFeatureValues(ctx, apiv1alpha.GetFeatureValuesRequest{Feature: feature})
+				if err != nil {
+					cmd.PrintErrf("Error: Failed to get the values of %s.\n\nThe error was: %v\n", feature, err)
+					env.Exit(1)
+					return
+				}
+				values := make(helmValues, len(response.Values))
+				for chart, v := range response.Values {
+					values[chart] = v
+				}
+				outputFormatter.Print(values)
+				return
+			}
+
+			config.MetricsServer = apiv1.MetricsServerConfig{}
+			config.CloudProvider = nil
+			config.Annotations = nil
+
 			var output any
 			switch key {
 			case "":
@@ -1,10 +1,46 @@
 package k8s
 
 import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	apiv1 "github.com/canonical/k8s-snap-api/api/v1"
+	apiv1alpha "github.com/canonical/k8s/api/v1alpha"
 	cmdutil "github.com/canonical/k8s/cmd/util"
 	"github.com/spf13/cobra"
 )
 
+// localNodeStatus is the status of the local node.
+// On worker nodes, localNodeStatus includes the kube-apiserver endpoints of the k8s-apiserver-proxy.
+type localNodeStatus struct {
+	apiv1.NodeStatus `yaml:",inline"`
+	APIServerProxy   *apiv1alpha.GetAPIServerProxyStatusResponse `json:"apiserver-proxy,omitempty" yaml:"apiserver-proxy,omitempty"`
+}
+
+func (s localNodeStatus) String() string {
+	if s.APIServerProxy == nil {
+		return fmt.Sprint(s.NodeStatus)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%v\n\napiserver-proxy endpoints:\n", s.NodeStatus)
+	w := tabwriter.NewWriter(&b, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "ADDRESS\tACTIVE\tCONNECTIONS\tDIAL ERRORS\tDEACTIVATIONS")
+	for _, ep := range s.APIServerProxy.Endpoints {
+		active := "yes"
+		switch {
+		case ep.Draining:
+			active = "draining"
+		case !ep.Active:
+			active = "no"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\n", ep.Address, active, ep.Connections, ep.DialErrors, ep.Deactivations)
+	}
+	w.Flush()
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
 func newLocalNodeStatusCommand(env cmdutil.ExecutionEnvironment) *cobra.Command {
 	var opts struct {
 		outputFormat string
@@ -34,7 +70,17 @@ func newLocalNodeStatusCommand(env cmdutil.ExecutionEnvironment) *cobra.Command
 				return
 			}
 
-			outputFormatter.Print(response.NodeStatus)
+			status := localNodeStatus{NodeStatus: response.NodeStatus}
+			if response.NodeStatus.ClusterRole == apiv1.ClusterRoleWorker {
+				proxyStatus, err := client.GetAPIServerProxyStatus(cmd.Context())
+				if err != nil {
+					cmd.PrintErrf("Warning: Failed to retrieve the k8s-apiserver-proxy status.\n\nThe error was: %v\n\n", err)
+				} else {
+					status.APIServerProxy = &proxyStatus
+				}
+			}
+
+			outputFormatter.Print(status)
 		},
 	}
 	cmd.Flags().StringVar(&opts.outputFormat, "output-format", "plain", "set the output format to one of plain, json or yaml")
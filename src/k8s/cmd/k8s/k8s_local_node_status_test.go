@@ -0,0 +1,104 @@
+package k8s_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	apiv1 "github.com/canonical/k8s-snap-api/api/v1"
+	apiv1alpha "github.com/canonical/k8s/api/v1alpha"
+	"github.com/canonical/k8s/cmd/k8s"
+	cmdutil "github.com/canonical/k8s/cmd/util"
+	k8sdmock "github.com/canonical/k8s/pkg/client/k8sd/mock"
+	snapmock "github.com/canonical/k8s/pkg/snap/mock"
+	. "github.com/onsi/gomega"
+)
+
+func TestLocalNodeStatusCmd(t *testing.T) {
+	proxyStatus := apiv1alpha.GetAPIServerProxyStatusResponse{
+		Endpoints: []apiv1alpha.APIServerProxyEndpoint{
+			{Address: "10.0.0.1:6443", Active: true, Connections: 4},
+			{Address: "10.0.0.2:6443", DialErrors: 2, Deactivations: 1},
+		},
+		Refreshes: 1,
+	}
+
+	tests := []struct {
+		name           string
+		args           []string
+		role           apiv1.ClusterRole
+		proxyErr       error
+		expectedStdout []string
+		expectedStderr string
+	}{
+		{
+			name:           "ControlPlane",
+			role:           apiv1.ClusterRoleControlPlane,
+			expectedStdout: []string{"{node1 10.0.0.1 control-plane }\n"},
+		},
+		{
+			name: "Worker",
+			role: apiv1.ClusterRoleWorker,
+			expectedStdout: []string{
+				"{node1 10.0.0.1 worker }\n\napiserver-proxy endpoints:\n",
+				"10.0.0.1:6443   yes      4",
+				"10.0.0.2:6443   no       0             2             1",
+			},
+		},
+		{
+			name:           "WorkerJSON",
+			args:           []string{"--output-format", "json"},
+			role:           apiv1.ClusterRoleWorker,
+			expectedStdout: []string{`"cluster-role": "worker"`, `"apiserver-proxy": {`, `"address": "10.0.0.2:6443"`},
+		},
+		{
+			name:           "WorkerProxyUnavailable",
+			role:           apiv1.ClusterRoleWorker,
+			proxyErr:       errors.New("connection refused"),
+			expectedStdout: []string{"{node1 10.0.0.1 worker }\n"},
+			expectedStderr: "Warning: Failed to retrieve the k8s-apiserver-proxy status.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+			mockClient := &k8sdmock.Mock{
+				NodeStatusResponse: apiv1.NodeStatusResponse{
+					NodeStatus: apiv1.NodeStatus{Name: "node1", Address: "10.0.0.1", ClusterRole: tt.role},
+				},
+				NodeStatusInitialized:           true,
+				GetAPIServerProxyStatusResponse: proxyStatus,
+				GetAPIServerProxyStatusErr:      tt.proxyErr,
+			}
+			var returnCode int
+			env := cmdutil.ExecutionEnvironment{
+				Stdout: stdout,
+				Stderr: stderr,
+				Getuid: func() int { return 0 },
+				Snap: &snapmock.Snap{
+					Mock: snapmock.Mock{
+						K8sdClient: mockClient,
+					},
+				},
+				Exit: func(rc int) { returnCode = rc },
+			}
+			cmd := k8s.NewRootCmd(env)
+
+			cmd.SetArgs(append([]string{"local-node-status"}, tt.args...))
+			cmd.Execute()
+
+			for _, s := range tt.expectedStdout {
+				g.Expect(stdout.String()).To(ContainSubstring(s))
+			}
+			g.Expect(stderr.String()).To(ContainSubstring(tt.expectedStderr))
+			g.Expect(returnCode).To(BeZero())
+			if tt.role != apiv1.ClusterRoleWorker {
+				g.Expect(stdout.String()).ToNot(ContainSubstring("apiserver-proxy"))
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	apiv1alpha "github.com/canonical/k8s/api/v1alpha"
+	cmdutil "github.com/canonical/k8s/cmd/util"
+	"github.com/spf13/cobra"
+)
+
+const migrateDatastoreLong = `Migrate the cluster datastore from k8s-dqlite to an external etcd cluster.
+
+The migration runs in the background of k8sd on this node in the following phases:
+
+  copying    all Kubernetes data is copied from k8s-dqlite to the external etcd
+             cluster, which must not contain any Kubernetes data. Keys with a
+             lease keep their remaining time to live. Changes made during the
+             copy are synced before the key counts of both datastores are verified.
+  cutover    the cluster configuration is updated to use the external datastore
+             and kube-apiserver is restarted on all control plane nodes.
+  verifying  once k8s-dqlite is no longer written to, changes made between the
+             copy and the kube-apiserver restarts are synced to the external
+             datastore. Keys that kube-apiserver changed in the external datastore
+             in the meantime are kept and reported as conflicts.
+
+By default, the command waits for the migration to complete. The progress of a
+migration started with --wait=false is shown by "k8s migrate-datastore --status".
+The migration stops if k8sd is restarted before it completes.
+The k8s-dqlite data is left in place and is not removed.`
+
+// datastoreMigration is the progress of a datastore migration.
+type datastoreMigration apiv1alpha.DatastoreMigration
+
+func (m datastoreMigration) String() string {
+	switch m.Phase {
+	case "completed":
+		return fmt.Sprintf("Migrated %d keys to the external datastore (source revision %d, target revision %d).\n%d keys changed after the migration were synced, %d conflicting keys kept their value in the external datastore.", m.Keys, m.SourceRevision, m.TargetRevision, m.CutoverSynced, m.CutoverConflicts)
+	case "failed":
+		return fmt.Sprintf("Datastore migration failed (started %s): %s", m.StartedAt.Local().Format(time.RFC3339), m.Message)
+	default:
+		return fmt.Sprintf("Datastore migration phase: %s (started %s, updated %s)", m.Phase, m.StartedAt.Local().Format(time.RFC3339), m.UpdatedAt.Local().Format(time.RFC3339))
+	}
+}
+
+func newMigrateDatastoreCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
+	var opts struct {
+		to           string
+		servers      []string
+		caCertPath   string
+		certPath     string
+		keyPath      string
+		status       bool
+		wait         bool
+		outputFormat string
+		timeout      time.Duration
+	}
+	cmd := &cobra.Command{
+		Use:    "migrate-datastore",
+		Short:  "Migrate the cluster datastore to an external etcd cluster",
+		Long:   migrateDatastoreLong,
+		Args:   cobra.NoArgs,
+		PreRun: chainPreRunHooks(hookRequireRoot(env), hookInitializeFormatter(env, &opts.outputFormat)),
+		Run: func(cmd *cobra.Command, args []string) {
+			var request apiv1alpha.MigrateDatastoreRequest
+			if !opts.status {
+				if opts.to != "external" {
+					cmd.PrintErrf("Error: Unsupported datastore type %q, only \"external\" is supported.\n", opts.to)
+					env.Exit(1)
+					return
+				}
+				if len(opts.servers) == 0 {
+					cmd.PrintErrln("Error: The --servers flag is required.")
+					env.Exit(1)
+					return
+				}
+				if (opts.certPath == "") != (opts.keyPath == "") {
+					cmd.PrintErrln("Error: The --client-crt and --client-key flags must be used together.")
+					env.Exit(1)
+					return
+				}
+
+				request = apiv1alpha.MigrateDatastoreRequest{
+					Type:    opts.to,
+					Servers: opts.servers,
+				}
+				for _, file := range []struct {
+					path string
+					val  *string
+				}{
+					{path: opts.caCertPath, val: &request.CACert},
+					{path: opts.certPath, val: &request.ClientCert},
+					{path: opts.keyPath, val: &request.ClientKey},
+				} {
+					if file.path == "" {
+						continue
+					}
+					b, err := os.ReadFile(file.path)
+					if err != nil {
+						cmd.PrintErrf("Error: Failed to read %q.\n\nThe error was: %v\n", file.path, err)
+						env.Exit(1)
+						return
+					}
+					*file.val = string(b)
+				}
+			}
+
+			client, err := env.Snap.K8sdClient("")
+			if err != nil {
+				cmd.PrintErrf("Error: Failed to create a k8sd client. Make sure that the k8sd service is running.\n\nThe error was: %v\n", err)
+				env.Exit(1)
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), opts.timeout)
+			cobra.OnFinalize(cancel)
+
+			if _, initialized, err := client.NodeStatus(cmd.Context()); err != nil {
+				cmd.PrintErrf("Error: Failed to check the current node status.\n\nThe error was: %v\n", err)
+				env.Exit(1)
+				return
+			} else if !initialized {
+				cmd.PrintErrln("Error: The node is not part of a Kubernetes cluster. You can bootstrap a new cluster with:\n\n  sudo k8s bootstrap")
+				env.Exit(1)
+				return
+			}
+
+			var migration apiv1alpha.DatastoreMigration
+			if opts.status {
+				response, err := client.GetDatastoreMigration(ctx)
+				if err != nil {
+					cmd.PrintErrf("Error: Failed to retrieve the datastore migration progress.\n\nThe error was: %v\n", err)
+					env.Exit(1)
+					return
+				}
+				if response.Migration == nil {
+					cmd.PrintErrln("Error: No datastore migration was started on this node.")
+					env.Exit(1)
+					return
+				}
+				migration = *response.Migration
+			} else {
+				response, err := client.MigrateDatastore(ctx, request)
+				if err != nil {
+					cmd.PrintErrf("Error: Failed to migrate the datastore.\n\nThe error was: %v\n", err)
+					env.Exit(1)
+					return
+				}
+				migration = response.Migration
+			}
+
+			if opts.wait && !opts.status {
+				phase := migration.Phase
+				cmd.PrintErrf("Datastore migration phase: %s\n", phase)
+				for migration.Phase != "completed" && migration.Phase != "failed" {
+					select {
+					case <-ctx.Done():
+						cmd.PrintErrf("Error: Timed out waiting for the datastore migration to complete. The migration continues in the background.\n\nThe error was: %v\n", ctx.Err())
+						env.Exit(1)
+						return
+					case <-time.After(5 * time.Second):
+					}
+
+					response, err := client.GetDatastoreMigration(ctx)
+					if err != nil {
+						cmd.PrintErrf("Error: Failed to retrieve the datastore migration progress.\n\nThe error was: %v\n", err)
+						env.Exit(1)
+						return
+					}
+					if response.Migration == nil {
+						cmd.PrintErrln("Error: The datastore migration was not found. k8sd may have been restarted.")
+						env.Exit(1)
+						return
+					}
+					migration = *response.Migration
+					if migration.Phase != phase {
+						phase = migration.Phase
+						cmd.PrintErrf("Datastore migration phase: %s\n", phase)
+					}
+				}
+			}
+
+			outputFormatter.Print(datastoreMigration(migration))
+			if migration.Phase == "failed" {
+				env.Exit(1)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.to, "to", "external", "the datastore type to migrate to. Only \"external\" is supported")
+	cmd.Flags().StringSliceVar(&opts.servers, "servers", nil, "comma-separated list of etcd servers of the external datastore, e.g. https://10.0.0.1:2379")
+	cmd.Flags().StringVar(&opts.caCertPath, "ca-crt", "", "path to the CA certificate of the external datastore")
+	cmd.Flags().StringVar(&opts.certPath, "client-crt", "", "path to the client certificate for the external datastore")
+	cmd.Flags().StringVar(&opts.keyPath, "client-key", "", "path to the client key for the external datastore")
+	cmd.Flags().BoolVar(&opts.status, "status", false, "show the progress of the latest datastore migration started on this node")
+	cmd.Flags().BoolVar(&opts.wait, "wait", true, "wait for the migration to complete")
+	cmd.Flags().StringVar(&opts.outputFormat, "output-format", "plain", "set the output format to one of plain, json or yaml")
+	cmd.Flags().DurationVar(&opts.timeout, "timeout", 2*time.Hour, "the max time to wait for the command to execute")
+
+	return cmd
+}
@@ -0,0 +1,129 @@
+package k8s_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	apiv1alpha "github.com/canonical/k8s/api/v1alpha"
+	"github.com/canonical/k8s/cmd/k8s"
+	cmdutil "github.com/canonical/k8s/cmd/util"
+	k8sdmock "github.com/canonical/k8s/pkg/client/k8sd/mock"
+	snapmock "github.com/canonical/k8s/pkg/snap/mock"
+	. "github.com/onsi/gomega"
+)
+
+func TestMigrateDatastoreCmd(t *testing.T) {
+	t0 := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	completed := apiv1alpha.DatastoreMigration{
+		Phase:            "completed",
+		StartedAt:        t0,
+		UpdatedAt:        t0,
+		Keys:             120,
+		SourceRevision:   1000,
+		TargetRevision:   130,
+		CutoverSynced:    4,
+		CutoverConflicts: 1,
+	}
+	running := apiv1alpha.DatastoreMigration{Phase: "copying", StartedAt: t0, UpdatedAt: t0}
+	failed := apiv1alpha.DatastoreMigration{Phase: "failed", StartedAt: t0, UpdatedAt: t0, Message: "target datastore is not empty"}
+
+	tests := []struct {
+		name            string
+		args            []string
+		migration       apiv1alpha.DatastoreMigration
+		status          *apiv1alpha.DatastoreMigration
+		err             error
+		expectedRequest apiv1alpha.MigrateDatastoreRequest
+		expectedCode    int
+		expectedStdout  string
+		expectedStderr  string
+	}{
+		{
+			name:            "completed",
+			args:            []string{"migrate-datastore", "--servers", "https://10.0.0.1:2379"},
+			migration:       completed,
+			expectedRequest: apiv1alpha.MigrateDatastoreRequest{Type: "external", Servers: []string{"https://10.0.0.1:2379"}},
+			expectedStdout:  "Migrated 120 keys to the external datastore",
+			expectedStderr:  "Datastore migration phase: completed",
+		},
+		{
+			name:            "no-wait",
+			args:            []string{"migrate-datastore", "--servers", "https://10.0.0.1:2379", "--wait=false"},
+			migration:       running,
+			expectedRequest: apiv1alpha.MigrateDatastoreRequest{Type: "external", Servers: []string{"https://10.0.0.1:2379"}},
+			expectedStdout:  "Datastore migration phase: copying",
+		},
+		{
+			name:            "failed",
+			args:            []string{"migrate-datastore", "--servers", "https://10.0.0.1:2379"},
+			migration:       failed,
+			expectedRequest: apiv1alpha.MigrateDatastoreRequest{Type: "external", Servers: []string{"https://10.0.0.1:2379"}},
+			expectedCode:    1,
+			expectedStdout:  "target datastore is not empty",
+		},
+		{
+			name:           "status",
+			args:           []string{"migrate-datastore", "--status"},
+			status:         &running,
+			expectedStdout: "Datastore migration phase: copying",
+		},
+		{
+			name:           "status-not-found",
+			args:           []string{"migrate-datastore", "--status"},
+			expectedCode:   1,
+			expectedStderr: "No datastore migration was started on this node",
+		},
+		{
+			name:           "missing-servers",
+			args:           []string{"migrate-datastore"},
+			expectedCode:   1,
+			expectedStderr: "The --servers flag is required",
+		},
+		{
+			name:            "error",
+			args:            []string{"migrate-datastore", "--servers", "https://10.0.0.1:2379"},
+			err:             fmt.Errorf("a datastore migration is already in progress"),
+			expectedRequest: apiv1alpha.MigrateDatastoreRequest{Type: "external", Servers: []string{"https://10.0.0.1:2379"}},
+			expectedCode:    1,
+			expectedStderr:  "a datastore migration is already in progress",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+			var returnCode int
+			client := &k8sdmock.Mock{
+				NodeStatusInitialized:         true,
+				MigrateDatastoreResponse:      apiv1alpha.MigrateDatastoreResponse{Migration: tt.migration},
+				MigrateDatastoreErr:           tt.err,
+				GetDatastoreMigrationResponse: apiv1alpha.GetDatastoreMigrationResponse{Migration: tt.status},
+			}
+			env := cmdutil.ExecutionEnvironment{
+				Stdout: stdout,
+				Stderr: stderr,
+				Getuid: func() int { return 0 },
+				Snap: &snapmock.Snap{
+					Mock: snapmock.Mock{
+						K8sdClient: client,
+					},
+				},
+				Exit: func(rc int) { returnCode = rc },
+			}
+			cmd := k8s.NewRootCmd(env)
+
+			cmd.SetArgs(tt.args)
+			cmd.Execute()
+
+			g.Expect(returnCode).To(Equal(tt.expectedCode))
+			g.Expect(stderr.String()).To(ContainSubstring(tt.expectedStderr))
+			g.Expect(stdout.String()).To(ContainSubstring(tt.expectedStdout))
+			g.Expect(client.MigrateDatastoreCalledWith).To(Equal(tt.expectedRequest))
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	apiv1alpha "github.com/canonical/k8s/api/v1alpha"
+	cmdutil "github.com/canonical/k8s/cmd/util"
+	"github.com/canonical/k8s/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+const rotateCALong = `Rotate the Kubernetes CA and client CA of the cluster.
+
+The rotation runs in the background on all cluster nodes in the following phases:
+
+  trust     the new CAs are added to the trust bundles of all nodes and kubeconfigs.
+  reissue   all certificates are reissued from the new CAs.
+  finalize  the old CAs are removed from the trust bundles of all nodes and kubeconfigs.
+
+Each phase is applied on all nodes before the rotation moves to the next phase.
+Control plane nodes restart their services one at a time, so the cluster remains
+available during the rotation. Worker nodes request their new certificates through
+certificate signing requests, which must be approved unless auto-approval is enabled.
+
+If a rotation is already in progress, it is resumed instead of starting a new one.
+The progress of the rotation is shown by "k8s certs-status".
+
+Kubeconfig files retrieved with "k8s config" before the rotation stop working once
+the rotation completes and have to be retrieved again.`
+
+// caRotation is the progress of a CA rotation that prints as a table in plain output.
+type caRotation apiv1alpha.CARotation
+
+func (r caRotation) String() string {
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 3, ' ', 0)
+	fmt.Fprintf(w, "CA rotation phase: %s (started %s, updated %s)\n\n", r.Phase, r.StartedAt.Local().Format(time.RFC3339), r.UpdatedAt.Local().Format(time.RFC3339))
+	fmt.Fprintln(w, "NODE\tPHASE\tUPDATED\tMESSAGE")
+	for _, node := range r.Nodes {
+		phase, updatedAt := node.Phase, ""
+		if phase == "" {
+			phase = "-"
+		}
+		if !node.UpdatedAt.IsZero() {
+			updatedAt = node.UpdatedAt.Local().Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", node.Node, phase, updatedAt, node.Message)
+	}
+	w.Flush()
+	return string(bytes.TrimRight(buf.Bytes(), "\n"))
+}
+
+func newRotateCACmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
+	var opts struct {
+		expiresIn    string
+		wait         bool
+		outputFormat string
+		timeout      time.Duration
+	}
+	cmd := &cobra.Command{
+		Use:    "rotate-ca",
+		Short:  "Rotate the Kubernetes CAs of the cluster",
+		Long:   rotateCALong,
+		Args:   cobra.NoArgs,
+		PreRun: chainPreRunHooks(hookRequireRoot(env), hookInitializeFormatter(env, &opts.outputFormat)),
+		Run: func(cmd *cobra.Command, args []string) {
+			ttl, err := utils.TTLToSeconds(opts.expiresIn)
+			if err != nil {
+				cmd.PrintErrf("Error: Failed to parse TTL.\n\nThe error was: %v\n", err)
+				env.Exit(1)
+				return
+			}
+
+			client, err := env.Snap.K8sdClient("")
+			if err != nil {
+				cmd.PrintErrf("Error: Failed to create a k8sd client. Make sure that the k8sd service is running.\n\nThe error was: %v\n", err)
+				env.Exit(1)
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), opts.timeout)
+			cobra.OnFinalize(cancel)
+
+			if _, initialized, err := client.NodeStatus(cmd.Context()); err != nil {
+				cmd.PrintErrf("Error: Failed to check the current node status.\n\nThe error was: %v\n", err)
+				env.Exit(1)
+				return
+			} else if !initialized {
+				cmd.PrintErrln("Error: The node is not part of a Kubernetes cluster. You can bootstrap a new cluster with:\n\n  sudo k8s bootstrap")
+				env.Exit(1)
+				return
+			}
+
+			response, err := client.RotateCA(ctx, apiv1alpha.RotateCARequest{ExpirationSeconds: ttl})
+			if err != nil {
+				cmd.PrintErrf("Error: Failed to rotate the Kubernetes CAs.\n\nThe error was: %v\n", err)
+				env.Exit(1)
+				return
+			}
+			rotation := response.Rotation
+
+			if opts.wait {
+				phase := rotation.Phase
+				cmd.PrintErrf("CA rotation phase: %s\n", phase)
+				for rotation.Phase != "completed" {
+					select {
+					case <-ctx.Done():
+						cmd.PrintErrf("Error: Timed out waiting for the CA rotation to complete. The rotation continues in the background.\n\nThe error was: %v\n", ctx.Err())
+						env.Exit(1)
+						return
+					case <-time.After(5 * time.Second):
+					}
+
+					response, err := client.GetCARotation(ctx)
+					if err != nil {
+						cmd.PrintErrf("Error: Failed to retrieve the CA rotation progress.\n\nThe error was: %v\n", err)
+						env.Exit(1)
+						return
+					}
+					if response.Rotation == nil {
+						cmd.PrintErrln("Error: The CA rotation was not found.")
+						env.Exit(1)
+						return
+					}
+					rotation = *response.Rotation
+					if rotation.Phase != phase {
+						phase = rotation.Phase
+						cmd.PrintErrf("CA rotation phase: %s\n", phase)
+					}
+				}
+			}
+
+			outputFormatter.Print(caRotation(rotation))
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.expiresIn, "expires-in", "20y", "the time until the new CAs expire, e.g. 10y")
+	cmd.Flags().BoolVar(&opts.wait, "wait", false, "wait for the rotation to complete")
+	cmd.Flags().StringVar(&opts.outputFormat, "output-format", "plain", "set the output format to one of plain, json or yaml")
+	cmd.Flags().DurationVar(&opts.timeout, "timeout", 2*time.Hour, "the max time to wait for the command to execute")
+
+	return cmd
+}
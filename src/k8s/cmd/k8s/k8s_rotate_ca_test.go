@@ -0,0 +1,104 @@
+package k8s_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	apiv1alpha "github.com/canonical/k8s/api/v1alpha"
+	"github.com/canonical/k8s/cmd/k8s"
+	cmdutil "github.com/canonical/k8s/cmd/util"
+	k8sdmock "github.com/canonical/k8s/pkg/client/k8sd/mock"
+	snapmock "github.com/canonical/k8s/pkg/snap/mock"
+	. "github.com/onsi/gomega"
+)
+
+func TestRotateCACmd(t *testing.T) {
+	t0 := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	rotation := apiv1alpha.CARotation{
+		Phase:     "reissue",
+		StartedAt: t0,
+		UpdatedAt: t0,
+		Nodes: []apiv1alpha.CARotationNode{
+			{Node: "cp1", Phase: "reissue", UpdatedAt: t0},
+			{Node: "cp2", Phase: "trust", Message: "waiting for node cp1 to finish rotating certificates", UpdatedAt: t0},
+			{Node: "worker1"},
+		},
+	}
+
+	tests := []struct {
+		name            string
+		args            []string
+		err             error
+		expectedRequest apiv1alpha.RotateCARequest
+		expectedCode    int
+		expectedStdout  []string
+		expectedStderr  string
+	}{
+		{
+			name:            "default",
+			args:            []string{"rotate-ca"},
+			expectedRequest: apiv1alpha.RotateCARequest{ExpirationSeconds: 20 * 365 * 24 * 60 * 60},
+			expectedStdout:  []string{"CA rotation phase: reissue", "NODE", "PHASE", "cp1", "waiting for node cp1", "worker1   -"},
+		},
+		{
+			name:            "expires-in",
+			args:            []string{"rotate-ca", "--expires-in", "10y"},
+			expectedRequest: apiv1alpha.RotateCARequest{ExpirationSeconds: 10 * 365 * 24 * 60 * 60},
+			expectedStdout:  []string{"CA rotation phase: reissue"},
+		},
+		{
+			name:           "invalid-ttl",
+			args:           []string{"rotate-ca", "--expires-in", "10"},
+			expectedCode:   1,
+			expectedStderr: "Failed to parse TTL",
+		},
+		{
+			name:           "error",
+			args:           []string{"rotate-ca"},
+			err:            fmt.Errorf("rotating an external Kubernetes CA is not supported"),
+			expectedCode:   1,
+			expectedStderr: "rotating an external Kubernetes CA is not supported",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+			var returnCode int
+			client := &k8sdmock.Mock{
+				NodeStatusInitialized: true,
+				RotateCAResponse:      apiv1alpha.RotateCAResponse{Rotation: rotation},
+				RotateCAErr:           tt.err,
+			}
+			env := cmdutil.ExecutionEnvironment{
+				Stdout: stdout,
+				Stderr: stderr,
+				Getuid: func() int { return 0 },
+				Snap: &snapmock.Snap{
+					Mock: snapmock.Mock{
+						K8sdClient: client,
+					},
+				},
+				Exit: func(rc int) { returnCode = rc },
+			}
+			cmd := k8s.NewRootCmd(env)
+
+			cmd.SetArgs(tt.args)
+			cmd.Execute()
+
+			g.Expect(returnCode).To(Equal(tt.expectedCode))
+			g.Expect(stderr.String()).To(ContainSubstring(tt.expectedStderr))
+			for _, expected := range tt.expectedStdout {
+				g.Expect(stdout.String()).To(ContainSubstring(expected))
+			}
+			if tt.expectedCode == 0 {
+				g.Expect(client.RotateCACalledWith).To(Equal(tt.expectedRequest))
+			}
+		})
+	}
+}
@@ -3,12 +3,14 @@ package k8s
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
 	apiv1 "github.com/canonical/k8s-snap-api/api/v1"
 	cmdutil "github.com/canonical/k8s/cmd/util"
 	"github.com/canonical/k8s/pkg/k8sd/features"
+	"github.com/canonical/k8s/pkg/k8sd/types"
 	"github.com/canonical/k8s/pkg/utils"
 	"github.com/mitchellh/mapstructure"
 	"github.com/spf13/cobra"
@@ -30,7 +32,7 @@ func newSetCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:    "set <feature.key=value> ...",
 		Short:  "Set cluster configuration",
-		Long:   fmt.Sprintf("Configure one of %s.\nUse `k8s get` to explore configuration options.", strings.Join(featureList, ", ")),
+		Long:   fmt.Sprintf("Configure one of %s.\nUse `k8s get` to explore configuration options.\nUse `k8s set <feature>.values-overrides=<yaml>` to override the Helm values of the charts of a feature, keyed by chart name, or `-` to remove the overrides.", strings.Join(featureList, ", ")),
 		Args:   cmdutil.MinimumNArgs(env, 1),
 		PreRun: chainPreRunHooks(hookRequireRoot(env), hookInitializeFormatter(env, &opts.outputFormat)),
 		Run: func(cmd *cobra.Command, args []string) {
@@ -126,6 +128,14 @@ func updateConfigMapstructure(config *apiv1.UserFacingClusterConfig, arg string)
 	key := parts[0]
 	value := parts[1]
 
+	if feature, ok := strings.CutSuffix(key, ".values-overrides"); ok && slices.Contains(toggleableFeatures(), feature) {
+		if config.Annotations == nil {
+			config.Annotations = map[string]string{}
+		}
+		config.Annotations[types.ValuesOverridesAnnotation(types.FeatureName(feature))] = value
+		return nil
+	}
+
 	if _, ok := knownSetKeys[key]; !ok {
 		return fmt.Errorf("unknown option key %q", key)
 	}
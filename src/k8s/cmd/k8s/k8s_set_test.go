@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	apiv1 "github.com/canonical/k8s-snap-api/api/v1"
+	k8sdtypes "github.com/canonical/k8s/pkg/k8sd/types"
 	"github.com/canonical/k8s/pkg/utils"
 	. "github.com/onsi/gomega"
 	"github.com/onsi/gomega/types"
@@ -199,3 +200,17 @@ func Test_updateConfigMapstructure(t *testing.T) {
 		}
 	}
 }
+
+func Test_updateConfigMapstructureValuesOverrides(t *testing.T) {
+	g := NewWithT(t)
+
+	var cfg apiv1.UserFacingClusterConfig
+	g.Expect(updateConfigMapstructure(&cfg, "ingress.values-overrides={ck-ingress: {controller: {replicas: 2}}}")).To(Succeed())
+	g.Expect(updateConfigMapstructure(&cfg, "dns.values-overrides=-")).To(Succeed())
+	g.Expect(cfg.Annotations).To(Equal(map[string]string{
+		k8sdtypes.ValuesOverridesAnnotation("ingress"): "{ck-ingress: {controller: {replicas: 2}}}",
+		k8sdtypes.ValuesOverridesAnnotation("dns"):     "-",
+	}))
+
+	g.Expect(updateConfigMapstructure(&cfg, "unknown.values-overrides={}")).ToNot(Succeed())
+}
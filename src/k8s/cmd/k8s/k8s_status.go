@@ -1,17 +1,87 @@
 package k8s
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"text/tabwriter"
 	"time"
 
 	apiv1 "github.com/canonical/k8s-snap-api/api/v1"
+	apiv1alpha "github.com/canonical/k8s/api/v1alpha"
 	cmdutil "github.com/canonical/k8s/cmd/util"
+	"github.com/canonical/lxd/shared/api"
 	"github.com/spf13/cobra"
 )
 
+// featureStatus is the status of a single feature that prints the reconciliation history as a table in plain output.
+type featureStatus struct {
+	Name    string                        `json:"name" yaml:"name"`
+	Status  apiv1.FeatureStatus           `json:"status" yaml:"status"`
+	Waiting bool                          `json:"waiting,omitempty" yaml:"waiting,omitempty"`
+	History []apiv1alpha.FeatureReconcile `json:"history,omitempty" yaml:"history,omitempty"`
+
+	showHistory bool
+}
+
+func (s featureStatus) String() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s: %s", s.Name, s.Status)
+	if !s.showHistory {
+		return buf.String()
+	}
+
+	if len(s.History) == 0 {
+		buf.WriteString("\n\nNo reconciliation history found.")
+		return buf.String()
+	}
+
+	buf.WriteString("\n\n")
+	w := tabwriter.NewWriter(&buf, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "STARTED\tDURATION\tRESULT\tVERSION\tVALUES\tERROR")
+	for _, r := range s.History {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			r.StartedAt.Local().Format(time.RFC3339),
+			r.FinishedAt.Sub(r.StartedAt).Round(time.Millisecond),
+			r.Result, r.Version, r.ValuesHash, r.Error,
+		)
+	}
+	w.Flush()
+	return string(bytes.TrimRight(buf.Bytes(), "\n"))
+}
+
+// certificateRotations is the automatic certificate rotation progress of the cluster nodes that prints as a table in plain output.
+type certificateRotations []apiv1alpha.CertificateRotation
+
+func (r certificateRotations) String() string {
+	if len(r) == 0 {
+		return "No certificate rotations found."
+	}
+
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "NODE\tSTATE\tCERTIFICATES\tUPDATED\tMESSAGE")
+	for _, rotation := range r {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			rotation.Node,
+			rotation.State,
+			strings.Join(rotation.Certificates, ","),
+			rotation.UpdatedAt.Local().Format(time.RFC3339),
+			rotation.Message,
+		)
+	}
+	w.Flush()
+	return string(bytes.TrimRight(buf.Bytes(), "\n"))
+}
+
 func newStatusCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
 	var opts struct {
 		waitReady    bool
+		feature      string
+		history      bool
+		rotation     bool
 		outputFormat string
 		timeout      time.Duration
 	}
@@ -27,6 +97,12 @@ func newStatusCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
 				opts.timeout = minTimeout
 			}
 
+			if opts.history && opts.feature == "" {
+				cmd.PrintErrln("Error: The --history flag requires a feature, e.g. --feature ingress.")
+				env.Exit(1)
+				return
+			}
+
 			client, err := env.Snap.K8sdClient("")
 			if err != nil {
 				cmd.PrintErrf("Error: Failed to create a k8sd client. Make sure that the k8sd service is running.\n\nThe error was: %v\n", err)
@@ -47,6 +123,34 @@ func newStatusCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
 				return
 			}
 
+			if opts.rotation {
+				response, err := client.ListCertificateRotations(ctx)
+				if err != nil {
+					cmd.PrintErrf("Error: Failed to retrieve the certificate rotation status.\n\nThe error was: %v\n", err)
+					env.Exit(1)
+					return
+				}
+
+				outputFormatter.Print(certificateRotations(response.Rotations))
+				return
+			}
+
+			if opts.feature != "" {
+				response, err := client.GetFeatureStatus(ctx, apiv1alpha.GetFeatureStatusRequest{Feature: opts.feature, History: opts.history})
+				if err != nil {
+					if api.StatusErrorCheck(err, http.StatusNotFound) {
+						cmd.PrintErrf("Error: Unknown feature %q.\n", opts.feature)
+					} else {
+						cmd.PrintErrf("Error: Failed to retrieve the status of feature %q.\n\nThe error was: %v\n", opts.feature, err)
+					}
+					env.Exit(1)
+					return
+				}
+
+				outputFormatter.Print(featureStatus{Name: opts.feature, Status: response.Status, Waiting: response.Waiting, History: response.History, showHistory: opts.history})
+				return
+			}
+
 			response, err := client.ClusterStatus(ctx, opts.waitReady)
 			if err != nil {
 				cmd.PrintErrf("Error: Failed to retrieve the cluster status.\n\nThe error was: %v\n", err)
@@ -63,6 +167,9 @@ func newStatusCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
 	}
 
 	cmd.Flags().BoolVar(&opts.waitReady, "wait-ready", false, "wait until at least one cluster node is ready")
+	cmd.Flags().StringVar(&opts.feature, "feature", "", "retrieve the status of a single feature, e.g. ingress")
+	cmd.Flags().BoolVar(&opts.history, "history", false, "include the reconciliation history of the feature")
+	cmd.Flags().BoolVar(&opts.rotation, "certificate-rotation", false, "retrieve the progress of the automatic certificate rotation of the cluster nodes")
 	cmd.Flags().StringVar(&opts.outputFormat, "output-format", "plain", "set the output format to one of plain, json or yaml")
 	cmd.Flags().DurationVar(&opts.timeout, "timeout", 90*time.Second, "the max time to wait for the command to execute")
 	return cmd
@@ -0,0 +1,161 @@
+package k8s_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	apiv1 "github.com/canonical/k8s-snap-api/api/v1"
+	apiv1alpha "github.com/canonical/k8s/api/v1alpha"
+	"github.com/canonical/k8s/cmd/k8s"
+	cmdutil "github.com/canonical/k8s/cmd/util"
+	k8sdmock "github.com/canonical/k8s/pkg/client/k8sd/mock"
+	snapmock "github.com/canonical/k8s/pkg/snap/mock"
+	. "github.com/onsi/gomega"
+)
+
+func TestStatusCmdFeature(t *testing.T) {
+	t0 := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name            string
+		args            []string
+		waiting         bool
+		expectedRequest apiv1alpha.GetFeatureStatusRequest
+		expectedCode    int
+		expectedStdout  []string
+		expectedStderr  string
+	}{
+		{
+			name:            "feature",
+			args:            []string{"--feature", "ingress"},
+			expectedRequest: apiv1alpha.GetFeatureStatusRequest{Feature: "ingress"},
+			expectedStdout:  []string{"ingress: failed to deploy"},
+		},
+		{
+			name:            "history",
+			args:            []string{"--feature", "ingress", "--history"},
+			expectedRequest: apiv1alpha.GetFeatureStatusRequest{Feature: "ingress", History: true},
+			expectedStdout:  []string{"ingress: failed to deploy", "STARTED", "2s", "failed", "1.2.3", "abcdef", "chart not found"},
+		},
+		{
+			name:            "waiting",
+			args:            []string{"--feature", "ingress", "--output-format", "json"},
+			waiting:         true,
+			expectedRequest: apiv1alpha.GetFeatureStatusRequest{Feature: "ingress"},
+			expectedStdout:  []string{`"waiting": true`},
+		},
+		{
+			name:           "history-without-feature",
+			args:           []string{"--history"},
+			expectedStderr: "Error: The --history flag requires a feature",
+			expectedCode:   1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+			mockClient := &k8sdmock.Mock{
+				NodeStatusInitialized: true,
+				GetFeatureStatusResponse: apiv1alpha.GetFeatureStatusResponse{
+					Status:  apiv1.FeatureStatus{Message: "failed to deploy"},
+					Waiting: tt.waiting,
+					History: []apiv1alpha.FeatureReconcile{{
+						StartedAt:  t0,
+						FinishedAt: t0.Add(2 * time.Second),
+						Result:     "failed",
+						Error:      "chart not found",
+						Version:    "1.2.3",
+						ValuesHash: "abcdef",
+					}},
+				},
+			}
+			var returnCode int
+			env := cmdutil.ExecutionEnvironment{
+				Stdout: stdout,
+				Stderr: stderr,
+				Getuid: func() int { return 0 },
+				Snap: &snapmock.Snap{
+					Mock: snapmock.Mock{
+						K8sdClient: mockClient,
+					},
+				},
+				Exit: func(rc int) { returnCode = rc },
+			}
+			cmd := k8s.NewRootCmd(env)
+
+			cmd.SetArgs(append([]string{"status"}, tt.args...))
+			cmd.Execute()
+
+			for _, expected := range tt.expectedStdout {
+				g.Expect(stdout.String()).To(ContainSubstring(expected))
+			}
+			g.Expect(stderr.String()).To(ContainSubstring(tt.expectedStderr))
+			g.Expect(returnCode).To(Equal(tt.expectedCode))
+			g.Expect(mockClient.GetFeatureStatusCalledWith).To(Equal(tt.expectedRequest))
+		})
+	}
+}
+
+func TestStatusCmdCertificateRotation(t *testing.T) {
+	t0 := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		response       apiv1alpha.ListCertificateRotationsResponse
+		expectedStdout []string
+	}{
+		{
+			name: "rotations",
+			response: apiv1alpha.ListCertificateRotationsResponse{
+				Rotations: []apiv1alpha.CertificateRotation{
+					{Node: "cp1", State: "completed", Certificates: []string{"apiserver", "kubelet"}, UpdatedAt: t0},
+					{Node: "cp2", State: "waiting", Certificates: []string{"kubelet"}, Message: "waiting for node cp1 to finish rotating certificates", UpdatedAt: t0},
+				},
+			},
+			expectedStdout: []string{"NODE", "STATE", "cp1", "completed", "apiserver,kubelet", "cp2", "waiting for node cp1"},
+		},
+		{
+			name:           "empty",
+			expectedStdout: []string{"No certificate rotations found."},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+			var returnCode int
+			env := cmdutil.ExecutionEnvironment{
+				Stdout: stdout,
+				Stderr: stderr,
+				Getuid: func() int { return 0 },
+				Snap: &snapmock.Snap{
+					Mock: snapmock.Mock{
+						K8sdClient: &k8sdmock.Mock{
+							NodeStatusInitialized:            true,
+							ListCertificateRotationsResponse: tt.response,
+						},
+					},
+				},
+				Exit: func(rc int) { returnCode = rc },
+			}
+			cmd := k8s.NewRootCmd(env)
+
+			cmd.SetArgs([]string{"status", "--certificate-rotation"})
+			cmd.Execute()
+
+			for _, expected := range tt.expectedStdout {
+				g.Expect(stdout.String()).To(ContainSubstring(expected))
+			}
+			g.Expect(stderr.String()).To(BeEmpty())
+			g.Expect(returnCode).To(Equal(0))
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	apiv1alpha "github.com/canonical/k8s/api/v1alpha"
+	cmdutil "github.com/canonical/k8s/cmd/util"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/spf13/cobra"
+)
+
+// kubernetesAuthTokens is a list of Kubernetes auth tokens that prints as a table in plain output.
+type kubernetesAuthTokens []apiv1alpha.KubernetesAuthToken
+
+func (k kubernetesAuthTokens) String() string {
+	if len(k) == 0 {
+		return "No auth tokens found."
+	}
+
+	now := time.Now()
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "ID\tUSER\tGROUPS\tEXPIRES AT\tLAST USED\tISSUED BY")
+	for _, t := range k {
+		groups := strings.Join(t.Groups, ",")
+		if groups == "" {
+			groups = "-"
+		}
+		expiresAt := "never"
+		if t.ExpiresAt != nil {
+			expiresAt = t.ExpiresAt.Local().Format(time.RFC3339)
+			if !now.Before(*t.ExpiresAt) {
+				expiresAt += " (expired)"
+			}
+		}
+		lastUsed := "never"
+		if t.LastUsed != nil {
+			lastUsed = t.LastUsed.Local().Format(time.RFC3339)
+		}
+		issuedBy := t.IssuedBy
+		if issuedBy == "" {
+			issuedBy = "-"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\n", t.ID, t.Username, groups, expiresAt, lastUsed, issuedBy)
+	}
+	w.Flush()
+	return string(bytes.TrimRight(buf.Bytes(), "\n"))
+}
+
+// authTokensRevokeResult is the result of revoking Kubernetes auth tokens.
+type authTokensRevokeResult struct {
+	// Revoked is the number of revoked tokens.
+	Revoked int64 `json:"revoked" yaml:"revoked"`
+}
+
+func (r authTokensRevokeResult) String() string {
+	if r.Revoked == 1 {
+		return "Revoked 1 auth token."
+	}
+	return fmt.Sprintf("Revoked %d auth tokens.", r.Revoked)
+}
+
+func newXAuthTokensCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
+	var opts struct {
+		outputFormat string
+		timeout      time.Duration
+		user         string
+		id           int64
+	}
+
+	listCmd := &cobra.Command{
+		Use:    "list",
+		Short:  "List the Kubernetes auth tokens",
+		Args:   cobra.NoArgs,
+		PreRun: chainPreRunHooks(hookRequireRoot(env), hookInitializeFormatter(env, &opts.outputFormat)),
+		Run: func(cmd *cobra.Command, args []string) {
+			if opts.timeout < minTimeout {
+				cmd.PrintErrf("Timeout %v is less than minimum of %v. Using the minimum %v instead.\n", opts.timeout, minTimeout, minTimeout)
+				opts.timeout = minTimeout
+			}
+
+			client, err := env.Snap.K8sdClient("")
+			if err != nil {
+				cmd.PrintErrf("Error: Failed to create a k8sd client. Make sure that the k8sd service is running.\n\nThe error was: %v\n", err)
+				env.Exit(1)
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), opts.timeout)
+			cobra.OnFinalize(cancel)
+
+			response, err := client.ListKubernetesAuthTokens(ctx, apiv1alpha.ListKubernetesAuthTokensRequest{Username: opts.user})
+			if err != nil {
+				cmd.PrintErrf("Error: Failed to list the auth tokens.\n\nThe error was: %v\n", err)
+				env.Exit(1)
+				return
+			}
+
+			outputFormatter.Print(kubernetesAuthTokens(response.Tokens))
+		},
+	}
+
+	revokeCmd := &cobra.Command{
+		Use:    "revoke (--user <user> | --id <id>)",
+		Short:  "Revoke a Kubernetes auth token, or all auth tokens of a user",
+		Args:   cobra.NoArgs,
+		PreRun: chainPreRunHooks(hookRequireRoot(env), hookInitializeFormatter(env, &opts.outputFormat)),
+		Run: func(cmd *cobra.Command, args []string) {
+			if (opts.user == "") == (opts.id == 0) {
+				cmd.PrintErrln("Error: Exactly one of --user and --id must be set.")
+				env.Exit(1)
+				return
+			}
+			if opts.id < 0 {
+				cmd.PrintErrf("Error: Invalid auth token ID %d. You can list the auth tokens with:\n\n  sudo k8s x-auth-tokens list\n", opts.id)
+				env.Exit(1)
+				return
+			}
+
+			if opts.timeout < minTimeout {
+				cmd.PrintErrf("Timeout %v is less than minimum of %v. Using the minimum %v instead.\n", opts.timeout, minTimeout, minTimeout)
+				opts.timeout = minTimeout
+			}
+
+			client, err := env.Snap.K8sdClient("")
+			if err != nil {
+				cmd.PrintErrf("Error: Failed to create a k8sd client. Make sure that the k8sd service is running.\n\nThe error was: %v\n", err)
+				env.Exit(1)
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), opts.timeout)
+			cobra.OnFinalize(cancel)
+
+			response, err := client.RevokeKubernetesAuthTokens(ctx, apiv1alpha.RevokeKubernetesAuthTokensRequest{ID: opts.id, Username: opts.user})
+			if err != nil {
+				if api.StatusErrorCheck(err, http.StatusNotFound) {
+					cmd.PrintErrf("Error: Auth token %d does not exist. You can list the auth tokens with:\n\n  sudo k8s x-auth-tokens list\n", opts.id)
+				} else {
+					cmd.PrintErrf("Error: Failed to revoke the auth tokens.\n\nThe error was: %v\n", err)
+				}
+				env.Exit(1)
+				return
+			}
+
+			outputFormatter.Print(authTokensRevokeResult{Revoked: response.Revoked})
+		},
+	}
+
+	listCmd.Flags().StringVar(&opts.user, "user", "", "only list the auth tokens of this user")
+	revokeCmd.Flags().StringVar(&opts.user, "user", "", "revoke all auth tokens of this user")
+	revokeCmd.Flags().Int64Var(&opts.id, "id", 0, "revoke the auth token with this ID")
+	for _, cmd := range []*cobra.Command{listCmd, revokeCmd} {
+		cmd.Flags().StringVar(&opts.outputFormat, "output-format", "plain", "set the output format to one of plain, json or yaml")
+		cmd.Flags().DurationVar(&opts.timeout, "timeout", 90*time.Second, "the max time to wait for the command to execute")
+	}
+
+	cmd := &cobra.Command{
+		Use:    "x-auth-tokens",
+		Short:  "Manage the Kubernetes auth tokens of k8sd",
+		Hidden: true,
+	}
+
+	cmd.AddCommand(listCmd)
+	cmd.AddCommand(revokeCmd)
+
+	return cmd
+}
@@ -0,0 +1,101 @@
+package k8s_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	apiv1alpha "github.com/canonical/k8s/api/v1alpha"
+	"github.com/canonical/k8s/cmd/k8s"
+	cmdutil "github.com/canonical/k8s/cmd/util"
+	k8sdmock "github.com/canonical/k8s/pkg/client/k8sd/mock"
+	snapmock "github.com/canonical/k8s/pkg/snap/mock"
+	"github.com/canonical/k8s/pkg/utils"
+	. "github.com/onsi/gomega"
+)
+
+func TestXAuthTokensCmd(t *testing.T) {
+	tests := []struct {
+		name           string
+		args           []string
+		expectedCode   int
+		expectedStdout string
+		expectedStderr string
+		expectedList   apiv1alpha.ListKubernetesAuthTokensRequest
+		expectedRevoke apiv1alpha.RevokeKubernetesAuthTokensRequest
+	}{
+		{
+			name:           "list",
+			args:           []string{"list"},
+			expectedStdout: "never",
+		},
+		{
+			name:           "list-user",
+			args:           []string{"list", "--user", "alice"},
+			expectedStdout: "alice",
+			expectedList:   apiv1alpha.ListKubernetesAuthTokensRequest{Username: "alice"},
+		},
+		{
+			name:           "revoke-user",
+			args:           []string{"revoke", "--user", "alice"},
+			expectedStdout: "Revoked 2 auth tokens.",
+			expectedRevoke: apiv1alpha.RevokeKubernetesAuthTokensRequest{Username: "alice"},
+		},
+		{
+			name:           "revoke-id",
+			args:           []string{"revoke", "--id", "3"},
+			expectedStdout: "Revoked 2 auth tokens.",
+			expectedRevoke: apiv1alpha.RevokeKubernetesAuthTokensRequest{ID: 3},
+		},
+		{
+			name:           "revoke-none",
+			args:           []string{"revoke"},
+			expectedStderr: "Error: Exactly one of --user and --id must be set.",
+			expectedCode:   1,
+		},
+		{
+			name:           "revoke-both",
+			args:           []string{"revoke", "--user", "alice", "--id", "3"},
+			expectedStderr: "Error: Exactly one of --user and --id must be set.",
+			expectedCode:   1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+			mockClient := &k8sdmock.Mock{
+				ListKubernetesAuthTokensResponse: apiv1alpha.ListKubernetesAuthTokensResponse{Tokens: []apiv1alpha.KubernetesAuthToken{
+					{ID: 1, Username: "system:kube-proxy"},
+					{ID: 3, Username: "alice", Groups: []string{"dev"}, ExpiresAt: utils.Pointer(time.Now().Add(time.Hour)), LastUsed: utils.Pointer(time.Now()), IssuedBy: "root"},
+				}},
+				RevokeKubernetesAuthTokensResponse: apiv1alpha.RevokeKubernetesAuthTokensResponse{Revoked: 2},
+			}
+			var returnCode int
+			env := cmdutil.ExecutionEnvironment{
+				Stdout: stdout,
+				Stderr: stderr,
+				Getuid: func() int { return 0 },
+				Snap: &snapmock.Snap{
+					Mock: snapmock.Mock{
+						K8sdClient: mockClient,
+					},
+				},
+				Exit: func(rc int) { returnCode = rc },
+			}
+			cmd := k8s.NewRootCmd(env)
+
+			cmd.SetArgs(append([]string{"x-auth-tokens"}, tt.args...))
+			cmd.Execute()
+
+			g.Expect(stdout.String()).To(ContainSubstring(tt.expectedStdout))
+			g.Expect(stderr.String()).To(ContainSubstring(tt.expectedStderr))
+			g.Expect(returnCode).To(Equal(tt.expectedCode))
+			g.Expect(mockClient.ListKubernetesAuthTokensCalledWith).To(Equal(tt.expectedList))
+			g.Expect(mockClient.RevokeKubernetesAuthTokensCalledWith).To(Equal(tt.expectedRevoke))
+		})
+	}
+}
@@ -56,6 +56,27 @@ func newXWaitForCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
 	}
 	waitForNetworkCmd.Flags().DurationVar(&opts.timeout, "timeout", 5*time.Minute, "maximum time to wait")
 
+	waitForCertManagerCmd := &cobra.Command{
+		Use:   string(features.CertManager),
+		Short: "Wait for cert-manager to be ready",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx, cancel := context.WithTimeout(cmd.Context(), opts.timeout)
+			defer cancel()
+			if err := control.WaitUntilReady(ctx, func() (bool, error) {
+				err := features.StatusChecks.CheckCertManager(cmd.Context(), env.Snap)
+				if err != nil {
+					cmd.PrintErrf("cert-manager not ready yet: %v\n", err.Error())
+				}
+				return err == nil, nil
+			}); err != nil {
+				cmd.PrintErrf("Error: cert-manager did not become ready: %v\n", err)
+				env.Exit(1)
+			}
+		},
+	}
+	waitForCertManagerCmd.Flags().DurationVar(&opts.timeout, "timeout", 5*time.Minute, "maximum time to wait")
+
 	cmd := &cobra.Command{
 		Use:    "x-wait-for",
 		Short:  "Wait for the cluster's feature to be in a ready state",
@@ -64,6 +85,7 @@ func newXWaitForCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
 
 	cmd.AddCommand(waitForDNSCmd)
 	cmd.AddCommand(waitForNetworkCmd)
+	cmd.AddCommand(waitForCertManagerCmd)
 
 	return cmd
 }
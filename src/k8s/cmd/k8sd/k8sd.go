@@ -24,6 +24,13 @@ var rootCmdOpts struct {
 	disableUpgradeController            bool
 	drainConnectionsTimeout             time.Duration
 	featureControllerMaxRetryAttempts   int
+	backupInterval                      time.Duration
+	backupRetention                     int
+	backupDir                           string
+	certificateRotationWindow           time.Duration
+	certificateRotationInterval         time.Duration
+	authTokenSweepInterval              time.Duration
+	metricsBindAddress                  string
 }
 
 func addCommands(root *cobra.Command, group *cobra.Group, commands ...*cobra.Command) {
@@ -63,6 +70,13 @@ func NewRootCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
 				DisableUpgradeController:            rootCmdOpts.disableUpgradeController,
 				DrainConnectionsTimeout:             rootCmdOpts.drainConnectionsTimeout,
 				FeatureControllerMaxRetryAttempts:   rootCmdOpts.featureControllerMaxRetryAttempts,
+				BackupInterval:                      rootCmdOpts.backupInterval,
+				BackupRetention:                     rootCmdOpts.backupRetention,
+				BackupDir:                           rootCmdOpts.backupDir,
+				CertificateRotationWindow:           rootCmdOpts.certificateRotationWindow,
+				CertificateRotationInterval:         rootCmdOpts.certificateRotationInterval,
+				AuthTokenSweepInterval:              rootCmdOpts.authTokenSweepInterval,
+				MetricsBindAddress:                  rootCmdOpts.metricsBindAddress,
 			})
 			if err != nil {
 				cmd.PrintErrf("Error: Failed to initialize k8sd: %v", err)
@@ -99,6 +113,13 @@ func NewRootCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
 	cmd.Flags().MarkDeprecated("port", "this flag does not have any effect, and will be removed in a future version")
 	cmd.Flags().DurationVar(&rootCmdOpts.drainConnectionsTimeout, "drain-connection-timeout", 10*time.Second, "amount of time to allow for all connections to drain when shutting down")
 	cmd.Flags().IntVar(&rootCmdOpts.featureControllerMaxRetryAttempts, "feature-controller-max-retry-attempts", 64, "Maximum number of retry attempts for the feature controller before giving up. Zero or negative values mean no limit.")
+	cmd.Flags().DurationVar(&rootCmdOpts.backupInterval, "backup-interval", 0, "Interval between scheduled datastore backups, e.g. \"24h\". Zero disables scheduled backups.")
+	cmd.Flags().IntVar(&rootCmdOpts.backupRetention, "backup-retention", 7, "Number of scheduled datastore backups to keep.")
+	cmd.Flags().StringVar(&rootCmdOpts.backupDir, "backup-dir", "", "Directory to store datastore backups. Defaults to /var/snap/k8s/common/var/lib/k8s-backups.")
+	cmd.Flags().DurationVar(&rootCmdOpts.certificateRotationWindow, "certificate-rotation-window", 30*24*time.Hour, "Renew the node certificates automatically when they expire within this window. Zero disables automatic certificate rotation.")
+	cmd.Flags().DurationVar(&rootCmdOpts.certificateRotationInterval, "certificate-rotation-interval", time.Hour, "Interval between checks for expiring node certificates.")
+	cmd.Flags().DurationVar(&rootCmdOpts.authTokenSweepInterval, "auth-token-sweep-interval", 10*time.Minute, "Interval between deletions of expired Kubernetes auth tokens. Zero disables the deletion of expired tokens.")
+	cmd.Flags().StringVar(&rootCmdOpts.metricsBindAddress, "metrics-bind-address", "127.0.0.1:9841", "Listen address for the HTTPS metrics endpoint. Use \":9841\" to allow Prometheus to scrape the metrics from other hosts, or \"0\" to disable the metrics endpoint.")
 
 	cmd.AddCommand(newSqlCmd(env))
 
@@ -0,0 +1,27 @@
+package helm
+
+// MergeValues deep-merges overrides into values and returns the result. The inputs are not modified.
+// Nested maps are merged recursively, all other values in overrides replace the respective values.
+// A nil value in overrides removes the respective key, as with "helm install --set key=null".
+func MergeValues(values map[string]any, overrides map[string]any) map[string]any {
+	result := make(map[string]any, len(values)+len(overrides))
+	for key, value := range values {
+		result[key] = value
+	}
+	for key, override := range overrides {
+		if override == nil {
+			delete(result, key)
+			continue
+		}
+		overrideMap, overrideIsMap := override.(map[string]any)
+		valueMap, valueIsMap := result[key].(map[string]any)
+		if overrideIsMap && valueIsMap {
+			result[key] = MergeValues(valueMap, overrideMap)
+		} else if overrideIsMap {
+			result[key] = MergeValues(nil, overrideMap)
+		} else {
+			result[key] = override
+		}
+	}
+	return result
+}
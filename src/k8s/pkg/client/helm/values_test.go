@@ -0,0 +1,40 @@
+package helm_test
+
+import (
+	"testing"
+
+	"github.com/canonical/k8s/pkg/client/helm"
+	. "github.com/onsi/gomega"
+)
+
+func TestMergeValues(t *testing.T) {
+	g := NewWithT(t)
+
+	values := map[string]any{
+		"replicas": 1,
+		"image":    map[string]any{"repository": "ghcr.io/canonical/coredns", "tag": "1.11.1"},
+		"service":  map[string]any{"clusterIP": "10.152.183.10"},
+		"args":     []string{"--a"},
+	}
+	overrides := map[string]any{
+		"replicas":  3,
+		"image":     map[string]any{"tag": "1.12.0"},
+		"service":   nil,
+		"args":      []any{"--b"},
+		"resources": map[string]any{"limits": map[string]any{"memory": "256Mi"}},
+	}
+
+	g.Expect(helm.MergeValues(values, overrides)).To(Equal(map[string]any{
+		"replicas":  3,
+		"image":     map[string]any{"repository": "ghcr.io/canonical/coredns", "tag": "1.12.0"},
+		"args":      []any{"--b"},
+		"resources": map[string]any{"limits": map[string]any{"memory": "256Mi"}},
+	}))
+
+	// inputs are not modified
+	g.Expect(values["replicas"]).To(Equal(1))
+	g.Expect(values["image"]).To(HaveKeyWithValue("tag", "1.11.1"))
+	g.Expect(values).To(HaveKey("service"))
+
+	g.Expect(helm.MergeValues(values, nil)).To(Equal(values))
+}
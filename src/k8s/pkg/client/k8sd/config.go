@@ -4,6 +4,7 @@ import (
 	"context"
 
 	apiv1 "github.com/canonical/k8s-snap-api/api/v1"
+	apiv1alpha "github.com/canonical/k8s/api/v1alpha"
 )
 
 func (c *k8sd) SetClusterConfig(ctx context.Context, request apiv1.SetClusterConfigRequest) error {
@@ -14,3 +15,23 @@ func (c *k8sd) SetClusterConfig(ctx context.Context, request apiv1.SetClusterCon
 func (c *k8sd) GetClusterConfig(ctx context.Context) (apiv1.GetClusterConfigResponse, error) {
 	return query(ctx, c, "GET", apiv1.GetClusterConfigRPC, nil, &apiv1.GetClusterConfigResponse{})
 }
+
+func (c *k8sd) GetFeatureValues(ctx context.Context, request apiv1alpha.GetFeatureValuesRequest) (apiv1alpha.GetFeatureValuesResponse, error) {
+	return query(ctx, c, "GET", apiv1alpha.GetFeatureValuesRPC, request, &apiv1alpha.GetFeatureValuesResponse{})
+}
+
+func (c *k8sd) ApplyClusterConfig(ctx context.Context, request apiv1alpha.ApplyClusterConfigRequest) (apiv1alpha.ApplyClusterConfigResponse, error) {
+	return query(ctx, c, "POST", apiv1alpha.ApplyClusterConfigRPC, request, &apiv1alpha.ApplyClusterConfigResponse{})
+}
+
+func (c *k8sd) ListClusterConfigRevisions(ctx context.Context, request apiv1alpha.ListClusterConfigRevisionsRequest) (apiv1alpha.ListClusterConfigRevisionsResponse, error) {
+	return query(ctx, c, "GET", apiv1alpha.ListClusterConfigRevisionsRPC, request, &apiv1alpha.ListClusterConfigRevisionsResponse{})
+}
+
+func (c *k8sd) DiffClusterConfigRevision(ctx context.Context, request apiv1alpha.DiffClusterConfigRevisionRequest) (apiv1alpha.DiffClusterConfigRevisionResponse, error) {
+	return query(ctx, c, "GET", apiv1alpha.DiffClusterConfigRevisionRPC, request, &apiv1alpha.DiffClusterConfigRevisionResponse{})
+}
+
+func (c *k8sd) RollbackClusterConfigRevision(ctx context.Context, request apiv1alpha.RollbackClusterConfigRevisionRequest) (apiv1alpha.RollbackClusterConfigRevisionResponse, error) {
+	return query(ctx, c, "POST", apiv1alpha.RollbackClusterConfigRevisionRPC, request, &apiv1alpha.RollbackClusterConfigRevisionResponse{})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	apiv1 "github.com/canonical/k8s-snap-api/api/v1"
+	apiv1alpha "github.com/canonical/k8s/api/v1alpha"
 )
 
 // ClusterClient implements methods for managing the cluster members.
@@ -25,14 +26,32 @@ type StatusClient interface {
 	NodeStatus(ctx context.Context) (apiv1.NodeStatusResponse, bool, error)
 	// ClusterStatus retrieves the current status of the Kubernetes cluster.
 	ClusterStatus(ctx context.Context, waitReady bool) (apiv1.ClusterStatusResponse, error)
+	// ListFeatures lists the features that are registered in k8sd, including the plugins.
+	ListFeatures(context.Context) (apiv1alpha.ListFeaturesResponse, error)
+	// GetFeatureStatus retrieves the status and the reconciliation history of a feature.
+	GetFeatureStatus(context.Context, apiv1alpha.GetFeatureStatusRequest) (apiv1alpha.GetFeatureStatusResponse, error)
+	// ListCertificateRotations retrieves the progress of the automatic certificate rotation of the cluster nodes.
+	ListCertificateRotations(context.Context) (apiv1alpha.ListCertificateRotationsResponse, error)
+	// GetAPIServerProxyStatus retrieves the state of the k8s-apiserver-proxy of the local worker node.
+	GetAPIServerProxyStatus(context.Context) (apiv1alpha.GetAPIServerProxyStatusResponse, error)
 }
 
 // ConfigClient implements methods to retrieve and manage the cluster configuration.
 type ConfigClient interface {
 	// GetClusterConfig retrieves the k8sd cluster configuration.
 	GetClusterConfig(context.Context) (apiv1.GetClusterConfigResponse, error)
+	// GetFeatureValues retrieves the effective Helm values of a feature.
+	GetFeatureValues(context.Context, apiv1alpha.GetFeatureValuesRequest) (apiv1alpha.GetFeatureValuesResponse, error)
 	// SetClusterConfig updates the k8sd cluster configuration.
 	SetClusterConfig(context.Context, apiv1.SetClusterConfigRequest) error
+	// ApplyClusterConfig declaratively applies a full k8sd cluster configuration.
+	ApplyClusterConfig(context.Context, apiv1alpha.ApplyClusterConfigRequest) (apiv1alpha.ApplyClusterConfigResponse, error)
+	// ListClusterConfigRevisions lists the recorded revisions of the k8sd cluster configuration.
+	ListClusterConfigRevisions(context.Context, apiv1alpha.ListClusterConfigRevisionsRequest) (apiv1alpha.ListClusterConfigRevisionsResponse, error)
+	// DiffClusterConfigRevision compares a revision of the k8sd cluster configuration with the current configuration.
+	DiffClusterConfigRevision(context.Context, apiv1alpha.DiffClusterConfigRevisionRequest) (apiv1alpha.DiffClusterConfigRevisionResponse, error)
+	// RollbackClusterConfigRevision restores a revision of the k8sd cluster configuration.
+	RollbackClusterConfigRevision(context.Context, apiv1alpha.RollbackClusterConfigRevisionRequest) (apiv1alpha.RollbackClusterConfigRevisionResponse, error)
 }
 
 // ClusterMaintenanceClient implements methods to manage the cluster.
@@ -45,12 +64,32 @@ type ClusterMaintenanceClient interface {
 	RefreshCertificatesUpdate(context.Context, apiv1.RefreshCertificatesUpdateRequest) (apiv1.RefreshCertificatesUpdateResponse, error)
 	// CertificatesStatus shows the status of the node's certificates.
 	CertificatesStatus(context.Context, apiv1.CertificatesStatusRequest) (apiv1.CertificatesStatusResponse, error)
+	// MigrateDatastore starts a migration of the cluster to a different datastore.
+	MigrateDatastore(context.Context, apiv1alpha.MigrateDatastoreRequest) (apiv1alpha.MigrateDatastoreResponse, error)
+	// GetDatastoreMigration retrieves the progress of the latest datastore migration started on the node.
+	GetDatastoreMigration(context.Context) (apiv1alpha.GetDatastoreMigrationResponse, error)
+	// RotateCA starts a rotation of the Kubernetes CAs, or returns the rotation in progress.
+	RotateCA(context.Context, apiv1alpha.RotateCARequest) (apiv1alpha.RotateCAResponse, error)
+	// GetCARotation retrieves the progress of the latest rotation of the Kubernetes CAs.
+	GetCARotation(context.Context) (apiv1alpha.GetCARotationResponse, error)
+	// DumpDatabase retrieves a consistent SQL text dump of the k8sd database from the local k8sd.
+	DumpDatabase(context.Context) (string, error)
 }
 
 // UserClient implements methods to enable accessing the cluster.
 type UserClient interface {
 	// KubeConfig retrieves a kubeconfig file that can be used to access the cluster.
 	KubeConfig(context.Context, apiv1.KubeConfigRequest) (apiv1.KubeConfigResponse, error)
+	// IssueKubeconfig issues a kubeconfig that is scoped to a user, groups and lifetime.
+	IssueKubeconfig(context.Context, apiv1alpha.IssueKubeconfigRequest) (apiv1alpha.IssueKubeconfigResponse, error)
+	// ListKubeconfigs lists the issued kubeconfigs that were not revoked.
+	ListKubeconfigs(context.Context, apiv1alpha.ListKubeconfigsRequest) (apiv1alpha.ListKubeconfigsResponse, error)
+	// RevokeKubeconfig revokes an issued kubeconfig.
+	RevokeKubeconfig(context.Context, apiv1alpha.RevokeKubeconfigRequest) (apiv1alpha.RevokeKubeconfigResponse, error)
+	// ListKubernetesAuthTokens lists the Kubernetes auth tokens.
+	ListKubernetesAuthTokens(context.Context, apiv1alpha.ListKubernetesAuthTokensRequest) (apiv1alpha.ListKubernetesAuthTokensResponse, error)
+	// RevokeKubernetesAuthTokens revokes a Kubernetes auth token, or all tokens of a user.
+	RevokeKubernetesAuthTokens(context.Context, apiv1alpha.RevokeKubernetesAuthTokensRequest) (apiv1alpha.RevokeKubernetesAuthTokensResponse, error)
 }
 
 // ClusterAPIClient implements methods related to ClusterAPI endpoints.
@@ -2,8 +2,10 @@ package k8sd
 
 import (
 	"context"
+	"fmt"
 
 	apiv1 "github.com/canonical/k8s-snap-api/api/v1"
+	apiv1alpha "github.com/canonical/k8s/api/v1alpha"
 )
 
 func (c *k8sd) RefreshCertificatesPlan(ctx context.Context, request apiv1.RefreshCertificatesPlanRequest) (apiv1.RefreshCertificatesPlanResponse, error) {
@@ -21,3 +23,27 @@ func (c *k8sd) RefreshCertificatesUpdate(ctx context.Context, request apiv1.Refr
 func (c *k8sd) CertificatesStatus(ctx context.Context, request apiv1.CertificatesStatusRequest) (apiv1.CertificatesStatusResponse, error) {
 	return query(ctx, c, "GET", apiv1.CertificatesStatusRPC, request, &apiv1.CertificatesStatusResponse{})
 }
+
+func (c *k8sd) MigrateDatastore(ctx context.Context, request apiv1alpha.MigrateDatastoreRequest) (apiv1alpha.MigrateDatastoreResponse, error) {
+	return query(ctx, c, "POST", apiv1alpha.MigrateDatastoreRPC, request, &apiv1alpha.MigrateDatastoreResponse{})
+}
+
+func (c *k8sd) GetDatastoreMigration(ctx context.Context) (apiv1alpha.GetDatastoreMigrationResponse, error) {
+	return query(ctx, c, "GET", apiv1alpha.MigrateDatastoreRPC, nil, &apiv1alpha.GetDatastoreMigrationResponse{})
+}
+
+func (c *k8sd) RotateCA(ctx context.Context, request apiv1alpha.RotateCARequest) (apiv1alpha.RotateCAResponse, error) {
+	return query(ctx, c, "POST", apiv1alpha.CARotationRPC, request, &apiv1alpha.RotateCAResponse{})
+}
+
+func (c *k8sd) GetCARotation(ctx context.Context) (apiv1alpha.GetCARotationResponse, error) {
+	return query(ctx, c, "GET", apiv1alpha.CARotationRPC, nil, &apiv1alpha.GetCARotationResponse{})
+}
+
+func (c *k8sd) DumpDatabase(ctx context.Context) (string, error) {
+	dump, _, err := c.app.SQL(ctx, ".dump")
+	if err != nil {
+		return "", fmt.Errorf("failed to dump k8sd database: %w", err)
+	}
+	return dump, nil
+}
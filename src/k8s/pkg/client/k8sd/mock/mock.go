@@ -4,6 +4,7 @@ import (
 	"context"
 
 	apiv1 "github.com/canonical/k8s-snap-api/api/v1"
+	apiv1alpha "github.com/canonical/k8s/api/v1alpha"
 	"github.com/canonical/k8s/pkg/client/k8sd"
 )
 
@@ -28,12 +29,42 @@ type Mock struct {
 	ClusterStatusResponse apiv1.ClusterStatusResponse
 	ClusterStatusErr      error
 
+	ListFeaturesResponse apiv1alpha.ListFeaturesResponse
+	ListFeaturesErr      error
+
+	GetFeatureStatusCalledWith apiv1alpha.GetFeatureStatusRequest
+	GetFeatureStatusResponse   apiv1alpha.GetFeatureStatusResponse
+	GetFeatureStatusErr        error
+
+	ListCertificateRotationsResponse apiv1alpha.ListCertificateRotationsResponse
+	ListCertificateRotationsErr      error
+
+	GetAPIServerProxyStatusResponse apiv1alpha.GetAPIServerProxyStatusResponse
+	GetAPIServerProxyStatusErr      error
+
 	// k8sd.ConfigClient
 	GetClusterConfigResponse   apiv1.GetClusterConfigResponse
 	GetClusterConfigErr        error
 	SetClusterConfigCalledWith apiv1.SetClusterConfigRequest
 	SetClusterConfigErr        error
 
+	GetFeatureValuesCalledWith apiv1alpha.GetFeatureValuesRequest
+	GetFeatureValuesResponse   apiv1alpha.GetFeatureValuesResponse
+	GetFeatureValuesErr        error
+
+	ApplyClusterConfigCalledWith apiv1alpha.ApplyClusterConfigRequest
+	ApplyClusterConfigResponse   apiv1alpha.ApplyClusterConfigResponse
+	ApplyClusterConfigErr        error
+
+	ListClusterConfigRevisionsResponse      apiv1alpha.ListClusterConfigRevisionsResponse
+	ListClusterConfigRevisionsErr           error
+	DiffClusterConfigRevisionCalledWith     apiv1alpha.DiffClusterConfigRevisionRequest
+	DiffClusterConfigRevisionResponse       apiv1alpha.DiffClusterConfigRevisionResponse
+	DiffClusterConfigRevisionErr            error
+	RollbackClusterConfigRevisionCalledWith apiv1alpha.RollbackClusterConfigRevisionRequest
+	RollbackClusterConfigRevisionResponse   apiv1alpha.RollbackClusterConfigRevisionResponse
+	RollbackClusterConfigRevisionErr        error
+
 	// k8sd.ClusterMaintenanceClient
 	RefreshCertificatesPlanCalledWith   apiv1.RefreshCertificatesPlanRequest
 	RefreshCertificatesPlanResponse     apiv1.RefreshCertificatesPlanResponse
@@ -49,10 +80,36 @@ type Mock struct {
 	CertificatesStatusResponse   apiv1.CertificatesStatusResponse
 	CertificatesStatusErr        error
 
+	MigrateDatastoreCalledWith    apiv1alpha.MigrateDatastoreRequest
+	MigrateDatastoreResponse      apiv1alpha.MigrateDatastoreResponse
+	MigrateDatastoreErr           error
+	GetDatastoreMigrationResponse apiv1alpha.GetDatastoreMigrationResponse
+	GetDatastoreMigrationErr      error
+	RotateCACalledWith            apiv1alpha.RotateCARequest
+	RotateCAResponse              apiv1alpha.RotateCAResponse
+	RotateCAErr                   error
+	GetCARotationResponse         apiv1alpha.GetCARotationResponse
+	GetCARotationErr              error
+	DumpDatabaseResponse          string
+	DumpDatabaseErr               error
+
 	// k8sd.UserClient
-	KubeConfigCalledWith apiv1.KubeConfigRequest
-	KubeConfigResponse   apiv1.KubeConfigResponse
-	KubeConfigErr        error
+	KubeConfigCalledWith                 apiv1.KubeConfigRequest
+	KubeConfigResponse                   apiv1.KubeConfigResponse
+	KubeConfigErr                        error
+	IssueKubeconfigCalledWith            apiv1alpha.IssueKubeconfigRequest
+	IssueKubeconfigResponse              apiv1alpha.IssueKubeconfigResponse
+	IssueKubeconfigErr                   error
+	ListKubeconfigsResponse              apiv1alpha.ListKubeconfigsResponse
+	ListKubeconfigsErr                   error
+	RevokeKubeconfigCalledWith           apiv1alpha.RevokeKubeconfigRequest
+	RevokeKubeconfigErr                  error
+	ListKubernetesAuthTokensCalledWith   apiv1alpha.ListKubernetesAuthTokensRequest
+	ListKubernetesAuthTokensResponse     apiv1alpha.ListKubernetesAuthTokensResponse
+	ListKubernetesAuthTokensErr          error
+	RevokeKubernetesAuthTokensCalledWith apiv1alpha.RevokeKubernetesAuthTokensRequest
+	RevokeKubernetesAuthTokensResponse   apiv1alpha.RevokeKubernetesAuthTokensResponse
+	RevokeKubernetesAuthTokensErr        error
 
 	// k8sd.ClusterAPIClient
 	SetClusterAPIAuthTokenCalledWith apiv1.ClusterAPISetAuthTokenRequest
@@ -87,6 +144,23 @@ func (m *Mock) ClusterStatus(_ context.Context, waitReady bool) (apiv1.ClusterSt
 	return m.ClusterStatusResponse, m.ClusterStatusErr
 }
 
+func (m *Mock) ListFeatures(_ context.Context) (apiv1alpha.ListFeaturesResponse, error) {
+	return m.ListFeaturesResponse, m.ListFeaturesErr
+}
+
+func (m *Mock) GetFeatureStatus(_ context.Context, request apiv1alpha.GetFeatureStatusRequest) (apiv1alpha.GetFeatureStatusResponse, error) {
+	m.GetFeatureStatusCalledWith = request
+	return m.GetFeatureStatusResponse, m.GetFeatureStatusErr
+}
+
+func (m *Mock) ListCertificateRotations(_ context.Context) (apiv1alpha.ListCertificateRotationsResponse, error) {
+	return m.ListCertificateRotationsResponse, m.ListCertificateRotationsErr
+}
+
+func (m *Mock) GetAPIServerProxyStatus(_ context.Context) (apiv1alpha.GetAPIServerProxyStatusResponse, error) {
+	return m.GetAPIServerProxyStatusResponse, m.GetAPIServerProxyStatusErr
+}
+
 func (m *Mock) RefreshCertificatesPlan(_ context.Context, request apiv1.RefreshCertificatesPlanRequest) (apiv1.RefreshCertificatesPlanResponse, error) {
 	return m.RefreshCertificatesPlanResponse, m.RefreshCertificatesPlanErr
 }
@@ -105,6 +179,28 @@ func (m *Mock) CertificatesStatus(_ context.Context, request apiv1.CertificatesS
 	return m.CertificatesStatusResponse, m.CertificatesStatusErr
 }
 
+func (m *Mock) MigrateDatastore(_ context.Context, request apiv1alpha.MigrateDatastoreRequest) (apiv1alpha.MigrateDatastoreResponse, error) {
+	m.MigrateDatastoreCalledWith = request
+	return m.MigrateDatastoreResponse, m.MigrateDatastoreErr
+}
+
+func (m *Mock) GetDatastoreMigration(_ context.Context) (apiv1alpha.GetDatastoreMigrationResponse, error) {
+	return m.GetDatastoreMigrationResponse, m.GetDatastoreMigrationErr
+}
+
+func (m *Mock) RotateCA(_ context.Context, request apiv1alpha.RotateCARequest) (apiv1alpha.RotateCAResponse, error) {
+	m.RotateCACalledWith = request
+	return m.RotateCAResponse, m.RotateCAErr
+}
+
+func (m *Mock) DumpDatabase(_ context.Context) (string, error) {
+	return m.DumpDatabaseResponse, m.DumpDatabaseErr
+}
+
+func (m *Mock) GetCARotation(_ context.Context) (apiv1alpha.GetCARotationResponse, error) {
+	return m.GetCARotationResponse, m.GetCARotationErr
+}
+
 func (m *Mock) GetClusterConfig(_ context.Context) (apiv1.GetClusterConfigResponse, error) {
 	return m.GetClusterConfigResponse, m.GetClusterConfigErr
 }
@@ -114,11 +210,59 @@ func (m *Mock) SetClusterConfig(_ context.Context, request apiv1.SetClusterConfi
 	return m.SetClusterConfigErr
 }
 
+func (m *Mock) ApplyClusterConfig(_ context.Context, request apiv1alpha.ApplyClusterConfigRequest) (apiv1alpha.ApplyClusterConfigResponse, error) {
+	m.ApplyClusterConfigCalledWith = request
+	return m.ApplyClusterConfigResponse, m.ApplyClusterConfigErr
+}
+
+func (m *Mock) GetFeatureValues(_ context.Context, request apiv1alpha.GetFeatureValuesRequest) (apiv1alpha.GetFeatureValuesResponse, error) {
+	m.GetFeatureValuesCalledWith = request
+	return m.GetFeatureValuesResponse, m.GetFeatureValuesErr
+}
+
+func (m *Mock) ListClusterConfigRevisions(_ context.Context, _ apiv1alpha.ListClusterConfigRevisionsRequest) (apiv1alpha.ListClusterConfigRevisionsResponse, error) {
+	return m.ListClusterConfigRevisionsResponse, m.ListClusterConfigRevisionsErr
+}
+
+func (m *Mock) DiffClusterConfigRevision(_ context.Context, request apiv1alpha.DiffClusterConfigRevisionRequest) (apiv1alpha.DiffClusterConfigRevisionResponse, error) {
+	m.DiffClusterConfigRevisionCalledWith = request
+	return m.DiffClusterConfigRevisionResponse, m.DiffClusterConfigRevisionErr
+}
+
+func (m *Mock) RollbackClusterConfigRevision(_ context.Context, request apiv1alpha.RollbackClusterConfigRevisionRequest) (apiv1alpha.RollbackClusterConfigRevisionResponse, error) {
+	m.RollbackClusterConfigRevisionCalledWith = request
+	return m.RollbackClusterConfigRevisionResponse, m.RollbackClusterConfigRevisionErr
+}
+
 func (m *Mock) KubeConfig(_ context.Context, request apiv1.KubeConfigRequest) (apiv1.KubeConfigResponse, error) {
 	m.KubeConfigCalledWith = request
 	return m.KubeConfigResponse, m.KubeConfigErr
 }
 
+func (m *Mock) IssueKubeconfig(_ context.Context, request apiv1alpha.IssueKubeconfigRequest) (apiv1alpha.IssueKubeconfigResponse, error) {
+	m.IssueKubeconfigCalledWith = request
+	return m.IssueKubeconfigResponse, m.IssueKubeconfigErr
+}
+
+func (m *Mock) ListKubeconfigs(_ context.Context, _ apiv1alpha.ListKubeconfigsRequest) (apiv1alpha.ListKubeconfigsResponse, error) {
+	return m.ListKubeconfigsResponse, m.ListKubeconfigsErr
+}
+
+func (m *Mock) RevokeKubeconfig(_ context.Context, request apiv1alpha.RevokeKubeconfigRequest) (apiv1alpha.RevokeKubeconfigResponse, error) {
+	m.RevokeKubeconfigCalledWith = request
+	return apiv1alpha.RevokeKubeconfigResponse{}, m.RevokeKubeconfigErr
+}
+
+func (m *Mock) ListKubernetesAuthTokens(_ context.Context, request apiv1alpha.ListKubernetesAuthTokensRequest) (apiv1alpha.ListKubernetesAuthTokensResponse, error) {
+	m.ListKubernetesAuthTokensCalledWith = request
+	return m.ListKubernetesAuthTokensResponse, m.ListKubernetesAuthTokensErr
+}
+
+func (m *Mock) RevokeKubernetesAuthTokens(_ context.Context, request apiv1alpha.RevokeKubernetesAuthTokensRequest) (apiv1alpha.RevokeKubernetesAuthTokensResponse, error) {
+	m.RevokeKubernetesAuthTokensCalledWith = request
+	return m.RevokeKubernetesAuthTokensResponse, m.RevokeKubernetesAuthTokensErr
+}
+
 func (m *Mock) SetClusterAPIAuthToken(_ context.Context, request apiv1.ClusterAPISetAuthTokenRequest) error {
 	m.SetClusterAPIAuthTokenCalledWith = request
 	return m.SetClusterAPIAuthTokenErr
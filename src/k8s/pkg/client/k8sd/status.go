@@ -6,6 +6,7 @@ import (
 	"net/http"
 
 	apiv1 "github.com/canonical/k8s-snap-api/api/v1"
+	apiv1alpha "github.com/canonical/k8s/api/v1alpha"
 	"github.com/canonical/k8s/pkg/utils/control"
 	"github.com/canonical/lxd/shared/api"
 )
@@ -41,3 +42,19 @@ func (c *k8sd) ClusterStatus(ctx context.Context, waitReady bool) (apiv1.Cluster
 	}
 	return response, nil
 }
+
+func (c *k8sd) ListFeatures(ctx context.Context) (apiv1alpha.ListFeaturesResponse, error) {
+	return query(ctx, c, "GET", apiv1alpha.ListFeaturesRPC, nil, &apiv1alpha.ListFeaturesResponse{})
+}
+
+func (c *k8sd) GetFeatureStatus(ctx context.Context, request apiv1alpha.GetFeatureStatusRequest) (apiv1alpha.GetFeatureStatusResponse, error) {
+	return query(ctx, c, "GET", apiv1alpha.GetFeatureStatusRPC, request, &apiv1alpha.GetFeatureStatusResponse{})
+}
+
+func (c *k8sd) ListCertificateRotations(ctx context.Context) (apiv1alpha.ListCertificateRotationsResponse, error) {
+	return query(ctx, c, "GET", apiv1alpha.ListCertificateRotationsRPC, nil, &apiv1alpha.ListCertificateRotationsResponse{})
+}
+
+func (c *k8sd) GetAPIServerProxyStatus(ctx context.Context) (apiv1alpha.GetAPIServerProxyStatusResponse, error) {
+	return query(ctx, c, "GET", apiv1alpha.GetAPIServerProxyStatusRPC, nil, &apiv1alpha.GetAPIServerProxyStatusResponse{})
+}
@@ -4,8 +4,29 @@ import (
 	"context"
 
 	apiv1 "github.com/canonical/k8s-snap-api/api/v1"
+	apiv1alpha "github.com/canonical/k8s/api/v1alpha"
 )
 
 func (c *k8sd) KubeConfig(ctx context.Context, request apiv1.KubeConfigRequest) (apiv1.KubeConfigResponse, error) {
 	return query(ctx, c, "GET", apiv1.KubeConfigRPC, request, &apiv1.KubeConfigResponse{})
 }
+
+func (c *k8sd) IssueKubeconfig(ctx context.Context, request apiv1alpha.IssueKubeconfigRequest) (apiv1alpha.IssueKubeconfigResponse, error) {
+	return query(ctx, c, "POST", apiv1alpha.IssueKubeconfigRPC, request, &apiv1alpha.IssueKubeconfigResponse{})
+}
+
+func (c *k8sd) ListKubeconfigs(ctx context.Context, request apiv1alpha.ListKubeconfigsRequest) (apiv1alpha.ListKubeconfigsResponse, error) {
+	return query(ctx, c, "GET", apiv1alpha.ListKubeconfigsRPC, request, &apiv1alpha.ListKubeconfigsResponse{})
+}
+
+func (c *k8sd) RevokeKubeconfig(ctx context.Context, request apiv1alpha.RevokeKubeconfigRequest) (apiv1alpha.RevokeKubeconfigResponse, error) {
+	return query(ctx, c, "POST", apiv1alpha.RevokeKubeconfigRPC, request, &apiv1alpha.RevokeKubeconfigResponse{})
+}
+
+func (c *k8sd) ListKubernetesAuthTokens(ctx context.Context, request apiv1alpha.ListKubernetesAuthTokensRequest) (apiv1alpha.ListKubernetesAuthTokensResponse, error) {
+	return query(ctx, c, "GET", apiv1alpha.ListKubernetesAuthTokensRPC, request, &apiv1alpha.ListKubernetesAuthTokensResponse{})
+}
+
+func (c *k8sd) RevokeKubernetesAuthTokens(ctx context.Context, request apiv1alpha.RevokeKubernetesAuthTokensRequest) (apiv1alpha.RevokeKubernetesAuthTokensResponse, error) {
+	return query(ctx, c, "POST", apiv1alpha.RevokeKubernetesAuthTokensRPC, request, &apiv1alpha.RevokeKubernetesAuthTokensResponse{})
+}
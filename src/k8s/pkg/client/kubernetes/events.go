@@ -0,0 +1,36 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CreateEvent records an event about the given object.
+// eventType is either corev1.EventTypeNormal or corev1.EventTypeWarning.
+func (c *Client) CreateEvent(ctx context.Context, object corev1.ObjectReference, eventType string, reason string, message string) error {
+	now := metav1.NewTime(time.Now())
+	event := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s.%x", object.Name, now.UnixNano()),
+			Namespace: object.Namespace,
+		},
+		InvolvedObject:      object,
+		Type:                eventType,
+		Reason:              reason,
+		Message:             message,
+		Source:              corev1.EventSource{Component: "k8sd"},
+		ReportingController: "k8sd",
+		FirstTimestamp:      now,
+		LastTimestamp:       now,
+		Count:               1,
+	}
+
+	if _, err := c.CoreV1().Events(object.Namespace).Create(ctx, event, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("failed to create event for %s %s/%s: %w", object.Kind, object.Namespace, object.Name, err)
+	}
+	return nil
+}
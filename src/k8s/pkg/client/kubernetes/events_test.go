@@ -0,0 +1,34 @@
+package kubernetes
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCreateEvent(t *testing.T) {
+	g := NewWithT(t)
+
+	clientset := fake.NewSimpleClientset()
+	client := &Client{Interface: clientset}
+
+	object := corev1.ObjectReference{Kind: "Feature", Namespace: "kube-system", Name: "ingress"}
+	err := client.CreateEvent(context.Background(), object, corev1.EventTypeWarning, "FeatureFailed", "failed to apply")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	events, err := clientset.CoreV1().Events("kube-system").List(context.Background(), metav1.ListOptions{})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(events.Items).To(HaveLen(1))
+
+	event := events.Items[0]
+	g.Expect(event.Name).To(HavePrefix("ingress."))
+	g.Expect(event.InvolvedObject).To(Equal(object))
+	g.Expect(event.Type).To(Equal(corev1.EventTypeWarning))
+	g.Expect(event.Reason).To(Equal("FeatureFailed"))
+	g.Expect(event.Message).To(Equal("failed to apply"))
+	g.Expect(event.Source.Component).To(Equal("k8sd"))
+}
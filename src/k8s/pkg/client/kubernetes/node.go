@@ -84,3 +84,53 @@ func (c *Client) NodeVersions(ctx context.Context) (map[string]*versionutil.Vers
 
 	return nodeVersions, nil
 }
+
+// updateNode applies update to the node and writes it back to the cluster.
+// updateNode retries if there is a conflict on the resource.
+func (c *Client) updateNode(ctx context.Context, name string, update func(node *v1.Node)) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		node, err := c.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to get node %s: %w", name, err)
+		}
+		update(node)
+		if _, err := c.CoreV1().Nodes().Update(ctx, node, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("failed to update node %s: %w", name, err)
+		}
+		return nil
+	})
+}
+
+// CordonNode marks the node as unschedulable, or schedulable again if unschedulable is false.
+func (c *Client) CordonNode(ctx context.Context, name string, unschedulable bool) error {
+	return c.updateNode(ctx, name, func(node *v1.Node) {
+		node.Spec.Unschedulable = unschedulable
+	})
+}
+
+// SetNodeLabel sets a label on the node.
+func (c *Client) SetNodeLabel(ctx context.Context, name string, key string, value string) error {
+	return c.updateNode(ctx, name, func(node *v1.Node) {
+		if node.Labels == nil {
+			node.Labels = make(map[string]string)
+		}
+		node.Labels[key] = value
+	})
+}
+
+// SetNodeAnnotation sets an annotation on the node.
+func (c *Client) SetNodeAnnotation(ctx context.Context, name string, key string, value string) error {
+	return c.updateNode(ctx, name, func(node *v1.Node) {
+		if node.Annotations == nil {
+			node.Annotations = make(map[string]string)
+		}
+		node.Annotations[key] = value
+	})
+}
+
+// RemoveNodeAnnotation removes an annotation from the node. RemoveNodeAnnotation does not fail if the annotation is not set.
+func (c *Client) RemoveNodeAnnotation(ctx context.Context, name string, key string) error {
+	return c.updateNode(ctx, name, func(node *v1.Node) {
+		delete(node.Annotations, key)
+	})
+}
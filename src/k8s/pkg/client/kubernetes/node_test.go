@@ -213,3 +213,32 @@ func TestNodeVersions(t *testing.T) {
 		g.Expect(err).To(MatchError(ContainSubstring("failed to parse version")))
 	})
 }
+
+func TestUpdateNode(t *testing.T) {
+	g := NewWithT(t)
+
+	client := &Client{Interface: fake.NewSimpleClientset(&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1"}})}
+	ctx := context.Background()
+
+	g.Expect(client.CordonNode(ctx, "node1", true)).To(Succeed())
+	g.Expect(client.SetNodeLabel(ctx, "node1", "label", "value")).To(Succeed())
+	g.Expect(client.SetNodeAnnotation(ctx, "node1", "annotation", "value")).To(Succeed())
+
+	node, err := client.GetNode(ctx, "node1")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(node.Spec.Unschedulable).To(BeTrue())
+	g.Expect(node.Labels).To(HaveKeyWithValue("label", "value"))
+	g.Expect(node.Annotations).To(HaveKeyWithValue("annotation", "value"))
+
+	g.Expect(client.CordonNode(ctx, "node1", false)).To(Succeed())
+	g.Expect(client.RemoveNodeAnnotation(ctx, "node1", "annotation")).To(Succeed())
+	// removing an annotation is idempotent
+	g.Expect(client.RemoveNodeAnnotation(ctx, "node1", "annotation")).To(Succeed())
+
+	node, err = client.GetNode(ctx, "node1")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(node.Spec.Unschedulable).To(BeFalse())
+	g.Expect(node.Annotations).ToNot(HaveKey("annotation"))
+
+	g.Expect(client.CordonNode(ctx, "missing", true)).ToNot(Succeed())
+}
@@ -5,7 +5,10 @@ import (
 	"fmt"
 
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 )
 
 // CheckForReadyPods checks if all pods in the specified namespace are ready.
@@ -52,3 +55,94 @@ func podIsReady(pod corev1.Pod) bool {
 
 	return false
 }
+
+// listNodePods returns the pods that are scheduled on the node.
+func (c *Client) listNodePods(ctx context.Context, nodeName string) ([]corev1.Pod, error) {
+	pods, err := c.CoreV1().Pods("").List(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("spec.nodeName", nodeName).String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods on node %s: %w", nodeName, err)
+	}
+
+	// the field selector is only a hint, e.g. it is not supported by the fake clientset
+	nodePods := make([]corev1.Pod, 0, len(pods.Items))
+	for _, pod := range pods.Items {
+		if pod.Spec.NodeName == nodeName {
+			nodePods = append(nodePods, pod)
+		}
+	}
+	return nodePods, nil
+}
+
+// EvictNodePods evicts the pods from the node, as "kubectl drain" does.
+// DaemonSet pods, static pods and pods that have completed are not evicted.
+// EvictNodePods returns the number of pods that are still running on the node, including pods that are terminating
+// and pods that cannot be evicted yet because of a PodDisruptionBudget. The node is drained once it returns zero.
+func (c *Client) EvictNodePods(ctx context.Context, nodeName string) (int, error) {
+	pods, err := c.listNodePods(ctx, nodeName)
+	if err != nil {
+		return 0, err
+	}
+
+	var remaining int
+	for _, pod := range pods {
+		if !podIsEvictable(pod) {
+			continue
+		}
+
+		remaining++
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+
+		if err := c.PolicyV1().Evictions(pod.Namespace).Evict(ctx, &policyv1.Eviction{
+			ObjectMeta: metav1.ObjectMeta{Name: pod.Name, Namespace: pod.Namespace},
+		}); err != nil {
+			switch {
+			case apierrors.IsNotFound(err):
+				remaining--
+			case apierrors.IsTooManyRequests(err):
+				// the eviction would violate a PodDisruptionBudget, it is retried by the next call
+			default:
+				return 0, fmt.Errorf("failed to evict pod %s/%s: %w", pod.Namespace, pod.Name, err)
+			}
+		}
+	}
+
+	return remaining, nil
+}
+
+// DeletePodNetworkPods deletes the pods on the node that do not use the host network, so that their controllers
+// re-create them with the current CNI. Pods without a controller are not deleted.
+func (c *Client) DeletePodNetworkPods(ctx context.Context, nodeName string) error {
+	pods, err := c.listNodePods(ctx, nodeName)
+	if err != nil {
+		return err
+	}
+
+	for _, pod := range pods {
+		if pod.Spec.HostNetwork || pod.DeletionTimestamp != nil || metav1.GetControllerOf(&pod) == nil {
+			continue
+		}
+		if err := c.CoreV1().Pods(pod.Namespace).Delete(ctx, pod.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete pod %s/%s: %w", pod.Namespace, pod.Name, err)
+		}
+	}
+	return nil
+}
+
+// podIsEvictable checks if a pod is removed when a node is drained.
+// DaemonSet pods are re-created on the node and static pods cannot be evicted, completed pods are already stopped.
+func podIsEvictable(pod corev1.Pod) bool {
+	if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+		return false
+	}
+	if _, ok := pod.Annotations[corev1.MirrorPodAnnotationKey]; ok {
+		return false
+	}
+	if controller := metav1.GetControllerOf(&pod); controller != nil && controller.Kind == "DaemonSet" {
+		return false
+	}
+	return true
+}
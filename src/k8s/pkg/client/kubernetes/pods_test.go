@@ -5,8 +5,11 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/canonical/k8s/pkg/utils"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/fake"
@@ -105,3 +108,96 @@ func TestCheckForReadyPods(t *testing.T) {
 		})
 	}
 }
+
+func testNodePod(name string, nodeName string, controllerKind string) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec:       corev1.PodSpec{NodeName: nodeName},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	if controllerKind != "" {
+		pod.OwnerReferences = []metav1.OwnerReference{{Kind: controllerKind, Name: "owner", Controller: utils.Pointer(true)}}
+	}
+	return pod
+}
+
+func TestEvictNodePods(t *testing.T) {
+	t.Run("Drain", func(t *testing.T) {
+		g := NewWithT(t)
+
+		completed := testNodePod("completed", "node1", "Job")
+		completed.Status.Phase = corev1.PodSucceeded
+		static := testNodePod("static", "node1", "Node")
+		static.Annotations = map[string]string{corev1.MirrorPodAnnotationKey: "hash"}
+
+		clientset := fake.NewSimpleClientset(
+			testNodePod("deployment", "node1", "ReplicaSet"),
+			testNodePod("standalone", "node1", ""),
+			testNodePod("daemonset", "node1", "DaemonSet"),
+			testNodePod("other-node", "node2", "ReplicaSet"),
+			completed,
+			static,
+		)
+		var evicted []string
+		clientset.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			if action.GetSubresource() != "eviction" {
+				return false, nil, nil
+			}
+			evicted = append(evicted, action.(k8stesting.CreateAction).GetObject().(*policyv1.Eviction).Name)
+			return true, nil, nil
+		})
+		client := &Client{Interface: clientset}
+
+		remaining, err := client.EvictNodePods(context.Background(), "node1")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(remaining).To(Equal(2))
+		g.Expect(evicted).To(ConsistOf("deployment", "standalone"))
+	})
+
+	t.Run("DisruptionBudget", func(t *testing.T) {
+		g := NewWithT(t)
+
+		clientset := fake.NewSimpleClientset(testNodePod("deployment", "node1", "ReplicaSet"))
+		clientset.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, apierrors.NewTooManyRequests("disruption budget", 10)
+		})
+		client := &Client{Interface: clientset}
+
+		remaining, err := client.EvictNodePods(context.Background(), "node1")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(remaining).To(Equal(1))
+	})
+
+	t.Run("Drained", func(t *testing.T) {
+		g := NewWithT(t)
+
+		client := &Client{Interface: fake.NewSimpleClientset(testNodePod("daemonset", "node1", "DaemonSet"))}
+
+		remaining, err := client.EvictNodePods(context.Background(), "node1")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(remaining).To(BeZero())
+	})
+}
+
+func TestDeletePodNetworkPods(t *testing.T) {
+	g := NewWithT(t)
+
+	hostNetwork := testNodePod("host-network", "node1", "DaemonSet")
+	hostNetwork.Spec.HostNetwork = true
+	client := &Client{Interface: fake.NewSimpleClientset(
+		testNodePod("daemonset", "node1", "DaemonSet"),
+		testNodePod("standalone", "node1", ""),
+		testNodePod("other-node", "node2", "DaemonSet"),
+		hostNetwork,
+	)}
+
+	g.Expect(client.DeletePodNetworkPods(context.Background(), "node1")).To(Succeed())
+
+	pods, err := client.CoreV1().Pods("default").List(context.Background(), metav1.ListOptions{})
+	g.Expect(err).ToNot(HaveOccurred())
+	var names []string
+	for _, pod := range pods.Items {
+		names = append(names, pod.Name)
+	}
+	g.Expect(names).To(ConsistOf("standalone", "other-node", "host-network"))
+}
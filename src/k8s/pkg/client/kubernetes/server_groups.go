@@ -3,6 +3,7 @@ package kubernetes
 import (
 	"fmt"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -15,3 +16,21 @@ func (c *Client) ListResourcesForGroupVersion(groupVersion string) (*v1.APIResou
 
 	return resources, nil
 }
+
+// HasResource checks if a given resource (e.g. "prometheusrules") of a group version is served by the cluster.
+func (c *Client) HasResource(groupVersion string, resource string) (bool, error) {
+	resources, err := c.ListResourcesForGroupVersion(groupVersion)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	for _, r := range resources.APIResources {
+		if r.Name == resource {
+			return true, nil
+		}
+	}
+	return false, nil
+}
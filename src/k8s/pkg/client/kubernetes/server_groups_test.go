@@ -64,3 +64,28 @@ func TestListResourcesForGroupVersion(t *testing.T) {
 		})
 	}
 }
+
+func TestHasResource(t *testing.T) {
+	g := NewWithT(t)
+	clientset := fakeclientset.NewSimpleClientset()
+	fakeDiscovery, ok := clientset.Discovery().(*fakediscovery.FakeDiscovery)
+	g.Expect(ok).To(BeTrue())
+	client := &kubernetes.Client{Interface: clientset}
+
+	exists, err := client.HasResource("monitoring.coreos.com/v1", "prometheusrules")
+	g.Expect(err).To(Not(HaveOccurred()))
+	g.Expect(exists).To(BeFalse())
+
+	fakeDiscovery.Resources = []*v1.APIResourceList{{
+		GroupVersion: "monitoring.coreos.com/v1",
+		APIResources: []v1.APIResource{{Name: "servicemonitors"}},
+	}}
+	exists, err = client.HasResource("monitoring.coreos.com/v1", "prometheusrules")
+	g.Expect(err).To(Not(HaveOccurred()))
+	g.Expect(exists).To(BeFalse())
+
+	fakeDiscovery.Resources[0].APIResources = append(fakeDiscovery.Resources[0].APIResources, v1.APIResource{Name: "prometheusrules"})
+	exists, err = client.HasResource("monitoring.coreos.com/v1", "prometheusrules")
+	g.Expect(err).To(Not(HaveOccurred()))
+	g.Expect(exists).To(BeTrue())
+}
@@ -16,3 +16,17 @@ func (c *Client) GetServiceClusterIP(ctx context.Context, name, namespace string
 
 	return svc.Spec.ClusterIP, nil
 }
+
+// GetServiceClusterIPs retrieves the ClusterIPs from a Kubernetes service.
+// For dual-stack services, the list contains one IP per IP family, starting with the primary ClusterIP.
+func (c *Client) GetServiceClusterIPs(ctx context.Context, name, namespace string) ([]string, error) {
+	svc, err := c.CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service '%s' in namespace '%s': %w", name, namespace, err)
+	}
+
+	if len(svc.Spec.ClusterIPs) == 0 && svc.Spec.ClusterIP != "" {
+		return []string{svc.Spec.ClusterIP}, nil
+	}
+	return svc.Spec.ClusterIPs, nil
+}
@@ -67,3 +67,60 @@ func TestGetServiceClusterIP(t *testing.T) {
 		})
 	}
 }
+
+func TestGetServiceClusterIPs(t *testing.T) {
+	tests := []struct {
+		name           string
+		serviceObjects []runtime.Object
+		expectedIPs    []string
+		expectError    bool
+	}{
+		{
+			name: "single stack service",
+			serviceObjects: []runtime.Object{
+				&corev1.Service{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-service", Namespace: "default"},
+					Spec:       corev1.ServiceSpec{ClusterIP: "192.168.1.1"},
+				},
+			},
+			expectedIPs: []string{"192.168.1.1"},
+		},
+		{
+			name: "dual stack service",
+			serviceObjects: []runtime.Object{
+				&corev1.Service{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-service", Namespace: "default"},
+					Spec: corev1.ServiceSpec{
+						ClusterIP:  "fd98::a",
+						ClusterIPs: []string{"fd98::a", "192.168.1.1"},
+					},
+				},
+			},
+			expectedIPs: []string{"fd98::a", "192.168.1.1"},
+		},
+		{
+			name:           "service does not exist",
+			serviceObjects: []runtime.Object{},
+			expectError:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			clientset := fake.NewSimpleClientset(tt.serviceObjects...)
+			client := &Client{Interface: clientset}
+
+			ips, err := client.GetServiceClusterIPs(context.Background(), "test-service", "default")
+
+			if tt.expectError {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(ips).To(BeEmpty())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(ips).To(Equal(tt.expectedIPs))
+			}
+		})
+	}
+}
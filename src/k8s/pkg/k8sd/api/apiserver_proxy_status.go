@@ -0,0 +1,49 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	apiv1alpha "github.com/canonical/k8s/api/v1alpha"
+	"github.com/canonical/k8s/pkg/proxy"
+	snaputil "github.com/canonical/k8s/pkg/snap/util"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/microcluster/v2/state"
+)
+
+// getAPIServerProxyStatus retrieves the state of the k8s-apiserver-proxy of the local node from its status listener.
+func (e *Endpoints) getAPIServerProxyStatus(s state.State, r *http.Request) response.Response {
+	address, err := snaputil.GetServiceArgument(e.provider.Snap(), "k8s-apiserver-proxy", "--status-address")
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return response.InternalError(fmt.Errorf("failed to get k8s-apiserver-proxy arguments: %w", err))
+	}
+	if address == "" {
+		return response.NotFound(fmt.Errorf("k8s-apiserver-proxy status is not available on this node"))
+	}
+
+	status, err := proxy.GetStatus(r.Context(), address)
+	if err != nil {
+		return response.InternalError(fmt.Errorf("failed to get k8s-apiserver-proxy status: %w", err))
+	}
+
+	result := apiv1alpha.GetAPIServerProxyStatusResponse{
+		Endpoints: make([]apiv1alpha.APIServerProxyEndpoint, 0, len(status.Endpoints)),
+		Refreshes: status.Refreshes,
+	}
+	for _, ep := range status.Endpoints {
+		result.Endpoints = append(result.Endpoints, apiv1alpha.APIServerProxyEndpoint{
+			Address:       ep.Address,
+			Active:        ep.Active,
+			Draining:      ep.Draining,
+			Connections:   ep.Connections,
+			BytesSent:     ep.BytesSent,
+			BytesReceived: ep.BytesReceived,
+			DialErrors:    ep.DialErrors,
+			Deactivations: ep.Deactivations,
+		})
+	}
+
+	return response.SyncResponse(true, &result)
+}
@@ -0,0 +1,206 @@
+package api
+
+import (
+	"context"
+	"crypto/x509/pkix"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	apiv1alpha "github.com/canonical/k8s/api/v1alpha"
+	"github.com/canonical/k8s/pkg/k8sd/database"
+	databaseutil "github.com/canonical/k8s/pkg/k8sd/database/util"
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/k8s/pkg/utils"
+	pkiutil "github.com/canonical/k8s/pkg/utils/pki"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/microcluster/v2/cluster"
+	"github.com/canonical/microcluster/v2/state"
+)
+
+func (e *Endpoints) postCARotation(s state.State, r *http.Request) response.Response {
+	req := apiv1alpha.RotateCARequest{}
+	if err := utils.NewStrictJSONDecoder(r.Body).Decode(&req); err != nil {
+		return response.BadRequest(fmt.Errorf("failed to parse request: %w", err))
+	}
+	if req.ExpirationSeconds < 0 {
+		return response.BadRequest(fmt.Errorf("expiration seconds must not be negative"))
+	}
+
+	config, err := databaseutil.GetClusterConfig(r.Context(), s)
+	if err != nil {
+		return response.InternalError(fmt.Errorf("failed to get cluster configuration: %w", err))
+	}
+	if config.Certificates.GetCAKey() == "" || config.Certificates.GetClientCAKey() == "" {
+		return response.BadRequest(fmt.Errorf("rotating an external Kubernetes CA is not supported"))
+	}
+	caCerts, err := pkiutil.LoadCertificateChain(config.Certificates.GetCACert())
+	if err != nil {
+		return response.InternalError(fmt.Errorf("failed to parse Kubernetes CA certificate: %w", err))
+	}
+	if !pkiutil.IsSelfSigned(caCerts[0]) {
+		return response.BadRequest(fmt.Errorf("rotating an intermediate Kubernetes CA is not supported"))
+	}
+
+	notBefore := time.Now()
+	notAfter := notBefore.AddDate(20, 0, 0)
+	if req.ExpirationSeconds > 0 {
+		notAfter = utils.SecondsToExpirationDate(notBefore, req.ExpirationSeconds)
+	}
+	newCertificates, err := generateCARotationCertificates(notBefore, notAfter, config.Certificates.GetKeyAlgorithm())
+	if err != nil {
+		return response.InternalError(fmt.Errorf("failed to generate new CAs: %w", err))
+	}
+
+	var result apiv1alpha.RotateCAResponse
+	if err := s.Database().Transaction(r.Context(), func(ctx context.Context, tx *sql.Tx) error {
+		rotation, err := database.GetCARotation(ctx, tx)
+		if err != nil {
+			return fmt.Errorf("failed to get CA rotation: %w", err)
+		}
+
+		// NOTE: An interrupted rotation is resumed instead of starting a new one.
+		if rotation == nil || rotation.Phase == types.CARotationCompleted {
+			// NOTE: The cluster configuration is read again, as it might have changed since the CAs were generated.
+			config, err := database.GetClusterConfig(ctx, tx)
+			if err != nil {
+				return fmt.Errorf("failed to get cluster configuration: %w", err)
+			}
+			started, err := database.StartCARotation(ctx, tx, types.CARotation{
+				Old: types.Certificates{
+					CACert:       config.Certificates.CACert,
+					CAKey:        config.Certificates.CAKey,
+					ClientCACert: config.Certificates.ClientCACert,
+					ClientCAKey:  config.Certificates.ClientCAKey,
+				},
+				New:       newCertificates,
+				StartedAt: notBefore,
+				UpdatedAt: notBefore,
+			}, requestIdentity(r))
+			if err != nil {
+				return fmt.Errorf("failed to start CA rotation: %w", err)
+			}
+			rotation = &started
+		}
+
+		if result.Rotation, err = caRotationStatus(ctx, tx, *rotation); err != nil {
+			return fmt.Errorf("failed to get CA rotation status: %w", err)
+		}
+		return nil
+	}); err != nil {
+		return response.InternalError(fmt.Errorf("database transaction to start CA rotation failed: %w", err))
+	}
+
+	return response.SyncResponse(true, &result)
+}
+
+func (e *Endpoints) getCARotation(s state.State, r *http.Request) response.Response {
+	var result apiv1alpha.GetCARotationResponse
+	if err := s.Database().Transaction(r.Context(), func(ctx context.Context, tx *sql.Tx) error {
+		rotation, err := database.GetCARotation(ctx, tx)
+		if err != nil {
+			return fmt.Errorf("failed to get CA rotation: %w", err)
+		}
+		if rotation == nil {
+			return nil
+		}
+
+		status, err := caRotationStatus(ctx, tx, *rotation)
+		if err != nil {
+			return fmt.Errorf("failed to get CA rotation status: %w", err)
+		}
+		result.Rotation = &status
+		return nil
+	}); err != nil {
+		return response.InternalError(fmt.Errorf("database transaction to get CA rotation failed: %w", err))
+	}
+
+	return response.SyncResponse(true, &result)
+}
+
+// caRotationStatus returns the progress of the rotation on all cluster members.
+func caRotationStatus(ctx context.Context, tx *sql.Tx, rotation types.CARotation) (apiv1alpha.CARotation, error) {
+	members, err := cluster.GetCoreClusterMembers(ctx, tx)
+	if err != nil {
+		return apiv1alpha.CARotation{}, fmt.Errorf("failed to get cluster members: %w", err)
+	}
+	nodes, err := database.ListCARotationNodes(ctx, tx, rotation.ID)
+	if err != nil {
+		return apiv1alpha.CARotation{}, fmt.Errorf("failed to list CA rotation nodes: %w", err)
+	}
+	progress := make(map[string]types.CARotationNode, len(nodes))
+	for _, node := range nodes {
+		progress[node.Node] = node
+	}
+
+	status := apiv1alpha.CARotation{
+		Phase:     string(rotation.Phase),
+		StartedAt: rotation.StartedAt,
+		UpdatedAt: rotation.UpdatedAt,
+		Nodes:     []apiv1alpha.CARotationNode{},
+	}
+	for _, member := range members {
+		if member.Role == cluster.Pending {
+			continue
+		}
+		node := progress[member.Name]
+		status.Nodes = append(status.Nodes, apiv1alpha.CARotationNode{
+			Node:      member.Name,
+			Phase:     string(node.Phase),
+			Message:   node.Message,
+			UpdatedAt: node.UpdatedAt,
+		})
+	}
+	sort.Slice(status.Nodes, func(i, j int) bool { return status.Nodes[i].Node < status.Nodes[j].Node })
+
+	return status, nil
+}
+
+// generateCARotationCertificates generates the new CAs of a CA rotation, along with the shared certificates of the
+// cluster configuration that are signed by them. The private keys are generated with the specified key algorithm.
+func generateCARotationCertificates(notBefore time.Time, notAfter time.Time, algorithm pkiutil.KeyAlgorithm) (types.Certificates, error) {
+	caCert, caKey, err := pkiutil.GenerateSelfSignedCA(pkix.Name{CommonName: "kubernetes-ca"}, notBefore, notAfter, algorithm)
+	if err != nil {
+		return types.Certificates{}, fmt.Errorf("failed to generate kubernetes CA: %w", err)
+	}
+	clientCACert, clientCAKey, err := pkiutil.GenerateSelfSignedCA(pkix.Name{CommonName: "kubernetes-ca-client"}, notBefore, notAfter, algorithm)
+	if err != nil {
+		return types.Certificates{}, fmt.Errorf("failed to generate kubernetes client CA: %w", err)
+	}
+
+	parent, parentKey, err := pkiutil.LoadCertificate(clientCACert, clientCAKey)
+	if err != nil {
+		return types.Certificates{}, fmt.Errorf("failed to parse kubernetes client CA: %w", err)
+	}
+
+	certificates := types.Certificates{
+		CACert:       utils.Pointer(caCert),
+		CAKey:        utils.Pointer(caKey),
+		ClientCACert: utils.Pointer(clientCACert),
+		ClientCAKey:  utils.Pointer(clientCAKey),
+	}
+	for _, i := range []struct {
+		name string
+		cn   string
+		cert **string
+		key  **string
+	}{
+		{name: "admin", cn: "kubernetes-admin", cert: &certificates.AdminClientCert, key: &certificates.AdminClientKey},
+		{name: "apiserver-kubelet-client", cn: "apiserver-kubelet-client", cert: &certificates.APIServerKubeletClientCert, key: &certificates.APIServerKubeletClientKey},
+	} {
+		template, err := pkiutil.GenerateCertificate(pkix.Name{CommonName: i.cn, Organization: []string{"system:masters"}}, notBefore, notAfter, false, nil, nil)
+		if err != nil {
+			return types.Certificates{}, fmt.Errorf("failed to generate %s certificate: %w", i.name, err)
+		}
+		cert, key, err := pkiutil.SignCertificate(template, algorithm, parent, parentKey)
+		if err != nil {
+			return types.Certificates{}, fmt.Errorf("failed to sign %s certificate: %w", i.name, err)
+		}
+		*i.cert = utils.Pointer(cert)
+		*i.key = utils.Pointer(key)
+	}
+
+	return certificates, nil
+}
@@ -0,0 +1,40 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	apiv1alpha "github.com/canonical/k8s/api/v1alpha"
+	"github.com/canonical/k8s/pkg/k8sd/database"
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/microcluster/v2/state"
+)
+
+func (e *Endpoints) getCertificateRotations(s state.State, r *http.Request) response.Response {
+	var rotations []types.CertificateRotation
+	if err := s.Database().Transaction(r.Context(), func(ctx context.Context, tx *sql.Tx) error {
+		var err error
+		if rotations, err = database.ListCertificateRotations(ctx, tx); err != nil {
+			return fmt.Errorf("failed to list certificate rotations: %w", err)
+		}
+		return nil
+	}); err != nil {
+		return response.InternalError(fmt.Errorf("database transaction to list certificate rotations failed: %w", err))
+	}
+
+	result := apiv1alpha.ListCertificateRotationsResponse{Rotations: []apiv1alpha.CertificateRotation{}}
+	for _, rotation := range rotations {
+		result.Rotations = append(result.Rotations, apiv1alpha.CertificateRotation{
+			Node:         rotation.Node,
+			State:        string(rotation.State),
+			Certificates: rotation.Certificates,
+			Message:      rotation.Message,
+			UpdatedAt:    rotation.UpdatedAt,
+		})
+	}
+
+	return response.SyncResponse(true, &result)
+}
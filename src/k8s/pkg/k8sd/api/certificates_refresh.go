@@ -2,11 +2,13 @@ package api
 
 import (
 	"context"
+	"crypto"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/sha256"
 	"crypto/x509/pkix"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"math"
 	"math/big"
@@ -17,6 +19,7 @@ import (
 	"time"
 
 	apiv1 "github.com/canonical/k8s-snap-api/api/v1"
+	"github.com/canonical/k8s/pkg/k8sd/controllers/csrsigning"
 	databaseutil "github.com/canonical/k8s/pkg/k8sd/database/util"
 	"github.com/canonical/k8s/pkg/k8sd/pki"
 	"github.com/canonical/k8s/pkg/k8sd/setup"
@@ -198,11 +201,11 @@ func (e *Endpoints) postRefreshCertsRun(s state.State, r *http.Request) response
 	if isWorker {
 		return refreshCertsRunWorker(s, r, snap)
 	}
-	return refreshCertsRunControlPlane(s, r, snap)
+	return refreshCertsRunControlPlane(s, r, snap, e.provider.Signers())
 }
 
 // refreshCertsRunControlPlane refreshes the certificates for a control plane node.
-func refreshCertsRunControlPlane(s state.State, r *http.Request, snap snap.Snap) response.Response {
+func refreshCertsRunControlPlane(s state.State, r *http.Request, snap snap.Snap, signers *csrsigning.SignerCache) response.Response {
 	log := log.FromContext(r.Context())
 
 	req := apiv1.RefreshCertificatesRunRequest{}
@@ -219,14 +222,47 @@ func refreshCertsRunControlPlane(s state.State, r *http.Request, snap snap.Snap)
 		certsToRefresh = getAllCertsForRole(apiv1.ClusterRoleControlPlane)
 	}
 
-	clusterConfig, err := databaseutil.GetClusterConfig(r.Context(), s)
+	certificates, err := RefreshControlPlaneCertificates(r.Context(), s, snap, signers, certsToRefresh, req.ExtraSANs, req.ExpirationSeconds)
 	if err != nil {
-		return response.InternalError(fmt.Errorf("failed to recover cluster config: %w", err))
+		return response.InternalError(err)
+	}
+
+	// NOTE: Restart the control plane services in a separate goroutine to avoid
+	// restarting the API server, which would break the k8sd proxy connection
+	// and cause missed responses in the proxy side.
+	restartFn := func(ctx context.Context) error {
+		if err := snaputil.RestartControlPlaneServices(ctx, snap); err != nil {
+			return fmt.Errorf("failed to restart control plane services: %w", err)
+		}
+		return nil
+	}
+	readyCh := nodeutil.StartAsyncRestart(log, restartFn)
+
+	apiServerCert, _, err := pkiutil.LoadCertificate(certificates.APIServerCert, "")
+	if err != nil {
+		return response.InternalError(fmt.Errorf("failed to read kubelet certificate: %w", err))
+	}
+
+	expirationTimeUNIX := apiServerCert.NotAfter.Unix()
+
+	return utils.SyncManualResponseWithSignal(r, readyCh, apiv1.RefreshCertificatesRunResponse{
+		ExpirationSeconds: int(expirationTimeUNIX),
+	})
+}
+
+// RefreshControlPlaneCertificates renews the given certificates of the local control plane node and writes them,
+// along with the control plane kubeconfig files. The services that use the certificates are not restarted.
+// CA keys that are not stored in the cluster configuration are taken from the signer of signers.
+// extraSANs are added to the serving certificates. expirationSeconds is the validity of the new certificates.
+func RefreshControlPlaneCertificates(ctx context.Context, s state.State, snap snap.Snap, signers *csrsigning.SignerCache, certsToRefresh []apiv1.CertificateName, extraSANs []string, expirationSeconds int) (*pki.ControlPlanePKI, error) {
+	clusterConfig, err := databaseutil.GetClusterConfig(ctx, s)
+	if err != nil {
+		return nil, fmt.Errorf("failed to recover cluster config: %w", err)
 	}
 
 	nodeIP := net.ParseIP(s.Address().Hostname())
 	if nodeIP == nil {
-		return response.InternalError(fmt.Errorf("failed to parse node IP address %q", s.Address().Hostname()))
+		return nil, fmt.Errorf("failed to parse node IP address %q", s.Address().Hostname())
 	}
 
 	var localhostAddress string
@@ -238,10 +274,25 @@ func refreshCertsRunControlPlane(s state.State, r *http.Request, snap snap.Snap)
 
 	serviceIPs, err := utils.GetKubernetesServiceIPsFromServiceCIDRs(clusterConfig.Network.GetServiceCIDR())
 	if err != nil {
-		return response.InternalError(fmt.Errorf("failed to get IP address(es) from ServiceCIDR %q: %w", clusterConfig.Network.GetServiceCIDR(), err))
+		return nil, fmt.Errorf("failed to get IP address(es) from ServiceCIDR %q: %w", clusterConfig.Network.GetServiceCIDR(), err)
 	}
 
-	extraIPs, extraNames := utils.SplitIPAndDNSSANs(req.ExtraSANs)
+	extraIPs, extraNames := utils.SplitIPAndDNSSANs(extraSANs)
+
+	// NOTE: CA keys that are not stored in the cluster configuration may be available through the configured signer.
+	signer, release, err := signers.Get(clusterConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CA signer: %w", err)
+	}
+	defer release()
+	caSigner, err := signerKey(ctx, signer, csrsigning.CAKubernetes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get kubernetes CA signer: %w", err)
+	}
+	clientCASigner, err := signerKey(ctx, signer, csrsigning.CAKubernetesClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get kubernetes client CA signer: %w", err)
+	}
 
 	// NOTE: Set the notBefore certificate time to the current time.
 	notBefore := time.Now()
@@ -250,10 +301,13 @@ func refreshCertsRunControlPlane(s state.State, r *http.Request, snap snap.Snap)
 		Hostname:                  s.Name(),
 		IPSANs:                    append(append([]net.IP{nodeIP}, serviceIPs...), extraIPs...),
 		NotBefore:                 notBefore,
-		NotAfter:                  utils.SecondsToExpirationDate(notBefore, req.ExpirationSeconds),
+		NotAfter:                  utils.SecondsToExpirationDate(notBefore, expirationSeconds),
 		DNSSANs:                   extraNames,
 		AllowSelfSignedCA:         true,
 		IncludeMachineAddressSANs: true,
+		KeyAlgorithm:              clusterConfig.Certificates.GetKeyAlgorithm(),
+		CASigner:                  caSigner,
+		ClientCASigner:            clientCASigner,
 	})
 
 	certificates.CACert = clusterConfig.Certificates.GetCACert()
@@ -268,50 +322,39 @@ func refreshCertsRunControlPlane(s state.State, r *http.Request, snap snap.Snap)
 
 	marker := newControlPlaneCertificateMarker(certificates)
 	if err := setup.ReadControlPlanePKI(snap, certificates, true); err != nil {
-		return response.InternalError(fmt.Errorf("failed to read managed control plane certificates: %w", err))
+		return nil, fmt.Errorf("failed to read managed control plane certificates: %w", err)
 	}
 
 	if err := setup.ReadControlPlanePKI(snap, certificates, false); err != nil {
-		return response.InternalError(fmt.Errorf("failed to read unmanaged control plane certificates: %w", err))
+		return nil, fmt.Errorf("failed to read unmanaged control plane certificates: %w", err)
 	}
 
 	if err := marker.markCertificatesForRefresh(certsToRefresh); err != nil {
-		return response.InternalError(fmt.Errorf("failed to mark certificates for refresh: %w", err))
+		return nil, fmt.Errorf("failed to mark certificates for refresh: %w", err)
 	}
 
 	if err := certificates.CompleteCertificates(); err != nil {
-		return response.InternalError(fmt.Errorf("failed to generate new control plane certificates: %w", err))
+		return nil, fmt.Errorf("failed to generate new control plane certificates: %w", err)
 	}
 
 	if _, err := setup.EnsureControlPlanePKI(snap, certificates); err != nil {
-		return response.InternalError(fmt.Errorf("failed to write control plane certificates: %w", err))
+		return nil, fmt.Errorf("failed to write control plane certificates: %w", err)
 	}
 
 	if err := setup.SetupControlPlaneKubeconfigs(snap.KubernetesConfigDir(), localhostAddress, clusterConfig.APIServer.GetSecurePort(), *certificates); err != nil {
-		return response.InternalError(fmt.Errorf("failed to generate control plane kubeconfigs: %w", err))
+		return nil, fmt.Errorf("failed to generate control plane kubeconfigs: %w", err)
 	}
 
-	// NOTE: Restart the control plane services in a separate goroutine to avoid
-	// restarting the API server, which would break the k8sd proxy connection
-	// and cause missed responses in the proxy side.
-	restartFn := func(ctx context.Context) error {
-		if err := snaputil.RestartControlPlaneServices(ctx, snap); err != nil {
-			return fmt.Errorf("failed to restart control plane services: %w", err)
-		}
-		return nil
-	}
-	readyCh := nodeutil.StartAsyncRestart(log, restartFn)
+	return certificates, nil
+}
 
-	apiServerCert, _, err := pkiutil.LoadCertificate(certificates.APIServerCert, "")
-	if err != nil {
-		return response.InternalError(fmt.Errorf("failed to read kubelet certificate: %w", err))
+// signerKey returns the key of the CA from the signer, or nil if the key is not available to the signer.
+func signerKey(ctx context.Context, signer csrsigning.Signer, ca csrsigning.CA) (crypto.Signer, error) {
+	key, err := signer.Key(ctx, ca)
+	if errors.Is(err, csrsigning.ErrMissingCAKey) {
+		return nil, nil
 	}
-
-	expirationTimeUNIX := apiServerCert.NotAfter.Unix()
-
-	return utils.SyncManualResponseWithSignal(r, readyCh, apiv1.RefreshCertificatesRunResponse{
-		ExpirationSeconds: int(expirationTimeUNIX),
-	})
+	return key, err
 }
 
 // refreshCertsRunWorker refreshes the certificates for a worker node.
@@ -332,20 +375,71 @@ func refreshCertsRunWorker(s state.State, r *http.Request, snap snap.Snap) respo
 		}
 	}
 
+	certificates, err := RefreshWorkerCertificates(r.Context(), s, snap, certsToRefresh, req.ExtraSANs, req.Seed, req.ExpirationSeconds)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	// NOTE: Restart the worker services in a separate goroutine to avoid
+	// restarting the kube-proxy and kubelet, which would break the
+	// proxy connection and cause missed responses in the proxy side.
+	restartFn := func(ctx context.Context) error {
+		restartKubelet := slices.Contains(certsToRefresh, apiv1.CertificateKubelet) || slices.Contains(certsToRefresh, apiv1.CertificateKubeletClient)
+		restartProxy := slices.Contains(certsToRefresh, apiv1.CertificateProxyClient)
+
+		if restartKubelet {
+			if err := snap.RestartServices(ctx, []string{"kubelet"}); err != nil {
+				return fmt.Errorf("failed to restart kubelet: %w", err)
+			}
+		}
+
+		if restartProxy {
+			if err := snap.RestartServices(ctx, []string{"kube-proxy"}); err != nil {
+				return fmt.Errorf("failed to restart kube-proxy: %w", err)
+			}
+		}
+		return nil
+	}
+	readyCh := nodeutil.StartAsyncRestart(log, restartFn)
+
+	cert, _, err := pkiutil.LoadCertificate(certificates.KubeletCert, "")
+	if err != nil {
+		return response.InternalError(fmt.Errorf("failed to load kubelet certificate: %w", err))
+	}
+
+	expirationTimeUNIX := cert.NotAfter.Unix()
+
+	return utils.SyncManualResponseWithSignal(r, readyCh, apiv1.RefreshCertificatesRunResponse{
+		ExpirationSeconds: int(expirationTimeUNIX),
+	})
+}
+
+// RefreshWorkerCertificates renews the given certificates of the local worker node through certificate signing requests
+// and writes them, along with the worker kubeconfig files. The services that use the certificates are not restarted.
+// extraSANs are added to the kubelet serving certificate. seed is used to name the certificate signing requests.
+// expirationSeconds is the requested validity of the new certificates.
+func RefreshWorkerCertificates(ctx context.Context, s state.State, snap snap.Snap, certsToRefresh []apiv1.CertificateName, extraSANs []string, seed int, expirationSeconds int) (*pki.WorkerNodePKI, error) {
+	log := log.FromContext(ctx)
+
 	client, err := snap.KubernetesNodeClient("")
 	if err != nil {
-		return response.InternalError(fmt.Errorf("failed to get Kubernetes client: %w", err))
+		return nil, fmt.Errorf("failed to get Kubernetes client: %w", err)
 	}
 
 	var certificates pki.WorkerNodePKI
 
-	clusterConfig, err := databaseutil.GetClusterConfig(r.Context(), s)
+	clusterConfig, err := databaseutil.GetClusterConfig(ctx, s)
 	if err != nil {
-		return response.InternalError(fmt.Errorf("failed to get cluster configuration: %w", err))
+		return nil, fmt.Errorf("failed to get cluster configuration: %w", err)
 	}
 
 	if clusterConfig.Certificates.CACert == nil || clusterConfig.Certificates.ClientCACert == nil {
-		return response.InternalError(fmt.Errorf("missing CA certificates from cluster config"))
+		return nil, fmt.Errorf("missing CA certificates from cluster config")
+	}
+
+	// NOTE: Keep the existing certificates that are not refreshed, as all of them are written below.
+	if err := setup.ReadWorkerPKI(snap, &certificates); err != nil {
+		return nil, fmt.Errorf("failed to read worker certificates: %w", err)
 	}
 
 	certificates.CACert = clusterConfig.Certificates.GetCACert()
@@ -353,25 +447,26 @@ func refreshCertsRunWorker(s state.State, r *http.Request, snap snap.Snap) respo
 
 	k8sdPublicKey, err := pkiutil.LoadRSAPublicKey(clusterConfig.Certificates.GetK8sdPublicKey())
 	if err != nil {
-		return response.InternalError(fmt.Errorf("failed to load k8sd public key: %w", err))
+		return nil, fmt.Errorf("failed to load k8sd public key: %w", err)
 	}
 
 	hostnames := []string{snap.Hostname()}
 	ips := []net.IP{net.ParseIP(s.Address().Hostname())}
 
-	extraIPs, extraNames := utils.SplitIPAndDNSSANs(req.ExtraSANs)
+	extraIPs, extraNames := utils.SplitIPAndDNSSANs(extraSANs)
 	hostnames = append(hostnames, extraNames...)
 	ips = append(ips, extraIPs...)
 
+	keyAlgorithm := clusterConfig.Certificates.GetKeyAlgorithm()
 	workerCSRDefs := getWorkerCSRDefinitions(snap.Hostname())
-	g, ctx := errgroup.WithContext(r.Context())
-	expirationSeconds := int32(req.ExpirationSeconds)
+	g, ctx := errgroup.WithContext(ctx)
+	csrExpirationSeconds := int32(expirationSeconds)
 
 	for _, certName := range certsToRefresh {
 		csrDef, found := workerCSRDefs[certName]
 		if !found {
 			// NOTE (mateoflorido): Should not happen after validation.
-			return response.InternalError(fmt.Errorf("CSR definition not found for %q", certName))
+			return nil, fmt.Errorf("CSR definition not found for %q", certName)
 		}
 		// nolint:exhaustive
 		switch certName {
@@ -386,13 +481,13 @@ func refreshCertsRunWorker(s state.State, r *http.Request, snap snap.Snap) respo
 			csrDef.targetKey = &certificates.KubeProxyClientKey
 		default:
 			// NOTE (mateoflorido): Should not happen after validation.
-			return response.InternalError(fmt.Errorf("unhandled certificate name %q for worker CSR", certName))
+			return nil, fmt.Errorf("unhandled certificate name %q for worker CSR", certName)
 		}
 
 		localCSRDef := csrDef
 
 		g.Go(func() error {
-			csrObjectName := fmt.Sprintf("k8sd-%d-%s", req.Seed, localCSRDef.CSRBaseName)
+			csrObjectName := fmt.Sprintf("k8sd-%d-%s", seed, localCSRDef.CSRBaseName)
 
 			var csrHostnames []string
 			var csrIPs []net.IP
@@ -406,7 +501,7 @@ func refreshCertsRunWorker(s state.State, r *http.Request, snap snap.Snap) respo
 					CommonName:   localCSRDef.CommonName,
 					Organization: localCSRDef.Organization,
 				},
-				2048,
+				keyAlgorithm,
 				csrHostnames,
 				csrIPs,
 			)
@@ -436,8 +531,8 @@ func refreshCertsRunWorker(s state.State, r *http.Request, snap snap.Snap) respo
 				},
 				Spec: certv1.CertificateSigningRequestSpec{
 					Request:           []byte(csrPEM),
-					ExpirationSeconds: &expirationSeconds,
-					Usages:            localCSRDef.Usages,
+					ExpirationSeconds: &csrExpirationSeconds,
+					Usages:            csrUsages(localCSRDef.Usages, keyAlgorithm),
 					SignerName:        localCSRDef.SignerName,
 				},
 			}
@@ -462,16 +557,16 @@ func refreshCertsRunWorker(s state.State, r *http.Request, snap snap.Snap) respo
 	}
 
 	if err := g.Wait(); err != nil {
-		return response.InternalError(fmt.Errorf("failed to get one or more worker node certificates: %w", err))
+		return nil, fmt.Errorf("failed to get one or more worker node certificates: %w", err)
 	}
 
 	if _, err = setup.EnsureWorkerPKI(snap, &certificates); err != nil {
-		return response.InternalError(fmt.Errorf("failed to write worker PKI: %w", err))
+		return nil, fmt.Errorf("failed to write worker PKI: %w", err)
 	}
 
 	nodeIP := net.ParseIP(s.Address().Hostname())
 	if nodeIP == nil {
-		return response.InternalError(fmt.Errorf("failed to parse node IP address %q", s.Address().Hostname()))
+		return nil, fmt.Errorf("failed to parse node IP address %q", s.Address().Hostname())
 	}
 
 	var localhostAddress string
@@ -484,44 +579,13 @@ func refreshCertsRunWorker(s state.State, r *http.Request, snap snap.Snap) respo
 	// Kubeconfigs
 	apiServerEndpoint := fmt.Sprintf("%s:%d", localhostAddress, clusterConfig.APIServer.GetSecurePort())
 	if err := setup.Kubeconfig(filepath.Join(snap.KubernetesConfigDir(), "kubelet.conf"), apiServerEndpoint, certificates.CACert, certificates.KubeletClientCert, certificates.KubeletClientKey); err != nil {
-		return response.InternalError(fmt.Errorf("failed to generate kubelet kubeconfig: %w", err))
+		return nil, fmt.Errorf("failed to generate kubelet kubeconfig: %w", err)
 	}
 	if err := setup.Kubeconfig(filepath.Join(snap.KubernetesConfigDir(), "proxy.conf"), apiServerEndpoint, certificates.CACert, certificates.KubeProxyClientCert, certificates.KubeProxyClientKey); err != nil {
-		return response.InternalError(fmt.Errorf("failed to generate kube-proxy kubeconfig: %w", err))
-	}
-
-	// NOTE: Restart the worker services in a separate goroutine to avoid
-	// restarting the kube-proxy and kubelet, which would break the
-	// proxy connection and cause missed responses in the proxy side.
-	restartFn := func(ctx context.Context) error {
-		restartKubelet := slices.Contains(certsToRefresh, apiv1.CertificateKubelet) || slices.Contains(certsToRefresh, apiv1.CertificateKubeletClient)
-		restartProxy := slices.Contains(certsToRefresh, apiv1.CertificateProxyClient)
-
-		if restartKubelet {
-			if err := snap.RestartServices(ctx, []string{"kubelet"}); err != nil {
-				return fmt.Errorf("failed to restart kubelet: %w", err)
-			}
-		}
-
-		if restartProxy {
-			if err := snap.RestartServices(ctx, []string{"kube-proxy"}); err != nil {
-				return fmt.Errorf("failed to restart kube-proxy: %w", err)
-			}
-		}
-		return nil
-	}
-	readyCh := nodeutil.StartAsyncRestart(log, restartFn)
-
-	cert, _, err := pkiutil.LoadCertificate(certificates.KubeletCert, "")
-	if err != nil {
-		return response.InternalError(fmt.Errorf("failed to load kubelet certificate: %w", err))
+		return nil, fmt.Errorf("failed to generate kube-proxy kubeconfig: %w", err)
 	}
 
-	expirationTimeUNIX := cert.NotAfter.Unix()
-
-	return utils.SyncManualResponseWithSignal(r, readyCh, apiv1.RefreshCertificatesRunResponse{
-		ExpirationSeconds: int(expirationTimeUNIX),
-	})
+	return &certificates, nil
 }
 
 // toCertificateNames converts a slice of strings to a slice of CertificateName.
@@ -581,6 +645,16 @@ func getWorkerCSRDefinitions(hostname string) map[apiv1.CertificateName]*csrDefi
 	}
 }
 
+// csrUsages returns the usages of a worker CSR for a private key of the given algorithm.
+// Key encipherment is only requested for RSA keys.
+func csrUsages(usages []certv1.KeyUsage, algorithm pkiutil.KeyAlgorithm) []certv1.KeyUsage {
+	switch algorithm {
+	case "", pkiutil.KeyAlgorithmRSA2048, pkiutil.KeyAlgorithmRSA4096:
+		return usages
+	}
+	return slices.DeleteFunc(slices.Clone(usages), func(usage certv1.KeyUsage) bool { return usage == certv1.UsageKeyEncipherment })
+}
+
 // isCertificateSigningRequestApprovedAndIssued checks if the certificate
 // signing request is approved and issued. It returns true if the CSR is
 // approved and issued, false if it is pending, and an error if it is denied
@@ -37,6 +37,12 @@ var (
 		"client",
 	}
 
+	etcdCertificateNames = []string{
+		"client",
+		"peer",
+		"server",
+	}
+
 	workerCertificateNames = []string{
 		"kubelet",
 	}
@@ -86,12 +92,19 @@ func getCertsStatusControlPlane(s state.State, r *http.Request, snap snap.Snap)
 	certificates = append(certificates, nodeCerts...)
 	certificates = append(certificates, kubeConfigCerts...)
 
-	if clusterConfig.Datastore.GetType() == "external" {
+	switch clusterConfig.Datastore.GetType() {
+	case "external":
 		dataStoreCerts, err := loadCertificateStatusesFromDir(snap.EtcdPKIDir(), dataStoreCertificateNames)
 		if err != nil {
 			return response.InternalError(fmt.Errorf("failed to read datastore certificates: %w", err))
 		}
 		certificates = append(certificates, dataStoreCerts...)
+	case "etcd":
+		etcdCerts, err := loadCertificateStatusesFromDir(snap.EtcdPKIDir(), etcdCertificateNames)
+		if err != nil {
+			return response.InternalError(fmt.Errorf("failed to read etcd certificates: %w", err))
+		}
+		certificates = append(certificates, etcdCerts...)
 	}
 
 	updateExternallyManaged(authorities, certificates)
@@ -199,6 +212,12 @@ func readCertificateAuthorities(clusterConfig *types.ClusterConfig) ([]apiv1.Cer
 		}
 	}
 
+	if clusterConfig.Datastore.GetType() == "etcd" {
+		if err := loadAndAppend(clusterConfig.Datastore.GetEtcdCACert(), clusterConfig.Datastore.GetEtcdCAKey(), "Etcd CA"); err != nil {
+			return cas, err
+		}
+	}
+
 	return cas, nil
 }
 
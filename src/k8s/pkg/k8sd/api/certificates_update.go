@@ -65,9 +65,10 @@ func refreshCertsUpdateControlPlane(s state.State, r *http.Request, snap snap.Sn
 	}
 
 	certificates := pki.NewControlPlanePKI(pki.ControlPlanePKIOpts{
-		Hostname:  s.Name(),
-		IPSANs:    append([]net.IP{nodeIP}, serviceIPs...),
-		NotBefore: time.Now(),
+		Hostname:     s.Name(),
+		IPSANs:       append([]net.IP{nodeIP}, serviceIPs...),
+		NotBefore:    time.Now(),
+		KeyAlgorithm: clusterConfig.Certificates.GetKeyAlgorithm(),
 	})
 
 	if err := setup.ReadControlPlanePKI(snap, certificates, true); err != nil {
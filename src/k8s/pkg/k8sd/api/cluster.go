@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"net"
 	"net/http"
 
 	apiv1 "github.com/canonical/k8s-snap-api/api/v1"
@@ -12,6 +13,7 @@ import (
 	databaseutil "github.com/canonical/k8s/pkg/k8sd/database/util"
 	"github.com/canonical/k8s/pkg/k8sd/features"
 	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/k8s/pkg/log"
 	"github.com/canonical/lxd/lxd/response"
 	"github.com/canonical/microcluster/v2/state"
 )
@@ -31,6 +33,27 @@ func (e *Endpoints) getClusterStatus(s state.State, r *http.Request) response.Re
 		return response.InternalError(fmt.Errorf("failed to get cluster config: %w", err))
 	}
 
+	var datastoreServers []string
+	switch config.Datastore.GetType() {
+	case "etcd":
+		if err := impl.SetEtcdDatastoreRoles(r.Context(), e.provider.Snap(), config.Datastore, members); err != nil {
+			// the local etcd member may be unavailable, report the cluster status regardless
+			log.FromContext(r.Context()).Error(err, "Failed to get etcd member roles")
+			for i := range members {
+				members[i].DatastoreRole = apiv1.DatastoreRoleUnknown
+			}
+		}
+		for _, member := range members {
+			host, _, err := net.SplitHostPort(member.Address)
+			if err != nil {
+				continue
+			}
+			datastoreServers = append(datastoreServers, fmt.Sprintf("https://%s", net.JoinHostPort(host, fmt.Sprintf("%d", config.Datastore.GetEtcdPort()))))
+		}
+	case "external":
+		datastoreServers = config.Datastore.GetExternalServers()
+	}
+
 	client, err := e.provider.Snap().KubernetesClient("")
 	if err != nil {
 		return response.InternalError(fmt.Errorf("failed to create k8s client: %w", err))
@@ -60,7 +83,7 @@ func (e *Endpoints) getClusterStatus(s state.State, r *http.Request) response.Re
 			Config:  config.ToUserFacing(),
 			Datastore: apiv1.Datastore{
 				Type:    config.Datastore.GetType(),
-				Servers: config.Datastore.GetExternalServers(),
+				Servers: datastoreServers,
 			},
 			DNS:           statuses[features.DNS].ToAPI(),
 			Network:       statuses[features.Network].ToAPI(),
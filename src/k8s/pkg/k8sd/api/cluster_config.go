@@ -5,10 +5,12 @@ import (
 	"database/sql"
 	"fmt"
 	"net/http"
+	"slices"
 
 	apiv1 "github.com/canonical/k8s-snap-api/api/v1"
 	"github.com/canonical/k8s/pkg/k8sd/database"
 	databaseutil "github.com/canonical/k8s/pkg/k8sd/database/util"
+	"github.com/canonical/k8s/pkg/k8sd/features"
 	"github.com/canonical/k8s/pkg/k8sd/types"
 	"github.com/canonical/k8s/pkg/utils"
 	"github.com/canonical/lxd/lxd/response"
@@ -29,9 +31,12 @@ func (e *Endpoints) putClusterConfig(s state.State, r *http.Request) response.Re
 	if requestedConfig.Datastore, err = types.DatastoreConfigFromUserFacing(req.Datastore); err != nil {
 		return response.BadRequest(fmt.Errorf("failed to parse datastore config: %w", err))
 	}
+	if err := validateValuesOverridesFeatures(requestedConfig.ValuesOverrides); err != nil {
+		return response.BadRequest(fmt.Errorf("invalid configuration: %w", err))
+	}
 
 	if err := s.Database().Transaction(r.Context(), func(ctx context.Context, tx *sql.Tx) error {
-		if _, err := database.SetClusterConfig(ctx, tx, requestedConfig); err != nil {
+		if _, err := database.SetClusterConfig(ctx, tx, requestedConfig, requestIdentity(r)); err != nil {
 			return fmt.Errorf("failed to update cluster configuration: %w", err)
 		}
 		return nil
@@ -40,15 +45,7 @@ func (e *Endpoints) putClusterConfig(s state.State, r *http.Request) response.Re
 	}
 
 	e.provider.NotifyUpdateNodeConfigController()
-	e.provider.NotifyFeatureController(
-		!requestedConfig.Network.Empty(),
-		!requestedConfig.Gateway.Empty(),
-		!requestedConfig.Ingress.Empty(),
-		!requestedConfig.LoadBalancer.Empty(),
-		!requestedConfig.LocalStorage.Empty(),
-		!requestedConfig.MetricsServer.Empty(),
-		!requestedConfig.DNS.Empty() || !requestedConfig.Kubelet.Empty(),
-	)
+	e.provider.NotifyFeatures(changedFeatures(requestedConfig)...)
 
 	return response.SyncResponse(true, &apiv1.SetClusterConfigResponse{})
 }
@@ -66,3 +63,25 @@ func (e *Endpoints) getClusterConfig(s state.State, r *http.Request) response.Re
 		ServiceCIDR: config.Network.ServiceCIDR,
 	})
 }
+
+// changedFeatures returns the registered features that are affected by the requested configuration.
+func changedFeatures(config types.ClusterConfig) []types.FeatureName {
+	var names []types.FeatureName
+	for _, feature := range features.Registered() {
+		if config.ValuesOverrides.Has(feature.Name) || feature.Changed(config) {
+			names = append(names, feature.Name)
+		}
+	}
+	return names
+}
+
+// validateValuesOverridesFeatures returns an error if values overrides are set for unknown features.
+func validateValuesOverridesFeatures(overrides types.ValuesOverrides) error {
+	known := features.Features()
+	for name := range overrides {
+		if !slices.Contains(known, name) {
+			return fmt.Errorf("values overrides for unknown feature %q", name)
+		}
+	}
+	return nil
+}
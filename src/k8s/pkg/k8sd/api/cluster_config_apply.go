@@ -0,0 +1,82 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	apiv1alpha "github.com/canonical/k8s/api/v1alpha"
+	"github.com/canonical/k8s/pkg/k8sd/database"
+	"github.com/canonical/k8s/pkg/k8sd/features"
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/k8s/pkg/utils"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/microcluster/v2/state"
+)
+
+// errInvalidClusterConfig is returned from the apply transaction when the requested configuration is rejected.
+var errInvalidClusterConfig = errors.New("invalid cluster configuration")
+
+func (e *Endpoints) postClusterConfigApply(s state.State, r *http.Request) response.Response {
+	var req apiv1alpha.ApplyClusterConfigRequest
+	if err := utils.NewStrictJSONDecoder(r.Body).Decode(&req); err != nil {
+		return response.BadRequest(fmt.Errorf("failed to decode request: %w", err))
+	}
+
+	var changes []types.ClusterConfigChange
+	if err := s.Database().Transaction(r.Context(), func(ctx context.Context, tx *sql.Tx) error {
+		existing, err := database.GetClusterConfig(ctx, tx)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve cluster configuration: %w", err)
+		}
+
+		userFacing := req.Config
+		if req.Prune {
+			// options that are stored as annotations in the user-facing configuration must be pruned as well.
+			userFacing = types.PruneUserFacingClusterConfig(userFacing, types.Annotations(existing.ToUserFacing().Annotations))
+		}
+		requested, err := types.ClusterConfigFromUserFacing(userFacing)
+		if err != nil {
+			return fmt.Errorf("%w: %w", errInvalidClusterConfig, err)
+		}
+		if err := validateValuesOverridesFeatures(requested.ValuesOverrides); err != nil {
+			return fmt.Errorf("%w: %w", errInvalidClusterConfig, err)
+		}
+
+		// Compute the changes using the same merge as SetClusterConfig, so that the dry-run
+		// reports exactly what would be applied.
+		merged, err := types.MergeClusterConfig(existing, requested)
+		if err != nil {
+			return fmt.Errorf("%w: %w", errInvalidClusterConfig, err)
+		}
+		if changes, err = types.DiffClusterConfig(existing, merged); err != nil {
+			return fmt.Errorf("failed to compare cluster configuration: %w", err)
+		}
+
+		if req.DryRun || len(changes) == 0 {
+			return nil
+		}
+		if _, err := database.SetClusterConfig(ctx, tx, requested, requestIdentity(r)); err != nil {
+			return fmt.Errorf("failed to update cluster configuration: %w", err)
+		}
+		return nil
+	}); err != nil {
+		if errors.Is(err, errInvalidClusterConfig) {
+			return response.BadRequest(err)
+		}
+		return response.InternalError(fmt.Errorf("database transaction to apply cluster configuration failed: %w", err))
+	}
+
+	applied := !req.DryRun && len(changes) > 0
+	if applied {
+		e.provider.NotifyUpdateNodeConfigController()
+		e.provider.NotifyFeatures(features.Features()...)
+	}
+
+	return response.SyncResponse(true, &apiv1alpha.ApplyClusterConfigResponse{
+		Changes: clusterConfigChangesToAPI(changes),
+		Applied: applied,
+	})
+}
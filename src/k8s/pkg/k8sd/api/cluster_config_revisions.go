@@ -0,0 +1,127 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	apiv1alpha "github.com/canonical/k8s/api/v1alpha"
+	"github.com/canonical/k8s/pkg/k8sd/database"
+	"github.com/canonical/k8s/pkg/k8sd/features"
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/k8s/pkg/utils"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/microcluster/v2/state"
+)
+
+func (e *Endpoints) getClusterConfigRevisions(s state.State, r *http.Request) response.Response {
+	var revisions []database.ClusterConfigRevision
+	if err := s.Database().Transaction(r.Context(), func(ctx context.Context, tx *sql.Tx) error {
+		var err error
+		if revisions, err = database.ListClusterConfigRevisions(ctx, tx); err != nil {
+			return fmt.Errorf("failed to list cluster config revisions: %w", err)
+		}
+		return nil
+	}); err != nil {
+		return response.InternalError(fmt.Errorf("database transaction to list cluster config revisions failed: %w", err))
+	}
+
+	result := make([]apiv1alpha.ClusterConfigRevision, 0, len(revisions))
+	for _, revision := range revisions {
+		result = append(result, apiv1alpha.ClusterConfigRevision{
+			Revision:  revision.Revision,
+			Timestamp: revision.Timestamp,
+			Identity:  revision.Identity,
+		})
+	}
+
+	return response.SyncResponse(true, &apiv1alpha.ListClusterConfigRevisionsResponse{Revisions: result})
+}
+
+func (e *Endpoints) getClusterConfigRevisionDiff(s state.State, r *http.Request) response.Response {
+	var req apiv1alpha.DiffClusterConfigRevisionRequest
+	if err := utils.NewStrictJSONDecoder(r.Body).Decode(&req); err != nil {
+		return response.BadRequest(fmt.Errorf("failed to decode request: %w", err))
+	}
+
+	var revision database.ClusterConfigRevision
+	var current types.ClusterConfig
+	if err := s.Database().Transaction(r.Context(), func(ctx context.Context, tx *sql.Tx) error {
+		var err error
+		if revision, err = database.GetClusterConfigRevision(ctx, tx, req.Revision); err != nil {
+			return fmt.Errorf("failed to retrieve cluster config revision: %w", err)
+		}
+		if current, err = database.GetClusterConfig(ctx, tx); err != nil {
+			return fmt.Errorf("failed to retrieve cluster configuration: %w", err)
+		}
+		return nil
+	}); err != nil {
+		if errors.Is(err, database.ErrClusterConfigRevisionNotFound) {
+			return response.NotFound(err)
+		}
+		return response.InternalError(fmt.Errorf("database transaction to retrieve cluster config revision failed: %w", err))
+	}
+
+	changes, err := types.DiffClusterConfig(revision.Config, current)
+	if err != nil {
+		return response.InternalError(fmt.Errorf("failed to compare cluster configuration: %w", err))
+	}
+
+	return response.SyncResponse(true, &apiv1alpha.DiffClusterConfigRevisionResponse{Changes: clusterConfigChangesToAPI(changes)})
+}
+
+func clusterConfigChangesToAPI(changes []types.ClusterConfigChange) []apiv1alpha.ClusterConfigChange {
+	result := make([]apiv1alpha.ClusterConfigChange, 0, len(changes))
+	for _, change := range changes {
+		result = append(result, apiv1alpha.ClusterConfigChange{Key: change.Key, Old: change.Old, New: change.New})
+	}
+	return result
+}
+
+func (e *Endpoints) postClusterConfigRevisionRollback(s state.State, r *http.Request) response.Response {
+	var req apiv1alpha.RollbackClusterConfigRevisionRequest
+	if err := utils.NewStrictJSONDecoder(r.Body).Decode(&req); err != nil {
+		return response.BadRequest(fmt.Errorf("failed to decode request: %w", err))
+	}
+
+	var newRevision int64
+	if err := s.Database().Transaction(r.Context(), func(ctx context.Context, tx *sql.Tx) error {
+		revision, err := database.GetClusterConfigRevision(ctx, tx, req.Revision)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve cluster config revision: %w", err)
+		}
+		current, err := database.GetClusterConfig(ctx, tx)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve cluster configuration: %w", err)
+		}
+		// Only the user-facing options that can be changed are restored. The update goes through the same
+		// merge and validation as regular updates.
+		update, err := types.RollbackClusterConfig(revision.Config, current)
+		if err != nil {
+			return fmt.Errorf("failed to prepare rollback of revision %d: %w", req.Revision, err)
+		}
+		if _, err := database.SetClusterConfig(ctx, tx, update, requestIdentity(r)); err != nil {
+			return fmt.Errorf("failed to update cluster configuration: %w", err)
+		}
+		revisions, err := database.ListClusterConfigRevisions(ctx, tx)
+		if err != nil {
+			return fmt.Errorf("failed to list cluster config revisions: %w", err)
+		}
+		if len(revisions) > 0 {
+			newRevision = revisions[len(revisions)-1].Revision
+		}
+		return nil
+	}); err != nil {
+		if errors.Is(err, database.ErrClusterConfigRevisionNotFound) {
+			return response.NotFound(err)
+		}
+		return response.InternalError(fmt.Errorf("database transaction to roll back cluster configuration failed: %w", err))
+	}
+
+	e.provider.NotifyUpdateNodeConfigController()
+	e.provider.NotifyFeatures(features.Features()...)
+
+	return response.SyncResponse(true, &apiv1alpha.RollbackClusterConfigRevisionResponse{Revision: newRevision})
+}
@@ -0,0 +1,177 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"sync"
+	"time"
+
+	apiv1alpha "github.com/canonical/k8s/api/v1alpha"
+	"github.com/canonical/k8s/pkg/k8sd/database"
+	databaseutil "github.com/canonical/k8s/pkg/k8sd/database/util"
+	"github.com/canonical/k8s/pkg/k8sd/datastore"
+	"github.com/canonical/k8s/pkg/log"
+	"github.com/canonical/k8s/pkg/utils"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/microcluster/v2/state"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+const (
+	// cutoverSettle is the time without writes to k8s-dqlite after which kube-apiserver is considered to use the new datastore on all nodes.
+	cutoverSettle = 30 * time.Second
+	// cutoverTimeout is the maximum time to wait for kube-apiserver to switch to the new datastore on all nodes.
+	cutoverTimeout = 15 * time.Minute
+)
+
+// datastoreMigration is the progress of the latest datastore migration started on this node.
+// The migration runs in the background of k8sd and stops if k8sd stops, so its progress is only kept in memory.
+type datastoreMigration struct {
+	mu     sync.Mutex
+	status *apiv1alpha.DatastoreMigration
+}
+
+// start records the start of a new migration. start returns false if a migration is already in progress.
+func (m *datastoreMigration) start() (apiv1alpha.DatastoreMigration, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.status != nil && m.status.Phase != "completed" && m.status.Phase != "failed" {
+		return *m.status, false
+	}
+	now := time.Now()
+	m.status = &apiv1alpha.DatastoreMigration{Phase: "copying", StartedAt: now, UpdatedAt: now}
+	return *m.status, true
+}
+
+// update applies f to the progress of the migration in progress.
+func (m *datastoreMigration) update(f func(status *apiv1alpha.DatastoreMigration)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	f(m.status)
+	m.status.UpdatedAt = time.Now()
+}
+
+// get returns the progress of the latest migration, or nil if no migration was started.
+func (m *datastoreMigration) get() *apiv1alpha.DatastoreMigration {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.status == nil {
+		return nil
+	}
+	status := *m.status
+	return &status
+}
+
+func (e *Endpoints) postMigrateDatastore(s state.State, r *http.Request) response.Response {
+	var req apiv1alpha.MigrateDatastoreRequest
+	if err := utils.NewStrictJSONDecoder(r.Body).Decode(&req); err != nil {
+		return response.BadRequest(fmt.Errorf("failed to decode request: %w", err))
+	}
+
+	if req.Type != "external" {
+		return response.BadRequest(fmt.Errorf("unsupported datastore type %q, only migration to an external datastore is supported", req.Type))
+	}
+	if len(req.Servers) == 0 {
+		return response.BadRequest(fmt.Errorf("no datastore servers specified"))
+	}
+
+	config, err := databaseutil.GetClusterConfig(r.Context(), s)
+	if err != nil {
+		return response.InternalError(fmt.Errorf("failed to retrieve cluster configuration: %w", err))
+	}
+	if config.Datastore.GetType() != "k8s-dqlite" {
+		return response.BadRequest(fmt.Errorf("cluster uses datastore %q, only migration from k8s-dqlite is supported", config.Datastore.GetType()))
+	}
+
+	src, err := datastore.NewClient([]string{fmt.Sprintf("unix://%s", filepath.Join(e.provider.Snap().K8sDqliteStateDir(), "k8s-dqlite.sock"))}, "", "", "")
+	if err != nil {
+		return response.InternalError(fmt.Errorf("failed to create k8s-dqlite client: %w", err))
+	}
+	dst, err := datastore.NewClient(req.Servers, req.CACert, req.ClientCert, req.ClientKey)
+	if err != nil {
+		src.Close()
+		return response.BadRequest(fmt.Errorf("failed to create external datastore client: %w", err))
+	}
+
+	status, ok := e.datastoreMigration.start()
+	if !ok {
+		src.Close()
+		dst.Close()
+		return response.BadRequest(fmt.Errorf("a datastore migration is already in progress since %s", status.StartedAt.Format(time.RFC3339)))
+	}
+
+	// NOTE: The migration outlives the request, so it runs with the context of the API server.
+	identity := requestIdentity(r)
+	go func() {
+		defer src.Close()
+		defer dst.Close()
+
+		if err := e.migrateDatastore(e.Context(), s, src, dst, req, identity); err != nil {
+			log.FromContext(e.Context()).WithValues("datastore", "migrate").Error(err, "Datastore migration failed")
+			e.datastoreMigration.update(func(status *apiv1alpha.DatastoreMigration) {
+				status.Phase = "failed"
+				status.Message = err.Error()
+			})
+		}
+	}()
+
+	return response.SyncResponse(true, &apiv1alpha.MigrateDatastoreResponse{Migration: status})
+}
+
+// migrateDatastore copies the contents of k8s-dqlite to the external datastore, switches the cluster to the
+// external datastore and verifies the migration once kube-apiserver uses the external datastore on all nodes.
+func (e *Endpoints) migrateDatastore(ctx context.Context, s state.State, src *clientv3.Client, dst *clientv3.Client, req apiv1alpha.MigrateDatastoreRequest, identity string) error {
+	log := log.FromContext(ctx).WithValues("datastore", "migrate")
+
+	result, err := datastore.Migrate(ctx, src, src, dst, dst)
+	if err != nil {
+		return fmt.Errorf("failed to migrate datastore: %w", err)
+	}
+	log.Info("Migrated datastore contents", "keys", result.Keys, "sourceRevision", result.SourceRevision, "targetRevision", result.TargetRevision)
+	e.datastoreMigration.update(func(status *apiv1alpha.DatastoreMigration) {
+		status.Phase = "cutover"
+		status.Keys = result.Keys
+		status.SourceRevision = result.SourceRevision
+		status.TargetRevision = result.TargetRevision
+	})
+
+	// The control plane configuration controller on each control plane node picks up the new datastore
+	// configuration and restarts kube-apiserver.
+	if err := s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		if _, err := database.SetDatastoreConfig(ctx, tx, req.Servers, req.CACert, req.ClientCert, req.ClientKey, identity); err != nil {
+			return fmt.Errorf("failed to update datastore configuration: %w", err)
+		}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("database transaction to update datastore configuration failed: %w", err)
+	}
+	e.datastoreMigration.update(func(status *apiv1alpha.DatastoreMigration) {
+		status.Phase = "verifying"
+	})
+
+	ctx, cancel := context.WithTimeout(ctx, cutoverTimeout)
+	defer cancel()
+	cutover, err := datastore.VerifyCutover(ctx, src, src, dst, dst, result, cutoverSettle)
+	if err != nil {
+		return fmt.Errorf("failed to verify datastore after the cutover: %w", err)
+	}
+	log.Info("Verified datastore after the cutover", "keys", cutover.Keys, "synced", cutover.Synced, "conflicts", cutover.Conflicts)
+	e.datastoreMigration.update(func(status *apiv1alpha.DatastoreMigration) {
+		status.Phase = "completed"
+		status.Keys = cutover.Keys
+		status.CutoverSynced = cutover.Synced
+		status.CutoverConflicts = cutover.Conflicts
+	})
+
+	return nil
+}
+
+func (e *Endpoints) getDatastoreMigration(s state.State, r *http.Request) response.Response {
+	return response.SyncResponse(true, &apiv1alpha.GetDatastoreMigrationResponse{Migration: e.datastoreMigration.get()})
+}
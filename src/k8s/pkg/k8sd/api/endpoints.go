@@ -6,12 +6,15 @@ import (
 	"time"
 
 	apiv1 "github.com/canonical/k8s-snap-api/api/v1"
+	apiv1alpha "github.com/canonical/k8s/api/v1alpha"
 	"github.com/canonical/microcluster/v2/rest"
 )
 
 type Endpoints struct {
 	context  context.Context
 	provider Provider
+
+	datastoreMigration datastoreMigration
 }
 
 // New creates a new API server instance.
@@ -59,6 +62,12 @@ func (e *Endpoints) Endpoints() []rest.Endpoint {
 			Path: apiv1.NodeStatusRPC,
 			Get:  rest.EndpointAction{Handler: e.getNodeStatus},
 		},
+		// Returns the state of the k8s-apiserver-proxy of the local (worker) node.
+		{
+			Name: "NodeAPIServerProxyStatus",
+			Path: apiv1alpha.GetAPIServerProxyStatusRPC,
+			Get:  rest.EndpointAction{Handler: e.getAPIServerProxyStatus},
+		},
 		// Clustering
 		// Unified token endpoint for both, control-plane and worker-node.
 		{
@@ -111,12 +120,38 @@ func (e *Endpoints) Endpoints() []rest.Endpoint {
 			Path: apiv1.CertificatesStatusRPC,
 			Get:  rest.EndpointAction{Handler: e.getCertificatesStatus},
 		},
+		{
+			Name: "Certificates/Rotations",
+			Path: apiv1alpha.ListCertificateRotationsRPC,
+			Get:  rest.EndpointAction{Handler: e.getCertificateRotations},
+		},
+		{
+			Name: "Certificates/CARotation",
+			Path: apiv1alpha.CARotationRPC,
+			Get:  rest.EndpointAction{Handler: e.getCARotation},
+			Post: rest.EndpointAction{Handler: e.postCARotation, AccessHandler: e.restrictWorkers},
+		},
 		// Kubeconfig
 		{
 			Name: "Kubeconfig",
 			Path: apiv1.KubeConfigRPC,
 			Get:  rest.EndpointAction{Handler: e.getKubeconfig, AccessHandler: e.restrictWorkers},
 		},
+		{
+			Name: "Kubeconfigs",
+			Path: apiv1alpha.ListKubeconfigsRPC,
+			Get:  rest.EndpointAction{Handler: e.getKubeconfigs, AccessHandler: e.restrictWorkers},
+		},
+		{
+			Name: "Kubeconfigs/Issue",
+			Path: apiv1alpha.IssueKubeconfigRPC,
+			Post: rest.EndpointAction{Handler: e.postIssueKubeconfig, AccessHandler: e.restrictWorkers},
+		},
+		{
+			Name: "Kubeconfigs/Revoke",
+			Path: apiv1alpha.RevokeKubeconfigRPC,
+			Post: rest.EndpointAction{Handler: e.postRevokeKubeconfig, AccessHandler: e.restrictWorkers},
+		},
 		// Get and modify the cluster configuration (e.g. to enable/disable features)
 		{
 			Name: "ClusterConfig",
@@ -124,6 +159,51 @@ func (e *Endpoints) Endpoints() []rest.Endpoint {
 			Put:  rest.EndpointAction{Handler: e.putClusterConfig, AccessHandler: e.restrictWorkers},
 			Get:  rest.EndpointAction{Handler: e.getClusterConfig, AccessHandler: e.restrictWorkers},
 		},
+		// Declarative cluster configuration
+		{
+			Name: "ClusterConfig/Apply",
+			Path: apiv1alpha.ApplyClusterConfigRPC,
+			Post: rest.EndpointAction{Handler: e.postClusterConfigApply, AccessHandler: e.restrictWorkers},
+		},
+		// Cluster configuration history
+		{
+			Name: "ClusterConfig/Revisions",
+			Path: apiv1alpha.ListClusterConfigRevisionsRPC,
+			Get:  rest.EndpointAction{Handler: e.getClusterConfigRevisions, AccessHandler: e.restrictWorkers},
+		},
+		{
+			Name: "ClusterConfig/Revisions/Diff",
+			Path: apiv1alpha.DiffClusterConfigRevisionRPC,
+			Get:  rest.EndpointAction{Handler: e.getClusterConfigRevisionDiff, AccessHandler: e.restrictWorkers},
+		},
+		{
+			Name: "ClusterConfig/Revisions/Rollback",
+			Path: apiv1alpha.RollbackClusterConfigRevisionRPC,
+			Post: rest.EndpointAction{Handler: e.postClusterConfigRevisionRollback, AccessHandler: e.restrictWorkers},
+		},
+		// Registered features, feature status, reconciliation history and effective values
+		{
+			Name: "Features",
+			Path: apiv1alpha.ListFeaturesRPC,
+			Get:  rest.EndpointAction{Handler: e.getFeatures},
+		},
+		{
+			Name: "Features/Status",
+			Path: apiv1alpha.GetFeatureStatusRPC,
+			Get:  rest.EndpointAction{Handler: e.getFeatureStatus, AccessHandler: e.restrictWorkers},
+		},
+		{
+			Name: "Features/Values",
+			Path: apiv1alpha.GetFeatureValuesRPC,
+			Get:  rest.EndpointAction{Handler: e.getFeatureValues, AccessHandler: e.restrictWorkers},
+		},
+		// Datastore migration
+		{
+			Name: "Datastore/Migrate",
+			Path: apiv1alpha.MigrateDatastoreRPC,
+			Post: rest.EndpointAction{Handler: e.postMigrateDatastore, AccessHandler: e.restrictWorkers},
+			Get:  rest.EndpointAction{Handler: e.getDatastoreMigration, AccessHandler: e.restrictWorkers},
+		},
 		// Kubernetes auth tokens and token review webhook for kube-apiserver
 		{
 			Name:   "KubernetesAuthTokens",
@@ -131,6 +211,16 @@ func (e *Endpoints) Endpoints() []rest.Endpoint {
 			Post:   rest.EndpointAction{Handler: e.postKubernetesAuthTokens},
 			Delete: rest.EndpointAction{Handler: e.deleteKubernetesAuthTokens},
 		},
+		{
+			Name: "KubernetesAuthTokens/List",
+			Path: apiv1alpha.ListKubernetesAuthTokensRPC,
+			Get:  rest.EndpointAction{Handler: e.getKubernetesAuthTokensList, AccessHandler: e.restrictWorkers},
+		},
+		{
+			Name: "KubernetesAuthTokens/Revoke",
+			Path: apiv1alpha.RevokeKubernetesAuthTokensRPC,
+			Post: rest.EndpointAction{Handler: e.postKubernetesAuthTokensRevoke, AccessHandler: e.restrictWorkers},
+		},
 		{
 			Name: "KubernetesAuthWebhook",
 			Path: apiv1.ReviewKubernetesAuthTokenRPC,
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"slices"
+
+	apiv1alpha "github.com/canonical/k8s/api/v1alpha"
+	"github.com/canonical/k8s/pkg/k8sd/database"
+	"github.com/canonical/k8s/pkg/k8sd/features"
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/k8s/pkg/utils"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/microcluster/v2/state"
+)
+
+func (e *Endpoints) getFeatureStatus(s state.State, r *http.Request) response.Response {
+	var req apiv1alpha.GetFeatureStatusRequest
+	if err := utils.NewStrictJSONDecoder(r.Body).Decode(&req); err != nil {
+		return response.BadRequest(fmt.Errorf("failed to decode request: %w", err))
+	}
+
+	name := types.FeatureName(req.Feature)
+	if !slices.Contains(features.Features(), name) {
+		return response.NotFound(fmt.Errorf("unknown feature %q", req.Feature))
+	}
+
+	var (
+		status  types.FeatureStatus
+		history []types.FeatureReconcile
+	)
+	if err := s.Database().Transaction(r.Context(), func(ctx context.Context, tx *sql.Tx) error {
+		statuses, err := database.GetFeatureStatuses(ctx, tx)
+		if err != nil {
+			return fmt.Errorf("failed to get feature statuses: %w", err)
+		}
+		status = statuses[name]
+
+		if req.History {
+			if history, err = database.ListFeatureReconciles(ctx, tx, name); err != nil {
+				return fmt.Errorf("failed to list feature reconciles: %w", err)
+			}
+		}
+		return nil
+	}); err != nil {
+		return response.InternalError(fmt.Errorf("database transaction to get feature status failed: %w", err))
+	}
+
+	result := apiv1alpha.GetFeatureStatusResponse{Status: status.ToAPI(), Waiting: status.Waiting}
+	for _, reconcile := range history {
+		result.History = append(result.History, apiv1alpha.FeatureReconcile{
+			StartedAt:  reconcile.StartedAt,
+			FinishedAt: reconcile.FinishedAt,
+			Result:     string(reconcile.Result),
+			Error:      reconcile.Error,
+			Version:    reconcile.Version,
+			ValuesHash: reconcile.ValuesHash,
+		})
+	}
+
+	return response.SyncResponse(true, &result)
+}
+
+func (e *Endpoints) getFeatureValues(s state.State, r *http.Request) response.Response {
+	var req apiv1alpha.GetFeatureValuesRequest
+	if err := utils.NewStrictJSONDecoder(r.Body).Decode(&req); err != nil {
+		return response.BadRequest(fmt.Errorf("failed to decode request: %w", err))
+	}
+
+	name := types.FeatureName(req.Feature)
+	if !slices.Contains(features.Features(), name) {
+		return response.NotFound(fmt.Errorf("unknown feature %q", req.Feature))
+	}
+
+	var values map[string]map[string]any
+	if err := s.Database().Transaction(r.Context(), func(ctx context.Context, tx *sql.Tx) error {
+		var err error
+		if values, err = database.GetFeatureValues(ctx, tx, name); err != nil {
+			return fmt.Errorf("failed to get feature values: %w", err)
+		}
+		return nil
+	}); err != nil {
+		return response.InternalError(fmt.Errorf("database transaction to get feature values failed: %w", err))
+	}
+
+	return response.SyncResponse(true, &apiv1alpha.GetFeatureValuesResponse{Values: values})
+}
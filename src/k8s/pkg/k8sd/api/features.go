@@ -0,0 +1,29 @@
+package api
+
+import (
+	"net/http"
+
+	apiv1alpha "github.com/canonical/k8s/api/v1alpha"
+	"github.com/canonical/k8s/pkg/k8sd/features"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/microcluster/v2/state"
+)
+
+// getFeatures lists the features that are registered in k8sd, including the plugins that are only known to k8sd.
+func (e *Endpoints) getFeatures(s state.State, r *http.Request) response.Response {
+	registered := features.Registered()
+	result := apiv1alpha.ListFeaturesResponse{
+		Features: make([]apiv1alpha.Feature, 0, len(registered)),
+	}
+	for _, feature := range registered {
+		f := apiv1alpha.Feature{
+			Name:    string(feature.Name),
+			Builtin: feature.Builtin,
+		}
+		for _, dependency := range feature.Dependencies {
+			f.Dependencies = append(f.Dependencies, string(dependency))
+		}
+		result.Features = append(result.Features, f)
+	}
+	return response.SyncResponse(true, &result)
+}
@@ -0,0 +1,30 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"os/user"
+	"strconv"
+
+	"github.com/canonical/lxd/lxd/request"
+	"github.com/canonical/lxd/lxd/ucred"
+)
+
+// requestIdentity returns a human readable identity of the client that sent the request.
+// For requests on the local unix socket, this is the name of the local user.
+// For requests from other cluster members, this is the common name of the client certificate.
+func requestIdentity(r *http.Request) string {
+	if r.Context().Value(request.CtxConn) != nil {
+		if cred, err := ucred.GetCredFromContext(r.Context()); err == nil {
+			uid := strconv.FormatUint(uint64(cred.Uid), 10)
+			if u, err := user.LookupId(uid); err == nil {
+				return u.Username
+			}
+			return fmt.Sprintf("uid=%s", uid)
+		}
+	}
+	if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
+		return r.TLS.PeerCertificates[0].Subject.CommonName
+	}
+	return r.RemoteAddr
+}
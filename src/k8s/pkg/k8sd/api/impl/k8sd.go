@@ -3,8 +3,12 @@ package impl
 import (
 	"context"
 	"fmt"
+	"net"
+	"net/url"
 
 	apiv1 "github.com/canonical/k8s-snap-api/api/v1"
+	"github.com/canonical/k8s/pkg/k8sd/datastore"
+	"github.com/canonical/k8s/pkg/k8sd/types"
 	"github.com/canonical/k8s/pkg/snap"
 	snaputil "github.com/canonical/k8s/pkg/snap/util"
 	nodeutil "github.com/canonical/k8s/pkg/utils/node"
@@ -60,3 +64,43 @@ func GetLocalNodeStatus(ctx context.Context, s state.State, snap snap.Snap) (api
 		ClusterRole: clusterRole,
 	}, nil
 }
+
+// SetEtcdDatastoreRoles sets the datastore roles of the control plane nodes from the members of the managed etcd cluster.
+// Voting members are reported as voters and learners as stand-by. Members that have not started yet, as well as nodes
+// that are not (yet) members of the etcd cluster, are reported as pending.
+func SetEtcdDatastoreRoles(ctx context.Context, snap snap.Snap, config types.Datastore, members []apiv1.NodeStatus) error {
+	client, err := datastore.NewEtcdClient([]string{fmt.Sprintf("https://127.0.0.1:%d", config.GetEtcdPort())}, snap.EtcdPKIDir())
+	if err != nil {
+		return fmt.Errorf("failed to create etcd client: %w", err)
+	}
+	defer client.Close()
+
+	etcdMembers, err := datastore.ListEtcdMembers(ctx, client)
+	if err != nil {
+		return fmt.Errorf("failed to list etcd members: %w", err)
+	}
+
+	for i, member := range members {
+		members[i].DatastoreRole = apiv1.DatastoreRolePending
+
+		host, _, err := net.SplitHostPort(member.Address)
+		if err != nil {
+			continue
+		}
+		for _, etcdMember := range etcdMembers {
+			u, err := url.Parse(etcdMember.PeerURL)
+			if err != nil || !net.ParseIP(u.Hostname()).Equal(net.ParseIP(host)) {
+				continue
+			}
+			switch {
+			case etcdMember.Name == "":
+				members[i].DatastoreRole = apiv1.DatastoreRolePending
+			case etcdMember.Learner:
+				members[i].DatastoreRole = apiv1.DatastoreRoleStandBy
+			default:
+				members[i].DatastoreRole = apiv1.DatastoreRoleVoter
+			}
+		}
+	}
+	return nil
+}
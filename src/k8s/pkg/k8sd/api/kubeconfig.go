@@ -1,17 +1,48 @@
 package api
 
 import (
+	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	apiv1 "github.com/canonical/k8s-snap-api/api/v1"
+	apiv1alpha "github.com/canonical/k8s/api/v1alpha"
+	"github.com/canonical/k8s/pkg/k8sd/database"
 	databaseutil "github.com/canonical/k8s/pkg/k8sd/database/util"
 	"github.com/canonical/k8s/pkg/k8sd/setup"
+	"github.com/canonical/k8s/pkg/k8sd/types"
 	"github.com/canonical/k8s/pkg/utils"
 	"github.com/canonical/lxd/lxd/response"
 	"github.com/canonical/microcluster/v2/state"
 )
 
+const (
+	// AnnotationKubeconfigMaxTTL is the maximum lifetime of issued kubeconfigs, e.g. "8h".
+	AnnotationKubeconfigMaxTTL = "k8sd/v1alpha1/kubeconfig/max-ttl"
+
+	// defaultKubeconfigMaxTTL is the maximum lifetime of issued kubeconfigs if AnnotationKubeconfigMaxTTL is not set.
+	defaultKubeconfigMaxTTL = 24 * time.Hour
+)
+
+// kubeconfigMaxTTL returns the maximum lifetime of issued kubeconfigs.
+func kubeconfigMaxTTL(annotations types.Annotations) (time.Duration, error) {
+	v, ok := annotations.Get(AnnotationKubeconfigMaxTTL)
+	if !ok {
+		return defaultKubeconfigMaxTTL, nil
+	}
+	maxTTL, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse %s: %w", AnnotationKubeconfigMaxTTL, err)
+	}
+	if maxTTL <= 0 {
+		return 0, fmt.Errorf("%s must be positive", AnnotationKubeconfigMaxTTL)
+	}
+	return maxTTL, nil
+}
+
 func (e *Endpoints) getKubeconfig(s state.State, r *http.Request) response.Response {
 	req := apiv1.KubeConfigRequest{}
 	if err := utils.NewStrictJSONDecoder(r.Body).Decode(&req); err != nil {
@@ -37,3 +68,111 @@ func (e *Endpoints) getKubeconfig(s state.State, r *http.Request) response.Respo
 		KubeConfig: kubeconfig,
 	})
 }
+
+func (e *Endpoints) postIssueKubeconfig(s state.State, r *http.Request) response.Response {
+	req := apiv1alpha.IssueKubeconfigRequest{}
+	if err := utils.NewStrictJSONDecoder(r.Body).Decode(&req); err != nil {
+		return response.BadRequest(fmt.Errorf("failed to parse request: %w", err))
+	}
+	if req.Username == "" {
+		return response.BadRequest(fmt.Errorf("username must be set"))
+	}
+	if req.TTL < 0 {
+		return response.BadRequest(fmt.Errorf("ttl must not be negative"))
+	}
+
+	config, err := databaseutil.GetClusterConfig(r.Context(), s)
+	if err != nil {
+		return response.InternalError(fmt.Errorf("failed to retrieve cluster config: %w", err))
+	}
+
+	maxTTL, err := kubeconfigMaxTTL(config.Annotations)
+	if err != nil {
+		return response.InternalError(err)
+	}
+	ttl := req.TTL
+	if ttl == 0 {
+		ttl = maxTTL
+	} else if ttl > maxTTL {
+		return response.BadRequest(fmt.Errorf("ttl %v exceeds the maximum of %v, which is configured with the %s annotation", ttl, maxTTL, AnnotationKubeconfigMaxTTL))
+	}
+
+	server := req.Server
+	if req.Server == "" {
+		server = fmt.Sprintf("%s:%d", s.Address().Hostname(), config.APIServer.GetSecurePort())
+	}
+
+	now := time.Now().Truncate(time.Second)
+	kubeconfigToken := types.KubernetesAuthToken{
+		Username: req.Username,
+		Groups:   req.Groups,
+		IssuedAt: now,
+		IssuedBy: requestIdentity(r),
+		Expiry:   now.Add(ttl),
+	}
+	var token string
+	if err := s.Database().Transaction(r.Context(), func(ctx context.Context, tx *sql.Tx) error {
+		var err error
+		kubeconfigToken.ID, token, err = database.CreateToken(ctx, tx, kubeconfigToken)
+		return err
+	}); err != nil {
+		return response.InternalError(fmt.Errorf("database transaction to issue kubeconfig token failed: %w", err))
+	}
+
+	kubeconfig, err := setup.KubeconfigTokenString(server, config.Certificates.GetCACert(), token)
+	if err != nil {
+		return response.InternalError(fmt.Errorf("failed to get kubeconfig: %w", err))
+	}
+
+	return response.SyncResponse(true, &apiv1alpha.IssueKubeconfigResponse{
+		KubeConfig: kubeconfig,
+		Issued:     issuedKubeconfig(kubeconfigToken),
+	})
+}
+
+func (e *Endpoints) getKubeconfigs(s state.State, r *http.Request) response.Response {
+	var tokens []types.KubernetesAuthToken
+	if err := s.Database().Transaction(r.Context(), func(ctx context.Context, tx *sql.Tx) error {
+		var err error
+		tokens, err = database.ListKubeconfigTokens(ctx, tx)
+		return err
+	}); err != nil {
+		return response.InternalError(fmt.Errorf("database transaction to list kubeconfig tokens failed: %w", err))
+	}
+
+	result := make([]apiv1alpha.IssuedKubeconfig, 0, len(tokens))
+	for _, token := range tokens {
+		result = append(result, issuedKubeconfig(token))
+	}
+
+	return response.SyncResponse(true, &apiv1alpha.ListKubeconfigsResponse{Kubeconfigs: result})
+}
+
+func (e *Endpoints) postRevokeKubeconfig(s state.State, r *http.Request) response.Response {
+	req := apiv1alpha.RevokeKubeconfigRequest{}
+	if err := utils.NewStrictJSONDecoder(r.Body).Decode(&req); err != nil {
+		return response.BadRequest(fmt.Errorf("failed to parse request: %w", err))
+	}
+
+	if err := s.Database().Transaction(r.Context(), func(ctx context.Context, tx *sql.Tx) error {
+		return database.RevokeKubeconfigToken(ctx, tx, req.ID)
+	}); err != nil {
+		if errors.Is(err, database.ErrKubeconfigTokenNotFound) {
+			return response.NotFound(err)
+		}
+		return response.InternalError(fmt.Errorf("database transaction to revoke kubeconfig token failed: %w", err))
+	}
+
+	return response.SyncResponse(true, &apiv1alpha.RevokeKubeconfigResponse{})
+}
+
+func issuedKubeconfig(token types.KubernetesAuthToken) apiv1alpha.IssuedKubeconfig {
+	return apiv1alpha.IssuedKubeconfig{
+		ID:        token.ID,
+		Username:  token.Username,
+		Groups:    token.Groups,
+		IssuedAt:  token.IssuedAt,
+		IssuedBy:  token.IssuedBy,
+		ExpiresAt: token.Expiry,
+	}
+}
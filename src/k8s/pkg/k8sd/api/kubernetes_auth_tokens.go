@@ -9,8 +9,10 @@ import (
 	"net/http"
 
 	apiv1 "github.com/canonical/k8s-snap-api/api/v1"
+	apiv1alpha "github.com/canonical/k8s/api/v1alpha"
 	"github.com/canonical/k8s/pkg/k8sd/database"
 	databaseutil "github.com/canonical/k8s/pkg/k8sd/database/util"
+	"github.com/canonical/k8s/pkg/k8sd/types"
 	"github.com/canonical/k8s/pkg/utils"
 	"github.com/canonical/lxd/lxd/response"
 	"github.com/canonical/microcluster/v2/state"
@@ -22,7 +24,7 @@ func (e *Endpoints) postKubernetesAuthTokens(s state.State, r *http.Request) res
 		return response.BadRequest(fmt.Errorf("failed to parse request: %w", err))
 	}
 
-	token, err := databaseutil.GetOrCreateAuthToken(r.Context(), s, request.Username, request.Groups)
+	token, err := databaseutil.CreateAuthToken(r.Context(), s, request.Username, request.Groups)
 	if err != nil {
 		return response.InternalError(err)
 	}
@@ -44,6 +46,76 @@ func (e *Endpoints) deleteKubernetesAuthTokens(s state.State, r *http.Request) r
 	return response.SyncResponse(true, nil)
 }
 
+func (e *Endpoints) getKubernetesAuthTokensList(s state.State, r *http.Request) response.Response {
+	req := apiv1alpha.ListKubernetesAuthTokensRequest{}
+	if err := utils.NewStrictJSONDecoder(r.Body).Decode(&req); err != nil {
+		return response.BadRequest(fmt.Errorf("failed to parse request: %w", err))
+	}
+
+	var tokens []types.KubernetesAuthToken
+	if err := s.Database().Transaction(r.Context(), func(ctx context.Context, tx *sql.Tx) error {
+		var err error
+		tokens, err = database.ListTokens(ctx, tx, req.Username)
+		return err
+	}); err != nil {
+		return response.InternalError(fmt.Errorf("database transaction to list auth tokens failed: %w", err))
+	}
+
+	result := make([]apiv1alpha.KubernetesAuthToken, 0, len(tokens))
+	for _, token := range tokens {
+		result = append(result, kubernetesAuthToken(token))
+	}
+
+	return response.SyncResponse(true, &apiv1alpha.ListKubernetesAuthTokensResponse{Tokens: result})
+}
+
+func (e *Endpoints) postKubernetesAuthTokensRevoke(s state.State, r *http.Request) response.Response {
+	req := apiv1alpha.RevokeKubernetesAuthTokensRequest{}
+	if err := utils.NewStrictJSONDecoder(r.Body).Decode(&req); err != nil {
+		return response.BadRequest(fmt.Errorf("failed to parse request: %w", err))
+	}
+	if (req.ID == 0) == (req.Username == "") {
+		return response.BadRequest(fmt.Errorf("exactly one of id and username must be set"))
+	}
+
+	var revoked int64
+	if err := s.Database().Transaction(r.Context(), func(ctx context.Context, tx *sql.Tx) error {
+		if req.Username != "" {
+			var err error
+			revoked, err = database.DeleteTokensByUsername(ctx, tx, req.Username)
+			return err
+		}
+		if err := database.DeleteTokenByID(ctx, tx, req.ID); err != nil {
+			return err
+		}
+		revoked = 1
+		return nil
+	}); err != nil {
+		if errors.Is(err, database.ErrTokenNotFound) {
+			return response.NotFound(err)
+		}
+		return response.InternalError(fmt.Errorf("database transaction to revoke auth tokens failed: %w", err))
+	}
+
+	return response.SyncResponse(true, &apiv1alpha.RevokeKubernetesAuthTokensResponse{Revoked: revoked})
+}
+
+func kubernetesAuthToken(token types.KubernetesAuthToken) apiv1alpha.KubernetesAuthToken {
+	result := apiv1alpha.KubernetesAuthToken{
+		ID:       token.ID,
+		Username: token.Username,
+		Groups:   token.Groups,
+		IssuedBy: token.IssuedBy,
+	}
+	if !token.Expiry.IsZero() {
+		result.ExpiresAt = utils.Pointer(token.Expiry)
+	}
+	if !token.LastUsed.IsZero() {
+		result.LastUsed = utils.Pointer(token.LastUsed)
+	}
+	return result
+}
+
 // postKubernetesAuthWebhook is used by kube-apiserver to handle TokenReview objects.
 // Note that we do not use the normal response.SyncResponse here, because it breaks the response format that kube-apiserver expects.
 func (e *Endpoints) postKubernetesAuthWebhook(s state.State, r *http.Request) response.Response {
@@ -1,6 +1,8 @@
 package api
 
 import (
+	"github.com/canonical/k8s/pkg/k8sd/controllers/csrsigning"
+	"github.com/canonical/k8s/pkg/k8sd/types"
 	"github.com/canonical/k8s/pkg/snap"
 	"github.com/canonical/microcluster/v2/microcluster"
 )
@@ -10,5 +12,6 @@ type Provider interface {
 	MicroCluster() *microcluster.MicroCluster
 	Snap() snap.Snap
 	NotifyUpdateNodeConfigController()
-	NotifyFeatureController(network, gateway, ingress, loadBalancer, localStorage, metricsServer, dns bool)
+	NotifyFeatures(names ...types.FeatureName)
+	Signers() *csrsigning.SignerCache
 }
@@ -9,9 +9,11 @@ import (
 	"time"
 
 	apiv1 "github.com/canonical/k8s-snap-api/api/v1"
+	"github.com/canonical/k8s/pkg/k8sd/controllers/csrsigning"
 	"github.com/canonical/k8s/pkg/k8sd/database"
 	databaseutil "github.com/canonical/k8s/pkg/k8sd/database/util"
 	"github.com/canonical/k8s/pkg/k8sd/pki"
+	"github.com/canonical/k8s/pkg/k8sd/types"
 	"github.com/canonical/k8s/pkg/utils"
 	"github.com/canonical/lxd/lxd/response"
 	"github.com/canonical/microcluster/v2/state"
@@ -38,16 +40,37 @@ func (e *Endpoints) postWorkerInfo(s state.State, r *http.Request) response.Resp
 		return response.InternalError(fmt.Errorf("failed to get cluster config: %w", err))
 	}
 
+	// NOTE: CA keys that are not stored in the cluster configuration may be available through the configured signer.
+	signer, release, err := e.provider.Signers().Get(cfg)
+	if err != nil {
+		return response.InternalError(fmt.Errorf("failed to create CA signer: %w", err))
+	}
+	defer release()
+	caSigner, err := signerKey(r.Context(), signer, csrsigning.CAKubernetes)
+	if err != nil {
+		return response.InternalError(fmt.Errorf("failed to get kubernetes CA signer: %w", err))
+	}
+	clientCASigner, err := signerKey(r.Context(), signer, csrsigning.CAKubernetesClient)
+	if err != nil {
+		return response.InternalError(fmt.Errorf("failed to get kubernetes client CA signer: %w", err))
+	}
+
 	// NOTE: Set the notBefore certificate time to the current time.
 	notBefore := time.Now()
 
 	// NOTE: Default certificate expiration is set to 10 years.
-	certificates := pki.NewControlPlanePKI(pki.ControlPlanePKIOpts{NotBefore: notBefore, NotAfter: notBefore.AddDate(10, 0, 0)})
+	certificates := pki.NewControlPlanePKI(pki.ControlPlanePKIOpts{
+		NotBefore:      notBefore,
+		NotAfter:       notBefore.AddDate(10, 0, 0),
+		KeyAlgorithm:   cfg.Certificates.GetKeyAlgorithm(),
+		CASigner:       caSigner,
+		ClientCASigner: clientCASigner,
+	})
 	certificates.CACert = cfg.Certificates.GetCACert()
 	certificates.CAKey = cfg.Certificates.GetCAKey()
 	certificates.ClientCACert = cfg.Certificates.GetClientCACert()
 	certificates.ClientCAKey = cfg.Certificates.GetClientCAKey()
-	workerCertificates, err := certificates.CompleteWorkerNodePKI(workerName, nodeIP, 2048)
+	workerCertificates, err := certificates.CompleteWorkerNodePKI(workerName, nodeIP, cfg.Certificates.GetKeyAlgorithm())
 	if err != nil {
 		return response.InternalError(fmt.Errorf("failed to generate worker PKI: %w", err))
 	}
@@ -97,6 +120,22 @@ func (e *Endpoints) postWorkerInfo(s state.State, r *http.Request) response.Resp
 		KubeProxyClientCert: workerCertificates.KubeProxyClientCert,
 		KubeProxyClientKey:  workerCertificates.KubeProxyClientKey,
 		K8sdPublicKey:       cfg.Certificates.GetK8sdPublicKey(),
-		Annotations:         cfg.Annotations,
+		Annotations:         workerAnnotations(cfg),
 	})
 }
+
+// workerAnnotations returns the cluster annotations for a joining worker node.
+// The key algorithm of the cluster certificates is included, so that the worker node requests certificates with
+// private keys of the same algorithm.
+func workerAnnotations(cfg types.ClusterConfig) map[string]string {
+	if cfg.Certificates.KeyAlgorithm == nil {
+		return cfg.Annotations
+	}
+
+	annotations := make(map[string]string, len(cfg.Annotations)+1)
+	for key, value := range cfg.Annotations {
+		annotations[key] = value
+	}
+	annotations[types.AnnotationCertificatesKeyAlgorithm] = *cfg.Certificates.KeyAlgorithm
+	return annotations
+}
@@ -11,6 +11,7 @@ import (
 	apiv1 "github.com/canonical/k8s-snap-api/api/v1"
 	"github.com/canonical/k8s/pkg/k8sd/api"
 	"github.com/canonical/k8s/pkg/k8sd/controllers"
+	"github.com/canonical/k8s/pkg/k8sd/controllers/csrsigning"
 	"github.com/canonical/k8s/pkg/k8sd/controllers/upgrade"
 	"github.com/canonical/k8s/pkg/k8sd/database"
 	"github.com/canonical/k8s/pkg/k8sd/features"
@@ -54,6 +55,22 @@ type Config struct {
 	// FeatureControllerMaxRetryAttempts is the maximum number of retry attempts for the reconcile loop
 	// of the feature controller. Zero or negative values mean no limit.
 	FeatureControllerMaxRetryAttempts int
+	// BackupInterval is the interval between scheduled datastore backups. Zero disables the backup controller.
+	BackupInterval time.Duration
+	// BackupRetention is the number of scheduled datastore backups to keep.
+	BackupRetention int
+	// BackupDir is the directory to store datastore backups. Empty to use the snap default.
+	BackupDir string
+	// CertificateRotationWindow is the time before expiry when node certificates are renewed automatically.
+	// Zero disables the certificate rotation controller.
+	CertificateRotationWindow time.Duration
+	// CertificateRotationInterval is the interval between checks for expiring node certificates.
+	CertificateRotationInterval time.Duration
+	// AuthTokenSweepInterval is the interval between deletions of expired Kubernetes auth tokens.
+	// Zero disables the auth token sweeper controller.
+	AuthTokenSweepInterval time.Duration
+	// MetricsBindAddress is the address to serve the k8sd metrics on. "0" disables the metrics endpoint.
+	MetricsBindAddress string
 }
 
 // App is the k8sd microcluster instance.
@@ -63,30 +80,34 @@ type App struct {
 	client  *client.Client
 	snap    snap.Snap
 
+	// signers keeps the CA signer, which is shared by the API endpoints and the controllers.
+	signers *csrsigning.SignerCache
+
 	// profilingAddress
 	profilingAddress string
 
 	// readyWg is used to denote that the microcluster node is now running
 	readyWg sync.WaitGroup
 
-	nodeConfigController         *controllers.NodeConfigurationController
-	nodeLabelController          *controllers.NodeLabelController
-	controlPlaneConfigController *controllers.ControlPlaneConfigurationController
-	controllerCoordinator        *controllers.Coordinator
+	nodeConfigController          *controllers.NodeConfigurationController
+	nodeLabelController           *controllers.NodeLabelController
+	controlPlaneConfigController  *controllers.ControlPlaneConfigurationController
+	backupController              *controllers.BackupController
+	certificateRotationController *controllers.CertificateRotationController
+	caRotationController          *controllers.CARotationController
+	authTokenSweeperController    *controllers.AuthTokenSweeperController
+	prometheusCRDController       *controllers.PrometheusCRDController
+	networkMigrationController    *controllers.NetworkMigrationController
+	controllerCoordinator         *controllers.Coordinator
 
 	// updateNodeConfigController
 	triggerUpdateNodeConfigControllerCh chan struct{}
 	updateNodeConfigController          *controllers.UpdateNodeConfigurationController
 
 	// featureController
-	triggerFeatureControllerNetworkCh       chan struct{}
-	triggerFeatureControllerGatewayCh       chan struct{}
-	triggerFeatureControllerIngressCh       chan struct{}
-	triggerFeatureControllerLoadBalancerCh  chan struct{}
-	triggerFeatureControllerLocalStorageCh  chan struct{}
-	triggerFeatureControllerMetricsServerCh chan struct{}
-	triggerFeatureControllerDNSCh           chan struct{}
-	featureController                       *controllers.FeatureController
+	// triggerFeatureControllerChs are the trigger channels of the registered features, keyed by feature name.
+	triggerFeatureControllerChs map[types.FeatureName]chan struct{}
+	featureController           *controllers.FeatureController
 }
 
 // New initializes a new microcluster instance from configuration.
@@ -111,6 +132,7 @@ func New(cfg Config) (*App, error) {
 		client:           client,
 		snap:             cfg.Snap,
 		profilingAddress: cfg.PprofAddress,
+		signers:          csrsigning.NewSignerCache(),
 	}
 	app.readyWg.Add(1)
 
@@ -134,6 +156,8 @@ func New(cfg Config) (*App, error) {
 				}
 				return serverStatus.Name, nil
 			},
+			features.Cleanup.CleanupNetworkProvider,
+			features.Cleanup.CleanupNetworkProviderConfig,
 		)
 	} else {
 		log.L().Info("node-label-controller disabled via config")
@@ -149,6 +173,52 @@ func New(cfg Config) (*App, error) {
 		log.L().Info("control-plane-config-controller disabled via config")
 	}
 
+	if cfg.BackupInterval > 0 {
+		app.backupController = controllers.NewBackupController(
+			cfg.Snap,
+			app.readyWg.Wait,
+			time.NewTicker(cfg.BackupInterval).C,
+			cfg.BackupDir,
+			cfg.BackupRetention,
+		)
+	} else {
+		log.L().Info("backup-controller disabled via config")
+	}
+
+	if cfg.CertificateRotationWindow > 0 && cfg.CertificateRotationInterval > 0 {
+		app.certificateRotationController = controllers.NewCertificateRotationController(
+			cfg.Snap,
+			app.readyWg.Wait,
+			time.NewTicker(cfg.CertificateRotationInterval).C,
+			cfg.CertificateRotationWindow,
+			app.signers,
+		)
+	} else {
+		log.L().Info("certificate-rotation-controller disabled via config")
+	}
+
+	app.caRotationController = controllers.NewCARotationController(
+		cfg.Snap,
+		app.readyWg.Wait,
+		time.NewTicker(30*time.Second).C,
+	)
+
+	if cfg.AuthTokenSweepInterval > 0 {
+		app.authTokenSweeperController = controllers.NewAuthTokenSweeperController(
+			cfg.Snap,
+			app.readyWg.Wait,
+			time.NewTicker(cfg.AuthTokenSweepInterval).C,
+		)
+	} else {
+		log.L().Info("auth-token-sweeper-controller disabled via config")
+	}
+
+	app.prometheusCRDController = controllers.NewPrometheusCRDController(
+		cfg.Snap,
+		app.readyWg.Wait,
+		time.NewTicker(time.Minute).C,
+	)
+
 	app.triggerUpdateNodeConfigControllerCh = make(chan struct{}, 1)
 
 	if !cfg.DisableUpdateNodeConfigController {
@@ -161,25 +231,22 @@ func New(cfg Config) (*App, error) {
 		log.L().Info("update-node-config-controller disabled via config")
 	}
 
-	app.triggerFeatureControllerNetworkCh = make(chan struct{}, 1)
-	app.triggerFeatureControllerGatewayCh = make(chan struct{}, 1)
-	app.triggerFeatureControllerIngressCh = make(chan struct{}, 1)
-	app.triggerFeatureControllerLoadBalancerCh = make(chan struct{}, 1)
-	app.triggerFeatureControllerLocalStorageCh = make(chan struct{}, 1)
-	app.triggerFeatureControllerMetricsServerCh = make(chan struct{}, 1)
-	app.triggerFeatureControllerDNSCh = make(chan struct{}, 1)
+	app.triggerFeatureControllerChs = make(map[types.FeatureName]chan struct{})
+	for _, name := range features.Features() {
+		app.triggerFeatureControllerChs[name] = make(chan struct{}, 1)
+	}
 
 	if !cfg.DisableFeatureController {
+		app.networkMigrationController = controllers.NewNetworkMigrationController(
+			cfg.Snap,
+			app.readyWg.Wait,
+			time.NewTicker(10*time.Second).C,
+		)
+
 		app.featureController = controllers.NewFeatureController(controllers.FeatureControllerOpts{
 			Snap:                          cfg.Snap,
 			WaitReady:                     app.readyWg.Wait,
-			TriggerNetworkCh:              app.triggerFeatureControllerNetworkCh,
-			TriggerGatewayCh:              app.triggerFeatureControllerGatewayCh,
-			TriggerIngressCh:              app.triggerFeatureControllerIngressCh,
-			TriggerLoadBalancerCh:         app.triggerFeatureControllerLoadBalancerCh,
-			TriggerDNSCh:                  app.triggerFeatureControllerDNSCh,
-			TriggerLocalStorageCh:         app.triggerFeatureControllerLocalStorageCh,
-			TriggerMetricsServerCh:        app.triggerFeatureControllerMetricsServerCh,
+			TriggerChs:                    app.triggerFeatureControllerChs,
 			ReconcileLoopMaxRetryAttempts: cfg.FeatureControllerMaxRetryAttempts,
 		})
 	} else {
@@ -191,27 +258,17 @@ func New(cfg Config) (*App, error) {
 		app.readyWg.Wait,
 		cfg.DisableUpgradeController,
 		upgrade.ControllerOptions{
-			FeatureControllerReadyCh:   app.featureController.ReadyCh(),
-			NotifyNetworkFeature:       app.NotifyNetwork,
-			NotifyGatewayFeature:       app.NotifyGateway,
-			NotifyIngressFeature:       app.NotifyIngress,
-			NotifyLoadBalancerFeature:  app.NotifyLoadBalancer,
-			NotifyLocalStorageFeature:  app.NotifyLocalStorage,
-			NotifyMetricsServerFeature: app.NotifyMetricsServer,
-			NotifyDNSFeature:           app.NotifyDNS,
-			FeatureToReconciledCh: map[types.FeatureName]<-chan struct{}{
-				features.Network:       app.featureController.ReconciledNetworkCh(),
-				features.Gateway:       app.featureController.ReconciledGatewayCh(),
-				features.Ingress:       app.featureController.ReconciledIngressCh(),
-				features.DNS:           app.featureController.ReconciledDNSCh(),
-				features.LoadBalancer:  app.featureController.ReconciledLoadBalancerCh(),
-				features.LocalStorage:  app.featureController.ReconciledLocalStorageCh(),
-				features.MetricsServer: app.featureController.ReconciledMetricsServerCh(),
+			FeatureControllerReadyCh: app.featureController.ReadyCh(),
+			NotifyFeature: func(name types.FeatureName) {
+				app.NotifyFeatures(name)
 			},
+			FeatureToReconciledCh:             app.featureController.ReconciledChs(),
 			FeatureControllerReadyTimeout:     10 * time.Minute,
 			FeatureControllerReconcileTimeout: 2 * time.Minute,
 		},
 		cfg.DisableCSRSigningController,
+		app.signers,
+		cfg.MetricsBindAddress,
 	)
 
 	return app, nil
@@ -266,6 +323,12 @@ func (a *App) Run(ctx context.Context, customHooks *state.Hooks) error {
 		}()
 	}
 
+	defer func() {
+		if err := a.signers.Close(); err != nil {
+			log.Error(err, "Failed to close CA signer")
+		}
+	}()
+
 	err := a.cluster.Start(ctx, microcluster.DaemonArgs{
 		Version:                 string(apiv1.K8sdAPIVersion),
 		Verbose:                 a.config.Verbose,
@@ -2,11 +2,24 @@ package app
 
 import (
 	"context"
+	"crypto/rand"
 	"fmt"
+	"math"
+	"math/big"
+	"net"
+	"time"
 
+	apiv1 "github.com/canonical/k8s-snap-api/api/v1"
+	"github.com/canonical/k8s/pkg/k8sd/api"
+	"github.com/canonical/k8s/pkg/k8sd/controllers/csrsigning"
+	databaseutil "github.com/canonical/k8s/pkg/k8sd/database/util"
+	"github.com/canonical/k8s/pkg/k8sd/datastore"
 	"github.com/canonical/k8s/pkg/k8sd/setup"
+	"github.com/canonical/k8s/pkg/k8sd/types"
 	"github.com/canonical/k8s/pkg/snap"
 	snaputil "github.com/canonical/k8s/pkg/snap/util"
+	"github.com/canonical/k8s/pkg/utils/control"
+	"github.com/canonical/microcluster/v2/state"
 )
 
 func startControlPlaneServices(ctx context.Context, snap snap.Snap, datastore string) error {
@@ -16,6 +29,10 @@ func startControlPlaneServices(ctx context.Context, snap snap.Snap, datastore st
 		if err := snaputil.StartK8sDqliteServices(ctx, snap); err != nil {
 			return fmt.Errorf("failed to start control plane services: %w", err)
 		}
+	case "etcd":
+		if err := snaputil.StartEtcdServices(ctx, snap); err != nil {
+			return fmt.Errorf("failed to start control plane services: %w", err)
+		}
 	case "external":
 	default:
 		return fmt.Errorf("unsupported datastore %s, must be one of %v", datastore, setup.SupportedDatastores)
@@ -27,6 +44,24 @@ func startControlPlaneServices(ctx context.Context, snap snap.Snap, datastore st
 	return nil
 }
 
+// promoteEtcdMember promotes the local etcd learner member to a voting member.
+// promoteEtcdMember retries until the learner has caught up with the leader.
+func promoteEtcdMember(ctx context.Context, snap snap.Snap, config types.Datastore, nodeIP net.IP) error {
+	client, err := datastore.NewEtcdClient([]string{fmt.Sprintf("https://127.0.0.1:%d", config.GetEtcdPort())}, snap.EtcdPKIDir())
+	if err != nil {
+		return fmt.Errorf("failed to create etcd client: %w", err)
+	}
+	defer client.Close()
+
+	peerURL := setup.EtcdPeerURL(nodeIP, config.GetEtcdPeerPort())
+	if err := control.RetryFor(ctx, 30, 2*time.Second, func() error {
+		return datastore.PromoteEtcdMember(ctx, client, peerURL)
+	}); err != nil {
+		return fmt.Errorf("failed to promote etcd member with peer URL %s: %w", peerURL, err)
+	}
+	return nil
+}
+
 func waitApiServerReady(ctx context.Context, snap snap.Snap) error {
 	// Wait for API server to come up
 	client, err := snap.KubernetesClient("")
@@ -40,3 +75,68 @@ func waitApiServerReady(ctx context.Context, snap snap.Snap) error {
 
 	return nil
 }
+
+// isDatabaseLeader returns true if the local node is the leader of the k8sd dqlite cluster.
+func isDatabaseLeader(ctx context.Context, s state.State) (bool, error) {
+	client, err := s.Database().Leader(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to connect to database leader: %w", err)
+	}
+	defer client.Close()
+
+	leader, err := client.Leader(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to get database leader: %w", err)
+	}
+	return leader.Address == s.Address().URL.Host, nil
+}
+
+// refreshNodeCertificates renews the given certificates of the local node, without restarting any services.
+func refreshNodeCertificates(ctx context.Context, s state.State, snap snap.Snap, signers *csrsigning.SignerCache, certificates []apiv1.CertificateName, extraSANs []string, expirationSeconds int) error {
+	isWorker, err := snaputil.IsWorker(snap)
+	if err != nil {
+		return fmt.Errorf("failed to check if node is a worker: %w", err)
+	}
+
+	if !isWorker {
+		if _, err := api.RefreshControlPlaneCertificates(ctx, s, snap, signers, certificates, extraSANs, expirationSeconds); err != nil {
+			return fmt.Errorf("failed to refresh control plane certificates: %w", err)
+		}
+		return nil
+	}
+
+	seedBigInt, err := rand.Int(rand.Reader, big.NewInt(math.MaxInt))
+	if err != nil {
+		return fmt.Errorf("failed to generate seed: %w", err)
+	}
+	if _, err := api.RefreshWorkerCertificates(ctx, s, snap, certificates, extraSANs, int(seedBigInt.Int64()), expirationSeconds); err != nil {
+		return fmt.Errorf("failed to refresh worker certificates: %w", err)
+	}
+	return nil
+}
+
+// applyCARotationPhase writes the CAs of the cluster configuration on the node and renews the given certificates.
+// This is used to apply the current phase of a CA rotation on the node. Services are not restarted.
+func applyCARotationPhase(ctx context.Context, s state.State, snap snap.Snap, signers *csrsigning.SignerCache, certificates []apiv1.CertificateName, extraSANs []string, expirationSeconds int) error {
+	isWorker, err := snaputil.IsWorker(snap)
+	if err != nil {
+		return fmt.Errorf("failed to check if node is a worker: %w", err)
+	}
+
+	if !isWorker {
+		config, err := databaseutil.GetClusterConfig(ctx, s)
+		if err != nil {
+			return fmt.Errorf("failed to get cluster configuration: %w", err)
+		}
+		// NOTE: The control plane certificates are signed with the CAs read from disk, so the CAs of the current
+		// phase are written first.
+		if _, err := setup.EnsureControlPlaneCAs(snap,
+			config.Certificates.GetCACert(), config.Certificates.GetCAKey(),
+			config.Certificates.GetClientCACert(), config.Certificates.GetClientCAKey(),
+		); err != nil {
+			return fmt.Errorf("failed to write CA certificates: %w", err)
+		}
+	}
+
+	return refreshNodeCertificates(ctx, s, snap, signers, certificates, extraSANs, expirationSeconds)
+}
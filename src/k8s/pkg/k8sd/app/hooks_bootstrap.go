@@ -16,6 +16,7 @@ import (
 
 	apiv1 "github.com/canonical/k8s-snap-api/api/v1"
 	"github.com/canonical/k8s/pkg/k8sd/database"
+	"github.com/canonical/k8s/pkg/k8sd/features"
 	"github.com/canonical/k8s/pkg/k8sd/pki"
 	"github.com/canonical/k8s/pkg/k8sd/setup"
 	"github.com/canonical/k8s/pkg/k8sd/types"
@@ -218,6 +219,7 @@ func (a *App) onBootstrapWorkerNode(ctx context.Context, s state.State, encodedT
 	// - Certificates.K8sdPublicKey: used to verify the signature of the k8sd-config configmap.
 	// - Certificates.CACert: kubernetes CA certificate.
 	// - Certificates.ClientCACert: kubernetes client CA certificate.
+	// - Certificates.KeyAlgorithm: algorithm of the private keys of certificate signing requests.
 	//
 	// TODO(neoaggelos): We should be explicit here and try to avoid having worker nodes use
 	// or set other cluster configuration keys by accident.
@@ -236,6 +238,10 @@ func (a *App) onBootstrapWorkerNode(ctx context.Context, s state.State, encodedT
 		},
 		Annotations: response.Annotations,
 	}
+	if keyAlgorithm, ok := cfg.Annotations.Get(types.AnnotationCertificatesKeyAlgorithm); ok {
+		cfg.Certificates.KeyAlgorithm = utils.Pointer(keyAlgorithm)
+		delete(cfg.Annotations, types.AnnotationCertificatesKeyAlgorithm)
+	}
 
 	serviceConfigs := types.K8sServiceConfigs{
 		ExtraNodeKubeletArgs:   joinConfig.ExtraNodeKubeletArgs,
@@ -248,7 +254,7 @@ func (a *App) onBootstrapWorkerNode(ctx context.Context, s state.State, encodedT
 	}
 
 	if err := s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
-		if _, err := database.SetClusterConfig(ctx, tx, cfg); err != nil {
+		if _, err := database.SetClusterConfig(ctx, tx, cfg, "k8sd/bootstrap"); err != nil {
 			return fmt.Errorf("failed to write cluster configuration: %w", err)
 		}
 		return nil
@@ -260,6 +266,8 @@ func (a *App) onBootstrapWorkerNode(ctx context.Context, s state.State, encodedT
 	if err := setup.Containerd(snap, joinConfig.ExtraNodeContainerdConfig, joinConfig.ExtraNodeContainerdArgs); err != nil {
 		return fmt.Errorf("failed to configure containerd: %w", err)
 	}
+	// only use node IPs of the IP families of the cluster, e.g. only the IPv6 address on IPv6-only clusters
+	nodeIPs = utils.NodeIPsForCIDRs(nodeIPs, response.PodCIDR)
 	if err := setup.KubeletWorker(snap, s.Name(), nodeIPs, response.ClusterDNS, response.ClusterDomain, response.CloudProvider, joinConfig.ExtraNodeKubeletArgs); err != nil {
 		return fmt.Errorf("failed to configure kubelet: %w", err)
 	}
@@ -317,6 +325,8 @@ func (a *App) onBootstrapControlPlane(ctx context.Context, s state.State, bootst
 	if err != nil {
 		return fmt.Errorf("failed to get local node IPs for kubelet: %w", err)
 	}
+	// only use node IPs of the IP families of the cluster, e.g. only the IPv6 address on IPv6-only clusters
+	nodeIPs = utils.NodeIPsForCIDRs(nodeIPs, cfg.Network.GetPodCIDR())
 
 	var localhostAddress string
 	if nodeIP.To4() == nil {
@@ -348,6 +358,7 @@ func (a *App) onBootstrapControlPlane(ctx context.Context, s state.State, bootst
 			NotBefore:         notBefore,
 			NotAfter:          notBefore.AddDate(20, 0, 0),
 			AllowSelfSignedCA: true,
+			KeyAlgorithm:      cfg.Certificates.GetKeyAlgorithm(),
 		})
 		if err := certificates.CompleteCertificates(); err != nil {
 			return fmt.Errorf("failed to initialize k8s-dqlite certificates: %w", err)
@@ -358,6 +369,25 @@ func (a *App) onBootstrapControlPlane(ctx context.Context, s state.State, bootst
 
 		cfg.Datastore.K8sDqliteCert = utils.Pointer(certificates.K8sDqliteCert)
 		cfg.Datastore.K8sDqliteKey = utils.Pointer(certificates.K8sDqliteKey)
+	case "etcd":
+		// NOTE: Default certificate expiration is set to 20 years.
+		certificates := pki.NewEtcdPKI(pki.EtcdPKIOpts{
+			Hostname:          s.Name(),
+			IPSANs:            []net.IP{nodeIP},
+			NotBefore:         notBefore,
+			NotAfter:          notBefore.AddDate(20, 0, 0),
+			AllowSelfSignedCA: true,
+			KeyAlgorithm:      cfg.Certificates.GetKeyAlgorithm(),
+		})
+		if err := certificates.CompleteCertificates(); err != nil {
+			return fmt.Errorf("failed to initialize etcd certificates: %w", err)
+		}
+		if _, err := setup.EnsureEtcdPKI(snap, certificates); err != nil {
+			return fmt.Errorf("failed to write etcd certificates: %w", err)
+		}
+
+		cfg.Datastore.EtcdCACert = utils.Pointer(certificates.CACert)
+		cfg.Datastore.EtcdCAKey = utils.Pointer(certificates.CAKey)
 	case "external":
 		certificates := &pki.ExternalDatastorePKI{
 			DatastoreCACert:     cfg.Datastore.GetExternalCACert(),
@@ -385,6 +415,7 @@ func (a *App) onBootstrapControlPlane(ctx context.Context, s state.State, bootst
 		NotAfter:                  notBefore.AddDate(20, 0, 0),
 		AllowSelfSignedCA:         true,
 		IncludeMachineAddressSANs: true,
+		KeyAlgorithm:              cfg.Certificates.GetKeyAlgorithm(),
 	})
 
 	certificates.CACert = bootstrapConfig.GetCACert()
@@ -461,6 +492,10 @@ func (a *App) onBootstrapControlPlane(ctx context.Context, s state.State, bootst
 		if err := setup.K8sDqlite(snap, address, nil, bootstrapConfig.ExtraNodeK8sDqliteArgs); err != nil {
 			return fmt.Errorf("failed to configure k8s-dqlite: %w", err)
 		}
+	case "etcd":
+		if err := setup.Etcd(snap, s.Name(), nodeIP, cfg.Datastore.GetEtcdPort(), cfg.Datastore.GetEtcdPeerPort(), ""); err != nil {
+			return fmt.Errorf("failed to configure etcd: %w", err)
+		}
 	case "external":
 	default:
 		return fmt.Errorf("unsupported datastore %s, must be one of %v", cfg.Datastore.GetType(), setup.SupportedDatastores)
@@ -482,7 +517,7 @@ func (a *App) onBootstrapControlPlane(ctx context.Context, s state.State, bootst
 	if err := setup.KubeScheduler(snap, bootstrapConfig.ExtraNodeKubeSchedulerArgs); err != nil {
 		return fmt.Errorf("failed to configure kube-scheduler: %w", err)
 	}
-	if err := setup.KubeAPIServer(snap, cfg.APIServer.GetSecurePort(), nodeIP, cfg.Network.GetServiceCIDR(), s.Address().Path("1.0", "kubernetes", "auth", "webhook").String(), true, cfg.Datastore, cfg.APIServer.GetAuthorizationMode(), bootstrapConfig.ExtraNodeKubeAPIServerArgs); err != nil {
+	if err := setup.KubeAPIServer(snap, cfg.APIServer.GetSecurePort(), utils.NodeIPsForCIDRs(nodeIPs, cfg.Network.GetServiceCIDR())[0], cfg.Network.GetServiceCIDR(), s.Address().Path("1.0", "kubernetes", "auth", "webhook").String(), true, cfg.Datastore, cfg.APIServer.GetAuthorizationMode(), cfg.Authentication, bootstrapConfig.ExtraNodeKubeAPIServerArgs); err != nil {
 		return fmt.Errorf("failed to configure kube-apiserver: %w", err)
 	}
 
@@ -492,7 +527,7 @@ func (a *App) onBootstrapControlPlane(ctx context.Context, s state.State, bootst
 
 	// Write cluster configuration to dqlite
 	if err := s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
-		if _, err := database.SetClusterConfig(ctx, tx, cfg); err != nil {
+		if _, err := database.SetClusterConfig(ctx, tx, cfg, "k8sd/bootstrap"); err != nil {
 			return fmt.Errorf("failed to write cluster configuration: %w", err)
 		}
 		return nil
@@ -523,15 +558,13 @@ func (a *App) onBootstrapControlPlane(ctx context.Context, s state.State, bootst
 	}
 	log.Info("API server is ready - notify controllers")
 
-	a.NotifyFeatureController(
-		cfg.Network.GetEnabled(),
-		cfg.Gateway.GetEnabled(),
-		cfg.Ingress.GetEnabled(),
-		cfg.LoadBalancer.GetEnabled(),
-		cfg.LocalStorage.GetEnabled(),
-		cfg.MetricsServer.GetEnabled(),
-		cfg.DNS.GetEnabled(),
-	)
+	var enabled []types.FeatureName
+	for _, name := range features.Features() {
+		if features.Enabled(cfg, name) {
+			enabled = append(enabled, name)
+		}
+	}
+	a.NotifyFeatures(enabled...)
 	a.NotifyUpdateNodeConfigController()
 	return nil
 }
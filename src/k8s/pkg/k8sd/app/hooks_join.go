@@ -10,6 +10,7 @@ import (
 	"github.com/canonical/k8s/pkg/client/kubernetes"
 	upgradesv1alpha "github.com/canonical/k8s/pkg/k8sd/crds/upgrades/v1alpha"
 	databaseutil "github.com/canonical/k8s/pkg/k8sd/database/util"
+	"github.com/canonical/k8s/pkg/k8sd/datastore"
 	"github.com/canonical/k8s/pkg/k8sd/pki"
 	"github.com/canonical/k8s/pkg/k8sd/setup"
 	"github.com/canonical/k8s/pkg/k8sd/types"
@@ -60,6 +61,8 @@ func (a *App) onPostJoin(ctx context.Context, s state.State, initConfig map[stri
 	if err != nil {
 		return fmt.Errorf("failed to get local node IPs for kubelet: %w", err)
 	}
+	// only use node IPs of the IP families of the cluster, e.g. only the IPv6 address on IPv6-only clusters
+	nodeIPs = utils.NodeIPsForCIDRs(nodeIPs, cfg.Network.GetPodCIDR())
 
 	var localhostAddress string
 	if nodeIP.To4() == nil {
@@ -83,10 +86,11 @@ func (a *App) onPostJoin(ctx context.Context, s state.State, initConfig map[stri
 	case "k8s-dqlite":
 		// NOTE: Default certificate expiration is set to 20 years.
 		certificates := pki.NewK8sDqlitePKI(pki.K8sDqlitePKIOpts{
-			Hostname:  s.Name(),
-			IPSANs:    []net.IP{net.ParseIP("127.0.0.1"), net.ParseIP("::1")},
-			NotBefore: notBefore,
-			NotAfter:  notBefore.AddDate(20, 0, 0),
+			Hostname:     s.Name(),
+			IPSANs:       []net.IP{net.ParseIP("127.0.0.1"), net.ParseIP("::1")},
+			NotBefore:    notBefore,
+			NotAfter:     notBefore.AddDate(20, 0, 0),
+			KeyAlgorithm: cfg.Certificates.GetKeyAlgorithm(),
 		})
 		certificates.K8sDqliteCert = cfg.Datastore.GetK8sDqliteCert()
 		certificates.K8sDqliteKey = cfg.Datastore.GetK8sDqliteKey()
@@ -96,6 +100,23 @@ func (a *App) onPostJoin(ctx context.Context, s state.State, initConfig map[stri
 		if _, err := setup.EnsureK8sDqlitePKI(snap, certificates); err != nil {
 			return fmt.Errorf("failed to write k8s-dqlite certificates: %w", err)
 		}
+	case "etcd":
+		// NOTE: Default certificate expiration is set to 20 years.
+		certificates := pki.NewEtcdPKI(pki.EtcdPKIOpts{
+			Hostname:     s.Name(),
+			IPSANs:       []net.IP{nodeIP},
+			NotBefore:    notBefore,
+			NotAfter:     notBefore.AddDate(20, 0, 0),
+			KeyAlgorithm: cfg.Certificates.GetKeyAlgorithm(),
+		})
+		certificates.CACert = cfg.Datastore.GetEtcdCACert()
+		certificates.CAKey = cfg.Datastore.GetEtcdCAKey()
+		if err := certificates.CompleteCertificates(); err != nil {
+			return fmt.Errorf("failed to initialize etcd certificates: %w", err)
+		}
+		if _, err := setup.EnsureEtcdPKI(snap, certificates); err != nil {
+			return fmt.Errorf("failed to write etcd certificates: %w", err)
+		}
 	case "external":
 		certificates := &pki.ExternalDatastorePKI{
 			DatastoreCACert:     cfg.Datastore.GetExternalCACert(),
@@ -122,6 +143,7 @@ func (a *App) onPostJoin(ctx context.Context, s state.State, initConfig map[stri
 		NotBefore:                 notBefore,
 		NotAfter:                  notBefore.AddDate(20, 0, 0),
 		IncludeMachineAddressSANs: true,
+		KeyAlgorithm:              cfg.Certificates.GetKeyAlgorithm(),
 	})
 
 	// load shared cluster certificates
@@ -208,6 +230,37 @@ func (a *App) onPostJoin(ctx context.Context, s state.State, initConfig map[stri
 		if err := setup.K8sDqlite(snap, address, cluster, joinConfig.ExtraNodeK8sDqliteArgs); err != nil {
 			return fmt.Errorf("failed to configure k8s-dqlite with address=%s cluster=%v: %w", address, cluster, err)
 		}
+	case "etcd":
+		leader, err := s.Leader()
+		if err != nil {
+			return fmt.Errorf("failed to get dqlite leader: %w", err)
+		}
+		members, err := leader.GetClusterMembers(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get microcluster members: %w", err)
+		}
+		var endpoints []string
+		for _, member := range members {
+			if member.Name == s.Name() {
+				continue
+			}
+			endpoints = append(endpoints, fmt.Sprintf("https://%s", net.JoinHostPort(member.Address.Addr().String(), fmt.Sprintf("%d", cfg.Datastore.GetEtcdPort()))))
+		}
+
+		client, err := datastore.NewEtcdClient(endpoints, snap.EtcdPKIDir())
+		if err != nil {
+			return fmt.Errorf("failed to create etcd client: %w", err)
+		}
+		defer client.Close()
+
+		peerURL := setup.EtcdPeerURL(nodeIP, cfg.Datastore.GetEtcdPeerPort())
+		initialCluster, err := datastore.AddEtcdMember(ctx, client, s.Name(), peerURL)
+		if err != nil {
+			return fmt.Errorf("failed to add etcd member with peer URL %s: %w", peerURL, err)
+		}
+		if err := setup.Etcd(snap, s.Name(), nodeIP, cfg.Datastore.GetEtcdPort(), cfg.Datastore.GetEtcdPeerPort(), initialCluster); err != nil {
+			return fmt.Errorf("failed to configure etcd with initial cluster %s: %w", initialCluster, err)
+		}
 	case "external":
 	default:
 		return fmt.Errorf("unsupported datastore %s, must be one of %v", cfg.Datastore.GetType(), setup.SupportedDatastores)
@@ -229,7 +282,7 @@ func (a *App) onPostJoin(ctx context.Context, s state.State, initConfig map[stri
 	if err := setup.KubeScheduler(snap, joinConfig.ExtraNodeKubeSchedulerArgs); err != nil {
 		return fmt.Errorf("failed to configure kube-scheduler: %w", err)
 	}
-	if err := setup.KubeAPIServer(snap, cfg.APIServer.GetSecurePort(), nodeIP, cfg.Network.GetServiceCIDR(), s.Address().Path("1.0", "kubernetes", "auth", "webhook").String(), true, cfg.Datastore, cfg.APIServer.GetAuthorizationMode(), joinConfig.ExtraNodeKubeAPIServerArgs); err != nil {
+	if err := setup.KubeAPIServer(snap, cfg.APIServer.GetSecurePort(), utils.NodeIPsForCIDRs(nodeIPs, cfg.Network.GetServiceCIDR())[0], cfg.Network.GetServiceCIDR(), s.Address().Path("1.0", "kubernetes", "auth", "webhook").String(), true, cfg.Datastore, cfg.APIServer.GetAuthorizationMode(), cfg.Authentication, joinConfig.ExtraNodeKubeAPIServerArgs); err != nil {
 		return fmt.Errorf("failed to configure kube-apiserver: %w", err)
 	}
 
@@ -253,6 +306,14 @@ func (a *App) onPostJoin(ctx context.Context, s state.State, initConfig map[stri
 		return fmt.Errorf("failed after retry: %w", err)
 	}
 
+	if cfg.Datastore.GetType() == "etcd" {
+		// The new member joins as a learner and can only be promoted once it has caught up with the leader.
+		log.Info("Promoting etcd member to voter")
+		if err := promoteEtcdMember(ctx, snap, cfg.Datastore, nodeIP); err != nil {
+			return fmt.Errorf("failed to promote etcd member: %w", err)
+		}
+	}
+
 	// Wait until Kube-API server is ready
 	if err := waitApiServerReady(ctx, snap); err != nil {
 		return fmt.Errorf("failed to wait for kube-apiserver to become ready: %w", err)
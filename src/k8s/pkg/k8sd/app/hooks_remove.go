@@ -9,7 +9,9 @@ import (
 	"os"
 
 	apiv1_annotations "github.com/canonical/k8s-snap-api/api/v1/annotations"
+	"github.com/canonical/k8s/pkg/k8sd/database"
 	databaseutil "github.com/canonical/k8s/pkg/k8sd/database/util"
+	"github.com/canonical/k8s/pkg/k8sd/datastore"
 	"github.com/canonical/k8s/pkg/k8sd/pki"
 	"github.com/canonical/k8s/pkg/k8sd/setup"
 	"github.com/canonical/k8s/pkg/log"
@@ -92,6 +94,25 @@ func (a *App) onPreRemove(ctx context.Context, s state.State, force bool) (rerr
 				log.Error(err, "Failed to create k8s-dqlite client: %w")
 			}
 
+		case "etcd":
+			client, err := datastore.NewEtcdClient([]string{fmt.Sprintf("https://127.0.0.1:%d", cfg.Datastore.GetEtcdPort())}, snap.EtcdPKIDir())
+			if err == nil {
+				log.Info("Removing node from etcd cluster")
+				peerURL := setup.EtcdPeerURL(net.ParseIP(s.Address().Hostname()), cfg.Datastore.GetEtcdPeerPort())
+				if err := datastore.RemoveEtcdMember(ctx, client, peerURL); err != nil {
+					// Removing the member might fail (e.g. if it is the only one in the cluster).
+					// We still want to continue with the file cleanup, hence we only log the error.
+					log.Error(err, "Failed to remove node from etcd cluster")
+				}
+				client.Close()
+			} else {
+				log.Error(err, "Failed to create etcd client")
+			}
+
+			log.Info("Cleaning up etcd certificates")
+			if _, err := setup.EnsureEtcdPKI(snap, &pki.EtcdPKI{}); err != nil {
+				log.Error(err, "Failed to cleanup etcd certificates")
+			}
 		case "external":
 			log.Info("Cleaning up external datastore certificates")
 			if _, err := setup.EnsureExtDatastorePKI(snap, &pki.ExternalDatastorePKI{}); err != nil {
@@ -103,10 +124,28 @@ func (a *App) onPreRemove(ctx context.Context, s state.State, force bool) (rerr
 		log.Error(err, "Failed to retrieve cluster config")
 	}
 
+	log.Info("Removing certificate rotation status")
+	if err := s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		return database.DeleteCertificateRotation(ctx, tx, s.Name())
+	}); err != nil {
+		log.Error(err, "Failed to remove certificate rotation status")
+	}
+
+	log.Info("Removing CA rotation progress")
+	if err := s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		return database.DeleteCARotationNode(ctx, tx, s.Name())
+	}); err != nil {
+		log.Error(err, "Failed to remove CA rotation progress")
+	}
+
 	log.Info("Cleaning up k8s-dqlite directory")
 	if err := os.RemoveAll(snap.K8sDqliteStateDir()); err != nil {
 		log.Error(err, "failed to cleanup k8s-dqlite state directory")
 	}
+	log.Info("Cleaning up etcd directory")
+	if err := os.RemoveAll(snap.EtcdStateDir()); err != nil {
+		log.Error(err, "failed to cleanup etcd state directory")
+	}
 	for _, dir := range []string{snap.ServiceArgumentsDir()} {
 		log.WithValues("directory", dir).Info("Cleaning up config files", dir)
 		if err := os.RemoveAll(dir); err != nil {
@@ -7,8 +7,12 @@ import (
 	"fmt"
 	"time"
 
+	apiv1 "github.com/canonical/k8s-snap-api/api/v1"
 	"github.com/canonical/k8s/pkg/k8sd/database"
 	databaseutil "github.com/canonical/k8s/pkg/k8sd/database/util"
+	"github.com/canonical/k8s/pkg/k8sd/features"
+	"github.com/canonical/k8s/pkg/k8sd/features/cilium"
+	metrics_server "github.com/canonical/k8s/pkg/k8sd/features/metrics-server"
 	"github.com/canonical/k8s/pkg/k8sd/types"
 	"github.com/canonical/k8s/pkg/log"
 	"github.com/canonical/k8s/pkg/utils"
@@ -52,11 +56,238 @@ func (a *App) onStart(ctx context.Context, s state.State) error {
 		})
 	}
 
+	// start backup controller
+	if a.backupController != nil {
+		go a.backupController.Run(ctx, func(ctx context.Context) (bool, error) {
+			return isDatabaseLeader(ctx, s)
+		})
+	}
+
+	// start auth token sweeper controller
+	if a.authTokenSweeperController != nil {
+		go a.authTokenSweeperController.Run(
+			ctx,
+			func(ctx context.Context) (bool, error) {
+				return isDatabaseLeader(ctx, s)
+			},
+			func(ctx context.Context, now time.Time) (int64, error) {
+				var n int64
+				if err := s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+					var err error
+					n, err = database.DeleteExpiredTokens(ctx, tx, now)
+					return err
+				}); err != nil {
+					return 0, fmt.Errorf("database transaction to delete expired auth tokens failed: %w", err)
+				}
+				return n, nil
+			},
+		)
+	}
+
+	// start prometheus CRD controller
+	if a.prometheusCRDController != nil {
+		go a.prometheusCRDController.Run(
+			ctx,
+			func(ctx context.Context) (bool, error) {
+				return metrics_server.PrometheusRuleCRDInstalled(ctx, a.snap)
+			},
+			func() { a.NotifyFeatures(features.MetricsServer) },
+		)
+	}
+
+	// start certificate rotation controller
+	if a.certificateRotationController != nil {
+		go a.certificateRotationController.Run(
+			ctx,
+			func(ctx context.Context) (types.ClusterConfig, error) {
+				return databaseutil.GetClusterConfig(ctx, s)
+			},
+			func(ctx context.Context, rotation types.CertificateRotation, lease time.Duration) (string, error) {
+				rotation.Node = s.Name()
+				var holder string
+				if err := s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+					var err error
+					holder, err = database.AcquireCertificateRotation(ctx, tx, rotation, lease)
+					return err
+				}); err != nil {
+					return "", fmt.Errorf("database transaction to acquire certificate rotation failed: %w", err)
+				}
+				return holder, nil
+			},
+			func(ctx context.Context, rotation types.CertificateRotation) error {
+				rotation.Node = s.Name()
+				if err := s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+					return database.SetCertificateRotation(ctx, tx, rotation)
+				}); err != nil {
+					return fmt.Errorf("database transaction to set certificate rotation failed: %w", err)
+				}
+				return nil
+			},
+			func(ctx context.Context, certificates []apiv1.CertificateName, extraSANs []string, expirationSeconds int) error {
+				return refreshNodeCertificates(ctx, s, a.snap, a.signers, certificates, extraSANs, expirationSeconds)
+			},
+		)
+	}
+
+	// start CA rotation controller
+	if a.caRotationController != nil {
+		go a.caRotationController.Run(
+			ctx,
+			func(ctx context.Context) (*types.CARotation, *types.CARotationNode, error) {
+				var (
+					rotation *types.CARotation
+					node     *types.CARotationNode
+				)
+				if err := s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+					var err error
+					if rotation, err = database.GetCARotation(ctx, tx); err != nil || rotation == nil {
+						return err
+					}
+					nodes, err := database.ListCARotationNodes(ctx, tx, rotation.ID)
+					if err != nil {
+						return err
+					}
+					for _, n := range nodes {
+						if n.Node == s.Name() {
+							node = &n
+						}
+					}
+					return nil
+				}); err != nil {
+					return nil, nil, fmt.Errorf("database transaction to get CA rotation failed: %w", err)
+				}
+				return rotation, node, nil
+			},
+			func(ctx context.Context, rotation types.CertificateRotation, lease time.Duration) (string, error) {
+				rotation.Node = s.Name()
+				var holder string
+				if err := s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+					var err error
+					holder, err = database.AcquireCertificateRotation(ctx, tx, rotation, lease)
+					return err
+				}); err != nil {
+					return "", fmt.Errorf("database transaction to acquire certificate rotation failed: %w", err)
+				}
+				return holder, nil
+			},
+			func(ctx context.Context, rotation types.CertificateRotation) error {
+				rotation.Node = s.Name()
+				if err := s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+					return database.SetCertificateRotation(ctx, tx, rotation)
+				}); err != nil {
+					return fmt.Errorf("database transaction to set certificate rotation failed: %w", err)
+				}
+				return nil
+			},
+			func(ctx context.Context, certificates []apiv1.CertificateName, extraSANs []string, expirationSeconds int) error {
+				return applyCARotationPhase(ctx, s, a.snap, a.signers, certificates, extraSANs, expirationSeconds)
+			},
+			func(ctx context.Context, rotationID int64, node types.CARotationNode) error {
+				node.Node = s.Name()
+				if err := s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+					return database.SetCARotationNode(ctx, tx, rotationID, node)
+				}); err != nil {
+					return fmt.Errorf("database transaction to set CA rotation progress failed: %w", err)
+				}
+				return nil
+			},
+			func(ctx context.Context) error {
+				if err := s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+					_, err := database.AdvanceCARotation(ctx, tx, "k8sd/ca-rotation")
+					return err
+				}); err != nil {
+					return fmt.Errorf("database transaction to advance CA rotation failed: %w", err)
+				}
+				return nil
+			},
+		)
+	}
+
 	// start update node config controller
 	if a.updateNodeConfigController != nil {
-		go a.updateNodeConfigController.Run(ctx, func(ctx context.Context) (types.ClusterConfig, error) {
-			return databaseutil.GetClusterConfig(ctx, s)
-		})
+		go a.updateNodeConfigController.Run(
+			ctx,
+			func(ctx context.Context) (types.ClusterConfig, error) {
+				return databaseutil.GetClusterConfig(ctx, s)
+			},
+			func(ctx context.Context) (bool, error) {
+				var values map[string]map[string]any
+				if err := s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+					var err error
+					if values, err = database.GetFeatureValues(ctx, tx, features.Network); err != nil {
+						return fmt.Errorf("failed to get network feature values from db: %w", err)
+					}
+					return nil
+				}); err != nil {
+					return false, fmt.Errorf("database transaction to get network feature values failed: %w", err)
+				}
+
+				// kube-proxy is replaced once the network feature applied the kube-proxy replacement and the network is ready
+				if replaced, _ := values[cilium.ChartCilium.Name]["kubeProxyReplacement"].(bool); !replaced {
+					return false, nil
+				}
+				if err := features.StatusChecks.CheckNetwork(ctx, a.snap); err != nil {
+					log.FromContext(ctx).V(1).Info("Network is not ready for the kube-proxy replacement", "error", err)
+					return false, nil
+				}
+				return true, nil
+			},
+		)
+	}
+
+	// start network migration controller
+	if a.networkMigrationController != nil {
+		go a.networkMigrationController.Run(
+			ctx,
+			func(ctx context.Context) (bool, error) {
+				return isDatabaseLeader(ctx, s)
+			},
+			func(ctx context.Context) (types.ClusterConfig, error) {
+				return databaseutil.GetClusterConfig(ctx, s)
+			},
+			func(ctx context.Context, cfg types.ClusterConfig) (types.FeatureStatus, bool, error) {
+				return features.MigrateNetwork(ctx, a.snap, s, cfg.APIServer, cfg.Network, cfg.Annotations)
+			},
+			func(ctx context.Context, status types.FeatureStatus) error {
+				if err := s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+					statuses, err := database.GetFeatureStatuses(ctx, tx)
+					if err != nil {
+						return fmt.Errorf("failed to get feature statuses from db: %w", err)
+					}
+					// the network feature reports the version of the network provider
+					status.Version = statuses[features.Network].Version
+					status.UpdatedAt = time.Now()
+					if err := database.SetFeatureStatus(ctx, tx, features.Network, status); err != nil {
+						return fmt.Errorf("failed to set feature status in db for %q: %w", features.Network, err)
+					}
+					return nil
+				}); err != nil {
+					return fmt.Errorf("database transaction to set network status failed: %w", err)
+				}
+				return nil
+			},
+			func(ctx context.Context, cfg types.ClusterConfig) error {
+				if err := s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+					if _, err := database.SetClusterConfig(ctx, tx, types.ClusterConfig{
+						Network: types.Network{
+							PodCIDR:          utils.Pointer(cfg.Network.GetMigrationPodCIDR()),
+							MigrationPodCIDR: utils.Pointer(""),
+						},
+					}, "k8sd/network-migration-controller"); err != nil {
+						return fmt.Errorf("failed to update cluster configuration for pod CIDR %s: %w", cfg.Network.GetMigrationPodCIDR(), err)
+					}
+					return nil
+				}); err != nil {
+					return fmt.Errorf("database transaction to update cluster configuration failed: %w", err)
+				}
+
+				// the network feature finishes the migration, and the nodes pick up the new pod CIDR
+				a.NotifyFeatures(features.Network)
+				a.NotifyUpdateNodeConfigController()
+
+				return nil
+			},
+		)
 	}
 
 	// start feature controller
@@ -69,19 +300,17 @@ func (a *App) onStart(ctx context.Context, s state.State) error {
 			func() state.State {
 				return s
 			},
-			func(ctx context.Context, dnsIP string) error {
+			func(ctx context.Context, update types.ClusterConfig) error {
 				if err := s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
-					if _, err := database.SetClusterConfig(ctx, tx, types.ClusterConfig{
-						Kubelet: types.Kubelet{ClusterDNS: utils.Pointer(dnsIP)},
-					}); err != nil {
-						return fmt.Errorf("failed to update cluster configuration for dns=%s: %w", dnsIP, err)
+					if _, err := database.SetClusterConfig(ctx, tx, update, "k8sd/feature-controller"); err != nil {
+						return fmt.Errorf("failed to update cluster configuration: %w", err)
 					}
 					return nil
 				}); err != nil {
 					return fmt.Errorf("database transaction to update cluster configuration failed: %w", err)
 				}
 
-				// DNS IP has changed, notify node config controller
+				// e.g. the DNS IP has changed, notify node config controller
 				a.NotifyUpdateNodeConfigController()
 
 				return nil
@@ -101,6 +330,41 @@ func (a *App) onStart(ctx context.Context, s state.State) error {
 				}
 				return nil
 			},
+			func(ctx context.Context) (map[types.FeatureName]types.FeatureStatus, error) {
+				var statuses map[types.FeatureName]types.FeatureStatus
+				if err := s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+					var err error
+					if statuses, err = database.GetFeatureStatuses(ctx, tx); err != nil {
+						return fmt.Errorf("failed to get feature statuses from db: %w", err)
+					}
+					return nil
+				}); err != nil {
+					return nil, fmt.Errorf("database transaction to get feature statuses failed: %w", err)
+				}
+				return statuses, nil
+			},
+			func(ctx context.Context, name types.FeatureName, reconcile types.FeatureReconcile) error {
+				if err := s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+					if err := database.AddFeatureReconcile(ctx, tx, name, reconcile); err != nil {
+						return fmt.Errorf("failed to add feature reconcile in db for %q: %w", name, err)
+					}
+					switch reconcile.Result {
+					case types.FeatureReconcileEnabled, types.FeatureReconcileDisabled:
+						if err := database.SetFeatureValues(ctx, tx, name, reconcile.Values); err != nil {
+							return fmt.Errorf("failed to set feature values in db for %q: %w", name, err)
+						}
+					}
+					return nil
+				}); err != nil {
+					return fmt.Errorf("database transaction to add feature reconcile failed: %w", err)
+				}
+
+				// the kube-proxy state of the nodes depends on the applied network feature
+				if name == features.Network {
+					a.NotifyUpdateNodeConfigController()
+				}
+				return nil
+			},
 		)
 	}
 
@@ -112,6 +376,17 @@ func (a *App) onStart(ctx context.Context, s state.State) error {
 				return databaseutil.GetClusterConfig(ctx, s)
 			},
 			func() state.State { return s },
+			func(ctx context.Context) ([]types.CertificateRotation, error) {
+				var rotations []types.CertificateRotation
+				if err := s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+					var err error
+					rotations, err = database.ListCertificateRotations(ctx, tx)
+					return err
+				}); err != nil {
+					return nil, fmt.Errorf("database transaction to list certificate rotations failed: %w", err)
+				}
+				return rotations, nil
+			},
 		); err != nil {
 			log.FromContext(ctx).Error(err, "Failed to start controller coordinator")
 		}
@@ -0,0 +1,415 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/canonical/k8s/pkg/k8sd/datastore"
+	"github.com/canonical/k8s/pkg/log"
+	"github.com/canonical/k8s/pkg/snap"
+	"github.com/canonical/k8s/pkg/utils"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// namePrefix is the prefix of all backup directory names.
+	namePrefix = "backup-"
+	// preRestorePrefix is the prefix of the backups that Restore takes of the state it replaces.
+	preRestorePrefix = "pre-restore-"
+	// nameTimeFormat is the timestamp format used in backup directory names.
+	nameTimeFormat = "20060102T150405Z"
+
+	metadataFile          = "backup.yaml"
+	k8sDqliteSnapshotFile = "k8s-dqlite.snapshot.gz"
+	k8sdDumpFile          = "k8sd.sql"
+
+	// k8sdPatchFile is executed by microcluster against the k8sd database when k8sd starts, and removed once applied.
+	k8sdPatchFile = "patch.global.sql"
+	// k8sdPatchTimeout is the maximum time to wait for k8sd to apply a restored database.
+	k8sdPatchTimeout = 2 * time.Minute
+)
+
+var (
+	// restoreServices are the services that are stopped while a backup is restored, so that
+	// the datastores are not written to. They are started again in reverse order afterwards.
+	restoreServices = []string{
+		"kube-apiserver",
+		"kube-controller-manager",
+		"kube-scheduler",
+	}
+)
+
+// ErrNotFound is returned when a backup does not exist.
+var ErrNotFound = errors.New("backup not found")
+
+// Backup describes a point-in-time backup of the cluster datastores.
+type Backup struct {
+	// Name is the name of the backup, e.g. "backup-20240101T000000Z".
+	Name string `json:"name" yaml:"name"`
+	// CreatedAt is the time the backup was taken.
+	CreatedAt time.Time `json:"created-at" yaml:"created-at"`
+	// Node is the hostname of the node that took the backup.
+	Node string `json:"node" yaml:"node"`
+	// K8sDqlite is true if the backup contains the k8s-dqlite datastore.
+	K8sDqlite bool `json:"k8s-dqlite" yaml:"k8s-dqlite"`
+	// K8sDqliteMembers is the list of k8s-dqlite cluster members at the time of the backup.
+	K8sDqliteMembers []string `json:"k8s-dqlite-members,omitempty" yaml:"k8s-dqlite-members,omitempty"`
+	// K8sDqliteRevision is the revision of the k8s-dqlite datastore that the backup matches.
+	K8sDqliteRevision int64 `json:"k8s-dqlite-revision,omitempty" yaml:"k8s-dqlite-revision,omitempty"`
+	// K8sDqliteKeys is the number of Kubernetes keys in the backup of the k8s-dqlite datastore.
+	K8sDqliteKeys int64 `json:"k8s-dqlite-keys,omitempty" yaml:"k8s-dqlite-keys,omitempty"`
+	// Path is the directory of the backup. It is not persisted in the backup metadata.
+	Path string `json:"path" yaml:"-"`
+}
+
+// Create takes a backup of the k8sd database and, if present, the k8s-dqlite datastore.
+// The backup is written to a new directory under dir.
+//
+// The datastores are not copied from disk. The k8s-dqlite datastore is read through its etcd API at
+// a single revision and the k8sd database is dumped by k8sd, so that the backup is consistent while
+// the datastores are in use.
+func Create(ctx context.Context, snap snap.Snap, dir string) (Backup, error) {
+	return create(ctx, snap, dir, namePrefix+time.Now().UTC().Format(nameTimeFormat))
+}
+
+func create(ctx context.Context, snap snap.Snap, dir string, name string) (Backup, error) {
+	b := Backup{
+		Name:      name,
+		CreatedAt: time.Now().UTC(),
+		Node:      snap.Hostname(),
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return Backup{}, fmt.Errorf("failed to create backup directory %q: %w", dir, err)
+	}
+
+	// write to a temporary directory first, so that interrupted backups are never listed
+	tmpDir, err := os.MkdirTemp(dir, ".partial-")
+	if err != nil {
+		return Backup{}, fmt.Errorf("failed to create temporary backup directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	if exists, err := utils.FileExists(snap.K8sDqliteStateDir(), "cluster.yaml"); err != nil {
+		return Backup{}, fmt.Errorf("failed to check for k8s-dqlite state: %w", err)
+	} else if exists {
+		client, err := snap.K8sDqliteClient(ctx)
+		if err != nil {
+			return Backup{}, fmt.Errorf("failed to create k8s-dqlite client: %w", err)
+		}
+		members, err := client.ListMembers(ctx)
+		if err != nil {
+			return Backup{}, fmt.Errorf("failed to list k8s-dqlite members: %w", err)
+		}
+		for _, member := range members {
+			b.K8sDqliteMembers = append(b.K8sDqliteMembers, member.Address)
+		}
+
+		result, err := saveK8sDqlite(ctx, snap, filepath.Join(tmpDir, k8sDqliteSnapshotFile))
+		if err != nil {
+			return Backup{}, fmt.Errorf("failed to back up k8s-dqlite datastore: %w", err)
+		}
+		b.K8sDqlite = true
+		b.K8sDqliteRevision = result.Revision
+		b.K8sDqliteKeys = result.Keys
+	}
+
+	client, err := snap.K8sdClient("")
+	if err != nil {
+		return Backup{}, fmt.Errorf("failed to create k8sd client: %w", err)
+	}
+	dump, err := client.DumpDatabase(ctx)
+	if err != nil {
+		return Backup{}, fmt.Errorf("failed to back up k8sd database: %w", err)
+	}
+	if err := utils.WriteFile(filepath.Join(tmpDir, k8sdDumpFile), []byte(dump), 0o600); err != nil {
+		return Backup{}, fmt.Errorf("failed to write k8sd database dump: %w", err)
+	}
+
+	data, err := yaml.Marshal(b)
+	if err != nil {
+		return Backup{}, fmt.Errorf("failed to marshal backup metadata: %w", err)
+	}
+	if err := utils.WriteFile(filepath.Join(tmpDir, metadataFile), data, 0o600); err != nil {
+		return Backup{}, fmt.Errorf("failed to write backup metadata: %w", err)
+	}
+
+	b.Path = filepath.Join(dir, b.Name)
+	if err := os.Rename(tmpDir, b.Path); err != nil {
+		return Backup{}, fmt.Errorf("failed to move backup to %q: %w", b.Path, err)
+	}
+
+	return b, nil
+}
+
+// List returns all backups found in dir, newest first.
+// List returns an empty list if dir does not exist.
+func List(dir string) ([]Backup, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read backup directory %q: %w", dir, err)
+	}
+
+	var backups []Backup
+	for _, entry := range entries {
+		if !entry.IsDir() || !strings.HasPrefix(entry.Name(), namePrefix) {
+			continue
+		}
+		b, err := load(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			log.L().Info("Skipping invalid backup", "name", entry.Name(), "error", err)
+			continue
+		}
+		backups = append(backups, b)
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].CreatedAt.After(backups[j].CreatedAt)
+	})
+	return backups, nil
+}
+
+// Get returns the backup with the given name from dir.
+// Get also returns the backups that Restore takes of the state it replaces, which are not listed.
+// Get returns ErrNotFound if the backup does not exist.
+func Get(dir string, name string) (Backup, error) {
+	if name != filepath.Base(name) || (!strings.HasPrefix(name, namePrefix) && !strings.HasPrefix(name, preRestorePrefix)) {
+		return Backup{}, fmt.Errorf("invalid backup name %q", name)
+	}
+	b, err := load(filepath.Join(dir, name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Backup{}, ErrNotFound
+		}
+		return Backup{}, err
+	}
+	return b, nil
+}
+
+// Prune removes all but the newest keep backups from dir.
+// Prune returns the names of the removed backups.
+func Prune(dir string, keep int) ([]string, error) {
+	if keep < 0 {
+		return nil, fmt.Errorf("invalid number of backups to keep %d", keep)
+	}
+	backups, err := List(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list backups: %w", err)
+	}
+	if len(backups) <= keep {
+		return nil, nil
+	}
+
+	var removed []string
+	for _, b := range backups[keep:] {
+		if err := os.RemoveAll(b.Path); err != nil {
+			return removed, fmt.Errorf("failed to remove backup %q: %w", b.Name, err)
+		}
+		removed = append(removed, b.Name)
+	}
+	return removed, nil
+}
+
+// Restore replaces the k8sd database and, if included in the backup, the k8s-dqlite datastore with the
+// contents of the named backup. Both datastores are replicated, so the restore applies to the whole cluster.
+// The control plane services of the local node are stopped during the restore and started again afterwards.
+//
+// Before the datastores are replaced, their current state is saved as a "pre-restore-<timestamp>" backup in dir.
+// If the restore fails, Restore rolls back to that backup before starting the services again.
+func Restore(ctx context.Context, snap snap.Snap, dir string, name string) error {
+	b, err := Get(dir, name)
+	if err != nil {
+		return fmt.Errorf("failed to load backup %q: %w", name, err)
+	}
+
+	log := log.FromContext(ctx).WithValues("backup", b.Name)
+
+	data, err := os.ReadFile(filepath.Join(b.Path, k8sdDumpFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("backup %q has no k8sd database dump, it was created by an older version and cannot be restored", b.Name)
+		}
+		return fmt.Errorf("failed to read k8sd database dump: %w", err)
+	}
+	client, err := snap.K8sdClient("")
+	if err != nil {
+		return fmt.Errorf("failed to create k8sd client: %w", err)
+	}
+	current, err := client.DumpDatabase(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to dump k8sd database: %w", err)
+	}
+	if err := parseK8sdDump(string(data)).checkSchema(parseK8sdDump(current)); err != nil {
+		return fmt.Errorf("backup %q does not match the k8sd database: %w", b.Name, err)
+	}
+
+	log.Info("Stopping services")
+	if err := snap.StopServices(ctx, restoreServices); err != nil {
+		return errors.Join(fmt.Errorf("failed to stop services: %w", err), startServices(ctx, snap))
+	}
+
+	log.Info("Saving current state")
+	pre, err := create(ctx, snap, dir, preRestorePrefix+time.Now().UTC().Format(nameTimeFormat))
+	if err != nil {
+		return errors.Join(fmt.Errorf("failed to save current state: %w", err), startServices(ctx, snap))
+	}
+
+	if err := restore(ctx, snap, b); err != nil {
+		log.Error(err, "Failed to restore backup, rolling back", "rollback", pre.Name)
+
+		// roll back even if ctx is cancelled, e.g. because the restore timed out
+		rollbackCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 2*k8sdPatchTimeout)
+		defer cancel()
+		if rollbackErr := restore(rollbackCtx, snap, pre); rollbackErr != nil {
+			err = errors.Join(err, fmt.Errorf("failed to roll back to %q: %w", pre.Name, rollbackErr))
+		}
+		return errors.Join(err, startServices(rollbackCtx, snap))
+	}
+
+	return startServices(ctx, snap)
+}
+
+// restore replaces the datastores with the contents of backup b.
+func restore(ctx context.Context, snap snap.Snap, b Backup) error {
+	if b.K8sDqlite {
+		log.FromContext(ctx).Info("Restoring k8s-dqlite datastore", "backup", b.Name)
+		if err := loadK8sDqlite(ctx, snap, filepath.Join(b.Path, k8sDqliteSnapshotFile)); err != nil {
+			return fmt.Errorf("failed to restore k8s-dqlite datastore: %w", err)
+		}
+	}
+
+	log.FromContext(ctx).Info("Restoring k8sd database", "backup", b.Name)
+	if err := loadK8sd(ctx, snap, filepath.Join(b.Path, k8sdDumpFile)); err != nil {
+		return fmt.Errorf("failed to restore k8sd database: %w", err)
+	}
+	return nil
+}
+
+// startServices starts the restoreServices in reverse order.
+func startServices(ctx context.Context, snap snap.Snap) error {
+	for i := len(restoreServices) - 1; i >= 0; i-- {
+		if err := snap.StartServices(ctx, []string{restoreServices[i]}); err != nil {
+			return fmt.Errorf("failed to start service %s: %w", restoreServices[i], err)
+		}
+	}
+	return nil
+}
+
+// newK8sDqliteClient creates an etcd client for the k8s-dqlite datastore of the local node.
+func newK8sDqliteClient(snap snap.Snap) (*clientv3.Client, error) {
+	return datastore.NewClient([]string{fmt.Sprintf("unix://%s", filepath.Join(snap.K8sDqliteStateDir(), "k8s-dqlite.sock"))}, "", "", "")
+}
+
+// saveK8sDqlite writes a snapshot of the k8s-dqlite datastore to path.
+func saveK8sDqlite(ctx context.Context, snap snap.Snap, path string) (datastore.SnapshotResult, error) {
+	client, err := newK8sDqliteClient(snap)
+	if err != nil {
+		return datastore.SnapshotResult{}, fmt.Errorf("failed to create k8s-dqlite client: %w", err)
+	}
+	defer client.Close()
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return datastore.SnapshotResult{}, fmt.Errorf("failed to create %q: %w", path, err)
+	}
+	defer f.Close()
+
+	result, err := datastore.Save(ctx, client, client, f)
+	if err != nil {
+		return datastore.SnapshotResult{}, err
+	}
+	if err := f.Close(); err != nil {
+		return datastore.SnapshotResult{}, fmt.Errorf("failed to write %q: %w", path, err)
+	}
+	return result, nil
+}
+
+// loadK8sDqlite replaces the k8s-dqlite datastore with the snapshot at path.
+func loadK8sDqlite(ctx context.Context, snap snap.Snap, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open %q: %w", path, err)
+	}
+	defer f.Close()
+
+	client, err := newK8sDqliteClient(snap)
+	if err != nil {
+		return fmt.Errorf("failed to create k8s-dqlite client: %w", err)
+	}
+	defer client.Close()
+
+	if _, err := datastore.Load(ctx, client, client, f); err != nil {
+		return err
+	}
+	return nil
+}
+
+// loadK8sd replaces the rows of the k8sd database with the rows of the dump at path.
+// The rows are written to a patch file that microcluster applies in a single transaction when k8sd starts.
+// If k8sd does not apply the patch, the patch is removed and k8sd is restarted with its database unchanged.
+func loadK8sd(ctx context.Context, snap snap.Snap, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %q: %w", path, err)
+	}
+
+	patchPath := filepath.Join(snap.K8sdStateDir(), k8sdPatchFile)
+	if err := utils.WriteFile(patchPath, []byte(parseK8sdDump(string(data)).patch()), 0o600); err != nil {
+		return fmt.Errorf("failed to write %q: %w", patchPath, err)
+	}
+
+	err = snap.RestartServices(ctx, []string{"k8sd"})
+	if err == nil {
+		err = waitForPatch(ctx, patchPath)
+	}
+	if err != nil {
+		if removeErr := os.Remove(patchPath); removeErr != nil && !os.IsNotExist(removeErr) {
+			return errors.Join(err, fmt.Errorf("failed to remove %q: %w", patchPath, removeErr))
+		}
+		return errors.Join(err, snap.RestartServices(context.WithoutCancel(ctx), []string{"k8sd"}))
+	}
+	return nil
+}
+
+// waitForPatch waits until k8sd has applied and removed the patch file at path.
+func waitForPatch(ctx context.Context, path string) error {
+	ctx, cancel := context.WithTimeout(ctx, k8sdPatchTimeout)
+	defer cancel()
+
+	for {
+		if exists, err := utils.FileExists(path); err != nil {
+			return err
+		} else if !exists {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("k8sd did not apply the restored database, check the k8sd logs: %w", ctx.Err())
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+}
+
+func load(path string) (Backup, error) {
+	data, err := os.ReadFile(filepath.Join(path, metadataFile))
+	if err != nil {
+		return Backup{}, err
+	}
+	var b Backup
+	if err := yaml.Unmarshal(data, &b); err != nil {
+		return Backup{}, fmt.Errorf("failed to parse backup metadata: %w", err)
+	}
+	b.Path = path
+	return b, nil
+}
@@ -0,0 +1,256 @@
+package backup_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	k8sdmock "github.com/canonical/k8s/pkg/client/k8sd/mock"
+	"github.com/canonical/k8s/pkg/k8sd/backup"
+	"github.com/canonical/k8s/pkg/snap/mock"
+	. "github.com/onsi/gomega"
+)
+
+// k8sdDump returns a SQL text dump of a k8sd database, as returned by microcluster.
+func k8sdDump(config string, extraSchema string) string {
+	return fmt.Sprintf(`PRAGMA foreign_keys=OFF;
+BEGIN TRANSACTION;
+CREATE TABLE schemas (
+    id INTEGER PRIMARY KEY
+);
+INSERT INTO schemas VALUES(1);
+CREATE TABLE core_cluster_members (
+    name TEXT
+);
+INSERT INTO core_cluster_members VALUES('node1');
+CREATE TABLE cluster_configs (
+    key TEXT PRIMARY KEY,
+    value TEXT%s
+);
+INSERT INTO cluster_configs VALUES('config',replace('%s\n','\n',char(10)));
+CREATE TABLE ca_rotations (
+    id INTEGER PRIMARY KEY AUTOINCREMENT
+);
+INSERT INTO ca_rotations VALUES(1);
+CREATE TABLE ca_rotation_nodes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    rotation_id INTEGER,
+    FOREIGN KEY (rotation_id) REFERENCES ca_rotations(id) ON DELETE CASCADE
+);
+DELETE FROM sqlite_sequence;
+INSERT INTO sqlite_sequence VALUES('ca_rotations',1);
+COMMIT;
+`, extraSchema, config)
+}
+
+// k8sdPatch returns the patch that restores the k8sd tables of k8sdDump(config, "").
+func k8sdPatch(config string) string {
+	return fmt.Sprintf(`DELETE FROM ca_rotation_nodes;
+DELETE FROM ca_rotations;
+DELETE FROM cluster_configs;
+INSERT INTO cluster_configs VALUES('config',replace('%s\n','\n',char(10)));
+INSERT INTO ca_rotations VALUES(1);
+`, config)
+}
+
+// applyPatches simulates k8sd applying the restored database when it starts.
+// Patches for which skip returns true are left in place.
+// applyPatches returns a channel with the contents of the applied patches.
+func applyPatches(ctx context.Context, path string, skip func(string) bool) <-chan string {
+	ch := make(chan string, 2)
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(50 * time.Millisecond):
+			}
+			b, err := os.ReadFile(path)
+			if err != nil || skip(string(b)) {
+				continue
+			}
+			if err := os.Remove(path); err == nil {
+				ch <- string(b)
+			}
+		}
+	}()
+	return ch
+}
+
+func TestBackup(t *testing.T) {
+	g := NewWithT(t)
+
+	dir := t.TempDir()
+	k8sd := &k8sdmock.Mock{DumpDatabaseResponse: k8sdDump("v1", "")}
+	s := &mock.Snap{
+		Mock: mock.Mock{
+			Hostname:          "node1",
+			K8sdStateDir:      filepath.Join(dir, "k8sd"),
+			K8sDqliteStateDir: filepath.Join(dir, "k8s-dqlite"),
+			BackupDir:         filepath.Join(dir, "backups"),
+			K8sdClient:        k8sd,
+		},
+	}
+	g.Expect(os.MkdirAll(s.Mock.K8sdStateDir, 0o700)).To(Succeed())
+	patchPath := filepath.Join(s.Mock.K8sdStateDir, "patch.global.sql")
+
+	ctx := context.Background()
+
+	b1, err := backup.Create(ctx, s, s.BackupDir())
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(b1.Node).To(Equal("node1"))
+	g.Expect(b1.K8sDqlite).To(BeFalse())
+	dump, err := os.ReadFile(filepath.Join(b1.Path, "k8sd.sql"))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(string(dump)).To(Equal(k8sdDump("v1", "")))
+
+	t.Run("Get", func(t *testing.T) {
+		g := NewWithT(t)
+
+		b, err := backup.Get(s.BackupDir(), b1.Name)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(b.Name).To(Equal(b1.Name))
+		g.Expect(b.CreatedAt.Equal(b1.CreatedAt)).To(BeTrue())
+
+		_, err = backup.Get(s.BackupDir(), "backup-missing")
+		g.Expect(err).To(MatchError(backup.ErrNotFound))
+
+		_, err = backup.Get(s.BackupDir(), "../backup-escape")
+		g.Expect(err).To(HaveOccurred())
+	})
+
+	t.Run("Restore", func(t *testing.T) {
+		g := NewWithT(t)
+		s.StopServicesCalledWith, s.StartServicesCalledWith, s.RestartServicesCalledWith = nil, nil, nil
+		k8sd.DumpDatabaseResponse = k8sdDump("v2", "")
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		patches := applyPatches(ctx, patchPath, func(string) bool { return false })
+
+		g.Expect(backup.Restore(ctx, s, s.BackupDir(), b1.Name)).To(Succeed())
+
+		g.Expect(patches).To(Receive(Equal(k8sdPatch("v1"))))
+		g.Expect(patchPath).ToNot(BeAnExistingFile())
+		g.Expect(s.StopServicesCalledWith).To(Equal([][]string{{"kube-apiserver", "kube-controller-manager", "kube-scheduler"}}))
+		g.Expect(s.StartServicesCalledWith).To(Equal([][]string{{"kube-scheduler"}, {"kube-controller-manager"}, {"kube-apiserver"}}))
+		g.Expect(s.RestartServicesCalledWith).To(Equal([][]string{{"k8sd"}}))
+
+		// the replaced state is kept as a backup that is not listed, but can be restored
+		matches, err := filepath.Glob(filepath.Join(s.BackupDir(), "pre-restore-*"))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(matches).To(HaveLen(1))
+		dump, err := os.ReadFile(filepath.Join(matches[0], "k8sd.sql"))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(string(dump)).To(Equal(k8sdDump("v2", "")))
+
+		_, err = backup.Get(s.BackupDir(), filepath.Base(matches[0]))
+		g.Expect(err).ToNot(HaveOccurred())
+		backups, err := backup.List(s.BackupDir())
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(backups).To(HaveLen(1))
+		g.Expect(os.RemoveAll(matches[0])).To(Succeed())
+	})
+
+	t.Run("RestoreRollback", func(t *testing.T) {
+		g := NewWithT(t)
+		s.StopServicesCalledWith, s.StartServicesCalledWith, s.RestartServicesCalledWith = nil, nil, nil
+		k8sd.DumpDatabaseResponse = k8sdDump("v2", "")
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		// k8sd fails to apply the backup, but applies the rollback
+		patches := applyPatches(ctx, patchPath, func(patch string) bool { return patch == k8sdPatch("v1") })
+		restoreCtx, restoreCancel := context.WithTimeout(ctx, time.Second)
+		defer restoreCancel()
+
+		err := backup.Restore(restoreCtx, s, s.BackupDir(), b1.Name)
+		g.Expect(err).To(MatchError(ContainSubstring("k8sd did not apply the restored database")))
+
+		g.Expect(patches).To(Receive(Equal(k8sdPatch("v2"))))
+		g.Expect(patchPath).ToNot(BeAnExistingFile())
+		g.Expect(s.RestartServicesCalledWith).To(Equal([][]string{{"k8sd"}, {"k8sd"}, {"k8sd"}}))
+		g.Expect(s.StartServicesCalledWith).To(Equal([][]string{{"kube-scheduler"}, {"kube-controller-manager"}, {"kube-apiserver"}}))
+
+		matches, err := filepath.Glob(filepath.Join(s.BackupDir(), "pre-restore-*"))
+		g.Expect(err).ToNot(HaveOccurred())
+		for _, match := range matches {
+			g.Expect(os.RemoveAll(match)).To(Succeed())
+		}
+	})
+
+	t.Run("RestoreSchemaMismatch", func(t *testing.T) {
+		g := NewWithT(t)
+		s.StopServicesCalledWith = nil
+		k8sd.DumpDatabaseResponse = k8sdDump("v2", ",\n    extra TEXT")
+
+		err := backup.Restore(ctx, s, s.BackupDir(), b1.Name)
+		g.Expect(err).To(MatchError(ContainSubstring("schema mismatch for cluster_configs")))
+		g.Expect(s.StopServicesCalledWith).To(BeEmpty())
+	})
+
+	t.Run("RestoreOldBackup", func(t *testing.T) {
+		g := NewWithT(t)
+		s.StopServicesCalledWith = nil
+
+		oldDir := filepath.Join(s.BackupDir(), "backup-20200101T000000Z")
+		g.Expect(os.MkdirAll(oldDir, 0o700)).To(Succeed())
+		g.Expect(os.WriteFile(filepath.Join(oldDir, "backup.yaml"), []byte("name: backup-20200101T000000Z\n"), 0o600)).To(Succeed())
+		defer os.RemoveAll(oldDir)
+
+		err := backup.Restore(ctx, s, s.BackupDir(), "backup-20200101T000000Z")
+		g.Expect(err).To(MatchError(ContainSubstring("older version")))
+		g.Expect(s.StopServicesCalledWith).To(BeEmpty())
+	})
+
+	t.Run("ListAndPrune", func(t *testing.T) {
+		g := NewWithT(t)
+
+		// backup names have a resolution of one second
+		time.Sleep(time.Second)
+		b2, err := backup.Create(ctx, s, s.BackupDir())
+		g.Expect(err).ToNot(HaveOccurred())
+
+		// leftovers of interrupted backups are ignored
+		g.Expect(os.MkdirAll(filepath.Join(s.BackupDir(), ".partial-123"), 0o700)).To(Succeed())
+
+		backups, err := backup.List(s.BackupDir())
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(backups).To(HaveLen(2))
+		g.Expect(backups[0].Name).To(Equal(b2.Name))
+		g.Expect(backups[1].Name).To(Equal(b1.Name))
+
+		removed, err := backup.Prune(s.BackupDir(), 1)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(removed).To(ConsistOf(b1.Name))
+
+		backups, err = backup.List(s.BackupDir())
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(backups).To(HaveLen(1))
+		g.Expect(backups[0].Name).To(Equal(b2.Name))
+	})
+}
+
+func TestBackupCreateFails(t *testing.T) {
+	g := NewWithT(t)
+
+	dir := t.TempDir()
+	s := &mock.Snap{
+		Mock: mock.Mock{
+			K8sdStateDir:      filepath.Join(dir, "k8sd"),
+			K8sDqliteStateDir: filepath.Join(dir, "k8s-dqlite"),
+			K8sdClient:        &k8sdmock.Mock{DumpDatabaseErr: fmt.Errorf("k8sd unavailable")},
+		},
+	}
+
+	_, err := backup.Create(context.Background(), s, filepath.Join(dir, "backups"))
+	g.Expect(err).To(MatchError(ContainSubstring("k8sd unavailable")))
+
+	// interrupted backups leave nothing behind
+	entries, err := os.ReadDir(filepath.Join(dir, "backups"))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(entries).To(BeEmpty())
+}
@@ -0,0 +1,107 @@
+package backup
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// k8sdDump is a SQL text dump of the k8sd database, as returned by microcluster.
+// Only the k8sd tables are considered. The microcluster tables describe the cluster members of
+// the local cluster and are never restored.
+type k8sdDump struct {
+	// schema maps the name of each k8sd table, index and trigger to its CREATE statement.
+	schema map[string]string
+	// tables are the k8sd tables in the order they were created.
+	tables []string
+	// rows are the INSERT statements of the rows of the k8sd tables.
+	rows []string
+}
+
+// parseK8sdDump parses a SQL text dump of the k8sd database.
+// The dump has one statement per line, except for CREATE statements, which may span multiple lines.
+func parseK8sdDump(dump string) k8sdDump {
+	d := k8sdDump{schema: make(map[string]string)}
+
+	lines := strings.Split(dump, "\n")
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		switch {
+		case strings.HasPrefix(line, "CREATE "):
+			stmt := line
+			for !strings.HasSuffix(stmt, ";") && i+1 < len(lines) {
+				i++
+				stmt += "\n" + lines[i]
+			}
+			kind, name := parseCreateStatement(stmt)
+			if isInternalTable(name) {
+				continue
+			}
+			d.schema[name] = stmt
+			if kind == "TABLE" {
+				d.tables = append(d.tables, name)
+			}
+		case strings.HasPrefix(line, "INSERT INTO "):
+			name, _, _ := strings.Cut(strings.TrimPrefix(line, "INSERT INTO "), " ")
+			if isInternalTable(name) {
+				continue
+			}
+			d.rows = append(d.rows, line)
+		}
+	}
+
+	return d
+}
+
+// checkSchema returns an error if the k8sd schema of the dump differs from the k8sd schema of other.
+func (d k8sdDump) checkSchema(other k8sdDump) error {
+	var mismatch []string
+	for name, stmt := range d.schema {
+		if other.schema[name] != stmt {
+			mismatch = append(mismatch, name)
+		}
+	}
+	for name := range other.schema {
+		if _, ok := d.schema[name]; !ok {
+			mismatch = append(mismatch, name)
+		}
+	}
+	if len(mismatch) > 0 {
+		sort.Strings(mismatch)
+		return fmt.Errorf("schema mismatch for %s", strings.Join(mismatch, ", "))
+	}
+	return nil
+}
+
+// patch returns the SQL statements that replace the rows of all k8sd tables with the rows of the dump.
+// Tables are cleared in reverse order of creation, so that rows referencing other tables are deleted first.
+func (d k8sdDump) patch() string {
+	var b strings.Builder
+	for i := len(d.tables) - 1; i >= 0; i-- {
+		fmt.Fprintf(&b, "DELETE FROM %s;\n", d.tables[i])
+	}
+	for _, row := range d.rows {
+		b.WriteString(row + "\n")
+	}
+	return b.String()
+}
+
+// parseCreateStatement returns the kind (e.g. "TABLE") and the name of the entity created by stmt.
+func parseCreateStatement(stmt string) (string, string) {
+	fields := strings.Fields(strings.Replace(stmt, " IF NOT EXISTS", "", 1))
+	for i, field := range fields {
+		switch field {
+		case "TABLE", "INDEX", "TRIGGER", "VIEW":
+			if i+1 < len(fields) {
+				name, _, _ := strings.Cut(fields[i+1], "(")
+				return field, strings.Trim(name, "\"`")
+			}
+		}
+	}
+	return "", ""
+}
+
+// isInternalTable returns true for the tables of microcluster and SQLite.
+func isInternalTable(name string) bool {
+	return name == "" || name == "schemas" || strings.HasPrefix(name, "core_") || strings.HasPrefix(name, "internal_") || strings.HasPrefix(name, "sqlite_")
+}
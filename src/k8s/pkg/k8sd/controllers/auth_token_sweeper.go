@@ -0,0 +1,84 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/canonical/k8s/pkg/log"
+	"github.com/canonical/k8s/pkg/snap"
+	snaputil "github.com/canonical/k8s/pkg/snap/util"
+)
+
+// AuthTokenSweeperController periodically deletes the Kubernetes auth tokens that have expired.
+type AuthTokenSweeperController struct {
+	snap      snap.Snap
+	waitReady func()
+	triggerCh <-chan time.Time
+	// reconciledCh is used to notify that the controller has finished its reconciliation loop.
+	reconciledCh chan struct{}
+}
+
+// NewAuthTokenSweeperController creates a new controller.
+// triggerCh is typically a `time.NewTicker(<sweep-interval>).C`.
+func NewAuthTokenSweeperController(snap snap.Snap, waitReady func(), triggerCh <-chan time.Time) *AuthTokenSweeperController {
+	return &AuthTokenSweeperController{
+		snap:         snap,
+		waitReady:    waitReady,
+		triggerCh:    triggerCh,
+		reconciledCh: make(chan struct{}, 1),
+	}
+}
+
+// Run starts the controller.
+// Run accepts a context to manage the lifecycle of the controller.
+// Run accepts a function that reports whether this node should delete the expired tokens. This is used
+// so that only a single control plane node (e.g. the k8sd database leader) writes to the database.
+// Run accepts a function that deletes the tokens that expired before the specified time, and returns their number.
+// Run will loop every time the trigger channel is.
+func (c *AuthTokenSweeperController) Run(
+	ctx context.Context,
+	shouldSweep func(context.Context) (bool, error),
+	deleteExpiredTokens func(context.Context, time.Time) (int64, error),
+) {
+	c.waitReady()
+
+	ctx = log.NewContext(ctx, log.FromContext(ctx).WithValues("controller", "auth-token-sweeper"))
+	log := log.FromContext(ctx)
+
+	for {
+		var now time.Time
+		select {
+		case <-ctx.Done():
+			return
+		case now = <-c.triggerCh:
+		}
+
+		if isWorker, err := snaputil.IsWorker(c.snap); err != nil {
+			log.Error(err, "Failed to check if running on a worker node")
+			continue
+		} else if isWorker {
+			log.Info("Stopping on worker node")
+			return
+		}
+
+		if ok, err := shouldSweep(ctx); err != nil {
+			log.Error(err, "Failed to check if node should delete expired auth tokens")
+		} else if ok {
+			if n, err := deleteExpiredTokens(ctx, now); err != nil {
+				log.Error(err, "Failed to delete expired auth tokens")
+			} else if n > 0 {
+				log.Info("Deleted expired auth tokens", "count", n)
+			}
+		}
+
+		select {
+		case c.reconciledCh <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// ReconciledCh returns the channel where the controller pushes when a reconciliation loop is finished.
+func (c *AuthTokenSweeperController) ReconciledCh() <-chan struct{} {
+	return c.reconciledCh
+}
@@ -0,0 +1,102 @@
+package controllers_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/canonical/k8s/pkg/k8sd/controllers"
+	"github.com/canonical/k8s/pkg/snap/mock"
+	. "github.com/onsi/gomega"
+)
+
+func TestAuthTokenSweeperController(t *testing.T) {
+	s := &mock.Snap{
+		Mock: mock.Mock{
+			LockFilesDir: filepath.Join(t.TempDir(), "locks"),
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	triggerCh := make(chan time.Time)
+	shouldSweep := false
+	var sweptAt []time.Time
+	var sweepErr error
+
+	ctrl := controllers.NewAuthTokenSweeperController(s, func() {}, triggerCh)
+	go ctrl.Run(
+		ctx,
+		func(context.Context) (bool, error) { return shouldSweep, nil },
+		func(_ context.Context, now time.Time) (int64, error) {
+			sweptAt = append(sweptAt, now)
+			return 1, sweepErr
+		},
+	)
+
+	reconcile := func(g Gomega, now time.Time) {
+		select {
+		case triggerCh <- now:
+		case <-time.After(channelSendTimeout):
+			g.Expect(false).To(BeTrue(), "Timed out while attempting to trigger controller reconcile loop")
+		}
+
+		select {
+		case <-ctrl.ReconciledCh():
+		case <-time.After(5 * time.Second):
+			g.Expect(false).To(BeTrue(), "Time out while waiting for the reconcile to complete")
+		}
+	}
+
+	t.Run("NotLeader", func(t *testing.T) {
+		g := NewWithT(t)
+
+		reconcile(g, time.Now())
+		g.Expect(sweptAt).To(BeEmpty())
+	})
+
+	t.Run("Leader", func(t *testing.T) {
+		g := NewWithT(t)
+
+		shouldSweep = true
+		now := time.Now()
+		reconcile(g, now)
+		g.Expect(sweptAt).To(ConsistOf(now))
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		g := NewWithT(t)
+
+		sweepErr = errors.New("database is locked")
+		reconcile(g, time.Now())
+		g.Expect(sweptAt).To(HaveLen(2))
+
+		// the controller keeps running after a failed sweep
+		sweepErr = nil
+		reconcile(g, time.Now())
+		g.Expect(sweptAt).To(HaveLen(3))
+	})
+
+	t.Run("Worker", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(os.MkdirAll(s.LockFilesDir(), 0o700)).To(Succeed())
+		g.Expect(os.WriteFile(filepath.Join(s.LockFilesDir(), "worker"), nil, 0o600)).To(Succeed())
+
+		select {
+		case triggerCh <- time.Now():
+		case <-time.After(channelSendTimeout):
+			g.Fail("Timed out while attempting to trigger controller reconcile loop")
+		}
+		select {
+		case <-ctrl.ReconciledCh():
+			g.Fail("Controller must stop on worker nodes")
+		case <-time.After(100 * time.Millisecond):
+		}
+		g.Expect(sweptAt).To(HaveLen(3))
+	})
+}
@@ -0,0 +1,97 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/canonical/k8s/pkg/k8sd/backup"
+	"github.com/canonical/k8s/pkg/log"
+	"github.com/canonical/k8s/pkg/snap"
+	snaputil "github.com/canonical/k8s/pkg/snap/util"
+)
+
+// BackupController periodically takes backups of the cluster datastores and prunes old backups.
+type BackupController struct {
+	snap      snap.Snap
+	waitReady func()
+	triggerCh <-chan time.Time
+	// dir is the directory where backups are stored.
+	dir string
+	// retention is the number of backups to keep.
+	retention int
+	// reconciledCh is used to notify that the controller has finished its reconciliation loop.
+	reconciledCh chan struct{}
+}
+
+// NewBackupController creates a new controller.
+// triggerCh is typically a `time.NewTicker(<backup-interval>).C`.
+// dir is the directory where backups are stored. If empty, snap.BackupDir() is used.
+// retention is the number of backups to keep.
+func NewBackupController(snap snap.Snap, waitReady func(), triggerCh <-chan time.Time, dir string, retention int) *BackupController {
+	if dir == "" {
+		dir = snap.BackupDir()
+	}
+	return &BackupController{
+		snap:         snap,
+		waitReady:    waitReady,
+		triggerCh:    triggerCh,
+		dir:          dir,
+		retention:    retention,
+		reconciledCh: make(chan struct{}, 1),
+	}
+}
+
+// Run starts the controller.
+// Run accepts a context to manage the lifecycle of the controller.
+// Run accepts a function that reports whether this node should take the backup. This is used
+// so that only a single control plane node (e.g. the k8sd database leader) takes scheduled backups.
+// Old backups are pruned on every control plane node, since backups are stored on the node that took them.
+// Run will loop every time the trigger channel is.
+func (c *BackupController) Run(ctx context.Context, shouldBackup func(context.Context) (bool, error)) {
+	c.waitReady()
+
+	ctx = log.NewContext(ctx, log.FromContext(ctx).WithValues("controller", "backup"))
+	log := log.FromContext(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-c.triggerCh:
+		}
+
+		if isWorker, err := snaputil.IsWorker(c.snap); err != nil {
+			log.Error(err, "Failed to check if running on a worker node")
+			continue
+		} else if isWorker {
+			log.Info("Stopping on worker node")
+			return
+		}
+
+		if ok, err := shouldBackup(ctx); err != nil {
+			log.Error(err, "Failed to check if node should take a backup")
+		} else if ok {
+			if b, err := backup.Create(ctx, c.snap, c.dir); err != nil {
+				log.Error(err, "Failed to take backup")
+			} else {
+				log.Info("Created backup", "name", b.Name, "path", b.Path)
+			}
+		}
+
+		if removed, err := backup.Prune(c.dir, c.retention); err != nil {
+			log.Error(err, "Failed to prune old backups")
+		} else if len(removed) > 0 {
+			log.Info("Pruned old backups", "names", removed)
+		}
+
+		select {
+		case c.reconciledCh <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// ReconciledCh returns the channel where the controller pushes when a reconciliation loop is finished.
+func (c *BackupController) ReconciledCh() <-chan struct{} {
+	return c.reconciledCh
+}
@@ -0,0 +1,93 @@
+package controllers_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	k8sdmock "github.com/canonical/k8s/pkg/client/k8sd/mock"
+	"github.com/canonical/k8s/pkg/k8sd/backup"
+	"github.com/canonical/k8s/pkg/k8sd/controllers"
+	"github.com/canonical/k8s/pkg/snap/mock"
+	. "github.com/onsi/gomega"
+)
+
+func TestBackupController(t *testing.T) {
+	dir := t.TempDir()
+	s := &mock.Snap{
+		Mock: mock.Mock{
+			Hostname:          "node1",
+			K8sdStateDir:      filepath.Join(dir, "k8sd"),
+			K8sDqliteStateDir: filepath.Join(dir, "k8s-dqlite"),
+			LockFilesDir:      filepath.Join(dir, "locks"),
+			BackupDir:         filepath.Join(dir, "backups"),
+			K8sdClient:        &k8sdmock.Mock{DumpDatabaseResponse: "BEGIN TRANSACTION;\nCOMMIT;\n"},
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	triggerCh := make(chan time.Time)
+	shouldBackup := false
+
+	ctrl := controllers.NewBackupController(s, func() {}, triggerCh, "", 2)
+	go ctrl.Run(ctx, func(context.Context) (bool, error) { return shouldBackup, nil })
+
+	reconcile := func(g Gomega) {
+		select {
+		case triggerCh <- time.Now():
+		case <-time.After(channelSendTimeout):
+			g.Expect(false).To(BeTrue(), "Timed out while attempting to trigger controller reconcile loop")
+		}
+
+		select {
+		case <-ctrl.ReconciledCh():
+		case <-time.After(5 * time.Second):
+			g.Expect(false).To(BeTrue(), "Time out while waiting for the reconcile to complete")
+		}
+	}
+
+	t.Run("NotLeader", func(t *testing.T) {
+		g := NewWithT(t)
+
+		reconcile(g)
+
+		backups, err := backup.List(s.BackupDir())
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(backups).To(BeEmpty())
+	})
+
+	t.Run("Retention", func(t *testing.T) {
+		g := NewWithT(t)
+
+		shouldBackup = true
+		for i := 0; i < 3; i++ {
+			if i > 0 {
+				// backup names have a resolution of one second
+				time.Sleep(time.Second)
+			}
+			reconcile(g)
+		}
+
+		backups, err := backup.List(s.BackupDir())
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(backups).To(HaveLen(2))
+	})
+	t.Run("PruneNotLeader", func(t *testing.T) {
+		g := NewWithT(t)
+
+		// backups taken while this node was the leader are pruned after the leadership moved
+		time.Sleep(time.Second)
+		_, err := backup.Create(ctx, s, s.BackupDir())
+		g.Expect(err).ToNot(HaveOccurred())
+
+		shouldBackup = false
+		reconcile(g)
+
+		backups, err := backup.List(s.BackupDir())
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(backups).To(HaveLen(2))
+	})
+}
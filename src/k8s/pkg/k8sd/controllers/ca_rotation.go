@@ -0,0 +1,246 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apiv1 "github.com/canonical/k8s-snap-api/api/v1"
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/k8s/pkg/log"
+	"github.com/canonical/k8s/pkg/snap"
+	snaputil "github.com/canonical/k8s/pkg/snap/util"
+)
+
+var (
+	// controlPlaneCARotationServices are the services that are restarted on control plane nodes to load the
+	// CAs and certificates of a CA rotation phase. kube-apiserver is restarted first, so that it trusts the
+	// certificates of the other services.
+	controlPlaneCARotationServices = []string{"kube-apiserver", "kube-controller-manager", "kube-scheduler", "kubelet", "kube-proxy"}
+
+	// workerCARotationServices are the services that are restarted on worker nodes to load the CAs and
+	// certificates of a CA rotation phase.
+	workerCARotationServices = []string{"kubelet", "kube-proxy"}
+)
+
+// CARotationController applies the phases of a Kubernetes CA rotation on the node.
+type CARotationController struct {
+	snap      snap.Snap
+	waitReady func()
+	triggerCh <-chan time.Time
+	// reconciledCh is used to notify that the controller has finished its reconciliation loop.
+	reconciledCh chan struct{}
+}
+
+// NewCARotationController creates a new controller.
+// triggerCh is typically a `time.NewTicker(<check-interval>).C`.
+func NewCARotationController(snap snap.Snap, waitReady func(), triggerCh <-chan time.Time) *CARotationController {
+	return &CARotationController{
+		snap:         snap,
+		waitReady:    waitReady,
+		triggerCh:    triggerCh,
+		reconciledCh: make(chan struct{}, 1),
+	}
+}
+
+// Run starts the controller.
+// Run accepts a context to manage the lifecycle of the controller.
+// Run accepts a function that retrieves the latest CA rotation and the progress of this node. Both are nil if the
+// CAs were never rotated, and the progress is nil if this node did not apply any phase of the rotation yet.
+// Run accepts two functions that record the certificate rotation of this node, which tracks the service restarts of
+// a phase. The first one starts a rotation, unless another node has an active rotation that was updated within the
+// lease duration, and returns the name of that node. This is used so that only a single control plane node restarts
+// its services at once. The second one records the state of a rotation that was started, and starts the rotation of
+// worker nodes, which do not wait for each other.
+// Run accepts a function that writes the CAs of the cluster configuration on this node and renews the given
+// certificates, without restarting any services.
+// Run accepts a function that records the CA rotation phase that this node applied, and why it did not apply the
+// current phase yet.
+// Run accepts a function that moves the CA rotation to the next phase once all nodes applied the current one.
+// Run will loop every time the trigger channel is.
+func (c *CARotationController) Run(
+	ctx context.Context,
+	getRotation func(context.Context) (*types.CARotation, *types.CARotationNode, error),
+	acquireRotation func(context.Context, types.CertificateRotation, time.Duration) (string, error),
+	setRotation func(context.Context, types.CertificateRotation) error,
+	applyPhase func(ctx context.Context, certificates []apiv1.CertificateName, extraSANs []string, expirationSeconds int) error,
+	setNode func(context.Context, int64, types.CARotationNode) error,
+	advance func(context.Context) error,
+) {
+	c.waitReady()
+
+	ctx = log.NewContext(ctx, log.FromContext(ctx).WithValues("controller", "ca-rotation"))
+	log := log.FromContext(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-c.triggerCh:
+		}
+
+		if err := c.reconcile(ctx, getRotation, acquireRotation, setRotation, applyPhase, setNode, advance); err != nil {
+			log.Error(err, "Failed to apply CA rotation")
+		}
+
+		select {
+		case c.reconciledCh <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (c *CARotationController) reconcile(
+	ctx context.Context,
+	getRotation func(context.Context) (*types.CARotation, *types.CARotationNode, error),
+	acquireRotation func(context.Context, types.CertificateRotation, time.Duration) (string, error),
+	setRotation func(context.Context, types.CertificateRotation) error,
+	applyPhase func(ctx context.Context, certificates []apiv1.CertificateName, extraSANs []string, expirationSeconds int) error,
+	setNode func(context.Context, int64, types.CARotationNode) error,
+	advance func(context.Context) error,
+) error {
+	rotation, node, err := getRotation(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get CA rotation: %w", err)
+	}
+	if rotation == nil || rotation.Phase == types.CARotationCompleted {
+		return nil
+	}
+
+	isWorker, err := snaputil.IsWorker(c.snap)
+	if err != nil {
+		return fmt.Errorf("failed to check if node is a worker: %w", err)
+	}
+
+	var applied types.CARotationPhase
+	if node != nil {
+		applied = node.Phase
+	}
+	if applied != rotation.Phase {
+		if err := c.applyPhase(ctx, *rotation, applied, isWorker, acquireRotation, setRotation, applyPhase, setNode); err != nil {
+			return err
+		}
+	}
+
+	// NOTE: Worker nodes can not write the cluster configuration, so the rotation is advanced by control plane nodes.
+	if !isWorker {
+		if err := advance(ctx); err != nil {
+			return fmt.Errorf("failed to advance CA rotation: %w", err)
+		}
+	}
+	return nil
+}
+
+// applyPhase applies the current phase of the rotation on the node and restarts the services of the node.
+// applied is the last phase that was applied on the node, which is kept in the progress of the node until the
+// current phase is applied successfully.
+func (c *CARotationController) applyPhase(
+	ctx context.Context,
+	rotation types.CARotation,
+	applied types.CARotationPhase,
+	isWorker bool,
+	acquireRotation func(context.Context, types.CertificateRotation, time.Duration) (string, error),
+	setRotation func(context.Context, types.CertificateRotation) error,
+	applyPhase func(ctx context.Context, certificates []apiv1.CertificateName, extraSANs []string, expirationSeconds int) error,
+	setNode func(context.Context, int64, types.CARotationNode) error,
+) error {
+	log := log.FromContext(ctx).WithValues("phase", rotation.Phase)
+
+	certificates, services := controlPlaneRotatedCertificates, controlPlaneCARotationServices
+	if isWorker {
+		certificates, services = workerRotatedCertificates, workerCARotationServices
+	}
+
+	var (
+		reissued   []rotatedCertificate
+		extraSANs  []string
+		expiration time.Duration
+	)
+	for _, certificate := range certificates {
+		cert, err := loadRotatedCertificate(c.snap, certificate)
+		if err != nil {
+			return fmt.Errorf("failed to load certificate %s: %w", certificate.name, err)
+		}
+		extraSANs = append(extraSANs, cert.DNSNames...)
+		for _, ip := range cert.IPAddresses {
+			extraSANs = append(extraSANs, ip.String())
+		}
+
+		// NOTE: The front proxy CA is not rotated, so the front proxy client certificate is kept.
+		if rotation.Phase != types.CARotationReissue || certificate.name == apiv1.CertificateFrontProxyClient {
+			continue
+		}
+		reissued = append(reissued, certificate)
+		expiration = max(expiration, cert.NotAfter.Sub(cert.NotBefore))
+	}
+
+	setProgress := func(message string) {
+		if err := setNode(ctx, rotation.ID, types.CARotationNode{
+			Phase:     applied,
+			Message:   message,
+			UpdatedAt: time.Now(),
+		}); err != nil {
+			log.Error(err, "Failed to record CA rotation progress")
+		}
+	}
+
+	restart := types.CertificateRotation{
+		State:        types.CertificateRotationRenewing,
+		Certificates: []string{fmt.Sprintf("ca-rotation/%s", rotation.Phase)},
+		UpdatedAt:    time.Now(),
+	}
+	if isWorker {
+		// NOTE: Worker nodes do not restart any control plane services, so they do not wait for each other.
+		if err := setRotation(ctx, restart); err != nil {
+			return fmt.Errorf("failed to record certificate rotation: %w", err)
+		}
+	} else if holder, err := acquireRotation(ctx, restart, certificateRotationLease); err != nil {
+		return fmt.Errorf("failed to acquire certificate rotation: %w", err)
+	} else if holder != "" {
+		log.Info("Waiting for another node to finish rotating certificates", "node", holder)
+		setProgress(fmt.Sprintf("waiting for node %s to finish rotating certificates", holder))
+		return nil
+	}
+
+	update := func(state types.CertificateRotationState, message string) {
+		restart.State = state
+		restart.Message = message
+		restart.UpdatedAt = time.Now()
+		if err := setRotation(ctx, restart); err != nil {
+			log.Error(err, "Failed to record certificate rotation", "state", state)
+		}
+	}
+
+	log.Info("Applying CA rotation phase")
+	applyCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+	if err := applyPhase(applyCtx, toCertificateNames(reissued), extraSANs, int(expiration.Seconds())); err != nil {
+		err = fmt.Errorf("failed to apply CA rotation phase %s: %w", rotation.Phase, err)
+		update(types.CertificateRotationFailed, err.Error())
+		setProgress(err.Error())
+		return err
+	}
+
+	for _, service := range services {
+		log.Info("Restarting service", "service", service)
+		update(types.CertificateRotationRestarting, fmt.Sprintf("restarting %s", service))
+		if err := restartService(ctx, c.snap, service); err != nil {
+			err = fmt.Errorf("failed to restart %s: %w", service, err)
+			update(types.CertificateRotationFailed, err.Error())
+			setProgress(err.Error())
+			return err
+		}
+	}
+
+	log.Info("Applied CA rotation phase")
+	update(types.CertificateRotationCompleted, "")
+	if err := setNode(ctx, rotation.ID, types.CARotationNode{Phase: rotation.Phase, UpdatedAt: time.Now()}); err != nil {
+		return fmt.Errorf("failed to record CA rotation progress: %w", err)
+	}
+	return nil
+}
+
+// ReconciledCh returns the channel where the controller pushes when a reconciliation loop is finished.
+func (c *CARotationController) ReconciledCh() <-chan struct{} {
+	return c.reconciledCh
+}
@@ -0,0 +1,161 @@
+package controllers_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	apiv1 "github.com/canonical/k8s-snap-api/api/v1"
+	"github.com/canonical/k8s/pkg/client/kubernetes"
+	"github.com/canonical/k8s/pkg/k8sd/controllers"
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/k8s/pkg/snap/mock"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCARotationController(t *testing.T) {
+	type result struct {
+		applied   bool
+		refreshed []apiv1.CertificateName
+		nodes     []types.CARotationNode
+		advanced  bool
+		s         *mock.Snap
+	}
+
+	run := func(t *testing.T, worker bool, holder string, rotation *types.CARotation, node *types.CARotationNode) result {
+		g := NewWithT(t)
+
+		dir := t.TempDir()
+		clientset := fake.NewSimpleClientset(&corev1.Endpoints{ObjectMeta: metav1.ObjectMeta{Name: "kubernetes", Namespace: "default"}})
+		s := &mock.Snap{
+			Mock: mock.Mock{
+				KubernetesPKIDir:    filepath.Join(dir, "pki"),
+				KubernetesConfigDir: filepath.Join(dir, "config"),
+				LockFilesDir:        filepath.Join(dir, "locks"),
+				KubernetesClient:    &kubernetes.Client{Interface: clientset},
+			},
+		}
+		g.Expect(os.MkdirAll(s.LockFilesDir(), 0o700)).To(Succeed())
+		if worker {
+			g.Expect(os.WriteFile(filepath.Join(s.LockFilesDir(), "worker"), nil, 0o600)).To(Succeed())
+			writeRotatedCertificates(g, s, []string{"kubelet"}, []string{"kubelet.conf", "proxy.conf"})
+		} else {
+			writeRotatedCertificates(g, s,
+				[]string{"apiserver", "apiserver-kubelet-client", "front-proxy-client", "kubelet"},
+				[]string{"admin.conf", "controller.conf", "scheduler.conf", "kubelet.conf", "proxy.conf"},
+			)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		triggerCh := make(chan time.Time)
+		ctrl := controllers.NewCARotationController(s, func() {}, triggerCh)
+
+		var r result
+		r.s = s
+		go ctrl.Run(
+			ctx,
+			func(context.Context) (*types.CARotation, *types.CARotationNode, error) { return rotation, node, nil },
+			func(context.Context, types.CertificateRotation, time.Duration) (string, error) {
+				g.Expect(worker).To(BeFalse(), "worker nodes must not acquire the certificate rotation")
+				return holder, nil
+			},
+			func(context.Context, types.CertificateRotation) error { return nil },
+			func(_ context.Context, certificates []apiv1.CertificateName, _ []string, _ int) error {
+				r.applied = true
+				r.refreshed = certificates
+				return nil
+			},
+			func(_ context.Context, id int64, node types.CARotationNode) error {
+				g.Expect(id).To(Equal(rotation.ID))
+				r.nodes = append(r.nodes, node)
+				return nil
+			},
+			func(context.Context) error {
+				r.advanced = true
+				return nil
+			},
+		)
+
+		select {
+		case triggerCh <- time.Now():
+		case <-time.After(channelSendTimeout):
+			g.Fail("Timed out while attempting to trigger controller reconcile loop")
+		}
+		select {
+		case <-ctrl.ReconciledCh():
+		case <-time.After(5 * time.Second):
+			g.Fail("Time out while waiting for the reconcile to complete")
+		}
+		return r
+	}
+
+	t.Run("NoRotation", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r := run(t, false, "", nil, nil)
+		g.Expect(r.applied).To(BeFalse())
+		g.Expect(r.advanced).To(BeFalse())
+		g.Expect(r.s.RestartServicesCalledWith).To(BeEmpty())
+	})
+
+	t.Run("Trust", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r := run(t, false, "", &types.CARotation{ID: 1, Phase: types.CARotationTrust}, nil)
+		g.Expect(r.applied).To(BeTrue())
+		g.Expect(r.refreshed).To(BeEmpty())
+		g.Expect(r.s.RestartServicesCalledWith).To(Equal([][]string{{"kube-apiserver"}, {"kube-controller-manager"}, {"kube-scheduler"}, {"kubelet"}, {"kube-proxy"}}))
+		g.Expect(r.nodes).To(HaveLen(1))
+		g.Expect(r.nodes[0].Phase).To(Equal(types.CARotationTrust))
+		g.Expect(r.advanced).To(BeTrue())
+	})
+
+	t.Run("Reissue", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r := run(t, false, "", &types.CARotation{ID: 1, Phase: types.CARotationReissue}, &types.CARotationNode{Phase: types.CARotationTrust})
+		g.Expect(r.refreshed).To(ConsistOf(
+			apiv1.CertificateAPIServer, apiv1.CertificateAPIServerKubeletClient, apiv1.CertificateKubelet,
+			apiv1.CertificateAdminClient, apiv1.CertificateControllerManagerClient, apiv1.CertificateSchedulerClient,
+			apiv1.CertificateKubeletClient, apiv1.CertificateProxyClient,
+		))
+		g.Expect(r.nodes[len(r.nodes)-1].Phase).To(Equal(types.CARotationReissue))
+	})
+
+	t.Run("Applied", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r := run(t, false, "", &types.CARotation{ID: 1, Phase: types.CARotationTrust}, &types.CARotationNode{Phase: types.CARotationTrust})
+		g.Expect(r.applied).To(BeFalse())
+		g.Expect(r.s.RestartServicesCalledWith).To(BeEmpty())
+		g.Expect(r.advanced).To(BeTrue())
+	})
+
+	t.Run("Waiting", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r := run(t, false, "node2", &types.CARotation{ID: 1, Phase: types.CARotationReissue}, &types.CARotationNode{Phase: types.CARotationTrust})
+		g.Expect(r.applied).To(BeFalse())
+		g.Expect(r.s.RestartServicesCalledWith).To(BeEmpty())
+		g.Expect(r.nodes).To(HaveLen(1))
+		g.Expect(r.nodes[0].Phase).To(Equal(types.CARotationTrust))
+		g.Expect(r.nodes[0].Message).To(ContainSubstring("node2"))
+	})
+
+	t.Run("Worker", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r := run(t, true, "", &types.CARotation{ID: 1, Phase: types.CARotationReissue}, &types.CARotationNode{Phase: types.CARotationTrust})
+		g.Expect(r.refreshed).To(ConsistOf(apiv1.CertificateKubelet, apiv1.CertificateKubeletClient, apiv1.CertificateProxyClient))
+		g.Expect(r.s.RestartServicesCalledWith).To(Equal([][]string{{"kubelet"}, {"kube-proxy"}}))
+		g.Expect(r.nodes[len(r.nodes)-1].Phase).To(Equal(types.CARotationReissue))
+		g.Expect(r.advanced).To(BeFalse())
+	})
+}
@@ -0,0 +1,145 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/k8s/pkg/log"
+	"github.com/canonical/k8s/pkg/snap"
+	snaputil "github.com/canonical/k8s/pkg/snap/util"
+	pkiutil "github.com/canonical/k8s/pkg/utils/pki"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// certificateMetricsTimeout is the timeout for reading the cluster configuration and certificate rotations on a scrape.
+const certificateMetricsTimeout = 10 * time.Second
+
+var (
+	certificateExpirationDesc = prometheus.NewDesc(
+		"k8sd_certificate_expiration_timestamp_seconds",
+		"Time when a certificate of the node expires, in seconds since the Unix epoch.",
+		[]string{"name", "node", "issuer"}, nil,
+	)
+	certificateAuthorityExpirationDesc = prometheus.NewDesc(
+		"k8sd_certificate_authority_expiration_timestamp_seconds",
+		"Time when a Kubernetes CA expires, in seconds since the Unix epoch.",
+		[]string{"name"}, nil,
+	)
+	certificateLastRotationDesc = prometheus.NewDesc(
+		"k8sd_certificate_last_rotation_timestamp_seconds",
+		"Time when the last automatic certificate rotation of a node completed, in seconds since the Unix epoch.",
+		[]string{"node"}, nil,
+	)
+)
+
+// CertificateMetricsCollector exports the expiry of the node certificates and the Kubernetes CAs, and the time of
+// the last certificate rotation of each node, as Prometheus metrics. The values are read on every scrape.
+type CertificateMetricsCollector struct {
+	snap                     snap.Snap
+	logger                   log.Logger
+	getClusterConfig         func(context.Context) (types.ClusterConfig, error)
+	listCertificateRotations func(context.Context) ([]types.CertificateRotation, error)
+}
+
+// NewCertificateMetricsCollector creates a new collector.
+// getClusterConfig is used to read the Kubernetes CA certificates.
+// listCertificateRotations is used to read the certificate rotation progress of all nodes.
+func NewCertificateMetricsCollector(
+	snap snap.Snap,
+	logger log.Logger,
+	getClusterConfig func(context.Context) (types.ClusterConfig, error),
+	listCertificateRotations func(context.Context) ([]types.CertificateRotation, error),
+) *CertificateMetricsCollector {
+	return &CertificateMetricsCollector{
+		snap:                     snap,
+		logger:                   logger,
+		getClusterConfig:         getClusterConfig,
+		listCertificateRotations: listCertificateRotations,
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *CertificateMetricsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- certificateExpirationDesc
+	ch <- certificateAuthorityExpirationDesc
+	ch <- certificateLastRotationDesc
+}
+
+// Collect implements prometheus.Collector.
+// Collect skips the metrics that cannot be read, so that a single missing certificate does not fail the scrape.
+func (c *CertificateMetricsCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), certificateMetricsTimeout)
+	defer cancel()
+
+	c.collectNodeCertificates(ch)
+	c.collectCertificateAuthorities(ctx, ch)
+	c.collectCertificateRotations(ctx, ch)
+}
+
+func (c *CertificateMetricsCollector) collectNodeCertificates(ch chan<- prometheus.Metric) {
+	isWorker, err := snaputil.IsWorker(c.snap)
+	if err != nil {
+		c.logger.Error(err, "Failed to check if node is a worker")
+		return
+	}
+	certificates := controlPlaneRotatedCertificates
+	if isWorker {
+		certificates = workerRotatedCertificates
+	}
+
+	node := c.snap.Hostname()
+	for _, certificate := range certificates {
+		cert, err := loadRotatedCertificate(c.snap, certificate)
+		if err != nil {
+			c.logger.Error(err, "Failed to load certificate", "certificate", certificate.name)
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(certificateExpirationDesc, prometheus.GaugeValue,
+			float64(cert.NotAfter.Unix()), string(certificate.name), node, cert.Issuer.CommonName)
+	}
+}
+
+func (c *CertificateMetricsCollector) collectCertificateAuthorities(ctx context.Context, ch chan<- prometheus.Metric) {
+	config, err := c.getClusterConfig(ctx)
+	if err != nil {
+		c.logger.Error(err, "Failed to get cluster configuration")
+		return
+	}
+
+	for _, ca := range []struct {
+		name    string
+		certPEM *string
+	}{
+		{name: "kubernetes-ca", certPEM: config.Certificates.CACert},
+		{name: "kubernetes-ca-client", certPEM: config.Certificates.ClientCACert},
+		{name: "front-proxy-ca", certPEM: config.Certificates.FrontProxyCACert},
+	} {
+		if ca.certPEM == nil || *ca.certPEM == "" {
+			continue
+		}
+		cert, _, err := pkiutil.LoadCertificate(*ca.certPEM, "")
+		if err != nil {
+			c.logger.Error(err, "Failed to load CA certificate", "ca", ca.name)
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(certificateAuthorityExpirationDesc, prometheus.GaugeValue,
+			float64(cert.NotAfter.Unix()), ca.name)
+	}
+}
+
+func (c *CertificateMetricsCollector) collectCertificateRotations(ctx context.Context, ch chan<- prometheus.Metric) {
+	rotations, err := c.listCertificateRotations(ctx)
+	if err != nil {
+		c.logger.Error(err, "Failed to list certificate rotations")
+		return
+	}
+
+	for _, rotation := range rotations {
+		if rotation.State != types.CertificateRotationCompleted {
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(certificateLastRotationDesc, prometheus.GaugeValue,
+			float64(rotation.UpdatedAt.Unix()), rotation.Node)
+	}
+}
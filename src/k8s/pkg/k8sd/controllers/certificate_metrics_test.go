@@ -0,0 +1,91 @@
+package controllers_test
+
+import (
+	"context"
+	"crypto/x509/pkix"
+	"errors"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/canonical/k8s/pkg/k8sd/controllers"
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/k8s/pkg/log"
+	"github.com/canonical/k8s/pkg/snap/mock"
+	"github.com/canonical/k8s/pkg/utils"
+	pkiutil "github.com/canonical/k8s/pkg/utils/pki"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestCertificateMetricsCollector(t *testing.T) {
+	g := NewWithT(t)
+
+	dir := t.TempDir()
+	s := &mock.Snap{
+		Mock: mock.Mock{
+			Hostname:            "node1",
+			KubernetesPKIDir:    filepath.Join(dir, "pki"),
+			KubernetesConfigDir: filepath.Join(dir, "config"),
+			LockFilesDir:        filepath.Join(dir, "locks"),
+		},
+	}
+	writeRotatedCertificates(g, s,
+		[]string{"apiserver", "apiserver-kubelet-client", "front-proxy-client", "kubelet"},
+		[]string{"admin.conf", "controller.conf", "scheduler.conf", "kubelet.conf", "proxy.conf"},
+	)
+
+	notAfter := time.Now().AddDate(10, 0, 0).Truncate(time.Second)
+	caPEM, _, err := pkiutil.GenerateSelfSignedCA(pkix.Name{CommonName: "kubernetes-ca"}, time.Now(), notAfter, pkiutil.KeyAlgorithmECDSAP256)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	rotatedAt := time.Unix(1700000000, 0)
+	collector := controllers.NewCertificateMetricsCollector(
+		s,
+		log.L(),
+		func(context.Context) (types.ClusterConfig, error) {
+			return types.ClusterConfig{Certificates: types.Certificates{CACert: utils.Pointer(caPEM)}}, nil
+		},
+		func(context.Context) ([]types.CertificateRotation, error) {
+			return []types.CertificateRotation{
+				{Node: "node1", State: types.CertificateRotationCompleted, UpdatedAt: rotatedAt},
+				{Node: "node2", State: types.CertificateRotationRenewing, UpdatedAt: rotatedAt},
+			}, nil
+		},
+	)
+
+	g.Expect(testutil.CollectAndCount(collector, "k8sd_certificate_expiration_timestamp_seconds")).To(Equal(9))
+	g.Expect(testutil.CollectAndCompare(collector, strings.NewReader(`
+# HELP k8sd_certificate_authority_expiration_timestamp_seconds Time when a Kubernetes CA expires, in seconds since the Unix epoch.
+# TYPE k8sd_certificate_authority_expiration_timestamp_seconds gauge
+k8sd_certificate_authority_expiration_timestamp_seconds{name="kubernetes-ca"} `+strconv.FormatFloat(float64(notAfter.Unix()), 'g', -1, 64)+`
+# HELP k8sd_certificate_last_rotation_timestamp_seconds Time when the last automatic certificate rotation of a node completed, in seconds since the Unix epoch.
+# TYPE k8sd_certificate_last_rotation_timestamp_seconds gauge
+k8sd_certificate_last_rotation_timestamp_seconds{node="node1"} 1.7e+09
+`), "k8sd_certificate_authority_expiration_timestamp_seconds", "k8sd_certificate_last_rotation_timestamp_seconds")).To(Succeed())
+
+	t.Run("MissingCertificates", func(t *testing.T) {
+		g := NewWithT(t)
+
+		s := &mock.Snap{
+			Mock: mock.Mock{
+				Hostname:            "node1",
+				KubernetesPKIDir:    filepath.Join(t.TempDir(), "pki"),
+				KubernetesConfigDir: filepath.Join(t.TempDir(), "config"),
+				LockFilesDir:        filepath.Join(t.TempDir(), "locks"),
+			},
+		}
+		collector := controllers.NewCertificateMetricsCollector(
+			s,
+			log.L(),
+			func(context.Context) (types.ClusterConfig, error) {
+				return types.ClusterConfig{}, errors.New("no database")
+			},
+			func(context.Context) ([]types.CertificateRotation, error) { return nil, errors.New("no database") },
+		)
+
+		g.Expect(testutil.CollectAndCount(collector)).To(BeZero())
+	})
+}
@@ -0,0 +1,342 @@
+package controllers
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	apiv1 "github.com/canonical/k8s-snap-api/api/v1"
+	"github.com/canonical/k8s/pkg/k8sd/controllers/csrsigning"
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/k8s/pkg/log"
+	"github.com/canonical/k8s/pkg/snap"
+	snaputil "github.com/canonical/k8s/pkg/snap/util"
+	pkiutil "github.com/canonical/k8s/pkg/utils/pki"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// certificateRotationLease is the time after which the certificate rotation of another control plane node
+// is not considered to be in progress anymore, e.g. because the node went down while rotating.
+const certificateRotationLease = 15 * time.Minute
+
+// rotatedCertificate is a certificate of the node that is renewed by the CertificateRotationController.
+type rotatedCertificate struct {
+	// name is the name of the certificate. The certificate is read from <name>.crt in the Kubernetes PKI directory,
+	// or from the kubeconfig file <name> in the Kubernetes config directory.
+	name       apiv1.CertificateName
+	kubeconfig bool
+	// service is the service that uses the certificate and is restarted after the renewal. Empty if no service uses it.
+	service string
+	// caKey returns the key of the CA that signs the certificate. Certificates of external CAs are not renewed,
+	// unless the key of the CA is available through the signer.
+	caKey func(types.Certificates) string
+	// ca is the CA that signs the certificate. Empty if the CA key is not available through the signer.
+	ca csrsigning.CA
+}
+
+var (
+	controlPlaneRotatedCertificates = []rotatedCertificate{
+		{name: apiv1.CertificateAPIServer, service: "kube-apiserver", caKey: types.Certificates.GetCAKey, ca: csrsigning.CAKubernetes},
+		{name: apiv1.CertificateAPIServerKubeletClient, service: "kube-apiserver", caKey: types.Certificates.GetClientCAKey, ca: csrsigning.CAKubernetesClient},
+		{name: apiv1.CertificateFrontProxyClient, service: "kube-apiserver", caKey: types.Certificates.GetFrontProxyCAKey},
+		{name: apiv1.CertificateKubelet, service: "kubelet", caKey: types.Certificates.GetCAKey, ca: csrsigning.CAKubernetes},
+		{name: apiv1.CertificateAdminClient, kubeconfig: true, caKey: types.Certificates.GetClientCAKey, ca: csrsigning.CAKubernetesClient},
+		{name: apiv1.CertificateControllerManagerClient, kubeconfig: true, service: "kube-controller-manager", caKey: types.Certificates.GetClientCAKey, ca: csrsigning.CAKubernetesClient},
+		{name: apiv1.CertificateSchedulerClient, kubeconfig: true, service: "kube-scheduler", caKey: types.Certificates.GetClientCAKey, ca: csrsigning.CAKubernetesClient},
+		{name: apiv1.CertificateKubeletClient, kubeconfig: true, service: "kubelet", caKey: types.Certificates.GetClientCAKey, ca: csrsigning.CAKubernetesClient},
+		{name: apiv1.CertificateProxyClient, kubeconfig: true, service: "kube-proxy", caKey: types.Certificates.GetClientCAKey, ca: csrsigning.CAKubernetesClient},
+	}
+
+	workerRotatedCertificates = []rotatedCertificate{
+		{name: apiv1.CertificateKubelet, service: "kubelet", caKey: types.Certificates.GetCAKey, ca: csrsigning.CAKubernetes},
+		{name: apiv1.CertificateKubeletClient, kubeconfig: true, service: "kubelet", caKey: types.Certificates.GetClientCAKey, ca: csrsigning.CAKubernetesClient},
+		{name: apiv1.CertificateProxyClient, kubeconfig: true, service: "kube-proxy", caKey: types.Certificates.GetClientCAKey, ca: csrsigning.CAKubernetesClient},
+	}
+)
+
+// CertificateRotationController renews the certificates of the node before they expire.
+type CertificateRotationController struct {
+	snap      snap.Snap
+	waitReady func()
+	triggerCh <-chan time.Time
+	// window is the time before expiry when certificates are renewed.
+	window time.Duration
+	// signers provides the CA keys that are not stored in the cluster configuration.
+	signers *csrsigning.SignerCache
+	// reconciledCh is used to notify that the controller has finished its reconciliation loop.
+	reconciledCh chan struct{}
+}
+
+// NewCertificateRotationController creates a new controller.
+// triggerCh is typically a `time.NewTicker(<check-interval>).C`.
+// window is the time before expiry when certificates are renewed. Certificates are renewed at the latest
+// when half of their lifetime has passed, so that short-lived certificates are not renewed on every check.
+// signers provides the CA keys that are not stored in the cluster configuration.
+func NewCertificateRotationController(snap snap.Snap, waitReady func(), triggerCh <-chan time.Time, window time.Duration, signers *csrsigning.SignerCache) *CertificateRotationController {
+	return &CertificateRotationController{
+		snap:         snap,
+		waitReady:    waitReady,
+		triggerCh:    triggerCh,
+		window:       window,
+		signers:      signers,
+		reconciledCh: make(chan struct{}, 1),
+	}
+}
+
+// Run starts the controller.
+// Run accepts a context to manage the lifecycle of the controller.
+// Run accepts a function that retrieves the current cluster configuration.
+// Run accepts two functions that record the rotation progress of this node. The first one starts a rotation, unless
+// another node has an active rotation that was updated within the lease duration, and returns the name of that node.
+// This is used so that only a single control plane node rotates its certificates at once. The second one records the
+// progress of a rotation that was started.
+// Run accepts a function that renews the given certificates of this node, without restarting any services.
+// Run will loop every time the trigger channel is.
+func (c *CertificateRotationController) Run(
+	ctx context.Context,
+	getClusterConfig func(context.Context) (types.ClusterConfig, error),
+	acquireRotation func(context.Context, types.CertificateRotation, time.Duration) (string, error),
+	setRotation func(context.Context, types.CertificateRotation) error,
+	refreshCertificates func(ctx context.Context, certificates []apiv1.CertificateName, extraSANs []string, expirationSeconds int) error,
+) {
+	c.waitReady()
+
+	ctx = log.NewContext(ctx, log.FromContext(ctx).WithValues("controller", "certificate-rotation"))
+	log := log.FromContext(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-c.triggerCh:
+		}
+
+		if err := c.reconcile(ctx, getClusterConfig, acquireRotation, setRotation, refreshCertificates); err != nil {
+			log.Error(err, "Failed to rotate certificates")
+		}
+
+		select {
+		case c.reconciledCh <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (c *CertificateRotationController) reconcile(
+	ctx context.Context,
+	getClusterConfig func(context.Context) (types.ClusterConfig, error),
+	acquireRotation func(context.Context, types.CertificateRotation, time.Duration) (string, error),
+	setRotation func(context.Context, types.CertificateRotation) error,
+	refreshCertificates func(ctx context.Context, certificates []apiv1.CertificateName, extraSANs []string, expirationSeconds int) error,
+) error {
+	log := log.FromContext(ctx)
+
+	isWorker, err := snaputil.IsWorker(c.snap)
+	if err != nil {
+		return fmt.Errorf("failed to check if node is a worker: %w", err)
+	}
+	certificates := controlPlaneRotatedCertificates
+	if isWorker {
+		certificates = workerRotatedCertificates
+	}
+
+	config, err := getClusterConfig(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get cluster configuration: %w", err)
+	}
+
+	now := time.Now()
+	var (
+		expiring   []rotatedCertificate
+		extraSANs  []string
+		expiration time.Duration
+	)
+	for _, certificate := range certificates {
+		cert, err := loadRotatedCertificate(c.snap, certificate)
+		if err != nil {
+			return fmt.Errorf("failed to load certificate %s: %w", certificate.name, err)
+		}
+
+		extraSANs = append(extraSANs, cert.DNSNames...)
+		for _, ip := range cert.IPAddresses {
+			extraSANs = append(extraSANs, ip.String())
+		}
+
+		if renew, err := c.canRenew(ctx, config, certificate, isWorker); err != nil {
+			return fmt.Errorf("failed to check CA of certificate %s: %w", certificate.name, err)
+		} else if !renew {
+			continue
+		}
+		lifetime := cert.NotAfter.Sub(cert.NotBefore)
+		if cert.NotAfter.Sub(now) >= min(c.window, lifetime/2) {
+			continue
+		}
+		expiring = append(expiring, certificate)
+		expiration = max(expiration, lifetime)
+	}
+
+	if len(expiring) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(expiring))
+	for _, certificate := range expiring {
+		names = append(names, string(certificate.name))
+	}
+	log = log.WithValues("certificates", names)
+
+	rotation := types.CertificateRotation{
+		State:        types.CertificateRotationRenewing,
+		Certificates: names,
+		UpdatedAt:    now,
+	}
+	if isWorker {
+		// NOTE: Worker nodes do not restart any control plane services, so they do not wait for each other.
+		if err := setRotation(ctx, rotation); err != nil {
+			return fmt.Errorf("failed to record certificate rotation: %w", err)
+		}
+	} else if holder, err := acquireRotation(ctx, rotation, certificateRotationLease); err != nil {
+		return fmt.Errorf("failed to acquire certificate rotation: %w", err)
+	} else if holder != "" {
+		log.Info("Waiting for another node to finish rotating certificates", "node", holder)
+		rotation.State = types.CertificateRotationWaiting
+		rotation.Message = fmt.Sprintf("waiting for node %s to finish rotating certificates", holder)
+		if err := setRotation(ctx, rotation); err != nil {
+			return fmt.Errorf("failed to record certificate rotation: %w", err)
+		}
+		return nil
+	}
+
+	update := func(state types.CertificateRotationState, message string) {
+		rotation.State = state
+		rotation.Message = message
+		rotation.UpdatedAt = time.Now()
+		if err := setRotation(ctx, rotation); err != nil {
+			log.Error(err, "Failed to record certificate rotation", "state", state)
+		}
+	}
+
+	log.Info("Renewing certificates")
+	refreshCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+	if err := refreshCertificates(refreshCtx, toCertificateNames(expiring), extraSANs, int(expiration.Seconds())); err != nil {
+		err = fmt.Errorf("failed to renew certificates: %w", err)
+		update(types.CertificateRotationFailed, err.Error())
+		return err
+	}
+
+	var services []string
+	for _, certificate := range expiring {
+		if certificate.service != "" && !slices.Contains(services, certificate.service) {
+			services = append(services, certificate.service)
+		}
+	}
+	for _, service := range services {
+		log.Info("Restarting service", "service", service)
+		update(types.CertificateRotationRestarting, fmt.Sprintf("restarting %s", service))
+		if err := restartService(ctx, c.snap, service); err != nil {
+			err = fmt.Errorf("failed to restart %s: %w", service, err)
+			update(types.CertificateRotationFailed, err.Error())
+			return err
+		}
+	}
+
+	log.Info("Rotated certificates")
+	update(types.CertificateRotationCompleted, "")
+	return nil
+}
+
+// canRenew returns true if the key of the CA that signs the certificate is available, either in the cluster
+// configuration or through the signer. The certificates of worker nodes are signed by the control plane nodes,
+// so any external signer is assumed to hold the CA keys.
+func (c *CertificateRotationController) canRenew(ctx context.Context, config types.ClusterConfig, certificate rotatedCertificate, isWorker bool) (bool, error) {
+	if certificate.caKey(config.Certificates) != "" {
+		return true, nil
+	}
+	if certificate.ca == "" || !csrsigning.UsesExternalSigner(config) {
+		return false, nil
+	}
+	if isWorker {
+		return true, nil
+	}
+
+	signer, release, err := c.signers.Get(config)
+	if err != nil {
+		return false, fmt.Errorf("failed to create CA signer: %w", err)
+	}
+	defer release()
+	if _, err := signer.Key(ctx, certificate.ca); err != nil {
+		if errors.Is(err, csrsigning.ErrMissingCAKey) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get %s key: %w", certificate.ca, err)
+	}
+	return true, nil
+}
+
+// loadRotatedCertificate reads the current certificate from the node.
+func loadRotatedCertificate(snap snap.Snap, certificate rotatedCertificate) (*x509.Certificate, error) {
+	var certPEM []byte
+	if certificate.kubeconfig {
+		kubeConfig, err := clientcmd.LoadFromFile(filepath.Join(snap.KubernetesConfigDir(), string(certificate.name)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load kubeconfig: %w", err)
+		}
+		authInfo, exists := kubeConfig.AuthInfos["k8s-user"]
+		if !exists {
+			return nil, fmt.Errorf("user 'k8s-user' not found in kubeconfig")
+		}
+		certPEM = authInfo.ClientCertificateData
+	} else {
+		b, err := os.ReadFile(filepath.Join(snap.KubernetesPKIDir(), fmt.Sprintf("%s.crt", certificate.name)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read certificate: %w", err)
+		}
+		certPEM = b
+	}
+
+	cert, _, err := pkiutil.LoadCertificate(string(certPEM), "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse certificate: %w", err)
+	}
+	return cert, nil
+}
+
+// restartService restarts a service of the node and waits for the kube-apiserver to become available again.
+func restartService(ctx context.Context, snap snap.Snap, service string) error {
+	if err := snap.RestartServices(ctx, []string{service}); err != nil {
+		return err
+	}
+	if service != "kube-apiserver" {
+		return nil
+	}
+
+	client, err := snap.KubernetesClient("")
+	if err != nil {
+		return fmt.Errorf("failed to create Kubernetes client: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+	if err := client.WaitKubernetesEndpointAvailable(ctx); err != nil {
+		return fmt.Errorf("kube-apiserver did not become available: %w", err)
+	}
+	return nil
+}
+
+// ReconciledCh returns the channel where the controller pushes when a reconciliation loop is finished.
+func (c *CertificateRotationController) ReconciledCh() <-chan struct{} {
+	return c.reconciledCh
+}
+
+func toCertificateNames(certificates []rotatedCertificate) []apiv1.CertificateName {
+	names := make([]apiv1.CertificateName, 0, len(certificates))
+	for _, certificate := range certificates {
+		names = append(names, certificate.name)
+	}
+	return names
+}
@@ -0,0 +1,219 @@
+package controllers_test
+
+import (
+	"context"
+	"crypto/x509/pkix"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	apiv1 "github.com/canonical/k8s-snap-api/api/v1"
+	"github.com/canonical/k8s/pkg/client/kubernetes"
+	"github.com/canonical/k8s/pkg/k8sd/controllers"
+	"github.com/canonical/k8s/pkg/k8sd/controllers/csrsigning"
+	"github.com/canonical/k8s/pkg/k8sd/setup"
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/k8s/pkg/snap/mock"
+	"github.com/canonical/k8s/pkg/utils"
+	pkiutil "github.com/canonical/k8s/pkg/utils/pki"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// writeRotatedCertificates writes the certificates of a node. The certificates in expiring are about to expire.
+func writeRotatedCertificates(g Gomega, s *mock.Snap, pkiFiles []string, kubeconfigs []string, expiring ...string) {
+	now := time.Now()
+	generate := func(name string) (string, string) {
+		notBefore, notAfter := now.Add(-time.Hour), now.AddDate(1, 0, 0)
+		for _, e := range expiring {
+			if e == name {
+				notBefore, notAfter = now.AddDate(-1, 0, 0), now.Add(time.Hour)
+			}
+		}
+		cert, err := pkiutil.GenerateCertificate(pkix.Name{CommonName: name}, notBefore, notAfter, false, []string{"node1"}, []net.IP{net.ParseIP("10.0.0.1")})
+		g.Expect(err).ToNot(HaveOccurred())
+		crtPEM, keyPEM, err := pkiutil.SignCertificate(cert, pkiutil.KeyAlgorithmRSA2048, cert, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		return crtPEM, keyPEM
+	}
+
+	g.Expect(os.MkdirAll(s.KubernetesPKIDir(), 0o700)).To(Succeed())
+	g.Expect(os.MkdirAll(s.KubernetesConfigDir(), 0o700)).To(Succeed())
+	for _, name := range pkiFiles {
+		crtPEM, _ := generate(name)
+		g.Expect(os.WriteFile(filepath.Join(s.KubernetesPKIDir(), fmt.Sprintf("%s.crt", name)), []byte(crtPEM), 0o600)).To(Succeed())
+	}
+	for _, name := range kubeconfigs {
+		crtPEM, keyPEM := generate(name)
+		g.Expect(setup.Kubeconfig(filepath.Join(s.KubernetesConfigDir(), name), "127.0.0.1:6443", crtPEM, crtPEM, keyPEM)).To(Succeed())
+	}
+}
+
+func TestCertificateRotationController(t *testing.T) {
+	config := types.ClusterConfig{
+		Certificates: types.Certificates{
+			CAKey: utils.Pointer("ca-key"),
+		},
+	}
+
+	type result struct {
+		refreshed []apiv1.CertificateName
+		extraSANs []string
+		rotations []types.CertificateRotation
+		s         *mock.Snap
+	}
+
+	run := func(t *testing.T, config types.ClusterConfig, worker bool, holder string, expiring ...string) result {
+		g := NewWithT(t)
+
+		dir := t.TempDir()
+		clientset := fake.NewSimpleClientset(&corev1.Endpoints{ObjectMeta: metav1.ObjectMeta{Name: "kubernetes", Namespace: "default"}})
+		s := &mock.Snap{
+			Mock: mock.Mock{
+				KubernetesPKIDir:    filepath.Join(dir, "pki"),
+				KubernetesConfigDir: filepath.Join(dir, "config"),
+				LockFilesDir:        filepath.Join(dir, "locks"),
+				KubernetesClient:    &kubernetes.Client{Interface: clientset},
+			},
+		}
+		g.Expect(os.MkdirAll(s.LockFilesDir(), 0o700)).To(Succeed())
+		if worker {
+			g.Expect(os.WriteFile(filepath.Join(s.LockFilesDir(), "worker"), nil, 0o600)).To(Succeed())
+			writeRotatedCertificates(g, s, []string{"kubelet"}, []string{"kubelet.conf", "proxy.conf"}, expiring...)
+		} else {
+			writeRotatedCertificates(g, s,
+				[]string{"apiserver", "apiserver-kubelet-client", "front-proxy-client", "kubelet"},
+				[]string{"admin.conf", "controller.conf", "scheduler.conf", "kubelet.conf", "proxy.conf"},
+				expiring...,
+			)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		triggerCh := make(chan time.Time)
+		ctrl := controllers.NewCertificateRotationController(s, func() {}, triggerCh, 30*24*time.Hour, csrsigning.NewSignerCache())
+
+		var r result
+		r.s = s
+		go ctrl.Run(
+			ctx,
+			func(context.Context) (types.ClusterConfig, error) { return config, nil },
+			func(_ context.Context, rotation types.CertificateRotation, _ time.Duration) (string, error) {
+				g.Expect(worker).To(BeFalse(), "worker nodes must not acquire the certificate rotation")
+				if holder == "" {
+					r.rotations = append(r.rotations, rotation)
+				}
+				return holder, nil
+			},
+			func(_ context.Context, rotation types.CertificateRotation) error {
+				r.rotations = append(r.rotations, rotation)
+				return nil
+			},
+			func(_ context.Context, certificates []apiv1.CertificateName, extraSANs []string, _ int) error {
+				r.refreshed = certificates
+				r.extraSANs = extraSANs
+				return nil
+			},
+		)
+
+		select {
+		case triggerCh <- time.Now():
+		case <-time.After(channelSendTimeout):
+			g.Fail("Timed out while attempting to trigger controller reconcile loop")
+		}
+		select {
+		case <-ctrl.ReconciledCh():
+		case <-time.After(5 * time.Second):
+			g.Fail("Time out while waiting for the reconcile to complete")
+		}
+		return r
+	}
+
+	t.Run("NotExpiring", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r := run(t, config, false, "")
+		g.Expect(r.refreshed).To(BeEmpty())
+		g.Expect(r.rotations).To(BeEmpty())
+		g.Expect(r.s.RestartServicesCalledWith).To(BeEmpty())
+	})
+
+	t.Run("ControlPlane", func(t *testing.T) {
+		g := NewWithT(t)
+
+		// front-proxy-client is signed by an external CA, as the front proxy CA key is not set.
+		r := run(t, config, false, "", "apiserver", "front-proxy-client", "scheduler.conf", "kubelet.conf")
+		g.Expect(r.refreshed).To(Equal([]apiv1.CertificateName{"apiserver", "scheduler.conf", "kubelet.conf"}))
+		g.Expect(r.extraSANs).To(ContainElements("node1", "10.0.0.1"))
+		g.Expect(r.s.RestartServicesCalledWith).To(Equal([][]string{{"kube-apiserver"}, {"kube-scheduler"}, {"kubelet"}}))
+
+		g.Expect(r.rotations).ToNot(BeEmpty())
+		g.Expect(r.rotations[0].State).To(Equal(types.CertificateRotationRenewing))
+		last := r.rotations[len(r.rotations)-1]
+		g.Expect(last.State).To(Equal(types.CertificateRotationCompleted))
+		g.Expect(last.Certificates).To(Equal([]string{"apiserver", "scheduler.conf", "kubelet.conf"}))
+	})
+
+	t.Run("Waiting", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r := run(t, config, false, "node2", "apiserver")
+		g.Expect(r.refreshed).To(BeEmpty())
+		g.Expect(r.s.RestartServicesCalledWith).To(BeEmpty())
+		g.Expect(r.rotations).To(HaveLen(1))
+		g.Expect(r.rotations[0].State).To(Equal(types.CertificateRotationWaiting))
+		g.Expect(r.rotations[0].Message).To(ContainSubstring("node2"))
+	})
+
+	t.Run("Worker", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r := run(t, config, true, "", "kubelet", "proxy.conf")
+		g.Expect(r.refreshed).To(Equal([]apiv1.CertificateName{"kubelet", "proxy.conf"}))
+		g.Expect(r.s.RestartServicesCalledWith).To(Equal([][]string{{"kubelet"}, {"kube-proxy"}}))
+		g.Expect(r.rotations[len(r.rotations)-1].State).To(Equal(types.CertificateRotationCompleted))
+	})
+	t.Run("ExternalSigner", func(t *testing.T) {
+		g := NewWithT(t)
+
+		caCert, _, err := pkiutil.GenerateSelfSignedCA(pkix.Name{CommonName: "kubernetes-ca"}, time.Now(), time.Now().AddDate(10, 0, 0), pkiutil.KeyAlgorithmECDSAP256)
+		g.Expect(err).ToNot(HaveOccurred())
+		config := types.ClusterConfig{
+			Certificates: types.Certificates{CACert: utils.Pointer(caCert)},
+			Annotations: types.Annotations{
+				csrsigning.AnnotationSigner:               csrsigning.SignerRemote,
+				csrsigning.AnnotationRemoteSignerEndpoint: "unix:///run/signer.sock",
+			},
+		}
+
+		// The CA keys are held by the remote signer. front-proxy-client is still signed by an external CA.
+		r := run(t, config, false, "", "apiserver", "front-proxy-client", "kubelet.conf")
+		g.Expect(r.refreshed).To(Equal([]apiv1.CertificateName{"apiserver", "kubelet.conf"}))
+	})
+
+	t.Run("ExternalSignerWorker", func(t *testing.T) {
+		g := NewWithT(t)
+
+		config := types.ClusterConfig{
+			Annotations: types.Annotations{csrsigning.AnnotationSigner: csrsigning.SignerPKCS11},
+		}
+
+		// The certificates of worker nodes are signed by the control plane nodes.
+		r := run(t, config, true, "", "kubelet", "proxy.conf")
+		g.Expect(r.refreshed).To(Equal([]apiv1.CertificateName{"kubelet", "proxy.conf"}))
+	})
+
+	t.Run("NoCAKey", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r := run(t, types.ClusterConfig{}, true, "", "kubelet", "proxy.conf")
+		g.Expect(r.refreshed).To(BeEmpty())
+		g.Expect(r.rotations).To(BeEmpty())
+	})
+}
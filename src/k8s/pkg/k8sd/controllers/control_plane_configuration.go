@@ -104,6 +104,20 @@ func (c *ControlPlaneConfigurationController) reconcile(ctx context.Context, con
 		}
 	}
 
+	// kube-apiserver: authentication
+	// kube-apiserver reloads the authentication config file when it changes, so only restart if the arguments changed.
+	updateArgs, deleteArgs, err := setup.KubeAPIServerAuthentication(c.snap, config.Authentication)
+	if err != nil {
+		return fmt.Errorf("failed to reconcile kube-apiserver authentication config: %w", err)
+	}
+	if mustRestart, err := snaputil.UpdateServiceArguments(c.snap, "kube-apiserver", updateArgs, deleteArgs); err != nil {
+		return fmt.Errorf("failed to update kube-apiserver authentication arguments: %w", err)
+	} else if mustRestart {
+		if err := c.snap.RestartServices(ctx, []string{"kube-apiserver"}); err != nil {
+			return fmt.Errorf("failed to restart kube-apiserver to apply authentication configuration: %w", err)
+		}
+	}
+
 	// kube-controller-manager: cloud-provider
 	if v := config.Kubelet.CloudProvider; v != nil {
 		mustRestart, err := snaputil.UpdateServiceArguments(c.snap, "kube-controller-manager", map[string]string{"--cloud-provider": *v}, nil)
@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -14,10 +15,13 @@ import (
 	"github.com/canonical/k8s/pkg/snap"
 	"github.com/canonical/k8s/pkg/utils"
 	"github.com/canonical/microcluster/v2/state"
+	"github.com/prometheus/client_golang/prometheus"
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/server"
 )
 
@@ -32,6 +36,10 @@ type Coordinator struct {
 
 	// CSR signing controller
 	disableCSRSigningController bool
+	signers                     *csrsigning.SignerCache
+
+	// metricsBindAddress is the address of the metrics endpoint. "0" disables the metrics endpoint.
+	metricsBindAddress string
 }
 
 // NewCoordinator creates a new Coordinator instance.
@@ -41,6 +49,8 @@ func NewCoordinator(
 	disableUpgradeController bool,
 	upgradeControllerOpts upgrade.ControllerOptions,
 	disableCSRSiningController bool,
+	signers *csrsigning.SignerCache,
+	metricsBindAddress string,
 ) *Coordinator {
 	return &Coordinator{
 		snap:                        snap,
@@ -48,14 +58,18 @@ func NewCoordinator(
 		disableUpgradeController:    disableUpgradeController,
 		upgradeControllerOpts:       upgradeControllerOpts,
 		disableCSRSigningController: disableCSRSiningController,
+		signers:                     signers,
+		metricsBindAddress:          metricsBindAddress,
 	}
 }
 
 // Run creates a manager, setup the controllers with the manager and starts the manager.
+// Run accepts a function that lists the certificate rotations of all nodes, which are exported as metrics.
 func (c *Coordinator) Run(
 	ctx context.Context,
 	getClusterConfig func(context.Context) (types.ClusterConfig, error),
 	getState func() state.State,
+	listCertificateRotations func(context.Context) ([]types.CertificateRotation, error),
 ) error {
 	logger := log.FromContext(ctx).WithName("controller-coordinator")
 	ctrllog.SetLogger(logger)
@@ -92,14 +106,21 @@ func (c *Coordinator) Run(
 		Cache: cache.Options{
 			SyncPeriod: utils.Pointer(10 * time.Minute),
 		},
+		// NOTE: The metrics endpoint is served over HTTPS and requires a token that is allowed to get "/metrics".
 		Metrics: server.Options{
-			BindAddress: "0",
+			BindAddress:    c.metricsBindAddress,
+			SecureServing:  true,
+			FilterProvider: filters.WithAuthenticationAndAuthorization,
 		},
 	})
 	if err != nil {
 		return fmt.Errorf("failed to create manager: %w", err)
 	}
 
+	if err := registerMetricsCollector(NewCertificateMetricsCollector(c.snap, logger.WithName("certificate-metrics"), getClusterConfig, listCertificateRotations)); err != nil {
+		return fmt.Errorf("failed to register certificate metrics: %w", err)
+	}
+
 	if err := c.setupControllers(ctx, getClusterConfig, getState, mgr); err != nil {
 		return fmt.Errorf("failed to setup controllers: %w", err)
 	}
@@ -180,6 +201,7 @@ func (c *Coordinator) setupCSRSigningController(
 		logger,
 		mgr.GetClient(),
 		getClusterConfig,
+		c.signers,
 	)
 
 	if err := csrsigningController.SetupWithManager(mgr); err != nil {
@@ -189,6 +211,20 @@ func (c *Coordinator) setupCSRSigningController(
 	return nil
 }
 
+// registerMetricsCollector registers a collector with the metrics registry of the manager.
+// Collectors that were registered by a previous run are replaced.
+func registerMetricsCollector(collector prometheus.Collector) error {
+	if err := metrics.Registry.Register(collector); err != nil {
+		var alreadyRegistered prometheus.AlreadyRegisteredError
+		if !errors.As(err, &alreadyRegistered) {
+			return err
+		}
+		metrics.Registry.Unregister(alreadyRegistered.ExistingCollector)
+		return metrics.Registry.Register(collector)
+	}
+	return nil
+}
+
 // featureUpgradesDisabled checks if feature upgrades are disabled in the cluster configuration.
 func featureUpgradesDisabled(clusterConfig types.ClusterConfig) bool {
 	_, ok := clusterConfig.Annotations.Get(apiv1_annotations.AnnotationDisableSeparateFeatureUpgrades)
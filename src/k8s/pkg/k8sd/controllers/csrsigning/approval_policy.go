@@ -0,0 +1,159 @@
+package csrsigning
+
+import (
+	"fmt"
+	"net"
+	"slices"
+
+	pkiutil "github.com/canonical/k8s/pkg/utils/pki"
+	certv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/yaml"
+)
+
+// AnnotationApprovalPolicy configures the policy for auto-approved CSRs, as a YAML or JSON object. For example:
+//
+//	verify-node-addresses: true
+//	max-expiration-seconds: 31536000
+//	rules:
+//	- group: system:nodes
+//	  signer-names: [k8sd.io/kubelet-serving, k8sd.io/kubelet-client, k8sd.io/kube-proxy-client]
+//	  usages: [digital signature, key encipherment, server auth, client auth]
+const AnnotationApprovalPolicy = "k8sd/v1alpha1/csrsigning/approval-policy"
+
+// approvalPolicy restricts the CSRs that are auto-approved, on top of the checks of validateCSR.
+type approvalPolicy struct {
+	// VerifyNodeAddresses requires the SANs of kubelet serving CSRs to match the name and addresses of the Node.
+	VerifyNodeAddresses bool `json:"verify-node-addresses,omitempty"`
+	// MaxExpirationSeconds caps the duration of the signed certificates. Zero means no limit.
+	MaxExpirationSeconds int32 `json:"max-expiration-seconds,omitempty"`
+	// Rules restrict the signer names and usages that groups can request.
+	// If set, a CSR is only approved if one of its groups has a matching rule.
+	Rules []approvalRule `json:"rules,omitempty"`
+}
+
+// approvalRule allows members of a group to request certificates from signers with the given usages.
+type approvalRule struct {
+	// Group is the group of the CSR requestor.
+	Group string `json:"group"`
+	// SignerNames are the allowed signer names. If empty, all signer names are allowed.
+	SignerNames []string `json:"signer-names,omitempty"`
+	// Usages are the allowed usages. If empty, all usages are allowed.
+	Usages []certv1.KeyUsage `json:"usages,omitempty"`
+}
+
+// policyViolation is a CSR denial by the approval policy. The reason is recorded in the Denied condition of the CSR.
+type policyViolation struct {
+	reason string
+	err    error
+}
+
+func (v *policyViolation) Error() string {
+	return v.err.Error()
+}
+
+// parseApprovalPolicy parses the approval policy from a YAML or JSON object.
+func parseApprovalPolicy(value string) (approvalPolicy, error) {
+	var policy approvalPolicy
+	if err := yaml.UnmarshalStrict([]byte(value), &policy); err != nil {
+		return approvalPolicy{}, fmt.Errorf("failed to parse approval policy: %w", err)
+	}
+	if policy.MaxExpirationSeconds < 0 {
+		return approvalPolicy{}, fmt.Errorf("max-expiration-seconds must not be negative")
+	}
+	for i, rule := range policy.Rules {
+		if rule.Group == "" {
+			return approvalPolicy{}, fmt.Errorf("rule %d has no group", i)
+		}
+	}
+	return policy, nil
+}
+
+// validateApprovalPolicy checks the CSR against the approval policy and returns a *policyViolation if it fails.
+// validateApprovalPolicy expects a CSR that passed validateCSR.
+// node is the Node of the requestor, which is only needed to verify the node addresses. A nil node fails verification.
+func validateApprovalPolicy(obj *certv1.CertificateSigningRequest, policy approvalPolicy, node *corev1.Node) error {
+	if err := validateApprovalRules(obj, policy.Rules); err != nil {
+		return err
+	}
+
+	if policy.VerifyNodeAddresses && obj.Spec.SignerName == "k8sd.io/kubelet-serving" {
+		if err := validateNodeAddresses(obj, node); err != nil {
+			return &policyViolation{reason: nodeAddressesDeniedReason, err: err}
+		}
+	}
+	return nil
+}
+
+// validateApprovalRules checks that one of the groups of the CSR may request the signer name and usages.
+func validateApprovalRules(obj *certv1.CertificateSigningRequest, rules []approvalRule) error {
+	if len(rules) == 0 {
+		return nil
+	}
+
+	groups := sets.New(obj.Spec.Groups...)
+	usages := sets.New(obj.Spec.Usages...)
+	var signerAllowed bool
+	for _, rule := range rules {
+		if !groups.Has(rule.Group) {
+			continue
+		}
+		if len(rule.SignerNames) > 0 && !slices.Contains(rule.SignerNames, obj.Spec.SignerName) {
+			continue
+		}
+		signerAllowed = true
+		if len(rule.Usages) == 0 || sets.New(rule.Usages...).IsSuperset(usages) {
+			return nil
+		}
+	}
+
+	if !signerAllowed {
+		return &policyViolation{
+			reason: signerNameDeniedReason,
+			err:    fmt.Errorf("groups %v are not allowed to request certificates from signer %s", obj.Spec.Groups, obj.Spec.SignerName),
+		}
+	}
+	return &policyViolation{
+		reason: usagesDeniedReason,
+		err:    fmt.Errorf("groups %v are not allowed to request usages %v from signer %s", obj.Spec.Groups, obj.Spec.Usages, obj.Spec.SignerName),
+	}
+}
+
+// validateNodeAddresses checks that the SANs of the CSR are the name, DNS names or IP addresses of the node.
+// Loopback addresses are always allowed.
+func validateNodeAddresses(obj *certv1.CertificateSigningRequest, node *corev1.Node) error {
+	if node == nil {
+		return fmt.Errorf("node %s not found", obj.GetAnnotations()["k8sd.io/node"])
+	}
+
+	csr, err := pkiutil.LoadCertificateRequest(string(obj.Spec.Request))
+	if err != nil {
+		return fmt.Errorf("failed to parse x509 certificate request: %w", err)
+	}
+
+	dnsNames := sets.New(node.Name)
+	ips := sets.New(net.IPv4(127, 0, 0, 1).String(), net.IPv6loopback.String())
+	for _, address := range node.Status.Addresses {
+		switch address.Type {
+		case corev1.NodeHostName, corev1.NodeInternalDNS, corev1.NodeExternalDNS:
+			dnsNames.Insert(address.Address)
+		case corev1.NodeInternalIP, corev1.NodeExternalIP:
+			if ip := net.ParseIP(address.Address); ip != nil {
+				ips.Insert(ip.String())
+			}
+		}
+	}
+
+	for _, dnsName := range csr.DNSNames {
+		if !dnsNames.Has(dnsName) {
+			return fmt.Errorf("DNS name %s is not an address of node %s", dnsName, node.Name)
+		}
+	}
+	for _, ip := range csr.IPAddresses {
+		if !ips.Has(ip.String()) {
+			return fmt.Errorf("IP address %s is not an address of node %s", ip, node.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+package csrsigning
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509/pkix"
+	"net"
+	"testing"
+
+	pkiutil "github.com/canonical/k8s/pkg/utils/pki"
+	. "github.com/onsi/gomega"
+	certv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParseApprovalPolicy(t *testing.T) {
+	t.Run("YAML", func(t *testing.T) {
+		g := NewWithT(t)
+
+		policy, err := parseApprovalPolicy(`
+verify-node-addresses: true
+max-expiration-seconds: 3600
+rules:
+- group: system:nodes
+  signer-names: [k8sd.io/kubelet-serving]
+  usages: [digital signature, server auth]
+`)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(policy).To(Equal(approvalPolicy{
+			VerifyNodeAddresses:  true,
+			MaxExpirationSeconds: 3600,
+			Rules: []approvalRule{{
+				Group:       "system:nodes",
+				SignerNames: []string{"k8sd.io/kubelet-serving"},
+				Usages:      []certv1.KeyUsage{certv1.UsageDigitalSignature, certv1.UsageServerAuth},
+			}},
+		}))
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		g := NewWithT(t)
+
+		policy, err := parseApprovalPolicy(`{"max-expiration-seconds": 600}`)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(policy).To(Equal(approvalPolicy{MaxExpirationSeconds: 600}))
+	})
+
+	for _, tc := range []struct {
+		name  string
+		value string
+	}{
+		{name: "UnknownField", value: "verify-addresses: true"},
+		{name: "NegativeExpiration", value: "max-expiration-seconds: -1"},
+		{name: "MissingGroup", value: "rules: [{signer-names: [k8sd.io/kubelet-serving]}]"},
+		{name: "NotAnObject", value: "true"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			_, err := parseApprovalPolicy(tc.value)
+			g.Expect(err).To(HaveOccurred())
+		})
+	}
+}
+
+func TestValidateApprovalPolicy(t *testing.T) {
+	g := NewWithT(t)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// newCSR returns a CSR that passes validateCSR.
+	newCSR := func(signerName string, dnsNames []string, ips []net.IP) *certv1.CertificateSigningRequest {
+		subject := pkix.Name{CommonName: "system:node:valid-node", Organization: []string{"system:nodes"}}
+		usages := []certv1.KeyUsage{certv1.UsageDigitalSignature, certv1.UsageKeyEncipherment, certv1.UsageClientAuth}
+		switch signerName {
+		case "k8sd.io/kubelet-serving":
+			usages = []certv1.KeyUsage{certv1.UsageDigitalSignature, certv1.UsageKeyEncipherment, certv1.UsageServerAuth}
+		case "k8sd.io/kube-proxy-client":
+			subject = pkix.Name{CommonName: "system:kube-proxy"}
+		}
+		csrPEM, _, err := pkiutil.GenerateCSR(subject, pkiutil.KeyAlgorithmRSA2048, dnsNames, ips)
+		g.Expect(err).NotTo(HaveOccurred())
+
+		obj := &certv1.CertificateSigningRequest{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					"k8sd.io/signature": mustCreateEncryptedSignature(g, &key.PublicKey, csrPEM),
+					"k8sd.io/node":      "valid-node",
+				},
+			},
+			Spec: certv1.CertificateSigningRequestSpec{
+				Request:    []byte(csrPEM),
+				Username:   "system:node:valid-node",
+				Groups:     []string{"system:nodes", "system:authenticated"},
+				SignerName: signerName,
+				Usages:     usages,
+			},
+		}
+		g.Expect(validateCSR(obj, key)).To(Succeed())
+		return obj
+	}
+
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "valid-node"},
+		Status: corev1.NodeStatus{
+			Addresses: []corev1.NodeAddress{
+				{Type: corev1.NodeInternalIP, Address: "10.0.0.10"},
+				{Type: corev1.NodeExternalIP, Address: "2001:db8::10"},
+				{Type: corev1.NodeHostName, Address: "valid-node.example.com"},
+			},
+		},
+	}
+
+	nodeRules := []approvalRule{
+		{Group: "system:nodes", SignerNames: []string{"k8sd.io/kubelet-serving"}, Usages: []certv1.KeyUsage{certv1.UsageDigitalSignature, certv1.UsageKeyEncipherment, certv1.UsageServerAuth}},
+		{Group: "system:nodes", SignerNames: []string{"k8sd.io/kubelet-client"}},
+	}
+
+	for _, tc := range []struct {
+		name         string
+		csr          *certv1.CertificateSigningRequest
+		policy       approvalPolicy
+		node         *corev1.Node
+		expectReason string
+	}{
+		{
+			name: "EmptyPolicy",
+			csr:  newCSR("k8sd.io/kubelet-serving", []string{"other-name"}, []net.IP{net.ParseIP("192.168.1.1")}),
+		},
+		{
+			name:   "RuleAllowsSigner",
+			csr:    newCSR("k8sd.io/kubelet-client", nil, nil),
+			policy: approvalPolicy{Rules: nodeRules},
+		},
+		{
+			name:         "NoRuleForSigner",
+			csr:          newCSR("k8sd.io/kube-proxy-client", nil, nil),
+			policy:       approvalPolicy{Rules: nodeRules},
+			expectReason: signerNameDeniedReason,
+		},
+		{
+			name:         "NoRuleForGroup",
+			csr:          newCSR("k8sd.io/kubelet-client", nil, nil),
+			policy:       approvalPolicy{Rules: []approvalRule{{Group: "system:masters"}}},
+			expectReason: signerNameDeniedReason,
+		},
+		{
+			name: "UsagesNotAllowed",
+			csr:  newCSR("k8sd.io/kubelet-serving", nil, nil),
+			policy: approvalPolicy{Rules: []approvalRule{
+				{Group: "system:nodes", SignerNames: []string{"k8sd.io/kubelet-serving"}, Usages: []certv1.KeyUsage{certv1.UsageDigitalSignature, certv1.UsageServerAuth}},
+			}},
+			expectReason: usagesDeniedReason,
+		},
+		{
+			name:   "NodeAddresses",
+			csr:    newCSR("k8sd.io/kubelet-serving", []string{"valid-node", "valid-node.example.com"}, []net.IP{net.ParseIP("10.0.0.10"), net.ParseIP("2001:db8::10"), net.ParseIP("127.0.0.1"), net.ParseIP("::1")}),
+			policy: approvalPolicy{VerifyNodeAddresses: true, Rules: nodeRules},
+			node:   node,
+		},
+		{
+			name:         "UnknownDNSName",
+			csr:          newCSR("k8sd.io/kubelet-serving", []string{"valid-node", "evil.example.com"}, nil),
+			policy:       approvalPolicy{VerifyNodeAddresses: true},
+			node:         node,
+			expectReason: nodeAddressesDeniedReason,
+		},
+		{
+			name:         "UnknownIP",
+			csr:          newCSR("k8sd.io/kubelet-serving", []string{"valid-node"}, []net.IP{net.ParseIP("10.0.0.11")}),
+			policy:       approvalPolicy{VerifyNodeAddresses: true},
+			node:         node,
+			expectReason: nodeAddressesDeniedReason,
+		},
+		{
+			name:         "NodeNotFound",
+			csr:          newCSR("k8sd.io/kubelet-serving", []string{"valid-node"}, nil),
+			policy:       approvalPolicy{VerifyNodeAddresses: true},
+			expectReason: nodeAddressesDeniedReason,
+		},
+		{
+			name:   "NodeAddressesIgnoredForClientCertificates",
+			csr:    newCSR("k8sd.io/kubelet-client", nil, nil),
+			policy: approvalPolicy{VerifyNodeAddresses: true},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			err := validateApprovalPolicy(tc.csr, tc.policy, tc.node)
+			if tc.expectReason == "" {
+				g.Expect(err).ToNot(HaveOccurred())
+				return
+			}
+
+			var violation *policyViolation
+			g.Expect(err).To(BeAssignableToTypeOf(violation))
+			g.Expect(err.(*policyViolation).reason).To(Equal(tc.expectReason))
+		})
+	}
+}
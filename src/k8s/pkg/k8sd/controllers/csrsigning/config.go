@@ -1,18 +1,28 @@
 package csrsigning
 
 import (
+	"fmt"
+
 	apiv1_annotations "github.com/canonical/k8s-snap-api/api/v1/annotations/csrsigning"
 	"github.com/canonical/k8s/pkg/k8sd/types"
 )
 
 type internalConfig struct {
-	autoApprove bool
+	autoApprove    bool
+	approvalPolicy approvalPolicy
 }
 
-func internalConfigFromAnnotations(annotations types.Annotations) internalConfig {
+func internalConfigFromAnnotations(annotations types.Annotations) (internalConfig, error) {
 	var cfg internalConfig
 	if v, ok := annotations.Get(apiv1_annotations.AnnotationAutoApprove); ok && v == "true" {
 		cfg.autoApprove = true
 	}
-	return cfg
+	if v, ok := annotations.Get(AnnotationApprovalPolicy); ok {
+		policy, err := parseApprovalPolicy(v)
+		if err != nil {
+			return internalConfig{}, fmt.Errorf("invalid %s annotation: %w", AnnotationApprovalPolicy, err)
+		}
+		cfg.approvalPolicy = policy
+	}
+	return cfg, nil
 }
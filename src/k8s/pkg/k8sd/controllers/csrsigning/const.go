@@ -16,4 +16,22 @@ const (
 	// missingKeyFailedMessage provides the failure message used when the
 	// controller is unable to sign the CSR due to a missing CA private key.
 	missingKeyFailedMessage = "The CSR could not be signed because the controller is missing the CA private key."
+
+	// approvedReason is the reason of the Approved condition of auto-approved CSRs.
+	approvedReason = "K8sdApprove"
+
+	// deniedReason is the reason of the Denied condition of CSRs that are not valid.
+	deniedReason = "K8sdDeny"
+
+	// signerNameDeniedReason is the reason of the Denied condition of CSRs for a signer name that the approval
+	// policy does not allow for the groups of the requestor.
+	signerNameDeniedReason = "K8sdDenySignerName"
+
+	// usagesDeniedReason is the reason of the Denied condition of CSRs with usages that the approval policy does
+	// not allow for the groups of the requestor.
+	usagesDeniedReason = "K8sdDenyUsages"
+
+	// nodeAddressesDeniedReason is the reason of the Denied condition of kubelet serving CSRs with SANs that do
+	// not match the addresses of the Node.
+	nodeAddressesDeniedReason = "K8sdDenyNodeAddresses"
 )
@@ -18,13 +18,15 @@ type Controller struct {
 	client               client.Client
 	managedSignerNames   map[string]struct{}
 	getClusterConfig     func(context.Context) (types.ClusterConfig, error)
-	reconcileAutoApprove func(context.Context, log.Logger, *certv1.CertificateSigningRequest, *rsa.PrivateKey, client.Client) (ctrl.Result, error)
+	signers              *SignerCache
+	reconcileAutoApprove func(context.Context, log.Logger, *certv1.CertificateSigningRequest, *rsa.PrivateKey, approvalPolicy, client.Client) (ctrl.Result, error)
 }
 
 func NewController(
 	logger logr.Logger,
 	client client.Client,
 	getClusterConfig func(context.Context) (types.ClusterConfig, error),
+	signers *SignerCache,
 ) *Controller {
 	return &Controller{
 		logger: logger,
@@ -35,6 +37,7 @@ func NewController(
 			"k8sd.io/kube-proxy-client": {},
 		},
 		getClusterConfig:     getClusterConfig,
+		signers:              signers,
 		reconcileAutoApprove: reconcileAutoApprove,
 	}
 }
@@ -6,9 +6,11 @@ import (
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/pem"
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/canonical/k8s/pkg/log"
 	"github.com/canonical/k8s/pkg/utils"
 	pkiutil "github.com/canonical/k8s/pkg/utils/pki"
 	certv1 "k8s.io/api/certificates/v1"
@@ -63,7 +65,11 @@ func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		log.Error(err, "Failed to retrieve k8sd cluster configuration")
 		return ctrl.Result{}, err
 	}
-	internal := internalConfigFromAnnotations(config.Annotations)
+	internal, err := internalConfigFromAnnotations(config.Annotations)
+	if err != nil {
+		log.Error(err, "Failed to parse CSR signing configuration")
+		return ctrl.Result{}, err
+	}
 
 	if !approved {
 		log.Info("CSR is not approved")
@@ -79,7 +85,7 @@ func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 			if err != nil {
 				return ctrl.Result{}, fmt.Errorf("failed to load cluster RSA key: %w", err)
 			}
-			return r.reconcileAutoApprove(ctx, log, obj, priv, r.client)
+			return r.reconcileAutoApprove(ctx, log, obj, priv, internal.approvalPolicy, r.client)
 		}
 
 		log.Info("Requeue while waiting for CSR to be approved")
@@ -108,22 +114,31 @@ func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		notAfter = time.Now().AddDate(10, 0, 0)
 	}
 
+	// NOTE: The approval policy caps the duration of signed certificates, including for manually approved CSRs.
+	if maxSeconds := internal.approvalPolicy.MaxExpirationSeconds; maxSeconds > 0 {
+		if maxNotAfter := utils.SecondsToExpirationDate(notBefore, int(maxSeconds)); notAfter.After(maxNotAfter) {
+			notAfter = maxNotAfter
+		}
+	}
+
+	signer, release, err := r.signers.Get(config)
+	if err != nil {
+		log.Error(err, "Failed to create CA signer")
+		return ctrl.Result{}, err
+	}
+	defer release()
+
 	var crtPEM []byte
 	switch obj.Spec.SignerName {
 	case "k8sd.io/kubelet-serving":
-		caCert, caKey, err := pkiutil.LoadCertificate(config.Certificates.GetCACert(), config.Certificates.GetCAKey())
+		caCert, _, err := pkiutil.LoadCertificate(config.Certificates.GetCACert(), "")
 		if err != nil {
-			log.Error(err, "Failed to load CA certificate and key")
+			log.Error(err, "Failed to load CA certificate")
 			return ctrl.Result{}, err
 		}
-		if caKey == nil {
-			log.Error(err, "Cannot sign certificate as CA private key is not available")
-			setFailedCSR(obj, missingKeyFailedReason, missingKeyFailedMessage)
-			if err := r.client.Status().Update(ctx, obj); err != nil {
-				log.Error(err, "Failed to update failed CSR")
-				return ctrl.Result{}, err
-			}
-			return ctrl.Result{}, nil
+		caKey, err := signer.Key(ctx, CAKubernetes)
+		if err != nil {
+			return r.handleSignerError(ctx, log, obj, err)
 		}
 		cert := &x509.Certificate{
 			SerialNumber: serialNumber,
@@ -137,13 +152,12 @@ func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 			DNSNames:              certRequest.DNSNames,
 			BasicConstraintsValid: true,
 			ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
-			KeyUsage:              x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+			KeyUsage:              pkiutil.LeafKeyUsage(certRequest.PublicKey),
 		}
 
 		derBytes, err := x509.CreateCertificate(rand.Reader, cert, caCert, certRequest.PublicKey, caKey)
 		if err != nil {
-			log.Error(err, "Failed to sign certificate")
-			return ctrl.Result{}, err
+			return r.handleSignerError(ctx, log, obj, err)
 		}
 		crtPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: derBytes})
 		if crtPEM == nil {
@@ -151,19 +165,14 @@ func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 			return ctrl.Result{RequeueAfter: requeueAfterSigningFailure}, nil
 		}
 	case "k8sd.io/kubelet-client":
-		caCert, caKey, err := pkiutil.LoadCertificate(config.Certificates.GetClientCACert(), config.Certificates.GetClientCAKey())
+		caCert, _, err := pkiutil.LoadCertificate(config.Certificates.GetClientCACert(), "")
 		if err != nil {
-			log.Error(err, "Failed to load client CA certificate and key")
+			log.Error(err, "Failed to load client CA certificate")
 			return ctrl.Result{}, err
 		}
-		if caKey == nil {
-			log.Error(err, "Cannot sign certificate as client CA private key is not available")
-			setFailedCSR(obj, missingKeyFailedReason, missingKeyFailedMessage)
-			if err := r.client.Status().Update(ctx, obj); err != nil {
-				log.Error(err, "Failed to update failed CSR")
-				return ctrl.Result{}, err
-			}
-			return ctrl.Result{}, nil
+		caKey, err := signer.Key(ctx, CAKubernetesClient)
+		if err != nil {
+			return r.handleSignerError(ctx, log, obj, err)
 		}
 		cert := &x509.Certificate{
 			SerialNumber: serialNumber,
@@ -175,13 +184,12 @@ func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 			NotAfter:              notAfter,
 			BasicConstraintsValid: true,
 			ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
-			KeyUsage:              x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+			KeyUsage:              pkiutil.LeafKeyUsage(certRequest.PublicKey),
 		}
 
 		derBytes, err := x509.CreateCertificate(rand.Reader, cert, caCert, certRequest.PublicKey, caKey)
 		if err != nil {
-			log.Error(err, "Failed to sign certificate")
-			return ctrl.Result{}, err
+			return r.handleSignerError(ctx, log, obj, err)
 		}
 		crtPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: derBytes})
 		if crtPEM == nil {
@@ -189,19 +197,14 @@ func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 			return ctrl.Result{RequeueAfter: requeueAfterSigningFailure}, nil
 		}
 	case "k8sd.io/kube-proxy-client":
-		caCert, caKey, err := pkiutil.LoadCertificate(config.Certificates.GetClientCACert(), config.Certificates.GetClientCAKey())
+		caCert, _, err := pkiutil.LoadCertificate(config.Certificates.GetClientCACert(), "")
 		if err != nil {
-			log.Error(err, "Failed to load client CA certificate and key")
+			log.Error(err, "Failed to load client CA certificate")
 			return ctrl.Result{}, err
 		}
-		if caKey == nil {
-			log.Error(err, "Cannot sign certificate as client CA private key is not available")
-			setFailedCSR(obj, missingKeyFailedReason, missingKeyFailedMessage)
-			if err := r.client.Status().Update(ctx, obj); err != nil {
-				log.Error(err, "Failed to update failed CSR")
-				return ctrl.Result{}, err
-			}
-			return ctrl.Result{}, nil
+		caKey, err := signer.Key(ctx, CAKubernetesClient)
+		if err != nil {
+			return r.handleSignerError(ctx, log, obj, err)
 		}
 		cert := &x509.Certificate{
 			SerialNumber: serialNumber,
@@ -212,13 +215,12 @@ func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 			NotAfter:              notAfter,
 			BasicConstraintsValid: true,
 			ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
-			KeyUsage:              x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+			KeyUsage:              pkiutil.LeafKeyUsage(certRequest.PublicKey),
 		}
 
 		derBytes, err := x509.CreateCertificate(rand.Reader, cert, caCert, certRequest.PublicKey, caKey)
 		if err != nil {
-			log.Error(err, "Failed to sign certificate")
-			return ctrl.Result{}, err
+			return r.handleSignerError(ctx, log, obj, err)
 		}
 		crtPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: derBytes})
 		if crtPEM == nil {
@@ -241,6 +243,23 @@ func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	return ctrl.Result{}, nil
 }
 
+// handleSignerError marks the CSR as failed if the CA private key is not available to the signer.
+// Other errors are returned, so that the CSR is retried.
+func (r *Controller) handleSignerError(ctx context.Context, log log.Logger, obj *certv1.CertificateSigningRequest, err error) (ctrl.Result, error) {
+	if !errors.Is(err, ErrMissingCAKey) {
+		log.Error(err, "Failed to sign certificate")
+		return ctrl.Result{}, err
+	}
+
+	log.Error(err, "Cannot sign certificate as CA private key is not available")
+	setFailedCSR(obj, missingKeyFailedReason, missingKeyFailedMessage)
+	if err := r.client.Status().Update(ctx, obj); err != nil {
+		log.Error(err, "Failed to update failed CSR")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
 func setFailedCSR(csr *certv1.CertificateSigningRequest, reason string, message string) {
 	failedCondition := certv1.CertificateSigningRequestCondition{
 		Type:           certv1.CertificateFailed,
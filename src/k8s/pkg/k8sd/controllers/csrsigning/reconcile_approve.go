@@ -3,17 +3,20 @@ package csrsigning
 import (
 	"context"
 	"crypto/rsa"
+	"errors"
 	"fmt"
 
 	"github.com/canonical/k8s/pkg/log"
 	certv1 "k8s.io/api/certificates/v1"
 	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 func reconcileAutoApprove(ctx context.Context, log log.Logger, csr *certv1.CertificateSigningRequest,
-	priv *rsa.PrivateKey, client client.Client,
+	priv *rsa.PrivateKey, policy approvalPolicy, client client.Client,
 ) (ctrl.Result, error) {
 	var result certv1.RequestConditionType
 
@@ -25,20 +28,52 @@ func reconcileAutoApprove(ctx context.Context, log log.Logger, csr *certv1.Certi
 			certv1.CertificateSigningRequestCondition{
 				Type:    certv1.CertificateDenied,
 				Status:  v1.ConditionTrue,
-				Reason:  "K8sdDeny",
+				Reason:  deniedReason,
 				Message: fmt.Sprintf("CSR is not valid: %v", err.Error()),
 			},
 		)
 	} else {
-		result = certv1.CertificateApproved
-		csr.Status.Conditions = append(csr.Status.Conditions,
-			certv1.CertificateSigningRequestCondition{
-				Type:    certv1.CertificateApproved,
-				Status:  v1.ConditionTrue,
-				Reason:  "K8sdApprove",
-				Message: "CSR approved by k8sd",
-			},
-		)
+		var node *v1.Node
+		if policy.VerifyNodeAddresses && csr.Spec.SignerName == "k8sd.io/kubelet-serving" {
+			node = &v1.Node{}
+			if err := client.Get(ctx, types.NamespacedName{Name: csr.GetAnnotations()["k8sd.io/node"]}, node); err != nil {
+				if !apierrors.IsNotFound(err) {
+					log.Error(err, "Failed to get node of CSR requestor")
+					return ctrl.Result{}, err
+				}
+				node = nil
+			}
+		}
+
+		if err := validateApprovalPolicy(csr, policy, node); err != nil {
+			log.Error(err, "CSR is not allowed by the approval policy")
+
+			reason := deniedReason
+			var violation *policyViolation
+			if errors.As(err, &violation) {
+				reason = violation.reason
+			}
+
+			result = certv1.CertificateDenied
+			csr.Status.Conditions = append(csr.Status.Conditions,
+				certv1.CertificateSigningRequestCondition{
+					Type:    certv1.CertificateDenied,
+					Status:  v1.ConditionTrue,
+					Reason:  reason,
+					Message: fmt.Sprintf("CSR is not allowed by the approval policy: %v", err.Error()),
+				},
+			)
+		} else {
+			result = certv1.CertificateApproved
+			csr.Status.Conditions = append(csr.Status.Conditions,
+				certv1.CertificateSigningRequestCondition{
+					Type:    certv1.CertificateApproved,
+					Status:  v1.ConditionTrue,
+					Reason:  approvedReason,
+					Message: "CSR approved by k8sd",
+				},
+			)
+		}
 	}
 
 	log = log.WithValues("result", result)
@@ -29,7 +29,7 @@ func TestAutoApprove(t *testing.T) {
 			CommonName:   "system:node:valid-node",
 			Organization: []string{"system:nodes"},
 		},
-		2048,
+		pkiutil.KeyAlgorithmRSA2048,
 		nil,
 		nil,
 	)
@@ -38,6 +38,7 @@ func TestAutoApprove(t *testing.T) {
 	for _, tc := range []struct {
 		name      string
 		csr       certv1.CertificateSigningRequest
+		policy    approvalPolicy
 		updateErr error
 
 		expectResult    ctrl.Result
@@ -120,6 +121,32 @@ func TestAutoApprove(t *testing.T) {
 				Reason: "K8sdApprove",
 			},
 		},
+		{
+			name: "ValidCSR/DeniedByPolicy",
+			csr: certv1.CertificateSigningRequest{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						"k8sd.io/signature": mustCreateEncryptedSignature(g, &key.PublicKey, csrPEM),
+						"k8sd.io/node":      "valid-node",
+					},
+				},
+				Spec: certv1.CertificateSigningRequestSpec{
+					Request:    []byte(csrPEM),
+					Username:   "system:node:valid-node",
+					Groups:     []string{"system:nodes"},
+					SignerName: "k8sd.io/kubelet-serving",
+					Usages:     []certv1.KeyUsage{certv1.UsageServerAuth, certv1.UsageDigitalSignature, certv1.UsageKeyEncipherment},
+				},
+			},
+			policy: approvalPolicy{Rules: []approvalRule{{Group: "system:nodes", SignerNames: []string{"k8sd.io/kubelet-client"}}}},
+
+			expectResult: ctrl.Result{},
+			expectCondition: certv1.CertificateSigningRequestCondition{
+				Type:   certv1.CertificateDenied,
+				Status: v1.ConditionTrue,
+				Reason: "K8sdDenySignerName",
+			},
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			k8sM := k8smock.New(
@@ -134,6 +161,7 @@ func TestAutoApprove(t *testing.T) {
 				log.L(),
 				&tc.csr,
 				key,
+				tc.policy,
 				k8sM,
 			)
 
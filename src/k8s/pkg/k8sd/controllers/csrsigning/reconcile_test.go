@@ -229,6 +229,7 @@ func TestFailedToGetClusterConfig(t *testing.T) {
 		getClusterConfig: func(context.Context) (types.ClusterConfig, error) {
 			return types.ClusterConfig{}, getCCErr
 		},
+		signers: NewSignerCache(),
 	}
 
 	g := NewWithT(t)
@@ -261,6 +262,7 @@ func TestNotApprovedCSR(t *testing.T) {
 			getClusterConfig: func(context.Context) (types.ClusterConfig, error) {
 				return types.ClusterConfig{}, nil
 			},
+			signers: NewSignerCache(),
 		}
 
 		g := NewWithT(t)
@@ -305,7 +307,8 @@ func TestNotApprovedCSR(t *testing.T) {
 					},
 				}, nil
 			},
-			reconcileAutoApprove: func(ctx context.Context, l log.Logger, csr *certv1.CertificateSigningRequest, pk *rsa.PrivateKey, c client.Client) (ctrl.Result, error) {
+			signers: NewSignerCache(),
+			reconcileAutoApprove: func(ctx context.Context, l log.Logger, csr *certv1.CertificateSigningRequest, pk *rsa.PrivateKey, p approvalPolicy, c client.Client) (ctrl.Result, error) {
 				called = true
 				return ctrl.Result{}, nil
 			},
@@ -349,6 +352,7 @@ func TestInvalidCSR(t *testing.T) {
 				Certificates: types.Certificates{},
 			}, nil
 		},
+		signers: NewSignerCache(),
 	}
 
 	result, err := reconciler.Reconcile(context.Background(), getDefaultRequest())
@@ -364,7 +368,7 @@ func TestInvalidCACertificate(t *testing.T) {
 			CommonName:   "system:node:valid-node",
 			Organization: []string{"system:nodes"},
 		},
-		2048,
+		pkiutil.KeyAlgorithmRSA2048,
 		nil,
 		nil,
 	)
@@ -406,6 +410,7 @@ func TestInvalidCACertificate(t *testing.T) {
 				},
 			}, nil
 		},
+		signers: NewSignerCache(),
 	}
 
 	result, err := reconciler.Reconcile(context.Background(), getDefaultRequest())
@@ -420,7 +425,7 @@ func TestUpdateCSRFailed(t *testing.T) {
 			CommonName:   "system:node:valid-node",
 			Organization: []string{"system:nodes"},
 		},
-		2048,
+		pkiutil.KeyAlgorithmRSA2048,
 		nil,
 		nil,
 	)
@@ -453,7 +458,7 @@ func TestUpdateCSRFailed(t *testing.T) {
 		nil,
 	)
 
-	caCert, caKey, err := pkiutil.GenerateSelfSignedCA(pkix.Name{CommonName: "kubernetes-ca"}, time.Now(), time.Now().AddDate(10, 0, 0), 2048)
+	caCert, caKey, err := pkiutil.GenerateSelfSignedCA(pkix.Name{CommonName: "kubernetes-ca"}, time.Now(), time.Now().AddDate(10, 0, 0), pkiutil.KeyAlgorithmRSA2048)
 	g.Expect(err).ToNot(HaveOccurred())
 
 	reconciler := &Controller{
@@ -469,6 +474,7 @@ func TestUpdateCSRFailed(t *testing.T) {
 				},
 			}, nil
 		},
+		signers: NewSignerCache(),
 	}
 
 	result, err := reconciler.Reconcile(context.Background(), getDefaultRequest())
@@ -479,12 +485,185 @@ func TestUpdateCSRFailed(t *testing.T) {
 }
 
 func TestUpdateCSRSucceed(t *testing.T) {
+	for _, algorithm := range []pkiutil.KeyAlgorithm{pkiutil.KeyAlgorithmRSA2048, pkiutil.KeyAlgorithmECDSAP256, pkiutil.KeyAlgorithmEd25519} {
+		t.Run(string(algorithm), func(t *testing.T) {
+			csrPEM, _, err := pkiutil.GenerateCSR(
+				pkix.Name{
+					CommonName:   "system:node:valid-node",
+					Organization: []string{"system:nodes"},
+				},
+				algorithm,
+				nil,
+				nil,
+			)
+
+			g := NewWithT(t)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			managedSigner := "k8sd.io/kubelet-serving"
+			csr := certv1.CertificateSigningRequest{
+				Spec: certv1.CertificateSigningRequestSpec{
+					SignerName: managedSigner,
+					Request:    []byte(csrPEM),
+				},
+				Status: certv1.CertificateSigningRequestStatus{
+					Conditions: []certv1.CertificateSigningRequestCondition{
+						{
+							Type: certv1.CertificateApproved,
+						},
+					},
+				},
+			}
+
+			k8sM := k8smock.New(
+				t,
+				k8smock.NewSubResourceClientMock(nil),
+				csr,
+				nil,
+			)
+
+			caCert, caKey, err := pkiutil.GenerateSelfSignedCA(pkix.Name{CommonName: "kubernetes-ca"}, time.Now(), time.Now().AddDate(10, 0, 0), algorithm)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			reconciler := &Controller{
+				client: k8sM,
+				managedSignerNames: map[string]struct{}{
+					managedSigner: {},
+				},
+				getClusterConfig: func(context.Context) (types.ClusterConfig, error) {
+					return types.ClusterConfig{
+						Certificates: types.Certificates{
+							CACert: ptr.To(caCert),
+							CAKey:  ptr.To(caKey),
+						},
+					}, nil
+				},
+				signers: NewSignerCache(),
+			}
+
+			result, err := reconciler.Reconcile(context.Background(), getDefaultRequest())
+
+			g.Expect(result).To(Equal(ctrl.Result{}))
+			g.Expect(err).ToNot(HaveOccurred())
+			k8sM.AssertUpdateCalled(t)
+		})
+	}
+}
+
+func TestApprovalPolicyMaxExpiration(t *testing.T) {
+	csrPEM, _, err := pkiutil.GenerateCSR(
+		pkix.Name{
+			CommonName:   "system:node:valid-node",
+			Organization: []string{"system:nodes"},
+		},
+		pkiutil.KeyAlgorithmRSA2048,
+		nil,
+		nil,
+	)
+
+	g := NewWithT(t)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	managedSigner := "k8sd.io/kubelet-client"
+	csr := certv1.CertificateSigningRequest{
+		Spec: certv1.CertificateSigningRequestSpec{
+			SignerName:        managedSigner,
+			Request:           []byte(csrPEM),
+			ExpirationSeconds: ptr.To(int32(86400)),
+		},
+		Status: certv1.CertificateSigningRequestStatus{
+			Conditions: []certv1.CertificateSigningRequestCondition{
+				{
+					Type: certv1.CertificateApproved,
+				},
+			},
+		},
+	}
+
+	k8sM := k8smock.New(
+		t,
+		k8smock.NewSubResourceClientMock(nil),
+		csr,
+		nil,
+	)
+
+	caCert, caKey, err := pkiutil.GenerateSelfSignedCA(pkix.Name{CommonName: "kubernetes-ca"}, time.Now(), time.Now().AddDate(10, 0, 0), pkiutil.KeyAlgorithmRSA2048)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	reconciler := &Controller{
+		client: k8sM,
+		managedSignerNames: map[string]struct{}{
+			managedSigner: {},
+		},
+		getClusterConfig: func(context.Context) (types.ClusterConfig, error) {
+			return types.ClusterConfig{
+				Certificates: types.Certificates{
+					CACert: ptr.To(caCert),
+					CAKey:  ptr.To(caKey),
+				},
+				Annotations: types.Annotations{
+					AnnotationApprovalPolicy: "max-expiration-seconds: 3600",
+				},
+			}, nil
+		},
+		signers: NewSignerCache(),
+	}
+
+	result, err := reconciler.Reconcile(context.Background(), getDefaultRequest())
+
+	g.Expect(result).To(Equal(ctrl.Result{}))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	updated, ok := k8sM.UpdatedObject().(*certv1.CertificateSigningRequest)
+	g.Expect(ok).To(BeTrue())
+	cert, _, err := pkiutil.LoadCertificate(string(updated.Status.Certificate), "")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(cert.NotAfter).To(BeTemporally("~", time.Now().Add(time.Hour), time.Minute))
+}
+
+func TestInvalidApprovalPolicy(t *testing.T) {
+	managedSigner := "managed-signer"
+	k8sM := k8smock.New(
+		t,
+		k8smock.NewSubResourceClientMock(nil),
+		certv1.CertificateSigningRequest{
+			Spec: certv1.CertificateSigningRequestSpec{
+				SignerName: managedSigner,
+			},
+		},
+		nil,
+	)
+
+	reconciler := &Controller{
+		client: k8sM,
+		managedSignerNames: map[string]struct{}{
+			managedSigner: {},
+		},
+		getClusterConfig: func(context.Context) (types.ClusterConfig, error) {
+			return types.ClusterConfig{
+				Annotations: types.Annotations{
+					AnnotationApprovalPolicy: "max-expiration-seconds: -1",
+				},
+			}, nil
+		},
+		signers: NewSignerCache(),
+	}
+
+	g := NewWithT(t)
+
+	result, err := reconciler.Reconcile(context.Background(), getDefaultRequest())
+
+	g.Expect(result).To(Equal(ctrl.Result{}))
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestMissingCAKey(t *testing.T) {
 	csrPEM, _, err := pkiutil.GenerateCSR(
 		pkix.Name{
 			CommonName:   "system:node:valid-node",
 			Organization: []string{"system:nodes"},
 		},
-		2048,
+		pkiutil.KeyAlgorithmRSA2048,
 		nil,
 		nil,
 	)
@@ -514,7 +693,7 @@ func TestUpdateCSRSucceed(t *testing.T) {
 		nil,
 	)
 
-	caCert, caKey, err := pkiutil.GenerateSelfSignedCA(pkix.Name{CommonName: "kubernetes-ca"}, time.Now(), time.Now().AddDate(10, 0, 0), 2048)
+	caCert, _, err := pkiutil.GenerateSelfSignedCA(pkix.Name{CommonName: "kubernetes-ca"}, time.Now(), time.Now().AddDate(10, 0, 0), pkiutil.KeyAlgorithmRSA2048)
 	g.Expect(err).ToNot(HaveOccurred())
 
 	reconciler := &Controller{
@@ -526,10 +705,10 @@ func TestUpdateCSRSucceed(t *testing.T) {
 			return types.ClusterConfig{
 				Certificates: types.Certificates{
 					CACert: ptr.To(caCert),
-					CAKey:  ptr.To(caKey),
 				},
 			}, nil
 		},
+		signers: NewSignerCache(),
 	}
 
 	result, err := reconciler.Reconcile(context.Background(), getDefaultRequest())
@@ -539,6 +718,61 @@ func TestUpdateCSRSucceed(t *testing.T) {
 	k8sM.AssertUpdateCalled(t)
 }
 
+func TestUnknownSigner(t *testing.T) {
+	csrPEM, _, err := pkiutil.GenerateCSR(
+		pkix.Name{
+			CommonName:   "system:node:valid-node",
+			Organization: []string{"system:nodes"},
+		},
+		pkiutil.KeyAlgorithmRSA2048,
+		nil,
+		nil,
+	)
+
+	g := NewWithT(t)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	managedSigner := "k8sd.io/kubelet-serving"
+	csr := certv1.CertificateSigningRequest{
+		Spec: certv1.CertificateSigningRequestSpec{
+			SignerName: managedSigner,
+			Request:    []byte(csrPEM),
+		},
+		Status: certv1.CertificateSigningRequestStatus{
+			Conditions: []certv1.CertificateSigningRequestCondition{
+				{
+					Type: certv1.CertificateApproved,
+				},
+			},
+		},
+	}
+
+	k8sM := k8smock.New(
+		t,
+		k8smock.NewSubResourceClientMock(nil),
+		csr,
+		nil,
+	)
+
+	reconciler := &Controller{
+		client: k8sM,
+		managedSignerNames: map[string]struct{}{
+			managedSigner: {},
+		},
+		getClusterConfig: func(context.Context) (types.ClusterConfig, error) {
+			return types.ClusterConfig{
+				Annotations: types.Annotations{AnnotationSigner: "unknown"},
+			}, nil
+		},
+		signers: NewSignerCache(),
+	}
+
+	result, err := reconciler.Reconcile(context.Background(), getDefaultRequest())
+
+	g.Expect(result).To(Equal(ctrl.Result{}))
+	g.Expect(err).To(HaveOccurred())
+}
+
 func getDefaultRequest() ctrl.Request {
 	return ctrl.Request{
 		NamespacedName: k8stypes.NamespacedName{
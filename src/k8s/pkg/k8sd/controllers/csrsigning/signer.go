@@ -0,0 +1,82 @@
+package csrsigning
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"fmt"
+
+	"github.com/canonical/k8s/pkg/k8sd/types"
+)
+
+const (
+	// AnnotationSigner configures where the private keys of the Kubernetes CAs are kept.
+	// Supported values are "local" (default), "pkcs11" and "remote".
+	AnnotationSigner = "k8sd/v1alpha1/csrsigning/signer"
+	// AnnotationPKCS11Module is the path to the PKCS#11 module on the control plane nodes.
+	AnnotationPKCS11Module = "k8sd/v1alpha1/csrsigning/pkcs11-module"
+	// AnnotationPKCS11TokenLabel is the label of the PKCS#11 token that holds the CA keys.
+	AnnotationPKCS11TokenLabel = "k8sd/v1alpha1/csrsigning/pkcs11-token-label"
+	// AnnotationPKCS11PinFile is the path to a file on the control plane nodes with the PIN of the PKCS#11 token.
+	AnnotationPKCS11PinFile = "k8sd/v1alpha1/csrsigning/pkcs11-pin-file"
+	// AnnotationPKCS11CAKeyLabel is the label of the Kubernetes CA key on the PKCS#11 token.
+	AnnotationPKCS11CAKeyLabel = "k8sd/v1alpha1/csrsigning/pkcs11-ca-key-label"
+	// AnnotationPKCS11ClientCAKeyLabel is the label of the Kubernetes client CA key on the PKCS#11 token.
+	AnnotationPKCS11ClientCAKeyLabel = "k8sd/v1alpha1/csrsigning/pkcs11-client-ca-key-label"
+	// AnnotationRemoteSignerEndpoint is the gRPC endpoint of the remote signer, e.g. "unix:///run/signer.sock".
+	AnnotationRemoteSignerEndpoint = "k8sd/v1alpha1/csrsigning/remote-signer-endpoint"
+)
+
+const (
+	// SignerLocal signs with the CA keys stored in the cluster configuration.
+	SignerLocal = "local"
+	// SignerPKCS11 signs with CA keys kept on a PKCS#11 token (e.g. an HSM).
+	SignerPKCS11 = "pkcs11"
+	// SignerRemote signs through a remote signer that implements the gRPC signer protocol.
+	SignerRemote = "remote"
+)
+
+// CA identifies one of the Kubernetes CAs.
+type CA string
+
+const (
+	// CAKubernetes is the Kubernetes CA, which signs server certificates.
+	CAKubernetes CA = "kubernetes-ca"
+	// CAKubernetesClient is the Kubernetes client CA, which signs client certificates.
+	CAKubernetesClient CA = "kubernetes-ca-client"
+)
+
+// ErrMissingCAKey is returned by signers when the private key of a CA is not available.
+var ErrMissingCAKey = errors.New("CA private key is not available")
+
+// Signer provides access to the private keys of the Kubernetes CAs.
+type Signer interface {
+	// Key returns the private key of the specified CA.
+	// Key returns an error wrapping ErrMissingCAKey if the key is not available.
+	Key(ctx context.Context, ca CA) (crypto.Signer, error)
+
+	// Close releases the resources held by the signer.
+	Close() error
+}
+
+// NewSigner returns the signer that is configured through the cluster configuration annotations.
+func NewSigner(config types.ClusterConfig) (Signer, error) {
+	signer, _ := config.Annotations.Get(AnnotationSigner)
+	switch signer {
+	case "", SignerLocal:
+		return newLocalSigner(config.Certificates), nil
+	case SignerPKCS11:
+		return newPKCS11Signer(config.Annotations)
+	case SignerRemote:
+		return newRemoteSigner(config.Annotations, config.Certificates)
+	default:
+		return nil, fmt.Errorf("unknown signer %q, must be one of %q, %q or %q", signer, SignerLocal, SignerPKCS11, SignerRemote)
+	}
+}
+
+// UsesExternalSigner returns true if the CA keys are kept outside the cluster configuration.
+// The external signer is only reachable from the control plane nodes.
+func UsesExternalSigner(config types.ClusterConfig) bool {
+	signer, _ := config.Annotations.Get(AnnotationSigner)
+	return signer != "" && signer != SignerLocal
+}
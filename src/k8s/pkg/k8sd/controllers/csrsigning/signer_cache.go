@@ -0,0 +1,119 @@
+package csrsigning
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+
+	"github.com/canonical/k8s/pkg/k8sd/types"
+)
+
+// signerAnnotations are the annotations that configure the signer.
+var signerAnnotations = []string{
+	AnnotationSigner,
+	AnnotationPKCS11Module,
+	AnnotationPKCS11TokenLabel,
+	AnnotationPKCS11PinFile,
+	AnnotationPKCS11CAKeyLabel,
+	AnnotationPKCS11ClientCAKeyLabel,
+	AnnotationRemoteSignerEndpoint,
+}
+
+// SignerCache keeps the signer of the current cluster configuration, so that PKCS#11 sessions
+// and remote signer connections are reused across certificate requests.
+// Signers are reference counted, so that a signer that is replaced while it is in use is only closed
+// once the last caller released it.
+// SignerCache is safe for concurrent use.
+type SignerCache struct {
+	mu      sync.Mutex
+	key     string
+	current *cachedSigner
+}
+
+// cachedSigner is a signer and the number of its references. The cache holds one reference to its current signer.
+// refs is protected by the mutex of the SignerCache.
+type cachedSigner struct {
+	signer Signer
+	refs   int
+}
+
+// NewSignerCache returns an empty SignerCache.
+func NewSignerCache() *SignerCache {
+	return &SignerCache{}
+}
+
+// signerConfigKey returns a key that changes whenever the signer configuration changes.
+func signerConfigKey(config types.ClusterConfig) string {
+	h := sha256.New()
+	for _, annotation := range signerAnnotations {
+		value, _ := config.Annotations.Get(annotation)
+		fmt.Fprintf(h, "%s=%q\n", annotation, value)
+	}
+	for _, value := range []string{
+		config.Certificates.GetCACert(),
+		config.Certificates.GetCAKey(),
+		config.Certificates.GetClientCACert(),
+		config.Certificates.GetClientCAKey(),
+	} {
+		fmt.Fprintf(h, "%q\n", value)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Get returns the signer for the cluster configuration and a function that releases it.
+// The signer is created on first use and replaced when the signer configuration or the CAs change.
+// Callers must call release once they no longer use the signer, and must not Close the signer.
+func (c *SignerCache) Get(config types.ClusterConfig) (signer Signer, release func(), err error) {
+	key := signerConfigKey(config)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.current == nil || c.key != key {
+		newSigner, err := NewSigner(config)
+		if err != nil {
+			return nil, nil, err
+		}
+		if c.current != nil {
+			// NOTE: Errors are ignored, as the previous signer is no longer used.
+			_ = c.releaseLocked(c.current)
+		}
+		c.key, c.current = key, &cachedSigner{signer: newSigner, refs: 1}
+	}
+
+	entry := c.current
+	entry.refs++
+	var once sync.Once
+	return entry.signer, func() {
+		once.Do(func() {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			// NOTE: Errors are ignored, as the signer was already used successfully or not at all.
+			_ = c.releaseLocked(entry)
+		})
+	}, nil
+}
+
+// releaseLocked drops a reference to the signer and closes it once it is no longer referenced.
+// releaseLocked must be called with the mutex held.
+func (c *SignerCache) releaseLocked(entry *cachedSigner) error {
+	entry.refs--
+	if entry.refs > 0 {
+		return nil
+	}
+	return entry.signer.Close()
+}
+
+// Close releases the cached signer. The signer is closed once all callers released it.
+func (c *SignerCache) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.current == nil {
+		return nil
+	}
+	err := c.releaseLocked(c.current)
+	c.key, c.current = "", nil
+	return err
+}
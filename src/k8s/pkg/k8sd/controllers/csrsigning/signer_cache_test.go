@@ -0,0 +1,131 @@
+package csrsigning
+
+import (
+	"context"
+	"crypto"
+	"testing"
+
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/ptr"
+)
+
+// closeCountingSigner is a signer that counts how often it was closed.
+type closeCountingSigner struct {
+	closed int
+}
+
+func (s *closeCountingSigner) Key(context.Context, CA) (crypto.Signer, error) {
+	return nil, ErrMissingCAKey
+}
+func (s *closeCountingSigner) Close() error { s.closed++; return nil }
+
+func TestSignerCache(t *testing.T) {
+	g := NewWithT(t)
+
+	cache := NewSignerCache()
+	defer cache.Close()
+
+	config := types.ClusterConfig{Certificates: types.Certificates{CAKey: ptr.To("ca-key")}}
+	signer, release, err := cache.Get(config)
+	g.Expect(err).ToNot(HaveOccurred())
+	release()
+
+	t.Run("Unchanged", func(t *testing.T) {
+		g := NewWithT(t)
+
+		// NOTE: Annotations that do not configure the signer do not replace the signer.
+		config := config
+		config.Annotations = types.Annotations{"k8sd/v1alpha/lifecycle/skip-cleanup-kubernetes-node-on-remove": "true"}
+		cached, release, err := cache.Get(config)
+		g.Expect(err).ToNot(HaveOccurred())
+		defer release()
+		g.Expect(cached).To(BeIdenticalTo(signer))
+	})
+
+	t.Run("Changed", func(t *testing.T) {
+		g := NewWithT(t)
+
+		config := types.ClusterConfig{Certificates: types.Certificates{CAKey: ptr.To("new-ca-key")}}
+		cached, release, err := cache.Get(config)
+		g.Expect(err).ToNot(HaveOccurred())
+		defer release()
+		g.Expect(cached).ToNot(BeIdenticalTo(signer))
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, _, err := cache.Get(types.ClusterConfig{Annotations: types.Annotations{AnnotationSigner: "vault"}})
+		g.Expect(err).To(HaveOccurred())
+	})
+
+	t.Run("Close", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(cache.Close()).To(Succeed())
+		cached, release, err := cache.Get(config)
+		g.Expect(err).ToNot(HaveOccurred())
+		defer release()
+		g.Expect(cached).ToNot(BeIdenticalTo(signer))
+	})
+}
+
+func TestSignerCacheRelease(t *testing.T) {
+	config := types.ClusterConfig{Certificates: types.Certificates{CAKey: ptr.To("ca-key")}}
+	newConfig := types.ClusterConfig{Certificates: types.Certificates{CAKey: ptr.To("new-ca-key")}}
+
+	// withSigner returns a cache whose current signer for config is a closeCountingSigner.
+	withSigner := func() (*SignerCache, *closeCountingSigner) {
+		signer := &closeCountingSigner{}
+		return &SignerCache{key: signerConfigKey(config), current: &cachedSigner{signer: signer, refs: 1}}, signer
+	}
+
+	t.Run("ReplacedWhileInUse", func(t *testing.T) {
+		g := NewWithT(t)
+		cache, signer := withSigner()
+		defer cache.Close()
+
+		inUse, release, err := cache.Get(config)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(inUse).To(BeIdenticalTo(signer))
+
+		// NOTE: The signer is replaced, but it is only closed once it was released.
+		_, releaseNew, err := cache.Get(newConfig)
+		g.Expect(err).ToNot(HaveOccurred())
+		defer releaseNew()
+		g.Expect(signer.closed).To(Equal(0))
+
+		release()
+		g.Expect(signer.closed).To(Equal(1))
+
+		// NOTE: Releasing twice does not drop another reference.
+		release()
+		g.Expect(signer.closed).To(Equal(1))
+	})
+
+	t.Run("ReplacedUnused", func(t *testing.T) {
+		g := NewWithT(t)
+		cache, signer := withSigner()
+		defer cache.Close()
+
+		_, release, err := cache.Get(newConfig)
+		g.Expect(err).ToNot(HaveOccurred())
+		defer release()
+		g.Expect(signer.closed).To(Equal(1))
+	})
+
+	t.Run("ClosedWhileInUse", func(t *testing.T) {
+		g := NewWithT(t)
+		cache, signer := withSigner()
+
+		_, release, err := cache.Get(config)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		g.Expect(cache.Close()).To(Succeed())
+		g.Expect(signer.closed).To(Equal(0))
+
+		release()
+		g.Expect(signer.closed).To(Equal(1))
+	})
+}
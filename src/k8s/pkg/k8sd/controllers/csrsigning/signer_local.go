@@ -0,0 +1,44 @@
+package csrsigning
+
+import (
+	"context"
+	"crypto"
+	"fmt"
+
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	pkiutil "github.com/canonical/k8s/pkg/utils/pki"
+)
+
+// localSigner uses the CA keys that are stored in the cluster configuration.
+type localSigner struct {
+	certificates types.Certificates
+}
+
+func newLocalSigner(certificates types.Certificates) *localSigner {
+	return &localSigner{certificates: certificates}
+}
+
+func (s *localSigner) Key(_ context.Context, ca CA) (crypto.Signer, error) {
+	var keyPEM string
+	switch ca {
+	case CAKubernetes:
+		keyPEM = s.certificates.GetCAKey()
+	case CAKubernetesClient:
+		keyPEM = s.certificates.GetClientCAKey()
+	default:
+		return nil, fmt.Errorf("unknown CA %q", ca)
+	}
+	if keyPEM == "" {
+		return nil, fmt.Errorf("%w: %s key is not set in the cluster configuration", ErrMissingCAKey, ca)
+	}
+
+	key, err := pkiutil.LoadPrivateKey(keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s key: %w", ca, err)
+	}
+	return key, nil
+}
+
+func (s *localSigner) Close() error {
+	return nil
+}
@@ -0,0 +1,80 @@
+package csrsigning
+
+import (
+	"context"
+	"crypto"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ThalesIgnite/crypto11"
+	"github.com/canonical/k8s/pkg/k8sd/types"
+)
+
+// pkcs11Signer uses CA keys that are kept on a PKCS#11 token, e.g. a hardware security module.
+type pkcs11Signer struct {
+	ctx    *crypto11.Context
+	labels map[CA]string
+}
+
+func newPKCS11Signer(annotations types.Annotations) (*pkcs11Signer, error) {
+	module, _ := annotations.Get(AnnotationPKCS11Module)
+	if module == "" {
+		return nil, fmt.Errorf("PKCS#11 module must be set with %s", AnnotationPKCS11Module)
+	}
+	tokenLabel, _ := annotations.Get(AnnotationPKCS11TokenLabel)
+	if tokenLabel == "" {
+		return nil, fmt.Errorf("PKCS#11 token label must be set with %s", AnnotationPKCS11TokenLabel)
+	}
+
+	var pin string
+	if pinFile, ok := annotations.Get(AnnotationPKCS11PinFile); ok && pinFile != "" {
+		b, err := os.ReadFile(pinFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read PKCS#11 PIN file: %w", err)
+		}
+		pin = strings.TrimSpace(string(b))
+	}
+
+	labels := map[CA]string{
+		CAKubernetes:       string(CAKubernetes),
+		CAKubernetesClient: string(CAKubernetesClient),
+	}
+	if v, ok := annotations.Get(AnnotationPKCS11CAKeyLabel); ok && v != "" {
+		labels[CAKubernetes] = v
+	}
+	if v, ok := annotations.Get(AnnotationPKCS11ClientCAKeyLabel); ok && v != "" {
+		labels[CAKubernetesClient] = v
+	}
+
+	ctx, err := crypto11.Configure(&crypto11.Config{
+		Path:       module,
+		TokenLabel: tokenLabel,
+		Pin:        pin,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open PKCS#11 token %q: %w", tokenLabel, err)
+	}
+
+	return &pkcs11Signer{ctx: ctx, labels: labels}, nil
+}
+
+func (s *pkcs11Signer) Key(_ context.Context, ca CA) (crypto.Signer, error) {
+	label, ok := s.labels[ca]
+	if !ok {
+		return nil, fmt.Errorf("unknown CA %q", ca)
+	}
+
+	key, err := s.ctx.FindKeyPair(nil, []byte(label))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find %s key with label %q: %w", ca, label, err)
+	}
+	if key == nil {
+		return nil, fmt.Errorf("%w: no key with label %q on the PKCS#11 token", ErrMissingCAKey, label)
+	}
+	return key, nil
+}
+
+func (s *pkcs11Signer) Close() error {
+	return s.ctx.Close()
+}
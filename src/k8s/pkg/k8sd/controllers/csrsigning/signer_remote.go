@@ -0,0 +1,162 @@
+package csrsigning
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	pkiutil "github.com/canonical/k8s/pkg/utils/pki"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+// The remote signer protocol is a gRPC service with a single unary method. Messages are encoded as JSON, so the
+// content type of the requests is "application/grpc+json".
+//
+//	service k8sd.csrsigning.v1alpha1.Signer {
+//	  rpc Sign(RemoteSignRequest) returns (RemoteSignResponse);
+//	}
+//
+// Remote signers must return a NotFound status code if they do not hold the requested key.
+const (
+	remoteSignerServiceName = "k8sd.csrsigning.v1alpha1.Signer"
+	// RemoteSignerSignMethod is the full name of the Sign method of the remote signer protocol.
+	RemoteSignerSignMethod = "/" + remoteSignerServiceName + "/Sign"
+
+	// remoteSignTimeout is the timeout of a single request to the remote signer.
+	remoteSignTimeout = 10 * time.Second
+)
+
+// RemoteSignRequest is the request message of the remote signer protocol.
+type RemoteSignRequest struct {
+	// KeyID is the CA whose key must be used, i.e. "kubernetes-ca" or "kubernetes-ca-client".
+	KeyID string `json:"key-id"`
+	// Digest is the digest to sign. If Hash is empty, Digest is the full message (e.g. for Ed25519 keys).
+	Digest []byte `json:"digest"`
+	// Hash is the name of the hash function used to compute Digest, e.g. "SHA-256".
+	Hash string `json:"hash,omitempty"`
+}
+
+// RemoteSignResponse is the response message of the remote signer protocol.
+type RemoteSignResponse struct {
+	// Signature is the signature of the digest. ECDSA signatures are ASN.1 encoded and RSA signatures use PKCS#1 v1.5.
+	Signature []byte `json:"signature"`
+}
+
+// jsonCodec encodes the remote signer protocol messages as JSON.
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v any) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v any) error { return json.Unmarshal(data, v) }
+func (jsonCodec) Name() string                       { return "json" }
+
+// NewRemoteSignerServer returns a gRPC server that implements the remote signer protocol with the specified function.
+func NewRemoteSignerServer(sign func(context.Context, *RemoteSignRequest) (*RemoteSignResponse, error), opts ...grpc.ServerOption) *grpc.Server {
+	server := grpc.NewServer(append(opts, grpc.ForceServerCodec(jsonCodec{}))...)
+	server.RegisterService(&grpc.ServiceDesc{
+		ServiceName: remoteSignerServiceName,
+		HandlerType: (*any)(nil),
+		Methods: []grpc.MethodDesc{{
+			MethodName: "Sign",
+			Handler: func(_ any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+				req := &RemoteSignRequest{}
+				if err := dec(req); err != nil {
+					return nil, err
+				}
+				if interceptor == nil {
+					return sign(ctx, req)
+				}
+				return interceptor(ctx, req, &grpc.UnaryServerInfo{FullMethod: RemoteSignerSignMethod}, func(ctx context.Context, req any) (any, error) {
+					return sign(ctx, req.(*RemoteSignRequest))
+				})
+			},
+		}},
+	}, nil)
+	return server
+}
+
+// remoteSigner signs through a remote signer. The public keys are taken from the CA certificates.
+type remoteSigner struct {
+	conn         *grpc.ClientConn
+	certificates types.Certificates
+}
+
+func newRemoteSigner(annotations types.Annotations, certificates types.Certificates) (*remoteSigner, error) {
+	endpoint, _ := annotations.Get(AnnotationRemoteSignerEndpoint)
+	if endpoint == "" {
+		return nil, fmt.Errorf("remote signer endpoint must be set with %s", AnnotationRemoteSignerEndpoint)
+	}
+
+	// NOTE: The remote signer is expected to listen on a local socket, hence no transport security.
+	conn, err := grpc.NewClient(endpoint, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create remote signer client for %q: %w", endpoint, err)
+	}
+
+	return &remoteSigner{conn: conn, certificates: certificates}, nil
+}
+
+func (s *remoteSigner) Key(ctx context.Context, ca CA) (crypto.Signer, error) {
+	var certPEM string
+	switch ca {
+	case CAKubernetes:
+		certPEM = s.certificates.GetCACert()
+	case CAKubernetesClient:
+		certPEM = s.certificates.GetClientCACert()
+	default:
+		return nil, fmt.Errorf("unknown CA %q", ca)
+	}
+
+	cert, _, err := pkiutil.LoadCertificate(certPEM, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s certificate: %w", ca, err)
+	}
+
+	return &remoteKey{ctx: ctx, conn: s.conn, ca: ca, public: cert.PublicKey}, nil
+}
+
+func (s *remoteSigner) Close() error {
+	return s.conn.Close()
+}
+
+// remoteKey implements crypto.Signer for a CA key that is held by a remote signer.
+type remoteKey struct {
+	ctx    context.Context
+	conn   *grpc.ClientConn
+	ca     CA
+	public crypto.PublicKey
+}
+
+func (k *remoteKey) Public() crypto.PublicKey {
+	return k.public
+}
+
+func (k *remoteKey) Sign(_ io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	if _, ok := opts.(*rsa.PSSOptions); ok {
+		return nil, fmt.Errorf("RSA-PSS signatures are not supported by the remote signer")
+	}
+
+	req := &RemoteSignRequest{KeyID: string(k.ca), Digest: digest}
+	if hash := opts.HashFunc(); hash != 0 {
+		req.Hash = hash.String()
+	}
+
+	ctx, cancel := context.WithTimeout(k.ctx, remoteSignTimeout)
+	defer cancel()
+
+	resp := &RemoteSignResponse{}
+	if err := k.conn.Invoke(ctx, RemoteSignerSignMethod, req, resp, grpc.ForceCodec(jsonCodec{})); err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, fmt.Errorf("%w: remote signer has no %s key: %w", ErrMissingCAKey, k.ca, err)
+		}
+		return nil, fmt.Errorf("remote signer failed to sign with %s key: %w", k.ca, err)
+	}
+	return resp.Signature, nil
+}
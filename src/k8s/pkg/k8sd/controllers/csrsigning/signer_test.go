@@ -0,0 +1,192 @@
+package csrsigning
+
+import (
+	"context"
+	"crypto"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ThalesIgnite/crypto11"
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	pkiutil "github.com/canonical/k8s/pkg/utils/pki"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/utils/ptr"
+)
+
+// signTestCertificate signs a leaf certificate with the CA key from the signer and verifies it against the CA.
+func signTestCertificate(ctx context.Context, signer Signer, ca CA, caPEM string) error {
+	caCert, _, err := pkiutil.LoadCertificate(caPEM, "")
+	if err != nil {
+		return err
+	}
+	caKey, err := signer.Key(ctx, ca)
+	if err != nil {
+		return err
+	}
+
+	template, err := pkiutil.GenerateCertificate(pkix.Name{CommonName: "test"}, time.Now(), time.Now().AddDate(1, 0, 0), false, nil, nil)
+	if err != nil {
+		return err
+	}
+	certPEM, keyPEM, err := pkiutil.SignCertificate(template, pkiutil.KeyAlgorithmECDSAP256, caCert, caKey)
+	if err != nil {
+		return err
+	}
+	return pkiutil.CertCheck{CN: "test", CaPEM: caPEM}.ValidateKeypair(certPEM, keyPEM)
+}
+
+func TestNewSigner(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		annotations types.Annotations
+	}{
+		{name: "Unknown", annotations: types.Annotations{AnnotationSigner: "vault"}},
+		{name: "PKCS11MissingModule", annotations: types.Annotations{AnnotationSigner: SignerPKCS11, AnnotationPKCS11TokenLabel: "k8s"}},
+		{name: "PKCS11MissingTokenLabel", annotations: types.Annotations{AnnotationSigner: SignerPKCS11, AnnotationPKCS11Module: "/lib/module.so"}},
+		{name: "PKCS11MissingPinFile", annotations: types.Annotations{AnnotationSigner: SignerPKCS11, AnnotationPKCS11Module: "/lib/module.so", AnnotationPKCS11TokenLabel: "k8s", AnnotationPKCS11PinFile: "/nonexistent"}},
+		{name: "RemoteMissingEndpoint", annotations: types.Annotations{AnnotationSigner: SignerRemote}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			_, err := NewSigner(types.ClusterConfig{Annotations: tc.annotations})
+			g.Expect(err).To(HaveOccurred())
+		})
+	}
+}
+
+func TestLocalSigner(t *testing.T) {
+	g := NewWithT(t)
+
+	caPEM, _, err := pkiutil.GenerateSelfSignedCA(pkix.Name{CommonName: "kubernetes-ca"}, time.Now(), time.Now().AddDate(1, 0, 0), pkiutil.KeyAlgorithmRSA2048)
+	g.Expect(err).ToNot(HaveOccurred())
+	clientCAPEM, clientCAKeyPEM, err := pkiutil.GenerateSelfSignedCA(pkix.Name{CommonName: "kubernetes-ca-client"}, time.Now(), time.Now().AddDate(1, 0, 0), pkiutil.KeyAlgorithmRSA2048)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	signer, err := NewSigner(types.ClusterConfig{
+		Certificates: types.Certificates{CACert: ptr.To(caPEM), ClientCACert: ptr.To(clientCAPEM), ClientCAKey: ptr.To(clientCAKeyPEM)},
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	defer signer.Close()
+
+	g.Expect(signTestCertificate(context.Background(), signer, CAKubernetesClient, clientCAPEM)).To(Succeed())
+
+	_, err = signer.Key(context.Background(), CAKubernetes)
+	g.Expect(err).To(MatchError(ErrMissingCAKey))
+}
+
+func TestRemoteSigner(t *testing.T) {
+	g := NewWithT(t)
+
+	caPEM, caKeyPEM, err := pkiutil.GenerateSelfSignedCA(pkix.Name{CommonName: "kubernetes-ca"}, time.Now(), time.Now().AddDate(1, 0, 0), pkiutil.KeyAlgorithmECDSAP256)
+	g.Expect(err).ToNot(HaveOccurred())
+	caKey, err := pkiutil.LoadPrivateKey(caKeyPEM)
+	g.Expect(err).ToNot(HaveOccurred())
+	clientCAPEM, _, err := pkiutil.GenerateSelfSignedCA(pkix.Name{CommonName: "kubernetes-ca-client"}, time.Now(), time.Now().AddDate(1, 0, 0), pkiutil.KeyAlgorithmECDSAP256)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// NOTE: Unix socket paths are limited in length, so t.TempDir() is not used.
+	dir, err := os.MkdirTemp("", "signer")
+	g.Expect(err).ToNot(HaveOccurred())
+	defer os.RemoveAll(dir)
+	socket := filepath.Join(dir, "signer.sock")
+
+	l, err := net.Listen("unix", socket)
+	g.Expect(err).ToNot(HaveOccurred())
+	server := NewRemoteSignerServer(func(_ context.Context, req *RemoteSignRequest) (*RemoteSignResponse, error) {
+		if req.KeyID != string(CAKubernetes) {
+			return nil, status.Errorf(codes.NotFound, "no key %q", req.KeyID)
+		}
+		if req.Hash != crypto.SHA256.String() {
+			return nil, status.Errorf(codes.InvalidArgument, "unexpected hash %q", req.Hash)
+		}
+		signature, err := caKey.Sign(rand.Reader, req.Digest, crypto.SHA256)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		return &RemoteSignResponse{Signature: signature}, nil
+	})
+	go func() { _ = server.Serve(l) }()
+	defer server.Stop()
+
+	signer, err := NewSigner(types.ClusterConfig{
+		Certificates: types.Certificates{CACert: ptr.To(caPEM), ClientCACert: ptr.To(clientCAPEM)},
+		Annotations: types.Annotations{
+			AnnotationSigner:               SignerRemote,
+			AnnotationRemoteSignerEndpoint: fmt.Sprintf("unix://%s", socket),
+		},
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	defer signer.Close()
+
+	g.Expect(signTestCertificate(context.Background(), signer, CAKubernetes, caPEM)).To(Succeed())
+	g.Expect(signTestCertificate(context.Background(), signer, CAKubernetesClient, clientCAPEM)).To(MatchError(ErrMissingCAKey))
+}
+
+// TestPKCS11Signer runs against SoftHSM, if available.
+func TestPKCS11Signer(t *testing.T) {
+	module := os.Getenv("SOFTHSM2_MODULE")
+	if module == "" {
+		module = "/usr/lib/softhsm/libsofthsm2.so"
+	}
+	if _, err := os.Stat(module); err != nil {
+		t.Skipf("SoftHSM module %q not available", module)
+	}
+	if _, err := exec.LookPath("softhsm2-util"); err != nil {
+		t.Skip("softhsm2-util not available")
+	}
+
+	g := NewWithT(t)
+	dir := t.TempDir()
+	g.Expect(os.Mkdir(filepath.Join(dir, "tokens"), 0o700)).To(Succeed())
+	g.Expect(os.WriteFile(filepath.Join(dir, "softhsm2.conf"), []byte(fmt.Sprintf("directories.tokendir = %s/tokens\nobjectstore.backend = file\n", dir)), 0o600)).To(Succeed())
+	g.Expect(os.WriteFile(filepath.Join(dir, "pin"), []byte("1234\n"), 0o600)).To(Succeed())
+	t.Setenv("SOFTHSM2_CONF", filepath.Join(dir, "softhsm2.conf"))
+
+	out, err := exec.Command("softhsm2-util", "--init-token", "--free", "--label", "k8sd", "--pin", "1234", "--so-pin", "1234").CombinedOutput()
+	g.Expect(err).ToNot(HaveOccurred(), string(out))
+
+	// Generate the CA key on the token and issue the CA certificate with it.
+	ctx, err := crypto11.Configure(&crypto11.Config{Path: module, TokenLabel: "k8sd", Pin: "1234"})
+	g.Expect(err).ToNot(HaveOccurred())
+	caKey, err := ctx.GenerateECDSAKeyPairWithLabel([]byte("1"), []byte("my-ca"), elliptic.P256())
+	g.Expect(err).ToNot(HaveOccurred())
+	template, err := pkiutil.GenerateCertificate(pkix.Name{CommonName: "kubernetes-ca"}, time.Now(), time.Now().AddDate(1, 0, 0), true, nil, nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	caDER, err := x509.CreateCertificate(rand.Reader, template, template, caKey.Public(), caKey)
+	g.Expect(err).ToNot(HaveOccurred())
+	caCert, err := x509.ParseCertificate(caDER)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(ctx.Close()).To(Succeed())
+	caPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caCert.Raw}))
+
+	signer, err := NewSigner(types.ClusterConfig{
+		Certificates: types.Certificates{CACert: ptr.To(caPEM)},
+		Annotations: types.Annotations{
+			AnnotationSigner:           SignerPKCS11,
+			AnnotationPKCS11Module:     module,
+			AnnotationPKCS11TokenLabel: "k8sd",
+			AnnotationPKCS11PinFile:    filepath.Join(dir, "pin"),
+			AnnotationPKCS11CAKeyLabel: "my-ca",
+		},
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	defer signer.Close()
+
+	g.Expect(signTestCertificate(context.Background(), signer, CAKubernetes, caPEM)).To(Succeed())
+
+	_, err = signer.Key(context.Background(), CAKubernetesClient)
+	g.Expect(err).To(MatchError(ErrMissingCAKey))
+}
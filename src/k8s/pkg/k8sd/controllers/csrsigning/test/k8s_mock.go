@@ -74,6 +74,14 @@ func (m *K8sMock) AssertUpdateCalled(t *testing.T) {
 	m.srcm.assertUpdateCalled(t)
 }
 
+// UpdatedObject returns the object of the last update call, or nil if update was not called.
+func (m *K8sMock) UpdatedObject() client.Object {
+	if len(m.srcm.updateCalledWith) == 0 {
+		return nil
+	}
+	return m.srcm.updateCalledWith[len(m.srcm.updateCalledWith)-1].obj
+}
+
 type updateArgs struct {
 	obj  client.Object
 	opts []client.SubResourceUpdateOption
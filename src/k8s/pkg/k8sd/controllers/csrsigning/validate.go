@@ -1,6 +1,7 @@
 package csrsigning
 
 import (
+	"crypto"
 	"crypto/rsa"
 	"crypto/sha256"
 	"crypto/subtle"
@@ -63,7 +64,7 @@ func validateCSR(obj *certv1.CertificateSigningRequest, priv *rsa.PrivateKey) er
 
 	switch obj.Spec.SignerName {
 	case "k8sd.io/kubelet-serving":
-		expectUsages := sets.New(certv1.UsageServerAuth, certv1.UsageDigitalSignature, certv1.UsageKeyEncipherment)
+		expectUsages := expectedUsages(csr.PublicKey, certv1.UsageServerAuth)
 		if !sets.New(obj.Spec.Usages...).Equal(expectUsages) {
 			return fmt.Errorf("CSR usages %v must match %v", obj.Spec.Usages, expectUsages)
 		}
@@ -76,7 +77,7 @@ func validateCSR(obj *certv1.CertificateSigningRequest, priv *rsa.PrivateKey) er
 		// csr.DNSNames == [...]
 		// csr.IPAddresses == [...]
 	case "k8sd.io/kubelet-client":
-		expectUsages := sets.New(certv1.UsageClientAuth, certv1.UsageDigitalSignature, certv1.UsageKeyEncipherment)
+		expectUsages := expectedUsages(csr.PublicKey, certv1.UsageClientAuth)
 		if !sets.New(obj.Spec.Usages...).Equal(expectUsages) {
 			return fmt.Errorf("CSR usages %v must match %v", obj.Spec.Usages, expectUsages)
 		}
@@ -87,7 +88,7 @@ func validateCSR(obj *certv1.CertificateSigningRequest, priv *rsa.PrivateKey) er
 			return fmt.Errorf("CSR organization %v must match %v", csr.Subject.Organization, []string{"system:nodes"})
 		}
 	case "k8sd.io/kube-proxy-client":
-		expectUsages := sets.New(certv1.UsageClientAuth, certv1.UsageDigitalSignature, certv1.UsageKeyEncipherment)
+		expectUsages := expectedUsages(csr.PublicKey, certv1.UsageClientAuth)
 		if !sets.New(obj.Spec.Usages...).Equal(expectUsages) {
 			return fmt.Errorf("CSR usages %v must match %v", obj.Spec.Usages, expectUsages)
 		}
@@ -102,3 +103,13 @@ func validateCSR(obj *certv1.CertificateSigningRequest, priv *rsa.PrivateKey) er
 	}
 	return nil
 }
+
+// expectedUsages returns the expected usages of a CSR for the given public key.
+// Key encipherment is only expected for RSA keys.
+func expectedUsages(pub crypto.PublicKey, usages ...certv1.KeyUsage) sets.Set[certv1.KeyUsage] {
+	expected := sets.New(usages...).Insert(certv1.UsageDigitalSignature)
+	if _, ok := pub.(*rsa.PublicKey); ok {
+		expected.Insert(certv1.UsageKeyEncipherment)
+	}
+	return expected
+}
@@ -25,7 +25,18 @@ func TestValidateCSREncryption(t *testing.T) {
 			CommonName:   "system:node:valid-node",
 			Organization: []string{"system:nodes"},
 		},
-		2048,
+		pkiutil.KeyAlgorithmRSA2048,
+		nil,
+		nil,
+	)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	ecdsaCSRPEM, _, err := pkiutil.GenerateCSR(
+		pkix.Name{
+			CommonName:   "system:node:valid-node",
+			Organization: []string{"system:nodes"},
+		},
+		pkiutil.KeyAlgorithmECDSAP256,
 		nil,
 		nil,
 	)
@@ -194,6 +205,45 @@ func TestValidateCSREncryption(t *testing.T) {
 			expectErr:        true,
 			expectErrMessage: "CSR usages",
 		},
+		{
+			name: "Valid ECDSA CSR",
+			csr: &certv1.CertificateSigningRequest{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						"k8sd.io/signature": mustCreateEncryptedSignature(g, &key.PublicKey, ecdsaCSRPEM),
+						"k8sd.io/node":      "valid-node",
+					},
+				},
+				Spec: certv1.CertificateSigningRequestSpec{
+					Request:    []byte(ecdsaCSRPEM),
+					Username:   "system:node:valid-node",
+					Groups:     []string{"system:nodes"},
+					SignerName: "k8sd.io/kubelet-client",
+					Usages:     []certv1.KeyUsage{certv1.UsageClientAuth, certv1.UsageDigitalSignature},
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "ECDSA CSR with key encipherment",
+			csr: &certv1.CertificateSigningRequest{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						"k8sd.io/signature": mustCreateEncryptedSignature(g, &key.PublicKey, ecdsaCSRPEM),
+						"k8sd.io/node":      "valid-node",
+					},
+				},
+				Spec: certv1.CertificateSigningRequestSpec{
+					Request:    []byte(ecdsaCSRPEM),
+					Username:   "system:node:valid-node",
+					Groups:     []string{"system:nodes"},
+					SignerName: "k8sd.io/kubelet-client",
+					Usages:     []certv1.KeyUsage{certv1.UsageClientAuth, certv1.UsageDigitalSignature, certv1.UsageKeyEncipherment},
+				},
+			},
+			expectErr:        true,
+			expectErrMessage: "CSR usages",
+		},
 	}
 
 	for _, tt := range tests {
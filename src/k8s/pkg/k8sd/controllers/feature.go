@@ -3,6 +3,7 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	apiv1_annotations "github.com/canonical/k8s-snap-api/api/v1/annotations"
@@ -16,34 +17,24 @@ import (
 	"github.com/canonical/microcluster/v2/state"
 )
 
-// FeatureController manages the lifecycle of built-in Canonical Kubernetes features on a running cluster.
-// The controller has separate trigger channels for each feature.
+// FeatureController manages the lifecycle of the Canonical Kubernetes features on a running cluster.
+// The controller reconciles the features that are registered in the features package, including the plugin
+// features. The controller has separate trigger channels for each feature.
 type FeatureController struct {
 	snap      snap.Snap
 	waitReady func()
 
 	readyCh chan struct{}
 
-	triggerNetworkCh       chan struct{}
-	triggerGatewayCh       chan struct{}
-	triggerIngressCh       chan struct{}
-	triggerLoadBalancerCh  chan struct{}
-	triggerDNSCh           chan struct{}
-	triggerLocalStorageCh  chan struct{}
-	triggerMetricsServerCh chan struct{}
+	// triggerChs are the trigger channels of the features, keyed by feature name.
+	triggerChs map[types.FeatureName]chan struct{}
 
 	// TODO(Hue): (KU-3219) Change these with an atomic bool or something similar.
 	// Because we don't close them when the feature is reconciled, we simply
 	// put something into them. And that thing is going to be gone as soon as we
 	// read from these channels. So "checking to see if a feature is reconciled",
 	// will technically cause it to be considered "not-reconciled" immediately.
-	reconciledNetworkCh       chan struct{}
-	reconciledGatewayCh       chan struct{}
-	reconciledIngressCh       chan struct{}
-	reconciledLoadBalancerCh  chan struct{}
-	reconciledDNSCh           chan struct{}
-	reconciledLocalStorageCh  chan struct{}
-	reconciledMetricsServerCh chan struct{}
+	reconciledChs map[types.FeatureName]chan struct{}
 
 	// reconcileLoopMaxRetryAttempts is the maximum number of retry attempts for the reconcile loop.
 	// Zero or negative values mean unlimited retries.
@@ -56,45 +47,22 @@ func (c *FeatureController) ReadyCh() <-chan struct{} {
 	return c.readyCh
 }
 
-func (c *FeatureController) ReconciledNetworkCh() <-chan struct{} {
-	return c.reconciledNetworkCh
-}
-
-func (c *FeatureController) ReconciledGatewayCh() <-chan struct{} {
-	return c.reconciledGatewayCh
-}
-
-func (c *FeatureController) ReconciledIngressCh() <-chan struct{} {
-	return c.reconciledIngressCh
-}
-
-func (c *FeatureController) ReconciledLoadBalancerCh() <-chan struct{} {
-	return c.reconciledLoadBalancerCh
-}
-
-func (c *FeatureController) ReconciledDNSCh() <-chan struct{} {
-	return c.reconciledDNSCh
-}
-
-func (c *FeatureController) ReconciledLocalStorageCh() <-chan struct{} {
-	return c.reconciledLocalStorageCh
-}
-
-func (c *FeatureController) ReconciledMetricsServerCh() <-chan struct{} {
-	return c.reconciledMetricsServerCh
+// ReconciledChs returns the channels that are notified when a feature is reconciled, keyed by feature name.
+func (c *FeatureController) ReconciledChs() map[types.FeatureName]<-chan struct{} {
+	chs := make(map[types.FeatureName]<-chan struct{}, len(c.reconciledChs))
+	for name, ch := range c.reconciledChs {
+		chs[name] = ch
+	}
+	return chs
 }
 
 type FeatureControllerOpts struct {
 	Snap      snap.Snap
 	WaitReady func()
 
-	TriggerNetworkCh       chan struct{}
-	TriggerGatewayCh       chan struct{}
-	TriggerIngressCh       chan struct{}
-	TriggerLoadBalancerCh  chan struct{}
-	TriggerDNSCh           chan struct{}
-	TriggerLocalStorageCh  chan struct{}
-	TriggerMetricsServerCh chan struct{}
+	// TriggerChs are the trigger channels of the registered features, keyed by feature name.
+	// Features without a trigger channel are not reconciled.
+	TriggerChs map[types.FeatureName]chan struct{}
 
 	// ReconcileLoopMaxRetryAttempts is the maximum number of retry attempts for the reconcile loop.
 	// Zero or negative values mean unlimited retries.
@@ -102,34 +70,32 @@ type FeatureControllerOpts struct {
 }
 
 func NewFeatureController(opts FeatureControllerOpts) *FeatureController {
+	reconciledChs := make(map[types.FeatureName]chan struct{}, len(opts.TriggerChs))
+	for name := range opts.TriggerChs {
+		reconciledChs[name] = make(chan struct{}, 1)
+	}
+
 	return &FeatureController{
 		snap:                          opts.Snap,
 		waitReady:                     opts.WaitReady,
 		readyCh:                       make(chan struct{}),
-		triggerNetworkCh:              opts.TriggerNetworkCh,
-		triggerGatewayCh:              opts.TriggerGatewayCh,
-		triggerIngressCh:              opts.TriggerIngressCh,
-		triggerLoadBalancerCh:         opts.TriggerLoadBalancerCh,
-		triggerDNSCh:                  opts.TriggerDNSCh,
-		triggerLocalStorageCh:         opts.TriggerLocalStorageCh,
-		triggerMetricsServerCh:        opts.TriggerMetricsServerCh,
-		reconciledNetworkCh:           make(chan struct{}, 1),
-		reconciledGatewayCh:           make(chan struct{}, 1),
-		reconciledIngressCh:           make(chan struct{}, 1),
-		reconciledLoadBalancerCh:      make(chan struct{}, 1),
-		reconciledDNSCh:               make(chan struct{}, 1),
-		reconciledLocalStorageCh:      make(chan struct{}, 1),
-		reconciledMetricsServerCh:     make(chan struct{}, 1),
+		triggerChs:                    opts.TriggerChs,
+		reconciledChs:                 reconciledChs,
 		reconcileLoopMaxRetryAttempts: opts.ReconcileLoopMaxRetryAttempts,
 	}
 }
 
+// Run starts a reconcile loop for every registered feature.
+// Run accepts a function that updates the cluster configuration with the changes that result from applying a
+// feature, e.g. the IP address of the DNS service.
 func (c *FeatureController) Run(
 	ctx context.Context,
 	getClusterConfig func(context.Context) (types.ClusterConfig, error),
 	getState func() state.State,
-	notifyDNSChangedIP func(ctx context.Context, dnsIP string) error,
+	updateClusterConfig func(ctx context.Context, update types.ClusterConfig) error,
 	setFeatureStatus func(ctx context.Context, name types.FeatureName, featureStatus types.FeatureStatus) error,
+	getFeatureStatuses func(ctx context.Context) (map[types.FeatureName]types.FeatureStatus, error),
+	recordFeatureReconcile func(ctx context.Context, name types.FeatureName, reconcile types.FeatureReconcile) error,
 ) {
 	ctx = log.NewContext(ctx, log.FromContext(ctx).WithValues("controller", "feature"))
 	log := log.FromContext(ctx)
@@ -138,85 +104,107 @@ func (c *FeatureController) Run(
 
 	s := getState()
 
-	go c.reconcileLoop(ctx, getClusterConfig, setFeatureStatus, features.Network, c.triggerNetworkCh, c.reconciledNetworkCh, func(cfg types.ClusterConfig) (types.FeatureStatus, error) {
-		return features.Implementation.ApplyNetwork(ctx, c.snap, s, cfg.APIServer, cfg.Network, cfg.Annotations)
-	})
-
-	go c.reconcileLoop(ctx, getClusterConfig, setFeatureStatus, features.Gateway, c.triggerGatewayCh, c.reconciledGatewayCh, func(cfg types.ClusterConfig) (types.FeatureStatus, error) {
-		return features.Implementation.ApplyGateway(ctx, c.snap, cfg.Gateway, cfg.Network, cfg.Annotations)
-	})
-
-	go c.reconcileLoop(ctx, getClusterConfig, setFeatureStatus, features.Ingress, c.triggerIngressCh, c.reconciledIngressCh, func(cfg types.ClusterConfig) (types.FeatureStatus, error) {
-		return features.Implementation.ApplyIngress(ctx, c.snap, cfg.Ingress, cfg.Network, cfg.Annotations)
-	})
-
-	go c.reconcileLoop(ctx, getClusterConfig, setFeatureStatus, features.LoadBalancer, c.triggerLoadBalancerCh, c.reconciledLoadBalancerCh, func(cfg types.ClusterConfig) (types.FeatureStatus, error) {
-		return features.Implementation.ApplyLoadBalancer(ctx, c.snap, cfg.LoadBalancer, cfg.Network, cfg.Annotations)
-	})
-
-	go c.reconcileLoop(ctx, getClusterConfig, setFeatureStatus, features.LocalStorage, c.triggerLocalStorageCh, c.reconciledLocalStorageCh, func(cfg types.ClusterConfig) (types.FeatureStatus, error) {
-		return features.Implementation.ApplyLocalStorage(ctx, c.snap, cfg.LocalStorage, cfg.Annotations)
-	})
-
-	go c.reconcileLoop(ctx, getClusterConfig, setFeatureStatus, features.MetricsServer, c.triggerMetricsServerCh, c.reconciledMetricsServerCh, func(cfg types.ClusterConfig) (types.FeatureStatus, error) {
-		return features.Implementation.ApplyMetricsServer(ctx, c.snap, cfg.MetricsServer, cfg.Annotations)
-	})
-
-	go c.reconcileLoop(ctx, getClusterConfig, setFeatureStatus, features.DNS, c.triggerDNSCh, c.reconciledDNSCh, func(cfg types.ClusterConfig) (types.FeatureStatus, error) {
-		featureStatus, dnsIP, err := features.Implementation.ApplyDNS(ctx, c.snap, cfg.DNS, cfg.Kubelet, cfg.Annotations)
+	for _, feature := range features.Registered() {
+		triggerCh, ok := c.triggerChs[feature.Name]
+		if !ok {
+			log.Info("Skipping feature without a trigger channel", "feature", feature.Name)
+			continue
+		}
 
-		if err != nil {
-			return featureStatus, fmt.Errorf("failed to apply DNS configuration: %w", err)
-		} else if dnsIP != "" {
-			if err := notifyDNSChangedIP(ctx, dnsIP); err != nil {
+		go c.reconcileLoop(ctx, getClusterConfig, setFeatureStatus, getFeatureStatuses, recordFeatureReconcile, feature.Name, triggerCh, c.reconciledChs[feature.Name], func(snap snap.Snap, cfg types.ClusterConfig) (types.FeatureStatus, error) {
+			featureStatus, update, err := feature.Apply(ctx, snap, s, cfg)
+			if err != nil || update == nil {
+				return featureStatus, err
+			}
+			if err := updateClusterConfig(ctx, *update); err != nil {
 				// we already have featureStatus.Message which contains wrapped error of the Apply<Feature>
-				// (or empty if no error occurs). we further wrap the error to add the DNS IP change error to the message
-				changeErr := fmt.Errorf("failed to update DNS IP address to %s: %w", dnsIP, err)
-				featureStatus.Message = fmt.Sprintf("%s: %v", featureStatus.Message, changeErr)
-				return featureStatus, changeErr
+				// (or empty if no error occurs). we further wrap the error to add the update error to the message
+				updateErr := fmt.Errorf("failed to update cluster configuration: %w", err)
+				featureStatus.Message = fmt.Sprintf("%s: %v", featureStatus.Message, updateErr)
+				return featureStatus, updateErr
 			}
-		}
-		return featureStatus, nil
-	})
+			return featureStatus, nil
+		})
+	}
 
 	close(c.readyCh)
 	log.Info("Feature controller ready")
 }
 
+// reconcile applies the feature configuration and updates the feature status.
+// The Helm values overrides of the feature are merged into the values of the charts that the feature applies.
+// reconcile returns the record of the reconcile attempt, as well as an error if the configuration could not be applied.
 func (c *FeatureController) reconcile(
 	ctx context.Context,
+	featureName types.FeatureName,
 	getClusterConfig func(context.Context) (types.ClusterConfig, error),
-	apply func(cfg types.ClusterConfig) (types.FeatureStatus, error),
+	apply func(snap snap.Snap, cfg types.ClusterConfig) (types.FeatureStatus, error),
 	updateFeatureStatus func(context.Context, types.FeatureStatus) error,
-) error {
+) (types.FeatureReconcile, error) {
+	reconcile := types.FeatureReconcile{StartedAt: time.Now()}
+
 	cfg, err := getClusterConfig(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to retrieve cluster configuration: %w", err)
+		err = fmt.Errorf("failed to retrieve cluster configuration: %w", err)
+		reconcile.FinishedAt = time.Now()
+		reconcile.Result = types.FeatureReconcileFailed
+		reconcile.Error = err.Error()
+		return reconcile, err
 	}
 
-	status, applyErr := apply(cfg)
+	overrides, err := cfg.ValuesOverrides.Get(featureName)
+	if err != nil {
+		reconcile.FinishedAt = time.Now()
+		reconcile.Result = types.FeatureReconcileFailed
+		reconcile.Error = err.Error()
+		return reconcile, err
+	}
+
+	featureSnap := newFeatureSnap(c.snap, overrides)
+	status, applyErr := apply(featureSnap, cfg)
+	if unknown := featureSnap.UnknownOverrides(); applyErr == nil && len(unknown) > 0 {
+		applyErr = fmt.Errorf("values overrides for charts that are not managed by the feature: %s", strings.Join(unknown, ", "))
+		status.Message = applyErr.Error()
+	}
 	if err := updateFeatureStatus(ctx, status); err != nil {
 		// NOTE (hue): status update errors are not returned but only logged. we might need some retry logic in the future.
 		log.FromContext(ctx).WithValues("message", status.Message, "applied-successfully", applyErr == nil).Error(err, "Failed to update feature status")
 	}
 
+	reconcile.FinishedAt = time.Now()
+	reconcile.Version = status.Version
+	reconcile.ValuesHash = featureSnap.ValuesHash()
+	switch {
+	case applyErr != nil:
+		reconcile.Result = types.FeatureReconcileFailed
+		reconcile.Error = applyErr.Error()
+	case status.Enabled:
+		reconcile.Result = types.FeatureReconcileEnabled
+		reconcile.Values = featureSnap.Values()
+	default:
+		reconcile.Result = types.FeatureReconcileDisabled
+	}
+
 	if applyErr != nil {
-		return fmt.Errorf("failed to apply configuration: %w", applyErr)
+		return reconcile, fmt.Errorf("failed to apply configuration: %w", applyErr)
 	}
 
-	return nil
+	return reconcile, nil
 }
 
 func (c *FeatureController) reconcileLoop(
 	ctx context.Context,
 	getClusterConfig func(context.Context) (types.ClusterConfig, error),
 	setFeatureStatus func(ctx context.Context, name types.FeatureName, status types.FeatureStatus) error,
+	getFeatureStatuses func(ctx context.Context) (map[types.FeatureName]types.FeatureStatus, error),
+	recordFeatureReconcile func(ctx context.Context, name types.FeatureName, reconcile types.FeatureReconcile) error,
 	featureName types.FeatureName,
 	triggerCh chan struct{},
 	reconciledCh chan struct{},
-	apply func(cfg types.ClusterConfig) (types.FeatureStatus, error),
+	apply func(snap snap.Snap, cfg types.ClusterConfig) (types.FeatureStatus, error),
 ) {
 	var attempts int
+	var lastResult types.FeatureReconcileResult
 
 	for {
 		select {
@@ -240,9 +228,40 @@ func (c *FeatureController) reconcileLoop(
 				continue
 			}
 
-			if err := c.reconcile(ctx, getClusterConfig, apply, func(ctx context.Context, status types.FeatureStatus) error {
+			startedAt := time.Now()
+			waitingMessage, err := c.isWaiting(ctx, getClusterConfig, getFeatureStatuses, featureName, func(ctx context.Context, status types.FeatureStatus) error {
+				return setFeatureStatus(ctx, featureName, status)
+			})
+			if err != nil {
+				log.Error(err, "Failed to check if feature is waiting for other features")
+				// notify triggerCh after 5 seconds to retry
+				time.AfterFunc(5*time.Second, func() { utils.MaybeNotify(triggerCh) })
+				continue
+			}
+
+			if waitingMessage != "" {
+				// only the start of the wait is recorded, to not flood the history with the fallback retries.
+				if lastResult != types.FeatureReconcileWaiting {
+					c.recordReconcile(ctx, recordFeatureReconcile, featureName, types.FeatureReconcile{
+						StartedAt:  startedAt,
+						FinishedAt: time.Now(),
+						Result:     types.FeatureReconcileWaiting,
+						Error:      waitingMessage,
+					}, &lastResult)
+				}
+
+				// the feature is resumed when the features it waits for are reconciled.
+				// notify triggerCh after 30 seconds as a fallback.
+				time.AfterFunc(30*time.Second, func() { utils.MaybeNotify(triggerCh) })
+				continue
+			}
+
+			reconcile, err := c.reconcile(ctx, featureName, getClusterConfig, apply, func(ctx context.Context, status types.FeatureStatus) error {
 				return setFeatureStatus(ctx, featureName, status)
-			}); err != nil {
+			})
+			c.recordReconcile(ctx, recordFeatureReconcile, featureName, reconcile, &lastResult)
+
+			if err != nil {
 				log.Error(err, "Failed to apply feature configuration")
 				attempts++
 
@@ -266,12 +285,82 @@ func (c *FeatureController) reconcileLoop(
 			} else {
 				utils.MaybeNotify(reconciledCh)
 				attempts = 0
+
+				if err := c.notifyWaitingFeatures(ctx, getFeatureStatuses, featureName); err != nil {
+					log.Error(err, "Failed to notify features waiting for feature")
+				}
 			}
 
 		}
 	}
 }
 
+// isWaiting checks if the feature is waiting for the features it depends on to be enabled, or for the features
+// that depend on it to be disabled. A waiting feature is marked as such in its feature status.
+// isWaiting returns the reason the feature is waiting, or an empty string if the feature is not waiting.
+func (c *FeatureController) isWaiting(
+	ctx context.Context,
+	getClusterConfig func(context.Context) (types.ClusterConfig, error),
+	getFeatureStatuses func(context.Context) (map[types.FeatureName]types.FeatureStatus, error),
+	featureName types.FeatureName,
+	updateFeatureStatus func(context.Context, types.FeatureStatus) error,
+) (string, error) {
+	cfg, err := getClusterConfig(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to retrieve cluster configuration: %w", err)
+	}
+
+	statuses, err := getFeatureStatuses(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to retrieve feature statuses: %w", err)
+	}
+
+	waitingFor := features.WaitingFor(cfg, statuses, featureName)
+	if waitingFor == "" {
+		return "", nil
+	}
+
+	target := "disabled"
+	if features.Enabled(cfg, featureName) {
+		target = "enabled"
+	}
+
+	log.FromContext(ctx).Info("Feature is waiting for another feature", "feature", featureName, "waiting-for", waitingFor)
+
+	status := statuses[featureName]
+	status.Waiting = true
+	status.Message = fmt.Sprintf("waiting for %s to be %s", waitingFor, target)
+	if err := updateFeatureStatus(ctx, status); err != nil {
+		// NOTE: status update errors are not returned but only logged, as in reconcile.
+		log.FromContext(ctx).WithValues("message", status.Message).Error(err, "Failed to update feature status")
+	}
+
+	return status.Message, nil
+}
+
+// notifyWaitingFeatures triggers the dependencies and dependents of a feature that are waiting for other features.
+func (c *FeatureController) notifyWaitingFeatures(
+	ctx context.Context,
+	getFeatureStatuses func(context.Context) (map[types.FeatureName]types.FeatureStatus, error),
+	featureName types.FeatureName,
+) error {
+	statuses, err := getFeatureStatuses(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve feature statuses: %w", err)
+	}
+
+	for _, name := range append(features.DependentsOf(featureName), features.DependenciesOf(featureName)...) {
+		if !statuses[name].Waiting {
+			continue
+		}
+		if ch, ok := c.triggerChs[name]; ok && ch != nil {
+			utils.MaybeNotify(ch)
+		}
+	}
+
+	return nil
+}
+
 // isBlocked checks if the feature controller is blocked by an in-progress upgrade.
 // If an upgrade is in progress, the feature controller will not apply any configuration changes.
 func (c *FeatureController) isBlocked(ctx context.Context, getClusterConfig func(context.Context) (types.ClusterConfig, error)) (bool, error) {
@@ -0,0 +1,67 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/k8s/pkg/log"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// featureEventReasons are the reasons of the Kubernetes events that are emitted when the
+// reconcile result of a feature changes.
+var featureEventReasons = map[types.FeatureReconcileResult]string{
+	types.FeatureReconcileEnabled:  "FeatureEnabled",
+	types.FeatureReconcileDisabled: "FeatureDisabled",
+	types.FeatureReconcileFailed:   "FeatureFailed",
+	types.FeatureReconcileWaiting:  "FeatureWaiting",
+}
+
+// recordReconcile records a reconcile attempt in the history of the feature.
+// recordReconcile emits a Kubernetes event in the kube-system namespace if the result differs from lastResult.
+// Errors are only logged, as they must not affect the reconciliation of the feature.
+func (c *FeatureController) recordReconcile(
+	ctx context.Context,
+	recordFeatureReconcile func(context.Context, types.FeatureName, types.FeatureReconcile) error,
+	featureName types.FeatureName,
+	reconcile types.FeatureReconcile,
+	lastResult *types.FeatureReconcileResult,
+) {
+	log := log.FromContext(ctx).WithValues("feature", featureName, "result", reconcile.Result)
+
+	if err := recordFeatureReconcile(ctx, featureName, reconcile); err != nil {
+		log.Error(err, "Failed to record feature reconcile")
+	}
+
+	if reconcile.Result == *lastResult {
+		return
+	}
+	*lastResult = reconcile.Result
+
+	eventType := corev1.EventTypeNormal
+	message := fmt.Sprintf("Feature %s is %s", featureName, reconcile.Result)
+	switch reconcile.Result {
+	case types.FeatureReconcileFailed:
+		eventType = corev1.EventTypeWarning
+		message = fmt.Sprintf("Failed to reconcile feature %s: %s", featureName, reconcile.Error)
+	case types.FeatureReconcileWaiting:
+		message = fmt.Sprintf("Feature %s is %s", featureName, reconcile.Error)
+	}
+	if reconcile.Version != "" && reconcile.Result == types.FeatureReconcileEnabled {
+		message = fmt.Sprintf("%s (version %s)", message, reconcile.Version)
+	}
+
+	client, err := c.snap.KubernetesClient("")
+	if err != nil {
+		log.Error(err, "Failed to create Kubernetes client to emit feature event")
+		return
+	}
+	if err := client.CreateEvent(ctx, corev1.ObjectReference{
+		Kind:      "Feature",
+		Namespace: "kube-system",
+		Name:      string(featureName),
+	}, eventType, featureEventReasons[reconcile.Result], message); err != nil {
+		log.Error(err, "Failed to emit feature event")
+	}
+}
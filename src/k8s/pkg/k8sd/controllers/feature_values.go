@@ -0,0 +1,108 @@
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"sort"
+	"sync"
+
+	"github.com/canonical/k8s/pkg/client/helm"
+	"github.com/canonical/k8s/pkg/snap"
+)
+
+// featureSnap is the snap.Snap that is used to reconcile a single feature.
+// featureSnap deep-merges the Helm values overrides of each chart into the values of the chart when the feature
+// installs or upgrades it. featureSnap records the effective values and a hash of the applied values.
+type featureSnap struct {
+	snap.Snap
+	// overrides are the Helm values overrides of the feature, keyed by chart name.
+	overrides map[string]map[string]any
+
+	mu     sync.Mutex
+	hash   hash.Hash
+	used   bool
+	charts map[string]struct{}
+	values map[string]map[string]any
+}
+
+func newFeatureSnap(s snap.Snap, overrides map[string]map[string]any) *featureSnap {
+	return &featureSnap{Snap: s, overrides: overrides, hash: sha256.New(), charts: map[string]struct{}{}, values: map[string]map[string]any{}}
+}
+
+func (s *featureSnap) HelmClient() helm.Client {
+	return &featureHelmClient{Client: s.Snap.HelmClient(), snap: s}
+}
+
+// ValuesHash returns the hash of the values of the applied Helm charts, or an empty string if no charts were applied.
+func (s *featureSnap) ValuesHash() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.used {
+		return ""
+	}
+	return hex.EncodeToString(s.hash.Sum(nil))[:12]
+}
+
+// UnknownOverrides returns the sorted names of the charts with values overrides that the feature did not apply.
+func (s *featureSnap) UnknownOverrides() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var names []string
+	for name := range s.overrides {
+		if _, ok := s.charts[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Values returns the effective values of the Helm charts that were installed or upgraded, keyed by chart name.
+func (s *featureSnap) Values() map[string]map[string]any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	values := make(map[string]map[string]any, len(s.values))
+	for name, v := range s.values {
+		values[name] = v
+	}
+	return values
+}
+
+type featureHelmClient struct {
+	helm.Client
+	snap *featureSnap
+}
+
+func (c *featureHelmClient) Apply(ctx context.Context, chart helm.InstallableChart, desired helm.State, values map[string]any) (bool, error) {
+	if overrides := c.snap.overrides[chart.Name]; desired != helm.StateDeleted && len(overrides) > 0 {
+		values = helm.MergeValues(values, overrides)
+	}
+
+	// json.Marshal sorts map keys, so equal values always produce the same hash.
+	b, err := json.Marshal(values)
+	if err != nil {
+		b = []byte(fmt.Sprintf("%v", values))
+	}
+
+	c.snap.mu.Lock()
+	fmt.Fprintf(c.snap.hash, "%s/%s/%v:", chart.Namespace, chart.Name, desired)
+	c.snap.hash.Write(b)
+	c.snap.used = true
+	c.snap.charts[chart.Name] = struct{}{}
+	c.snap.mu.Unlock()
+
+	changed, err := c.Client.Apply(ctx, chart, desired, values)
+	if err == nil && desired != helm.StateDeleted {
+		c.snap.mu.Lock()
+		c.snap.values[chart.Name] = values
+		c.snap.mu.Unlock()
+	}
+	return changed, err
+}
@@ -0,0 +1,127 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/k8s/pkg/log"
+	"github.com/canonical/k8s/pkg/snap"
+	snaputil "github.com/canonical/k8s/pkg/snap/util"
+)
+
+// NetworkMigrationController switches the nodes of the cluster to another network provider, while a migration pod
+// CIDR is configured. The controller advances the migration by a single step on every trigger, so that the feature
+// controller is not blocked while the nodes are drained and switched.
+type NetworkMigrationController struct {
+	snap      snap.Snap
+	waitReady func()
+	triggerCh <-chan time.Time
+	// reconciledCh is used to notify that the controller has finished its reconciliation loop.
+	reconciledCh chan struct{}
+}
+
+// NewNetworkMigrationController creates a new controller.
+// triggerCh is typically a `time.NewTicker(<interval>).C`.
+func NewNetworkMigrationController(snap snap.Snap, waitReady func(), triggerCh <-chan time.Time) *NetworkMigrationController {
+	return &NetworkMigrationController{
+		snap:         snap,
+		waitReady:    waitReady,
+		triggerCh:    triggerCh,
+		reconciledCh: make(chan struct{}, 1),
+	}
+}
+
+// Run starts the controller.
+// Run accepts a context to manage the lifecycle of the controller.
+// Run accepts a function that reports whether this node should switch the nodes. This is used so that only a single
+// control plane node (e.g. the k8sd database leader) switches the nodes.
+// Run accepts a function that retrieves the current cluster configuration.
+// Run accepts a function that advances the migration, and returns the network feature status and whether the
+// migration is complete.
+// Run accepts a function that records the network feature status.
+// Run accepts a function that makes the migration pod CIDR the pod CIDR of the cluster once the migration is complete.
+// Run will loop every time the trigger channel is.
+func (c *NetworkMigrationController) Run(
+	ctx context.Context,
+	shouldMigrate func(context.Context) (bool, error),
+	getClusterConfig func(context.Context) (types.ClusterConfig, error),
+	migrateNetwork func(context.Context, types.ClusterConfig) (types.FeatureStatus, bool, error),
+	setNetworkStatus func(context.Context, types.FeatureStatus) error,
+	completeMigration func(context.Context, types.ClusterConfig) error,
+) {
+	c.waitReady()
+
+	ctx = log.NewContext(ctx, log.FromContext(ctx).WithValues("controller", "network-migration"))
+	log := log.FromContext(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-c.triggerCh:
+		}
+
+		if isWorker, err := snaputil.IsWorker(c.snap); err != nil {
+			log.Error(err, "Failed to check if running on a worker node")
+			continue
+		} else if isWorker {
+			log.Info("Stopping on worker node")
+			return
+		}
+
+		if err := c.reconcile(ctx, shouldMigrate, getClusterConfig, migrateNetwork, setNetworkStatus, completeMigration); err != nil {
+			log.Error(err, "Failed to switch network provider")
+		}
+
+		select {
+		case c.reconciledCh <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (c *NetworkMigrationController) reconcile(
+	ctx context.Context,
+	shouldMigrate func(context.Context) (bool, error),
+	getClusterConfig func(context.Context) (types.ClusterConfig, error),
+	migrateNetwork func(context.Context, types.ClusterConfig) (types.FeatureStatus, bool, error),
+	setNetworkStatus func(context.Context, types.FeatureStatus) error,
+	completeMigration func(context.Context, types.ClusterConfig) error,
+) error {
+	if ok, err := shouldMigrate(ctx); err != nil {
+		return fmt.Errorf("failed to check if node should switch network provider: %w", err)
+	} else if !ok {
+		return nil
+	}
+
+	config, err := getClusterConfig(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve cluster configuration: %w", err)
+	}
+	if config.Network.GetMigrationPodCIDR() == "" {
+		return nil
+	}
+
+	status, done, migrateErr := migrateNetwork(ctx, config)
+	if err := setNetworkStatus(ctx, status); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to update network status", "message", status.Message)
+	}
+	if migrateErr != nil {
+		return migrateErr
+	}
+
+	if done {
+		if err := completeMigration(ctx, config); err != nil {
+			return fmt.Errorf("failed to set migration pod CIDR as pod CIDR: %w", err)
+		}
+		log.FromContext(ctx).Info("Switched network provider", "provider", config.Network.GetProvider())
+	}
+	return nil
+}
+
+// ReconciledCh returns the channel where the controller pushes when a reconciliation loop is finished.
+func (c *NetworkMigrationController) ReconciledCh() <-chan struct{} {
+	return c.reconciledCh
+}
@@ -0,0 +1,103 @@
+package controllers_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/canonical/k8s/pkg/k8sd/controllers"
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/k8s/pkg/snap/mock"
+	"github.com/canonical/k8s/pkg/utils"
+	. "github.com/onsi/gomega"
+)
+
+func TestNetworkMigrationController(t *testing.T) {
+	s := &mock.Snap{
+		Mock: mock.Mock{
+			LockFilesDir: filepath.Join(t.TempDir(), "locks"),
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	triggerCh := make(chan time.Time)
+	config := types.ClusterConfig{Network: types.Network{PodCIDR: utils.Pointer("10.1.0.0/16")}}
+	var migrateErr error
+	var steps int
+	var statuses []string
+	var completed []string
+
+	ctrl := controllers.NewNetworkMigrationController(s, func() {}, triggerCh)
+	go ctrl.Run(
+		ctx,
+		func(context.Context) (bool, error) { return true, nil },
+		func(context.Context) (types.ClusterConfig, error) { return config, nil },
+		func(context.Context, types.ClusterConfig) (types.FeatureStatus, bool, error) {
+			steps++
+			if migrateErr != nil {
+				return types.FeatureStatus{Message: "failed"}, false, migrateErr
+			}
+			return types.FeatureStatus{Enabled: true, Message: "switching"}, steps == 3, nil
+		},
+		func(_ context.Context, status types.FeatureStatus) error {
+			statuses = append(statuses, status.Message)
+			return nil
+		},
+		func(_ context.Context, config types.ClusterConfig) error {
+			completed = append(completed, config.Network.GetMigrationPodCIDR())
+			return nil
+		},
+	)
+
+	reconcile := func(g Gomega) {
+		select {
+		case triggerCh <- time.Now():
+		case <-time.After(channelSendTimeout):
+			g.Expect(false).To(BeTrue(), "Timed out while attempting to trigger controller reconcile loop")
+		}
+
+		select {
+		case <-ctrl.ReconciledCh():
+		case <-time.After(5 * time.Second):
+			g.Expect(false).To(BeTrue(), "Time out while waiting for the reconcile to complete")
+		}
+	}
+
+	t.Run("NoMigration", func(t *testing.T) {
+		g := NewWithT(t)
+
+		reconcile(g)
+		g.Expect(steps).To(BeZero())
+		g.Expect(statuses).To(BeEmpty())
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		g := NewWithT(t)
+
+		config.Network.MigrationPodCIDR = utils.Pointer("10.100.0.0/16")
+		migrateErr = errors.New("failed to list nodes")
+		reconcile(g)
+		g.Expect(steps).To(Equal(1))
+		g.Expect(statuses).To(Equal([]string{"failed"}))
+		g.Expect(completed).To(BeEmpty())
+	})
+
+	t.Run("Migrate", func(t *testing.T) {
+		g := NewWithT(t)
+
+		// the migration advances by a single step on every trigger
+		migrateErr = nil
+		reconcile(g)
+		g.Expect(steps).To(Equal(2))
+		g.Expect(completed).To(BeEmpty())
+
+		reconcile(g)
+		g.Expect(steps).To(Equal(3))
+		g.Expect(statuses).To(Equal([]string{"failed", "switching", "switching"}))
+		g.Expect(completed).To(Equal([]string{"10.100.0.0/16"}))
+	})
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/canonical/k8s/pkg/log"
 	"github.com/canonical/k8s/pkg/snap"
 	snaputil "github.com/canonical/k8s/pkg/snap/util"
+	"github.com/canonical/k8s/pkg/snap/util/cleanup"
 	"github.com/canonical/k8s/pkg/utils/control"
 	v1 "k8s.io/api/core/v1"
 )
@@ -118,6 +119,45 @@ func (c *NodeConfigurationController) reconcile(ctx context.Context, configMap *
 		}
 	}
 
+	if config.KubeProxyEnabled != nil {
+		if err := c.reconcileKubeProxy(ctx, *config.KubeProxyEnabled); err != nil {
+			return fmt.Errorf("failed to reconcile kube-proxy: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// reconcileKubeProxy stops kube-proxy and removes its rules if kube-proxy is replaced by the CNI,
+// and starts it again otherwise.
+func (c *NodeConfigurationController) reconcileKubeProxy(ctx context.Context, enabled bool) error {
+	log := log.FromContext(ctx)
+
+	disabled, err := snaputil.IsKubeProxyDisabled(c.snap)
+	if err != nil {
+		return fmt.Errorf("failed to check if kube-proxy is disabled: %w", err)
+	}
+
+	switch {
+	case !enabled && !disabled:
+		log.Info("Stopping kube-proxy, as it is replaced by the CNI")
+		if err := c.snap.StopServices(ctx, []string{"kube-proxy"}); err != nil {
+			return fmt.Errorf("failed to stop kube-proxy: %w", err)
+		}
+		cleanup.RemoveKubeProxyRules(ctx, c.snap)
+		if err := snaputil.MarkKubeProxyDisabled(c.snap, true); err != nil {
+			return err
+		}
+	case enabled && disabled:
+		log.Info("Starting kube-proxy")
+		if err := c.snap.StartServices(ctx, []string{"kube-proxy"}); err != nil {
+			return fmt.Errorf("failed to start kube-proxy: %w", err)
+		}
+		if err := snaputil.MarkKubeProxyDisabled(c.snap, false); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
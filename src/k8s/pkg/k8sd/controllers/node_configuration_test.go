@@ -239,3 +239,74 @@ func TestConfigPropagation(t *testing.T) {
 		})
 	}
 }
+
+func TestConfigPropagationKubeProxy(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clientset := fake.NewSimpleClientset()
+	watcher := watch.NewFake()
+	clientset.PrependWatchReactor("configmaps", k8stesting.DefaultWatchReactor(watcher, nil))
+
+	s := &mock.Snap{
+		Mock: mock.Mock{
+			ServiceArgumentsDir:  filepath.Join(t.TempDir(), "args"),
+			LockFilesDir:         t.TempDir(),
+			UID:                  os.Getuid(),
+			GID:                  os.Getgid(),
+			KubernetesNodeClient: &kubernetes.Client{Interface: clientset},
+		},
+	}
+
+	g := NewWithT(t)
+	g.Expect(setup.EnsureAllDirectories(s)).To(Succeed())
+
+	ctrl := controllers.NewNodeConfigurationController(s, func() {})
+	go ctrl.Run(ctx, func(ctx context.Context) (*rsa.PublicKey, error) { return nil, nil })
+	defer watcher.Stop()
+
+	for _, tc := range []struct {
+		name        string
+		enabled     string
+		expectStop  bool
+		expectStart bool
+	}{
+		{name: "Disable", enabled: "false", expectStop: true},
+		{name: "KeepDisabled", enabled: "false"},
+		{name: "Enable", enabled: "true", expectStart: true},
+		{name: "KeepEnabled", enabled: "true"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s.StartServicesCalledWith = nil
+			s.StopServicesCalledWith = nil
+
+			g := NewWithT(t)
+
+			watcher.Add(&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "k8sd-config", Namespace: "kube-system"},
+				Data:       map[string]string{"kube-proxy-enabled": tc.enabled},
+			})
+
+			select {
+			case <-ctrl.ReconciledCh():
+			case <-time.After(channelSendTimeout):
+				g.Fail("Time out while waiting for the reconcile to complete")
+			}
+
+			if tc.expectStop {
+				g.Expect(s.StopServicesCalledWith).To(Equal([][]string{{"kube-proxy"}}))
+			} else {
+				g.Expect(s.StopServicesCalledWith).To(BeEmpty())
+			}
+			if tc.expectStart {
+				g.Expect(s.StartServicesCalledWith).To(Equal([][]string{{"kube-proxy"}}))
+			} else {
+				g.Expect(s.StartServicesCalledWith).To(BeEmpty())
+			}
+
+			disabled, err := snaputil.IsKubeProxyDisabled(s)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(disabled).To(Equal(tc.enabled == "false"))
+		})
+	}
+}
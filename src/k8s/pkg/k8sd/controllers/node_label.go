@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/canonical/k8s/pkg/k8sd/types"
 	"github.com/canonical/k8s/pkg/log"
 	"github.com/canonical/k8s/pkg/snap"
 	snaputil "github.com/canonical/k8s/pkg/snap/util"
@@ -18,14 +19,20 @@ type NodeLabelController struct {
 	// reconciledCh is used to notify that the controller has finished its reconciliation loop.
 	reconciledCh chan struct{}
 	getNodeName  func(ctx context.Context) (string, error)
+	// cleanupNetwork removes the node-local state of a network provider, after the node was switched to another one.
+	cleanupNetwork func(ctx context.Context, snap snap.Snap, provider string) error
+	// cleanupNetworkConfig removes the CNI configuration of a network provider, while the node is switched to another one.
+	cleanupNetworkConfig func(ctx context.Context, snap snap.Snap, provider string) error
 }
 
-func NewNodeLabelController(snap snap.Snap, waitReady func(), getNodeName func(ctx context.Context) (string, error)) *NodeLabelController {
+func NewNodeLabelController(snap snap.Snap, waitReady func(), getNodeName func(ctx context.Context) (string, error), cleanupNetwork func(ctx context.Context, snap snap.Snap, provider string) error, cleanupNetworkConfig func(ctx context.Context, snap snap.Snap, provider string) error) *NodeLabelController {
 	return &NodeLabelController{
-		snap:         snap,
-		waitReady:    waitReady,
-		reconciledCh: make(chan struct{}, 1),
-		getNodeName:  getNodeName,
+		snap:                 snap,
+		waitReady:            waitReady,
+		reconciledCh:         make(chan struct{}, 1),
+		getNodeName:          getNodeName,
+		cleanupNetwork:       cleanupNetwork,
+		cleanupNetworkConfig: cleanupNetworkConfig,
 	}
 }
 
@@ -124,11 +131,63 @@ func (c *NodeLabelController) updateDqliteFailureDomain(ctx context.Context, fai
 	return nil
 }
 
+// reconcileNetworkProvider records the network provider that the node was switched to, and removes the CNI
+// configuration or cleans up the previous network provider if requested by the network feature.
+func (c *NodeLabelController) reconcileNetworkProvider(ctx context.Context, node *v1.Node) error {
+	log := log.FromContext(ctx)
+
+	if provider, ok := node.Labels[types.NodeLabelNetworkProvider]; ok {
+		current, err := snaputil.GetNetworkProvider(c.snap)
+		if err != nil {
+			return err
+		}
+		if current != provider {
+			if err := snaputil.SetNetworkProvider(c.snap, provider); err != nil {
+				return err
+			}
+		}
+	}
+
+	if previous, ok := node.Annotations[types.NodeAnnotationNetworkSwitch]; ok {
+		log.Info("Removing CNI configuration of previous network provider", "provider", previous)
+		if err := c.cleanupNetworkConfig(ctx, c.snap, previous); err != nil {
+			return fmt.Errorf("failed to remove CNI configuration of network provider %s: %w", previous, err)
+		}
+		if err := c.removeNodeAnnotation(ctx, node.Name, types.NodeAnnotationNetworkSwitch); err != nil {
+			return fmt.Errorf("failed to mark CNI configuration of network provider %s as removed: %w", previous, err)
+		}
+	}
+
+	if previous, ok := node.Annotations[types.NodeAnnotationNetworkCleanup]; ok {
+		log.Info("Cleaning up previous network provider", "provider", previous)
+		if err := c.cleanupNetwork(ctx, c.snap, previous); err != nil {
+			return fmt.Errorf("failed to clean up network provider %s: %w", previous, err)
+		}
+		if err := c.removeNodeAnnotation(ctx, node.Name, types.NodeAnnotationNetworkCleanup); err != nil {
+			return fmt.Errorf("failed to mark network provider %s as cleaned up: %w", previous, err)
+		}
+	}
+
+	return nil
+}
+
+func (c *NodeLabelController) removeNodeAnnotation(ctx context.Context, nodeName string, key string) error {
+	client, err := c.snap.KubernetesNodeClient("")
+	if err != nil {
+		return fmt.Errorf("failed to create Kubernetes client: %w", err)
+	}
+	return client.RemoveNodeAnnotation(ctx, nodeName, key)
+}
+
 func (c *NodeLabelController) reconcile(ctx context.Context, node *v1.Node) error {
 	if err := c.reconcileFailureDomain(ctx, node); err != nil {
 		return fmt.Errorf("failed to reconcile failure domain: %w", err)
 	}
 
+	if err := c.reconcileNetworkProvider(ctx, node); err != nil {
+		return fmt.Errorf("failed to reconcile network provider: %w", err)
+	}
+
 	return nil
 }
 
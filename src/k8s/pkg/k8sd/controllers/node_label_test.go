@@ -11,6 +11,8 @@ import (
 	"github.com/canonical/k8s/pkg/client/kubernetes"
 	"github.com/canonical/k8s/pkg/k8sd/controllers"
 	"github.com/canonical/k8s/pkg/k8sd/setup"
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/k8s/pkg/snap"
 	"github.com/canonical/k8s/pkg/snap/mock"
 	snaputil "github.com/canonical/k8s/pkg/snap/util"
 	"github.com/canonical/k8s/pkg/utils"
@@ -107,7 +109,11 @@ func TestAvailabilityZoneLabel(t *testing.T) {
 	g.Expect(os.MkdirAll(k8sdDbDir, 0o700)).To(Succeed())
 
 	nodeName := "test-node-name"
-	ctrl := controllers.NewNodeLabelController(s, func() {}, func(context.Context) (string, error) { return nodeName, nil })
+	ctrl := controllers.NewNodeLabelController(s, func() {}, func(context.Context) (string, error) { return nodeName, nil }, func(context.Context, snap.Snap, string) error {
+		return fmt.Errorf("unexpected network cleanup")
+	}, func(context.Context, snap.Snap, string) error {
+		return fmt.Errorf("unexpected network config cleanup")
+	})
 
 	go ctrl.Run(ctx)
 	defer watcher.Stop()
@@ -174,3 +180,73 @@ func TestAvailabilityZoneLabel(t *testing.T) {
 		})
 	}
 }
+
+func TestNetworkProviderSwitch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	g := NewWithT(t)
+
+	nodeName := "test-node-name"
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: nodeName,
+			Labels: map[string]string{
+				types.NodeLabelNetworkProvider: "calico",
+			},
+			Annotations: map[string]string{
+				types.NodeAnnotationNetworkSwitch:  "cilium",
+				types.NodeAnnotationNetworkCleanup: "cilium",
+			},
+		},
+	}
+
+	clientset := fake.NewSimpleClientset(node)
+	watcher := watch.NewFake()
+	clientset.PrependWatchReactor("nodes", k8stesting.DefaultWatchReactor(watcher, nil))
+
+	s := &mock.Snap{
+		Mock: mock.Mock{
+			K8sdStateDir:         filepath.Join(t.TempDir(), "k8sd"),
+			K8sDqliteStateDir:    filepath.Join(t.TempDir(), "k8s-dqlite"),
+			LockFilesDir:         filepath.Join(t.TempDir(), "lock"),
+			UID:                  os.Getuid(),
+			GID:                  os.Getgid(),
+			KubernetesNodeClient: &kubernetes.Client{Interface: clientset},
+		},
+	}
+	g.Expect(setup.EnsureAllDirectories(s)).To(Succeed())
+	g.Expect(os.MkdirAll(filepath.Join(s.K8sdStateDir(), "database"), 0o700)).To(Succeed())
+
+	var cleanedUp, removedConfig []string
+	ctrl := controllers.NewNodeLabelController(s, func() {}, func(context.Context) (string, error) { return nodeName, nil }, func(_ context.Context, _ snap.Snap, provider string) error {
+		cleanedUp = append(cleanedUp, provider)
+		return nil
+	}, func(_ context.Context, _ snap.Snap, provider string) error {
+		removedConfig = append(removedConfig, provider)
+		return nil
+	})
+
+	go ctrl.Run(ctx)
+	defer watcher.Stop()
+
+	watcher.Add(node)
+
+	select {
+	case <-ctrl.ReconciledCh():
+	case <-time.After(channelSendTimeout):
+		g.Fail("Time out while waiting for the reconcile to complete")
+	}
+
+	g.Expect(cleanedUp).To(Equal([]string{"cilium"}))
+	g.Expect(removedConfig).To(Equal([]string{"cilium"}))
+
+	provider, err := snaputil.GetNetworkProvider(s)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(provider).To(Equal("calico"))
+
+	updated, err := clientset.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(updated.Annotations).ToNot(HaveKey(types.NodeAnnotationNetworkSwitch))
+	g.Expect(updated.Annotations).ToNot(HaveKey(types.NodeAnnotationNetworkCleanup))
+}
@@ -0,0 +1,80 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/canonical/k8s/pkg/log"
+	"github.com/canonical/k8s/pkg/snap"
+	snaputil "github.com/canonical/k8s/pkg/snap/util"
+)
+
+// PrometheusCRDController periodically checks if the Prometheus Operator CRDs are installed in the cluster.
+// The controller notifies the metrics-server feature when the CRDs are installed or removed, so that the certificate
+// expiry alerts are deployed even if Prometheus is installed after the feature.
+type PrometheusCRDController struct {
+	snap      snap.Snap
+	waitReady func()
+	triggerCh <-chan time.Time
+	// reconciledCh is used to notify that the controller has finished its reconciliation loop.
+	reconciledCh chan struct{}
+}
+
+// NewPrometheusCRDController creates a new controller.
+// triggerCh is typically a `time.NewTicker(<check-interval>).C`.
+func NewPrometheusCRDController(snap snap.Snap, waitReady func(), triggerCh <-chan time.Time) *PrometheusCRDController {
+	return &PrometheusCRDController{
+		snap:         snap,
+		waitReady:    waitReady,
+		triggerCh:    triggerCh,
+		reconciledCh: make(chan struct{}, 1),
+	}
+}
+
+// Run starts the controller.
+// Run accepts a context to manage the lifecycle of the controller.
+// Run accepts a function that checks if the Prometheus Operator CRDs are installed.
+// Run accepts a function that notifies the metrics-server feature to reconcile.
+// Run will loop every time the trigger channel is.
+func (c *PrometheusCRDController) Run(ctx context.Context, crdInstalled func(context.Context) (bool, error), notify func()) {
+	c.waitReady()
+
+	ctx = log.NewContext(ctx, log.FromContext(ctx).WithValues("controller", "prometheus-crd"))
+	log := log.FromContext(ctx)
+
+	// the feature is notified once the CRDs are first seen, in case they were installed while k8sd started
+	var installed bool
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-c.triggerCh:
+		}
+
+		if isWorker, err := snaputil.IsWorker(c.snap); err != nil {
+			log.Error(err, "Failed to check if running on a worker node")
+			continue
+		} else if isWorker {
+			log.Info("Stopping on worker node")
+			return
+		}
+
+		if ok, err := crdInstalled(ctx); err != nil {
+			log.Error(err, "Failed to check if the Prometheus Operator CRDs are installed")
+		} else if ok != installed {
+			log.Info("Prometheus Operator CRDs changed, reconciling metrics-server", "installed", ok)
+			installed = ok
+			notify()
+		}
+
+		select {
+		case c.reconciledCh <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// ReconciledCh returns the channel where the controller pushes when a reconciliation loop is finished.
+func (c *PrometheusCRDController) ReconciledCh() <-chan struct{} {
+	return c.reconciledCh
+}
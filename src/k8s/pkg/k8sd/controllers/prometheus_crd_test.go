@@ -0,0 +1,72 @@
+package controllers_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/canonical/k8s/pkg/k8sd/controllers"
+	"github.com/canonical/k8s/pkg/snap/mock"
+	. "github.com/onsi/gomega"
+)
+
+func TestPrometheusCRDController(t *testing.T) {
+	g := NewWithT(t)
+	s := &mock.Snap{
+		Mock: mock.Mock{
+			LockFilesDir: filepath.Join(t.TempDir(), "locks"),
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	triggerCh := make(chan time.Time)
+	var installed bool
+	var checkErr error
+	var notified int
+
+	ctrl := controllers.NewPrometheusCRDController(s, func() {}, triggerCh)
+	go ctrl.Run(
+		ctx,
+		func(context.Context) (bool, error) { return installed, checkErr },
+		func() { notified++ },
+	)
+
+	reconcile := func() {
+		select {
+		case triggerCh <- time.Now():
+		case <-time.After(channelSendTimeout):
+			g.Fail("Timed out while attempting to trigger controller reconcile loop")
+		}
+
+		select {
+		case <-ctrl.ReconciledCh():
+		case <-time.After(5 * time.Second):
+			g.Fail("Time out while waiting for the reconcile to complete")
+		}
+	}
+
+	// nothing to do while the CRDs are not installed
+	reconcile()
+	g.Expect(notified).To(Equal(0))
+
+	// the feature is notified once after the CRDs are installed
+	installed = true
+	reconcile()
+	reconcile()
+	g.Expect(notified).To(Equal(1))
+
+	// failed checks are ignored
+	checkErr = errors.New("connection refused")
+	installed = false
+	reconcile()
+	g.Expect(notified).To(Equal(1))
+
+	// the feature is notified after the CRDs are removed
+	checkErr = nil
+	reconcile()
+	g.Expect(notified).To(Equal(2))
+}
@@ -3,15 +3,22 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/canonical/k8s/pkg/client/kubernetes"
 	"github.com/canonical/k8s/pkg/k8sd/types"
 	"github.com/canonical/k8s/pkg/log"
 	"github.com/canonical/k8s/pkg/snap"
 	snaputil "github.com/canonical/k8s/pkg/snap/util"
+	"github.com/canonical/k8s/pkg/utils"
 	pkiutil "github.com/canonical/k8s/pkg/utils/pki"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// kubeProxyRetryInterval is the interval to check again whether the network feature replaced kube-proxy.
+const kubeProxyRetryInterval = 10 * time.Second
+
 // UpdateNodeConfigurationController asynchronously performs updates of the cluster config.
 // A new reconcile loop is triggered by pushing to the triggerCh channel.
 type UpdateNodeConfigurationController struct {
@@ -38,8 +45,9 @@ func NewUpdateNodeConfigurationController(snap snap.Snap, waitReady func(), trig
 // Run starts the controller.
 // Run accepts a context to manage the lifecycle of the controller.
 // Run accepts a function that retrieves the current cluster configuration.
+// Run accepts a function that returns true once the network feature applied the kube-proxy replacement and is ready.
 // Run will loop everytime the TriggerCh is triggered.
-func (c *UpdateNodeConfigurationController) Run(ctx context.Context, getClusterConfig func(context.Context) (types.ClusterConfig, error)) {
+func (c *UpdateNodeConfigurationController) Run(ctx context.Context, getClusterConfig func(context.Context) (types.ClusterConfig, error), kubeProxyReplaced func(context.Context) (bool, error)) {
 	ctx = log.NewContext(ctx, log.FromContext(ctx).WithValues("controller", "update-node-configuration"))
 	log := log.FromContext(ctx)
 
@@ -47,11 +55,13 @@ func (c *UpdateNodeConfigurationController) Run(ctx context.Context, getClusterC
 	c.waitReady()
 	log.V(1).Info("Starting update node configuration controller")
 
+	retryCh := make(chan struct{}, 1)
 	for {
 		select {
 		case <-ctx.Done():
 			return
 		case <-c.triggerCh:
+		case <-retryCh:
 		}
 
 		if isWorker, err := snaputil.IsWorker(c.snap); err != nil {
@@ -73,8 +83,11 @@ func (c *UpdateNodeConfigurationController) Run(ctx context.Context, getClusterC
 			log.Error(err, "Failed to create a Kubernetes client")
 		}
 
-		if err := c.reconcile(ctx, client, config); err != nil {
+		if pending, err := c.reconcile(ctx, client, config, kubeProxyReplaced); err != nil {
 			log.Error(err, "Failed to reconcile cluster configuration")
+		} else if pending {
+			log.V(1).Info("Waiting for the network feature to replace kube-proxy")
+			time.AfterFunc(kubeProxyRetryInterval, func() { utils.MaybeNotify(retryCh) })
 		}
 
 		// notify downstream that the reconciliation loop is done.
@@ -85,25 +98,66 @@ func (c *UpdateNodeConfigurationController) Run(ctx context.Context, getClusterC
 	}
 }
 
-func (c *UpdateNodeConfigurationController) reconcile(ctx context.Context, client *kubernetes.Client, config types.ClusterConfig) error {
+// reconcile publishes the node configuration. reconcile returns true if the kube-proxy state is pending on the network feature.
+func (c *UpdateNodeConfigurationController) reconcile(ctx context.Context, client *kubernetes.Client, config types.ClusterConfig, kubeProxyReplaced func(context.Context) (bool, error)) (bool, error) {
 	log := log.FromContext(ctx)
 	log.V(1).Info("Reconciling node configuration")
 
 	keyPEM := config.Certificates.GetK8sdPrivateKey()
 	key, err := pkiutil.LoadRSAPrivateKey(keyPEM)
 	if err != nil && keyPEM != "" {
-		return fmt.Errorf("failed to load cluster RSA key: %w", err)
+		return false, fmt.Errorf("failed to load cluster RSA key: %w", err)
 	}
 
-	cmData, err := config.Kubelet.ToConfigMap(key)
+	var pending bool
+	kubelet := config.Kubelet
+	if config.Network.GetEnabled() {
+		var enabled bool
+		if enabled, pending, err = kubeProxyEnabled(ctx, client, config, kubeProxyReplaced); err != nil {
+			return false, fmt.Errorf("failed to check kube-proxy state: %w", err)
+		}
+		kubelet.KubeProxyEnabled = utils.Pointer(enabled)
+	}
+	cmData, err := kubelet.ToConfigMap(key)
 	if err != nil {
-		return fmt.Errorf("failed to format kubelet configmap data: %w", err)
+		return false, fmt.Errorf("failed to format kubelet configmap data: %w", err)
 	}
 	if _, err := client.UpdateConfigMap(ctx, "kube-system", "k8sd-config", cmData); err != nil {
-		return fmt.Errorf("failed to update node config: %w", err)
+		return false, fmt.Errorf("failed to update node config: %w", err)
+	}
+
+	return pending, nil
+}
+
+// kubeProxyEnabled returns whether kube-proxy should run on the nodes.
+// Nodes stop kube-proxy only once the network feature applied the kube-proxy replacement and is ready, so that
+// services are never left without routing. Until then, the published kube-proxy state is kept and kubeProxyEnabled
+// returns pending. Starting kube-proxy is always safe, so it is published immediately.
+func kubeProxyEnabled(ctx context.Context, client *kubernetes.Client, config types.ClusterConfig, kubeProxyReplaced func(context.Context) (bool, error)) (enabled bool, pending bool, err error) {
+	if !config.Network.Cilium.GetKubeProxyReplacement() {
+		return true, false, nil
+	}
+
+	replaced, err := kubeProxyReplaced(ctx)
+	if err != nil {
+		return false, false, fmt.Errorf("failed to check kube-proxy replacement: %w", err)
+	}
+	if replaced {
+		return false, false, nil
 	}
 
-	return nil
+	cm, err := client.CoreV1().ConfigMaps("kube-system").Get(ctx, "k8sd-config", metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return true, true, nil
+		}
+		return false, false, fmt.Errorf("failed to get node config: %w", err)
+	}
+	published, err := types.KubeletFromConfigMap(cm.Data, nil)
+	if err != nil {
+		return false, false, fmt.Errorf("failed to parse node config: %w", err)
+	}
+	return published.KubeProxyEnabled == nil || *published.KubeProxyEnabled, true, nil
 }
 
 // ReconciledCh returns the channel where the controller pushes when a reconciliation loop is finished.
@@ -98,7 +98,7 @@ func TestUpdateNodeConfigurationController(t *testing.T) {
 			defer close(triggerCh)
 
 			ctrl := controllers.NewUpdateNodeConfigurationController(s, func() {}, triggerCh)
-			go ctrl.Run(ctx, configProvider.getConfig)
+			go ctrl.Run(ctx, configProvider.getConfig, func(context.Context) (bool, error) { return true, nil })
 
 			select {
 			case triggerCh <- struct{}{}:
@@ -133,3 +133,87 @@ func TestUpdateNodeConfigurationController(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateNodeConfigurationControllerKubeProxy(t *testing.T) {
+	replacement := types.Network{
+		Enabled: utils.Pointer(true),
+		Cilium:  types.Cilium{KubeProxyReplacement: utils.Pointer(true)},
+	}
+	for _, tc := range []struct {
+		name           string
+		network        types.Network
+		replaced       bool
+		published      map[string]string
+		expectedConfig map[string]string
+	}{
+		{
+			name:    "NetworkDisabled",
+			network: types.Network{Enabled: utils.Pointer(false)},
+		},
+		{
+			name:           "KubeProxy",
+			network:        types.Network{Enabled: utils.Pointer(true)},
+			published:      map[string]string{"kube-proxy-enabled": "false"},
+			expectedConfig: map[string]string{"kube-proxy-enabled": "true"},
+		},
+		{
+			name:           "KubeProxyReplacement",
+			network:        replacement,
+			replaced:       true,
+			expectedConfig: map[string]string{"kube-proxy-enabled": "false"},
+		},
+		{
+			name:           "KubeProxyReplacementPending",
+			network:        replacement,
+			expectedConfig: map[string]string{"kube-proxy-enabled": "true"},
+		},
+		{
+			name:           "KubeProxyReplacementNotReady",
+			network:        replacement,
+			published:      map[string]string{"kube-proxy-enabled": "false"},
+			expectedConfig: map[string]string{"kube-proxy-enabled": "false"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			clientset := fake.NewSimpleClientset(&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "k8sd-config", Namespace: "kube-system"},
+				Data:       tc.published,
+			})
+			s := &mock.Snap{
+				Mock: mock.Mock{
+					KubernetesClient: &kubernetes.Client{Interface: clientset},
+				},
+			}
+			triggerCh := make(chan struct{})
+			defer close(triggerCh)
+
+			configProvider := &configProvider{config: types.ClusterConfig{Network: tc.network}}
+			ctrl := controllers.NewUpdateNodeConfigurationController(s, func() {}, triggerCh)
+			go ctrl.Run(ctx, configProvider.getConfig, func(context.Context) (bool, error) { return tc.replaced, nil })
+
+			select {
+			case triggerCh <- struct{}{}:
+			case <-time.After(channelSendTimeout):
+				g.Fail("Timed out while attempting to trigger controller reconcile loop")
+			}
+
+			select {
+			case <-ctrl.ReconciledCh():
+			case <-time.After(channelSendTimeout):
+				g.Fail("Time out while waiting for the reconcile to complete")
+			}
+
+			result, err := clientset.CoreV1().ConfigMaps("kube-system").Get(ctx, "k8sd-config", metav1.GetOptions{})
+			g.Expect(err).ToNot(HaveOccurred())
+			if len(tc.expectedConfig) == 0 {
+				g.Expect(result.Data).To(BeEmpty())
+			} else {
+				g.Expect(result.Data).To(Equal(tc.expectedConfig))
+			}
+		})
+	}
+}
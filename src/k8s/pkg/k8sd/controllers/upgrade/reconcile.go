@@ -173,24 +173,9 @@ func (c *Controller) transitionTo(ctx context.Context, upgrade *upgradesv1alpha.
 }
 
 func (c *Controller) triggerFeature(name types.FeatureName) error {
-	switch name {
-	case features.Network:
-		c.notifyNetworkFeature()
-	case features.Gateway:
-		c.notifyGatewayFeature()
-	case features.Ingress:
-		c.notifyIngressFeature()
-	case features.LoadBalancer:
-		c.notifyLoadBalancerFeature()
-	case features.LocalStorage:
-		c.notifyLocalStorageFeature()
-	case features.MetricsServer:
-		c.notifyMetricsServerFeature()
-	case features.DNS:
-		c.notifyDNSFeature()
-	default:
+	if _, ok := features.Get(name); !ok {
 		return fmt.Errorf("unknown feature %q", name)
 	}
-
+	c.notifyFeature(name)
 	return nil
 }
@@ -0,0 +1,203 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/k8s/pkg/log"
+	"github.com/canonical/microcluster/v2/cluster"
+)
+
+var caRotationsStmts = map[string]int{
+	"insert":        MustPrepareStatement("ca-rotations", "insert.sql"),
+	"select-latest": MustPrepareStatement("ca-rotations", "select-latest.sql"),
+	"update-phase":  MustPrepareStatement("ca-rotations", "update-phase.sql"),
+
+	"upsert-node": MustPrepareStatement("ca-rotation-nodes", "upsert.sql"),
+	"select-node": MustPrepareStatement("ca-rotation-nodes", "select.sql"),
+	"delete-node": MustPrepareStatement("ca-rotation-nodes", "delete.sql"),
+}
+
+// StartCARotation records a new CA rotation in the trust phase and updates the certificates of the cluster
+// configuration accordingly on behalf of identity.
+// StartCARotation will return the recorded rotation on success.
+func StartCARotation(ctx context.Context, tx *sql.Tx, rotation types.CARotation, identity string) (types.CARotation, error) {
+	oldCertificates, err := json.Marshal(rotation.Old)
+	if err != nil {
+		return types.CARotation{}, fmt.Errorf("failed to encode old certificates: %w", err)
+	}
+	newCertificates, err := json.Marshal(rotation.New)
+	if err != nil {
+		return types.CARotation{}, fmt.Errorf("failed to encode new certificates: %w", err)
+	}
+
+	rotation.Phase = types.CARotationTrust
+	insertTxStmt, err := cluster.Stmt(tx, caRotationsStmts["insert"])
+	if err != nil {
+		return types.CARotation{}, fmt.Errorf("failed to prepare insert statement: %w", err)
+	}
+	result, err := insertTxStmt.ExecContext(ctx,
+		rotation.Phase,
+		string(oldCertificates),
+		string(newCertificates),
+		rotation.StartedAt.UTC().Format(time.RFC3339Nano),
+		rotation.UpdatedAt.UTC().Format(time.RFC3339Nano),
+	)
+	if err != nil {
+		return types.CARotation{}, fmt.Errorf("failed to execute insert statement: %w", err)
+	}
+	if rotation.ID, err = result.LastInsertId(); err != nil {
+		return types.CARotation{}, fmt.Errorf("failed to get rotation ID: %w", err)
+	}
+
+	if _, err := SetClusterCertificates(ctx, tx, rotation.ClusterCertificates(rotation.Phase), identity); err != nil {
+		return types.CARotation{}, fmt.Errorf("failed to update cluster certificates: %w", err)
+	}
+	return rotation, nil
+}
+
+// GetCARotation returns the latest CA rotation, or nil if the CAs were never rotated.
+func GetCARotation(ctx context.Context, tx *sql.Tx) (*types.CARotation, error) {
+	selectTxStmt, err := cluster.Stmt(tx, caRotationsStmts["select-latest"])
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare select statement: %w", err)
+	}
+
+	var (
+		rotation                         types.CARotation
+		oldCertificates, newCertificates string
+		startedAt, updatedAt             string
+	)
+	if err := selectTxStmt.QueryRowContext(ctx).Scan(&rotation.ID, &rotation.Phase, &oldCertificates, &newCertificates, &startedAt, &updatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to execute select statement: %w", err)
+	}
+	if err := json.Unmarshal([]byte(oldCertificates), &rotation.Old); err != nil {
+		return nil, fmt.Errorf("failed to parse old certificates: %w", err)
+	}
+	if err := json.Unmarshal([]byte(newCertificates), &rotation.New); err != nil {
+		return nil, fmt.Errorf("failed to parse new certificates: %w", err)
+	}
+	if rotation.StartedAt, err = time.Parse(time.RFC3339Nano, startedAt); err != nil {
+		log.FromContext(ctx).Error(err, "failed to parse time", "original", startedAt)
+	}
+	if rotation.UpdatedAt, err = time.Parse(time.RFC3339Nano, updatedAt); err != nil {
+		log.FromContext(ctx).Error(err, "failed to parse time", "original", updatedAt)
+	}
+	return &rotation, nil
+}
+
+// AdvanceCARotation moves the latest CA rotation to the next phase once the current phase was applied on all
+// cluster members, and updates the certificates of the cluster configuration accordingly on behalf of identity.
+// AdvanceCARotation returns the latest rotation, or nil if the CAs were never rotated.
+func AdvanceCARotation(ctx context.Context, tx *sql.Tx, identity string) (*types.CARotation, error) {
+	rotation, err := GetCARotation(ctx, tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get CA rotation: %w", err)
+	} else if rotation == nil || rotation.Phase == types.CARotationCompleted {
+		return rotation, nil
+	}
+
+	members, err := cluster.GetCoreClusterMembers(ctx, tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cluster members: %w", err)
+	}
+	nodes, err := ListCARotationNodes(ctx, tx, rotation.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list CA rotation nodes: %w", err)
+	}
+	phases := make(map[string]types.CARotationPhase, len(nodes))
+	for _, node := range nodes {
+		phases[node.Node] = node.Phase
+	}
+	for _, member := range members {
+		if member.Role != cluster.Pending && phases[member.Name] != rotation.Phase {
+			return rotation, nil
+		}
+	}
+
+	rotation.Phase = rotation.Phase.Next()
+	rotation.UpdatedAt = time.Now()
+	updateTxStmt, err := cluster.Stmt(tx, caRotationsStmts["update-phase"])
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare update statement: %w", err)
+	}
+	if _, err := updateTxStmt.ExecContext(ctx, rotation.Phase, rotation.UpdatedAt.UTC().Format(time.RFC3339Nano), rotation.ID); err != nil {
+		return nil, fmt.Errorf("failed to execute update statement: %w", err)
+	}
+	if _, err := SetClusterCertificates(ctx, tx, rotation.ClusterCertificates(rotation.Phase), identity); err != nil {
+		return nil, fmt.Errorf("failed to update cluster certificates: %w", err)
+	}
+	return rotation, nil
+}
+
+// SetCARotationNode records the progress of a node in the given CA rotation.
+func SetCARotationNode(ctx context.Context, tx *sql.Tx, rotationID int64, node types.CARotationNode) error {
+	upsertTxStmt, err := cluster.Stmt(tx, caRotationsStmts["upsert-node"])
+	if err != nil {
+		return fmt.Errorf("failed to prepare upsert statement: %w", err)
+	}
+	if _, err := upsertTxStmt.ExecContext(ctx,
+		rotationID,
+		node.Node,
+		node.Phase,
+		node.Message,
+		node.UpdatedAt.UTC().Format(time.RFC3339Nano),
+	); err != nil {
+		return fmt.Errorf("failed to execute upsert statement: %w", err)
+	}
+	return nil
+}
+
+// ListCARotationNodes returns the progress of the nodes in the given CA rotation, ordered by node name.
+func ListCARotationNodes(ctx context.Context, tx *sql.Tx, rotationID int64) ([]types.CARotationNode, error) {
+	selectTxStmt, err := cluster.Stmt(tx, caRotationsStmts["select-node"])
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare select statement: %w", err)
+	}
+
+	rows, err := selectTxStmt.QueryContext(ctx, rotationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute select statement: %w", err)
+	}
+	defer rows.Close()
+
+	var result []types.CARotationNode
+	for rows.Next() {
+		var (
+			node      types.CARotationNode
+			updatedAt string
+		)
+		if err := rows.Scan(&node.Node, &node.Phase, &node.Message, &updatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		if node.UpdatedAt, err = time.Parse(time.RFC3339Nano, updatedAt); err != nil {
+			log.FromContext(ctx).Error(err, "failed to parse time", "original", updatedAt)
+		}
+		result = append(result, node)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read rows: %w", err)
+	}
+
+	return result, nil
+}
+
+// DeleteCARotationNode deletes the progress of a node in all CA rotations.
+func DeleteCARotationNode(ctx context.Context, tx *sql.Tx, node string) error {
+	deleteTxStmt, err := cluster.Stmt(tx, caRotationsStmts["delete-node"])
+	if err != nil {
+		return fmt.Errorf("failed to prepare delete statement: %w", err)
+	}
+	if _, err := deleteTxStmt.ExecContext(ctx, node); err != nil {
+		return fmt.Errorf("failed to execute delete statement: %w", err)
+	}
+	return nil
+}
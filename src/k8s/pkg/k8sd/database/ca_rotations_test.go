@@ -0,0 +1,119 @@
+package database_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/canonical/k8s/pkg/k8sd/database"
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/k8s/pkg/utils"
+	testenv "github.com/canonical/k8s/pkg/utils/microcluster"
+	"github.com/canonical/microcluster/v2/state"
+	. "github.com/onsi/gomega"
+)
+
+func TestCARotations(t *testing.T) {
+	testenv.WithState(t, func(ctx context.Context, s state.State) {
+		_ = s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+			t0 := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+			config := types.ClusterConfig{
+				Certificates: types.Certificates{
+					CACert: utils.Pointer("OLD CA CERT"),
+					CAKey:  utils.Pointer("OLD CA KEY"),
+				},
+			}
+			config.SetDefaults()
+			_, err := database.SetClusterConfig(ctx, tx, config, "test")
+			NewWithT(t).Expect(err).To(Not(HaveOccurred()))
+
+			t.Run("Empty", func(t *testing.T) {
+				g := NewWithT(t)
+				rotation, err := database.GetCARotation(ctx, tx)
+				g.Expect(err).To(Not(HaveOccurred()))
+				g.Expect(rotation).To(BeNil())
+			})
+
+			var rotation types.CARotation
+			t.Run("Start", func(t *testing.T) {
+				g := NewWithT(t)
+
+				rotation, err = database.StartCARotation(ctx, tx, types.CARotation{
+					Old: types.Certificates{CACert: utils.Pointer("OLD CA CERT"), CAKey: utils.Pointer("OLD CA KEY")},
+					New: types.Certificates{
+						CACert:          utils.Pointer("NEW CA CERT"),
+						CAKey:           utils.Pointer("NEW CA KEY"),
+						ClientCACert:    utils.Pointer("NEW CLIENT CA CERT"),
+						ClientCAKey:     utils.Pointer("NEW CLIENT CA KEY"),
+						AdminClientCert: utils.Pointer("NEW ADMIN CERT"),
+						AdminClientKey:  utils.Pointer("NEW ADMIN KEY"),
+					},
+					StartedAt: t0,
+					UpdatedAt: t0,
+				}, "test")
+				g.Expect(err).To(Not(HaveOccurred()))
+				g.Expect(rotation.Phase).To(Equal(types.CARotationTrust))
+
+				latest, err := database.GetCARotation(ctx, tx)
+				g.Expect(err).To(Not(HaveOccurred()))
+				g.Expect(latest).ToNot(BeNil())
+				g.Expect(*latest).To(Equal(rotation))
+
+				config, err := database.GetClusterConfig(ctx, tx)
+				g.Expect(err).To(Not(HaveOccurred()))
+				g.Expect(config.Certificates.GetCACert()).To(Equal("OLD CA CERT\nNEW CA CERT\n"))
+				g.Expect(config.Certificates.GetCAKey()).To(Equal("OLD CA KEY"))
+				g.Expect(config.Certificates.GetClientCACert()).To(Equal("OLD CA CERT\nNEW CLIENT CA CERT\n"))
+				g.Expect(config.Certificates.GetClientCAKey()).To(Equal("OLD CA KEY"))
+			})
+
+			t.Run("Advance", func(t *testing.T) {
+				g := NewWithT(t)
+
+				// the phase was not applied on the node yet
+				latest, err := database.AdvanceCARotation(ctx, tx, "test")
+				g.Expect(err).To(Not(HaveOccurred()))
+				g.Expect(latest.Phase).To(Equal(types.CARotationTrust))
+
+				g.Expect(database.SetCARotationNode(ctx, tx, rotation.ID, types.CARotationNode{Node: s.Name(), Phase: types.CARotationTrust, UpdatedAt: t0})).To(Succeed())
+				latest, err = database.AdvanceCARotation(ctx, tx, "test")
+				g.Expect(err).To(Not(HaveOccurred()))
+				g.Expect(latest.Phase).To(Equal(types.CARotationReissue))
+
+				config, err := database.GetClusterConfig(ctx, tx)
+				g.Expect(err).To(Not(HaveOccurred()))
+				g.Expect(config.Certificates.GetCACert()).To(Equal("NEW CA CERT\nOLD CA CERT\n"))
+				g.Expect(config.Certificates.GetCAKey()).To(Equal("NEW CA KEY"))
+				g.Expect(config.Certificates.GetAdminClientCert()).To(Equal("NEW ADMIN CERT"))
+
+				for _, phase := range []types.CARotationPhase{types.CARotationReissue, types.CARotationFinalize} {
+					g.Expect(database.SetCARotationNode(ctx, tx, rotation.ID, types.CARotationNode{Node: s.Name(), Phase: phase, UpdatedAt: t0})).To(Succeed())
+					_, err = database.AdvanceCARotation(ctx, tx, "test")
+					g.Expect(err).To(Not(HaveOccurred()))
+				}
+
+				latest, err = database.GetCARotation(ctx, tx)
+				g.Expect(err).To(Not(HaveOccurred()))
+				g.Expect(latest.Phase).To(Equal(types.CARotationCompleted))
+
+				config, err = database.GetClusterConfig(ctx, tx)
+				g.Expect(err).To(Not(HaveOccurred()))
+				g.Expect(config.Certificates.GetCACert()).To(Equal("NEW CA CERT\n"))
+				g.Expect(config.Certificates.GetClientCACert()).To(Equal("NEW CLIENT CA CERT\n"))
+			})
+
+			t.Run("DeleteNode", func(t *testing.T) {
+				g := NewWithT(t)
+
+				g.Expect(database.DeleteCARotationNode(ctx, tx, s.Name())).To(Succeed())
+				nodes, err := database.ListCARotationNodes(ctx, tx, rotation.ID)
+				g.Expect(err).To(Not(HaveOccurred()))
+				g.Expect(nodes).To(BeEmpty())
+			})
+
+			return nil
+		})
+	})
+}
@@ -0,0 +1,107 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/k8s/pkg/log"
+	"github.com/canonical/microcluster/v2/cluster"
+)
+
+var certificateRotationsStmts = map[string]int{
+	"upsert": MustPrepareStatement("certificate-rotations", "upsert.sql"),
+	"select": MustPrepareStatement("certificate-rotations", "select.sql"),
+	"delete": MustPrepareStatement("certificate-rotations", "delete.sql"),
+}
+
+// SetCertificateRotation records the certificate rotation progress of a node.
+func SetCertificateRotation(ctx context.Context, tx *sql.Tx, rotation types.CertificateRotation) error {
+	upsertTxStmt, err := cluster.Stmt(tx, certificateRotationsStmts["upsert"])
+	if err != nil {
+		return fmt.Errorf("failed to prepare upsert statement: %w", err)
+	}
+	if _, err := upsertTxStmt.ExecContext(ctx,
+		rotation.Node,
+		rotation.State,
+		strings.Join(rotation.Certificates, ","),
+		rotation.Message,
+		rotation.UpdatedAt.UTC().Format(time.RFC3339Nano),
+	); err != nil {
+		return fmt.Errorf("failed to execute upsert statement: %w", err)
+	}
+	return nil
+}
+
+// ListCertificateRotations returns the certificate rotation progress of all nodes, ordered by node name.
+func ListCertificateRotations(ctx context.Context, tx *sql.Tx) ([]types.CertificateRotation, error) {
+	selectTxStmt, err := cluster.Stmt(tx, certificateRotationsStmts["select"])
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare select statement: %w", err)
+	}
+
+	rows, err := selectTxStmt.QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute select statement: %w", err)
+	}
+	defer rows.Close()
+
+	var result []types.CertificateRotation
+	for rows.Next() {
+		var (
+			rotation                types.CertificateRotation
+			certificates, updatedAt string
+		)
+		if err := rows.Scan(&rotation.Node, &rotation.State, &certificates, &rotation.Message, &updatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		if certificates != "" {
+			rotation.Certificates = strings.Split(certificates, ",")
+		}
+		if rotation.UpdatedAt, err = time.Parse(time.RFC3339Nano, updatedAt); err != nil {
+			log.FromContext(ctx).Error(err, "failed to parse time", "original", updatedAt)
+		}
+		result = append(result, rotation)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read rows: %w", err)
+	}
+
+	return result, nil
+}
+
+// AcquireCertificateRotation records the certificate rotation progress of a node, unless another node has an active
+// certificate rotation. Rotations that were not updated within the lease duration are not considered active.
+// AcquireCertificateRotation returns the name of the node with the active rotation, or an empty string if the
+// rotation progress was recorded.
+func AcquireCertificateRotation(ctx context.Context, tx *sql.Tx, rotation types.CertificateRotation, lease time.Duration) (string, error) {
+	rotations, err := ListCertificateRotations(ctx, tx)
+	if err != nil {
+		return "", fmt.Errorf("failed to list certificate rotations: %w", err)
+	}
+	for _, other := range rotations {
+		if other.Node != rotation.Node && other.Active(rotation.UpdatedAt, lease) {
+			return other.Node, nil
+		}
+	}
+
+	if err := SetCertificateRotation(ctx, tx, rotation); err != nil {
+		return "", fmt.Errorf("failed to set certificate rotation: %w", err)
+	}
+	return "", nil
+}
+
+// DeleteCertificateRotation deletes the certificate rotation progress of a node.
+func DeleteCertificateRotation(ctx context.Context, tx *sql.Tx, node string) error {
+	deleteTxStmt, err := cluster.Stmt(tx, certificateRotationsStmts["delete"])
+	if err != nil {
+		return fmt.Errorf("failed to prepare delete statement: %w", err)
+	}
+	if _, err := deleteTxStmt.ExecContext(ctx, node); err != nil {
+		return fmt.Errorf("failed to execute delete statement: %w", err)
+	}
+	return nil
+}
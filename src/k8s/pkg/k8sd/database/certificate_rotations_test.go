@@ -0,0 +1,66 @@
+package database_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/canonical/k8s/pkg/k8sd/database"
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	testenv "github.com/canonical/k8s/pkg/utils/microcluster"
+	"github.com/canonical/microcluster/v2/state"
+	. "github.com/onsi/gomega"
+)
+
+func TestCertificateRotations(t *testing.T) {
+	testenv.WithState(t, func(ctx context.Context, s state.State) {
+		_ = s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+			t0 := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+			t.Run("Empty", func(t *testing.T) {
+				g := NewWithT(t)
+				rotations, err := database.ListCertificateRotations(ctx, tx)
+				g.Expect(err).To(Not(HaveOccurred()))
+				g.Expect(rotations).To(BeEmpty())
+			})
+
+			t.Run("Acquire", func(t *testing.T) {
+				g := NewWithT(t)
+
+				node1 := types.CertificateRotation{Node: "node1", State: types.CertificateRotationRenewing, Certificates: []string{"apiserver", "kubelet"}, UpdatedAt: t0}
+				holder, err := database.AcquireCertificateRotation(ctx, tx, node1, 15*time.Minute)
+				g.Expect(err).To(Not(HaveOccurred()))
+				g.Expect(holder).To(BeEmpty())
+
+				node2 := types.CertificateRotation{Node: "node2", State: types.CertificateRotationRenewing, Certificates: []string{"apiserver"}, UpdatedAt: t0.Add(time.Minute)}
+				holder, err = database.AcquireCertificateRotation(ctx, tx, node2, 15*time.Minute)
+				g.Expect(err).To(Not(HaveOccurred()))
+				g.Expect(holder).To(Equal("node1"))
+
+				// the rotation of node1 expired
+				node2.UpdatedAt = t0.Add(time.Hour)
+				holder, err = database.AcquireCertificateRotation(ctx, tx, node2, 15*time.Minute)
+				g.Expect(err).To(Not(HaveOccurred()))
+				g.Expect(holder).To(BeEmpty())
+
+				rotations, err := database.ListCertificateRotations(ctx, tx)
+				g.Expect(err).To(Not(HaveOccurred()))
+				g.Expect(rotations).To(Equal([]types.CertificateRotation{node1, node2}))
+			})
+
+			t.Run("Delete", func(t *testing.T) {
+				g := NewWithT(t)
+
+				g.Expect(database.SetCertificateRotation(ctx, tx, types.CertificateRotation{Node: "node2", State: types.CertificateRotationCompleted, UpdatedAt: t0})).To(Succeed())
+				g.Expect(database.DeleteCertificateRotation(ctx, tx, "node1")).To(Succeed())
+
+				rotations, err := database.ListCertificateRotations(ctx, tx)
+				g.Expect(err).To(Not(HaveOccurred()))
+				g.Expect(rotations).To(Equal([]types.CertificateRotation{{Node: "node2", State: types.CertificateRotationCompleted, UpdatedAt: t0}}))
+			})
+
+			return nil
+		})
+	})
+}
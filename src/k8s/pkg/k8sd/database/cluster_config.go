@@ -7,6 +7,7 @@ import (
 	"fmt"
 
 	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/k8s/pkg/utils"
 	"github.com/canonical/microcluster/v2/cluster"
 )
 
@@ -17,8 +18,9 @@ var clusterConfigsStmts = map[string]int{
 
 // SetClusterConfig updates the cluster configuration with any non-empty values that are set.
 // SetClusterConfig will attempt to merge the existing and new configs, and return an error if any protected fields have changed.
+// SetClusterConfig will record the merged cluster configuration as a new revision on behalf of identity.
 // SetClusterConfig will return the merged cluster configuration on success.
-func SetClusterConfig(ctx context.Context, tx *sql.Tx, new types.ClusterConfig) (types.ClusterConfig, error) {
+func SetClusterConfig(ctx context.Context, tx *sql.Tx, new types.ClusterConfig, identity string) (types.ClusterConfig, error) {
 	old, err := GetClusterConfig(ctx, tx)
 	if err != nil {
 		return types.ClusterConfig{}, fmt.Errorf("failed to fetch existing cluster config: %w", err)
@@ -28,18 +30,76 @@ func SetClusterConfig(ctx context.Context, tx *sql.Tx, new types.ClusterConfig)
 		return types.ClusterConfig{}, fmt.Errorf("failed to merge new cluster configuration options: %w", err)
 	}
 
+	if err := insertClusterConfig(ctx, tx, config, identity); err != nil {
+		return types.ClusterConfig{}, err
+	}
+	return config, nil
+}
+
+// SetDatastoreConfig switches the cluster from k8s-dqlite to the external datastore described by servers and the
+// (optional) PEM encoded certificates. SetDatastoreConfig must only be used after the datastore contents have been migrated.
+// SetDatastoreConfig will return the updated cluster configuration on success.
+func SetDatastoreConfig(ctx context.Context, tx *sql.Tx, servers []string, caCert string, clientCert string, clientKey string, identity string) (types.ClusterConfig, error) {
+	return SetClusterConfig(ctx, tx, types.ClusterConfig{
+		Datastore: types.Datastore{
+			Type:               utils.Pointer("external"),
+			ExternalServers:    utils.Pointer(servers),
+			ExternalCACert:     utils.Pointer(caCert),
+			ExternalClientCert: utils.Pointer(clientCert),
+			ExternalClientKey:  utils.Pointer(clientKey),
+		},
+	}, identity)
+}
+
+// SetClusterCertificates replaces the certificates of the cluster configuration with any non-nil values that are set.
+// Unlike SetClusterConfig, SetClusterCertificates changes the cluster CAs, so it must only be used to rotate them.
+// SetClusterCertificates will return the updated cluster configuration on success.
+func SetClusterCertificates(ctx context.Context, tx *sql.Tx, certificates types.Certificates, identity string) (types.ClusterConfig, error) {
+	config, err := GetClusterConfig(ctx, tx)
+	if err != nil {
+		return types.ClusterConfig{}, fmt.Errorf("failed to fetch existing cluster config: %w", err)
+	}
+
+	for _, field := range []struct {
+		val *(*string)
+		new *string
+	}{
+		{val: &config.Certificates.CACert, new: certificates.CACert},
+		{val: &config.Certificates.CAKey, new: certificates.CAKey},
+		{val: &config.Certificates.ClientCACert, new: certificates.ClientCACert},
+		{val: &config.Certificates.ClientCAKey, new: certificates.ClientCAKey},
+		{val: &config.Certificates.APIServerKubeletClientCert, new: certificates.APIServerKubeletClientCert},
+		{val: &config.Certificates.APIServerKubeletClientKey, new: certificates.APIServerKubeletClientKey},
+		{val: &config.Certificates.AdminClientCert, new: certificates.AdminClientCert},
+		{val: &config.Certificates.AdminClientKey, new: certificates.AdminClientKey},
+	} {
+		if field.new != nil {
+			*field.val = field.new
+		}
+	}
+
+	if err := insertClusterConfig(ctx, tx, config, identity); err != nil {
+		return types.ClusterConfig{}, err
+	}
+	return config, nil
+}
+
+func insertClusterConfig(ctx context.Context, tx *sql.Tx, config types.ClusterConfig, identity string) error {
 	b, err := json.Marshal(config)
 	if err != nil {
-		return types.ClusterConfig{}, fmt.Errorf("failed to encode cluster config: %w", err)
+		return fmt.Errorf("failed to encode cluster config: %w", err)
 	}
 	insertTxStmt, err := cluster.Stmt(tx, clusterConfigsStmts["insert-v1alpha2"])
 	if err != nil {
-		return types.ClusterConfig{}, fmt.Errorf("failed to prepare insert statement: %w", err)
+		return fmt.Errorf("failed to prepare insert statement: %w", err)
 	}
 	if _, err := insertTxStmt.ExecContext(ctx, string(b)); err != nil {
-		return types.ClusterConfig{}, fmt.Errorf("failed to insert v1alpha2 config: %w", err)
+		return fmt.Errorf("failed to insert v1alpha2 config: %w", err)
 	}
-	return config, nil
+	if err := insertClusterConfigRevision(ctx, tx, config, identity); err != nil {
+		return fmt.Errorf("failed to record cluster config revision: %w", err)
+	}
+	return nil
 }
 
 // GetClusterConfig retrieves the cluster configuration from the database.
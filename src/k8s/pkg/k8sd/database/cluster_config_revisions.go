@@ -0,0 +1,184 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/k8s/pkg/log"
+	"github.com/canonical/microcluster/v2/cluster"
+)
+
+// MaxClusterConfigRevisions is the number of cluster config revisions that are kept.
+// Older revisions are removed when a new revision is recorded.
+const MaxClusterConfigRevisions = 100
+
+var clusterConfigRevisionsStmts = map[string]int{
+	"insert":       MustPrepareStatement("cluster-config-revisions", "insert.sql"),
+	"delete-old":   MustPrepareStatement("cluster-config-revisions", "delete-old.sql"),
+	"select":       MustPrepareStatement("cluster-config-revisions", "select.sql"),
+	"select-by-id": MustPrepareStatement("cluster-config-revisions", "select-by-id.sql"),
+}
+
+// ErrClusterConfigRevisionNotFound is returned when a cluster config revision does not exist.
+var ErrClusterConfigRevisionNotFound = errors.New("cluster config revision not found")
+
+// ClusterConfigRevision is a recorded revision of the cluster configuration.
+type ClusterConfigRevision struct {
+	// Revision is the sequence number of the revision.
+	Revision int64
+	// Timestamp is the time the revision was recorded.
+	Timestamp time.Time
+	// Identity is the identity that requested the change.
+	Identity string
+	// Config holds the options of the merged cluster configuration that are part of the revision history,
+	// see types.ClusterConfig.RevisionOptions. Config is only set by GetClusterConfigRevision.
+	Config types.ClusterConfig
+}
+
+// insertClusterConfigRevision records the options of config that are part of the revision history, and removes
+// the oldest revisions beyond MaxClusterConfigRevisions.
+func insertClusterConfigRevision(ctx context.Context, tx *sql.Tx, config types.ClusterConfig, identity string) error {
+	value, err := encodeClusterConfigRevision(config)
+	if err != nil {
+		return err
+	}
+
+	insertTxStmt, err := cluster.Stmt(tx, clusterConfigRevisionsStmts["insert"])
+	if err != nil {
+		return fmt.Errorf("failed to prepare insert statement: %w", err)
+	}
+	if _, err := insertTxStmt.ExecContext(ctx, time.Now().UTC().Format(time.RFC3339), identity, value); err != nil {
+		return fmt.Errorf("failed to execute insert statement: %w", err)
+	}
+
+	deleteTxStmt, err := cluster.Stmt(tx, clusterConfigRevisionsStmts["delete-old"])
+	if err != nil {
+		return fmt.Errorf("failed to prepare delete statement: %w", err)
+	}
+	if _, err := deleteTxStmt.ExecContext(ctx, MaxClusterConfigRevisions); err != nil {
+		return fmt.Errorf("failed to execute delete statement: %w", err)
+	}
+	return nil
+}
+
+func encodeClusterConfigRevision(config types.ClusterConfig) (string, error) {
+	options, err := config.RevisionOptions()
+	if err != nil {
+		return "", fmt.Errorf("failed to get revision options: %w", err)
+	}
+	b, err := json.Marshal(options)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cluster config revision: %w", err)
+	}
+	return string(b), nil
+}
+
+// stripClusterConfigRevisions removes the certificates, keys and other internal options from the revisions
+// that were recorded with the full cluster configuration.
+// stripClusterConfigRevisions is a schema update, as the revision options cannot be computed in SQL.
+func stripClusterConfigRevisions(ctx context.Context, tx *sql.Tx) error {
+	rows, err := tx.QueryContext(ctx, "SELECT id, value FROM cluster_config_revisions")
+	if err != nil {
+		return fmt.Errorf("failed to select revisions: %w", err)
+	}
+	values := make(map[int64]string)
+	for rows.Next() {
+		var (
+			id    int64
+			value string
+		)
+		if err := rows.Scan(&id, &value); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan row: %w", err)
+		}
+		values[id] = value
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read rows: %w", err)
+	}
+
+	for id, value := range values {
+		var config types.ClusterConfig
+		if err := json.Unmarshal([]byte(value), &config); err != nil {
+			return fmt.Errorf("failed to parse revision %d: %w", id, err)
+		}
+		stripped, err := encodeClusterConfigRevision(config)
+		if err != nil {
+			return fmt.Errorf("failed to strip revision %d: %w", id, err)
+		}
+		if _, err := tx.ExecContext(ctx, "UPDATE cluster_config_revisions SET value = ? WHERE id = ?", stripped, id); err != nil {
+			return fmt.Errorf("failed to update revision %d: %w", id, err)
+		}
+	}
+	return nil
+}
+
+// ListClusterConfigRevisions returns the recorded revisions of the cluster configuration, oldest first.
+// The Config field of the returned revisions is not populated.
+func ListClusterConfigRevisions(ctx context.Context, tx *sql.Tx) ([]ClusterConfigRevision, error) {
+	selectTxStmt, err := cluster.Stmt(tx, clusterConfigRevisionsStmts["select"])
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare select statement: %w", err)
+	}
+
+	rows, err := selectTxStmt.QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute select statement: %w", err)
+	}
+	defer rows.Close()
+
+	var result []ClusterConfigRevision
+	for rows.Next() {
+		var (
+			revision ClusterConfigRevision
+			ts       string
+		)
+		if err := rows.Scan(&revision.Revision, &ts, &revision.Identity); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		if revision.Timestamp, err = time.Parse(time.RFC3339, ts); err != nil {
+			log.FromContext(ctx).Error(err, "failed to parse time", "original", ts)
+		}
+		result = append(result, revision)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read rows: %w", err)
+	}
+
+	return result, nil
+}
+
+// GetClusterConfigRevision returns the given revision of the cluster configuration.
+// GetClusterConfigRevision returns ErrClusterConfigRevisionNotFound if the revision does not exist.
+func GetClusterConfigRevision(ctx context.Context, tx *sql.Tx, revision int64) (ClusterConfigRevision, error) {
+	selectTxStmt, err := cluster.Stmt(tx, clusterConfigRevisionsStmts["select-by-id"])
+	if err != nil {
+		return ClusterConfigRevision{}, fmt.Errorf("failed to prepare select statement: %w", err)
+	}
+
+	var (
+		result ClusterConfigRevision
+		ts     string
+		value  string
+	)
+	if err := selectTxStmt.QueryRowContext(ctx, revision).Scan(&result.Revision, &ts, &result.Identity, &value); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ClusterConfigRevision{}, fmt.Errorf("revision %d: %w", revision, ErrClusterConfigRevisionNotFound)
+		}
+		return ClusterConfigRevision{}, fmt.Errorf("failed to execute select statement: %w", err)
+	}
+	if result.Timestamp, err = time.Parse(time.RFC3339, ts); err != nil {
+		log.FromContext(ctx).Error(err, "failed to parse time", "original", ts)
+	}
+	if err := json.Unmarshal([]byte(value), &result.Config); err != nil {
+		return ClusterConfigRevision{}, fmt.Errorf("failed to parse revision %d: %w", revision, err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,111 @@
+package database_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/canonical/k8s/pkg/k8sd/database"
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/k8s/pkg/utils"
+	testenv "github.com/canonical/k8s/pkg/utils/microcluster"
+	"github.com/canonical/microcluster/v2/state"
+	. "github.com/onsi/gomega"
+)
+
+func TestClusterConfigRevisions(t *testing.T) {
+	testenv.WithState(t, func(ctx context.Context, s state.State) {
+		g := NewWithT(t)
+
+		err := s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+			bootstrapConfig := types.ClusterConfig{
+				Certificates: types.Certificates{
+					CACert:            utils.Pointer("CA CERT DATA"),
+					CAKey:             utils.Pointer("CA KEY DATA"),
+					ServiceAccountKey: utils.Pointer("SA KEY DATA"),
+					K8sdPrivateKey:    utils.Pointer("K8SD KEY DATA"),
+				},
+				Datastore: types.Datastore{
+					EtcdCAKey: utils.Pointer("ETCD CA KEY DATA"),
+				},
+				Kubelet: types.Kubelet{ClusterDNS: utils.Pointer("10.152.183.10")},
+			}
+			bootstrapConfig.SetDefaults()
+			if _, err := database.SetClusterConfig(ctx, tx, bootstrapConfig, "k8sd/bootstrap"); err != nil {
+				return err
+			}
+			if _, err := database.SetClusterConfig(ctx, tx, types.ClusterConfig{
+				Kubelet: types.Kubelet{ClusterDNS: utils.Pointer("10.152.183.20")},
+			}, "root"); err != nil {
+				return err
+			}
+			return nil
+		})
+		g.Expect(err).To(Not(HaveOccurred()))
+
+		t.Run("List", func(t *testing.T) {
+			g := NewWithT(t)
+
+			err := s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+				revisions, err := database.ListClusterConfigRevisions(ctx, tx)
+				g.Expect(err).To(Not(HaveOccurred()))
+				g.Expect(revisions).To(HaveLen(2))
+				g.Expect(revisions[0].Revision).To(BeNumerically("<", revisions[1].Revision))
+				g.Expect(revisions[0].Identity).To(Equal("k8sd/bootstrap"))
+				g.Expect(revisions[1].Identity).To(Equal("root"))
+				g.Expect(revisions[1].Timestamp.IsZero()).To(BeFalse())
+				return nil
+			})
+			g.Expect(err).To(Not(HaveOccurred()))
+		})
+
+		t.Run("Get", func(t *testing.T) {
+			g := NewWithT(t)
+
+			err := s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+				revisions, err := database.ListClusterConfigRevisions(ctx, tx)
+				g.Expect(err).To(Not(HaveOccurred()))
+
+				revision, err := database.GetClusterConfigRevision(ctx, tx, revisions[0].Revision)
+				g.Expect(err).To(Not(HaveOccurred()))
+				g.Expect(revision.Config.Kubelet.GetClusterDNS()).To(Equal("10.152.183.10"))
+				g.Expect(revision.Config.Datastore.GetType()).To(Equal("k8s-dqlite"))
+
+				// secrets are not recorded in the revision history
+				g.Expect(revision.Config.Certificates).To(Equal(types.Certificates{}))
+				g.Expect(revision.Config.Datastore.EtcdCAKey).To(BeNil())
+
+				_, err = database.GetClusterConfigRevision(ctx, tx, revisions[1].Revision+100)
+				g.Expect(err).To(MatchError(database.ErrClusterConfigRevisionNotFound))
+				return nil
+			})
+			g.Expect(err).To(Not(HaveOccurred()))
+		})
+
+		t.Run("Prune", func(t *testing.T) {
+			g := NewWithT(t)
+
+			err := s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+				for i := 0; i < database.MaxClusterConfigRevisions; i++ {
+					if _, err := database.SetClusterConfig(ctx, tx, types.ClusterConfig{
+						Kubelet: types.Kubelet{ClusterDomain: utils.Pointer(fmt.Sprintf("cluster%d.local", i))},
+					}, "root"); err != nil {
+						return err
+					}
+				}
+
+				revisions, err := database.ListClusterConfigRevisions(ctx, tx)
+				g.Expect(err).To(Not(HaveOccurred()))
+				g.Expect(revisions).To(HaveLen(database.MaxClusterConfigRevisions))
+				g.Expect(revisions[0].Identity).To(Equal("root"))
+
+				revision, err := database.GetClusterConfigRevision(ctx, tx, revisions[len(revisions)-1].Revision)
+				g.Expect(err).To(Not(HaveOccurred()))
+				g.Expect(revision.Config.Kubelet.GetClusterDomain()).To(Equal(fmt.Sprintf("cluster%d.local", database.MaxClusterConfigRevisions-1)))
+				return nil
+			})
+			g.Expect(err).To(Not(HaveOccurred()))
+		})
+	})
+}
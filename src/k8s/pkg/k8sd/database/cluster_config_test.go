@@ -27,7 +27,7 @@ func TestClusterConfig(t *testing.T) {
 
 			// Write some config to the database
 			err := s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
-				_, err := database.SetClusterConfig(context.Background(), tx, expectedClusterConfig)
+				_, err := database.SetClusterConfig(context.Background(), tx, expectedClusterConfig, "test")
 				g.Expect(err).To(Not(HaveOccurred()))
 				return nil
 			})
@@ -59,7 +59,7 @@ func TestClusterConfig(t *testing.T) {
 					Certificates: types.Certificates{
 						CACert: utils.Pointer("CA CERT NEW DATA"),
 					},
-				})
+				}, "test")
 				g.Expect(err).To(HaveOccurred())
 				return err
 			})
@@ -104,7 +104,7 @@ func TestClusterConfig(t *testing.T) {
 					Certificates: types.Certificates{
 						ServiceAccountKey: utils.Pointer("SA KEY DATA"),
 					},
-				})
+				}, "test")
 				g.Expect(returnedConfig).To(Equal(expectedClusterConfig))
 				g.Expect(err).To(Not(HaveOccurred()))
 				return nil
@@ -119,5 +119,31 @@ func TestClusterConfig(t *testing.T) {
 			})
 			g.Expect(err).To(Not(HaveOccurred()))
 		})
+
+		t.Run("SetDatastoreConfig", func(t *testing.T) {
+			g := NewWithT(t)
+
+			err := s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+				returnedConfig, err := database.SetDatastoreConfig(ctx, tx, []string{"https://10.0.0.1:2379"}, "CA DATA", "CERT DATA", "KEY DATA", "test")
+				g.Expect(err).To(Not(HaveOccurred()))
+				g.Expect(returnedConfig.Datastore.GetType()).To(Equal("external"))
+				return nil
+			})
+			g.Expect(err).To(Not(HaveOccurred()))
+
+			err = s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+				clusterConfig, err := database.GetClusterConfig(ctx, tx)
+				g.Expect(err).To(Not(HaveOccurred()))
+				g.Expect(clusterConfig.Datastore.GetType()).To(Equal("external"))
+				g.Expect(clusterConfig.Datastore.GetExternalServers()).To(Equal([]string{"https://10.0.0.1:2379"}))
+				g.Expect(clusterConfig.Datastore.GetExternalCACert()).To(Equal("CA DATA"))
+				g.Expect(clusterConfig.Datastore.GetExternalClientCert()).To(Equal("CERT DATA"))
+				g.Expect(clusterConfig.Datastore.GetExternalClientKey()).To(Equal("KEY DATA"))
+				// other settings are not affected
+				g.Expect(clusterConfig.Certificates.GetCACert()).To(Equal("CA CERT DATA"))
+				return nil
+			})
+			g.Expect(err).To(Not(HaveOccurred()))
+		})
 	})
 }
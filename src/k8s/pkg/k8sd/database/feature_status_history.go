@@ -0,0 +1,88 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/k8s/pkg/log"
+	"github.com/canonical/microcluster/v2/cluster"
+)
+
+// MaxFeatureReconciles is the number of reconcile attempts that are kept in the history of each feature.
+const MaxFeatureReconciles = 50
+
+var featureStatusHistoryStmts = map[string]int{
+	"insert": MustPrepareStatement("feature-status-history", "insert.sql"),
+	"select": MustPrepareStatement("feature-status-history", "select.sql"),
+	"prune":  MustPrepareStatement("feature-status-history", "prune.sql"),
+}
+
+// AddFeatureReconcile records a reconcile attempt in the history of the given feature.
+// Only the last MaxFeatureReconciles attempts are kept for each feature.
+func AddFeatureReconcile(ctx context.Context, tx *sql.Tx, name types.FeatureName, reconcile types.FeatureReconcile) error {
+	insertTxStmt, err := cluster.Stmt(tx, featureStatusHistoryStmts["insert"])
+	if err != nil {
+		return fmt.Errorf("failed to prepare insert statement: %w", err)
+	}
+	if _, err := insertTxStmt.ExecContext(ctx,
+		name,
+		reconcile.StartedAt.UTC().Format(time.RFC3339Nano),
+		reconcile.FinishedAt.UTC().Format(time.RFC3339Nano),
+		reconcile.Result,
+		reconcile.Error,
+		reconcile.Version,
+		reconcile.ValuesHash,
+	); err != nil {
+		return fmt.Errorf("failed to execute insert statement: %w", err)
+	}
+
+	pruneTxStmt, err := cluster.Stmt(tx, featureStatusHistoryStmts["prune"])
+	if err != nil {
+		return fmt.Errorf("failed to prepare prune statement: %w", err)
+	}
+	if _, err := pruneTxStmt.ExecContext(ctx, name, name, MaxFeatureReconciles); err != nil {
+		return fmt.Errorf("failed to execute prune statement: %w", err)
+	}
+
+	return nil
+}
+
+// ListFeatureReconciles returns the recorded reconcile attempts of the given feature, oldest first.
+func ListFeatureReconciles(ctx context.Context, tx *sql.Tx, name types.FeatureName) ([]types.FeatureReconcile, error) {
+	selectTxStmt, err := cluster.Stmt(tx, featureStatusHistoryStmts["select"])
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare select statement: %w", err)
+	}
+
+	rows, err := selectTxStmt.QueryContext(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute select statement: %w", err)
+	}
+	defer rows.Close()
+
+	var result []types.FeatureReconcile
+	for rows.Next() {
+		var (
+			reconcile             types.FeatureReconcile
+			startedAt, finishedAt string
+		)
+		if err := rows.Scan(&startedAt, &finishedAt, &reconcile.Result, &reconcile.Error, &reconcile.Version, &reconcile.ValuesHash); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		if reconcile.StartedAt, err = time.Parse(time.RFC3339Nano, startedAt); err != nil {
+			log.FromContext(ctx).Error(err, "failed to parse time", "original", startedAt)
+		}
+		if reconcile.FinishedAt, err = time.Parse(time.RFC3339Nano, finishedAt); err != nil {
+			log.FromContext(ctx).Error(err, "failed to parse time", "original", finishedAt)
+		}
+		result = append(result, reconcile)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read rows: %w", err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,80 @@
+package database_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/canonical/k8s/pkg/k8sd/database"
+	"github.com/canonical/k8s/pkg/k8sd/features"
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	testenv "github.com/canonical/k8s/pkg/utils/microcluster"
+	"github.com/canonical/microcluster/v2/state"
+	. "github.com/onsi/gomega"
+)
+
+func TestFeatureReconciles(t *testing.T) {
+	testenv.WithState(t, func(ctx context.Context, s state.State) {
+		_ = s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+			t0 := time.Now().UTC()
+
+			t.Run("Empty", func(t *testing.T) {
+				g := NewWithT(t)
+				reconciles, err := database.ListFeatureReconciles(ctx, tx, features.Ingress)
+				g.Expect(err).To(Not(HaveOccurred()))
+				g.Expect(reconciles).To(BeEmpty())
+			})
+
+			t.Run("Add", func(t *testing.T) {
+				g := NewWithT(t)
+
+				failed := types.FeatureReconcile{
+					StartedAt:  t0,
+					FinishedAt: t0.Add(time.Second),
+					Result:     types.FeatureReconcileFailed,
+					Error:      "failed to apply",
+					Version:    "1.2.3",
+					ValuesHash: "abc",
+				}
+				g.Expect(database.AddFeatureReconcile(ctx, tx, features.Ingress, failed)).To(Succeed())
+				g.Expect(database.AddFeatureReconcile(ctx, tx, features.Gateway, types.FeatureReconcile{Result: types.FeatureReconcileEnabled})).To(Succeed())
+
+				reconciles, err := database.ListFeatureReconciles(ctx, tx, features.Ingress)
+				g.Expect(err).To(Not(HaveOccurred()))
+				g.Expect(reconciles).To(HaveLen(1))
+				g.Expect(reconciles[0].StartedAt).To(BeTemporally("==", failed.StartedAt))
+				g.Expect(reconciles[0].FinishedAt).To(BeTemporally("==", failed.FinishedAt))
+				g.Expect(reconciles[0].Result).To(Equal(failed.Result))
+				g.Expect(reconciles[0].Error).To(Equal(failed.Error))
+				g.Expect(reconciles[0].Version).To(Equal(failed.Version))
+				g.Expect(reconciles[0].ValuesHash).To(Equal(failed.ValuesHash))
+			})
+
+			t.Run("Bounded", func(t *testing.T) {
+				g := NewWithT(t)
+
+				for i := 0; i < database.MaxFeatureReconciles+5; i++ {
+					g.Expect(database.AddFeatureReconcile(ctx, tx, features.DNS, types.FeatureReconcile{
+						Result:  types.FeatureReconcileEnabled,
+						Version: fmt.Sprintf("%d", i),
+					})).To(Succeed())
+				}
+
+				reconciles, err := database.ListFeatureReconciles(ctx, tx, features.DNS)
+				g.Expect(err).To(Not(HaveOccurred()))
+				g.Expect(reconciles).To(HaveLen(database.MaxFeatureReconciles))
+				g.Expect(reconciles[0].Version).To(Equal("5"))
+				g.Expect(reconciles[len(reconciles)-1].Version).To(Equal(fmt.Sprintf("%d", database.MaxFeatureReconciles+4)))
+
+				// other features are not pruned
+				reconciles, err = database.ListFeatureReconciles(ctx, tx, features.Gateway)
+				g.Expect(err).To(Not(HaveOccurred()))
+				g.Expect(reconciles).To(HaveLen(1))
+			})
+
+			return nil
+		})
+	})
+}
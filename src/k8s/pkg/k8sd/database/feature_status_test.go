@@ -42,6 +42,13 @@ func TestFeatureStatus(t *testing.T) {
 				Version:   "10.20.30",
 				UpdatedAt: t0,
 			}
+			ingressStatus := types.FeatureStatus{
+				Enabled:   false,
+				Waiting:   true,
+				Message:   "waiting for network to be enabled",
+				Version:   "1.0.0",
+				UpdatedAt: t0,
+			}
 
 			t.Run("ReturnNothingInitially", func(t *testing.T) {
 				g := NewWithT(t)
@@ -110,6 +117,28 @@ func TestFeatureStatus(t *testing.T) {
 				g.Expect(ss[features.Gateway].Version).To(Equal(gatewayStatus.Version))
 				g.Expect(ss[features.Gateway].UpdatedAt).To(Equal(gatewayStatus.UpdatedAt))
 			})
+			t.Run("WaitingStatus", func(t *testing.T) {
+				g := NewWithT(t)
+
+				err := database.SetFeatureStatus(ctx, tx, features.Ingress, ingressStatus)
+				g.Expect(err).To(Not(HaveOccurred()))
+
+				ss, err := database.GetFeatureStatuses(ctx, tx)
+				g.Expect(err).To(Not(HaveOccurred()))
+				g.Expect(ss[features.Ingress].Enabled).To(BeFalse())
+				g.Expect(ss[features.Ingress].Waiting).To(BeTrue())
+				g.Expect(ss[features.Ingress].Message).To(Equal(ingressStatus.Message))
+
+				// the waiting flag is cleared once the feature is reconciled
+				ingressStatus.Enabled, ingressStatus.Waiting, ingressStatus.Message = true, false, "enabled"
+				err = database.SetFeatureStatus(ctx, tx, features.Ingress, ingressStatus)
+				g.Expect(err).To(Not(HaveOccurred()))
+
+				ss, err = database.GetFeatureStatuses(ctx, tx)
+				g.Expect(err).To(Not(HaveOccurred()))
+				g.Expect(ss[features.Ingress].Enabled).To(BeTrue())
+				g.Expect(ss[features.Ingress].Waiting).To(BeFalse())
+			})
 
 			return nil
 		})
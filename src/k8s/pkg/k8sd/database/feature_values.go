@@ -0,0 +1,60 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/microcluster/v2/cluster"
+)
+
+var featureValuesStmts = map[string]int{
+	"upsert": MustPrepareStatement("feature-values", "upsert.sql"),
+	"select": MustPrepareStatement("feature-values", "select.sql"),
+}
+
+// SetFeatureValues stores the effective Helm values of the given feature, keyed by chart name.
+func SetFeatureValues(ctx context.Context, tx *sql.Tx, name types.FeatureName, values map[string]map[string]any) error {
+	if values == nil {
+		values = map[string]map[string]any{}
+	}
+	b, err := json.Marshal(values)
+	if err != nil {
+		return fmt.Errorf("failed to encode values: %w", err)
+	}
+
+	upsertTxStmt, err := cluster.Stmt(tx, featureValuesStmts["upsert"])
+	if err != nil {
+		return fmt.Errorf("failed to prepare upsert statement: %w", err)
+	}
+	if _, err := upsertTxStmt.ExecContext(ctx, name, string(b)); err != nil {
+		return fmt.Errorf("failed to execute upsert statement: %w", err)
+	}
+	return nil
+}
+
+// GetFeatureValues returns the effective Helm values of the given feature, keyed by chart name.
+// GetFeatureValues returns an empty map if no values were stored for the feature.
+func GetFeatureValues(ctx context.Context, tx *sql.Tx, name types.FeatureName) (map[string]map[string]any, error) {
+	selectTxStmt, err := cluster.Stmt(tx, featureValuesStmts["select"])
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare select statement: %w", err)
+	}
+
+	var value string
+	if err := selectTxStmt.QueryRowContext(ctx, name).Scan(&value); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return map[string]map[string]any{}, nil
+		}
+		return nil, fmt.Errorf("failed to execute select statement: %w", err)
+	}
+
+	var values map[string]map[string]any
+	if err := json.Unmarshal([]byte(value), &values); err != nil {
+		return nil, fmt.Errorf("failed to parse values: %w", err)
+	}
+	return values, nil
+}
@@ -0,0 +1,46 @@
+package database_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/canonical/k8s/pkg/k8sd/database"
+	"github.com/canonical/k8s/pkg/k8sd/features"
+	testenv "github.com/canonical/k8s/pkg/utils/microcluster"
+	"github.com/canonical/microcluster/v2/state"
+	. "github.com/onsi/gomega"
+)
+
+func TestFeatureValues(t *testing.T) {
+	testenv.WithState(t, func(ctx context.Context, s state.State) {
+		_ = s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+			t.Run("Empty", func(t *testing.T) {
+				g := NewWithT(t)
+				values, err := database.GetFeatureValues(ctx, tx, features.DNS)
+				g.Expect(err).To(Not(HaveOccurred()))
+				g.Expect(values).To(BeEmpty())
+			})
+
+			t.Run("SetAndUpdate", func(t *testing.T) {
+				g := NewWithT(t)
+
+				err := database.SetFeatureValues(ctx, tx, features.DNS, map[string]map[string]any{
+					"ck-dns": {"replicaCount": float64(2)},
+				})
+				g.Expect(err).To(Not(HaveOccurred()))
+
+				values, err := database.GetFeatureValues(ctx, tx, features.DNS)
+				g.Expect(err).To(Not(HaveOccurred()))
+				g.Expect(values).To(Equal(map[string]map[string]any{"ck-dns": {"replicaCount": float64(2)}}))
+
+				g.Expect(database.SetFeatureValues(ctx, tx, features.DNS, nil)).To(Succeed())
+				values, err = database.GetFeatureValues(ctx, tx, features.DNS)
+				g.Expect(err).To(Not(HaveOccurred()))
+				g.Expect(values).To(BeEmpty())
+			})
+
+			return nil
+		})
+	})
+}
@@ -3,24 +3,46 @@ package database
 import (
 	"context"
 	"crypto/rand"
+	"crypto/sha256"
 	"database/sql"
 	_ "embed"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
+	"github.com/canonical/k8s/pkg/k8sd/types"
 	"github.com/canonical/microcluster/v2/cluster"
 )
 
 var k8sdTokensStmts = map[string]int{
-	"insert-token":       MustPrepareStatement("kubernetes-auth-tokens", "insert-token.sql"),
-	"select-by-token":    MustPrepareStatement("kubernetes-auth-tokens", "select-by-token.sql"),
-	"select-by-username": MustPrepareStatement("kubernetes-auth-tokens", "select-by-username.sql"),
-	"delete-by-token":    MustPrepareStatement("kubernetes-auth-tokens", "delete-by-token.sql"),
-	"delete-by-username": MustPrepareStatement("kubernetes-auth-tokens", "delete-by-username.sql"),
+	"insert-token":              MustPrepareStatement("kubernetes-auth-tokens", "insert-token.sql"),
+	"select-by-token":           MustPrepareStatement("kubernetes-auth-tokens", "select-by-token.sql"),
+	"select-tokens":             MustPrepareStatement("kubernetes-auth-tokens", "select-tokens.sql"),
+	"select-tokens-by-username": MustPrepareStatement("kubernetes-auth-tokens", "select-tokens-by-username.sql"),
+	"select-kubeconfig-tokens":  MustPrepareStatement("kubernetes-auth-tokens", "select-kubeconfig-tokens.sql"),
+	"update-last-used":          MustPrepareStatement("kubernetes-auth-tokens", "update-last-used.sql"),
+	"delete-by-token":           MustPrepareStatement("kubernetes-auth-tokens", "delete-by-token.sql"),
+	"delete-by-id":              MustPrepareStatement("kubernetes-auth-tokens", "delete-by-id.sql"),
+	"delete-by-username":        MustPrepareStatement("kubernetes-auth-tokens", "delete-by-username.sql"),
+	"delete-expired":            MustPrepareStatement("kubernetes-auth-tokens", "delete-expired.sql"),
+	"delete-kubeconfig-token":   MustPrepareStatement("kubernetes-auth-tokens", "delete-kubeconfig-token.sql"),
 }
 
+// tokenLastUsedResolution is the resolution of the last used time of tokens.
+// CheckToken only writes the last used time if it is older than this, so that not every authenticated request to
+// kube-apiserver results in a database write.
+const tokenLastUsedResolution = time.Minute
+
+var (
+	// ErrKubeconfigTokenNotFound is returned when revoking a kubeconfig token that does not exist.
+	ErrKubeconfigTokenNotFound = errors.New("kubeconfig token not found")
+	// ErrTokenNotFound is returned when revoking a token that does not exist.
+	ErrTokenNotFound = errors.New("token not found")
+)
+
 func groupsToString(inGroups []string) (string, error) {
 	groupMap := make(map[string]struct{}, len(inGroups))
 	groups := make([]string, 0, len(inGroups))
@@ -45,61 +67,137 @@ func groupsToList(inGroups string) []string {
 	return strings.Split(inGroups, ",")
 }
 
+// hashToken returns the hash of a token, which is stored in the database instead of the token itself.
+// Tokens are random, so a plain SHA256 hash is sufficient.
+func hashToken(token string) string {
+	h := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(h[:])
+}
+
+// nullTime returns a NULL time for the zero time.
+func nullTime(t time.Time) sql.NullTime {
+	if t.IsZero() {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: t.UTC(), Valid: true}
+}
+
+// hashKubernetesAuthTokens replaces the tokens that were stored in plain text with their hashes.
+// hashKubernetesAuthTokens is a schema update, as the hashes cannot be computed in SQL.
+func hashKubernetesAuthTokens(ctx context.Context, tx *sql.Tx) error {
+	rows, err := tx.QueryContext(ctx, "SELECT id, token FROM kubernetes_auth_tokens")
+	if err != nil {
+		return fmt.Errorf("failed to select tokens: %w", err)
+	}
+	tokens := make(map[int64]string)
+	for rows.Next() {
+		var (
+			id    int64
+			token string
+		)
+		if err := rows.Scan(&id, &token); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan row: %w", err)
+		}
+		tokens[id] = token
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read rows: %w", err)
+	}
+
+	for id, token := range tokens {
+		if _, err := tx.ExecContext(ctx, "UPDATE kubernetes_auth_tokens SET token = ? WHERE id = ?", hashToken(token), id); err != nil {
+			return fmt.Errorf("failed to hash token %d: %w", id, err)
+		}
+	}
+	return nil
+}
+
 // CheckToken returns the username and groups of a token (if valid).
-// CheckToken returns an error in case the token is not valid.
+// CheckToken returns an error in case the token is not valid or has expired.
+// CheckToken records when the token was last used, with a resolution of tokenLastUsedResolution.
 func CheckToken(ctx context.Context, tx *sql.Tx, token string) (string, []string, error) {
 	txStmt, err := cluster.Stmt(tx, k8sdTokensStmts["select-by-token"])
 	if err != nil {
 		return "", nil, fmt.Errorf("failed to prepare statement: %w", err)
 	}
-	var username, groupsString string
-	if err := txStmt.QueryRowContext(ctx, token).Scan(&username, &groupsString); err != nil {
+	var (
+		id               int64
+		username, groups string
+		expiry, lastUsed sql.NullTime
+	)
+	if err := txStmt.QueryRowContext(ctx, hashToken(token)).Scan(&id, &username, &groups, &expiry, &lastUsed); err != nil {
 		if err == sql.ErrNoRows {
 			return "", nil, fmt.Errorf("invalid token")
 		}
 		return "", nil, fmt.Errorf("failed to check token: %w", err)
 	}
+	now := time.Now()
+	if expiry.Valid && !now.Before(expiry.Time) {
+		return "", nil, fmt.Errorf("token has expired")
+	}
+
+	if !lastUsed.Valid || now.Sub(lastUsed.Time) >= tokenLastUsedResolution {
+		updateTxStmt, err := cluster.Stmt(tx, k8sdTokensStmts["update-last-used"])
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to prepare update statement: %w", err)
+		}
+		if _, err := updateTxStmt.ExecContext(ctx, now.UTC().Truncate(time.Second), id); err != nil {
+			return "", nil, fmt.Errorf("failed to update last used time: %w", err)
+		}
+	}
 
-	return username, groupsToList(groupsString), nil
+	return username, groupsToList(groups), nil
 }
 
-// GetOrCreateToken returns a token that matches the specified identify (username and groups).
-// GetOrCreateToken will create an existing token (if available).
-// GetOrCreateToken will create a new token otherwise.
-// GetOrCreateToken returns an error in case the auth is empty or a token could not be generated.
-func GetOrCreateToken(ctx context.Context, tx *sql.Tx, username string, groups []string) (string, error) {
-	if username == "" {
-		return "", fmt.Errorf("username cannot be empty")
+// generateToken returns a new random token.
+func generateToken() (string, error) {
+	b := make([]byte, 20)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("is the system entropy low? failed to get random bytes: %w", err)
 	}
-	groupsString, err := groupsToString(groups)
-	if err != nil {
-		return "", fmt.Errorf("invalid groups: %w", err)
+	return fmt.Sprintf("token::%s", hex.EncodeToString(b)), nil
+}
+
+// CreateToken creates a new token for the identity (username and groups) of authToken.
+// CreateToken stores the expiry and issuer of authToken. The ID and last used time of authToken are ignored.
+// CreateToken always creates a new token, as only the hash of existing tokens is known.
+// CreateToken returns the ID of the token and the token.
+// CreateToken returns an error in case the username is empty or a token could not be generated.
+func CreateToken(ctx context.Context, tx *sql.Tx, authToken types.KubernetesAuthToken) (int64, string, error) {
+	if authToken.Username == "" {
+		return 0, "", fmt.Errorf("username cannot be empty")
 	}
-	selectTxStmt, err := cluster.Stmt(tx, k8sdTokensStmts["select-by-username"])
+	groupsString, err := groupsToString(authToken.Groups)
 	if err != nil {
-		return "", fmt.Errorf("failed to prepare select statement: %w", err)
+		return 0, "", fmt.Errorf("invalid groups: %w", err)
 	}
-	var token string
-	if selectTxStmt.QueryRowContext(ctx, username, groupsString).Scan(&token) == nil {
-		return token, nil
+
+	token, err := generateToken()
+	if err != nil {
+		return 0, "", err
 	}
 
-	// generate random bytes for the token
-	b := make([]byte, 20)
-	if _, err := rand.Read(b); err != nil {
-		return "", fmt.Errorf("is the system entropy low? failed to get random bytes: %w", err)
+	var issuedBy sql.NullString
+	if authToken.IssuedBy != "" {
+		issuedBy = sql.NullString{String: authToken.IssuedBy, Valid: true}
 	}
-	token = fmt.Sprintf("token::%s", hex.EncodeToString(b))
 
 	insertTxStmt, err := cluster.Stmt(tx, k8sdTokensStmts["insert-token"])
 	if err != nil {
-		return "", fmt.Errorf("failed to prepare insert statement: %w", err)
+		return 0, "", fmt.Errorf("failed to prepare insert statement: %w", err)
 	}
-	if _, err := insertTxStmt.ExecContext(ctx, username, groupsString, token); err != nil {
-		return "", fmt.Errorf("insert token query failed: %w", err)
+	result, err := insertTxStmt.ExecContext(ctx, authToken.Username, groupsString, hashToken(token), nullTime(authToken.Expiry), nullTime(authToken.IssuedAt), issuedBy)
+	if err != nil {
+		return 0, "", fmt.Errorf("insert token query failed: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to retrieve token ID: %w", err)
 	}
 
-	return token, nil
+	return id, token, nil
 }
 
 // DeleteToken deletes the specified token (if any).
@@ -113,8 +211,138 @@ func DeleteToken(ctx context.Context, tx *sql.Tx, token string) error {
 	if err != nil {
 		return fmt.Errorf("failed to prepare delete statement: %w", err)
 	}
-	if _, err := deleteTxStmt.ExecContext(ctx, token); err != nil {
+	if _, err := deleteTxStmt.ExecContext(ctx, hashToken(token)); err != nil {
+		return fmt.Errorf("delete token query failed: %w", err)
+	}
+	return nil
+}
+
+// DeleteTokenByID deletes the token with the specified ID.
+// DeleteTokenByID returns ErrTokenNotFound if no token has the specified ID.
+func DeleteTokenByID(ctx context.Context, tx *sql.Tx, id int64) error {
+	deleteTxStmt, err := cluster.Stmt(tx, k8sdTokensStmts["delete-by-id"])
+	if err != nil {
+		return fmt.Errorf("failed to prepare delete statement: %w", err)
+	}
+	result, err := deleteTxStmt.ExecContext(ctx, id)
+	if err != nil {
+		return fmt.Errorf("delete token query failed: %w", err)
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to retrieve deleted tokens: %w", err)
+	} else if n == 0 {
+		return fmt.Errorf("%w: %d", ErrTokenNotFound, id)
+	}
+	return nil
+}
+
+// DeleteTokensByUsername deletes all tokens of a user, regardless of their groups.
+// DeleteTokensByUsername returns the number of deleted tokens.
+func DeleteTokensByUsername(ctx context.Context, tx *sql.Tx, username string) (int64, error) {
+	if username == "" {
+		return 0, fmt.Errorf("username cannot be empty")
+	}
+
+	deleteTxStmt, err := cluster.Stmt(tx, k8sdTokensStmts["delete-by-username"])
+	if err != nil {
+		return 0, fmt.Errorf("failed to prepare delete statement: %w", err)
+	}
+	result, err := deleteTxStmt.ExecContext(ctx, username)
+	if err != nil {
+		return 0, fmt.Errorf("delete tokens query failed: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to retrieve deleted tokens: %w", err)
+	}
+	return n, nil
+}
+
+// DeleteExpiredTokens deletes the tokens that expired at or before now.
+// DeleteExpiredTokens returns the number of deleted tokens.
+func DeleteExpiredTokens(ctx context.Context, tx *sql.Tx, now time.Time) (int64, error) {
+	deleteTxStmt, err := cluster.Stmt(tx, k8sdTokensStmts["delete-expired"])
+	if err != nil {
+		return 0, fmt.Errorf("failed to prepare delete statement: %w", err)
+	}
+	result, err := deleteTxStmt.ExecContext(ctx, now.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("delete expired tokens query failed: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to retrieve deleted tokens: %w", err)
+	}
+	return n, nil
+}
+
+// ListTokens returns the tokens of a user, or the tokens of all users if username is empty.
+// ListTokens includes expired tokens that were not deleted yet.
+func ListTokens(ctx context.Context, tx *sql.Tx, username string) ([]types.KubernetesAuthToken, error) {
+	if username == "" {
+		return selectTokens(ctx, tx, "select-tokens")
+	}
+	return selectTokens(ctx, tx, "select-tokens-by-username", username)
+}
+
+// ListKubeconfigTokens returns the tokens that were issued for scoped kubeconfigs, including expired ones.
+func ListKubeconfigTokens(ctx context.Context, tx *sql.Tx) ([]types.KubernetesAuthToken, error) {
+	return selectTokens(ctx, tx, "select-kubeconfig-tokens")
+}
+
+// selectTokens runs one of the statements that select full token rows.
+func selectTokens(ctx context.Context, tx *sql.Tx, stmt string, args ...any) ([]types.KubernetesAuthToken, error) {
+	selectTxStmt, err := cluster.Stmt(tx, k8sdTokensStmts[stmt])
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare select statement: %w", err)
+	}
+
+	rows, err := selectTxStmt.QueryContext(ctx, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute select statement: %w", err)
+	}
+	defer rows.Close()
+
+	var result []types.KubernetesAuthToken
+	for rows.Next() {
+		var (
+			token                      types.KubernetesAuthToken
+			groups                     string
+			expiry, lastUsed, issuedAt sql.NullTime
+			issuedBy                   sql.NullString
+		)
+		if err := rows.Scan(&token.ID, &token.Username, &groups, &expiry, &lastUsed, &issuedAt, &issuedBy); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		token.Groups = groupsToList(groups)
+		token.Expiry = expiry.Time
+		token.LastUsed = lastUsed.Time
+		token.IssuedAt = issuedAt.Time
+		token.IssuedBy = issuedBy.String
+		result = append(result, token)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read rows: %w", err)
+	}
+
+	return result, nil
+}
+
+// RevokeKubeconfigToken deletes the token of a scoped kubeconfig.
+// RevokeKubeconfigToken returns an error if no token of a scoped kubeconfig has the specified ID.
+func RevokeKubeconfigToken(ctx context.Context, tx *sql.Tx, id int64) error {
+	deleteTxStmt, err := cluster.Stmt(tx, k8sdTokensStmts["delete-kubeconfig-token"])
+	if err != nil {
+		return fmt.Errorf("failed to prepare delete statement: %w", err)
+	}
+	result, err := deleteTxStmt.ExecContext(ctx, id)
+	if err != nil {
 		return fmt.Errorf("delete token query failed: %w", err)
 	}
+	if n, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to retrieve deleted tokens: %w", err)
+	} else if n == 0 {
+		return fmt.Errorf("%w: %d", ErrKubeconfigTokenNotFound, id)
+	}
 	return nil
 }
@@ -4,8 +4,10 @@ import (
 	"context"
 	"database/sql"
 	"testing"
+	"time"
 
 	"github.com/canonical/k8s/pkg/k8sd/database"
+	"github.com/canonical/k8s/pkg/k8sd/types"
 	testenv "github.com/canonical/k8s/pkg/utils/microcluster"
 	"github.com/canonical/microcluster/v2/state"
 	. "github.com/onsi/gomega"
@@ -15,16 +17,16 @@ func TestKubernetesAuthTokens(t *testing.T) {
 	testenv.WithState(t, func(ctx context.Context, s state.State) {
 		var token1, token2 string
 
-		t.Run("GetOrCreateToken", func(t *testing.T) {
+		t.Run("CreateToken", func(t *testing.T) {
 			g := NewWithT(t)
 			err := s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
 				var err error
 
-				token1, err = database.GetOrCreateToken(ctx, tx, "user1", []string{"group1", "group2"})
+				_, token1, err = database.CreateToken(ctx, tx, types.KubernetesAuthToken{Username: "user1", Groups: []string{"group1", "group2"}})
 				g.Expect(err).To(Not(HaveOccurred()))
 				g.Expect(token1).To(Not(BeEmpty()))
 
-				token2, err = database.GetOrCreateToken(ctx, tx, "user2", []string{"group1", "group2"})
+				_, token2, err = database.CreateToken(ctx, tx, types.KubernetesAuthToken{Username: "user2", Groups: []string{"group1", "group2"}})
 				g.Expect(err).To(Not(HaveOccurred()))
 				g.Expect(token2).To(Not(BeEmpty()))
 
@@ -36,9 +38,20 @@ func TestKubernetesAuthTokens(t *testing.T) {
 			t.Run("Existing", func(t *testing.T) {
 				g := NewWithT(t)
 				err := s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
-					token, err := database.GetOrCreateToken(ctx, tx, "user1", []string{"group1", "group2"})
+					_, token, err := database.CreateToken(ctx, tx, types.KubernetesAuthToken{Username: "user1", Groups: []string{"group1", "group2"}})
 					g.Expect(err).To(Not(HaveOccurred()))
-					g.Expect(token).To(Equal(token1))
+					g.Expect(token).To(Not(Equal(token1)))
+					return nil
+				})
+				g.Expect(err).To(Not(HaveOccurred()))
+			})
+
+			t.Run("Hashed", func(t *testing.T) {
+				g := NewWithT(t)
+				err := s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+					var n int
+					g.Expect(tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM kubernetes_auth_tokens WHERE token = ?", token1).Scan(&n)).To(Succeed())
+					g.Expect(n).To(BeZero())
 					return nil
 				})
 				g.Expect(err).To(Not(HaveOccurred()))
@@ -70,6 +83,19 @@ func TestKubernetesAuthTokens(t *testing.T) {
 			})
 		})
 
+		t.Run("LastUsed", func(t *testing.T) {
+			g := NewWithT(t)
+			err := s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+				tokens, err := database.ListTokens(ctx, tx, "user1")
+				g.Expect(err).To(Not(HaveOccurred()))
+				g.Expect(tokens).To(HaveLen(2))
+				g.Expect(tokens[0].LastUsed).To(BeTemporally("~", time.Now(), time.Minute))
+				g.Expect(tokens[1].LastUsed.IsZero()).To(BeTrue())
+				return nil
+			})
+			g.Expect(err).To(Not(HaveOccurred()))
+		})
+
 		t.Run("DeleteToken", func(t *testing.T) {
 			g := NewWithT(t)
 			err := s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
@@ -84,5 +110,141 @@ func TestKubernetesAuthTokens(t *testing.T) {
 			})
 			g.Expect(err).To(Not(HaveOccurred()))
 		})
+
+		t.Run("DeleteTokensByUsername", func(t *testing.T) {
+			g := NewWithT(t)
+			err := s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+				n, err := database.DeleteTokensByUsername(ctx, tx, "user1")
+				g.Expect(err).To(Not(HaveOccurred()))
+				g.Expect(n).To(Equal(int64(2)))
+
+				_, _, err = database.CheckToken(ctx, tx, token1)
+				g.Expect(err).To(HaveOccurred())
+
+				tokens, err := database.ListTokens(ctx, tx, "")
+				g.Expect(err).To(Not(HaveOccurred()))
+				g.Expect(tokens).To(BeEmpty())
+				return nil
+			})
+			g.Expect(err).To(Not(HaveOccurred()))
+		})
+	})
+}
+
+func TestCreateTokenSameIdentity(t *testing.T) {
+	testenv.WithState(t, func(ctx context.Context, s state.State) {
+		g := NewWithT(t)
+		err := s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+			_, token1, err := database.CreateToken(ctx, tx, types.KubernetesAuthToken{Username: "user1", Groups: []string{"group2", "group1"}})
+			g.Expect(err).To(Not(HaveOccurred()))
+
+			// a token for the same username and groups is issued in addition to the existing one
+			_, token2, err := database.CreateToken(ctx, tx, types.KubernetesAuthToken{Username: "user1", Groups: []string{"group1", "group2"}})
+			g.Expect(err).To(Not(HaveOccurred()))
+			g.Expect(token2).To(Not(Equal(token1)))
+
+			for _, token := range []string{token1, token2} {
+				_, _, err = database.CheckToken(ctx, tx, token)
+				g.Expect(err).To(Not(HaveOccurred()))
+			}
+
+			tokens, err := database.ListTokens(ctx, tx, "user1")
+			g.Expect(err).To(Not(HaveOccurred()))
+			g.Expect(tokens).To(HaveLen(2))
+			return nil
+		})
+		g.Expect(err).To(Not(HaveOccurred()))
+	})
+}
+
+func TestDeleteExpiredTokens(t *testing.T) {
+	testenv.WithState(t, func(ctx context.Context, s state.State) {
+		g := NewWithT(t)
+		now := time.Now().Truncate(time.Second)
+
+		err := s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+			_, _, err := database.CreateToken(ctx, tx, types.KubernetesAuthToken{Username: "forever"})
+			g.Expect(err).To(Not(HaveOccurred()))
+			validID, _, err := database.CreateToken(ctx, tx, types.KubernetesAuthToken{Username: "valid", Expiry: now.Add(time.Hour)})
+			g.Expect(err).To(Not(HaveOccurred()))
+			expiredID, _, err := database.CreateToken(ctx, tx, types.KubernetesAuthToken{Username: "expired", Expiry: now.Add(-time.Hour)})
+			g.Expect(err).To(Not(HaveOccurred()))
+
+			n, err := database.DeleteExpiredTokens(ctx, tx, now)
+			g.Expect(err).To(Not(HaveOccurred()))
+			g.Expect(n).To(Equal(int64(1)))
+
+			tokens, err := database.ListTokens(ctx, tx, "")
+			g.Expect(err).To(Not(HaveOccurred()))
+			g.Expect(tokens).To(HaveLen(2))
+			g.Expect(tokens[0].Expiry.IsZero()).To(BeTrue())
+			g.Expect(tokens[1].ID).To(Equal(validID))
+
+			g.Expect(database.DeleteTokenByID(ctx, tx, validID)).To(Succeed())
+			g.Expect(database.DeleteTokenByID(ctx, tx, expiredID)).To(MatchError(database.ErrTokenNotFound))
+			return nil
+		})
+		g.Expect(err).To(Not(HaveOccurred()))
+	})
+}
+
+func TestKubeconfigTokens(t *testing.T) {
+	testenv.WithState(t, func(ctx context.Context, s state.State) {
+		g := NewWithT(t)
+		now := time.Now().Truncate(time.Second)
+
+		err := s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+			_, _, err := database.CreateToken(ctx, tx, types.KubernetesAuthToken{Username: "system:kube-proxy"})
+			g.Expect(err).To(Not(HaveOccurred()))
+
+			id, token, err := database.CreateToken(ctx, tx, types.KubernetesAuthToken{
+				Username: "alice",
+				Groups:   []string{"dev"},
+				IssuedAt: now,
+				IssuedBy: "root",
+				Expiry:   now.Add(8 * time.Hour),
+			})
+			g.Expect(err).To(Not(HaveOccurred()))
+			g.Expect(token).To(HavePrefix("token::"))
+
+			username, groups, err := database.CheckToken(ctx, tx, token)
+			g.Expect(err).To(Not(HaveOccurred()))
+			g.Expect(username).To(Equal("alice"))
+			g.Expect(groups).To(ConsistOf("dev"))
+
+			expiredID, expiredToken, err := database.CreateToken(ctx, tx, types.KubernetesAuthToken{
+				Username: "bob",
+				IssuedAt: now.Add(-2 * time.Hour),
+				IssuedBy: "root",
+				Expiry:   now.Add(-time.Hour),
+			})
+			g.Expect(err).To(Not(HaveOccurred()))
+
+			_, _, err = database.CheckToken(ctx, tx, expiredToken)
+			g.Expect(err).To(MatchError(ContainSubstring("expired")))
+
+			tokens, err := database.ListKubeconfigTokens(ctx, tx)
+			g.Expect(err).To(Not(HaveOccurred()))
+			g.Expect(tokens).To(HaveLen(2))
+			g.Expect(tokens[0].ID).To(Equal(id))
+			g.Expect(tokens[0].Username).To(Equal("alice"))
+			g.Expect(tokens[0].Groups).To(ConsistOf("dev"))
+			g.Expect(tokens[0].IssuedBy).To(Equal("root"))
+			g.Expect(tokens[0].IssuedAt.Equal(now)).To(BeTrue())
+			g.Expect(tokens[0].Expiry.Equal(now.Add(8 * time.Hour))).To(BeTrue())
+			g.Expect(tokens[1].ID).To(Equal(expiredID))
+			g.Expect(tokens[1].Groups).To(BeEmpty())
+
+			g.Expect(database.RevokeKubeconfigToken(ctx, tx, id)).To(Succeed())
+			_, _, err = database.CheckToken(ctx, tx, token)
+			g.Expect(err).To(HaveOccurred())
+			g.Expect(database.RevokeKubeconfigToken(ctx, tx, id)).To(MatchError(database.ErrKubeconfigTokenNotFound))
+
+			tokens, err = database.ListKubeconfigTokens(ctx, tx)
+			g.Expect(err).To(Not(HaveOccurred()))
+			g.Expect(tokens).To(HaveLen(1))
+			return nil
+		})
+		g.Expect(err).To(Not(HaveOccurred()))
 	})
 }
@@ -23,6 +23,16 @@ var (
 		schemaApplyMigration("feature-status", "000-feature-status.sql"),
 		schemaApplyMigration("worker-tokens", "001-add-expiry.sql"),
 		schemaApplyMigration("worker-nodes", "001-delete.sql"),
+		schemaApplyMigration("cluster-config-revisions", "000-create.sql"),
+		schemaApplyMigration("feature-status", "001-add-waiting.sql"),
+		schemaApplyMigration("feature-status-history", "000-create.sql"),
+		schemaApplyMigration("feature-values", "000-create.sql"),
+		schemaApplyMigration("certificate-rotations", "000-create.sql"),
+		schemaApplyMigration("ca-rotations", "000-create.sql"),
+		schemaApplyMigration("kubernetes-auth-tokens", "001-add-kubeconfigs.sql"),
+		schemaApplyMigration("kubernetes-auth-tokens", "002-add-last-used.sql"),
+		hashKubernetesAuthTokens,
+		stripClusterConfigRevisions,
 	}
 
 	//go:embed sql/migrations
@@ -0,0 +1,19 @@
+CREATE TABLE ca_rotations (
+    id                  INTEGER     PRIMARY KEY AUTOINCREMENT NOT NULL,
+    phase               TEXT        NOT NULL,
+    old_certificates    TEXT        NOT NULL,
+    new_certificates    TEXT        NOT NULL,
+    started_at          TEXT        NOT NULL,
+    updated_at          TEXT        NOT NULL
+);
+
+CREATE TABLE ca_rotation_nodes (
+    id              INTEGER     PRIMARY KEY AUTOINCREMENT NOT NULL,
+    rotation_id     INTEGER     NOT NULL,
+    node            TEXT        NOT NULL,
+    phase           TEXT        NOT NULL,
+    message         TEXT        NOT NULL,
+    updated_at      TEXT        NOT NULL,
+    UNIQUE(rotation_id, node),
+    FOREIGN KEY (rotation_id) REFERENCES ca_rotations(id) ON DELETE CASCADE
+)
@@ -0,0 +1,8 @@
+CREATE TABLE certificate_rotations (
+    id              INTEGER     PRIMARY KEY AUTOINCREMENT NOT NULL,
+    node            TEXT        UNIQUE NOT NULL,
+    state           TEXT        NOT NULL,
+    certificates    TEXT        NOT NULL,
+    message         TEXT        NOT NULL,
+    updated_at      TEXT        NOT NULL
+)
@@ -0,0 +1,6 @@
+CREATE TABLE cluster_config_revisions (
+    id          INTEGER     PRIMARY KEY AUTOINCREMENT NOT NULL,
+    timestamp   TEXT        NOT NULL,
+    identity    TEXT        NOT NULL,
+    value       TEXT        NOT NULL
+)
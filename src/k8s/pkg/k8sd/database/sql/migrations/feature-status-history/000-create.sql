@@ -0,0 +1,10 @@
+CREATE TABLE feature_status_history (
+    id              INTEGER     PRIMARY KEY AUTOINCREMENT NOT NULL,
+    name            TEXT        NOT NULL,
+    started_at      TEXT        NOT NULL,
+    finished_at     TEXT        NOT NULL,
+    result          TEXT        NOT NULL,
+    error           TEXT        NOT NULL,
+    version         TEXT        NOT NULL,
+    values_hash     TEXT        NOT NULL
+)
@@ -0,0 +1 @@
+ALTER TABLE feature_status ADD COLUMN waiting BOOLEAN NOT NULL DEFAULT FALSE;
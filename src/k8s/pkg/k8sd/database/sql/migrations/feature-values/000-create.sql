@@ -0,0 +1,5 @@
+CREATE TABLE feature_values (
+    id          INTEGER     PRIMARY KEY AUTOINCREMENT NOT NULL,
+    name        TEXT        UNIQUE NOT NULL,
+    value       TEXT        NOT NULL
+)
@@ -0,0 +1,6 @@
+ALTER TABLE kubernetes_auth_tokens
+ADD COLUMN expiry DATETIME;
+ALTER TABLE kubernetes_auth_tokens
+ADD COLUMN issued_at DATETIME;
+ALTER TABLE kubernetes_auth_tokens
+ADD COLUMN issued_by TEXT;
@@ -0,0 +1,4 @@
+DELETE FROM
+    ca_rotation_nodes
+WHERE
+    ( node = ? )
@@ -0,0 +1,8 @@
+SELECT
+    n.node, n.phase, n.message, n.updated_at
+FROM
+    ca_rotation_nodes AS n
+WHERE
+    ( n.rotation_id = ? )
+ORDER BY
+    n.node
@@ -0,0 +1,8 @@
+INSERT INTO
+    ca_rotation_nodes(rotation_id, node, phase, message, updated_at)
+VALUES
+    ( ?, ?, ?, ?, ? )
+ON CONFLICT(rotation_id, node) DO UPDATE SET
+    phase=excluded.phase,
+    message=excluded.message,
+    updated_at=excluded.updated_at;
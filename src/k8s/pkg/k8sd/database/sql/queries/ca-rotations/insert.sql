@@ -0,0 +1,4 @@
+INSERT INTO
+    ca_rotations(phase, old_certificates, new_certificates, started_at, updated_at)
+VALUES
+    ( ?, ?, ?, ?, ? )
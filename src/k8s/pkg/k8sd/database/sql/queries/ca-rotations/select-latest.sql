@@ -0,0 +1,7 @@
+SELECT
+    r.id, r.phase, r.old_certificates, r.new_certificates, r.started_at, r.updated_at
+FROM
+    ca_rotations AS r
+ORDER BY
+    r.id DESC
+LIMIT 1
@@ -0,0 +1,6 @@
+UPDATE
+    ca_rotations
+SET
+    phase = ?, updated_at = ?
+WHERE
+    ( id = ? )
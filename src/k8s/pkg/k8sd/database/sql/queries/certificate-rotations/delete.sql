@@ -0,0 +1,4 @@
+DELETE FROM
+    certificate_rotations
+WHERE
+    ( node = ? )
@@ -0,0 +1,6 @@
+SELECT
+    r.node, r.state, r.certificates, r.message, r.updated_at
+FROM
+    certificate_rotations AS r
+ORDER BY
+    r.node
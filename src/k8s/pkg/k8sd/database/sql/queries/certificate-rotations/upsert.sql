@@ -0,0 +1,9 @@
+INSERT INTO
+    certificate_rotations(node, state, certificates, message, updated_at)
+VALUES
+    ( ?, ?, ?, ?, ? )
+ON CONFLICT(node) DO UPDATE SET
+    state=excluded.state,
+    certificates=excluded.certificates,
+    message=excluded.message,
+    updated_at=excluded.updated_at;
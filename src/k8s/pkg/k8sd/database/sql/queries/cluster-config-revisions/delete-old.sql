@@ -0,0 +1,6 @@
+DELETE FROM
+    cluster_config_revisions
+WHERE
+    id NOT IN (
+        SELECT r.id FROM cluster_config_revisions AS r ORDER BY r.id DESC LIMIT ?
+    )
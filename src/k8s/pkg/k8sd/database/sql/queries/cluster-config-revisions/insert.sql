@@ -0,0 +1,4 @@
+INSERT INTO
+    cluster_config_revisions(timestamp, identity, value)
+VALUES
+    ( ?, ?, ? )
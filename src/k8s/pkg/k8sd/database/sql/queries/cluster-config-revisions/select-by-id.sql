@@ -0,0 +1,6 @@
+SELECT
+    r.id, r.timestamp, r.identity, r.value
+FROM
+    cluster_config_revisions AS r
+WHERE
+    ( r.id = ? )
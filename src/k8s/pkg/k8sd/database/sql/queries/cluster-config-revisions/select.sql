@@ -0,0 +1,6 @@
+SELECT
+    r.id, r.timestamp, r.identity
+FROM
+    cluster_config_revisions AS r
+ORDER BY
+    r.id
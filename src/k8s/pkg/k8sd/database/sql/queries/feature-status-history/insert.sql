@@ -0,0 +1,4 @@
+INSERT INTO
+    feature_status_history(name, started_at, finished_at, result, error, version, values_hash)
+VALUES
+    ( ?, ?, ?, ?, ?, ?, ? )
@@ -0,0 +1,6 @@
+DELETE FROM
+    feature_status_history
+WHERE
+    ( name = ? ) AND id NOT IN (
+        SELECT id FROM feature_status_history WHERE ( name = ? ) ORDER BY id DESC LIMIT ?
+    )
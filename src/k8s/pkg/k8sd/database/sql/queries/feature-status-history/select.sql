@@ -0,0 +1,8 @@
+SELECT
+    h.started_at, h.finished_at, h.result, h.error, h.version, h.values_hash
+FROM
+    feature_status_history AS h
+WHERE
+    ( h.name = ? )
+ORDER BY
+    h.id
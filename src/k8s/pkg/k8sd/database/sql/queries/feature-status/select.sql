@@ -1,4 +1,4 @@
 SELECT
-    name, message, version, timestamp, enabled
+    name, message, version, timestamp, enabled, waiting
 FROM
     feature_status
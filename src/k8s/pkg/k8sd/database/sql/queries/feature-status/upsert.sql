@@ -1,9 +1,10 @@
 INSERT INTO
-    feature_status(name, message, version, timestamp, enabled)
+    feature_status(name, message, version, timestamp, enabled, waiting)
 VALUES
-    (?, ?, ?, ?, ?)
+    (?, ?, ?, ?, ?, ?)
 ON CONFLICT(name) DO UPDATE SET
     message=excluded.message,
     version=excluded.version,
     timestamp=excluded.timestamp,
-    enabled=excluded.enabled;
+    enabled=excluded.enabled,
+    waiting=excluded.waiting;
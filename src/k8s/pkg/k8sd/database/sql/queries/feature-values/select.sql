@@ -0,0 +1,6 @@
+SELECT
+    v.value
+FROM
+    feature_values AS v
+WHERE
+    ( v.name = ? )
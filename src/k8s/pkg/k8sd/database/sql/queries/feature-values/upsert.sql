@@ -0,0 +1,6 @@
+INSERT INTO
+    feature_values(name, value)
+VALUES
+    ( ?, ? )
+ON CONFLICT(name) DO UPDATE SET
+    value=excluded.value;
@@ -0,0 +1,4 @@
+DELETE FROM
+    kubernetes_auth_tokens AS t
+WHERE
+    ( t.id = ? AND t.issued_at IS NOT NULL )
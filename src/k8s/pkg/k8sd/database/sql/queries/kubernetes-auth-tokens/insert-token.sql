@@ -1,4 +1,4 @@
 INSERT INTO
-    kubernetes_auth_tokens(username, groups, token)
+    kubernetes_auth_tokens(username, groups, token, expiry, issued_at, issued_by)
 VALUES
-    ( ?, ?, ? )
+    ( ?, ?, ?, ?, ?, ? )
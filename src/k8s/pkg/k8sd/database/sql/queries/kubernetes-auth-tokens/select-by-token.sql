@@ -1,5 +1,5 @@
 SELECT
-    username, groups
+    id, username, groups, expiry, last_used
 FROM
     kubernetes_auth_tokens AS t
 WHERE
@@ -0,0 +1,8 @@
+SELECT
+    id, username, groups, expiry, last_used, issued_at, issued_by
+FROM
+    kubernetes_auth_tokens AS t
+WHERE
+    ( t.issued_at IS NOT NULL )
+ORDER BY
+    t.id
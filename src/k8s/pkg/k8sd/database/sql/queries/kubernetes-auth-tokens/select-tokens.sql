@@ -0,0 +1,6 @@
+SELECT
+    id, username, groups, expiry, last_used, issued_at, issued_by
+FROM
+    kubernetes_auth_tokens AS t
+ORDER BY
+    t.id
@@ -0,0 +1,6 @@
+UPDATE
+    kubernetes_auth_tokens
+SET
+    last_used = ?
+WHERE
+    ( id = ? )
@@ -6,15 +6,18 @@ import (
 	"fmt"
 
 	"github.com/canonical/k8s/pkg/k8sd/database"
+	"github.com/canonical/k8s/pkg/k8sd/types"
 	"github.com/canonical/microcluster/v2/state"
 )
 
-// GetOrCreateAuthToken returns a k8s auth token based on the provided username/groups.
-func GetOrCreateAuthToken(ctx context.Context, state state.State, username string, groups []string) (string, error) {
+// CreateAuthToken creates a new k8s auth token for the provided username/groups. The token does not expire.
+// CreateAuthToken always issues an additional token, so tokens that were issued before for the same username/groups
+// keep working until they are revoked.
+func CreateAuthToken(ctx context.Context, state state.State, username string, groups []string) (string, error) {
 	var token string
 	if err := state.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
 		var err error
-		token, err = database.GetOrCreateToken(ctx, tx, username, groups)
+		_, token, err = database.CreateToken(ctx, tx, types.KubernetesAuthToken{Username: username, Groups: groups})
 		return err
 	}); err != nil {
 		return "", fmt.Errorf("database transaction failed: %w", err)
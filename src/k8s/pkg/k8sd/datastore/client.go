@@ -0,0 +1,47 @@
+package datastore
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.uber.org/zap"
+)
+
+// NewClient creates an etcd client for the given servers.
+// Servers may be unix sockets, e.g. "unix:///var/snap/k8s/common/var/lib/k8s-dqlite/k8s-dqlite.sock".
+// caCert, clientCert and clientKey are PEM encoded and may be empty.
+func NewClient(servers []string, caCert string, clientCert string, clientKey string) (*clientv3.Client, error) {
+	config := clientv3.Config{
+		Endpoints:   servers,
+		DialTimeout: 10 * time.Second,
+		Logger:      zap.NewNop(),
+	}
+
+	if caCert != "" || clientCert != "" {
+		tlsConfig := &tls.Config{}
+		if caCert != "" {
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM([]byte(caCert)) {
+				return nil, fmt.Errorf("invalid datastore CA certificate")
+			}
+			tlsConfig.RootCAs = pool
+		}
+		if clientCert != "" {
+			cert, err := tls.X509KeyPair([]byte(clientCert), []byte(clientKey))
+			if err != nil {
+				return nil, fmt.Errorf("failed to load datastore client certificate: %w", err)
+			}
+			tlsConfig.Certificates = []tls.Certificate{cert}
+		}
+		config.TLS = tlsConfig
+	}
+
+	client, err := clientv3.New(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create etcd client: %w", err)
+	}
+	return client, nil
+}
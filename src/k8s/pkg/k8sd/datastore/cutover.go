@@ -0,0 +1,173 @@
+package datastore
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/canonical/k8s/pkg/log"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// CutoverResult is the result of verifying a datastore migration after kube-apiserver switched to the target datastore.
+type CutoverResult struct {
+	// SourceRevision is the final revision of the source datastore.
+	SourceRevision int64
+	// Synced is the number of keys that were changed in the source datastore after the migration and synced to the target datastore.
+	Synced int64
+	// Conflicts is the number of keys that were changed in both datastores after the migration.
+	// The target datastore keeps its version of these keys.
+	Conflicts int64
+	// Keys is the number of keys in the target datastore after the cutover.
+	Keys int64
+}
+
+// VerifyCutover verifies a datastore migration once the cluster configuration points kube-apiserver to the dst datastore.
+//
+// kube-apiserver instances keep writing to the src datastore until they are restarted, so VerifyCutover first waits
+// until the revision of the src datastore did not change for the settle duration. Keys that were changed in the src
+// datastore after the migration are then synced to the dst datastore, unless they were also changed in the dst
+// datastore after the migration. Writes to the dst datastore are conditional on the revision of the key, so that
+// changes made by kube-apiserver after the cutover are never overwritten.
+// Finally, VerifyCutover verifies that the src datastore did not change while it was synced.
+func VerifyCutover(ctx context.Context, src clientv3.KV, srcLease clientv3.Lease, dst clientv3.KV, dstLease clientv3.Lease, migrated MigrateResult, settle time.Duration) (CutoverResult, error) {
+	log := log.FromContext(ctx).WithValues("datastore", "cutover")
+
+	log.Info("Waiting for writes to the source datastore to stop")
+	rev, err := waitForSettle(ctx, src, settle)
+	if err != nil {
+		return CutoverResult{}, fmt.Errorf("failed to wait for writes to the source datastore to stop: %w", err)
+	}
+
+	result := CutoverResult{SourceRevision: rev}
+	if rev != migrated.SourceRevision {
+		log.Info("Source datastore changed after the migration, syncing changes", "revision", migrated.SourceRevision, "current", rev)
+		if err := syncCutover(ctx, src, dst, newLeaseMap(srcLease, dstLease), migrated, rev, &result); err != nil {
+			return CutoverResult{}, fmt.Errorf("failed to sync changed keys: %w", err)
+		}
+
+		resp, err := src.Get(ctx, keyPrefix, clientv3.WithPrefix(), clientv3.WithCountOnly())
+		if err != nil {
+			return CutoverResult{}, fmt.Errorf("failed to query source datastore revision: %w", err)
+		}
+		if resp.Header.Revision != rev {
+			return CutoverResult{}, fmt.Errorf("source datastore changed from revision %d to %d while it was synced, kube-apiserver is still using it", rev, resp.Header.Revision)
+		}
+	}
+
+	resp, err := dst.Get(ctx, keyPrefix, clientv3.WithPrefix(), clientv3.WithCountOnly())
+	if err != nil {
+		return CutoverResult{}, fmt.Errorf("failed to count keys in target datastore: %w", err)
+	}
+	if resp.Header.Revision < migrated.TargetRevision {
+		return CutoverResult{}, fmt.Errorf("target datastore is at revision %d, which is older than revision %d after the migration", resp.Header.Revision, migrated.TargetRevision)
+	}
+	result.Keys = resp.Count
+
+	return result, nil
+}
+
+// waitForSettle waits until the revision of the datastore did not change for the settle duration and returns it.
+func waitForSettle(ctx context.Context, kv clientv3.KV, settle time.Duration) (int64, error) {
+	var rev int64
+	for {
+		resp, err := kv.Get(ctx, keyPrefix, clientv3.WithPrefix(), clientv3.WithCountOnly())
+		if err != nil {
+			return 0, fmt.Errorf("failed to query revision: %w", err)
+		}
+		if resp.Header.Revision == rev {
+			return rev, nil
+		}
+		rev = resp.Header.Revision
+
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-time.After(settle):
+		}
+	}
+}
+
+// syncCutover syncs the keys that changed in src between the migrated revision and rev to dst.
+// Keys of dst that changed after the migrated target revision are kept and counted as conflicts.
+func syncCutover(ctx context.Context, src clientv3.KV, dst clientv3.KV, leases *leaseMap, migrated MigrateResult, rev int64, result *CutoverResult) error {
+	// dstRevs maps the keys of dst to their revision
+	dstRevs := make(map[string]int64)
+	resp, err := dst.Get(ctx, keyPrefix, clientv3.WithPrefix(), clientv3.WithKeysOnly())
+	if err != nil {
+		return fmt.Errorf("failed to list keys from target datastore: %w", err)
+	}
+	for _, kv := range resp.Kvs {
+		dstRevs[string(kv.Key)] = kv.ModRevision
+	}
+
+	// apply writes the op to dst if the key did not change after the migration
+	apply := func(key string, op clientv3.Op) error {
+		dstRev := dstRevs[key]
+		if dstRev > migrated.TargetRevision {
+			result.Conflicts++
+			return nil
+		}
+		resp, err := dst.Txn(ctx).If(clientv3.Compare(clientv3.ModRevision(key), "=", dstRev)).Then(op).Commit()
+		if err != nil {
+			return fmt.Errorf("failed to write key %q to target datastore: %w", key, err)
+		}
+		if !resp.Succeeded {
+			result.Conflicts++
+			return nil
+		}
+		result.Synced++
+		return nil
+	}
+
+	var (
+		srcKeys = make(map[string]struct{})
+		key     = keyPrefix
+		end     = clientv3.GetPrefixRangeEnd(keyPrefix)
+	)
+	for {
+		resp, err := src.Get(ctx, key, clientv3.WithRange(end), clientv3.WithLimit(pageSize), clientv3.WithRev(rev))
+		if err != nil {
+			return fmt.Errorf("failed to list keys from source datastore: %w", err)
+		}
+
+		for _, kv := range resp.Kvs {
+			var opts []clientv3.OpOption
+			if kv.Lease != 0 {
+				lease, ok, err := leases.get(ctx, kv.Lease)
+				if err != nil {
+					return fmt.Errorf("failed to get lease of key %q: %w", string(kv.Key), err)
+				}
+				if !ok {
+					continue
+				}
+				opts = append(opts, clientv3.WithLease(lease))
+			}
+
+			srcKeys[string(kv.Key)] = struct{}{}
+			if kv.ModRevision <= migrated.SourceRevision {
+				continue
+			}
+			if err := apply(string(kv.Key), clientv3.OpPut(string(kv.Key), string(kv.Value), opts...)); err != nil {
+				return err
+			}
+		}
+
+		if !resp.More || len(resp.Kvs) == 0 {
+			break
+		}
+		key = string(resp.Kvs[len(resp.Kvs)-1].Key) + "\x00"
+	}
+
+	// keys that were deleted from src after the migration
+	for key, dstRev := range dstRevs {
+		if _, ok := srcKeys[key]; ok || dstRev > migrated.TargetRevision {
+			continue
+		}
+		if err := apply(key, clientv3.OpDelete(key)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
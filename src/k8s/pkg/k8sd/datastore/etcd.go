@@ -0,0 +1,120 @@
+package datastore
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// EtcdMember is a member of the managed etcd cluster.
+type EtcdMember struct {
+	// Name is the name of the member. Name is empty if the member has not started yet.
+	Name string
+	// PeerURL is the URL that the member uses to communicate with other members.
+	PeerURL string
+	// Learner is true if the member is a non-voting member.
+	Learner bool
+}
+
+// NewEtcdClient creates a client for the managed etcd cluster, using the CA and client certificates in pkiDir.
+func NewEtcdClient(servers []string, pkiDir string) (*clientv3.Client, error) {
+	var files [3]string
+	for i, name := range []string{"ca.crt", "client.crt", "client.key"} {
+		b, err := os.ReadFile(filepath.Join(pkiDir, name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read etcd certificate: %w", err)
+		}
+		files[i] = string(b)
+	}
+	return NewClient(servers, files[0], files[1], files[2])
+}
+
+// ListEtcdMembers returns the members of the managed etcd cluster.
+func ListEtcdMembers(ctx context.Context, c clientv3.Cluster) ([]EtcdMember, error) {
+	resp, err := c.MemberList(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list etcd members: %w", err)
+	}
+
+	members := make([]EtcdMember, 0, len(resp.Members))
+	for _, m := range resp.Members {
+		member := EtcdMember{Name: m.Name, Learner: m.IsLearner}
+		if len(m.PeerURLs) > 0 {
+			member.PeerURL = m.PeerURLs[0]
+		}
+		members = append(members, member)
+	}
+	return members, nil
+}
+
+// AddEtcdMember adds a new learner member with the given name and peer URL to the etcd cluster.
+// AddEtcdMember returns the value of the --initial-cluster argument that the new member must be started with.
+// Learners do not count towards the quorum until they are promoted with PromoteEtcdMember.
+func AddEtcdMember(ctx context.Context, c clientv3.Cluster, name string, peerURL string) (string, error) {
+	resp, err := c.MemberAddAsLearner(ctx, []string{peerURL})
+	if err != nil {
+		return "", fmt.Errorf("failed to add etcd member: %w", err)
+	}
+
+	var initialCluster []string
+	for _, m := range resp.Members {
+		memberName := m.Name
+		if m.ID == resp.Member.ID {
+			memberName = name
+		}
+		// members that have not started yet do not have a name and cannot be part of the initial cluster
+		if memberName == "" {
+			continue
+		}
+		for _, url := range m.PeerURLs {
+			initialCluster = append(initialCluster, fmt.Sprintf("%s=%s", memberName, url))
+		}
+	}
+	return strings.Join(initialCluster, ","), nil
+}
+
+// PromoteEtcdMember promotes the learner member with the given peer URL to a voting member.
+// PromoteEtcdMember fails if the learner has not caught up with the leader yet, so callers are expected to retry.
+func PromoteEtcdMember(ctx context.Context, c clientv3.Cluster, peerURL string) error {
+	resp, err := c.MemberList(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list etcd members: %w", err)
+	}
+	for _, m := range resp.Members {
+		if !slices.Contains(m.PeerURLs, peerURL) {
+			continue
+		}
+		if !m.IsLearner {
+			return nil
+		}
+		if _, err := c.MemberPromote(ctx, m.ID); err != nil {
+			return fmt.Errorf("failed to promote etcd member %q: %w", peerURL, err)
+		}
+		return nil
+	}
+	return fmt.Errorf("etcd member %q not found", peerURL)
+}
+
+// RemoveEtcdMember removes the member with the given peer URL from the etcd cluster.
+// RemoveEtcdMember does nothing if no such member exists.
+func RemoveEtcdMember(ctx context.Context, c clientv3.Cluster, peerURL string) error {
+	resp, err := c.MemberList(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list etcd members: %w", err)
+	}
+	for _, m := range resp.Members {
+		if !slices.Contains(m.PeerURLs, peerURL) {
+			continue
+		}
+		if _, err := c.MemberRemove(ctx, m.ID); err != nil {
+			return fmt.Errorf("failed to remove etcd member %q: %w", peerURL, err)
+		}
+		return nil
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package datastore_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/canonical/k8s/pkg/k8sd/datastore"
+	. "github.com/onsi/gomega"
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// fakeCluster is an in-memory clientv3.Cluster.
+type fakeCluster struct {
+	nextID  uint64
+	members []*pb.Member
+
+	// promoteErr is returned by MemberPromote, if set.
+	promoteErr error
+}
+
+func (f *fakeCluster) add(name string, peerURL string, learner bool) *pb.Member {
+	f.nextID++
+	m := &pb.Member{ID: f.nextID, Name: name, PeerURLs: []string{peerURL}, IsLearner: learner}
+	f.members = append(f.members, m)
+	return m
+}
+
+func (f *fakeCluster) MemberList(ctx context.Context) (*clientv3.MemberListResponse, error) {
+	return &clientv3.MemberListResponse{Members: f.members}, nil
+}
+
+func (f *fakeCluster) MemberAdd(ctx context.Context, peerAddrs []string) (*clientv3.MemberAddResponse, error) {
+	m := f.add("", peerAddrs[0], false)
+	return &clientv3.MemberAddResponse{Member: m, Members: f.members}, nil
+}
+
+func (f *fakeCluster) MemberAddAsLearner(ctx context.Context, peerAddrs []string) (*clientv3.MemberAddResponse, error) {
+	m := f.add("", peerAddrs[0], true)
+	return &clientv3.MemberAddResponse{Member: m, Members: f.members}, nil
+}
+
+func (f *fakeCluster) MemberRemove(ctx context.Context, id uint64) (*clientv3.MemberRemoveResponse, error) {
+	for i, m := range f.members {
+		if m.ID == id {
+			f.members = append(f.members[:i], f.members[i+1:]...)
+			return &clientv3.MemberRemoveResponse{Members: f.members}, nil
+		}
+	}
+	return nil, fmt.Errorf("member %d not found", id)
+}
+
+func (f *fakeCluster) MemberUpdate(ctx context.Context, id uint64, peerAddrs []string) (*clientv3.MemberUpdateResponse, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (f *fakeCluster) MemberPromote(ctx context.Context, id uint64) (*clientv3.MemberPromoteResponse, error) {
+	if f.promoteErr != nil {
+		return nil, f.promoteErr
+	}
+	for _, m := range f.members {
+		if m.ID == id {
+			m.IsLearner = false
+			return &clientv3.MemberPromoteResponse{Members: f.members}, nil
+		}
+	}
+	return nil, fmt.Errorf("member %d not found", id)
+}
+
+func TestEtcdMembers(t *testing.T) {
+	t.Run("Add", func(t *testing.T) {
+		g := NewWithT(t)
+
+		c := &fakeCluster{}
+		c.add("n1", "https://10.0.0.1:2380", false)
+		c.add("", "https://10.0.0.2:2380", true)
+
+		initialCluster, err := datastore.AddEtcdMember(context.Background(), c, "n3", "https://10.0.0.3:2380")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(initialCluster).To(Equal("n1=https://10.0.0.1:2380,n3=https://10.0.0.3:2380"))
+
+		members, err := datastore.ListEtcdMembers(context.Background(), c)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(members).To(ContainElement(datastore.EtcdMember{PeerURL: "https://10.0.0.3:2380", Learner: true}))
+	})
+
+	t.Run("Promote", func(t *testing.T) {
+		g := NewWithT(t)
+
+		c := &fakeCluster{}
+		c.add("n1", "https://10.0.0.1:2380", false)
+		learner := c.add("n2", "https://10.0.0.2:2380", true)
+
+		c.promoteErr = fmt.Errorf("can only promote a learner member which is in sync with leader")
+		g.Expect(datastore.PromoteEtcdMember(context.Background(), c, "https://10.0.0.2:2380")).ToNot(Succeed())
+		g.Expect(learner.IsLearner).To(BeTrue())
+
+		c.promoteErr = nil
+		g.Expect(datastore.PromoteEtcdMember(context.Background(), c, "https://10.0.0.2:2380")).To(Succeed())
+		g.Expect(learner.IsLearner).To(BeFalse())
+
+		// promoting a voting member is a no-op
+		g.Expect(datastore.PromoteEtcdMember(context.Background(), c, "https://10.0.0.2:2380")).To(Succeed())
+
+		g.Expect(datastore.PromoteEtcdMember(context.Background(), c, "https://10.0.0.3:2380")).ToNot(Succeed())
+	})
+
+	t.Run("Remove", func(t *testing.T) {
+		g := NewWithT(t)
+
+		c := &fakeCluster{}
+		c.add("n1", "https://10.0.0.1:2380", false)
+		c.add("n2", "https://10.0.0.2:2380", false)
+
+		g.Expect(datastore.RemoveEtcdMember(context.Background(), c, "https://10.0.0.2:2380")).To(Succeed())
+		g.Expect(datastore.RemoveEtcdMember(context.Background(), c, "https://10.0.0.3:2380")).To(Succeed())
+
+		members, err := datastore.ListEtcdMembers(context.Background(), c)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(members).To(Equal([]datastore.EtcdMember{{Name: "n1", PeerURL: "https://10.0.0.1:2380"}}))
+	})
+}
@@ -0,0 +1,202 @@
+package datastore
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/canonical/k8s/pkg/log"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+const (
+	// keyPrefix is the prefix of all keys written by kube-apiserver.
+	keyPrefix = "/registry/"
+	// pageSize is the number of keys that are retrieved from the source datastore in a single request.
+	pageSize = 500
+	// maxSyncRounds is the maximum number of passes to catch up with writes that happen during the migration.
+	maxSyncRounds = 5
+)
+
+// MigrateResult is the result of a datastore migration.
+type MigrateResult struct {
+	// Keys is the number of keys in the target datastore after the migration.
+	Keys int64
+	// SourceRevision is the revision of the source datastore that the target datastore matches.
+	SourceRevision int64
+	// TargetRevision is the revision of the target datastore after the migration.
+	TargetRevision int64
+}
+
+// Migrate copies all Kubernetes keys from the src datastore to the dst datastore.
+// Migrate refuses to write to a dst datastore that already contains Kubernetes keys.
+// Keys with a lease are attached to new leases in the dst datastore with the remaining time to live of their source lease.
+//
+// Migrate can run while kube-apiserver is writing to the source datastore. After the initial copy,
+// Migrate performs up to maxSyncRounds passes to copy keys that were changed and delete keys that
+// were removed in the meantime, until the source revision no longer changes.
+// Finally, Migrate verifies that both datastores contain the same number of keys at the synced revision.
+func Migrate(ctx context.Context, src clientv3.KV, srcLease clientv3.Lease, dst clientv3.KV, dstLease clientv3.Lease) (MigrateResult, error) {
+	log := log.FromContext(ctx).WithValues("datastore", "migrate")
+
+	if resp, err := dst.Get(ctx, keyPrefix, clientv3.WithPrefix(), clientv3.WithCountOnly()); err != nil {
+		return MigrateResult{}, fmt.Errorf("failed to query target datastore: %w", err)
+	} else if resp.Count > 0 {
+		return MigrateResult{}, fmt.Errorf("target datastore is not empty, found %d keys with prefix %q", resp.Count, keyPrefix)
+	}
+
+	leases := newLeaseMap(srcLease, dstLease)
+
+	log.Info("Copying keys to target datastore")
+	synced, err := syncKeys(ctx, src, dst, leases, 0)
+	if err != nil {
+		return MigrateResult{}, fmt.Errorf("failed to copy keys: %w", err)
+	}
+
+	for i := 0; i < maxSyncRounds; i++ {
+		resp, err := src.Get(ctx, keyPrefix, clientv3.WithPrefix(), clientv3.WithCountOnly())
+		if err != nil {
+			return MigrateResult{}, fmt.Errorf("failed to query source datastore revision: %w", err)
+		}
+		if resp.Header.Revision == synced.revision {
+			break
+		}
+
+		log.Info("Source datastore changed during migration, syncing changes", "revision", synced.revision, "current", resp.Header.Revision)
+		if synced, err = syncKeys(ctx, src, dst, leases, synced.revision); err != nil {
+			return MigrateResult{}, fmt.Errorf("failed to sync changed keys: %w", err)
+		}
+	}
+
+	resp, err := dst.Get(ctx, keyPrefix, clientv3.WithPrefix(), clientv3.WithCountOnly())
+	if err != nil {
+		return MigrateResult{}, fmt.Errorf("failed to count keys in target datastore: %w", err)
+	}
+	if resp.Count != synced.keys {
+		return MigrateResult{}, fmt.Errorf("key count mismatch after migration, source datastore has %d keys at revision %d but target datastore has %d keys", synced.keys, synced.revision, resp.Count)
+	}
+
+	return MigrateResult{
+		Keys:           resp.Count,
+		SourceRevision: synced.revision,
+		TargetRevision: resp.Header.Revision,
+	}, nil
+}
+
+// syncResult is the result of a single pass of syncKeys.
+type syncResult struct {
+	// revision is the revision of the source datastore that the target datastore has been synced to.
+	revision int64
+	// keys is the number of keys of the source datastore at revision, excluding keys with an expired lease.
+	keys int64
+}
+
+// syncKeys copies all keys from src that were modified after sinceRev to dst.
+// Keys with an expired lease are not copied.
+// If sinceRev is not zero, keys that no longer exist in src are also deleted from dst.
+func syncKeys(ctx context.Context, src clientv3.KV, dst clientv3.KV, leases *leaseMap, sinceRev int64) (syncResult, error) {
+	var (
+		rev     int64
+		srcKeys = make(map[string]struct{})
+		key     = keyPrefix
+		end     = clientv3.GetPrefixRangeEnd(keyPrefix)
+	)
+
+	for {
+		opts := []clientv3.OpOption{clientv3.WithRange(end), clientv3.WithLimit(pageSize)}
+		if rev > 0 {
+			// read all pages at the same revision for a consistent view of the source datastore
+			opts = append(opts, clientv3.WithRev(rev))
+		}
+		resp, err := src.Get(ctx, key, opts...)
+		if err != nil {
+			return syncResult{}, fmt.Errorf("failed to list keys from source datastore: %w", err)
+		}
+		if rev == 0 {
+			rev = resp.Header.Revision
+		}
+
+		for _, kv := range resp.Kvs {
+			var opts []clientv3.OpOption
+			if kv.Lease != 0 {
+				lease, ok, err := leases.get(ctx, kv.Lease)
+				if err != nil {
+					return syncResult{}, fmt.Errorf("failed to get lease of key %q: %w", string(kv.Key), err)
+				}
+				if !ok {
+					// the lease expired since the keys were listed
+					continue
+				}
+				opts = append(opts, clientv3.WithLease(lease))
+			}
+
+			srcKeys[string(kv.Key)] = struct{}{}
+			if kv.ModRevision <= sinceRev {
+				continue
+			}
+			if _, err := dst.Put(ctx, string(kv.Key), string(kv.Value), opts...); err != nil {
+				return syncResult{}, fmt.Errorf("failed to write key %q to target datastore: %w", string(kv.Key), err)
+			}
+		}
+
+		if !resp.More || len(resp.Kvs) == 0 {
+			break
+		}
+		key = string(resp.Kvs[len(resp.Kvs)-1].Key) + "\x00"
+	}
+
+	result := syncResult{revision: rev, keys: int64(len(srcKeys))}
+	if sinceRev == 0 {
+		return result, nil
+	}
+
+	resp, err := dst.Get(ctx, keyPrefix, clientv3.WithPrefix(), clientv3.WithKeysOnly())
+	if err != nil {
+		return syncResult{}, fmt.Errorf("failed to list keys from target datastore: %w", err)
+	}
+	for _, kv := range resp.Kvs {
+		if _, ok := srcKeys[string(kv.Key)]; ok {
+			continue
+		}
+		if _, err := dst.Delete(ctx, string(kv.Key)); err != nil {
+			return syncResult{}, fmt.Errorf("failed to delete key %q from target datastore: %w", string(kv.Key), err)
+		}
+	}
+
+	return result, nil
+}
+
+// leaseMap grants a lease in the target datastore for each lease of the source datastore.
+type leaseMap struct {
+	src clientv3.Lease
+	dst clientv3.Lease
+	// ids maps the ID of each source lease to the ID of its target lease, or 0 if the source lease expired.
+	ids map[int64]clientv3.LeaseID
+}
+
+func newLeaseMap(src clientv3.Lease, dst clientv3.Lease) *leaseMap {
+	return &leaseMap{src: src, dst: dst, ids: make(map[int64]clientv3.LeaseID)}
+}
+
+// get returns the target lease of the source lease with the given ID.
+// get grants the target lease with the remaining time to live of the source lease on first use.
+// get returns false if the source lease expired.
+func (m *leaseMap) get(ctx context.Context, id int64) (clientv3.LeaseID, bool, error) {
+	if lease, ok := m.ids[id]; ok {
+		return lease, lease != 0, nil
+	}
+
+	ttl, err := leaseTTL(ctx, m.src, id)
+	if err != nil {
+		return 0, false, err
+	}
+	if ttl <= 0 {
+		m.ids[id] = 0
+		return 0, false, nil
+	}
+	resp, err := m.dst.Grant(ctx, ttl)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to grant lease: %w", err)
+	}
+	m.ids[id] = resp.ID
+	return resp.ID, true, nil
+}
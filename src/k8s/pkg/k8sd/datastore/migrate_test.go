@@ -0,0 +1,325 @@
+package datastore_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/canonical/k8s/pkg/k8sd/datastore"
+	. "github.com/onsi/gomega"
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// fakeKV is an in-memory clientv3.KV that supports the operations used by datastore.Migrate, datastore.VerifyCutover, datastore.Save and datastore.Load.
+type fakeKV struct {
+	rev int64
+	kvs map[string]*mvccpb.KeyValue
+
+	// onGet is called before every range request, if set.
+	onGet func()
+}
+
+func newFakeKV(keys map[string]string) *fakeKV {
+	kv := &fakeKV{kvs: make(map[string]*mvccpb.KeyValue)}
+	for k, v := range keys {
+		kv.put(k, v)
+	}
+	return kv
+}
+
+func (f *fakeKV) put(key, value string) {
+	f.putWithLease(key, value, 0)
+}
+
+func (f *fakeKV) putWithLease(key, value string, lease int64) {
+	f.rev++
+	f.kvs[key] = &mvccpb.KeyValue{Key: []byte(key), Value: []byte(value), ModRevision: f.rev, Lease: lease}
+}
+
+func (f *fakeKV) Get(ctx context.Context, key string, opts ...clientv3.OpOption) (*clientv3.GetResponse, error) {
+	if f.onGet != nil {
+		f.onGet()
+	}
+
+	op := clientv3.OpGet(key, opts...)
+	var kvs []*mvccpb.KeyValue
+	for k, kv := range f.kvs {
+		if k == key || (op.RangeBytes() != nil && k >= key && bytes.Compare([]byte(k), op.RangeBytes()) < 0) {
+			kvs = append(kvs, kv)
+		}
+	}
+	sort.Slice(kvs, func(i, j int) bool { return bytes.Compare(kvs[i].Key, kvs[j].Key) < 0 })
+
+	resp := &clientv3.GetResponse{Header: &pb.ResponseHeader{Revision: f.rev}, Count: int64(len(kvs))}
+	if !op.IsCountOnly() {
+		resp.Kvs = kvs
+	}
+	return resp, nil
+}
+
+func (f *fakeKV) Put(ctx context.Context, key, val string, opts ...clientv3.OpOption) (*clientv3.PutResponse, error) {
+	// clientv3.Op does not expose the lease of a put
+	op := clientv3.OpPut(key, val, opts...)
+	f.putWithLease(key, val, reflect.ValueOf(op).FieldByName("leaseID").Int())
+	return &clientv3.PutResponse{Header: &pb.ResponseHeader{Revision: f.rev}}, nil
+}
+
+func (f *fakeKV) Delete(ctx context.Context, key string, opts ...clientv3.OpOption) (*clientv3.DeleteResponse, error) {
+	op := clientv3.OpDelete(key, opts...)
+	f.rev++
+	for k := range f.kvs {
+		if k == key || (op.RangeBytes() != nil && k >= key && bytes.Compare([]byte(k), op.RangeBytes()) < 0) {
+			delete(f.kvs, k)
+		}
+	}
+	return &clientv3.DeleteResponse{Header: &pb.ResponseHeader{Revision: f.rev}}, nil
+}
+
+func (f *fakeKV) Compact(ctx context.Context, rev int64, opts ...clientv3.CompactOption) (*clientv3.CompactResponse, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (f *fakeKV) Do(ctx context.Context, op clientv3.Op) (clientv3.OpResponse, error) {
+	return clientv3.OpResponse{}, fmt.Errorf("not implemented")
+}
+
+func (f *fakeKV) Txn(ctx context.Context) clientv3.Txn {
+	return &fakeTxn{kv: f}
+}
+
+// fakeTxn is a transaction of fakeKV that supports comparing the revision of keys.
+type fakeTxn struct {
+	kv   *fakeKV
+	cmps []clientv3.Cmp
+	ops  []clientv3.Op
+}
+
+func (t *fakeTxn) If(cs ...clientv3.Cmp) clientv3.Txn {
+	t.cmps = append(t.cmps, cs...)
+	return t
+}
+
+func (t *fakeTxn) Then(ops ...clientv3.Op) clientv3.Txn {
+	t.ops = append(t.ops, ops...)
+	return t
+}
+
+func (t *fakeTxn) Else(ops ...clientv3.Op) clientv3.Txn {
+	panic("not implemented")
+}
+
+func (t *fakeTxn) Commit() (*clientv3.TxnResponse, error) {
+	for _, cmp := range t.cmps {
+		var rev int64
+		if kv, ok := t.kv.kvs[string(cmp.Key)]; ok {
+			rev = kv.ModRevision
+		}
+		if cmp.Target != pb.Compare_MOD || cmp.Result != pb.Compare_EQUAL {
+			return nil, fmt.Errorf("not implemented")
+		}
+		if rev != cmp.TargetUnion.(*pb.Compare_ModRevision).ModRevision {
+			return &clientv3.TxnResponse{Header: &pb.ResponseHeader{Revision: t.kv.rev}}, nil
+		}
+	}
+	for _, op := range t.ops {
+		switch {
+		case op.IsPut():
+			t.kv.putWithLease(string(op.KeyBytes()), string(op.ValueBytes()), reflect.ValueOf(op).FieldByName("leaseID").Int())
+		case op.IsDelete():
+			t.kv.rev++
+			delete(t.kv.kvs, string(op.KeyBytes()))
+		}
+	}
+	return &clientv3.TxnResponse{Header: &pb.ResponseHeader{Revision: t.kv.rev}, Succeeded: true}, nil
+}
+
+func (f *fakeKV) leases() map[string]int64 {
+	leases := make(map[string]int64, len(f.kvs))
+	for k, kv := range f.kvs {
+		leases[k] = kv.Lease
+	}
+	return leases
+}
+
+func (f *fakeKV) values() map[string]string {
+	values := make(map[string]string, len(f.kvs))
+	for k, kv := range f.kvs {
+		values[k] = string(kv.Value)
+	}
+	return values
+}
+
+func TestMigrate(t *testing.T) {
+	t.Run("Copy", func(t *testing.T) {
+		g := NewWithT(t)
+
+		src := newFakeKV(map[string]string{
+			"/registry/pods/default/a":       "pod-a",
+			"/registry/pods/default/b":       "pod-b",
+			"/registry/services/default/svc": "svc",
+			"/other/key":                     "ignored",
+		})
+		dst := newFakeKV(nil)
+
+		result, err := datastore.Migrate(context.Background(), src, newFakeLease(nil), dst, newFakeLease(nil))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result.Keys).To(Equal(int64(3)))
+		g.Expect(result.SourceRevision).To(Equal(src.rev))
+		g.Expect(result.TargetRevision).To(Equal(dst.rev))
+		g.Expect(dst.values()).To(Equal(map[string]string{
+			"/registry/pods/default/a":       "pod-a",
+			"/registry/pods/default/b":       "pod-b",
+			"/registry/services/default/svc": "svc",
+		}))
+	})
+
+	t.Run("SyncChanges", func(t *testing.T) {
+		g := NewWithT(t)
+
+		src := newFakeKV(map[string]string{
+			"/registry/pods/default/a": "pod-a",
+			"/registry/pods/default/b": "pod-b",
+		})
+		dst := newFakeKV(nil)
+
+		// simulate writes by kube-apiserver after the initial copy
+		gets := 0
+		src.onGet = func() {
+			gets++
+			if gets == 2 {
+				src.put("/registry/pods/default/a", "pod-a-updated")
+				src.put("/registry/pods/default/c", "pod-c")
+				delete(src.kvs, "/registry/pods/default/b")
+			}
+		}
+
+		result, err := datastore.Migrate(context.Background(), src, newFakeLease(nil), dst, newFakeLease(nil))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result.Keys).To(Equal(int64(2)))
+		g.Expect(result.SourceRevision).To(Equal(src.rev))
+		g.Expect(dst.values()).To(Equal(map[string]string{
+			"/registry/pods/default/a": "pod-a-updated",
+			"/registry/pods/default/c": "pod-c",
+		}))
+	})
+
+	t.Run("Leases", func(t *testing.T) {
+		g := NewWithT(t)
+
+		src := newFakeKV(map[string]string{"/registry/pods/default/a": "pod-a"})
+		src.putWithLease("/registry/events/default/a", "event-a", 7)
+		src.putWithLease("/registry/events/default/b", "event-b", 7)
+		src.putWithLease("/registry/events/default/expired", "expired", 8)
+		dst := newFakeKV(nil)
+		dstLease := newFakeLease(nil)
+
+		result, err := datastore.Migrate(context.Background(), src, newFakeLease(map[int64]int64{7: 3600}), dst, dstLease)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result.Keys).To(Equal(int64(3)))
+		g.Expect(dst.values()).To(HaveLen(3))
+
+		// keys that shared a lease share a new lease with the remaining TTL
+		leases := dst.leases()
+		g.Expect(leases["/registry/pods/default/a"]).To(BeZero())
+		g.Expect(leases["/registry/events/default/a"]).ToNot(BeZero())
+		g.Expect(leases["/registry/events/default/b"]).To(Equal(leases["/registry/events/default/a"]))
+		g.Expect(dstLease.ttls[leases["/registry/events/default/a"]]).To(Equal(int64(3600)))
+	})
+
+	t.Run("TargetNotEmpty", func(t *testing.T) {
+		g := NewWithT(t)
+
+		src := newFakeKV(map[string]string{"/registry/pods/default/a": "pod-a"})
+		dst := newFakeKV(map[string]string{"/registry/pods/default/b": "pod-b"})
+
+		_, err := datastore.Migrate(context.Background(), src, newFakeLease(nil), dst, newFakeLease(nil))
+		g.Expect(err).To(MatchError(ContainSubstring("not empty")))
+		g.Expect(dst.values()).To(Equal(map[string]string{"/registry/pods/default/b": "pod-b"}))
+	})
+}
+
+func TestVerifyCutover(t *testing.T) {
+	t.Run("Unchanged", func(t *testing.T) {
+		g := NewWithT(t)
+
+		src := newFakeKV(map[string]string{"/registry/pods/default/a": "pod-a"})
+		dst := newFakeKV(nil)
+		migrated, err := datastore.Migrate(context.Background(), src, newFakeLease(nil), dst, newFakeLease(nil))
+		g.Expect(err).ToNot(HaveOccurred())
+
+		// kube-apiserver writes to the target datastore after the cutover
+		dst.put("/registry/pods/default/b", "pod-b")
+
+		result, err := datastore.VerifyCutover(context.Background(), src, newFakeLease(nil), dst, newFakeLease(nil), migrated, time.Millisecond)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result).To(Equal(datastore.CutoverResult{SourceRevision: migrated.SourceRevision, Keys: 2}))
+	})
+
+	t.Run("SyncChanges", func(t *testing.T) {
+		g := NewWithT(t)
+
+		src := newFakeKV(map[string]string{
+			"/registry/pods/default/a": "pod-a",
+			"/registry/pods/default/b": "pod-b",
+			"/registry/pods/default/c": "pod-c",
+		})
+		dst := newFakeKV(nil)
+		migrated, err := datastore.Migrate(context.Background(), src, newFakeLease(nil), dst, newFakeLease(nil))
+		g.Expect(err).ToNot(HaveOccurred())
+
+		// writes to the source datastore before kube-apiserver restarted
+		src.put("/registry/pods/default/a", "pod-a-src")
+		src.putWithLease("/registry/events/default/a", "event-a", 7)
+		src.put("/registry/pods/default/c", "pod-c-src")
+		delete(src.kvs, "/registry/pods/default/b")
+		src.rev++
+
+		// writes to the target datastore after kube-apiserver restarted
+		dst.put("/registry/pods/default/c", "pod-c-dst")
+		dst.put("/registry/pods/default/d", "pod-d")
+
+		dstLease := newFakeLease(nil)
+		result, err := datastore.VerifyCutover(context.Background(), src, newFakeLease(map[int64]int64{7: 3600}), dst, dstLease, migrated, time.Millisecond)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result.SourceRevision).To(Equal(src.rev))
+		g.Expect(result.Synced).To(Equal(int64(3)))
+		g.Expect(result.Conflicts).To(Equal(int64(1)))
+		g.Expect(result.Keys).To(Equal(int64(4)))
+		g.Expect(dst.values()).To(Equal(map[string]string{
+			"/registry/pods/default/a":   "pod-a-src",
+			"/registry/pods/default/c":   "pod-c-dst",
+			"/registry/pods/default/d":   "pod-d",
+			"/registry/events/default/a": "event-a",
+		}))
+		g.Expect(dstLease.ttls[dst.leases()["/registry/events/default/a"]]).To(Equal(int64(3600)))
+	})
+
+	t.Run("SourceStillWritten", func(t *testing.T) {
+		g := NewWithT(t)
+
+		src := newFakeKV(map[string]string{"/registry/pods/default/a": "pod-a"})
+		dst := newFakeKV(nil)
+		migrated, err := datastore.Migrate(context.Background(), src, newFakeLease(nil), dst, newFakeLease(nil))
+		g.Expect(err).ToNot(HaveOccurred())
+
+		src.put("/registry/pods/default/a", "pod-a-updated")
+
+		// simulate a kube-apiserver that keeps writing to the source datastore while it is synced
+		gets := 0
+		src.onGet = func() {
+			gets++
+			if gets == 3 {
+				src.put("/registry/pods/default/b", "pod-b")
+			}
+		}
+
+		_, err = datastore.VerifyCutover(context.Background(), src, newFakeLease(nil), dst, newFakeLease(nil), migrated, time.Millisecond)
+		g.Expect(err).To(MatchError(ContainSubstring("kube-apiserver is still using it")))
+	})
+}
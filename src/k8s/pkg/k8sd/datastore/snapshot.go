@@ -0,0 +1,168 @@
+package datastore
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// SnapshotResult is the result of saving or loading a datastore snapshot.
+type SnapshotResult struct {
+	// Keys is the number of keys in the snapshot.
+	Keys int64
+	// Revision is the revision of the datastore that the snapshot matches.
+	// For Load, this is the revision of the datastore after the snapshot was loaded.
+	Revision int64
+}
+
+// snapshotEntry is a single key in a datastore snapshot.
+type snapshotEntry struct {
+	Key   []byte `json:"key"`
+	Value []byte `json:"value"`
+	// Lease is the lease ID of the key in the datastore the snapshot was taken from.
+	// Keys that share a lease share a lease again after Load.
+	Lease int64 `json:"lease,omitempty"`
+	// TTL is the remaining time to live in seconds of the lease of the key.
+	TTL int64 `json:"ttl,omitempty"`
+}
+
+// Save writes a consistent snapshot of all Kubernetes keys of the datastore to w.
+// All keys are read at the same revision, so Save can run while kube-apiserver is writing to the datastore.
+// The snapshot is a gzip compressed stream of JSON entries.
+func Save(ctx context.Context, kv clientv3.KV, lease clientv3.Lease, w io.Writer) (SnapshotResult, error) {
+	gz := gzip.NewWriter(w)
+	enc := json.NewEncoder(gz)
+
+	var (
+		result SnapshotResult
+		ttls   = make(map[int64]int64)
+		key    = keyPrefix
+		end    = clientv3.GetPrefixRangeEnd(keyPrefix)
+	)
+
+	for {
+		opts := []clientv3.OpOption{clientv3.WithRange(end), clientv3.WithLimit(pageSize)}
+		if result.Revision > 0 {
+			opts = append(opts, clientv3.WithRev(result.Revision))
+		}
+		resp, err := kv.Get(ctx, key, opts...)
+		if err != nil {
+			return SnapshotResult{}, fmt.Errorf("failed to list keys: %w", err)
+		}
+		if result.Revision == 0 {
+			result.Revision = resp.Header.Revision
+		}
+
+		for _, item := range resp.Kvs {
+			entry := snapshotEntry{Key: item.Key, Value: item.Value}
+			if item.Lease != 0 {
+				ttl, ok := ttls[item.Lease]
+				if !ok {
+					if ttl, err = leaseTTL(ctx, lease, item.Lease); err != nil {
+						return SnapshotResult{}, fmt.Errorf("failed to get lease of key %q: %w", string(item.Key), err)
+					}
+					ttls[item.Lease] = ttl
+				}
+				if ttl <= 0 {
+					// the lease expired since the keys were listed
+					continue
+				}
+				entry.Lease, entry.TTL = item.Lease, ttl
+			}
+
+			if err := enc.Encode(entry); err != nil {
+				return SnapshotResult{}, fmt.Errorf("failed to write key %q: %w", string(item.Key), err)
+			}
+			result.Keys++
+		}
+
+		if !resp.More || len(resp.Kvs) == 0 {
+			break
+		}
+		key = string(resp.Kvs[len(resp.Kvs)-1].Key) + "\x00"
+	}
+
+	if err := gz.Close(); err != nil {
+		return SnapshotResult{}, fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	return result, nil
+}
+
+// Load replaces all Kubernetes keys of the datastore with the keys of a snapshot written by Save.
+// Keys with a lease are attached to new leases with the remaining time to live recorded in the snapshot.
+// Load must not run while kube-apiserver is writing to the datastore.
+func Load(ctx context.Context, kv clientv3.KV, lease clientv3.Lease, r io.Reader) (SnapshotResult, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return SnapshotResult{}, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+	defer gz.Close()
+
+	// read the whole snapshot before the datastore is modified, so that corrupt snapshots are rejected
+	var entries []snapshotEntry
+	dec := json.NewDecoder(bufio.NewReader(gz))
+	for {
+		var entry snapshotEntry
+		if err := dec.Decode(&entry); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return SnapshotResult{}, fmt.Errorf("failed to parse snapshot: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	if _, err := kv.Delete(ctx, keyPrefix, clientv3.WithPrefix()); err != nil {
+		return SnapshotResult{}, fmt.Errorf("failed to delete existing keys: %w", err)
+	}
+
+	leases := make(map[int64]clientv3.LeaseID)
+	for _, entry := range entries {
+		var opts []clientv3.OpOption
+		if entry.Lease != 0 {
+			id, ok := leases[entry.Lease]
+			if !ok {
+				resp, err := lease.Grant(ctx, entry.TTL)
+				if err != nil {
+					return SnapshotResult{}, fmt.Errorf("failed to grant lease for key %q: %w", string(entry.Key), err)
+				}
+				id = resp.ID
+				leases[entry.Lease] = id
+			}
+			opts = append(opts, clientv3.WithLease(id))
+		}
+		if _, err := kv.Put(ctx, string(entry.Key), string(entry.Value), opts...); err != nil {
+			return SnapshotResult{}, fmt.Errorf("failed to write key %q: %w", string(entry.Key), err)
+		}
+	}
+
+	resp, err := kv.Get(ctx, keyPrefix, clientv3.WithPrefix(), clientv3.WithCountOnly())
+	if err != nil {
+		return SnapshotResult{}, fmt.Errorf("failed to count keys: %w", err)
+	}
+	if resp.Count != int64(len(entries)) {
+		return SnapshotResult{}, fmt.Errorf("key count mismatch after load, snapshot has %d keys but datastore has %d keys", len(entries), resp.Count)
+	}
+
+	return SnapshotResult{Keys: resp.Count, Revision: resp.Header.Revision}, nil
+}
+
+// leaseTTL returns the remaining time to live in seconds of a lease.
+// Kine-based datastores such as k8s-dqlite do not implement TimeToLive and use the TTL of the lease as its ID,
+// so leaseTTL falls back to the lease ID if TimeToLive fails.
+func leaseTTL(ctx context.Context, lease clientv3.Lease, id int64) (int64, error) {
+	resp, err := lease.TimeToLive(ctx, clientv3.LeaseID(id))
+	if err != nil {
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
+		return id, nil
+	}
+	return resp.TTL, nil
+}
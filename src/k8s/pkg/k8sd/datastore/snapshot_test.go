@@ -0,0 +1,123 @@
+package datastore_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/canonical/k8s/pkg/k8sd/datastore"
+	. "github.com/onsi/gomega"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// fakeLease is an in-memory clientv3.Lease that supports the operations used by datastore.Save and datastore.Load.
+type fakeLease struct {
+	clientv3.Lease
+
+	nextID int64
+	ttls   map[int64]int64
+
+	// timeToLiveErr is returned by TimeToLive, if set.
+	timeToLiveErr error
+}
+
+func newFakeLease(ttls map[int64]int64) *fakeLease {
+	if ttls == nil {
+		ttls = make(map[int64]int64)
+	}
+	return &fakeLease{nextID: 1000, ttls: ttls}
+}
+
+func (f *fakeLease) Grant(ctx context.Context, ttl int64) (*clientv3.LeaseGrantResponse, error) {
+	f.nextID++
+	f.ttls[f.nextID] = ttl
+	return &clientv3.LeaseGrantResponse{ID: clientv3.LeaseID(f.nextID), TTL: ttl}, nil
+}
+
+func (f *fakeLease) TimeToLive(ctx context.Context, id clientv3.LeaseID, opts ...clientv3.LeaseOption) (*clientv3.LeaseTimeToLiveResponse, error) {
+	if f.timeToLiveErr != nil {
+		return nil, f.timeToLiveErr
+	}
+	ttl, ok := f.ttls[int64(id)]
+	if !ok {
+		ttl = -1
+	}
+	return &clientv3.LeaseTimeToLiveResponse{ID: id, TTL: ttl}, nil
+}
+
+func TestSnapshot(t *testing.T) {
+	t.Run("SaveAndLoad", func(t *testing.T) {
+		g := NewWithT(t)
+
+		src := newFakeKV(map[string]string{
+			"/registry/pods/default/a":       "pod-a",
+			"/registry/services/default/svc": "svc",
+			"/other/key":                     "ignored",
+		})
+		src.putWithLease("/registry/events/default/a", "event-a", 7)
+		src.putWithLease("/registry/events/default/b", "event-b", 7)
+		src.putWithLease("/registry/events/default/expired", "expired", 8)
+		srcLease := newFakeLease(map[int64]int64{7: 3600})
+
+		var buf bytes.Buffer
+		saved, err := datastore.Save(context.Background(), src, srcLease, &buf)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(saved.Keys).To(Equal(int64(4)))
+		g.Expect(saved.Revision).To(Equal(src.rev))
+
+		dst := newFakeKV(map[string]string{
+			"/registry/pods/default/a":     "pod-a-updated",
+			"/registry/pods/default/stale": "stale",
+			"/other/key":                   "kept",
+		})
+		dstLease := newFakeLease(nil)
+
+		loaded, err := datastore.Load(context.Background(), dst, dstLease, &buf)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(loaded.Keys).To(Equal(int64(4)))
+		g.Expect(loaded.Revision).To(Equal(dst.rev))
+		g.Expect(dst.values()).To(Equal(map[string]string{
+			"/registry/pods/default/a":       "pod-a",
+			"/registry/services/default/svc": "svc",
+			"/registry/events/default/a":     "event-a",
+			"/registry/events/default/b":     "event-b",
+			"/other/key":                     "kept",
+		}))
+
+		// keys that shared a lease share a new lease with the remaining TTL
+		leases := dst.leases()
+		g.Expect(leases["/registry/pods/default/a"]).To(BeZero())
+		g.Expect(leases["/registry/events/default/a"]).ToNot(BeZero())
+		g.Expect(leases["/registry/events/default/b"]).To(Equal(leases["/registry/events/default/a"]))
+		g.Expect(dstLease.ttls[leases["/registry/events/default/a"]]).To(Equal(int64(3600)))
+	})
+
+	t.Run("LeaseIDAsTTL", func(t *testing.T) {
+		g := NewWithT(t)
+
+		src := newFakeKV(nil)
+		src.putWithLease("/registry/events/default/a", "event-a", 60)
+		srcLease := newFakeLease(nil)
+		srcLease.timeToLiveErr = fmt.Errorf("not supported")
+
+		var buf bytes.Buffer
+		_, err := datastore.Save(context.Background(), src, srcLease, &buf)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		dst := newFakeKV(nil)
+		dstLease := newFakeLease(nil)
+		_, err = datastore.Load(context.Background(), dst, dstLease, &buf)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(dstLease.ttls[dst.leases()["/registry/events/default/a"]]).To(Equal(int64(60)))
+	})
+
+	t.Run("CorruptSnapshot", func(t *testing.T) {
+		g := NewWithT(t)
+
+		dst := newFakeKV(map[string]string{"/registry/pods/default/a": "pod-a"})
+		_, err := datastore.Load(context.Background(), dst, newFakeLease(nil), bytes.NewBufferString("invalid"))
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(dst.values()).To(Equal(map[string]string{"/registry/pods/default/a": "pod-a"}))
+	})
+}
@@ -0,0 +1,109 @@
+package features
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/k8s/pkg/snap"
+	"github.com/canonical/k8s/pkg/utils"
+	"github.com/canonical/microcluster/v2/state"
+)
+
+// builtinFeatures are the built-in features, which are applied through Implementation.
+var builtinFeatures = []Feature{
+	{
+		Name:    Network,
+		Enabled: func(cfg types.ClusterConfig) bool { return cfg.Network.GetEnabled() },
+		Changed: func(cfg types.ClusterConfig) bool { return !cfg.Network.Empty() },
+		Apply: func(ctx context.Context, snap snap.Snap, s state.State, cfg types.ClusterConfig) (types.FeatureStatus, *types.ClusterConfig, error) {
+			status, err := Implementation.ApplyNetwork(ctx, snap, s, cfg.APIServer, cfg.Network, cfg.Annotations)
+			return status, nil, err
+		},
+	},
+	{
+		Name:         Gateway,
+		Dependencies: []types.FeatureName{Network},
+		Enabled:      func(cfg types.ClusterConfig) bool { return cfg.Gateway.GetEnabled() },
+		Changed:      func(cfg types.ClusterConfig) bool { return !cfg.Gateway.Empty() },
+		Apply: func(ctx context.Context, snap snap.Snap, _ state.State, cfg types.ClusterConfig) (types.FeatureStatus, *types.ClusterConfig, error) {
+			status, err := Implementation.ApplyGateway(ctx, snap, cfg.Gateway, cfg.Network, cfg.Annotations)
+			return status, nil, err
+		},
+	},
+	{
+		Name:         Ingress,
+		Dependencies: []types.FeatureName{Network},
+		Enabled:      func(cfg types.ClusterConfig) bool { return cfg.Ingress.GetEnabled() },
+		Changed:      func(cfg types.ClusterConfig) bool { return !cfg.Ingress.Empty() },
+		Apply: func(ctx context.Context, snap snap.Snap, _ state.State, cfg types.ClusterConfig) (types.FeatureStatus, *types.ClusterConfig, error) {
+			status, err := Implementation.ApplyIngress(ctx, snap, cfg.Ingress, cfg.Network, cfg.Annotations)
+			return status, nil, err
+		},
+	},
+	{
+		Name:         LoadBalancer,
+		Dependencies: []types.FeatureName{Network},
+		Enabled:      func(cfg types.ClusterConfig) bool { return cfg.LoadBalancer.GetEnabled() },
+		Changed:      func(cfg types.ClusterConfig) bool { return !cfg.LoadBalancer.Empty() },
+		Apply: func(ctx context.Context, snap snap.Snap, _ state.State, cfg types.ClusterConfig) (types.FeatureStatus, *types.ClusterConfig, error) {
+			status, err := Implementation.ApplyLoadBalancer(ctx, snap, cfg.LoadBalancer, cfg.Network, cfg.Annotations)
+			return status, nil, err
+		},
+	},
+	{
+		Name:    LocalStorage,
+		Enabled: func(cfg types.ClusterConfig) bool { return cfg.LocalStorage.GetEnabled() },
+		Changed: func(cfg types.ClusterConfig) bool { return !cfg.LocalStorage.Empty() },
+		Apply: func(ctx context.Context, snap snap.Snap, _ state.State, cfg types.ClusterConfig) (types.FeatureStatus, *types.ClusterConfig, error) {
+			status, err := Implementation.ApplyLocalStorage(ctx, snap, cfg.LocalStorage, cfg.Annotations)
+			return status, nil, err
+		},
+	},
+	{
+		Name:    MetricsServer,
+		Enabled: func(cfg types.ClusterConfig) bool { return cfg.MetricsServer.GetEnabled() },
+		Changed: func(cfg types.ClusterConfig) bool { return !cfg.MetricsServer.Empty() },
+		Apply: func(ctx context.Context, snap snap.Snap, _ state.State, cfg types.ClusterConfig) (types.FeatureStatus, *types.ClusterConfig, error) {
+			status, err := Implementation.ApplyMetricsServer(ctx, snap, cfg.MetricsServer, cfg.Annotations)
+			return status, nil, err
+		},
+	},
+	{
+		Name:    CertManager,
+		Enabled: func(cfg types.ClusterConfig) bool { return cfg.CertManager.GetEnabled() },
+		// NOTE: The ingress configuration is used for the ingress certificates.
+		Changed: func(cfg types.ClusterConfig) bool {
+			return !cfg.CertManager.Empty() || !cfg.Ingress.Empty() || cfg.Annotations[types.AnnotationCertManagerEnabled] == "-"
+		},
+		Apply: func(ctx context.Context, snap snap.Snap, _ state.State, cfg types.ClusterConfig) (types.FeatureStatus, *types.ClusterConfig, error) {
+			status, err := Implementation.ApplyCertManager(ctx, snap, cfg.CertManager, cfg.Ingress, cfg.Annotations)
+			return status, nil, err
+		},
+	},
+	{
+		Name:         DNS,
+		Dependencies: []types.FeatureName{Network},
+		Enabled:      func(cfg types.ClusterConfig) bool { return cfg.DNS.GetEnabled() },
+		// NOTE: The kubelet configuration contains the cluster domain.
+		Changed: func(cfg types.ClusterConfig) bool { return !cfg.DNS.Empty() || !cfg.Kubelet.Empty() },
+		Apply: func(ctx context.Context, snap snap.Snap, _ state.State, cfg types.ClusterConfig) (types.FeatureStatus, *types.ClusterConfig, error) {
+			status, dnsIP, err := Implementation.ApplyDNS(ctx, snap, cfg.DNS, cfg.Kubelet, cfg.Annotations)
+			if err != nil {
+				return status, nil, fmt.Errorf("failed to apply DNS configuration: %w", err)
+			}
+			if dnsIP == "" {
+				return status, nil, nil
+			}
+			// the kubelets of all nodes are configured with the IP address of the DNS service
+			return status, &types.ClusterConfig{Kubelet: types.Kubelet{ClusterDNS: utils.Pointer(dnsIP)}}, nil
+		},
+	},
+}
+
+func init() {
+	for _, feature := range builtinFeatures {
+		feature.Builtin = true
+		register(feature)
+	}
+}
@@ -0,0 +1,134 @@
+package cert_manager
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/canonical/k8s/pkg/client/helm"
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/k8s/pkg/snap"
+)
+
+const (
+	enabledMsg          = "enabled"
+	disabledMsg         = "disabled"
+	deleteFailedMsgTmpl = "Failed to delete cert-manager, the error was: %v"
+	deployFailedMsgTmpl = "Failed to deploy cert-manager, the error was: %v"
+)
+
+// ApplyCertManager deploys cert-manager when cfg.Enabled is true.
+// ApplyCertManager removes cert-manager when cfg.Enabled is false.
+// ApplyCertManager deploys a certificate for the default ingress TLS secret when cfg.IngressClusterIssuer is set,
+// so that the secret is issued by the ClusterIssuer instead of being created manually.
+// ApplyCertManager will always return a FeatureStatus indicating the current status of the
+// deployment.
+// ApplyCertManager returns an error if anything fails. The error is also wrapped in the .Message field of the
+// returned FeatureStatus.
+func ApplyCertManager(ctx context.Context, snap snap.Snap, cfg types.CertManager, ingress types.Ingress, _ types.Annotations) (types.FeatureStatus, error) {
+	m := snap.HelmClient()
+
+	if !cfg.GetEnabled() {
+		// The certificate must be removed before cert-manager, as the Certificate CRD is removed with it.
+		if _, err := m.Apply(ctx, chartIngressCertificate, helm.StateDeleted, nil); err != nil {
+			err = fmt.Errorf("failed to delete default ingress certificate: %w", err)
+			return types.FeatureStatus{
+				Enabled: false,
+				Version: ImageTag,
+				Message: fmt.Sprintf(deleteFailedMsgTmpl, err),
+			}, err
+		}
+		if _, err := m.Apply(ctx, ChartCertManager, helm.StateDeleted, nil); err != nil {
+			err = fmt.Errorf("failed to delete cert-manager chart: %w", err)
+			return types.FeatureStatus{
+				Enabled: false,
+				Version: ImageTag,
+				Message: fmt.Sprintf(deleteFailedMsgTmpl, err),
+			}, err
+		}
+		return types.FeatureStatus{
+			Enabled: false,
+			Version: ImageTag,
+			Message: disabledMsg,
+		}, nil
+	}
+
+	values := map[string]any{
+		"crds": map[string]any{
+			"enabled": true,
+		},
+		"image": map[string]any{
+			"repository": controllerImageRepo,
+			"tag":        ImageTag,
+		},
+		"webhook": map[string]any{
+			"image": map[string]any{
+				"repository": webhookImageRepo,
+				"tag":        ImageTag,
+			},
+		},
+		"cainjector": map[string]any{
+			"image": map[string]any{
+				"repository": cainjectorImageRepo,
+				"tag":        ImageTag,
+			},
+		},
+		"acmesolver": map[string]any{
+			"image": map[string]any{
+				"repository": acmesolverImageRepo,
+				"tag":        ImageTag,
+			},
+		},
+		"startupapicheck": map[string]any{
+			"image": map[string]any{
+				"repository": startupapicheckImageRepo,
+				"tag":        ImageTag,
+			},
+		},
+	}
+
+	if _, err := m.Apply(ctx, ChartCertManager, helm.StatePresent, values); err != nil {
+		err = fmt.Errorf("failed to install cert-manager chart: %w", err)
+		return types.FeatureStatus{
+			Enabled: false,
+			Version: ImageTag,
+			Message: fmt.Sprintf(deployFailedMsgTmpl, err),
+		}, err
+	}
+
+	if cfg.GetIngressClusterIssuer() == "" {
+		if _, err := m.Apply(ctx, chartIngressCertificate, helm.StateDeleted, nil); err != nil {
+			err = fmt.Errorf("failed to delete default ingress certificate: %w", err)
+			return types.FeatureStatus{
+				Enabled: false,
+				Version: ImageTag,
+				Message: fmt.Sprintf(deployFailedMsgTmpl, err),
+			}, err
+		}
+		return types.FeatureStatus{
+			Enabled: true,
+			Version: ImageTag,
+			Message: enabledMsg,
+		}, nil
+	}
+
+	// The certificate is issued into the secret that the ingress controller uses as default certificate.
+	// Installing it fails until the cert-manager webhook is ready, in which case the feature controller retries.
+	if _, err := m.Apply(ctx, chartIngressCertificate, helm.StatePresent, map[string]any{
+		"secretName":    ingress.GetDefaultTLSSecret(),
+		"clusterIssuer": cfg.GetIngressClusterIssuer(),
+		"dnsNames":      cfg.GetIngressDNSNames(),
+	}); err != nil {
+		err = fmt.Errorf("failed to install default ingress certificate: %w", err)
+		return types.FeatureStatus{
+			Enabled: false,
+			Version: ImageTag,
+			Message: fmt.Sprintf(deployFailedMsgTmpl, err),
+		}, err
+	}
+
+	return types.FeatureStatus{
+		Enabled: true,
+		Version: ImageTag,
+		Message: enabledMsg,
+	}, nil
+}
@@ -0,0 +1,85 @@
+package cert_manager_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/canonical/k8s/pkg/client/helm"
+	helmmock "github.com/canonical/k8s/pkg/client/helm/mock"
+	cert_manager "github.com/canonical/k8s/pkg/k8sd/features/cert-manager"
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	snapmock "github.com/canonical/k8s/pkg/snap/mock"
+	"github.com/canonical/k8s/pkg/utils"
+	. "github.com/onsi/gomega"
+)
+
+func TestApplyCertManager(t *testing.T) {
+	t.Run("Enabled", func(t *testing.T) {
+		g := NewWithT(t)
+		h := &helmmock.Mock{}
+		s := &snapmock.Snap{Mock: snapmock.Mock{HelmClient: h}}
+
+		status, err := cert_manager.ApplyCertManager(context.Background(), s, types.CertManager{Enabled: utils.Pointer(true)}, types.Ingress{}, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(status.Enabled).To(BeTrue())
+		g.Expect(status.Version).To(Equal(cert_manager.ImageTag))
+		g.Expect(h.ApplyCalledWith).To(HaveLen(2))
+		g.Expect(h.ApplyCalledWith[0].Chart).To(Equal(cert_manager.ChartCertManager))
+		g.Expect(h.ApplyCalledWith[0].State).To(Equal(helm.StatePresent))
+		g.Expect(h.ApplyCalledWith[0].Values).To(HaveKeyWithValue("crds", HaveKeyWithValue("enabled", true)))
+		g.Expect(h.ApplyCalledWith[1].Chart.Name).To(Equal("ck-ingress-certificate"))
+		g.Expect(h.ApplyCalledWith[1].State).To(Equal(helm.StateDeleted))
+	})
+
+	t.Run("IngressClusterIssuer", func(t *testing.T) {
+		g := NewWithT(t)
+		h := &helmmock.Mock{}
+		s := &snapmock.Snap{Mock: snapmock.Mock{HelmClient: h}}
+
+		cfg := types.CertManager{
+			Enabled:              utils.Pointer(true),
+			IngressClusterIssuer: utils.Pointer("letsencrypt"),
+			IngressDNSNames:      utils.Pointer([]string{"example.com", "*.example.com"}),
+		}
+		ingress := types.Ingress{Enabled: utils.Pointer(true), DefaultTLSSecret: utils.Pointer("ingress-tls")}
+
+		status, err := cert_manager.ApplyCertManager(context.Background(), s, cfg, ingress, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(status.Enabled).To(BeTrue())
+		g.Expect(h.ApplyCalledWith).To(HaveLen(2))
+		g.Expect(h.ApplyCalledWith[1].Chart.Name).To(Equal("ck-ingress-certificate"))
+		g.Expect(h.ApplyCalledWith[1].State).To(Equal(helm.StatePresent))
+		g.Expect(h.ApplyCalledWith[1].Values).To(Equal(map[string]any{
+			"secretName":    "ingress-tls",
+			"clusterIssuer": "letsencrypt",
+			"dnsNames":      []string{"example.com", "*.example.com"},
+		}))
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		g := NewWithT(t)
+		h := &helmmock.Mock{}
+		s := &snapmock.Snap{Mock: snapmock.Mock{HelmClient: h}}
+
+		status, err := cert_manager.ApplyCertManager(context.Background(), s, types.CertManager{Enabled: utils.Pointer(false)}, types.Ingress{}, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(status.Enabled).To(BeFalse())
+		g.Expect(h.ApplyCalledWith).To(HaveLen(2))
+		g.Expect(h.ApplyCalledWith[0].Chart.Name).To(Equal("ck-ingress-certificate"))
+		g.Expect(h.ApplyCalledWith[0].State).To(Equal(helm.StateDeleted))
+		g.Expect(h.ApplyCalledWith[1].Chart).To(Equal(cert_manager.ChartCertManager))
+		g.Expect(h.ApplyCalledWith[1].State).To(Equal(helm.StateDeleted))
+	})
+
+	t.Run("HelmError", func(t *testing.T) {
+		g := NewWithT(t)
+		h := &helmmock.Mock{ApplyErr: errors.New("failed to apply")}
+		s := &snapmock.Snap{Mock: snapmock.Mock{HelmClient: h}}
+
+		status, err := cert_manager.ApplyCertManager(context.Background(), s, types.CertManager{Enabled: utils.Pointer(true)}, types.Ingress{}, nil)
+		g.Expect(err).To(MatchError(ContainSubstring("failed to apply")))
+		g.Expect(status.Enabled).To(BeFalse())
+		g.Expect(status.Message).To(ContainSubstring("Failed to deploy cert-manager"))
+	})
+}
@@ -0,0 +1,41 @@
+package cert_manager
+
+import (
+	"path/filepath"
+
+	"github.com/canonical/k8s/pkg/client/helm"
+)
+
+var (
+	// ChartCertManager represents manifests to deploy cert-manager.
+	ChartCertManager = helm.InstallableChart{
+		Name:         "cert-manager",
+		Namespace:    "cert-manager",
+		ManifestPath: filepath.Join("charts", "cert-manager-v1.17.1.tgz"),
+	}
+
+	// chartIngressCertificate represents manifests to deploy the certificate for the default ingress TLS secret.
+	chartIngressCertificate = helm.InstallableChart{
+		Name:         "ck-ingress-certificate",
+		Namespace:    "kube-system",
+		ManifestPath: filepath.Join("charts", "ck-ingress-certificate"),
+	}
+
+	// controllerImageRepo is the image to use for the cert-manager controller.
+	controllerImageRepo = "quay.io/jetstack/cert-manager-controller"
+
+	// webhookImageRepo is the image to use for the cert-manager webhook.
+	webhookImageRepo = "quay.io/jetstack/cert-manager-webhook"
+
+	// cainjectorImageRepo is the image to use for the cert-manager CA injector.
+	cainjectorImageRepo = "quay.io/jetstack/cert-manager-cainjector"
+
+	// acmesolverImageRepo is the image to use for the cert-manager ACME HTTP01 solver.
+	acmesolverImageRepo = "quay.io/jetstack/cert-manager-acmesolver"
+
+	// startupapicheckImageRepo is the image to use for the cert-manager startup API check.
+	startupapicheckImageRepo = "quay.io/jetstack/cert-manager-startupapicheck"
+
+	// ImageTag is the tag to use for all cert-manager images.
+	ImageTag = "v1.17.1"
+)
@@ -0,0 +1,17 @@
+package cert_manager
+
+import (
+	"fmt"
+
+	"github.com/canonical/k8s/pkg/k8sd/images"
+)
+
+func init() {
+	images.Register(
+		fmt.Sprintf("%s:%s", controllerImageRepo, ImageTag),
+		fmt.Sprintf("%s:%s", webhookImageRepo, ImageTag),
+		fmt.Sprintf("%s:%s", cainjectorImageRepo, ImageTag),
+		fmt.Sprintf("%s:%s", acmesolverImageRepo, ImageTag),
+		fmt.Sprintf("%s:%s", startupapicheckImageRepo, ImageTag),
+	)
+}
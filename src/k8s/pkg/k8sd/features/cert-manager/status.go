@@ -0,0 +1,35 @@
+package cert_manager
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/canonical/k8s/pkg/snap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CheckCertManager checks the cert-manager deployments in the cluster.
+func CheckCertManager(ctx context.Context, snap snap.Snap) error {
+	client, err := snap.KubernetesClient(ChartCertManager.Namespace)
+	if err != nil {
+		return fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	for _, check := range []struct {
+		name      string
+		namespace string
+		labels    map[string]string
+	}{
+		{name: "cert-manager", namespace: ChartCertManager.Namespace, labels: map[string]string{"app.kubernetes.io/name": "cert-manager", "app.kubernetes.io/component": "controller"}},
+		{name: "cert-manager-webhook", namespace: ChartCertManager.Namespace, labels: map[string]string{"app.kubernetes.io/name": "webhook", "app.kubernetes.io/component": "webhook"}},
+		{name: "cert-manager-cainjector", namespace: ChartCertManager.Namespace, labels: map[string]string{"app.kubernetes.io/name": "cainjector", "app.kubernetes.io/component": "cainjector"}},
+	} {
+		if err := client.CheckForReadyPods(ctx, check.namespace, metav1.ListOptions{
+			LabelSelector: metav1.FormatLabelSelector(&metav1.LabelSelector{MatchLabels: check.labels}),
+		}); err != nil {
+			return fmt.Errorf("%v pods not yet ready: %w", check.name, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package cilium
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/canonical/k8s/pkg/client/kubernetes"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// MigrationNodeConfigName is the name of the CiliumNodeConfig that enables the CNI configuration of Cilium on the
+// nodes that are switched to Cilium.
+const MigrationNodeConfigName = "k8sd-network-migration"
+
+// MigrationValues are the Helm values of Cilium while the nodes are switched to or from another network provider.
+// Cilium runs next to the other network provider on every node: it does not manage the CNI configuration of the
+// nodes, does not enforce network policies and routes through the host, so that pods of both network providers can
+// reach each other.
+// See https://docs.cilium.io/en/stable/installation/k8s-install-migration/
+var MigrationValues = map[string]any{
+	"cni": map[string]any{
+		"customConf": true,
+		"uninstall":  false,
+		"exclusive":  false,
+	},
+	"policyEnforcementMode": "never",
+	"bpf": map[string]any{
+		"hostLegacyRouting": true,
+	},
+	"operator": map[string]any{
+		"unmanagedPodWatcher": map[string]any{
+			"restart": false,
+		},
+	},
+}
+
+var ciliumNodeConfigGVK = schema.GroupVersionKind{Group: "cilium.io", Version: "v2", Kind: "CiliumNodeConfig"}
+
+// ApplyMigrationNodeConfig creates the CiliumNodeConfig that makes the Cilium agents on the nodes with the given
+// labels write the Cilium CNI configuration, while Cilium is deployed with MigrationValues.
+// If present is false, ApplyMigrationNodeConfig deletes the CiliumNodeConfig.
+func ApplyMigrationNodeConfig(ctx context.Context, client *kubernetes.Client, nodeLabels map[string]string, present bool) error {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(ciliumNodeConfigGVK)
+	obj.SetNamespace("kube-system")
+	obj.SetName(MigrationNodeConfigName)
+
+	if !present {
+		if err := client.Client.Delete(ctx, obj); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete CiliumNodeConfig: %w", err)
+		}
+		return nil
+	}
+
+	matchLabels := make(map[string]any, len(nodeLabels))
+	for key, value := range nodeLabels {
+		matchLabels[key] = value
+	}
+	obj.Object["spec"] = map[string]any{
+		"nodeSelector": map[string]any{
+			"matchLabels": matchLabels,
+		},
+		"defaults": map[string]any{
+			"write-cni-conf-when-ready": "/host/etc/cni/net.d/05-cilium.conflist",
+			"custom-cni-conf":           "false",
+			"cni-exclusive":             "true",
+		},
+	}
+
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(ciliumNodeConfigGVK)
+	if err := client.Client.Get(ctx, ctrlclient.ObjectKeyFromObject(obj), existing); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get CiliumNodeConfig: %w", err)
+		}
+		if err := client.Client.Create(ctx, obj); err != nil {
+			return fmt.Errorf("failed to create CiliumNodeConfig: %w", err)
+		}
+		return nil
+	}
+
+	existing.Object["spec"] = obj.Object["spec"]
+	if err := client.Client.Update(ctx, existing); err != nil {
+		return fmt.Errorf("failed to update CiliumNodeConfig: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package cilium_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/canonical/k8s/pkg/client/kubernetes"
+	"github.com/canonical/k8s/pkg/k8sd/features/cilium"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestApplyMigrationNodeConfig(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	client := &kubernetes.Client{Client: ctrlfake.NewClientBuilder().Build()}
+	nodeLabels := map[string]string{"k8sd.io/network-provider": "cilium"}
+
+	get := func() (*unstructured.Unstructured, error) {
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(schema.GroupVersionKind{Group: "cilium.io", Version: "v2", Kind: "CiliumNodeConfig"})
+		err := client.Client.Get(ctx, ctrlclient.ObjectKey{Namespace: "kube-system", Name: cilium.MigrationNodeConfigName}, obj)
+		return obj, err
+	}
+
+	// applying twice updates the existing CiliumNodeConfig
+	g.Expect(cilium.ApplyMigrationNodeConfig(ctx, client, nodeLabels, true)).To(Succeed())
+	g.Expect(cilium.ApplyMigrationNodeConfig(ctx, client, nodeLabels, true)).To(Succeed())
+
+	obj, err := get()
+	g.Expect(err).ToNot(HaveOccurred())
+	matchLabels, _, err := unstructured.NestedMap(obj.Object, "spec", "nodeSelector", "matchLabels")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(matchLabels).To(Equal(map[string]any{"k8sd.io/network-provider": "cilium"}))
+	defaults, _, err := unstructured.NestedStringMap(obj.Object, "spec", "defaults")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(defaults).To(HaveKeyWithValue("custom-cni-conf", "false"))
+	g.Expect(defaults).To(HaveKeyWithValue("write-cni-conf-when-ready", "/host/etc/cni/net.d/05-cilium.conflist"))
+
+	// removing is idempotent
+	g.Expect(cilium.ApplyMigrationNodeConfig(ctx, client, nodeLabels, false)).To(Succeed())
+	g.Expect(cilium.ApplyMigrationNodeConfig(ctx, client, nodeLabels, false)).To(Succeed())
+
+	_, err = get()
+	g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+}
@@ -159,10 +159,13 @@ func ApplyNetwork(ctx context.Context, snap snap.Snap, s state.State, apiserver
 		}, err
 	}
 
+	cilium := network.Cilium
+
 	ciliumNodePortValues := map[string]any{
 		"enabled": true,
-		// kube-proxy also binds to the same port for health checks so we need to disable it
-		"enableHealthCheck": false,
+		// kube-proxy also binds to the same port for health checks so we need to disable it,
+		// unless kube-proxy is replaced by cilium
+		"enableHealthCheck": cilium.GetKubeProxyReplacement(),
 	}
 
 	if config.directRoutingDevice != "" {
@@ -239,7 +242,27 @@ func ApplyNetwork(ctx context.Context, snap snap.Snap, s state.State, apiserver
 				"enabled": true,
 			},
 		},
-		"tunnelPort": config.tunnelPort,
+		"tunnelPort":           config.tunnelPort,
+		"kubeProxyReplacement": cilium.GetKubeProxyReplacement(),
+		"bandwidthManager": map[string]any{
+			"enabled": cilium.GetBandwidthManager(),
+		},
+		"hubble": map[string]any{
+			"enabled": true,
+			"relay": map[string]any{
+				"enabled": cilium.GetHubbleRelay(),
+			},
+			"ui": map[string]any{
+				"enabled": cilium.GetHubbleUI(),
+			},
+		},
+	}
+
+	if cilium.GetEncryption() == types.CiliumEncryptionWireGuard {
+		values["encryption"] = map[string]any{
+			"enabled": true,
+			"type":    types.CiliumEncryptionWireGuard,
+		}
 	}
 
 	// If we are deploying with IPv6 only, we need to set the routing mode to native
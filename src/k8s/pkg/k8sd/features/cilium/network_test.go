@@ -264,6 +264,47 @@ func TestNetworkEnabled(t *testing.T) {
 			sctpValues := callArgs.Values["sctp"].(map[string]interface{})
 			g.Expect(sctpValues["enabled"]).To(BeTrue())
 		})
+
+		t.Run("CiliumOptions", func(t *testing.T) {
+			g := NewWithT(t)
+
+			helmM := &helmmock.Mock{}
+			snapM := &snapmock.Snap{
+				Mock: snapmock.Mock{
+					HelmClient: helmM,
+				},
+			}
+			network := types.Network{
+				Enabled: ptr.To(true),
+				PodCIDR: ptr.To("192.0.2.0/24,2001:db8::/32"),
+				Cilium: types.Cilium{
+					HubbleRelay:          ptr.To(true),
+					HubbleUI:             ptr.To(true),
+					Encryption:           ptr.To(types.CiliumEncryptionWireGuard),
+					KubeProxyReplacement: ptr.To(true),
+					BandwidthManager:     ptr.To(true),
+				},
+			}
+			apiserver := types.APIServer{
+				SecurePort: ptr.To(6443),
+			}
+
+			status, err := cilium.ApplyNetwork(context.Background(), snapM, s, apiserver, network, annotations)
+
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(status.Enabled).To(BeTrue())
+			g.Expect(helmM.ApplyCalledWith).To(HaveLen(1))
+
+			values := helmM.ApplyCalledWith[0].Values
+			g.Expect(values["kubeProxyReplacement"]).To(BeTrue())
+			g.Expect(values["nodePort"].(map[string]any)["enableHealthCheck"]).To(BeTrue())
+			g.Expect(values["bandwidthManager"].(map[string]any)["enabled"]).To(BeTrue())
+			g.Expect(values["encryption"]).To(Equal(map[string]any{"enabled": true, "type": "wireguard"}))
+
+			hubbleValues := values["hubble"].(map[string]any)
+			g.Expect(hubbleValues["relay"].(map[string]any)["enabled"]).To(BeTrue())
+			g.Expect(hubbleValues["ui"].(map[string]any)["enabled"]).To(BeTrue())
+		})
 	})
 }
 
@@ -491,4 +532,10 @@ func validateNetworkValues(g Gomega, values map[string]any, network types.Networ
 	_, exists = annotations.Get(apiv1_annotations.AnnotationSCTPEnabled)
 	sctpValues := values["sctp"].(map[string]interface{})
 	g.Expect(sctpValues["enabled"]).To(Equal(exists))
+
+	g.Expect(values["kubeProxyReplacement"]).To(Equal(network.Cilium.GetKubeProxyReplacement()))
+	g.Expect(values["bandwidthManager"].(map[string]any)["enabled"]).To(Equal(network.Cilium.GetBandwidthManager()))
+	if network.Cilium.GetEncryption() == "" {
+		g.Expect(values).ToNot(HaveKey("encryption"))
+	}
 }
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/canonical/k8s/pkg/snap"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -13,6 +14,8 @@ const (
 	EnabledMsg  = "enabled"
 )
 
+// CheckNetwork checks that the Cilium pods are ready.
+// CheckNetwork also checks the Hubble relay and UI pods, if they are enabled in the cluster.
 func CheckNetwork(ctx context.Context, snap snap.Snap) error {
 	client, err := snap.KubernetesClient("kube-system")
 	if err != nil {
@@ -23,10 +26,21 @@ func CheckNetwork(ctx context.Context, snap snap.Snap) error {
 		name      string
 		namespace string
 		labels    map[string]string
+		// deployment is the name of the deployment of optional components, which are only checked if it exists.
+		deployment string
 	}{
 		{name: "cilium-operator", namespace: "kube-system", labels: map[string]string{"io.cilium/app": "operator"}},
 		{name: "cilium", namespace: "kube-system", labels: map[string]string{"k8s-app": "cilium"}},
+		{name: "hubble-relay", namespace: "kube-system", labels: map[string]string{"k8s-app": "hubble-relay"}, deployment: "hubble-relay"},
+		{name: "hubble-ui", namespace: "kube-system", labels: map[string]string{"k8s-app": "hubble-ui"}, deployment: "hubble-ui"},
 	} {
+		if check.deployment != "" {
+			if _, err := client.AppsV1().Deployments(check.namespace).Get(ctx, check.deployment, metav1.GetOptions{}); apierrors.IsNotFound(err) {
+				continue
+			} else if err != nil {
+				return fmt.Errorf("failed to get %v deployment: %w", check.name, err)
+			}
+		}
 		if err := client.CheckForReadyPods(ctx, check.namespace, metav1.ListOptions{
 			LabelSelector: metav1.FormatLabelSelector(&metav1.LabelSelector{MatchLabels: check.labels}),
 		}); err != nil {
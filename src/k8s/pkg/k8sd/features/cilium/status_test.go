@@ -9,6 +9,7 @@ import (
 	"github.com/canonical/k8s/pkg/k8sd/features/cilium"
 	snapmock "github.com/canonical/k8s/pkg/snap/mock"
 	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/fake"
@@ -121,4 +122,73 @@ func TestCheckNetwork(t *testing.T) {
 		err := cilium.CheckNetwork(context.Background(), snapM)
 		g.Expect(err).NotTo(HaveOccurred())
 	})
+
+	t.Run("hubbleRelayNotReady", func(t *testing.T) {
+		g := NewWithT(t)
+
+		clientset := fake.NewSimpleClientset(
+			&appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "hubble-relay", Namespace: "kube-system"},
+			},
+			&corev1.PodList{
+				Items: []corev1.Pod{
+					readyPod("operator", map[string]string{"io.cilium/app": "operator"}),
+					readyPod("cilium", map[string]string{"k8s-app": "cilium"}),
+				},
+			},
+		)
+		snapM := &snapmock.Snap{
+			Mock: snapmock.Mock{
+				KubernetesClient: &kubernetes.Client{
+					Interface: clientset,
+				},
+			},
+		}
+
+		err := cilium.CheckNetwork(context.Background(), snapM)
+		g.Expect(err).To(MatchError(ContainSubstring("hubble-relay pods not yet ready")))
+	})
+
+	t.Run("hubbleRelayReady", func(t *testing.T) {
+		g := NewWithT(t)
+
+		clientset := fake.NewSimpleClientset(
+			&appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "hubble-relay", Namespace: "kube-system"},
+			},
+			&corev1.PodList{
+				Items: []corev1.Pod{
+					readyPod("operator", map[string]string{"io.cilium/app": "operator"}),
+					readyPod("cilium", map[string]string{"k8s-app": "cilium"}),
+					readyPod("hubble-relay", map[string]string{"k8s-app": "hubble-relay"}),
+				},
+			},
+		)
+		snapM := &snapmock.Snap{
+			Mock: snapmock.Mock{
+				KubernetesClient: &kubernetes.Client{
+					Interface: clientset,
+				},
+			},
+		}
+
+		err := cilium.CheckNetwork(context.Background(), snapM)
+		g.Expect(err).NotTo(HaveOccurred())
+	})
+}
+
+func readyPod(name string, labels map[string]string) corev1.Pod {
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "kube-system",
+			Labels:    labels,
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			Conditions: []corev1.PodCondition{
+				{Type: corev1.PodReady, Status: corev1.ConditionTrue},
+			},
+		},
+	}
 }
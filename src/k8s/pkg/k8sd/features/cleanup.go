@@ -7,13 +7,28 @@ import (
 )
 
 type CleanupInterface interface {
+	// CleanupNetwork removes the node-local state of the network provider that the node was switched to.
 	CleanupNetwork(context.Context, snap.Snap) error
+	// CleanupNetworkProvider removes the node-local state of the named network provider.
+	CleanupNetworkProvider(context.Context, snap.Snap, string) error
+	// CleanupNetworkProviderConfig removes the CNI configuration of the named network provider from the node.
+	CleanupNetworkProviderConfig(context.Context, snap.Snap, string) error
 }
 
 type cleanup struct {
-	cleanupNetwork func(context.Context, snap.Snap) error
+	cleanupNetwork               func(context.Context, snap.Snap) error
+	cleanupNetworkProvider       func(context.Context, snap.Snap, string) error
+	cleanupNetworkProviderConfig func(context.Context, snap.Snap, string) error
 }
 
 func (c *cleanup) CleanupNetwork(ctx context.Context, snap snap.Snap) error {
 	return c.cleanupNetwork(ctx, snap)
 }
+
+func (c *cleanup) CleanupNetworkProvider(ctx context.Context, snap snap.Snap, provider string) error {
+	return c.cleanupNetworkProvider(ctx, snap, provider)
+}
+
+func (c *cleanup) CleanupNetworkProviderConfig(ctx context.Context, snap snap.Snap, provider string) error {
+	return c.cleanupNetworkProviderConfig(ctx, snap, provider)
+}
@@ -20,7 +20,8 @@ const (
 // ApplyDNS manages the deployment of CoreDNS, with customization options from dns and kubelet, which are retrieved from the cluster configuration.
 // ApplyDNS will uninstall CoreDNS from the cluster if dns.Enabled is false.
 // ApplyDNS will install or refresh CoreDNS if dns.Enabled is true.
-// ApplyDNS will return the ClusterIP address of the coredns service, if successful.
+// ApplyDNS will return the comma-separated ClusterIP addresses of the coredns service, if successful.
+// On dual-stack clusters, the coredns service gets one ClusterIP per IP family.
 // ApplyDNS will always return a FeatureStatus indicating the current status of the
 // deployment.
 // ApplyDNS returns an error if anything fails. The error is also wrapped in the .Message field of the
@@ -44,15 +45,24 @@ func ApplyDNS(ctx context.Context, snap snap.Snap, dns types.DNS, kubelet types.
 		}, "", nil
 	}
 
+	service := map[string]any{
+		"name":           "coredns",
+		"ipFamilyPolicy": "PreferDualStack",
+	}
+	if clusterDNS := kubelet.GetClusterDNS(); clusterDNS != "" {
+		clusterIPs := strings.Split(clusterDNS, ",")
+		service["clusterIP"] = clusterIPs[0]
+		if len(clusterIPs) > 1 {
+			service["clusterIPs"] = clusterIPs
+		}
+	}
+
 	values := map[string]any{
 		"image": map[string]any{
 			"repository": imageRepo,
 			"tag":        ImageTag,
 		},
-		"service": map[string]any{
-			"name":      "coredns",
-			"clusterIP": kubelet.GetClusterDNS(),
-		},
+		"service": service,
 		"serviceAccount": map[string]any{
 			"create": true,
 			"name":   "coredns",
@@ -113,7 +123,7 @@ func ApplyDNS(ctx context.Context, snap snap.Snap, dns types.DNS, kubelet types.
 			Message: fmt.Sprintf(deployFailedMsgTmpl, err),
 		}, "", err
 	}
-	dnsIP, err := client.GetServiceClusterIP(ctx, "coredns", "kube-system")
+	dnsIPs, err := client.GetServiceClusterIPs(ctx, "coredns", "kube-system")
 	if err != nil {
 		err = fmt.Errorf("failed to retrieve the coredns service: %w", err)
 		return types.FeatureStatus{
@@ -123,6 +133,7 @@ func ApplyDNS(ctx context.Context, snap snap.Snap, dns types.DNS, kubelet types.
 		}, "", err
 	}
 
+	dnsIP := strings.Join(dnsIPs, ",")
 	return types.FeatureStatus{
 		Enabled: true,
 		Version: ImageTag,
@@ -183,11 +183,60 @@ func TestEnabled(t *testing.T) {
 		g.Expect(callArgs.State).To(Equal(helm.StatePresent))
 		validateValues(g, callArgs.Values, dns, kubelet)
 	})
+	t.Run("DualStack", func(t *testing.T) {
+		g := NewWithT(t)
+
+		helmM := &helmmock.Mock{}
+		corednsService := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "coredns",
+				Namespace: "kube-system",
+			},
+			Spec: corev1.ServiceSpec{
+				ClusterIP:  "10.96.0.10",
+				ClusterIPs: []string{"10.96.0.10", "fd98::a"},
+			},
+		}
+		clientset := fake.NewSimpleClientset(corednsService)
+		snapM := &snapmock.Snap{
+			Mock: snapmock.Mock{
+				HelmClient:       helmM,
+				KubernetesClient: &kubernetes.Client{Interface: clientset},
+			},
+		}
+		dns := types.DNS{
+			Enabled: ptr.To(true),
+		}
+		kubelet := types.Kubelet{
+			ClusterDNS: ptr.To("10.96.0.10,fd98::a"),
+		}
+
+		status, str, err := coredns.ApplyDNS(context.Background(), snapM, dns, kubelet, nil)
+
+		g.Expect(err).To(Not(HaveOccurred()))
+		g.Expect(str).To(Equal("10.96.0.10,fd98::a"))
+		g.Expect(status.Message).To(ContainSubstring("enabled at 10.96.0.10,fd98::a"))
+		g.Expect(status.Enabled).To(BeTrue())
+		g.Expect(helmM.ApplyCalledWith).To(HaveLen(1))
+
+		callArgs := helmM.ApplyCalledWith[0]
+		g.Expect(callArgs.State).To(Equal(helm.StatePresent))
+		validateValues(g, callArgs.Values, dns, kubelet)
+	})
 }
 
 func validateValues(g Gomega, values map[string]any, dns types.DNS, kubelet types.Kubelet) {
 	service := values["service"].(map[string]any)
-	g.Expect(service["clusterIP"]).To(Equal(kubelet.GetClusterDNS()))
+	g.Expect(service["ipFamilyPolicy"]).To(Equal("PreferDualStack"))
+	if clusterDNS := kubelet.GetClusterDNS(); clusterDNS != "" {
+		clusterIPs := strings.Split(clusterDNS, ",")
+		g.Expect(service["clusterIP"]).To(Equal(clusterIPs[0]))
+		if len(clusterIPs) > 1 {
+			g.Expect(service["clusterIPs"]).To(Equal(clusterIPs))
+		}
+	} else {
+		g.Expect(service).ToNot(HaveKey("clusterIP"))
+	}
 
 	servers := values["servers"].([]map[string]any)
 	plugins := servers[0]["plugins"].([]map[string]any)
@@ -0,0 +1,70 @@
+package features
+
+import (
+	"slices"
+
+	"github.com/canonical/k8s/pkg/k8sd/types"
+)
+
+// DependenciesOf returns the features that the given feature depends on.
+// A feature is only deployed once the features it depends on report enabled,
+// and the features it depends on are only removed once it reports disabled.
+func DependenciesOf(name types.FeatureName) []types.FeatureName {
+	if feature, ok := Get(name); ok {
+		return slices.Clone(feature.Dependencies)
+	}
+	return nil
+}
+
+// DependentsOf returns the features that depend on the given feature.
+func DependentsOf(name types.FeatureName) []types.FeatureName {
+	var dependents []types.FeatureName
+	for _, feature := range Features() {
+		if slices.Contains(DependenciesOf(feature), name) {
+			dependents = append(dependents, feature)
+		}
+	}
+	return dependents
+}
+
+// WaitingFor returns the feature that blocks the reconciliation of the given feature, if any.
+// An enabled feature waits for the enabled features it depends on to report enabled.
+// A disabled feature waits for the disabled features that depend on it to report disabled, so that
+// features are removed in the reverse order of their deployment.
+// Features that are disabled in the cluster configuration are managed externally, so they never block
+// their dependents.
+// WaitingFor returns an empty string if the feature can be reconciled.
+func WaitingFor(cfg types.ClusterConfig, statuses map[types.FeatureName]types.FeatureStatus, name types.FeatureName) types.FeatureName {
+	if Enabled(cfg, name) {
+		for _, dependency := range DependenciesOf(name) {
+			if Enabled(cfg, dependency) && !statuses[dependency].Enabled {
+				return dependency
+			}
+		}
+		return ""
+	}
+
+	for _, dependent := range DependentsOf(name) {
+		if !Enabled(cfg, dependent) && statuses[dependent].Enabled {
+			return dependent
+		}
+	}
+	return ""
+}
+
+// dependsOn returns true if name is reachable from the given dependencies in the dependency graph.
+func dependsOn(dependencies []types.FeatureName, name types.FeatureName, visited map[types.FeatureName]struct{}) bool {
+	for _, dependency := range dependencies {
+		if dependency == name {
+			return true
+		}
+		if _, ok := visited[dependency]; ok {
+			continue
+		}
+		visited[dependency] = struct{}{}
+		if dependsOn(DependenciesOf(dependency), name, visited) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,115 @@
+package features_test
+
+import (
+	"testing"
+
+	"github.com/canonical/k8s/pkg/k8sd/features"
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/k8s/pkg/utils"
+	. "github.com/onsi/gomega"
+)
+
+func TestDependencies(t *testing.T) {
+	g := NewWithT(t)
+
+	plugin := testPlugin("test-dependencies")
+	plugin.Dependencies = []types.FeatureName{features.Gateway}
+	features.Register(plugin)
+
+	g.Expect(features.DependenciesOf(features.Gateway)).To(ConsistOf(features.Network))
+	g.Expect(features.DependenciesOf(features.Network)).To(BeEmpty())
+	g.Expect(features.DependenciesOf(plugin.Name)).To(ConsistOf(features.Gateway))
+
+	g.Expect(features.DependentsOf(features.Network)).To(ConsistOf(features.Gateway, features.Ingress, features.LoadBalancer, features.DNS))
+	g.Expect(features.DependentsOf(features.Gateway)).To(ConsistOf(plugin.Name))
+	g.Expect(features.DependentsOf(features.MetricsServer)).To(BeEmpty())
+
+	t.Run("Cycle", func(t *testing.T) {
+		g := NewWithT(t)
+
+		self := testPlugin("test-dependencies-self")
+		self.Dependencies = []types.FeatureName{self.Name}
+		g.Expect(func() { features.Register(self) }).To(Panic())
+
+		a := testPlugin("test-dependencies-a")
+		a.Dependencies = []types.FeatureName{"test-dependencies-b"}
+		features.Register(a)
+
+		b := testPlugin("test-dependencies-b")
+		b.Dependencies = []types.FeatureName{a.Name}
+		g.Expect(func() { features.Register(b) }).To(Panic())
+	})
+}
+
+func TestWaitingFor(t *testing.T) {
+	enabled := types.FeatureStatus{Enabled: true}
+	disabled := types.FeatureStatus{Enabled: false}
+
+	for _, tc := range []struct {
+		name       string
+		cfg        types.ClusterConfig
+		statuses   map[types.FeatureName]types.FeatureStatus
+		feature    types.FeatureName
+		waitingFor types.FeatureName
+	}{
+		{
+			name: "EnabledWaitsForDependency",
+			cfg: types.ClusterConfig{
+				Network: types.Network{Enabled: utils.Pointer(true)},
+				Gateway: types.Gateway{Enabled: utils.Pointer(true)},
+			},
+			statuses:   map[types.FeatureName]types.FeatureStatus{features.Network: disabled},
+			feature:    features.Gateway,
+			waitingFor: features.Network,
+		},
+		{
+			name: "EnabledWithDependencyEnabled",
+			cfg: types.ClusterConfig{
+				Network: types.Network{Enabled: utils.Pointer(true)},
+				Gateway: types.Gateway{Enabled: utils.Pointer(true)},
+			},
+			statuses: map[types.FeatureName]types.FeatureStatus{features.Network: enabled},
+			feature:  features.Gateway,
+		},
+		{
+			name: "EnabledWithExternalDependency",
+			cfg: types.ClusterConfig{
+				Network: types.Network{Enabled: utils.Pointer(false)},
+				DNS:     types.DNS{Enabled: utils.Pointer(true)},
+			},
+			feature: features.DNS,
+		},
+		{
+			name: "DisabledWaitsForDependent",
+			cfg: types.ClusterConfig{
+				Network: types.Network{Enabled: utils.Pointer(false)},
+				Gateway: types.Gateway{Enabled: utils.Pointer(false)},
+			},
+			statuses:   map[types.FeatureName]types.FeatureStatus{features.Network: enabled, features.Gateway: enabled},
+			feature:    features.Network,
+			waitingFor: features.Gateway,
+		},
+		{
+			name: "DisabledWithDependentsDisabled",
+			cfg: types.ClusterConfig{
+				Network: types.Network{Enabled: utils.Pointer(false)},
+			},
+			statuses: map[types.FeatureName]types.FeatureStatus{features.Network: enabled, features.Gateway: disabled},
+			feature:  features.Network,
+		},
+		{
+			name: "DisabledWithDependentEnabled",
+			cfg: types.ClusterConfig{
+				Network:      types.Network{Enabled: utils.Pointer(false)},
+				LoadBalancer: types.LoadBalancer{Enabled: utils.Pointer(true)},
+			},
+			statuses: map[types.FeatureName]types.FeatureStatus{features.Network: enabled, features.LoadBalancer: enabled},
+			feature:  features.Network,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(features.WaitingFor(tc.cfg, tc.statuses, tc.feature)).To(Equal(tc.waitingFor))
+		})
+	}
+}
@@ -1,35 +1,49 @@
 package features
 
 import (
+	cert_manager "github.com/canonical/k8s/pkg/k8sd/features/cert-manager"
 	"github.com/canonical/k8s/pkg/k8sd/features/cilium"
 	"github.com/canonical/k8s/pkg/k8sd/features/coredns"
 	"github.com/canonical/k8s/pkg/k8sd/features/localpv"
 	"github.com/canonical/k8s/pkg/k8sd/features/metallb"
 	metrics_server "github.com/canonical/k8s/pkg/k8sd/features/metrics-server"
+	"github.com/canonical/k8s/pkg/k8sd/types"
 )
 
 // Default implements the Canonical Kubernetes built-in features.
-// Cilium is used for networking (network + ingress + gateway).
+// Cilium is used for ingress and gateway. The network provider is Cilium (default) or Calico, see networkProviders.
 // MetalLB is used for LoadBalancer.
 // CoreDNS is used for DNS.
 // MetricsServer is used for metrics-server.
 // LocalPV Rawfile CSI is used for local-storage.
+// cert-manager is used for cert-manager.
 var Implementation Interface = &implementation{
 	applyDNS:           coredns.ApplyDNS,
-	applyNetwork:       cilium.ApplyNetwork,
+	applyNetwork:       applyNetwork,
 	applyLoadBalancer:  metallb.ApplyLoadBalancer,
 	applyIngress:       cilium.ApplyIngress,
 	applyGateway:       cilium.ApplyGateway,
 	applyMetricsServer: metrics_server.ApplyMetricsServer,
 	applyLocalStorage:  localpv.ApplyLocalStorage,
+	applyCertManager:   cert_manager.ApplyCertManager,
 }
 
 // StatusChecks implements the Canonical Kubernetes built-in feature status checks.
 var StatusChecks StatusInterface = &statusChecks{
-	checkNetwork: cilium.CheckNetwork,
-	checkDNS:     coredns.CheckDNS,
+	checkNetwork:     checkNetwork,
+	checkDNS:         coredns.CheckDNS,
+	checkCertManager: cert_manager.CheckCertManager,
 }
 
 var Cleanup CleanupInterface = &cleanup{
-	cleanupNetwork: cilium.CleanupNetwork,
+	cleanupNetwork:               cleanupNetwork,
+	cleanupNetworkProvider:       cleanupNetworkProvider,
+	cleanupNetworkProviderConfig: cleanupNetworkProviderConfig,
+}
+
+func init() {
+	types.DefaultNetworkImplementation = types.NetworkImplementation{
+		DefaultProvider: types.NetworkProviderCilium,
+		IngressProvider: types.NetworkProviderCilium,
+	}
 }
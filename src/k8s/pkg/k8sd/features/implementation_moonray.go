@@ -3,33 +3,46 @@
 package features
 
 import (
-	"github.com/canonical/k8s/pkg/k8sd/features/calico"
+	cert_manager "github.com/canonical/k8s/pkg/k8sd/features/cert-manager"
 	"github.com/canonical/k8s/pkg/k8sd/features/contour"
 	"github.com/canonical/k8s/pkg/k8sd/features/coredns"
 	"github.com/canonical/k8s/pkg/k8sd/features/localpv"
 	"github.com/canonical/k8s/pkg/k8sd/features/metallb"
 	metrics_server "github.com/canonical/k8s/pkg/k8sd/features/metrics-server"
+	"github.com/canonical/k8s/pkg/k8sd/types"
 )
 
 // Implementation contains the moonray features for Canonical Kubernetes.
+// The network provider is Calico (default) or Cilium, see networkProviders.
 // TODO: Replace default by moonray.
 var Implementation Interface = &implementation{
 	applyDNS:           coredns.ApplyDNS,
-	applyNetwork:       calico.ApplyNetwork,
+	applyNetwork:       applyNetwork,
 	applyLoadBalancer:  metallb.ApplyLoadBalancer,
 	applyIngress:       contour.ApplyIngress,
 	applyGateway:       contour.ApplyGateway,
 	applyMetricsServer: metrics_server.ApplyMetricsServer,
 	applyLocalStorage:  localpv.ApplyLocalStorage,
+	applyCertManager:   cert_manager.ApplyCertManager,
 }
 
 // StatusChecks implements the Canonical Kubernetes moonray feature status checks.
 // TODO: Replace default by moonray.
 var StatusChecks StatusInterface = &statusChecks{
-	checkNetwork: calico.CheckNetwork,
-	checkDNS:     coredns.CheckDNS,
+	checkNetwork:     checkNetwork,
+	checkDNS:         coredns.CheckDNS,
+	checkCertManager: cert_manager.CheckCertManager,
 }
 
 var Cleanup CleanupInterface = &cleanup{
-	cleanupNetwork: calico.CleanupNetwork,
+	cleanupNetwork:               cleanupNetwork,
+	cleanupNetworkProvider:       cleanupNetworkProvider,
+	cleanupNetworkProviderConfig: cleanupNetworkProviderConfig,
+}
+
+func init() {
+	// Ingress and gateway are implemented by Contour and work with any network provider.
+	types.DefaultNetworkImplementation = types.NetworkImplementation{
+		DefaultProvider: types.NetworkProviderCalico,
+	}
 }
@@ -24,6 +24,8 @@ type Interface interface {
 	ApplyMetricsServer(context.Context, snap.Snap, types.MetricsServer, types.Annotations) (types.FeatureStatus, error)
 	// ApplyLocalStorage is used to configure the Local Storage feature on Canonical Kubernetes.
 	ApplyLocalStorage(context.Context, snap.Snap, types.LocalStorage, types.Annotations) (types.FeatureStatus, error)
+	// ApplyCertManager is used to configure the cert-manager feature on Canonical Kubernetes.
+	ApplyCertManager(context.Context, snap.Snap, types.CertManager, types.Ingress, types.Annotations) (types.FeatureStatus, error)
 }
 
 // implementation implements Interface.
@@ -35,6 +37,7 @@ type implementation struct {
 	applyGateway       func(context.Context, snap.Snap, types.Gateway, types.Network, types.Annotations) (types.FeatureStatus, error)
 	applyMetricsServer func(context.Context, snap.Snap, types.MetricsServer, types.Annotations) (types.FeatureStatus, error)
 	applyLocalStorage  func(context.Context, snap.Snap, types.LocalStorage, types.Annotations) (types.FeatureStatus, error)
+	applyCertManager   func(context.Context, snap.Snap, types.CertManager, types.Ingress, types.Annotations) (types.FeatureStatus, error)
 }
 
 func (i *implementation) ApplyDNS(ctx context.Context, snap snap.Snap, dns types.DNS, kubelet types.Kubelet, annotations types.Annotations) (types.FeatureStatus, string, error) {
@@ -64,3 +67,7 @@ func (i *implementation) ApplyMetricsServer(ctx context.Context, snap snap.Snap,
 func (i *implementation) ApplyLocalStorage(ctx context.Context, snap snap.Snap, cfg types.LocalStorage, annotations types.Annotations) (types.FeatureStatus, error) {
 	return i.applyLocalStorage(ctx, snap, cfg, annotations)
 }
+
+func (i *implementation) ApplyCertManager(ctx context.Context, snap snap.Snap, cfg types.CertManager, ingress types.Ingress, annotations types.Annotations) (types.FeatureStatus, error) {
+	return i.applyCertManager(ctx, snap, cfg, ingress, annotations)
+}
@@ -14,6 +14,14 @@ var (
 		ManifestPath: filepath.Join("charts", "metrics-server-3.12.2.tgz"),
 	}
 
+	// chartCertificateAlerts represents manifests to deploy the certificate expiry alerts.
+	// The alerts are only deployed if the Prometheus Operator CRDs are installed.
+	chartCertificateAlerts = helm.InstallableChart{
+		Name:         "ck-certificate-alerts",
+		Namespace:    "kube-system",
+		ManifestPath: filepath.Join("charts", "ck-certificate-alerts"),
+	}
+
 	// imageRepo is the image to use for metrics-server.
 	imageRepo = "ghcr.io/canonical/metrics-server"
 
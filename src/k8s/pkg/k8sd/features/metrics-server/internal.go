@@ -1,16 +1,25 @@
 package metrics_server
 
 import (
+	"fmt"
+
 	apiv1_annotations "github.com/canonical/k8s-snap-api/api/v1/annotations/metrics-server"
 	"github.com/canonical/k8s/pkg/k8sd/types"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
+// AnnotationCertificateAlertsLabels are extra labels for the PrometheusRule with the certificate expiry alerts,
+// e.g. "release=kube-prometheus-stack" to match the rule selector of the Prometheus instance.
+const AnnotationCertificateAlertsLabels = "k8sd/v1alpha1/metrics-server/certificate-alerts-labels"
+
 type config struct {
 	imageRepo string
 	imageTag  string
+
+	certificateAlertsLabels map[string]string
 }
 
-func internalConfig(annotations types.Annotations) config {
+func internalConfig(annotations types.Annotations) (config, error) {
 	config := config{
 		imageRepo: imageRepo,
 		imageTag:  imageTag,
@@ -22,6 +31,13 @@ func internalConfig(annotations types.Annotations) config {
 	if v, ok := annotations.Get(apiv1_annotations.AnnotationImageTag); ok {
 		config.imageTag = v
 	}
+	if v, ok := annotations.Get(AnnotationCertificateAlertsLabels); ok {
+		alertsLabels, err := labels.ConvertSelectorToLabelsMap(v)
+		if err != nil {
+			return config, fmt.Errorf("failed to parse %s: %w", AnnotationCertificateAlertsLabels, err)
+		}
+		config.certificateAlertsLabels = alertsLabels
+	}
 
-	return config
+	return config, nil
 }
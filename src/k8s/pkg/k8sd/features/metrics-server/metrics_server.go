@@ -18,6 +18,9 @@ const (
 
 // ApplyMetricsServer deploys metrics-server when cfg.Enabled is true.
 // ApplyMetricsServer removes metrics-server when cfg.Enabled is false.
+// ApplyMetricsServer also deploys a PrometheusRule with alerts for expiring certificates, if the Prometheus Operator
+// CRDs are installed. The presence of the CRDs is part of the chart values, so the rule is deployed once the
+// feature is reconciled after the CRDs were installed.
 // ApplyMetricsServer will always return a FeatureStatus indicating the current status of the
 // deployment.
 // ApplyMetricsServer returns an error if anything fails. The error is also wrapped in the .Message field of the
@@ -25,7 +28,39 @@ const (
 func ApplyMetricsServer(ctx context.Context, snap snap.Snap, cfg types.MetricsServer, annotations types.Annotations) (types.FeatureStatus, error) {
 	m := snap.HelmClient()
 
-	config := internalConfig(annotations)
+	if !cfg.GetEnabled() {
+		if _, err := m.Apply(ctx, chart, helm.StateDeleted, nil); err != nil {
+			err = fmt.Errorf("failed to delete metrics server chart: %w", err)
+			return types.FeatureStatus{
+				Enabled: false,
+				Version: imageTag,
+				Message: fmt.Sprintf(deleteFailedMsgTmpl, err),
+			}, err
+		}
+		if _, err := m.Apply(ctx, chartCertificateAlerts, helm.StateDeleted, nil); err != nil {
+			err = fmt.Errorf("failed to delete certificate alerts chart: %w", err)
+			return types.FeatureStatus{
+				Enabled: false,
+				Version: imageTag,
+				Message: fmt.Sprintf(deleteFailedMsgTmpl, err),
+			}, err
+		}
+		return types.FeatureStatus{
+			Enabled: false,
+			Version: imageTag,
+			Message: disabledMsg,
+		}, nil
+	}
+
+	config, err := internalConfig(annotations)
+	if err != nil {
+		err = fmt.Errorf("failed to parse annotations: %w", err)
+		return types.FeatureStatus{
+			Enabled: false,
+			Version: imageTag,
+			Message: fmt.Sprintf(deployFailedMsgTmpl, err),
+		}, err
+	}
 
 	values := map[string]any{
 		"image": map[string]any{
@@ -38,36 +73,53 @@ func ApplyMetricsServer(ctx context.Context, snap snap.Snap, cfg types.MetricsSe
 		},
 	}
 
-	_, err := m.Apply(ctx, chart, helm.StatePresentOrDeleted(cfg.GetEnabled()), values)
+	if _, err := m.Apply(ctx, chart, helm.StatePresent, values); err != nil {
+		err = fmt.Errorf("failed to install metrics server chart: %w", err)
+		return types.FeatureStatus{
+			Enabled: false,
+			Version: imageTag,
+			Message: fmt.Sprintf(deployFailedMsgTmpl, err),
+		}, err
+	}
+
+	crdInstalled, err := PrometheusRuleCRDInstalled(ctx, snap)
 	if err != nil {
-		if cfg.GetEnabled() {
-			err = fmt.Errorf("failed to install metrics server chart: %w", err)
-			return types.FeatureStatus{
-				Enabled: false,
-				Version: imageTag,
-				Message: fmt.Sprintf(deployFailedMsgTmpl, err),
-			}, err
-		} else {
-			err = fmt.Errorf("failed to delete metrics server chart: %w", err)
-			return types.FeatureStatus{
-				Enabled: false,
-				Version: imageTag,
-				Message: fmt.Sprintf(deleteFailedMsgTmpl, err),
-			}, err
-		}
-	} else {
-		if cfg.GetEnabled() {
-			return types.FeatureStatus{
-				Enabled: true,
-				Version: imageTag,
-				Message: enabledMsg,
-			}, nil
-		} else {
-			return types.FeatureStatus{
-				Enabled: false,
-				Version: imageTag,
-				Message: disabledMsg,
-			}, nil
-		}
+		err = fmt.Errorf("failed to check for the PrometheusRule CRD: %w", err)
+		return types.FeatureStatus{
+			Enabled: false,
+			Version: imageTag,
+			Message: fmt.Sprintf(deployFailedMsgTmpl, err),
+		}, err
+	}
+
+	if _, err := m.Apply(ctx, chartCertificateAlerts, helm.StatePresent, map[string]any{
+		"labels":            config.certificateAlertsLabels,
+		"prometheusRuleCRD": crdInstalled,
+	}); err != nil {
+		err = fmt.Errorf("failed to install certificate alerts chart: %w", err)
+		return types.FeatureStatus{
+			Enabled: false,
+			Version: imageTag,
+			Message: fmt.Sprintf(deployFailedMsgTmpl, err),
+		}, err
+	}
+
+	return types.FeatureStatus{
+		Enabled: true,
+		Version: imageTag,
+		Message: enabledMsg,
+	}, nil
+}
+
+// PrometheusRuleCRDInstalled checks if the PrometheusRule CRD of the Prometheus Operator is installed in the cluster.
+func PrometheusRuleCRDInstalled(ctx context.Context, snap snap.Snap) (bool, error) {
+	client, err := snap.KubernetesClient("")
+	if err != nil {
+		return false, fmt.Errorf("failed to create Kubernetes client: %w", err)
+	}
+	installed, err := client.HasResource("monitoring.coreos.com/v1", "prometheusrules")
+	if err != nil {
+		return false, fmt.Errorf("failed to list monitoring.coreos.com/v1 resources: %w", err)
 	}
+	return installed, nil
 }
@@ -8,11 +8,15 @@ import (
 	apiv1_annotations "github.com/canonical/k8s-snap-api/api/v1/annotations/metrics-server"
 	"github.com/canonical/k8s/pkg/client/helm"
 	helmmock "github.com/canonical/k8s/pkg/client/helm/mock"
+	"github.com/canonical/k8s/pkg/client/kubernetes"
 	metrics_server "github.com/canonical/k8s/pkg/k8sd/features/metrics-server"
 	"github.com/canonical/k8s/pkg/k8sd/types"
 	snapmock "github.com/canonical/k8s/pkg/snap/mock"
 	"github.com/canonical/k8s/pkg/utils"
 	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
 )
 
 func TestApplyMetricsServer(t *testing.T) {
@@ -63,7 +67,8 @@ func TestApplyMetricsServer(t *testing.T) {
 			}
 			s := &snapmock.Snap{
 				Mock: snapmock.Mock{
-					HelmClient: h,
+					HelmClient:       h,
+					KubernetesClient: &kubernetes.Client{Interface: fake.NewSimpleClientset()},
 				},
 			}
 
@@ -74,11 +79,21 @@ func TestApplyMetricsServer(t *testing.T) {
 				g.Expect(err).To(HaveOccurred())
 			}
 
-			g.Expect(h.ApplyCalledWith).To(ConsistOf(SatisfyAll(
+			g.Expect(h.ApplyCalledWith[0]).To(SatisfyAll(
 				HaveField("Chart.Name", Equal("metrics-server")),
 				HaveField("Chart.Namespace", Equal("kube-system")),
 				HaveField("State", Equal(tc.expectState)),
-			)))
+			))
+			if tc.helmError == nil {
+				g.Expect(h.ApplyCalledWith).To(HaveLen(2))
+				g.Expect(h.ApplyCalledWith[1]).To(SatisfyAll(
+					HaveField("Chart.Name", Equal("ck-certificate-alerts")),
+					HaveField("Chart.Namespace", Equal("kube-system")),
+					HaveField("State", Equal(tc.expectState)),
+				))
+			} else {
+				g.Expect(h.ApplyCalledWith).To(HaveLen(1))
+			}
 			switch {
 			case errors.Is(tc.helmError, helmErr):
 				g.Expect(status.Message).To(ContainSubstring(helmErr.Error()))
@@ -95,7 +110,8 @@ func TestApplyMetricsServer(t *testing.T) {
 		h := &helmmock.Mock{}
 		s := &snapmock.Snap{
 			Mock: snapmock.Mock{
-				HelmClient: h,
+				HelmClient:       h,
+				KubernetesClient: &kubernetes.Client{Interface: fake.NewSimpleClientset()},
 			},
 		}
 
@@ -109,10 +125,89 @@ func TestApplyMetricsServer(t *testing.T) {
 
 		status, err := metrics_server.ApplyMetricsServer(context.Background(), s, cfg, annotations)
 		g.Expect(err).To(Not(HaveOccurred()))
-		g.Expect(h.ApplyCalledWith).To(ConsistOf(HaveField("Values", HaveKeyWithValue("image", SatisfyAll(
+		g.Expect(h.ApplyCalledWith).To(ContainElement(HaveField("Values", HaveKeyWithValue("image", SatisfyAll(
 			HaveKeyWithValue("repository", "custom-image"),
 			HaveKeyWithValue("tag", "custom-tag"),
 		)))))
 		g.Expect(status.Message).To(Equal("enabled"))
 	})
+
+	t.Run("CertificateAlertsLabels", func(t *testing.T) {
+		g := NewWithT(t)
+		h := &helmmock.Mock{}
+		s := &snapmock.Snap{
+			Mock: snapmock.Mock{
+				HelmClient:       h,
+				KubernetesClient: &kubernetes.Client{Interface: fake.NewSimpleClientset()},
+			},
+		}
+
+		cfg := types.MetricsServer{
+			Enabled: utils.Pointer(true),
+		}
+		annotations := types.Annotations{
+			metrics_server.AnnotationCertificateAlertsLabels: "release=kube-prometheus-stack,team=k8s",
+		}
+
+		_, err := metrics_server.ApplyMetricsServer(context.Background(), s, cfg, annotations)
+		g.Expect(err).To(Not(HaveOccurred()))
+		g.Expect(h.ApplyCalledWith).To(ContainElement(SatisfyAll(
+			HaveField("Chart.Name", Equal("ck-certificate-alerts")),
+			HaveField("Values", HaveKeyWithValue("labels", Equal(map[string]string{
+				"release": "kube-prometheus-stack",
+				"team":    "k8s",
+			}))),
+		)))
+	})
+
+	t.Run("PrometheusRuleCRD", func(t *testing.T) {
+		g := NewWithT(t)
+		h := &helmmock.Mock{}
+		clientset := fake.NewSimpleClientset()
+		s := &snapmock.Snap{
+			Mock: snapmock.Mock{
+				HelmClient:       h,
+				KubernetesClient: &kubernetes.Client{Interface: clientset},
+			},
+		}
+		cfg := types.MetricsServer{
+			Enabled: utils.Pointer(true),
+		}
+
+		_, err := metrics_server.ApplyMetricsServer(context.Background(), s, cfg, nil)
+		g.Expect(err).To(Not(HaveOccurred()))
+		g.Expect(h.ApplyCalledWith[1].Values).To(HaveKeyWithValue("prometheusRuleCRD", false))
+
+		// the rule is deployed when the feature is reconciled after the CRD was installed
+		clientset.Discovery().(*fakediscovery.FakeDiscovery).Resources = []*metav1.APIResourceList{{
+			GroupVersion: "monitoring.coreos.com/v1",
+			APIResources: []metav1.APIResource{{Name: "prometheusrules"}},
+		}}
+		_, err = metrics_server.ApplyMetricsServer(context.Background(), s, cfg, nil)
+		g.Expect(err).To(Not(HaveOccurred()))
+		g.Expect(h.ApplyCalledWith[3].Values).To(HaveKeyWithValue("prometheusRuleCRD", true))
+	})
+
+	t.Run("InvalidCertificateAlertsLabels", func(t *testing.T) {
+		g := NewWithT(t)
+		h := &helmmock.Mock{}
+		s := &snapmock.Snap{
+			Mock: snapmock.Mock{
+				HelmClient:       h,
+				KubernetesClient: &kubernetes.Client{Interface: fake.NewSimpleClientset()},
+			},
+		}
+
+		cfg := types.MetricsServer{
+			Enabled: utils.Pointer(true),
+		}
+		annotations := types.Annotations{
+			metrics_server.AnnotationCertificateAlertsLabels: "release",
+		}
+
+		status, err := metrics_server.ApplyMetricsServer(context.Background(), s, cfg, annotations)
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(h.ApplyCalledWith).To(BeEmpty())
+		g.Expect(status.Enabled).To(BeFalse())
+	})
 }
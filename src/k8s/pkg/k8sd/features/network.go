@@ -0,0 +1,402 @@
+package features
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/canonical/k8s/pkg/client/helm"
+	"github.com/canonical/k8s/pkg/client/kubernetes"
+	"github.com/canonical/k8s/pkg/k8sd/features/calico"
+	"github.com/canonical/k8s/pkg/k8sd/features/cilium"
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/k8s/pkg/snap"
+	snaputil "github.com/canonical/k8s/pkg/snap/util"
+	"github.com/canonical/k8s/pkg/utils"
+	"github.com/canonical/microcluster/v2/state"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+)
+
+const (
+	networkDeployFailedMsgTmpl  = "Failed to deploy network, the error was: %v"
+	networkMigrateFailedMsgTmpl = "Failed to switch network provider from %s to %s, the error was: %v"
+)
+
+// networkProvider is a network implementation (CNI) that can be deployed by the network feature.
+type networkProvider struct {
+	applyNetwork   func(context.Context, snap.Snap, state.State, types.APIServer, types.Network, types.Annotations) (types.FeatureStatus, error)
+	checkNetwork   func(context.Context, snap.Snap) error
+	cleanupNetwork func(context.Context, snap.Snap) error
+
+	// agentNamespace, agentName and agentLabels identify the DaemonSet of the CNI agent that runs on every node.
+	agentNamespace string
+	agentName      string
+	agentLabels    map[string]string
+	// cniConfig is the name of the CNI configuration file that the CNI agent writes in the CNI configuration
+	// directory of the nodes. The file that sorts first is used by the container runtime.
+	cniConfig string
+
+	// migrationValues are the Helm values of the network provider while the nodes are switched, so that it runs
+	// next to the other network provider on all nodes.
+	migrationValues map[string]any
+	// applyMigration makes the network provider manage the CNI configuration of the nodes that are labelled with
+	// it while deployed with migrationValues, or reverts that if present is false.
+	// applyMigration is nil if the network provider always manages the CNI configuration of the nodes.
+	applyMigration func(ctx context.Context, client *kubernetes.Client, present bool) error
+}
+
+// networkProviders are the network providers that can be selected with the network provider setting.
+var networkProviders = map[string]networkProvider{
+	types.NetworkProviderCilium: {
+		applyNetwork:    cilium.ApplyNetwork,
+		checkNetwork:    cilium.CheckNetwork,
+		cleanupNetwork:  cilium.CleanupNetwork,
+		agentNamespace:  "kube-system",
+		agentName:       "cilium",
+		agentLabels:     map[string]string{"k8s-app": "cilium"},
+		cniConfig:       "05-cilium.conflist",
+		migrationValues: cilium.MigrationValues,
+		applyMigration: func(ctx context.Context, client *kubernetes.Client, present bool) error {
+			return cilium.ApplyMigrationNodeConfig(ctx, client, map[string]string{types.NodeLabelNetworkProvider: types.NetworkProviderCilium}, present)
+		},
+	},
+	types.NetworkProviderCalico: {
+		applyNetwork:   calico.ApplyNetwork,
+		checkNetwork:   calico.CheckNetwork,
+		cleanupNetwork: calico.CleanupNetwork,
+		agentNamespace: "calico-system",
+		agentName:      "calico-node",
+		agentLabels:    map[string]string{"app.kubernetes.io/name": "calico-node"},
+		// Calico always writes its CNI configuration, which is overruled by the Cilium CNI configuration on the
+		// nodes that are not switched to Calico.
+		cniConfig: "10-calico.conflist",
+	},
+}
+
+// applyNetwork deploys the configured network provider.
+// If another network provider is still running on the cluster, applyNetwork starts switching the cluster to the
+// configured network provider, see MigrateNetwork. While the nodes are switched, both network providers run on all
+// nodes with separate pod CIDRs: the previous one with the pod CIDR and the configured one with the migration pod
+// CIDR. applyNetwork does not wait for the nodes to be switched.
+// If the network is disabled, applyNetwork removes all network providers.
+func applyNetwork(ctx context.Context, snap snap.Snap, s state.State, apiserver types.APIServer, network types.Network, annotations types.Annotations) (types.FeatureStatus, error) {
+	target, ok := networkProviders[network.GetProvider()]
+	if !ok {
+		err := fmt.Errorf("unsupported network provider %q", network.GetProvider())
+		return types.FeatureStatus{Message: fmt.Sprintf(networkDeployFailedMsgTmpl, err)}, err
+	}
+
+	if !network.GetEnabled() {
+		return removeNetwork(ctx, snap, s, apiserver, network, annotations)
+	}
+
+	client, err := snap.KubernetesClient("")
+	if err != nil {
+		err = fmt.Errorf("failed to create Kubernetes client: %w", err)
+		return types.FeatureStatus{Message: fmt.Sprintf(networkDeployFailedMsgTmpl, err)}, err
+	}
+
+	migration, err := getNetworkMigration(ctx, client)
+	if err != nil {
+		return types.FeatureStatus{Message: fmt.Sprintf(networkDeployFailedMsgTmpl, err)}, err
+	}
+
+	if migration == nil {
+		previous, err := runningNetworkProvider(ctx, client, network.GetProvider())
+		if err != nil {
+			return types.FeatureStatus{Message: fmt.Sprintf(networkDeployFailedMsgTmpl, err)}, err
+		}
+		if previous != "" {
+			migration = &networkMigration{from: previous, to: network.GetProvider(), phase: networkMigrationSwitching}
+		}
+	}
+
+	if migration != nil {
+		if network.GetMigrationPodCIDR() != "" {
+			return startNetworkMigration(ctx, snap, s, client, apiserver, network, annotations, migration)
+		}
+		if migration.phase != networkMigrationCompleted {
+			err := fmt.Errorf("network provider %s is still running, set the %q annotation to a pod CIDR that does not overlap with the pod CIDR %s to switch to %s", migration.from, types.AnnotationNetworkMigrationPodCIDR, network.GetPodCIDR(), migration.to)
+			return types.FeatureStatus{Message: fmt.Sprintf(networkMigrateFailedMsgTmpl, migration.from, migration.to, err)}, err
+		}
+	}
+
+	status, err := target.applyNetwork(ctx, snap, s, apiserver, network, annotations)
+	if err != nil {
+		return status, err
+	}
+
+	if migration != nil {
+		// the migration pod CIDR became the pod CIDR, the nodes are switched and the previous network provider removed
+		if err := finishNetworkMigration(ctx, client); err != nil {
+			status.Message = fmt.Sprintf(networkDeployFailedMsgTmpl, err)
+			return status, err
+		}
+	}
+
+	// the nodes record the network provider they use, so that they are cleaned up accordingly when removed
+	if err := labelNodes(ctx, client, network.GetProvider(), false); err != nil {
+		err = fmt.Errorf("failed to label nodes: %w", err)
+		status.Message = fmt.Sprintf(networkDeployFailedMsgTmpl, err)
+		return status, err
+	}
+
+	return status, nil
+}
+
+// startNetworkMigration deploys both network providers of the migration next to each other, so that MigrateNetwork
+// can switch the nodes one by one.
+func startNetworkMigration(ctx context.Context, snap snap.Snap, s state.State, client *kubernetes.Client, apiserver types.APIServer, network types.Network, annotations types.Annotations, migration *networkMigration) (types.FeatureStatus, error) {
+	failed := func(status types.FeatureStatus, err error) (types.FeatureStatus, error) {
+		status.Message = fmt.Sprintf(networkMigrateFailedMsgTmpl, migration.from, migration.to, err)
+		return status, err
+	}
+
+	if migration.to != network.GetProvider() {
+		return failed(types.FeatureStatus{}, fmt.Errorf("the cluster is being switched to %s, wait for the switch to complete", migration.to))
+	}
+
+	previous, target := networkProviders[migration.from], networkProviders[migration.to]
+
+	if migration.phase == networkMigrationSwitching {
+		// the nodes that are not switched yet keep using the previous network provider
+		if err := labelNodes(ctx, client, migration.from, true); err != nil {
+			return failed(types.FeatureStatus{}, fmt.Errorf("failed to label nodes: %w", err))
+		}
+		if err := setNetworkMigration(ctx, client, *migration); err != nil {
+			return failed(types.FeatureStatus{}, err)
+		}
+
+		if status, err := previous.applyNetwork(ctx, &networkValuesSnap{Snap: snap, values: previous.migrationValues}, s, apiserver, network, annotations); err != nil {
+			return failed(status, fmt.Errorf("failed to deploy %s next to %s: %w", migration.from, migration.to, err))
+		}
+	}
+
+	migrationNetwork := network
+	migrationNetwork.PodCIDR = utils.Pointer(network.GetMigrationPodCIDR())
+	status, err := target.applyNetwork(ctx, &networkValuesSnap{Snap: snap, values: target.migrationValues}, s, apiserver, migrationNetwork, annotations)
+	if err != nil {
+		return failed(status, fmt.Errorf("failed to deploy %s next to %s: %w", migration.to, migration.from, err))
+	}
+
+	for _, provider := range []networkProvider{previous, target} {
+		if provider.applyMigration == nil {
+			continue
+		}
+		if err := provider.applyMigration(ctx, client, true); err != nil {
+			return failed(status, err)
+		}
+	}
+
+	status.Message = fmt.Sprintf("switching network provider from %s to %s", migration.from, migration.to)
+	return status, nil
+}
+
+// finishNetworkMigration reverts the configuration of the network providers for the migration.
+func finishNetworkMigration(ctx context.Context, client *kubernetes.Client) error {
+	for _, provider := range networkProviders {
+		if provider.applyMigration == nil {
+			continue
+		}
+		if err := provider.applyMigration(ctx, client, false); err != nil {
+			return err
+		}
+	}
+	return deleteNetworkMigration(ctx, client)
+}
+
+// removeNetwork removes all network providers.
+func removeNetwork(ctx context.Context, snap snap.Snap, s state.State, apiserver types.APIServer, network types.Network, annotations types.Annotations) (types.FeatureStatus, error) {
+	status, err := networkProviders[network.GetProvider()].applyNetwork(ctx, snap, s, apiserver, network, annotations)
+	if err != nil {
+		return status, err
+	}
+	for name, provider := range networkProviders {
+		if name == network.GetProvider() {
+			continue
+		}
+		if otherStatus, err := provider.applyNetwork(ctx, snap, s, apiserver, network, annotations); err != nil {
+			return otherStatus, fmt.Errorf("failed to remove network provider %s: %w", name, err)
+		}
+	}
+
+	client, err := snap.KubernetesClient("")
+	if err != nil {
+		err = fmt.Errorf("failed to create Kubernetes client: %w", err)
+		status.Message = fmt.Sprintf(networkDeployFailedMsgTmpl, err)
+		return status, err
+	}
+	if err := finishNetworkMigration(ctx, client); err != nil {
+		status.Message = fmt.Sprintf(networkDeployFailedMsgTmpl, err)
+		return status, err
+	}
+	return status, nil
+}
+
+// runningNetworkProvider returns the name of the network provider, other than the configured one, whose CNI agent
+// is running on the cluster. runningNetworkProvider returns an empty string if no other network provider is running.
+func runningNetworkProvider(ctx context.Context, client *kubernetes.Client, configured string) (string, error) {
+	for name, provider := range networkProviders {
+		if name == configured {
+			continue
+		}
+		ds, err := client.AppsV1().DaemonSets(provider.agentNamespace).Get(ctx, provider.agentName, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return "", fmt.Errorf("failed to get %s agent: %w", name, err)
+		}
+		if ds.DeletionTimestamp == nil {
+			return name, nil
+		}
+	}
+	return "", nil
+}
+
+// labelNodes sets the network provider label on all nodes, or only on the nodes without one if onlyUnlabelled is true.
+func labelNodes(ctx context.Context, client *kubernetes.Client, provider string, onlyUnlabelled bool) error {
+	nodes, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list nodes: %w", err)
+	}
+	for _, node := range nodes.Items {
+		current, ok := node.Labels[types.NodeLabelNetworkProvider]
+		if current == provider || (ok && onlyUnlabelled) {
+			continue
+		}
+		if err := client.SetNodeLabel(ctx, node.Name, types.NodeLabelNetworkProvider, provider); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// agentPods returns the pods of the CNI agent of the network provider that are running on the node, or on all
+// nodes if nodeName is empty.
+func agentPods(ctx context.Context, client *kubernetes.Client, provider networkProvider, nodeName string) ([]corev1.Pod, error) {
+	opts := metav1.ListOptions{
+		LabelSelector: metav1.FormatLabelSelector(&metav1.LabelSelector{MatchLabels: provider.agentLabels}),
+	}
+	if nodeName != "" {
+		opts.FieldSelector = fields.OneTermEqualSelector("spec.nodeName", nodeName).String()
+	}
+	pods, err := client.CoreV1().Pods(provider.agentNamespace).List(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	var nodePods []corev1.Pod
+	for _, pod := range pods.Items {
+		if nodeName == "" || pod.Spec.NodeName == nodeName {
+			nodePods = append(nodePods, pod)
+		}
+	}
+	return nodePods, nil
+}
+
+// podIsReady checks if the pod is running and ready.
+func podIsReady(pod corev1.Pod) bool {
+	if pod.Status.Phase != corev1.PodRunning {
+		return false
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady && condition.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// networkValuesSnap is the snap.Snap that is used to deploy a network provider while the nodes are switched.
+// networkValuesSnap deep-merges values into the values of all charts that are installed or upgraded.
+type networkValuesSnap struct {
+	snap.Snap
+	values map[string]any
+}
+
+func (s *networkValuesSnap) HelmClient() helm.Client {
+	return &networkValuesHelmClient{Client: s.Snap.HelmClient(), values: s.values}
+}
+
+type networkValuesHelmClient struct {
+	helm.Client
+	values map[string]any
+}
+
+func (c *networkValuesHelmClient) Apply(ctx context.Context, chart helm.InstallableChart, desired helm.State, values map[string]any) (bool, error) {
+	if desired != helm.StateDeleted && c.values != nil {
+		values = helm.MergeValues(values, c.values)
+	}
+	return c.Client.Apply(ctx, chart, desired, values)
+}
+
+// checkNetwork checks the status of the network providers that are deployed on the cluster.
+// While the nodes are switched to another network provider, both network providers are checked.
+func checkNetwork(ctx context.Context, snap snap.Snap) error {
+	client, err := snap.KubernetesClient("")
+	if err != nil {
+		return fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	var checked bool
+	for _, name := range []string{types.NetworkProviderCilium, types.NetworkProviderCalico} {
+		provider := networkProviders[name]
+		if _, err := client.AppsV1().DaemonSets(provider.agentNamespace).Get(ctx, provider.agentName, metav1.GetOptions{}); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return fmt.Errorf("failed to get %s agent: %w", name, err)
+		}
+		if err := provider.checkNetwork(ctx, snap); err != nil {
+			return err
+		}
+		checked = true
+	}
+
+	if !checked {
+		// no network provider is deployed yet, report the missing pods of the default network provider
+		return networkProviders[types.DefaultNetworkImplementation.DefaultProvider].checkNetwork(ctx, snap)
+	}
+	return nil
+}
+
+// cleanupNetwork removes the node-local state of the network provider that the node was switched to.
+// Nodes that were never switched use the default network provider of the build.
+func cleanupNetwork(ctx context.Context, snap snap.Snap) error {
+	provider, err := snaputil.GetNetworkProvider(snap)
+	if err != nil {
+		return err
+	}
+	if provider == "" {
+		provider = types.DefaultNetworkImplementation.DefaultProvider
+	}
+	return cleanupNetworkProvider(ctx, snap, provider)
+}
+
+// cleanupNetworkProvider removes the node-local state of the named network provider.
+func cleanupNetworkProvider(ctx context.Context, snap snap.Snap, name string) error {
+	provider, ok := networkProviders[name]
+	if !ok {
+		return fmt.Errorf("unsupported network provider %q", name)
+	}
+	return provider.cleanupNetwork(ctx, snap)
+}
+
+// cleanupNetworkProviderConfig removes the CNI configuration of the named network provider from the node, so that
+// new pods on the node use the network provider that the node was switched to.
+func cleanupNetworkProviderConfig(_ context.Context, snap snap.Snap, name string) error {
+	provider, ok := networkProviders[name]
+	if !ok {
+		return fmt.Errorf("unsupported network provider %q", name)
+	}
+	if err := os.Remove(filepath.Join(snap.CNIConfDir(), provider.cniConfig)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove CNI configuration of %s: %w", name, err)
+	}
+	return nil
+}
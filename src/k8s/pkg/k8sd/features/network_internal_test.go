@@ -0,0 +1,305 @@
+package features
+
+import (
+	"context"
+	"testing"
+
+	"github.com/canonical/k8s/pkg/client/helm"
+	helmmock "github.com/canonical/k8s/pkg/client/helm/mock"
+	"github.com/canonical/k8s/pkg/client/kubernetes"
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/k8s/pkg/snap"
+	snapmock "github.com/canonical/k8s/pkg/snap/mock"
+	"github.com/canonical/k8s/pkg/utils"
+	"github.com/canonical/microcluster/v2/state"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// testNetworkProvider returns a network provider that installs or removes a chart in the given namespace.
+// onRemove is called when the network provider is removed.
+func testNetworkProvider(namespace string, onRemove func()) networkProvider {
+	chart := helm.InstallableChart{Name: "ck-network", Namespace: namespace}
+	return networkProvider{
+		applyNetwork: func(ctx context.Context, snap snap.Snap, _ state.State, _ types.APIServer, network types.Network, _ types.Annotations) (types.FeatureStatus, error) {
+			desired := helm.StatePresent
+			if !network.GetEnabled() {
+				desired = helm.StateDeleted
+			}
+			if _, err := snap.HelmClient().Apply(ctx, chart, desired, map[string]any{"podCIDR": network.GetPodCIDR()}); err != nil {
+				return types.FeatureStatus{Version: namespace}, err
+			}
+			if !network.GetEnabled() && onRemove != nil {
+				onRemove()
+			}
+			return types.FeatureStatus{Enabled: network.GetEnabled(), Version: namespace, Message: "enabled"}, nil
+		},
+		agentNamespace:  namespace,
+		agentName:       "agent",
+		agentLabels:     map[string]string{"app": "agent"},
+		migrationValues: map[string]any{"migration": true},
+	}
+}
+
+func testAgentPod(namespace string, nodeName string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "agent-" + nodeName,
+			Namespace:       namespace,
+			Labels:          map[string]string{"app": "agent"},
+			OwnerReferences: []metav1.OwnerReference{{Kind: "DaemonSet", Name: "agent", Controller: utils.Pointer(true)}},
+		},
+		Spec: corev1.PodSpec{NodeName: nodeName, HostNetwork: true},
+		Status: corev1.PodStatus{
+			Phase:      corev1.PodRunning,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+		},
+	}
+}
+
+// withTestNetworkProviders replaces the network providers with the "from" and "to" test network providers.
+func withTestNetworkProviders(t *testing.T, from networkProvider, to networkProvider) {
+	providers := networkProviders
+	networkProviders = map[string]networkProvider{"from": from, "to": to}
+	t.Cleanup(func() { networkProviders = providers })
+}
+
+func TestNetworkMigration(t *testing.T) {
+	podsGVR := corev1.SchemeGroupVersion.WithResource("pods")
+	network := types.Network{
+		Enabled:          utils.Pointer(true),
+		Provider:         utils.Pointer("to"),
+		PodCIDR:          utils.Pointer("10.1.0.0/16"),
+		MigrationPodCIDR: utils.Pointer("10.100.0.0/16"),
+	}
+
+	t.Run("MigrationPodCIDRRequired", func(t *testing.T) {
+		g := NewWithT(t)
+		withTestNetworkProviders(t, testNetworkProvider("from", nil), testNetworkProvider("to", nil))
+
+		clientset := fake.NewSimpleClientset(&appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "from"}})
+		helmClient := &helmmock.Mock{}
+		snap := &snapmock.Snap{Mock: snapmock.Mock{HelmClient: helmClient, KubernetesClient: &kubernetes.Client{Interface: clientset}}}
+
+		withoutMigrationPodCIDR := network
+		withoutMigrationPodCIDR.MigrationPodCIDR = nil
+		status, err := applyNetwork(context.Background(), snap, nil, types.APIServer{}, withoutMigrationPodCIDR, nil)
+		g.Expect(err).To(MatchError(ContainSubstring(types.AnnotationNetworkMigrationPodCIDR)))
+		g.Expect(status.Message).To(HavePrefix("Failed to switch network provider from from to to"))
+		g.Expect(helmClient.ApplyCalledWith).To(BeEmpty())
+	})
+
+	t.Run("SwitchNodes", func(t *testing.T) {
+		g := NewWithT(t)
+		ctx := context.Background()
+
+		clientset := fake.NewSimpleClientset(
+			&appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "from"}},
+			&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1", Labels: map[string]string{types.NodeLabelNetworkProvider: "from"}}},
+			&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node2"}},
+			testAgentPod("from", "node1"),
+			testAgentPod("from", "node2"),
+			testAgentPod("to", "node1"),
+			testAgentPod("to", "node2"),
+			&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "workload", Namespace: "default", OwnerReferences: []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "workload", Controller: utils.Pointer(true)}}},
+				Spec:       corev1.PodSpec{NodeName: "node2"},
+				Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+			},
+		)
+		// evictions remove the pod
+		clientset.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			if action.GetSubresource() != "eviction" {
+				return false, nil, nil
+			}
+			return true, nil, clientset.Tracker().Delete(podsGVR, action.GetNamespace(), action.(k8stesting.CreateAction).GetObject().(metav1.Object).GetName())
+		})
+		// the CNI agents are re-created as soon as they are deleted
+		var restarted []string
+		clientset.PrependReactor("delete", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			if action.GetNamespace() == "default" {
+				return false, nil, nil
+			}
+			restarted = append(restarted, action.GetNamespace()+"/"+action.(k8stesting.DeleteAction).GetName())
+			return true, nil, nil
+		})
+		// the nodes remove the CNI configuration and clean up the previous network provider as soon as requested
+		var switches, cleanups []string
+		clientset.PrependReactor("update", "nodes", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			node := action.(k8stesting.UpdateAction).GetObject().(*corev1.Node)
+			if provider, ok := node.Annotations[types.NodeAnnotationNetworkSwitch]; ok {
+				switches = append(switches, node.Name+"/"+provider)
+				delete(node.Annotations, types.NodeAnnotationNetworkSwitch)
+			}
+			if provider, ok := node.Annotations[types.NodeAnnotationNetworkCleanup]; ok {
+				cleanups = append(cleanups, node.Name+"/"+provider)
+				delete(node.Annotations, types.NodeAnnotationNetworkCleanup)
+			}
+			return false, nil, nil
+		})
+
+		var migrationApplied []bool
+		from := testNetworkProvider("from", func() {
+			for _, nodeName := range []string{"node1", "node2"} {
+				g.Expect(clientset.Tracker().Delete(podsGVR, "from", "agent-"+nodeName)).To(Succeed())
+			}
+			g.Expect(clientset.Tracker().Delete(appsv1.SchemeGroupVersion.WithResource("daemonsets"), "from", "agent")).To(Succeed())
+		})
+		to := testNetworkProvider("to", nil)
+		to.applyMigration = func(_ context.Context, _ *kubernetes.Client, present bool) error {
+			migrationApplied = append(migrationApplied, present)
+			return nil
+		}
+		withTestNetworkProviders(t, from, to)
+
+		helmClient := &helmmock.Mock{}
+		snap := &snapmock.Snap{Mock: snapmock.Mock{HelmClient: helmClient, KubernetesClient: &kubernetes.Client{Interface: clientset}}}
+
+		// the network feature deploys both network providers with separate pod CIDRs
+		status, err := applyNetwork(ctx, snap, nil, types.APIServer{}, network, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(status.Message).To(Equal("switching network provider from from to to"))
+
+		g.Expect(helmClient.ApplyCalledWith).To(HaveLen(2))
+		g.Expect(helmClient.ApplyCalledWith[0].Chart.Namespace).To(Equal("from"))
+		g.Expect(helmClient.ApplyCalledWith[0].Values).To(Equal(map[string]any{"podCIDR": "10.1.0.0/16", "migration": true}))
+		g.Expect(helmClient.ApplyCalledWith[1].Chart.Namespace).To(Equal("to"))
+		g.Expect(helmClient.ApplyCalledWith[1].Values).To(Equal(map[string]any{"podCIDR": "10.100.0.0/16", "migration": true}))
+		g.Expect(migrationApplied).To(Equal([]bool{true}))
+
+		node, err := clientset.CoreV1().Nodes().Get(ctx, "node2", metav1.GetOptions{})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(node.Labels).To(HaveKeyWithValue(types.NodeLabelNetworkProvider, "from"))
+
+		// the migration controller switches the nodes one step at a time
+		var messages []string
+		var done bool
+		for i := 0; i < 20 && !done; i++ {
+			status, done, err = MigrateNetwork(ctx, snap, nil, types.APIServer{}, network, nil)
+			g.Expect(err).ToNot(HaveOccurred())
+			messages = append(messages, status.Message)
+		}
+		g.Expect(done).To(BeTrue())
+		g.Expect(messages).To(Equal([]string{
+			"switching network provider from from to to: draining node node1 (0/2 nodes switched)",
+			"switching network provider from from to to: switching node node1 (0/2 nodes switched)",
+			"switching network provider from from to to: switched node node1 (0/2 nodes switched)",
+			"switching network provider from from to to: draining node node2 (1/2 nodes switched)",
+			"switching network provider from from to to: draining node node2 (1 pods left) (1/2 nodes switched)",
+			"switching network provider from from to to: switching node node2 (1/2 nodes switched)",
+			"switching network provider from from to to: switched node node2 (1/2 nodes switched)",
+			"switching network provider from from to to: removing from",
+			"switching network provider from from to to: cleaning up from on the nodes",
+			"switching network provider from from to to: completed",
+		}))
+
+		g.Expect(switches).To(Equal([]string{"node1/from", "node2/from"}))
+		g.Expect(cleanups).To(ConsistOf("node1/from", "node2/from"))
+		g.Expect(restarted).To(Equal([]string{"from/agent-node1", "to/agent-node1", "from/agent-node2", "to/agent-node2"}))
+		g.Expect(helmClient.ApplyCalledWith).To(HaveLen(3))
+		g.Expect(helmClient.ApplyCalledWith[2].Chart.Namespace).To(Equal("from"))
+		g.Expect(helmClient.ApplyCalledWith[2].State).To(Equal(helm.StateDeleted))
+
+		nodes, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+		g.Expect(err).ToNot(HaveOccurred())
+		for _, node := range nodes.Items {
+			g.Expect(node.Labels).To(HaveKeyWithValue(types.NodeLabelNetworkProvider, "to"))
+			g.Expect(node.Annotations).ToNot(HaveKey(types.NodeAnnotationNetworkMigration))
+			g.Expect(node.Spec.Unschedulable).To(BeFalse())
+		}
+
+		pods, err := clientset.CoreV1().Pods("default").List(ctx, metav1.ListOptions{})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(pods.Items).To(BeEmpty())
+
+		// the migration pod CIDR becomes the pod CIDR, and the network feature deploys the network provider as usual
+		completed := network
+		completed.PodCIDR = network.MigrationPodCIDR
+		completed.MigrationPodCIDR = nil
+		status, err = applyNetwork(ctx, snap, nil, types.APIServer{}, completed, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(status).To(Equal(types.FeatureStatus{Enabled: true, Version: "to", Message: "enabled"}))
+		g.Expect(helmClient.ApplyCalledWith).To(HaveLen(4))
+		g.Expect(helmClient.ApplyCalledWith[3].Values).To(Equal(map[string]any{"podCIDR": "10.100.0.0/16"}))
+		g.Expect(migrationApplied).To(Equal([]bool{true, false}))
+
+		_, err = clientset.CoreV1().ConfigMaps(networkMigrationNamespace).Get(ctx, networkMigrationName, metav1.GetOptions{})
+		g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	t.Run("AgentNotReady", func(t *testing.T) {
+		g := NewWithT(t)
+		ctx := context.Background()
+		withTestNetworkProviders(t, testNetworkProvider("from", nil), testNetworkProvider("to", nil))
+
+		clientset := fake.NewSimpleClientset(
+			&corev1.Node{ObjectMeta: metav1.ObjectMeta{
+				Name:        "node1",
+				Labels:      map[string]string{types.NodeLabelNetworkProvider: "to"},
+				Annotations: map[string]string{types.NodeAnnotationNetworkMigration: nodeNetworkMigrationSwitch},
+			}, Spec: corev1.NodeSpec{Unschedulable: true}},
+			&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: networkMigrationName, Namespace: networkMigrationNamespace},
+				Data:       map[string]string{"from": "from", "to": "to", "phase": networkMigrationSwitching},
+			},
+		)
+		snap := &snapmock.Snap{Mock: snapmock.Mock{HelmClient: &helmmock.Mock{}, KubernetesClient: &kubernetes.Client{Interface: clientset}}}
+
+		// the migration does not advance until the agent of the target network provider is ready on the node
+		for i := 0; i < 2; i++ {
+			status, done, err := MigrateNetwork(ctx, snap, nil, types.APIServer{}, network, nil)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(done).To(BeFalse())
+			g.Expect(status.Message).To(Equal("switching network provider from from to to: waiting for the to agent on node node1 to be ready (0/1 nodes switched)"))
+		}
+
+		node, err := clientset.CoreV1().Nodes().Get(ctx, "node1", metav1.GetOptions{})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(node.Spec.Unschedulable).To(BeTrue())
+	})
+}
+
+func TestRunningNetworkProvider(t *testing.T) {
+	g := NewWithT(t)
+
+	client := &kubernetes.Client{Interface: fake.NewSimpleClientset(
+		&appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "calico-node", Namespace: "calico-system"}},
+	)}
+
+	previous, err := runningNetworkProvider(context.Background(), client, types.NetworkProviderCilium)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(previous).To(Equal(types.NetworkProviderCalico))
+
+	previous, err = runningNetworkProvider(context.Background(), client, types.NetworkProviderCalico)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(previous).To(BeEmpty())
+}
+
+func TestLabelNodes(t *testing.T) {
+	g := NewWithT(t)
+
+	clientset := fake.NewSimpleClientset(
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1"}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node2", Labels: map[string]string{types.NodeLabelNetworkProvider: "cilium"}}},
+	)
+
+	g.Expect(labelNodes(context.Background(), &kubernetes.Client{Interface: clientset}, "calico", false)).To(Succeed())
+
+	nodes, err := clientset.CoreV1().Nodes().List(context.Background(), metav1.ListOptions{})
+	g.Expect(err).ToNot(HaveOccurred())
+	for _, node := range nodes.Items {
+		g.Expect(node.Labels).To(HaveKeyWithValue(types.NodeLabelNetworkProvider, "calico"))
+	}
+}
+
+func TestCleanupNetworkProvider(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(cleanupNetworkProvider(context.Background(), &snapmock.Snap{}, "flannel")).To(MatchError(ContainSubstring("unsupported network provider")))
+}
@@ -0,0 +1,326 @@
+package features
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/canonical/k8s/pkg/client/kubernetes"
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/k8s/pkg/log"
+	"github.com/canonical/k8s/pkg/snap"
+	"github.com/canonical/k8s/pkg/utils"
+	"github.com/canonical/microcluster/v2/state"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// networkMigrationNamespace and networkMigrationName identify the ConfigMap that records a network migration.
+	networkMigrationNamespace = "kube-system"
+	networkMigrationName      = "k8sd-network-migration"
+
+	// networkMigrationSwitching is the phase where the nodes are switched one by one.
+	networkMigrationSwitching = "switching"
+	// networkMigrationRemoving is the phase where the previous network provider is removed.
+	networkMigrationRemoving = "removing"
+	// networkMigrationCleanup is the phase where the nodes clean up the local state of the previous network provider.
+	networkMigrationCleanup = "cleanup"
+	// networkMigrationCompleted is the phase where the migration pod CIDR becomes the pod CIDR of the cluster.
+	networkMigrationCompleted = "completed"
+
+	// nodeNetworkMigrationDrain is the step where a node is drained before it is switched.
+	nodeNetworkMigrationDrain = "drain"
+	// nodeNetworkMigrationSwitch is the step where a node switches its CNI configuration.
+	nodeNetworkMigrationSwitch = "switch"
+)
+
+// networkMigration is the state of a network migration, recorded in a ConfigMap on the cluster.
+type networkMigration struct {
+	from  string
+	to    string
+	phase string
+}
+
+// getNetworkMigration returns the network migration of the cluster, or nil if the cluster is not being switched to
+// another network provider.
+func getNetworkMigration(ctx context.Context, client *kubernetes.Client) (*networkMigration, error) {
+	cm, err := client.CoreV1().ConfigMaps(networkMigrationNamespace).Get(ctx, networkMigrationName, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get network migration: %w", err)
+	}
+	return &networkMigration{from: cm.Data["from"], to: cm.Data["to"], phase: cm.Data["phase"]}, nil
+}
+
+// setNetworkMigration records the network migration of the cluster.
+func setNetworkMigration(ctx context.Context, client *kubernetes.Client, migration networkMigration) error {
+	data := map[string]string{"from": migration.from, "to": migration.to, "phase": migration.phase}
+
+	cm, err := client.CoreV1().ConfigMaps(networkMigrationNamespace).Get(ctx, networkMigrationName, metav1.GetOptions{})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get network migration: %w", err)
+		}
+		if _, err := client.CoreV1().ConfigMaps(networkMigrationNamespace).Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: networkMigrationName, Namespace: networkMigrationNamespace},
+			Data:       data,
+		}, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("failed to create network migration: %w", err)
+		}
+		return nil
+	}
+
+	cm.Data = data
+	if _, err := client.CoreV1().ConfigMaps(networkMigrationNamespace).Update(ctx, cm, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update network migration: %w", err)
+	}
+	return nil
+}
+
+// deleteNetworkMigration removes the record of the network migration of the cluster.
+func deleteNetworkMigration(ctx context.Context, client *kubernetes.Client) error {
+	if err := client.CoreV1().ConfigMaps(networkMigrationNamespace).Delete(ctx, networkMigrationName, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete network migration: %w", err)
+	}
+	return nil
+}
+
+// MigrateNetwork advances the migration of the cluster to the configured network provider by a single step, and
+// does not wait for the step to take effect. MigrateNetwork is called repeatedly until it returns true, after which
+// the migration pod CIDR must become the pod CIDR of the cluster.
+// The nodes are switched one by one:
+//   - the node is cordoned and drained
+//   - the node is labelled, so that the CNI agents on the node switch the CNI configuration of the node
+//   - the node is requested to remove the CNI configuration of the previous network provider
+//   - the pods that are left on the node are re-created with the target CNI and the node is uncordoned
+//
+// Once all nodes are switched, the previous network provider is removed and the nodes are requested to clean up
+// its local state.
+// MigrateNetwork returns the status of the network feature, which reports the progress of the migration.
+func MigrateNetwork(ctx context.Context, snap snap.Snap, s state.State, apiserver types.APIServer, network types.Network, annotations types.Annotations) (types.FeatureStatus, bool, error) {
+	client, err := snap.KubernetesClient("")
+	if err != nil {
+		err = fmt.Errorf("failed to create Kubernetes client: %w", err)
+		return types.FeatureStatus{Enabled: true, Message: fmt.Sprintf(networkDeployFailedMsgTmpl, err)}, false, err
+	}
+
+	migration, err := getNetworkMigration(ctx, client)
+	if err != nil {
+		return types.FeatureStatus{Enabled: true, Message: fmt.Sprintf(networkDeployFailedMsgTmpl, err)}, false, err
+	}
+	if migration == nil {
+		// the network feature starts the migration
+		return types.FeatureStatus{
+			Enabled: true,
+			Message: fmt.Sprintf("waiting to switch network provider to %s", network.GetProvider()),
+		}, false, nil
+	}
+
+	m := &networkMigrator{
+		client:    client,
+		migration: *migration,
+		previous:  networkProviders[migration.from],
+		target:    networkProviders[migration.to],
+	}
+
+	message, done, err := m.step(ctx, snap, s, apiserver, network, annotations)
+	if err != nil {
+		return types.FeatureStatus{Enabled: true, Message: fmt.Sprintf(networkMigrateFailedMsgTmpl, migration.from, migration.to, err)}, false, err
+	}
+	return types.FeatureStatus{
+		Enabled: true,
+		Message: fmt.Sprintf("switching network provider from %s to %s: %s", migration.from, migration.to, message),
+	}, done, nil
+}
+
+// networkMigrator advances a network migration.
+type networkMigrator struct {
+	client    *kubernetes.Client
+	migration networkMigration
+	previous  networkProvider
+	target    networkProvider
+}
+
+// step advances the network migration and returns a message that describes its progress.
+func (m *networkMigrator) step(ctx context.Context, snap snap.Snap, s state.State, apiserver types.APIServer, network types.Network, annotations types.Annotations) (string, bool, error) {
+	log := log.FromContext(ctx).WithValues("from", m.migration.from, "to", m.migration.to)
+
+	if m.migration.to != network.GetProvider() {
+		return "", false, fmt.Errorf("the network provider is set to %s", network.GetProvider())
+	}
+
+	nodes, err := m.client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", false, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	sort.Slice(nodes.Items, func(i, j int) bool { return nodes.Items[i].Name < nodes.Items[j].Name })
+
+	switch m.migration.phase {
+	case networkMigrationSwitching:
+		var switched int
+		var next *corev1.Node
+		for i, node := range nodes.Items {
+			if _, inProgress := node.Annotations[types.NodeAnnotationNetworkMigration]; inProgress {
+				next = &nodes.Items[i]
+			} else if node.Labels[types.NodeLabelNetworkProvider] == m.migration.to {
+				switched++
+			}
+		}
+		// nodes are switched one at a time, in the order of their names
+		for i, node := range nodes.Items {
+			if next != nil {
+				break
+			}
+			if node.Labels[types.NodeLabelNetworkProvider] != m.migration.to {
+				next = &nodes.Items[i]
+			}
+		}
+
+		if next != nil {
+			message, err := m.switchNode(ctx, next)
+			if err != nil {
+				return "", false, fmt.Errorf("failed to switch node %s: %w", next.Name, err)
+			}
+			return fmt.Sprintf("%s (%d/%d nodes switched)", message, switched, len(nodes.Items)), false, nil
+		}
+
+		log.Info("Switched all nodes, removing previous network provider")
+		disabled := network
+		disabled.Enabled = utils.Pointer(false)
+		if _, err := m.previous.applyNetwork(ctx, snap, s, apiserver, disabled, annotations); err != nil {
+			return "", false, fmt.Errorf("failed to remove %s: %w", m.migration.from, err)
+		}
+		if err := m.setPhase(ctx, networkMigrationRemoving); err != nil {
+			return "", false, err
+		}
+		return fmt.Sprintf("removing %s", m.migration.from), false, nil
+
+	case networkMigrationRemoving:
+		pods, err := agentPods(ctx, m.client, m.previous, "")
+		if err != nil {
+			return "", false, err
+		}
+		if len(pods) > 0 {
+			return fmt.Sprintf("waiting for %d %s agents to stop", len(pods), m.migration.from), false, nil
+		}
+		for _, node := range nodes.Items {
+			if err := m.client.SetNodeAnnotation(ctx, node.Name, types.NodeAnnotationNetworkCleanup, m.migration.from); err != nil {
+				return "", false, fmt.Errorf("failed to request cleanup of %s on node %s: %w", m.migration.from, node.Name, err)
+			}
+		}
+		if err := m.setPhase(ctx, networkMigrationCleanup); err != nil {
+			return "", false, err
+		}
+		return fmt.Sprintf("cleaning up %s on the nodes", m.migration.from), false, nil
+
+	case networkMigrationCleanup:
+		var pending int
+		for _, node := range nodes.Items {
+			if _, ok := node.Annotations[types.NodeAnnotationNetworkCleanup]; ok {
+				pending++
+			}
+		}
+		if pending > 0 {
+			return fmt.Sprintf("waiting for %d nodes to clean up %s", pending, m.migration.from), false, nil
+		}
+		if err := m.setPhase(ctx, networkMigrationCompleted); err != nil {
+			return "", false, err
+		}
+		log.Info("Switched network provider", "nodes", len(nodes.Items))
+		return "completed", true, nil
+
+	case networkMigrationCompleted:
+		return "completed", true, nil
+
+	default:
+		return "", false, fmt.Errorf("unknown network migration phase %q", m.migration.phase)
+	}
+}
+
+// switchNode advances the switch of a single node to the target network provider.
+// The step of the node is recorded in the types.NodeAnnotationNetworkMigration annotation of the node.
+func (m *networkMigrator) switchNode(ctx context.Context, node *corev1.Node) (string, error) {
+	switch node.Annotations[types.NodeAnnotationNetworkMigration] {
+	case "":
+		log.FromContext(ctx).Info("Switching node to network provider", "node", node.Name, "provider", m.migration.to)
+		if err := m.client.CordonNode(ctx, node.Name, true); err != nil {
+			return "", fmt.Errorf("failed to cordon node: %w", err)
+		}
+		if err := m.client.SetNodeAnnotation(ctx, node.Name, types.NodeAnnotationNetworkMigration, nodeNetworkMigrationDrain); err != nil {
+			return "", fmt.Errorf("failed to mark node as switching: %w", err)
+		}
+		return fmt.Sprintf("draining node %s", node.Name), nil
+
+	case nodeNetworkMigrationDrain:
+		remaining, err := m.client.EvictNodePods(ctx, node.Name)
+		if err != nil {
+			return "", fmt.Errorf("failed to drain node: %w", err)
+		}
+		if remaining > 0 {
+			return fmt.Sprintf("draining node %s (%d pods left)", node.Name, remaining), nil
+		}
+
+		if err := m.client.SetNodeLabel(ctx, node.Name, types.NodeLabelNetworkProvider, m.migration.to); err != nil {
+			return "", fmt.Errorf("failed to label node: %w", err)
+		}
+		if err := m.client.SetNodeAnnotation(ctx, node.Name, types.NodeAnnotationNetworkSwitch, m.migration.from); err != nil {
+			return "", fmt.Errorf("failed to request removal of the %s CNI configuration: %w", m.migration.from, err)
+		}
+		// the CNI agents pick up the label of the node when they start
+		for _, provider := range []networkProvider{m.previous, m.target} {
+			pods, err := agentPods(ctx, m.client, provider, node.Name)
+			if err != nil {
+				return "", err
+			}
+			for _, pod := range pods {
+				if err := m.client.CoreV1().Pods(pod.Namespace).Delete(ctx, pod.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+					return "", fmt.Errorf("failed to restart CNI agent %s: %w", pod.Name, err)
+				}
+			}
+		}
+		if err := m.client.SetNodeAnnotation(ctx, node.Name, types.NodeAnnotationNetworkMigration, nodeNetworkMigrationSwitch); err != nil {
+			return "", fmt.Errorf("failed to mark node as switched: %w", err)
+		}
+		return fmt.Sprintf("switching node %s", node.Name), nil
+
+	case nodeNetworkMigrationSwitch:
+		if _, ok := node.Annotations[types.NodeAnnotationNetworkSwitch]; ok {
+			return fmt.Sprintf("waiting for node %s to remove the %s CNI configuration", node.Name, m.migration.from), nil
+		}
+		pods, err := agentPods(ctx, m.client, m.target, node.Name)
+		if err != nil {
+			return "", err
+		}
+		var ready bool
+		for _, pod := range pods {
+			ready = ready || podIsReady(pod)
+		}
+		if !ready {
+			return fmt.Sprintf("waiting for the %s agent on node %s to be ready", m.migration.to, node.Name), nil
+		}
+
+		if err := m.client.DeletePodNetworkPods(ctx, node.Name); err != nil {
+			return "", fmt.Errorf("failed to re-create pods: %w", err)
+		}
+		if err := m.client.CordonNode(ctx, node.Name, false); err != nil {
+			return "", fmt.Errorf("failed to uncordon node: %w", err)
+		}
+		if err := m.client.RemoveNodeAnnotation(ctx, node.Name, types.NodeAnnotationNetworkMigration); err != nil {
+			return "", fmt.Errorf("failed to mark node as done: %w", err)
+		}
+		return fmt.Sprintf("switched node %s", node.Name), nil
+
+	default:
+		return "", fmt.Errorf("unknown network migration step %q", node.Annotations[types.NodeAnnotationNetworkMigration])
+	}
+}
+
+// setPhase records the next phase of the network migration.
+func (m *networkMigrator) setPhase(ctx context.Context, phase string) error {
+	m.migration.phase = phase
+	return setNetworkMigration(ctx, m.client, m.migration)
+}
@@ -0,0 +1,247 @@
+package features
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/canonical/k8s/pkg/client/helm"
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/k8s/pkg/snap"
+	"github.com/canonical/microcluster/v2/state"
+)
+
+// Plugin is a feature that is not built into Canonical Kubernetes, but is deployed from a single Helm chart.
+// Plugins are registered with Register and reconciled by the FeatureController like the built-in features.
+type Plugin struct {
+	// Name is the name of the feature, as used by "k8s enable" and "k8s disable".
+	Name types.FeatureName
+	// Version is the version of the feature that is reported in the feature status.
+	Version string
+	// Chart is the Helm chart that deploys the feature.
+	Chart helm.InstallableChart
+	// Values returns the values for the Helm chart. If nil, the chart is installed with its default values.
+	Values func(context.Context, snap.Snap, types.ClusterConfig) (map[string]any, error)
+	// CheckStatus checks whether the feature is ready. If nil, the feature is ready once the chart is installed.
+	CheckStatus func(context.Context, snap.Snap) error
+	// Dependencies is the list of features that must be enabled before the feature can be enabled.
+	// The feature is deployed after its dependencies report enabled, and removed before them.
+	Dependencies []types.FeatureName
+}
+
+// Feature is a feature that is reconciled by the FeatureController.
+// The built-in features and the plugin features are kept in the same registry.
+type Feature struct {
+	// Name is the name of the feature.
+	Name types.FeatureName
+	// Builtin is true for the built-in features, which are configured through the user-facing cluster configuration.
+	// Plugin features are enabled through the PluginEnabledAnnotation.
+	Builtin bool
+	// Dependencies is the list of features that must be enabled before the feature can be enabled.
+	Dependencies []types.FeatureName
+	// Enabled returns true if the feature is enabled in the cluster configuration.
+	Enabled func(types.ClusterConfig) bool
+	// Changed returns true if the requested change of the cluster configuration affects the feature.
+	// Changes of the values overrides of the feature always affect it.
+	Changed func(types.ClusterConfig) bool
+	// Apply deploys the feature when it is enabled and removes it when it is disabled.
+	// Apply returns the changes to the cluster configuration that result from the deployment, or nil.
+	Apply func(context.Context, snap.Snap, state.State, types.ClusterConfig) (types.FeatureStatus, *types.ClusterConfig, error)
+}
+
+var (
+	registeredFeaturesMu sync.RWMutex
+	registeredFeatures   = map[types.FeatureName]Feature{}
+)
+
+// register adds a feature to the registry.
+// register panics if a feature with the same name is already registered.
+func register(feature Feature) {
+	registeredFeaturesMu.Lock()
+	defer registeredFeaturesMu.Unlock()
+	if _, ok := registeredFeatures[feature.Name]; ok {
+		panic(fmt.Sprintf("feature %q is already registered", feature.Name))
+	}
+	registeredFeatures[feature.Name] = feature
+}
+
+// Register registers a plugin feature.
+// Register is used by the `init()` method in individual packages.
+// Register panics if the plugin is invalid or a feature with the same name is already registered.
+func Register(plugin Plugin) {
+	if plugin.Name == "" {
+		panic("cannot register a feature without a name")
+	}
+	if plugin.Chart.Name == "" || plugin.Chart.Namespace == "" || plugin.Chart.ManifestPath == "" {
+		panic(fmt.Sprintf("cannot register feature %q without a chart", plugin.Name))
+	}
+	if dependsOn(plugin.Dependencies, plugin.Name, map[types.FeatureName]struct{}{}) {
+		panic(fmt.Sprintf("cannot register feature %q, it has a cyclic dependency", plugin.Name))
+	}
+
+	register(Feature{
+		Name:         plugin.Name,
+		Dependencies: plugin.Dependencies,
+		Enabled: func(cfg types.ClusterConfig) bool {
+			return PluginEnabled(cfg, plugin.Name)
+		},
+		Changed: func(cfg types.ClusterConfig) bool {
+			prefix := fmt.Sprintf("k8sd/v1alpha/features/%s/", plugin.Name)
+			for key := range cfg.Annotations {
+				if strings.HasPrefix(key, prefix) {
+					return true
+				}
+			}
+			return false
+		},
+		Apply: func(ctx context.Context, snap snap.Snap, _ state.State, cfg types.ClusterConfig) (types.FeatureStatus, *types.ClusterConfig, error) {
+			status, err := ApplyPlugin(ctx, snap, plugin, cfg)
+			return status, nil, err
+		},
+	})
+}
+
+// Registered returns the registered built-in and plugin features, sorted by name.
+func Registered() []Feature {
+	registeredFeaturesMu.RLock()
+	defer registeredFeaturesMu.RUnlock()
+
+	registered := make([]Feature, 0, len(registeredFeatures))
+	for _, feature := range registeredFeatures {
+		registered = append(registered, feature)
+	}
+	slices.SortFunc(registered, func(a, b Feature) int { return cmp.Compare(a.Name, b.Name) })
+	return registered
+}
+
+// Features returns the names of the registered built-in and plugin features, sorted by name.
+func Features() []types.FeatureName {
+	registered := Registered()
+	names := make([]types.FeatureName, 0, len(registered))
+	for _, feature := range registered {
+		names = append(names, feature.Name)
+	}
+	return names
+}
+
+// PluginNames returns the names of the registered plugin features, sorted by name.
+func PluginNames() []types.FeatureName {
+	var names []types.FeatureName
+	for _, feature := range Registered() {
+		if !feature.Builtin {
+			names = append(names, feature.Name)
+		}
+	}
+	return names
+}
+
+// Get returns the registered feature with the given name.
+func Get(name types.FeatureName) (Feature, bool) {
+	registeredFeaturesMu.RLock()
+	defer registeredFeaturesMu.RUnlock()
+
+	feature, ok := registeredFeatures[name]
+	return feature, ok
+}
+
+// PluginEnabledAnnotation returns the cluster config annotation that controls whether a plugin feature is enabled.
+func PluginEnabledAnnotation(name types.FeatureName) string {
+	return fmt.Sprintf("k8sd/v1alpha/features/%s/enabled", name)
+}
+
+// PluginEnabled returns true if the plugin feature is enabled in the cluster configuration.
+func PluginEnabled(cfg types.ClusterConfig, name types.FeatureName) bool {
+	v, ok := cfg.Annotations.Get(PluginEnabledAnnotation(name))
+	if !ok {
+		return false
+	}
+	enabled, _ := strconv.ParseBool(v)
+	return enabled
+}
+
+// Enabled returns true if the registered feature is enabled in the cluster configuration.
+func Enabled(cfg types.ClusterConfig, name types.FeatureName) bool {
+	feature, ok := Get(name)
+	return ok && feature.Enabled(cfg)
+}
+
+// ApplyPlugin deploys the plugin chart when the plugin is enabled in the cluster configuration.
+// ApplyPlugin removes the plugin chart when the plugin is disabled.
+// ApplyPlugin will always return a FeatureStatus indicating the current status of the deployment.
+// ApplyPlugin returns an error if anything fails, or if a dependency of an enabled plugin is not enabled.
+// The error is also wrapped in the .Message field of the returned FeatureStatus.
+func ApplyPlugin(ctx context.Context, snap snap.Snap, plugin Plugin, cfg types.ClusterConfig) (types.FeatureStatus, error) {
+	enabled := PluginEnabled(cfg, plugin.Name)
+
+	if enabled {
+		for _, dependency := range plugin.Dependencies {
+			if !Enabled(cfg, dependency) {
+				err := fmt.Errorf("feature %q requires %q to be enabled", plugin.Name, dependency)
+				return types.FeatureStatus{
+					Enabled: false,
+					Version: plugin.Version,
+					Message: fmt.Sprintf("Failed to deploy %s, the error was: %v", plugin.Name, err),
+				}, err
+			}
+		}
+	}
+
+	var values map[string]any
+	if enabled && plugin.Values != nil {
+		var err error
+		if values, err = plugin.Values(ctx, snap, cfg); err != nil {
+			err = fmt.Errorf("failed to compute %s chart values: %w", plugin.Name, err)
+			return types.FeatureStatus{
+				Enabled: false,
+				Version: plugin.Version,
+				Message: fmt.Sprintf("Failed to deploy %s, the error was: %v", plugin.Name, err),
+			}, err
+		}
+	}
+
+	if _, err := snap.HelmClient().Apply(ctx, plugin.Chart, helm.StatePresentOrDeleted(enabled), values); err != nil {
+		if enabled {
+			err = fmt.Errorf("failed to install %s chart: %w", plugin.Name, err)
+			return types.FeatureStatus{
+				Enabled: false,
+				Version: plugin.Version,
+				Message: fmt.Sprintf("Failed to deploy %s, the error was: %v", plugin.Name, err),
+			}, err
+		}
+		err = fmt.Errorf("failed to delete %s chart: %w", plugin.Name, err)
+		return types.FeatureStatus{
+			Enabled: false,
+			Version: plugin.Version,
+			Message: fmt.Sprintf("Failed to delete %s, the error was: %v", plugin.Name, err),
+		}, err
+	}
+
+	if !enabled {
+		return types.FeatureStatus{
+			Enabled: false,
+			Version: plugin.Version,
+			Message: "disabled",
+		}, nil
+	}
+
+	if plugin.CheckStatus != nil {
+		if err := plugin.CheckStatus(ctx, snap); err != nil {
+			err = fmt.Errorf("%s is not ready: %w", plugin.Name, err)
+			return types.FeatureStatus{
+				Enabled: false,
+				Version: plugin.Version,
+				Message: fmt.Sprintf("Deployed %s, but it is not ready yet: %v", plugin.Name, err),
+			}, err
+		}
+	}
+
+	return types.FeatureStatus{
+		Enabled: true,
+		Version: plugin.Version,
+		Message: "enabled",
+	}, nil
+}
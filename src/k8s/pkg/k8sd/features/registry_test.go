@@ -0,0 +1,169 @@
+package features_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/canonical/k8s/pkg/client/helm"
+	helmmock "github.com/canonical/k8s/pkg/client/helm/mock"
+	"github.com/canonical/k8s/pkg/k8sd/features"
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/k8s/pkg/snap"
+	snapmock "github.com/canonical/k8s/pkg/snap/mock"
+	"github.com/canonical/k8s/pkg/utils"
+	. "github.com/onsi/gomega"
+)
+
+func testPlugin(name types.FeatureName) features.Plugin {
+	return features.Plugin{
+		Name:    name,
+		Version: "v1.0.0",
+		Chart: helm.InstallableChart{
+			Name:         string(name),
+			Namespace:    "kube-system",
+			ManifestPath: "charts/" + string(name) + "-1.0.0.tgz",
+		},
+	}
+}
+
+func TestRegister(t *testing.T) {
+	g := NewWithT(t)
+
+	features.Register(testPlugin("test-register-b"))
+	features.Register(testPlugin("test-register-a"))
+
+	feature, ok := features.Get("test-register-a")
+	g.Expect(ok).To(BeTrue())
+	g.Expect(feature.Builtin).To(BeFalse())
+	g.Expect(feature.Changed(types.ClusterConfig{
+		Annotations: types.Annotations{features.PluginEnabledAnnotation("test-register-a"): "true"},
+	})).To(BeTrue())
+	g.Expect(feature.Changed(types.ClusterConfig{
+		Annotations: types.Annotations{features.PluginEnabledAnnotation("test-register-b"): "true"},
+	})).To(BeFalse())
+
+	_, ok = features.Get("test-register-missing")
+	g.Expect(ok).To(BeFalse())
+
+	names := features.PluginNames()
+	g.Expect(names).To(ContainElements(types.FeatureName("test-register-a"), types.FeatureName("test-register-b")))
+	g.Expect(names).ToNot(ContainElement(features.Ingress))
+	g.Expect(slices.IsSorted(names)).To(BeTrue())
+
+	all := features.Features()
+	g.Expect(all).To(ContainElements(features.Ingress, types.FeatureName("test-register-a")))
+	g.Expect(slices.IsSorted(all)).To(BeTrue())
+
+	t.Run("Builtin", func(t *testing.T) {
+		g := NewWithT(t)
+		feature, ok := features.Get(features.Ingress)
+		g.Expect(ok).To(BeTrue())
+		g.Expect(feature.Builtin).To(BeTrue())
+		g.Expect(feature.Dependencies).To(ConsistOf(features.Network))
+	})
+
+	t.Run("Duplicate", func(t *testing.T) {
+		g := NewWithT(t)
+		g.Expect(func() { features.Register(testPlugin("test-register-a")) }).To(Panic())
+	})
+
+	t.Run("BuiltIn", func(t *testing.T) {
+		g := NewWithT(t)
+		g.Expect(func() { features.Register(testPlugin(features.Ingress)) }).To(Panic())
+	})
+
+	t.Run("NoChart", func(t *testing.T) {
+		g := NewWithT(t)
+		g.Expect(func() { features.Register(features.Plugin{Name: "test-register-no-chart"}) }).To(Panic())
+	})
+}
+
+func TestApplyPlugin(t *testing.T) {
+	enabledConfig := func(name types.FeatureName) types.ClusterConfig {
+		return types.ClusterConfig{
+			Annotations: types.Annotations{features.PluginEnabledAnnotation(name): "true"},
+		}
+	}
+
+	t.Run("Enabled", func(t *testing.T) {
+		g := NewWithT(t)
+		h := &helmmock.Mock{}
+		s := &snapmock.Snap{Mock: snapmock.Mock{HelmClient: h}}
+
+		plugin := testPlugin("test-apply")
+		plugin.Values = func(_ context.Context, _ snap.Snap, cfg types.ClusterConfig) (map[string]any, error) {
+			return map[string]any{"clusterDomain": cfg.Kubelet.GetClusterDomain()}, nil
+		}
+		cfg := enabledConfig(plugin.Name)
+		cfg.Kubelet.ClusterDomain = utils.Pointer("cluster.local")
+
+		status, err := features.ApplyPlugin(context.Background(), s, plugin, cfg)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(status.Enabled).To(BeTrue())
+		g.Expect(status.Version).To(Equal("v1.0.0"))
+		g.Expect(h.ApplyCalledWith).To(ConsistOf(SatisfyAll(
+			HaveField("Chart", Equal(plugin.Chart)),
+			HaveField("State", Equal(helm.StatePresent)),
+			HaveField("Values", HaveKeyWithValue("clusterDomain", "cluster.local")),
+		)))
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		g := NewWithT(t)
+		h := &helmmock.Mock{}
+		s := &snapmock.Snap{Mock: snapmock.Mock{HelmClient: h}}
+
+		plugin := testPlugin("test-apply")
+		status, err := features.ApplyPlugin(context.Background(), s, plugin, types.ClusterConfig{})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(status.Enabled).To(BeFalse())
+		g.Expect(h.ApplyCalledWith).To(ConsistOf(HaveField("State", Equal(helm.StateDeleted))))
+	})
+
+	t.Run("MissingDependency", func(t *testing.T) {
+		g := NewWithT(t)
+		h := &helmmock.Mock{}
+		s := &snapmock.Snap{Mock: snapmock.Mock{HelmClient: h}}
+
+		plugin := testPlugin("test-apply")
+		plugin.Dependencies = []types.FeatureName{features.Network}
+
+		status, err := features.ApplyPlugin(context.Background(), s, plugin, enabledConfig(plugin.Name))
+		g.Expect(err).To(MatchError(ContainSubstring(`requires "network"`)))
+		g.Expect(status.Enabled).To(BeFalse())
+		g.Expect(h.ApplyCalledWith).To(BeEmpty())
+
+		cfg := enabledConfig(plugin.Name)
+		cfg.Network.Enabled = utils.Pointer(true)
+		status, err = features.ApplyPlugin(context.Background(), s, plugin, cfg)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(status.Enabled).To(BeTrue())
+	})
+
+	t.Run("HelmError", func(t *testing.T) {
+		g := NewWithT(t)
+		h := &helmmock.Mock{ApplyErr: errors.New("failed to apply")}
+		s := &snapmock.Snap{Mock: snapmock.Mock{HelmClient: h}}
+
+		plugin := testPlugin("test-apply")
+		status, err := features.ApplyPlugin(context.Background(), s, plugin, enabledConfig(plugin.Name))
+		g.Expect(err).To(MatchError(ContainSubstring("failed to apply")))
+		g.Expect(status.Enabled).To(BeFalse())
+		g.Expect(status.Message).To(ContainSubstring("failed to apply"))
+	})
+
+	t.Run("NotReady", func(t *testing.T) {
+		g := NewWithT(t)
+		h := &helmmock.Mock{}
+		s := &snapmock.Snap{Mock: snapmock.Mock{HelmClient: h}}
+
+		plugin := testPlugin("test-apply")
+		plugin.CheckStatus = func(context.Context, snap.Snap) error { return errors.New("pods not ready") }
+
+		status, err := features.ApplyPlugin(context.Background(), s, plugin, enabledConfig(plugin.Name))
+		g.Expect(err).To(MatchError(ContainSubstring("pods not ready")))
+		g.Expect(status.Enabled).To(BeFalse())
+	})
+}
@@ -12,12 +12,15 @@ type StatusInterface interface {
 	CheckDNS(context.Context, snap.Snap) error
 	// CheckNetwork checks the status of the Network feature.
 	CheckNetwork(context.Context, snap.Snap) error
+	// CheckCertManager checks the status of the cert-manager feature.
+	CheckCertManager(context.Context, snap.Snap) error
 }
 
 // statusChecks implements the StatusInterface.
 type statusChecks struct {
-	checkDNS     func(context.Context, snap.Snap) error
-	checkNetwork func(context.Context, snap.Snap) error
+	checkDNS         func(context.Context, snap.Snap) error
+	checkNetwork     func(context.Context, snap.Snap) error
+	checkCertManager func(context.Context, snap.Snap) error
 }
 
 func (s *statusChecks) CheckDNS(ctx context.Context, snap snap.Snap) error {
@@ -27,3 +30,7 @@ func (s *statusChecks) CheckDNS(ctx context.Context, snap snap.Snap) error {
 func (s *statusChecks) CheckNetwork(ctx context.Context, snap snap.Snap) error {
 	return s.checkNetwork(ctx, snap)
 }
+
+func (s *statusChecks) CheckCertManager(ctx context.Context, snap snap.Snap) error {
+	return s.checkCertManager(ctx, snap)
+}
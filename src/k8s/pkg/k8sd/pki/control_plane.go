@@ -1,9 +1,13 @@
 package pki
 
 import (
+	"crypto"
+	"crypto/x509"
 	"crypto/x509/pkix"
+	"encoding/pem"
 	"fmt"
 	"net"
+	"strings"
 	"time"
 
 	pkiutil "github.com/canonical/k8s/pkg/utils/pki"
@@ -11,13 +15,16 @@ import (
 
 // ControlPlanePKI is a list of all certificates we require for a control plane node.
 type ControlPlanePKI struct {
-	allowSelfSignedCA         bool      // create self-signed CA certificates if missing
-	includeMachineAddressSANs bool      // include any machine IP addresses as SANs for generated certificates
-	hostname                  string    // node name
-	ipSANs                    []net.IP  // IP SANs for generated certificates
-	dnsSANs                   []string  // DNS SANs for the certificates below
-	notBefore                 time.Time // not before date for the certificates
-	notAfter                  time.Time // not after (expiration date) for the certificates
+	allowSelfSignedCA         bool                 // create self-signed CA certificates if missing
+	includeMachineAddressSANs bool                 // include any machine IP addresses as SANs for generated certificates
+	hostname                  string               // node name
+	ipSANs                    []net.IP             // IP SANs for generated certificates
+	dnsSANs                   []string             // DNS SANs for the certificates below
+	notBefore                 time.Time            // not before date for the certificates
+	notAfter                  time.Time            // not after (expiration date) for the certificates
+	keyAlgorithm              pkiutil.KeyAlgorithm // algorithm of the generated private keys
+	caSigner                  crypto.Signer        // signer for kubernetes-ca, used if CAKey is not set
+	clientCASigner            crypto.Signer        // signer for kubernetes-ca-client, used if ClientCAKey is not set
 
 	CACert, CAKey                             string // CN=kubernetes-ca (self-signed)
 	ClientCACert, ClientCAKey                 string // CN=kubernetes-ca-client (self-signed)
@@ -61,6 +68,11 @@ type ControlPlanePKIOpts struct {
 	NotAfter                  time.Time
 	AllowSelfSignedCA         bool
 	IncludeMachineAddressSANs bool
+	KeyAlgorithm              pkiutil.KeyAlgorithm
+	// CASigner and ClientCASigner sign certificates with CA keys that are kept outside of k8sd (e.g. in an HSM).
+	// They are only used if the respective CA key is not set.
+	CASigner       crypto.Signer
+	ClientCASigner crypto.Signer
 }
 
 func NewControlPlanePKI(opts ControlPlanePKIOpts) *ControlPlanePKI {
@@ -77,6 +89,9 @@ func NewControlPlanePKI(opts ControlPlanePKIOpts) *ControlPlanePKI {
 		dnsSANs:                   opts.DNSSANs,
 		allowSelfSignedCA:         opts.AllowSelfSignedCA,
 		includeMachineAddressSANs: opts.IncludeMachineAddressSANs,
+		keyAlgorithm:              opts.KeyAlgorithm,
+		caSigner:                  opts.CASigner,
+		clientCASigner:            opts.ClientCASigner,
 	}
 }
 
@@ -112,7 +127,7 @@ func (c *ControlPlanePKI) CompleteCertificates() error {
 		if !c.allowSelfSignedCA {
 			return fmt.Errorf("kubernetes CA not specified and generating self-signed CA not allowed")
 		}
-		cert, key, err := pkiutil.GenerateSelfSignedCA(pkix.Name{CommonName: "kubernetes-ca"}, c.notBefore, c.notAfter, 2048)
+		cert, key, err := pkiutil.GenerateSelfSignedCA(pkix.Name{CommonName: "kubernetes-ca"}, c.notBefore, c.notAfter, c.keyAlgorithm)
 		if err != nil {
 			return fmt.Errorf("failed to generate kubernetes CA: %w", err)
 		}
@@ -123,6 +138,23 @@ func (c *ControlPlanePKI) CompleteCertificates() error {
 		if err := certCheck.ValidateKeypair(c.CACert, c.CAKey); err != nil {
 			return fmt.Errorf("kubernetes CA certificate validation failure: %w", err)
 		}
+		if err := pkiutil.VerifyCertificateChain(c.CACert); err != nil {
+			return fmt.Errorf("kubernetes CA certificate chain validation failure: %w", err)
+		}
+	}
+
+	// The intermediate CAs of the chain are included in the kube-apiserver certificate. The root CA is never
+	// served, and it is never trusted for client certificates.
+	intermediateCA, caChain, err := intermediateCAChain(c.CACert)
+	if err != nil {
+		return fmt.Errorf("failed to parse kubernetes CA: %w", err)
+	}
+
+	// Client certificates are issued by an intermediate kubernetes CA as well, unless a client CA is set.
+	// Only the intermediate CA is trusted, so that certificates issued by the root CA are rejected.
+	if intermediateCA != "" && c.CAKey != "" && c.ClientCACert == "" && c.ClientCAKey == "" {
+		c.ClientCACert = intermediateCA
+		c.ClientCAKey = c.CAKey
 	}
 
 	// Generate self-signed client CA (if not set already)
@@ -130,7 +162,7 @@ func (c *ControlPlanePKI) CompleteCertificates() error {
 		if !c.allowSelfSignedCA {
 			return fmt.Errorf("kubernetes client CA not specified and generating self-signed CA not allowed")
 		}
-		cert, key, err := pkiutil.GenerateSelfSignedCA(pkix.Name{CommonName: "kubernetes-ca-client"}, c.notBefore, c.notAfter, 2048)
+		cert, key, err := pkiutil.GenerateSelfSignedCA(pkix.Name{CommonName: "kubernetes-ca-client"}, c.notBefore, c.notAfter, c.keyAlgorithm)
 		if err != nil {
 			return fmt.Errorf("failed to generate kubernetes client CA: %w", err)
 		}
@@ -141,16 +173,17 @@ func (c *ControlPlanePKI) CompleteCertificates() error {
 		if err := certCheck.ValidateKeypair(c.ClientCACert, c.ClientCAKey); err != nil {
 			return fmt.Errorf("kubernetes client CA certificate validation failure: %w", err)
 		}
+		// NOTE: The chain of the intermediate kubernetes CA is verified above.
+		if c.ClientCACert != intermediateCA {
+			if err := pkiutil.VerifyCertificateChain(c.ClientCACert); err != nil {
+				return fmt.Errorf("kubernetes client CA certificate chain validation failure: %w", err)
+			}
+		}
 	}
 
-	serverCACert, serverCAKey, err := pkiutil.LoadCertificate(c.CACert, c.CAKey)
-	if err != nil {
-		return fmt.Errorf("failed to parse kubernetes CA: %w", err)
-	}
-
-	clientCACert, clientCAKey, err := pkiutil.LoadCertificate(c.ClientCACert, c.ClientCAKey)
+	serverCACert, serverCAKey, clientCACert, clientCAKey, err := c.loadCAs()
 	if err != nil {
-		return fmt.Errorf("failed to parse kubernetes CA: %w", err)
+		return err
 	}
 
 	// Generate self-signed CA for front-proxy (if not set already)
@@ -158,7 +191,7 @@ func (c *ControlPlanePKI) CompleteCertificates() error {
 		if !c.allowSelfSignedCA {
 			return fmt.Errorf("front-proxy CA not specified and generating self-signed CA not allowed")
 		}
-		cert, key, err := pkiutil.GenerateSelfSignedCA(pkix.Name{CommonName: "front-proxy-ca"}, c.notBefore, c.notAfter, 2048)
+		cert, key, err := pkiutil.GenerateSelfSignedCA(pkix.Name{CommonName: "front-proxy-ca"}, c.notBefore, c.notAfter, c.keyAlgorithm)
 		if err != nil {
 			return fmt.Errorf("failed to generate front-proxy CA: %w", err)
 		}
@@ -169,6 +202,9 @@ func (c *ControlPlanePKI) CompleteCertificates() error {
 		if err := certCheck.ValidateKeypair(c.FrontProxyCACert, c.FrontProxyCAKey); err != nil {
 			return fmt.Errorf("kubernetes front-proxy CA certificate validation failure: %w", err)
 		}
+		if err := pkiutil.VerifyCertificateChain(c.FrontProxyCACert); err != nil {
+			return fmt.Errorf("kubernetes front-proxy CA certificate chain validation failure: %w", err)
+		}
 	}
 
 	// Generate front proxy client certificate (ok to override)
@@ -185,7 +221,7 @@ func (c *ControlPlanePKI) CompleteCertificates() error {
 		if err != nil {
 			return fmt.Errorf("failed to generate front-proxy-client certificate: %w", err)
 		}
-		cert, key, err := pkiutil.SignCertificate(template, 2048, frontProxyCACert, &frontProxyCAKey.PublicKey, frontProxyCAKey)
+		cert, key, err := pkiutil.SignCertificate(template, c.keyAlgorithm, frontProxyCACert, frontProxyCAKey)
 		if err != nil {
 			return fmt.Errorf("failed to sign front-proxy-client certificate: %w", err)
 		}
@@ -226,7 +262,7 @@ func (c *ControlPlanePKI) CompleteCertificates() error {
 		if err != nil {
 			return fmt.Errorf("failed to generate kubelet certificate: %w", err)
 		}
-		cert, key, err := pkiutil.SignCertificate(template, 2048, serverCACert, &serverCAKey.PublicKey, serverCAKey)
+		cert, key, err := pkiutil.SignCertificate(template, c.keyAlgorithm, serverCACert, serverCAKey)
 		if err != nil {
 			return fmt.Errorf("failed to sign kubelet certificate: %w", err)
 		}
@@ -253,7 +289,7 @@ func (c *ControlPlanePKI) CompleteCertificates() error {
 		if err != nil {
 			return fmt.Errorf("failed to generate apiserver-kubelet-client certificate: %w", err)
 		}
-		cert, key, err := pkiutil.SignCertificate(template, 2048, clientCACert, &clientCAKey.PublicKey, clientCAKey)
+		cert, key, err := pkiutil.SignCertificate(template, c.keyAlgorithm, clientCACert, clientCAKey)
 		if err != nil {
 			return fmt.Errorf("failed to sign apiserver-kubelet-client certificate: %w", err)
 		}
@@ -284,12 +320,12 @@ func (c *ControlPlanePKI) CompleteCertificates() error {
 		if err != nil {
 			return fmt.Errorf("failed to generate apiserver certificate: %w", err)
 		}
-		cert, key, err := pkiutil.SignCertificate(template, 2048, serverCACert, &serverCAKey.PublicKey, serverCAKey)
+		cert, key, err := pkiutil.SignCertificate(template, c.keyAlgorithm, serverCACert, serverCAKey)
 		if err != nil {
 			return fmt.Errorf("failed to sign apiserver certificate: %w", err)
 		}
 
-		c.APIServerCert = cert
+		c.APIServerCert = cert + caChain
 		c.APIServerKey = key
 	} else {
 		certCheck := pkiutil.CertCheck{
@@ -324,7 +360,7 @@ func (c *ControlPlanePKI) CompleteCertificates() error {
 				return fmt.Errorf("failed to generate %s client certificate: %w", i.name, err)
 			}
 
-			cert, key, err := pkiutil.SignCertificate(template, 2048, clientCACert, &clientCAKey.PublicKey, clientCAKey)
+			cert, key, err := pkiutil.SignCertificate(template, c.keyAlgorithm, clientCACert, clientCAKey)
 			if err != nil {
 				return fmt.Errorf("failed to sign %s client certificate: %w", i.name, err)
 			}
@@ -351,3 +387,46 @@ func (c *ControlPlanePKI) CompleteCertificates() error {
 
 	return nil
 }
+
+// intermediateCAChain returns the certificate of an intermediate CA, and the chain of intermediate CA certificates
+// that is appended to serving certificates. Self-signed root certificates are not part of the chain, as clients must
+// already trust them.
+// intermediateCAChain returns empty strings if the CA is self-signed.
+func intermediateCAChain(caPEM string) (string, string, error) {
+	certs, err := pkiutil.LoadCertificateChain(caPEM)
+	if err != nil {
+		return "", "", err
+	}
+	if pkiutil.IsSelfSigned(certs[0]) {
+		return "", "", nil
+	}
+
+	var chain strings.Builder
+	for _, cert := range certs {
+		if !pkiutil.IsSelfSigned(cert) {
+			chain.Write(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}))
+		}
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certs[0].Raw})), chain.String(), nil
+}
+
+// loadCAs parses the kubernetes CA and client CA. The CA signers are used for CAs without a private key.
+func (c *ControlPlanePKI) loadCAs() (*x509.Certificate, crypto.Signer, *x509.Certificate, crypto.Signer, error) {
+	serverCACert, serverCAKey, err := pkiutil.LoadCertificate(c.CACert, c.CAKey)
+	if err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("failed to parse kubernetes CA: %w", err)
+	}
+	if serverCAKey == nil {
+		serverCAKey = c.caSigner
+	}
+
+	clientCACert, clientCAKey, err := pkiutil.LoadCertificate(c.ClientCACert, c.ClientCAKey)
+	if err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("failed to parse kubernetes client CA: %w", err)
+	}
+	if clientCAKey == nil {
+		clientCAKey = c.clientCASigner
+	}
+
+	return serverCACert, serverCAKey, clientCACert, clientCAKey, nil
+}
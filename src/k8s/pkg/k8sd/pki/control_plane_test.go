@@ -2,6 +2,8 @@ package pki_test
 
 import (
 	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/sha256"
@@ -64,7 +66,7 @@ func patchCertPEM(
 		return "", "", fmt.Errorf("failed to load CA cert: %w", err)
 	}
 
-	certPem, keyPem, err := pkiutil.SignCertificate(template, 2048, caCert, &caCert.PublicKey, caKey)
+	certPem, keyPem, err := pkiutil.SignCertificate(template, pkiutil.KeyAlgorithmRSA2048, caCert, caKey)
 	if err != nil {
 		return "", "", fmt.Errorf("failed to sign cert: %w", err)
 	}
@@ -290,3 +292,175 @@ func TestControlPlaneCertificates(t *testing.T) {
 		g.Expect(err).To(MatchError(MatchRegexp(`certificate dns name \(.*\) validation failure`)))
 	})
 }
+
+func TestControlPlaneCertificatesKeyAlgorithm(t *testing.T) {
+	for _, tc := range []struct {
+		algorithm pkiutil.KeyAlgorithm
+		keyType   any
+	}{
+		{algorithm: pkiutil.KeyAlgorithmECDSAP256, keyType: &ecdsa.PrivateKey{}},
+		{algorithm: pkiutil.KeyAlgorithmEd25519, keyType: ed25519.PrivateKey{}},
+	} {
+		t.Run(string(tc.algorithm), func(t *testing.T) {
+			g := NewWithT(t)
+
+			notBefore := time.Now()
+			c := pki.NewControlPlanePKI(pki.ControlPlanePKIOpts{
+				Hostname:          "h1",
+				NotBefore:         notBefore,
+				NotAfter:          notBefore.AddDate(1, 0, 0),
+				AllowSelfSignedCA: true,
+				KeyAlgorithm:      tc.algorithm,
+			})
+			g.Expect(c.CompleteCertificates()).To(Succeed())
+			g.Expect(c.CompleteCertificates()).To(Succeed())
+
+			for _, keyPEM := range []string{c.CAKey, c.ClientCAKey, c.FrontProxyCAKey, c.APIServerKey, c.KubeletKey, c.AdminClientKey} {
+				key, err := pkiutil.LoadPrivateKey(keyPEM)
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(key).To(BeAssignableToTypeOf(tc.keyType))
+			}
+			g.Expect(pkiutil.CertCheck{CN: "kube-apiserver", CaPEM: c.CACert}.ValidateKeypair(c.APIServerCert, c.APIServerKey)).To(Succeed())
+
+			// NOTE: The k8sd and service account keys are always RSA keys.
+			_, err := pkiutil.LoadRSAPrivateKey(c.K8sdPrivateKey)
+			g.Expect(err).ToNot(HaveOccurred())
+			_, err = pkiutil.LoadRSAPrivateKey(c.ServiceAccountKey)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			worker, err := c.CompleteWorkerNodePKI("w1", net.IP{10, 0, 0, 2}, tc.algorithm)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(pkiutil.CertCheck{CN: "system:node:w1", CaPEM: c.CACert}.ValidateKeypair(worker.KubeletCert, worker.KubeletKey)).To(Succeed())
+			g.Expect(pkiutil.CertCheck{CN: "system:kube-proxy", CaPEM: c.ClientCACert}.ValidateKeypair(worker.KubeProxyClientCert, worker.KubeProxyClientKey)).To(Succeed())
+		})
+	}
+}
+
+func TestControlPlaneCertificatesIntermediateCA(t *testing.T) {
+	g := NewWithT(t)
+	notBefore := time.Now()
+	notAfter := notBefore.AddDate(1, 0, 0)
+
+	rootPEM, rootKeyPEM, err := pkiutil.GenerateSelfSignedCA(pkix.Name{CommonName: "root-ca"}, notBefore, notAfter, pkiutil.KeyAlgorithmRSA2048)
+	g.Expect(err).ToNot(HaveOccurred())
+	rootCert, rootKey, err := pkiutil.LoadCertificate(rootPEM, rootKeyPEM)
+	g.Expect(err).ToNot(HaveOccurred())
+	template, err := pkiutil.GenerateCertificate(pkix.Name{CommonName: "intermediate-ca"}, notBefore, notAfter, true, nil, nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	intermediatePEM, intermediateKeyPEM, err := pkiutil.SignCertificate(template, pkiutil.KeyAlgorithmRSA2048, rootCert, rootKey)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	newPKI := func() *pki.ControlPlanePKI {
+		c := pki.NewControlPlanePKI(pki.ControlPlanePKIOpts{
+			Hostname:  "h1",
+			NotBefore: notBefore,
+			NotAfter:  notAfter,
+			// NOTE: The front-proxy CA is not issued by the intermediate CA.
+			AllowSelfSignedCA: true,
+		})
+		c.CACert = intermediatePEM + rootPEM
+		c.CAKey = intermediateKeyPEM
+		return c
+	}
+
+	t.Run("IssuesFromIntermediate", func(t *testing.T) {
+		g := NewWithT(t)
+
+		c := newPKI()
+		g.Expect(c.CompleteCertificates()).To(Succeed())
+
+		// The client CA defaults to the intermediate CA, without the root CA.
+		g.Expect(c.ClientCACert).To(Equal(intermediatePEM))
+		g.Expect(c.ClientCAKey).To(Equal(c.CAKey))
+
+		// The kube-apiserver certificate includes the intermediate CA, but not the root CA.
+		chain, err := pkiutil.LoadCertificateChain(c.APIServerCert)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(chain).To(HaveLen(2))
+		g.Expect(chain[0].Subject.CommonName).To(Equal("kube-apiserver"))
+		g.Expect(chain[1].Subject.CommonName).To(Equal("intermediate-ca"))
+
+		roots := x509.NewCertPool()
+		roots.AddCert(rootCert)
+		intermediates := x509.NewCertPool()
+		intermediates.AddCert(chain[1])
+		_, err = chain[0].Verify(x509.VerifyOptions{Roots: roots, Intermediates: intermediates, DNSName: "kubernetes.default"})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		g.Expect(pkiutil.CertCheck{CN: "kubernetes-admin", CaPEM: rootPEM}.ValidateKeypair(c.AdminClientCert, c.AdminClientKey)).ToNot(Succeed())
+		g.Expect(pkiutil.CertCheck{CN: "kubernetes-admin", CaPEM: c.ClientCACert}.ValidateKeypair(c.AdminClientCert, c.AdminClientKey)).To(Succeed())
+
+		// Client certificates issued by the root CA are rejected.
+		template, err := pkiutil.GenerateCertificate(pkix.Name{CommonName: "root-admin", Organization: []string{"system:masters"}}, notBefore, notAfter, false, nil, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+		rootClientPEM, rootClientKeyPEM, err := pkiutil.SignCertificate(template, pkiutil.KeyAlgorithmRSA2048, rootCert, rootKey)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(pkiutil.CertCheck{CN: "root-admin", CaPEM: c.ClientCACert}.ValidateKeypair(rootClientPEM, rootClientKeyPEM)).ToNot(Succeed())
+
+		clientCAs, err := pkiutil.LoadCertificateChain(c.ClientCACert)
+		g.Expect(err).ToNot(HaveOccurred())
+		clientRoots := x509.NewCertPool()
+		for _, cert := range clientCAs {
+			clientRoots.AddCert(cert)
+		}
+		rootClientCert, _, err := pkiutil.LoadCertificate(rootClientPEM, "")
+		g.Expect(err).ToNot(HaveOccurred())
+		_, err = rootClientCert.Verify(x509.VerifyOptions{Roots: clientRoots, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}})
+		g.Expect(err).To(HaveOccurred())
+
+		adminCert, _, err := pkiutil.LoadCertificate(c.AdminClientCert, "")
+		g.Expect(err).ToNot(HaveOccurred())
+		_, err = adminCert.Verify(x509.VerifyOptions{Roots: clientRoots, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		// Completing the certificates again keeps them unchanged.
+		apiServerCert := c.APIServerCert
+		g.Expect(c.CompleteCertificates()).To(Succeed())
+		g.Expect(c.APIServerCert).To(Equal(apiServerCert))
+	})
+
+	t.Run("MissingRoot", func(t *testing.T) {
+		g := NewWithT(t)
+
+		c := newPKI()
+		c.CACert = intermediatePEM
+		g.Expect(c.CompleteCertificates()).To(MatchError(ContainSubstring("chain validation failure")))
+	})
+}
+
+func TestControlPlaneCertificatesCASigner(t *testing.T) {
+	g := NewWithT(t)
+	notBefore := time.Now()
+
+	caPEM, caKeyPEM, err := pkiutil.GenerateSelfSignedCA(pkix.Name{CommonName: "kubernetes-ca"}, notBefore, notBefore.AddDate(1, 0, 0), pkiutil.KeyAlgorithmECDSAP256)
+	g.Expect(err).ToNot(HaveOccurred())
+	caKey, err := pkiutil.LoadPrivateKey(caKeyPEM)
+	g.Expect(err).ToNot(HaveOccurred())
+	clientCAPEM, clientCAKeyPEM, err := pkiutil.GenerateSelfSignedCA(pkix.Name{CommonName: "kubernetes-ca-client"}, notBefore, notBefore.AddDate(1, 0, 0), pkiutil.KeyAlgorithmECDSAP256)
+	g.Expect(err).ToNot(HaveOccurred())
+	clientCAKey, err := pkiutil.LoadPrivateKey(clientCAKeyPEM)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// NOTE: The CA keys are only available through the signers, e.g. when they are kept in an HSM.
+	c := pki.NewControlPlanePKI(pki.ControlPlanePKIOpts{
+		Hostname:          "h1",
+		NotBefore:         notBefore,
+		NotAfter:          notBefore.AddDate(1, 0, 0),
+		AllowSelfSignedCA: true,
+		CASigner:          caKey,
+		ClientCASigner:    clientCAKey,
+	})
+	c.CACert = caPEM
+	c.ClientCACert = clientCAPEM
+	g.Expect(c.CompleteCertificates()).To(Succeed())
+
+	g.Expect(c.CAKey).To(BeEmpty())
+	g.Expect(c.ClientCAKey).To(BeEmpty())
+	g.Expect(pkiutil.CertCheck{CN: "kube-apiserver", CaPEM: caPEM}.ValidateKeypair(c.APIServerCert, c.APIServerKey)).To(Succeed())
+	g.Expect(pkiutil.CertCheck{CN: "kubernetes-admin", CaPEM: clientCAPEM}.ValidateKeypair(c.AdminClientCert, c.AdminClientKey)).To(Succeed())
+
+	worker, err := c.CompleteWorkerNodePKI("w1", net.IP{10, 0, 0, 2}, pkiutil.KeyAlgorithmECDSAP256)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(pkiutil.CertCheck{CN: "system:node:w1", CaPEM: caPEM}.ValidateKeypair(worker.KubeletCert, worker.KubeletKey)).To(Succeed())
+}
@@ -0,0 +1,149 @@
+package pki
+
+import (
+	"crypto/x509/pkix"
+	"fmt"
+	"net"
+	"time"
+
+	pkiutil "github.com/canonical/k8s/pkg/utils/pki"
+)
+
+// EtcdPKI is a list of certificates required by the managed etcd datastore.
+type EtcdPKI struct {
+	allowSelfSignedCA bool                 // create self-signed CA certificates if missing
+	hostname          string               // node name
+	ipSANs            []net.IP             // IP SANs for generated certificates
+	dnsSANs           []string             // DNS SANs for the certificates below
+	notBefore         time.Time            // notBefore date for the generated certificates
+	notAfter          time.Time            // not after date (expiration date) for the generated certificates
+	keyAlgorithm      pkiutil.KeyAlgorithm // algorithm of the generated private keys
+
+	// CN=etcd-ca (self-signed)
+	CACert, CAKey string
+
+	// CN=hostname, DNS=hostname, IP=127.0.0.1 (signed by etcd-ca)
+	ServerCert, ServerKey string
+
+	// CN=hostname, DNS=hostname, IP=127.0.0.1 (signed by etcd-ca)
+	PeerCert, PeerKey string
+
+	// CN=kube-apiserver-etcd-client (signed by etcd-ca)
+	APIServerClientCert, APIServerClientKey string
+}
+
+type EtcdPKIOpts struct {
+	Hostname          string
+	DNSSANs           []string
+	IPSANs            []net.IP
+	NotBefore         time.Time
+	NotAfter          time.Time
+	AllowSelfSignedCA bool
+	KeyAlgorithm      pkiutil.KeyAlgorithm
+}
+
+func NewEtcdPKI(opts EtcdPKIOpts) *EtcdPKI {
+	// NOTE: Default NotAfter is 1 year from the NotBefore date
+	if opts.NotAfter.IsZero() {
+		opts.NotAfter = opts.NotBefore.AddDate(1, 0, 0)
+	}
+
+	return &EtcdPKI{
+		allowSelfSignedCA: opts.AllowSelfSignedCA,
+		hostname:          opts.Hostname,
+		notBefore:         opts.NotBefore,
+		notAfter:          opts.NotAfter,
+		ipSANs:            opts.IPSANs,
+		dnsSANs:           opts.DNSSANs,
+		keyAlgorithm:      opts.KeyAlgorithm,
+	}
+}
+
+// CompleteCertificates generates missing or unset certificates.
+// The etcd CA is shared by all members of the cluster, while the server, peer and client certificates are generated for each node.
+func (c *EtcdPKI) CompleteCertificates() error {
+	// Fail hard if keys of self-signed certificates are set without the respective certificates
+	switch {
+	case c.CACert == "" && c.CAKey != "":
+		return fmt.Errorf("etcd CA key set without a certificate, fail to prevent further issues")
+	case c.CACert != "" && c.CAKey == "":
+		return fmt.Errorf("etcd CA certificate set without a key, fail to prevent further issues")
+	}
+
+	// Generate self-signed CA (if not set already)
+	if c.CACert == "" && c.CAKey == "" {
+		if !c.allowSelfSignedCA {
+			return fmt.Errorf("etcd CA not specified and generating self-signed CA not allowed")
+		}
+		cert, key, err := pkiutil.GenerateSelfSignedCA(pkix.Name{CommonName: "etcd-ca"}, c.notBefore, c.notAfter, c.keyAlgorithm)
+		if err != nil {
+			return fmt.Errorf("failed to generate etcd CA: %w", err)
+		}
+		c.CACert = cert
+		c.CAKey = key
+	} else {
+		certCheck := pkiutil.CertCheck{AllowSelfSigned: true}
+		if err := certCheck.ValidateKeypair(c.CACert, c.CAKey); err != nil {
+			return fmt.Errorf("etcd CA certificate validation failure: %w", err)
+		}
+	}
+
+	caCert, caKey, err := pkiutil.LoadCertificate(c.CACert, c.CAKey)
+	if err != nil {
+		return fmt.Errorf("failed to parse etcd CA: %w", err)
+	}
+
+	for _, i := range []struct {
+		name    string
+		cn      string
+		dnsSANs []string
+		ipSANs  []net.IP
+		cert    *string
+		key     *string
+	}{
+		{
+			name:    "etcd server",
+			cn:      c.hostname,
+			dnsSANs: append([]string{c.hostname}, c.dnsSANs...),
+			ipSANs:  append(append([]net.IP{}, c.ipSANs...), net.ParseIP("127.0.0.1"), net.ParseIP("::1")),
+			cert:    &c.ServerCert,
+			key:     &c.ServerKey,
+		},
+		{
+			name:    "etcd peer",
+			cn:      c.hostname,
+			dnsSANs: append([]string{c.hostname}, c.dnsSANs...),
+			ipSANs:  append(append([]net.IP{}, c.ipSANs...), net.ParseIP("127.0.0.1"), net.ParseIP("::1")),
+			cert:    &c.PeerCert,
+			key:     &c.PeerKey,
+		},
+		{
+			name: "kube-apiserver-etcd-client",
+			cn:   "kube-apiserver-etcd-client",
+			cert: &c.APIServerClientCert,
+			key:  &c.APIServerClientKey,
+		},
+	} {
+		if *i.cert != "" && *i.key != "" {
+			certCheck := pkiutil.CertCheck{CN: i.cn, CaPEM: c.CACert}
+			if err := certCheck.ValidateKeypair(*i.cert, *i.key); err != nil {
+				return fmt.Errorf("%s certificate validation failure: %w", i.name, err)
+			}
+			continue
+		}
+
+		template, err := pkiutil.GenerateCertificate(pkix.Name{CommonName: i.cn}, c.notBefore, c.notAfter, false, i.dnsSANs, i.ipSANs)
+		if err != nil {
+			return fmt.Errorf("failed to generate %s certificate: %w", i.name, err)
+		}
+		cert, key, err := pkiutil.SignCertificate(template, c.keyAlgorithm, caCert, caKey)
+		if err != nil {
+			return fmt.Errorf("failed to sign %s certificate: %w", i.name, err)
+		}
+
+		*i.cert = cert
+		*i.key = key
+	}
+
+	return nil
+}
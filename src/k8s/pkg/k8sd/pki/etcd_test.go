@@ -0,0 +1,99 @@
+package pki_test
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/canonical/k8s/pkg/k8sd/pki"
+	pkiutil "github.com/canonical/k8s/pkg/utils/pki"
+	. "github.com/onsi/gomega"
+)
+
+func TestEtcdPKI_CompleteCertificates(t *testing.T) {
+	notBefore := time.Now()
+	opts := pki.EtcdPKIOpts{
+		Hostname:          "h1",
+		IPSANs:            []net.IP{net.ParseIP("192.168.2.123")},
+		NotBefore:         notBefore,
+		NotAfter:          notBefore.AddDate(1, 0, 0),
+		AllowSelfSignedCA: true,
+	}
+
+	t.Run("SelfSigned", func(t *testing.T) {
+		g := NewWithT(t)
+
+		c := pki.NewEtcdPKI(opts)
+		g.Expect(c.CompleteCertificates()).To(Succeed())
+		g.Expect(c.CompleteCertificates()).To(Succeed())
+
+		for _, cert := range []struct {
+			cert string
+			key  string
+			cn   string
+		}{
+			{cert: c.ServerCert, key: c.ServerKey, cn: "h1"},
+			{cert: c.PeerCert, key: c.PeerKey, cn: "h1"},
+			{cert: c.APIServerClientCert, key: c.APIServerClientKey, cn: "kube-apiserver-etcd-client"},
+		} {
+			certCheck := pkiutil.CertCheck{CN: cert.cn, CaPEM: c.CACert}
+			g.Expect(certCheck.ValidateKeypair(cert.cert, cert.key)).To(Succeed())
+		}
+
+		block, _ := pem.Decode([]byte(c.PeerCert))
+		g.Expect(block).ToNot(BeNil())
+		cert, err := x509.ParseCertificate(block.Bytes)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(cert.DNSNames).To(ConsistOf("h1"))
+		g.Expect(cert.IPAddresses).To(ConsistOf(net.ParseIP("192.168.2.123").To4(), net.ParseIP("127.0.0.1").To4(), net.ParseIP("::1")))
+		g.Expect(cert.ExtKeyUsage).To(ContainElements(x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth))
+	})
+
+	t.Run("SharedCA", func(t *testing.T) {
+		g := NewWithT(t)
+
+		first := pki.NewEtcdPKI(opts)
+		g.Expect(first.CompleteCertificates()).To(Succeed())
+
+		// joining nodes only receive the CA and generate their own certificates
+		second := pki.NewEtcdPKI(pki.EtcdPKIOpts{Hostname: "h2", NotBefore: notBefore})
+		second.CACert = first.CACert
+		second.CAKey = first.CAKey
+		g.Expect(second.CompleteCertificates()).To(Succeed())
+
+		certCheck := pkiutil.CertCheck{CN: "h2", CaPEM: first.CACert}
+		g.Expect(certCheck.ValidateKeypair(second.PeerCert, second.PeerKey)).To(Succeed())
+	})
+
+	t.Run("MissingCAKey", func(t *testing.T) {
+		g := NewWithT(t)
+
+		c := pki.NewEtcdPKI(opts)
+		c.CACert = mustReadTestData(t, "ca.pem")
+		g.Expect(c.CompleteCertificates()).ToNot(Succeed())
+	})
+
+	t.Run("NoSelfSignedCA", func(t *testing.T) {
+		g := NewWithT(t)
+
+		c := pki.NewEtcdPKI(pki.EtcdPKIOpts{Hostname: "h1", NotBefore: notBefore})
+		g.Expect(c.CompleteCertificates()).ToNot(Succeed())
+	})
+
+	t.Run("InvalidCertificate", func(t *testing.T) {
+		g := NewWithT(t)
+
+		c := pki.NewEtcdPKI(opts)
+		g.Expect(c.CompleteCertificates()).To(Succeed())
+
+		other := pki.NewEtcdPKI(opts)
+		g.Expect(other.CompleteCertificates()).To(Succeed())
+
+		// certificates signed by a different CA are rejected
+		c.ServerCert = other.ServerCert
+		c.ServerKey = other.ServerKey
+		g.Expect(c.CompleteCertificates()).ToNot(Succeed())
+	})
+}
@@ -11,12 +11,13 @@ import (
 
 // K8sDqlitePKI is a list of certificates required by the k8s-dqlite datastore.
 type K8sDqlitePKI struct {
-	allowSelfSignedCA bool      // create self-signed CA certificates if missing
-	hostname          string    // node name
-	ipSANs            []net.IP  // IP SANs for generated certificates
-	dnsSANs           []string  // DNS SANs for the certificates below
-	notBefore         time.Time // notBefore date for the generated certificates
-	notAfter          time.Time // not after date (expiration date) for the generated certificates
+	allowSelfSignedCA bool                 // create self-signed CA certificates if missing
+	hostname          string               // node name
+	ipSANs            []net.IP             // IP SANs for generated certificates
+	dnsSANs           []string             // DNS SANs for the certificates below
+	notBefore         time.Time            // notBefore date for the generated certificates
+	notAfter          time.Time            // not after date (expiration date) for the generated certificates
+	keyAlgorithm      pkiutil.KeyAlgorithm // algorithm of the generated private keys
 
 	// CN=k8s, DNS=hostname, IP=127.0.0.1 (self-signed)
 	K8sDqliteCert, K8sDqliteKey string
@@ -30,6 +31,7 @@ type K8sDqlitePKIOpts struct {
 	NotAfter          time.Time
 	AllowSelfSignedCA bool
 	Datastore         string
+	KeyAlgorithm      pkiutil.KeyAlgorithm
 }
 
 func NewK8sDqlitePKI(opts K8sDqlitePKIOpts) *K8sDqlitePKI {
@@ -45,6 +47,7 @@ func NewK8sDqlitePKI(opts K8sDqlitePKIOpts) *K8sDqlitePKI {
 		notAfter:          opts.NotAfter,
 		ipSANs:            opts.IPSANs,
 		dnsSANs:           opts.DNSSANs,
+		keyAlgorithm:      opts.KeyAlgorithm,
 	}
 }
 
@@ -68,7 +71,7 @@ func (c *K8sDqlitePKI) CompleteCertificates() error {
 		if err != nil {
 			return fmt.Errorf("failed to generate k8s-dqlite certificate: %w", err)
 		}
-		cert, key, err := pkiutil.SignCertificate(template, 2048, template, nil, nil)
+		cert, key, err := pkiutil.SignCertificate(template, c.keyAlgorithm, template, nil)
 		if err != nil {
 			return fmt.Errorf("failed to self-sign k8s-dqlite certificate: %w", err)
 		}
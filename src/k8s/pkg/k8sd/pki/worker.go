@@ -24,15 +24,11 @@ type WorkerNodePKI struct {
 }
 
 // CompleteWorkerNodePKI generates the PKI needed for a worker node.
-func (c *ControlPlanePKI) CompleteWorkerNodePKI(hostname string, nodeIP net.IP, bits int) (*WorkerNodePKI, error) {
-	serverCACert, serverCAKey, err := pkiutil.LoadCertificate(c.CACert, c.CAKey)
+// The private keys of the worker node certificates are generated with the specified key algorithm.
+func (c *ControlPlanePKI) CompleteWorkerNodePKI(hostname string, nodeIP net.IP, algorithm pkiutil.KeyAlgorithm) (*WorkerNodePKI, error) {
+	serverCACert, serverCAKey, clientCACert, clientCAKey, err := c.loadCAs()
 	if err != nil {
-		return nil, fmt.Errorf("failed to load kubernetes CA: %w", err)
-	}
-
-	clientCACert, clientCAKey, err := pkiutil.LoadCertificate(c.ClientCACert, c.ClientCAKey)
-	if err != nil {
-		return nil, fmt.Errorf("failed to load kubernetes client CA: %w", err)
+		return nil, err
 	}
 
 	pki := &WorkerNodePKI{CACert: c.CACert, ClientCACert: c.ClientCACert}
@@ -53,7 +49,7 @@ func (c *ControlPlanePKI) CompleteWorkerNodePKI(hostname string, nodeIP net.IP,
 		if err != nil {
 			return nil, fmt.Errorf("failed to generate kubelet certificate for hostname=%s address=%s: %w", hostname, nodeIP.String(), err)
 		}
-		cert, key, err := pkiutil.SignCertificate(template, bits, serverCACert, &serverCAKey.PublicKey, serverCAKey)
+		cert, key, err := pkiutil.SignCertificate(template, algorithm, serverCACert, serverCAKey)
 		if err != nil {
 			return nil, fmt.Errorf("failed to sign kubelet certificate for hostname=%s address=%s: %w", hostname, nodeIP.String(), err)
 		}
@@ -79,7 +75,7 @@ func (c *ControlPlanePKI) CompleteWorkerNodePKI(hostname string, nodeIP net.IP,
 					return nil, fmt.Errorf("failed to generate %s client certificate: %w", i.name, err)
 				}
 
-				cert, key, err := pkiutil.SignCertificate(template, 2048, clientCACert, &clientCAKey.PublicKey, clientCAKey)
+				cert, key, err := pkiutil.SignCertificate(template, algorithm, clientCACert, clientCAKey)
 				if err != nil {
 					return nil, fmt.Errorf("failed to sign %s client certificate: %w", i.name, err)
 				}
@@ -92,6 +92,21 @@ func EnsureK8sDqlitePKI(snap snap.Snap, certificates *pki.K8sDqlitePKI) (bool, e
 	})
 }
 
+// EnsureEtcdPKI ensures the managed etcd PKI files are present
+// and have the correct content, permissions and ownership.
+// It returns true if one or more files were updated and any error that occurred.
+func EnsureEtcdPKI(snap snap.Snap, certificates *pki.EtcdPKI) (bool, error) {
+	return ensureFiles(snap.UID(), snap.GID(), 0o600, map[string]string{
+		filepath.Join(snap.EtcdPKIDir(), "ca.crt"):     certificates.CACert,
+		filepath.Join(snap.EtcdPKIDir(), "server.crt"): certificates.ServerCert,
+		filepath.Join(snap.EtcdPKIDir(), "server.key"): certificates.ServerKey,
+		filepath.Join(snap.EtcdPKIDir(), "peer.crt"):   certificates.PeerCert,
+		filepath.Join(snap.EtcdPKIDir(), "peer.key"):   certificates.PeerKey,
+		filepath.Join(snap.EtcdPKIDir(), "client.crt"): certificates.APIServerClientCert,
+		filepath.Join(snap.EtcdPKIDir(), "client.key"): certificates.APIServerClientKey,
+	})
+}
+
 // EnsureControlPlanePKI ensures the control plane PKI files are present
 // and have the correct content, permissions and ownership.
 // It returns true if one or more files were updated and any error that occurred.
@@ -127,6 +142,18 @@ func EnsureWorkerPKI(snap snap.Snap, certificates *pki.WorkerNodePKI) (bool, err
 	})
 }
 
+// EnsureControlPlaneCAs ensures the Kubernetes CA and client CA files of a control plane node are present
+// and have the correct content, permissions and ownership. This is used when the CAs of the cluster are rotated.
+// It returns true if one or more files were updated and any error that occurred.
+func EnsureControlPlaneCAs(snap snap.Snap, caCert, caKey, clientCACert, clientCAKey string) (bool, error) {
+	return ensureFiles(snap.UID(), snap.GID(), 0o600, map[string]string{
+		filepath.Join(snap.KubernetesPKIDir(), "ca.crt"):        caCert,
+		filepath.Join(snap.KubernetesPKIDir(), "ca.key"):        caKey,
+		filepath.Join(snap.KubernetesPKIDir(), "client-ca.crt"): clientCACert,
+		filepath.Join(snap.KubernetesPKIDir(), "client-ca.key"): clientCAKey,
+	})
+}
+
 // ReadControlPlanePKI reads the existing control plane PKI files and kubeconfig files,
 // populating a ControlPlanePKI structure with their contents.
 // The readManaged parameter controls which certificates to read:
@@ -307,3 +334,41 @@ func ReadControlPlanePKI(snap snap.Snap, certificates *pki.ControlPlanePKI, read
 
 	return nil
 }
+
+// ReadWorkerPKI reads the existing worker node certificates and the client certificates of the worker kubeconfig files,
+// populating a WorkerNodePKI structure with their contents. The CA certificates are not read.
+func ReadWorkerPKI(snap snap.Snap, certificates *pki.WorkerNodePKI) error {
+	for filePath, field := range map[string]*string{
+		filepath.Join(snap.KubernetesPKIDir(), "kubelet.crt"): &certificates.KubeletCert,
+		filepath.Join(snap.KubernetesPKIDir(), "kubelet.key"): &certificates.KubeletKey,
+	} {
+		content, err := os.ReadFile(filePath)
+		if err != nil {
+			return fmt.Errorf("failed to read file %s: %w", filePath, err)
+		}
+		*field = string(content)
+	}
+
+	for configName, fields := range map[string]struct {
+		certField *string
+		keyField  *string
+	}{
+		"kubelet.conf": {certField: &certificates.KubeletClientCert, keyField: &certificates.KubeletClientKey},
+		"proxy.conf":   {certField: &certificates.KubeProxyClientCert, keyField: &certificates.KubeProxyClientKey},
+	} {
+		kubeConfig, err := clientcmd.LoadFromFile(filepath.Join(snap.KubernetesConfigDir(), configName))
+		if err != nil {
+			return fmt.Errorf("failed to load kubeconfig %s: %w", configName, err)
+		}
+
+		authInfo, exists := kubeConfig.AuthInfos["k8s-user"]
+		if !exists {
+			return fmt.Errorf("user 'k8s-user' not found in kubeconfig %s", configName)
+		}
+
+		*fields.certField = string(authInfo.ClientCertificateData)
+		*fields.keyField = string(authInfo.ClientKeyData)
+	}
+
+	return nil
+}
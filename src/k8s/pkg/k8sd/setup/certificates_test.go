@@ -41,6 +41,51 @@ func TestEnsureK8sDqlitePKI(t *testing.T) {
 	}
 }
 
+// TestEnsureEtcdPKI tests the EnsureEtcdPKI function.
+func TestEnsureEtcdPKI(t *testing.T) {
+	g := NewWithT(t)
+	tempDir := t.TempDir()
+	mock := &mock.Snap{
+		Mock: mock.Mock{
+			EtcdPKIDir: tempDir,
+			UID:        os.Getuid(),
+			GID:        os.Getgid(),
+		},
+	}
+	certificates := &pki.EtcdPKI{
+		CACert:              "ca_cert",
+		CAKey:               "ca_key",
+		ServerCert:          "server_cert",
+		ServerKey:           "server_key",
+		PeerCert:            "peer_cert",
+		PeerKey:             "peer_key",
+		APIServerClientCert: "client_cert",
+		APIServerClientKey:  "client_key",
+	}
+
+	_, err := setup.EnsureEtcdPKI(mock, certificates)
+	g.Expect(err).To(Not(HaveOccurred()))
+
+	expectedFiles := []string{
+		filepath.Join(tempDir, "ca.crt"),
+		filepath.Join(tempDir, "server.crt"),
+		filepath.Join(tempDir, "server.key"),
+		filepath.Join(tempDir, "peer.crt"),
+		filepath.Join(tempDir, "peer.key"),
+		filepath.Join(tempDir, "client.crt"),
+		filepath.Join(tempDir, "client.key"),
+	}
+
+	for _, file := range expectedFiles {
+		_, err := os.Stat(file)
+		g.Expect(err).To(Not(HaveOccurred()))
+	}
+
+	// the CA key is only stored in the cluster configuration
+	_, err = os.Stat(filepath.Join(tempDir, "ca.key"))
+	g.Expect(err).To(MatchError(os.ErrNotExist))
+}
+
 // TestEnsureControlPlanePKI tests the EnsureControlPlanePKI function.
 func TestEnsureControlPlanePKI(t *testing.T) {
 	g := NewWithT(t)
@@ -131,6 +176,39 @@ func TestEnsureWorkerPKI(t *testing.T) {
 	}
 }
 
+func TestEnsureControlPlaneCAs(t *testing.T) {
+	g := NewWithT(t)
+	tempDir := t.TempDir()
+	mock := &mock.Snap{
+		Mock: mock.Mock{
+			KubernetesPKIDir: tempDir,
+			UID:              os.Getuid(),
+			GID:              os.Getgid(),
+		},
+	}
+	g.Expect(os.WriteFile(filepath.Join(tempDir, "apiserver.crt"), []byte("apiserver_cert"), 0o600)).To(Succeed())
+
+	changed, err := setup.EnsureControlPlaneCAs(mock, "ca_cert", "ca_key", "client_ca_cert", "client_ca_key")
+	g.Expect(err).To(Not(HaveOccurred()))
+	g.Expect(changed).To(BeTrue())
+
+	for file, expected := range map[string]string{
+		"ca.crt":        "ca_cert",
+		"ca.key":        "ca_key",
+		"client-ca.crt": "client_ca_cert",
+		"client-ca.key": "client_ca_key",
+		"apiserver.crt": "apiserver_cert",
+	} {
+		b, err := os.ReadFile(filepath.Join(tempDir, file))
+		g.Expect(err).To(Not(HaveOccurred()))
+		g.Expect(string(b)).To(Equal(expected))
+	}
+
+	changed, err = setup.EnsureControlPlaneCAs(mock, "ca_cert", "ca_key", "client_ca_cert", "client_ca_key")
+	g.Expect(err).To(Not(HaveOccurred()))
+	g.Expect(changed).To(BeFalse())
+}
+
 func TestExtDatastorePKI(t *testing.T) {
 	g := NewWithT(t)
 	tempDir := t.TempDir()
@@ -308,3 +386,39 @@ func TestReadControlPlanePKI(t *testing.T) {
 		})
 	}
 }
+
+func TestReadWorkerPKI(t *testing.T) {
+	g := NewWithT(t)
+
+	mock := &mock.Snap{
+		Mock: mock.Mock{
+			KubernetesPKIDir:    t.TempDir(),
+			KubernetesConfigDir: t.TempDir(),
+			UID:                 os.Getuid(),
+			GID:                 os.Getgid(),
+		},
+	}
+
+	orig := &pki.WorkerNodePKI{
+		CACert:              "ca_cert_val",
+		ClientCACert:        "client_ca_cert_val",
+		KubeletCert:         "kubelet_cert_val",
+		KubeletKey:          "kubelet_key_val",
+		KubeletClientCert:   "kubelet_client_cert_val",
+		KubeletClientKey:    "kubelet_client_key_val",
+		KubeProxyClientCert: "proxy_cert_val",
+		KubeProxyClientKey:  "proxy_key_val",
+	}
+
+	_, err := setup.EnsureWorkerPKI(mock, orig)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(setup.Kubeconfig(filepath.Join(mock.KubernetesConfigDir(), "kubelet.conf"), "127.0.0.1:6443", orig.CACert, orig.KubeletClientCert, orig.KubeletClientKey)).To(Succeed())
+	g.Expect(setup.Kubeconfig(filepath.Join(mock.KubernetesConfigDir(), "proxy.conf"), "127.0.0.1:6443", orig.CACert, orig.KubeProxyClientCert, orig.KubeProxyClientKey)).To(Succeed())
+
+	readCerts := &pki.WorkerNodePKI{}
+	g.Expect(setup.ReadWorkerPKI(mock, readCerts)).To(Succeed())
+
+	orig.CACert = ""
+	orig.ClientCACert = ""
+	g.Expect(readCerts).To(Equal(orig))
+}
@@ -0,0 +1,63 @@
+package setup
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+
+	"github.com/canonical/k8s/pkg/snap"
+	snaputil "github.com/canonical/k8s/pkg/snap/util"
+	"github.com/canonical/k8s/pkg/utils"
+)
+
+// EtcdPeerURL returns the URL that the etcd member on the node with the given IP uses to communicate with other members.
+func EtcdPeerURL(nodeIP net.IP, peerPort int) string {
+	return fmt.Sprintf("https://%s:%d", utils.ToIPString(nodeIP), peerPort)
+}
+
+// Etcd configures the arguments of the managed etcd service.
+// initialCluster is the list of members as returned by datastore.AddEtcdMember when joining an existing cluster.
+// If initialCluster is empty, the member starts a new cluster.
+func Etcd(snap snap.Snap, name string, nodeIP net.IP, clientPort int, peerPort int, initialCluster string) error {
+	// cleanup in case of existing cluster
+	if _, err := os.Stat(filepath.Join(snap.EtcdStateDir(), "member")); err == nil {
+		if err := os.RemoveAll(snap.EtcdStateDir()); err != nil {
+			return fmt.Errorf("failed to cleanup not-empty etcd directory: %w", err)
+		}
+		if err := os.MkdirAll(snap.EtcdStateDir(), 0o700); err != nil {
+			return fmt.Errorf("failed to create etcd state directory: %w", err)
+		}
+	}
+
+	peerURL := EtcdPeerURL(nodeIP, peerPort)
+	clientURL := fmt.Sprintf("https://%s:%d", utils.ToIPString(nodeIP), clientPort)
+
+	initialClusterState := "existing"
+	if initialCluster == "" {
+		initialCluster = fmt.Sprintf("%s=%s", name, peerURL)
+		initialClusterState = "new"
+	}
+
+	if _, err := snaputil.UpdateServiceArguments(snap, "etcd", map[string]string{
+		"--name":                        name,
+		"--data-dir":                    snap.EtcdStateDir(),
+		"--listen-client-urls":          fmt.Sprintf("https://127.0.0.1:%d,%s", clientPort, clientURL),
+		"--advertise-client-urls":       clientURL,
+		"--listen-peer-urls":            peerURL,
+		"--initial-advertise-peer-urls": peerURL,
+		"--initial-cluster":             initialCluster,
+		"--initial-cluster-state":       initialClusterState,
+		"--client-cert-auth":            "true",
+		"--trusted-ca-file":             filepath.Join(snap.EtcdPKIDir(), "ca.crt"),
+		"--cert-file":                   filepath.Join(snap.EtcdPKIDir(), "server.crt"),
+		"--key-file":                    filepath.Join(snap.EtcdPKIDir(), "server.key"),
+		"--peer-client-cert-auth":       "true",
+		"--peer-trusted-ca-file":        filepath.Join(snap.EtcdPKIDir(), "ca.crt"),
+		"--peer-cert-file":              filepath.Join(snap.EtcdPKIDir(), "peer.crt"),
+		"--peer-key-file":               filepath.Join(snap.EtcdPKIDir(), "peer.key"),
+	}, nil); err != nil {
+		return fmt.Errorf("failed to write arguments file: %w", err)
+	}
+	return nil
+}
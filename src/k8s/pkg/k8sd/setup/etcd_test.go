@@ -0,0 +1,85 @@
+package setup_test
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/canonical/k8s/pkg/k8sd/setup"
+	"github.com/canonical/k8s/pkg/snap/mock"
+	snaputil "github.com/canonical/k8s/pkg/snap/util"
+	. "github.com/onsi/gomega"
+)
+
+func setEtcdMock(s *mock.Snap, dir string) {
+	s.Mock = mock.Mock{
+		ServiceArgumentsDir: filepath.Join(dir, "args"),
+		EtcdStateDir:        filepath.Join(dir, "etcd"),
+		EtcdPKIDir:          filepath.Join(dir, "pki", "etcd"),
+	}
+}
+
+func TestEtcd(t *testing.T) {
+	t.Run("NewCluster", func(t *testing.T) {
+		g := NewWithT(t)
+
+		s := mustSetupSnapAndDirectories(t, setEtcdMock)
+
+		g.Expect(setup.Etcd(s, "n1", net.ParseIP("192.168.0.1"), 2379, 2380, "")).To(Succeed())
+
+		tests := []struct {
+			key         string
+			expectedVal string
+		}{
+			{key: "--name", expectedVal: "n1"},
+			{key: "--data-dir", expectedVal: s.Mock.EtcdStateDir},
+			{key: "--listen-client-urls", expectedVal: "https://127.0.0.1:2379,https://192.168.0.1:2379"},
+			{key: "--advertise-client-urls", expectedVal: "https://192.168.0.1:2379"},
+			{key: "--listen-peer-urls", expectedVal: "https://192.168.0.1:2380"},
+			{key: "--initial-advertise-peer-urls", expectedVal: "https://192.168.0.1:2380"},
+			{key: "--initial-cluster", expectedVal: "n1=https://192.168.0.1:2380"},
+			{key: "--initial-cluster-state", expectedVal: "new"},
+			{key: "--client-cert-auth", expectedVal: "true"},
+			{key: "--trusted-ca-file", expectedVal: filepath.Join(s.Mock.EtcdPKIDir, "ca.crt")},
+			{key: "--cert-file", expectedVal: filepath.Join(s.Mock.EtcdPKIDir, "server.crt")},
+			{key: "--key-file", expectedVal: filepath.Join(s.Mock.EtcdPKIDir, "server.key")},
+			{key: "--peer-client-cert-auth", expectedVal: "true"},
+			{key: "--peer-trusted-ca-file", expectedVal: filepath.Join(s.Mock.EtcdPKIDir, "ca.crt")},
+			{key: "--peer-cert-file", expectedVal: filepath.Join(s.Mock.EtcdPKIDir, "peer.crt")},
+			{key: "--peer-key-file", expectedVal: filepath.Join(s.Mock.EtcdPKIDir, "peer.key")},
+		}
+		for _, tc := range tests {
+			t.Run(tc.key, func(t *testing.T) {
+				g := NewWithT(t)
+				val, err := snaputil.GetServiceArgument(s, "etcd", tc.key)
+				g.Expect(err).To(Not(HaveOccurred()))
+				g.Expect(val).To(Equal(tc.expectedVal))
+			})
+		}
+	})
+
+	t.Run("ExistingCluster", func(t *testing.T) {
+		g := NewWithT(t)
+
+		s := mustSetupSnapAndDirectories(t, setEtcdMock)
+
+		// stale member data from a previous cluster is removed
+		g.Expect(os.MkdirAll(filepath.Join(s.Mock.EtcdStateDir, "member"), 0o700)).To(Succeed())
+
+		g.Expect(setup.Etcd(s, "n2", net.ParseIP("fd00::2"), 2379, 2380, "n1=https://[fd00::1]:2380,n2=https://[fd00::2]:2380")).To(Succeed())
+
+		_, err := os.Stat(filepath.Join(s.Mock.EtcdStateDir, "member"))
+		g.Expect(err).To(MatchError(os.ErrNotExist))
+
+		for key, expectedVal := range map[string]string{
+			"--initial-advertise-peer-urls": "https://[fd00::2]:2380",
+			"--initial-cluster":             "n1=https://[fd00::1]:2380,n2=https://[fd00::2]:2380",
+			"--initial-cluster-state":       "existing",
+		} {
+			val, err := snaputil.GetServiceArgument(s, "etcd", key)
+			g.Expect(err).To(Not(HaveOccurred()))
+			g.Expect(val).To(Equal(expectedVal))
+		}
+	})
+}
@@ -21,6 +21,8 @@ func K8sAPIServerProxy(snap snap.Snap, servers []string, securePort int, extraAr
 		"--endpoints":  configFile,
 		"--kubeconfig": filepath.Join(snap.KubernetesConfigDir(), "kubelet.conf"),
 		"--listen":     fmt.Sprintf(":%d", securePort),
+		// Serve metrics and status locally, for k8s local-node-status.
+		"--status-address": "127.0.0.1:6444",
 	}, nil); err != nil {
 		return fmt.Errorf("failed to write arguments file: %w", err)
 	}
@@ -36,6 +36,7 @@ func TestK8sApiServerProxy(t *testing.T) {
 			{key: "--endpoints", expectedVal: filepath.Join(s.Mock.ServiceExtraConfigDir, "k8s-apiserver-proxy.json")},
 			{key: "--kubeconfig", expectedVal: filepath.Join(s.Mock.KubernetesConfigDir, "kubelet.conf")},
 			{key: "--listen", expectedVal: ":6443"},
+			{key: "--status-address", expectedVal: "127.0.0.1:6444"},
 		}
 		for _, tc := range tests {
 			t.Run(tc.key, func(t *testing.T) {
@@ -70,6 +71,7 @@ func TestK8sApiServerProxy(t *testing.T) {
 			{key: "--endpoints", expectedVal: filepath.Join(s.Mock.ServiceExtraConfigDir, "k8s-apiserver-proxy.json")},
 			{key: "--kubeconfig", expectedVal: filepath.Join(s.Mock.KubernetesConfigDir, "overridden-kubelet.conf")},
 			{key: "--my-extra-arg", expectedVal: "my-extra-val"},
+			{key: "--status-address", expectedVal: "127.0.0.1:6444"},
 		}
 		for _, tc := range tests {
 			t.Run(tc.key, func(t *testing.T) {
@@ -19,7 +19,7 @@ type apiserverAuthTokenWebhookTemplateConfig struct {
 	CAPath string
 }
 
-var SupportedDatastores = []string{"k8s-dqlite", "external"}
+var SupportedDatastores = []string{"k8s-dqlite", "etcd", "external"}
 
 var (
 	apiserverAuthTokenWebhookTemplate = mustTemplate("apiserver", "auth-token-webhook.conf")
@@ -50,7 +50,7 @@ var (
 )
 
 // KubeAPIServer configures kube-apiserver on the local node.
-func KubeAPIServer(snap snap.Snap, securePort int, nodeIP net.IP, serviceCIDR string, authWebhookURL string, enableFrontProxy bool, datastore types.Datastore, authorizationMode string, extraArgs map[string]*string) error {
+func KubeAPIServer(snap snap.Snap, securePort int, nodeIP net.IP, serviceCIDR string, authWebhookURL string, enableFrontProxy bool, datastore types.Datastore, authorizationMode string, authentication types.Authentication, extraArgs map[string]*string) error {
 	authTokenWebhookConfigFile := filepath.Join(snap.ServiceExtraConfigDir(), "auth-token-webhook.conf")
 	authTokenWebhookFile, err := os.OpenFile(authTokenWebhookConfigFile, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
 	if err != nil {
@@ -94,7 +94,7 @@ func KubeAPIServer(snap snap.Snap, securePort int, nodeIP net.IP, serviceCIDR st
 	}
 
 	switch datastore.GetType() {
-	case "k8s-dqlite", "external":
+	case "k8s-dqlite", "etcd", "external":
 	default:
 		return fmt.Errorf("unsupported datastore %s, must be one of %v", datastore.GetType(), SupportedDatastores)
 	}
@@ -104,6 +104,15 @@ func KubeAPIServer(snap snap.Snap, securePort int, nodeIP net.IP, serviceCIDR st
 		args[key] = val
 	}
 
+	authenticationUpdateArgs, authenticationDeleteArgs, err := KubeAPIServerAuthentication(snap, authentication)
+	if err != nil {
+		return fmt.Errorf("failed to configure authentication: %w", err)
+	}
+	for key, val := range authenticationUpdateArgs {
+		args[key] = val
+	}
+	deleteArgs = append(deleteArgs, authenticationDeleteArgs...)
+
 	if enableFrontProxy {
 		args["--requestheader-client-ca-file"] = filepath.Join(snap.KubernetesPKIDir(), "front-proxy-ca.crt")
 		args["--requestheader-allowed-names"] = "front-proxy-client"
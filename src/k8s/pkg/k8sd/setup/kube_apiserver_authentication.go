@@ -0,0 +1,91 @@
+package setup
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/k8s/pkg/snap"
+	"github.com/canonical/k8s/pkg/utils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apiserverv1beta1 "k8s.io/apiserver/pkg/apis/apiserver/v1beta1"
+	"sigs.k8s.io/yaml"
+)
+
+// KubeAPIServerAuthentication writes the structured authentication configuration file of kube-apiserver, or removes it
+// if no authenticators are configured.
+// KubeAPIServerAuthentication returns updateArgs, deleteArgs that can be used with snaputil.UpdateServiceArguments().
+// kube-apiserver reloads the file when it changes, so a restart is only needed if the arguments change.
+func KubeAPIServerAuthentication(snap snap.Snap, authentication types.Authentication) (map[string]string, []string, error) {
+	configFile := filepath.Join(snap.ServiceExtraConfigDir(), "authentication-config.yaml")
+
+	config, err := kubeAPIServerAuthenticationConfig(authentication)
+	if err != nil {
+		return nil, nil, err
+	}
+	if config == nil {
+		if err := os.Remove(configFile); err != nil && !os.IsNotExist(err) {
+			return nil, nil, fmt.Errorf("failed to remove authentication-config.yaml: %w", err)
+		}
+		return nil, []string{"--authentication-config"}, nil
+	}
+
+	b, err := yaml.Marshal(config)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode authentication config: %w", err)
+	}
+	if err := utils.WriteFile(configFile, b, 0o600); err != nil {
+		return nil, nil, fmt.Errorf("failed to write authentication-config.yaml: %w", err)
+	}
+	return map[string]string{"--authentication-config": configFile}, nil, nil
+}
+
+// kubeAPIServerAuthenticationConfig returns the AuthenticationConfiguration for kube-apiserver.
+// kubeAPIServerAuthenticationConfig returns nil if no authenticators are configured.
+func kubeAPIServerAuthenticationConfig(authentication types.Authentication) (*apiserverv1beta1.AuthenticationConfiguration, error) {
+	if v := authentication.GetConfig(); v != "" {
+		config, err := types.ParseAuthenticationConfig(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid authentication config: %w", err)
+		}
+		return config, nil
+	}
+
+	oidc := authentication.OIDC
+	if oidc.GetIssuerURL() == "" {
+		return nil, nil
+	}
+
+	// Match the defaults of the --oidc-* kube-apiserver arguments.
+	usernameClaim := oidc.GetUsernameClaim()
+	if usernameClaim == "" {
+		usernameClaim = "sub"
+	}
+	usernamePrefix := oidc.GetUsernamePrefix()
+	if oidc.UsernamePrefix == nil && usernameClaim != "email" {
+		usernamePrefix = oidc.GetIssuerURL() + "#"
+	}
+
+	jwt := apiserverv1beta1.JWTAuthenticator{
+		Issuer: apiserverv1beta1.Issuer{
+			URL:                  oidc.GetIssuerURL(),
+			CertificateAuthority: oidc.GetCACert(),
+			Audiences:            []string{oidc.GetClientID()},
+		},
+		ClaimMappings: apiserverv1beta1.ClaimMappings{
+			Username: apiserverv1beta1.PrefixedClaimOrExpression{Claim: usernameClaim, Prefix: utils.Pointer(usernamePrefix)},
+		},
+	}
+	if v := oidc.GetGroupsClaim(); v != "" {
+		jwt.ClaimMappings.Groups = apiserverv1beta1.PrefixedClaimOrExpression{Claim: v, Prefix: utils.Pointer(oidc.GetGroupsPrefix())}
+	}
+
+	return &apiserverv1beta1.AuthenticationConfiguration{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: types.AuthenticationConfigAPIVersion,
+			Kind:       "AuthenticationConfiguration",
+		},
+		JWT: []apiserverv1beta1.JWTAuthenticator{jwt},
+	}, nil
+}
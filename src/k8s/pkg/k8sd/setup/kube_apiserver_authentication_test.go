@@ -0,0 +1,116 @@
+package setup_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/canonical/k8s/pkg/k8sd/setup"
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/k8s/pkg/utils"
+	. "github.com/onsi/gomega"
+)
+
+func TestKubeAPIServerAuthentication(t *testing.T) {
+	t.Run("OIDC", func(t *testing.T) {
+		g := NewWithT(t)
+
+		s := mustSetupSnapAndDirectories(t, setKubeAPIServerMock)
+		configFile := filepath.Join(s.Mock.ServiceExtraConfigDir, "authentication-config.yaml")
+
+		updateArgs, deleteArgs, err := setup.KubeAPIServerAuthentication(s, types.Authentication{
+			OIDC: types.OIDC{
+				IssuerURL:   utils.Pointer("https://accounts.example.com"),
+				ClientID:    utils.Pointer("kubernetes"),
+				GroupsClaim: utils.Pointer("groups"),
+			},
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(updateArgs).To(Equal(map[string]string{"--authentication-config": configFile}))
+		g.Expect(deleteArgs).To(BeEmpty())
+
+		b, err := os.ReadFile(configFile)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(string(b)).To(Equal(`apiVersion: apiserver.config.k8s.io/v1beta1
+jwt:
+- claimMappings:
+    groups:
+      claim: groups
+      prefix: ""
+    uid: {}
+    username:
+      claim: sub
+      prefix: https://accounts.example.com#
+  issuer:
+    audiences:
+    - kubernetes
+    url: https://accounts.example.com
+kind: AuthenticationConfiguration
+`))
+
+		t.Run("Remove", func(t *testing.T) {
+			g := NewWithT(t)
+
+			updateArgs, deleteArgs, err := setup.KubeAPIServerAuthentication(s, types.Authentication{})
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(updateArgs).To(BeEmpty())
+			g.Expect(deleteArgs).To(ConsistOf("--authentication-config"))
+			g.Expect(configFile).ToNot(BeAnExistingFile())
+		})
+	})
+
+	t.Run("EmailUsername", func(t *testing.T) {
+		g := NewWithT(t)
+
+		s := mustSetupSnapAndDirectories(t, setKubeAPIServerMock)
+
+		_, _, err := setup.KubeAPIServerAuthentication(s, types.Authentication{
+			OIDC: types.OIDC{
+				IssuerURL:     utils.Pointer("https://accounts.example.com"),
+				ClientID:      utils.Pointer("kubernetes"),
+				UsernameClaim: utils.Pointer("email"),
+			},
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		b, err := os.ReadFile(filepath.Join(s.Mock.ServiceExtraConfigDir, "authentication-config.yaml"))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(string(b)).To(ContainSubstring("claim: email\n      prefix: \"\"\n"))
+		g.Expect(string(b)).To(ContainSubstring("groups: {}"))
+	})
+
+	t.Run("Config", func(t *testing.T) {
+		g := NewWithT(t)
+
+		s := mustSetupSnapAndDirectories(t, setKubeAPIServerMock)
+
+		_, _, err := setup.KubeAPIServerAuthentication(s, types.Authentication{
+			Config: utils.Pointer(`apiVersion: apiserver.config.k8s.io/v1beta1
+kind: AuthenticationConfiguration
+jwt:
+- issuer:
+    url: https://one.example.com
+    audiences: [kubernetes]
+  claimMappings:
+    username:
+      expression: "'one:' + claims.sub"
+`),
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		b, err := os.ReadFile(filepath.Join(s.Mock.ServiceExtraConfigDir, "authentication-config.yaml"))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(string(b)).To(ContainSubstring("expression: '''one:'' + claims.sub'"))
+	})
+
+	t.Run("InvalidConfig", func(t *testing.T) {
+		g := NewWithT(t)
+
+		s := mustSetupSnapAndDirectories(t, setKubeAPIServerMock)
+
+		_, _, err := setup.KubeAPIServerAuthentication(s, types.Authentication{
+			Config: utils.Pointer("kind: StructuredAuthorizationConfiguration"),
+		})
+		g.Expect(err).To(HaveOccurred())
+	})
+}
@@ -56,6 +56,17 @@ func KubeconfigString(url string, caPEM string, crtPEM string, keyPEM string) (s
 	return string(kubeconfig), nil
 }
 
+// KubeconfigTokenString provides a stringified kubeconfig that authenticates with a bearer token.
+func KubeconfigTokenString(url string, caPEM string, token string) (string, error) {
+	config := createConfig(url, caPEM, "", "")
+	config.AuthInfos["k8s-user"] = &clientcmdapi.AuthInfo{Token: token}
+	kubeconfig, err := clientcmd.Write(*config)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode kubeconfig yaml: %w", err)
+	}
+	return string(kubeconfig), nil
+}
+
 // SetupControlPlaneKubeconfigs writes kubeconfig files for the control plane components.
 func SetupControlPlaneKubeconfigs(kubeConfigDir string, localhostAddress string, securePort int, pki pki.ControlPlanePKI) error {
 	for _, kubeconfig := range []struct {
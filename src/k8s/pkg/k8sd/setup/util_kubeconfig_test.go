@@ -36,3 +36,32 @@ users:
 	g.Expect(actual).To(Equal(expectedConfig))
 	g.Expect(err).To(Not(HaveOccurred()))
 }
+
+func TestKubeconfigTokenString(t *testing.T) {
+	g := NewWithT(t)
+
+	expectedConfig := `apiVersion: v1
+clusters:
+- cluster:
+    certificate-authority-data: Y2E=
+    server: https://server
+  name: k8s
+contexts:
+- context:
+    cluster: k8s
+    user: k8s-user
+  name: k8s
+current-context: k8s
+kind: Config
+preferences: {}
+users:
+- name: k8s-user
+  user:
+    token: token::abc
+`
+
+	actual, err := setup.KubeconfigTokenString("server", "ca", "token::abc")
+
+	g.Expect(actual).To(Equal(expectedConfig))
+	g.Expect(err).To(Not(HaveOccurred()))
+}
@@ -0,0 +1,118 @@
+package types
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/canonical/k8s/pkg/utils"
+)
+
+// CARotationPhase is a phase of the rotation of the Kubernetes CAs.
+// Each phase is applied on all cluster nodes before the rotation moves to the next phase.
+type CARotationPhase string
+
+const (
+	// CARotationTrust distributes a trust bundle with the old and the new CAs to all nodes.
+	// Certificates are still issued by the old CAs.
+	CARotationTrust CARotationPhase = "trust"
+	// CARotationReissue reissues the certificates of all nodes from the new CAs. The old CAs are still trusted.
+	CARotationReissue CARotationPhase = "reissue"
+	// CARotationFinalize removes the old CAs from the trust bundle of all nodes.
+	CARotationFinalize CARotationPhase = "finalize"
+	// CARotationCompleted means that the rotation is complete.
+	CARotationCompleted CARotationPhase = "completed"
+)
+
+// Next returns the phase that follows p.
+func (p CARotationPhase) Next() CARotationPhase {
+	switch p {
+	case CARotationTrust:
+		return CARotationReissue
+	case CARotationReissue:
+		return CARotationFinalize
+	default:
+		return CARotationCompleted
+	}
+}
+
+// CARotation is a rotation of the Kubernetes CA and client CA of the cluster.
+type CARotation struct {
+	// ID is the unique identifier of the rotation.
+	ID int64
+	// Phase is the current phase of the rotation.
+	Phase CARotationPhase
+	// Old are the CAs of the cluster before the rotation, along with their keys.
+	Old Certificates
+	// New are the new CAs, along with their keys, and the shared certificates of the cluster configuration
+	// that are reissued from the new CAs.
+	New Certificates
+	// StartedAt is the time the rotation started.
+	StartedAt time.Time
+	// UpdatedAt is the time the rotation moved to the current phase.
+	UpdatedAt time.Time
+}
+
+// ClusterCertificates returns the certificates of the cluster configuration during the given phase.
+// During the trust and reissue phases, the CA certificates are bundles of the old and the new CAs.
+// The first certificate of each bundle is the CA that matches the key, and is used to issue new certificates.
+// Fields that do not change in the given phase are nil.
+func (r CARotation) ClusterCertificates(phase CARotationPhase) Certificates {
+	switch phase {
+	case CARotationTrust:
+		return Certificates{
+			CACert:       utils.Pointer(caBundle(r.Old.GetCACert(), r.New.GetCACert())),
+			CAKey:        utils.Pointer(r.Old.GetCAKey()),
+			ClientCACert: utils.Pointer(caBundle(r.Old.GetClientCACert(), r.New.GetClientCACert())),
+			ClientCAKey:  utils.Pointer(r.Old.GetClientCAKey()),
+		}
+	case CARotationReissue:
+		return Certificates{
+			CACert:                     utils.Pointer(caBundle(r.New.GetCACert(), r.Old.GetCACert())),
+			CAKey:                      utils.Pointer(r.New.GetCAKey()),
+			ClientCACert:               utils.Pointer(caBundle(r.New.GetClientCACert(), r.Old.GetClientCACert())),
+			ClientCAKey:                utils.Pointer(r.New.GetClientCAKey()),
+			APIServerKubeletClientCert: r.New.APIServerKubeletClientCert,
+			APIServerKubeletClientKey:  r.New.APIServerKubeletClientKey,
+			AdminClientCert:            r.New.AdminClientCert,
+			AdminClientKey:             r.New.AdminClientKey,
+		}
+	default:
+		return Certificates{
+			CACert:                     utils.Pointer(caBundle(r.New.GetCACert())),
+			CAKey:                      utils.Pointer(r.New.GetCAKey()),
+			ClientCACert:               utils.Pointer(caBundle(r.New.GetClientCACert())),
+			ClientCAKey:                utils.Pointer(r.New.GetClientCAKey()),
+			APIServerKubeletClientCert: r.New.APIServerKubeletClientCert,
+			APIServerKubeletClientKey:  r.New.APIServerKubeletClientKey,
+			AdminClientCert:            r.New.AdminClientCert,
+			AdminClientKey:             r.New.AdminClientKey,
+		}
+	}
+}
+
+// CARotationNode is the progress of a node in a CA rotation.
+type CARotationNode struct {
+	// Node is the name of the node.
+	Node string
+	// Phase is the last phase that was applied on the node.
+	Phase CARotationPhase
+	// Message contains information about the progress of the node, e.g. the error of a failed attempt to apply the phase.
+	// Message is only supposed to be human readable and should not be programmatically parsed.
+	Message string
+	// UpdatedAt is the time the progress of the node was last updated.
+	UpdatedAt time.Time
+}
+
+// caBundle concatenates PEM encoded certificates. Empty and duplicate certificates are skipped.
+func caBundle(certs ...string) string {
+	var parts []string
+	for _, cert := range certs {
+		cert = strings.TrimSpace(cert)
+		if cert == "" || slices.Contains(parts, cert) {
+			continue
+		}
+		parts = append(parts, cert)
+	}
+	return strings.Join(parts, "\n") + "\n"
+}
@@ -0,0 +1,77 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/k8s/pkg/utils"
+	. "github.com/onsi/gomega"
+)
+
+func TestCARotationClusterCertificates(t *testing.T) {
+	rotation := types.CARotation{
+		Old: types.Certificates{
+			CACert: utils.Pointer("OLD CA\n"),
+			CAKey:  utils.Pointer("OLD CA KEY"),
+		},
+		New: types.Certificates{
+			CACert:          utils.Pointer("NEW CA\n"),
+			CAKey:           utils.Pointer("NEW CA KEY"),
+			ClientCACert:    utils.Pointer("NEW CLIENT CA\n"),
+			ClientCAKey:     utils.Pointer("NEW CLIENT CA KEY"),
+			AdminClientCert: utils.Pointer("NEW ADMIN"),
+			AdminClientKey:  utils.Pointer("NEW ADMIN KEY"),
+		},
+	}
+
+	for _, tc := range []struct {
+		phase    types.CARotationPhase
+		expected types.Certificates
+	}{
+		{
+			phase: types.CARotationTrust,
+			expected: types.Certificates{
+				// the old CA was also used as client CA
+				CACert:       utils.Pointer("OLD CA\nNEW CA\n"),
+				CAKey:        utils.Pointer("OLD CA KEY"),
+				ClientCACert: utils.Pointer("OLD CA\nNEW CLIENT CA\n"),
+				ClientCAKey:  utils.Pointer("OLD CA KEY"),
+			},
+		},
+		{
+			phase: types.CARotationReissue,
+			expected: types.Certificates{
+				CACert:          utils.Pointer("NEW CA\nOLD CA\n"),
+				CAKey:           utils.Pointer("NEW CA KEY"),
+				ClientCACert:    utils.Pointer("NEW CLIENT CA\nOLD CA\n"),
+				ClientCAKey:     utils.Pointer("NEW CLIENT CA KEY"),
+				AdminClientCert: utils.Pointer("NEW ADMIN"),
+				AdminClientKey:  utils.Pointer("NEW ADMIN KEY"),
+			},
+		},
+		{
+			phase: types.CARotationFinalize,
+			expected: types.Certificates{
+				CACert:          utils.Pointer("NEW CA\n"),
+				CAKey:           utils.Pointer("NEW CA KEY"),
+				ClientCACert:    utils.Pointer("NEW CLIENT CA\n"),
+				ClientCAKey:     utils.Pointer("NEW CLIENT CA KEY"),
+				AdminClientCert: utils.Pointer("NEW ADMIN"),
+				AdminClientKey:  utils.Pointer("NEW ADMIN KEY"),
+			},
+		},
+	} {
+		t.Run(string(tc.phase), func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(rotation.ClusterCertificates(tc.phase)).To(Equal(tc.expected))
+		})
+	}
+}
+
+func TestCARotationPhaseNext(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(types.CARotationTrust.Next()).To(Equal(types.CARotationReissue))
+	g.Expect(types.CARotationReissue.Next()).To(Equal(types.CARotationFinalize))
+	g.Expect(types.CARotationFinalize.Next()).To(Equal(types.CARotationCompleted))
+	g.Expect(types.CARotationCompleted.Next()).To(Equal(types.CARotationCompleted))
+}
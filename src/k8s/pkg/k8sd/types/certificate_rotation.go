@@ -0,0 +1,47 @@
+package types
+
+import (
+	"time"
+)
+
+// CertificateRotationState is the state of the automatic certificate rotation on a node.
+type CertificateRotationState string
+
+const (
+	// CertificateRotationWaiting means that the node has expiring certificates, but another node is rotating its certificates.
+	CertificateRotationWaiting CertificateRotationState = "waiting"
+	// CertificateRotationRenewing means that the node is renewing its expiring certificates.
+	CertificateRotationRenewing CertificateRotationState = "renewing"
+	// CertificateRotationRestarting means that the node is restarting the services that use the renewed certificates.
+	CertificateRotationRestarting CertificateRotationState = "restarting"
+	// CertificateRotationCompleted means that the last certificate rotation of the node succeeded.
+	CertificateRotationCompleted CertificateRotationState = "completed"
+	// CertificateRotationFailed means that the last certificate rotation of the node failed.
+	CertificateRotationFailed CertificateRotationState = "failed"
+)
+
+// CertificateRotation is the progress of the automatic certificate rotation on a node.
+type CertificateRotation struct {
+	// Node is the name of the node.
+	Node string
+	// State is the state of the rotation.
+	State CertificateRotationState
+	// Certificates are the names of the certificates that are being rotated, or were rotated last.
+	Certificates []string
+	// Message contains information about the rotation, e.g. the service being restarted or the error of a failed rotation.
+	// Message is only supposed to be human readable and should not be programmatically parsed.
+	Message string
+	// UpdatedAt is the time the rotation progress was last updated.
+	UpdatedAt time.Time
+}
+
+// Active returns true if the node is renewing certificates or restarting services.
+// A rotation that was not updated within the lease duration is not active anymore, e.g. because the node went down.
+func (r CertificateRotation) Active(now time.Time, lease time.Duration) bool {
+	switch r.State {
+	case CertificateRotationRenewing, CertificateRotationRestarting:
+		return now.Sub(r.UpdatedAt) < lease
+	default:
+		return false
+	}
+}
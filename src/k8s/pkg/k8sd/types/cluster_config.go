@@ -6,6 +6,8 @@ type ClusterConfig struct {
 	APIServer    APIServer    `json:"apiserver,omitempty"`
 	Kubelet      Kubelet      `json:"kubelet,omitempty"`
 
+	Authentication Authentication `json:"authentication,omitempty"`
+
 	Network       Network       `json:"network,omitempty"`
 	DNS           DNS           `json:"dns,omitempty"`
 	Ingress       Ingress       `json:"ingress,omitempty"`
@@ -13,6 +15,9 @@ type ClusterConfig struct {
 	Gateway       Gateway       `json:"gateway,omitempty"`
 	LocalStorage  LocalStorage  `json:"local-storage,omitempty"`
 	MetricsServer MetricsServer `json:"metrics-server,omitempty"`
+	CertManager   CertManager   `json:"cert-manager,omitempty"`
+
+	ValuesOverrides ValuesOverrides `json:"values-overrides,omitempty"`
 
 	Annotations Annotations `json:"annotations,omitempty"`
 }
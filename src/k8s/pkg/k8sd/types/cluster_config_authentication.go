@@ -0,0 +1,169 @@
+package types
+
+import (
+	"encoding/pem"
+	"fmt"
+	"net/url"
+
+	"github.com/canonical/k8s/pkg/utils"
+	apiserverv1beta1 "k8s.io/apiserver/pkg/apis/apiserver/v1beta1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// AnnotationAuthenticationOIDCIssuerURL is the URL of the OIDC issuer, e.g. "https://accounts.example.com".
+	AnnotationAuthenticationOIDCIssuerURL = "k8sd/v1alpha/authentication/oidc/issuer-url"
+	// AnnotationAuthenticationOIDCClientID is the client ID that the OIDC tokens must be issued for.
+	AnnotationAuthenticationOIDCClientID = "k8sd/v1alpha/authentication/oidc/client-id"
+	// AnnotationAuthenticationOIDCUsernameClaim is the claim of the OIDC token that is used as the username.
+	AnnotationAuthenticationOIDCUsernameClaim = "k8sd/v1alpha/authentication/oidc/username-claim"
+	// AnnotationAuthenticationOIDCUsernamePrefix is the prefix that is added to the OIDC usernames.
+	AnnotationAuthenticationOIDCUsernamePrefix = "k8sd/v1alpha/authentication/oidc/username-prefix"
+	// AnnotationAuthenticationOIDCGroupsClaim is the claim of the OIDC token that is used as the groups.
+	AnnotationAuthenticationOIDCGroupsClaim = "k8sd/v1alpha/authentication/oidc/groups-claim"
+	// AnnotationAuthenticationOIDCGroupsPrefix is the prefix that is added to the OIDC groups.
+	AnnotationAuthenticationOIDCGroupsPrefix = "k8sd/v1alpha/authentication/oidc/groups-prefix"
+	// AnnotationAuthenticationOIDCCACert is the PEM encoded CA certificate of the OIDC issuer.
+	AnnotationAuthenticationOIDCCACert = "k8sd/v1alpha/authentication/oidc/ca-crt"
+	// AnnotationAuthenticationConfig is a full structured AuthenticationConfiguration for kube-apiserver, in YAML.
+	// AnnotationAuthenticationConfig cannot be combined with the OIDC annotations.
+	AnnotationAuthenticationConfig = "k8sd/v1alpha/authentication/config"
+
+	// AuthenticationConfigAPIVersion is the apiVersion of the structured AuthenticationConfiguration.
+	AuthenticationConfigAPIVersion = "apiserver.config.k8s.io/v1beta1"
+)
+
+// Authentication is the configuration of the kube-apiserver authenticators that are configured in addition to
+// client certificates and k8sd auth tokens.
+type Authentication struct {
+	OIDC OIDC `json:"oidc,omitempty"`
+	// Config is a full structured AuthenticationConfiguration, in YAML.
+	Config *string `json:"config,omitempty"`
+}
+
+// OIDC is the configuration of an OpenID Connect issuer whose tokens are accepted by kube-apiserver.
+type OIDC struct {
+	IssuerURL      *string `json:"issuer-url,omitempty"`
+	ClientID       *string `json:"client-id,omitempty"`
+	UsernameClaim  *string `json:"username-claim,omitempty"`
+	UsernamePrefix *string `json:"username-prefix,omitempty"`
+	GroupsClaim    *string `json:"groups-claim,omitempty"`
+	GroupsPrefix   *string `json:"groups-prefix,omitempty"`
+	CACert         *string `json:"ca-crt,omitempty"`
+}
+
+func (c OIDC) GetIssuerURL() string      { return getField(c.IssuerURL) }
+func (c OIDC) GetClientID() string       { return getField(c.ClientID) }
+func (c OIDC) GetUsernameClaim() string  { return getField(c.UsernameClaim) }
+func (c OIDC) GetUsernamePrefix() string { return getField(c.UsernamePrefix) }
+func (c OIDC) GetGroupsClaim() string    { return getField(c.GroupsClaim) }
+func (c OIDC) GetGroupsPrefix() string   { return getField(c.GroupsPrefix) }
+func (c OIDC) GetCACert() string         { return getField(c.CACert) }
+func (c OIDC) Empty() bool               { return c == OIDC{} }
+
+func (c Authentication) GetConfig() string { return getField(c.Config) }
+func (c Authentication) Empty() bool       { return c.OIDC.Empty() && c.Config == nil }
+
+// authenticationAnnotations maps the authentication annotations to the respective options.
+func authenticationAnnotations(c *Authentication) map[string]**string {
+	return map[string]**string{
+		AnnotationAuthenticationOIDCIssuerURL:      &c.OIDC.IssuerURL,
+		AnnotationAuthenticationOIDCClientID:       &c.OIDC.ClientID,
+		AnnotationAuthenticationOIDCUsernameClaim:  &c.OIDC.UsernameClaim,
+		AnnotationAuthenticationOIDCUsernamePrefix: &c.OIDC.UsernamePrefix,
+		AnnotationAuthenticationOIDCGroupsClaim:    &c.OIDC.GroupsClaim,
+		AnnotationAuthenticationOIDCGroupsPrefix:   &c.OIDC.GroupsPrefix,
+		AnnotationAuthenticationOIDCCACert:         &c.OIDC.CACert,
+		AnnotationAuthenticationConfig:             &c.Config,
+	}
+}
+
+// authenticationFromAnnotations extracts the authentication configuration from the annotations.
+// authenticationFromAnnotations returns the remaining annotations. The input annotations are not modified.
+// An annotation value of "-" resets the respective option.
+func authenticationFromAnnotations(annotations Annotations) (Authentication, Annotations) {
+	var config Authentication
+	var remaining Annotations
+	if annotations != nil {
+		remaining = make(Annotations, len(annotations))
+	}
+	fields := authenticationAnnotations(&config)
+	for key, value := range annotations {
+		field, ok := fields[key]
+		if !ok {
+			remaining[key] = value
+			continue
+		}
+		if value == "-" {
+			value = ""
+		}
+		*field = utils.Pointer(value)
+	}
+	return config, remaining
+}
+
+// authenticationToAnnotations adds the authentication configuration to a copy of the annotations.
+// Options that were reset are not added.
+func authenticationToAnnotations(config Authentication, annotations map[string]string) map[string]string {
+	if config.Empty() {
+		return annotations
+	}
+
+	result := make(map[string]string, len(annotations)+8)
+	for key, value := range annotations {
+		result[key] = value
+	}
+	for key, field := range authenticationAnnotations(&config) {
+		if *field != nil && **field != "" {
+			result[key] = **field
+		}
+	}
+	return result
+}
+
+// ParseAuthenticationConfig parses and validates a structured AuthenticationConfiguration for kube-apiserver.
+func ParseAuthenticationConfig(config string) (*apiserverv1beta1.AuthenticationConfiguration, error) {
+	var result apiserverv1beta1.AuthenticationConfiguration
+	if err := yaml.UnmarshalStrict([]byte(config), &result); err != nil {
+		return nil, fmt.Errorf("failed to parse AuthenticationConfiguration: %w", err)
+	}
+	if result.Kind != "AuthenticationConfiguration" {
+		return nil, fmt.Errorf("kind must be AuthenticationConfiguration, not %q", result.Kind)
+	}
+	if result.APIVersion != AuthenticationConfigAPIVersion {
+		return nil, fmt.Errorf("apiVersion must be %s, not %q", AuthenticationConfigAPIVersion, result.APIVersion)
+	}
+	return &result, nil
+}
+
+// validate checks that the authentication configuration is complete and that it is not ambiguous.
+func (c Authentication) validate() error {
+	if c.GetConfig() != "" {
+		if c.OIDC.GetIssuerURL() != "" || c.OIDC.GetClientID() != "" {
+			return fmt.Errorf("authentication config cannot be combined with the OIDC options")
+		}
+		if _, err := ParseAuthenticationConfig(c.GetConfig()); err != nil {
+			return fmt.Errorf("invalid authentication config: %w", err)
+		}
+		return nil
+	}
+
+	if c.OIDC.GetIssuerURL() == "" {
+		if c.OIDC.GetClientID() != "" {
+			return fmt.Errorf("OIDC client ID requires the OIDC issuer URL to be set")
+		}
+		return nil
+	}
+	if u, err := url.Parse(c.OIDC.GetIssuerURL()); err != nil || u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("OIDC issuer URL %q must be a https:// URL", c.OIDC.GetIssuerURL())
+	}
+	if c.OIDC.GetClientID() == "" {
+		return fmt.Errorf("OIDC issuer URL requires the OIDC client ID to be set")
+	}
+	if caCert := c.OIDC.GetCACert(); caCert != "" {
+		if block, _ := pem.Decode([]byte(caCert)); block == nil || block.Type != "CERTIFICATE" {
+			return fmt.Errorf("OIDC CA certificate must be a PEM encoded certificate")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package types_test
+
+import (
+	"testing"
+
+	apiv1 "github.com/canonical/k8s-snap-api/api/v1"
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/k8s/pkg/utils"
+	. "github.com/onsi/gomega"
+)
+
+func TestAuthenticationAnnotations(t *testing.T) {
+	t.Run("FromUserFacing", func(t *testing.T) {
+		g := NewWithT(t)
+
+		config, err := types.ClusterConfigFromUserFacing(apiv1.UserFacingClusterConfig{
+			Annotations: map[string]string{
+				types.AnnotationAuthenticationOIDCIssuerURL:   "https://accounts.example.com",
+				types.AnnotationAuthenticationOIDCClientID:    "kubernetes",
+				types.AnnotationAuthenticationOIDCGroupsClaim: "groups",
+				"key": "value",
+			},
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(config.Authentication).To(Equal(types.Authentication{
+			OIDC: types.OIDC{
+				IssuerURL:   utils.Pointer("https://accounts.example.com"),
+				ClientID:    utils.Pointer("kubernetes"),
+				GroupsClaim: utils.Pointer("groups"),
+			},
+		}))
+		g.Expect(config.Annotations).To(Equal(types.Annotations{"key": "value"}))
+	})
+
+	t.Run("Reset", func(t *testing.T) {
+		g := NewWithT(t)
+
+		config, err := types.ClusterConfigFromUserFacing(apiv1.UserFacingClusterConfig{
+			Annotations: map[string]string{
+				types.AnnotationAuthenticationOIDCIssuerURL: "-",
+				types.AnnotationAuthenticationOIDCClientID:  "-",
+				types.AnnotationAuthenticationConfig:        "-",
+			},
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(config.Authentication).To(Equal(types.Authentication{
+			OIDC: types.OIDC{
+				IssuerURL: utils.Pointer(""),
+				ClientID:  utils.Pointer(""),
+			},
+			Config: utils.Pointer(""),
+		}))
+	})
+
+	t.Run("ToUserFacing", func(t *testing.T) {
+		g := NewWithT(t)
+
+		config := types.ClusterConfig{
+			Authentication: types.Authentication{
+				OIDC: types.OIDC{
+					IssuerURL:      utils.Pointer("https://accounts.example.com"),
+					ClientID:       utils.Pointer("kubernetes"),
+					UsernamePrefix: utils.Pointer(""),
+				},
+			},
+			Annotations: types.Annotations{"key": "value"},
+		}
+		g.Expect(config.ToUserFacing().Annotations).To(Equal(map[string]string{
+			types.AnnotationAuthenticationOIDCIssuerURL: "https://accounts.example.com",
+			types.AnnotationAuthenticationOIDCClientID:  "kubernetes",
+			"key": "value",
+		}))
+		g.Expect(config.Annotations).To(HaveLen(1))
+	})
+}
+
+func TestValidateAuthentication(t *testing.T) {
+	for _, tc := range []struct {
+		name           string
+		authentication types.Authentication
+		expectErr      bool
+	}{
+		{
+			name: "Empty",
+		},
+		{
+			name: "OIDC",
+			authentication: types.Authentication{OIDC: types.OIDC{
+				IssuerURL: utils.Pointer("https://accounts.example.com"),
+				ClientID:  utils.Pointer("kubernetes"),
+			}},
+		},
+		{
+			name: "OIDCInsecureIssuer",
+			authentication: types.Authentication{OIDC: types.OIDC{
+				IssuerURL: utils.Pointer("http://accounts.example.com"),
+				ClientID:  utils.Pointer("kubernetes"),
+			}},
+			expectErr: true,
+		},
+		{
+			name:           "OIDCWithoutClientID",
+			authentication: types.Authentication{OIDC: types.OIDC{IssuerURL: utils.Pointer("https://accounts.example.com")}},
+			expectErr:      true,
+		},
+		{
+			name:           "OIDCWithoutIssuer",
+			authentication: types.Authentication{OIDC: types.OIDC{ClientID: utils.Pointer("kubernetes")}},
+			expectErr:      true,
+		},
+		{
+			name: "OIDCInvalidCACert",
+			authentication: types.Authentication{OIDC: types.OIDC{
+				IssuerURL: utils.Pointer("https://accounts.example.com"),
+				ClientID:  utils.Pointer("kubernetes"),
+				CACert:    utils.Pointer("CA DATA"),
+			}},
+			expectErr: true,
+		},
+		{
+			name: "Config",
+			authentication: types.Authentication{
+				Config: utils.Pointer("apiVersion: apiserver.config.k8s.io/v1beta1\nkind: AuthenticationConfiguration\n"),
+			},
+		},
+		{
+			name: "ConfigInvalidKind",
+			authentication: types.Authentication{
+				Config: utils.Pointer("apiVersion: apiserver.config.k8s.io/v1beta1\nkind: AuthorizationConfiguration\n"),
+			},
+			expectErr: true,
+		},
+		{
+			name: "ConfigUnknownField",
+			authentication: types.Authentication{
+				Config: utils.Pointer("apiVersion: apiserver.config.k8s.io/v1beta1\nkind: AuthenticationConfiguration\noidc: {}\n"),
+			},
+			expectErr: true,
+		},
+		{
+			name: "ConfigWithOIDC",
+			authentication: types.Authentication{
+				OIDC: types.OIDC{
+					IssuerURL: utils.Pointer("https://accounts.example.com"),
+					ClientID:  utils.Pointer("kubernetes"),
+				},
+				Config: utils.Pointer("apiVersion: apiserver.config.k8s.io/v1beta1\nkind: AuthenticationConfiguration\n"),
+			},
+			expectErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			config := types.ClusterConfig{
+				Network: types.Network{
+					PodCIDR:     utils.Pointer("10.1.0.0/16"),
+					ServiceCIDR: utils.Pointer("10.152.183.0/24"),
+				},
+				Authentication: tc.authentication,
+			}
+			if tc.expectErr {
+				g.Expect(config.Validate()).ToNot(Succeed())
+			} else {
+				g.Expect(config.Validate()).To(Succeed())
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package types
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/canonical/k8s/pkg/utils"
+)
+
+const (
+	// AnnotationCertManagerEnabled enables or disables the cert-manager feature.
+	AnnotationCertManagerEnabled = "k8sd/v1alpha/cert-manager/enabled"
+	// AnnotationCertManagerIngressClusterIssuer is the name of the ClusterIssuer that issues the default ingress TLS certificate.
+	AnnotationCertManagerIngressClusterIssuer = "k8sd/v1alpha/cert-manager/ingress-cluster-issuer"
+	// AnnotationCertManagerIngressDNSNames is a comma-separated list of DNS names for the default ingress TLS certificate.
+	AnnotationCertManagerIngressDNSNames = "k8sd/v1alpha/cert-manager/ingress-dns-names"
+)
+
+// CertManager is the configuration of the cert-manager feature.
+type CertManager struct {
+	Enabled              *bool     `json:"enabled,omitempty"`
+	IngressClusterIssuer *string   `json:"ingress-cluster-issuer,omitempty"`
+	IngressDNSNames      *[]string `json:"ingress-dns-names,omitempty"`
+}
+
+func (c CertManager) GetEnabled() bool                { return getField(c.Enabled) }
+func (c CertManager) GetIngressClusterIssuer() string { return getField(c.IngressClusterIssuer) }
+func (c CertManager) GetIngressDNSNames() []string    { return getField(c.IngressDNSNames) }
+func (c CertManager) Empty() bool {
+	return c.Enabled == nil && c.IngressClusterIssuer == nil && c.IngressDNSNames == nil
+}
+
+// certManagerFromAnnotations extracts the cert-manager configuration from the annotations.
+// certManagerFromAnnotations returns the remaining annotations. The input annotations are not modified.
+// An annotation value of "-" resets the respective option. The enabled option is reset to its default when merged
+// into an existing configuration, so its "-" annotation is kept in the remaining annotations.
+func certManagerFromAnnotations(annotations Annotations) (CertManager, Annotations, error) {
+	var config CertManager
+	var remaining Annotations
+	if annotations != nil {
+		remaining = make(Annotations, len(annotations))
+	}
+	for key, value := range annotations {
+		switch key {
+		case AnnotationCertManagerEnabled:
+			if value == "-" {
+				remaining[key] = value
+				continue
+			}
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				return CertManager{}, nil, fmt.Errorf("invalid value %q for annotation %q: %w", value, key, err)
+			}
+			config.Enabled = utils.Pointer(enabled)
+		case AnnotationCertManagerIngressClusterIssuer:
+			if value == "-" {
+				value = ""
+			}
+			config.IngressClusterIssuer = utils.Pointer(value)
+		case AnnotationCertManagerIngressDNSNames:
+			names := []string{}
+			if value != "-" {
+				for _, name := range strings.Split(value, ",") {
+					if name = strings.TrimSpace(name); name != "" {
+						names = append(names, name)
+					}
+				}
+			}
+			config.IngressDNSNames = utils.Pointer(names)
+		default:
+			remaining[key] = value
+		}
+	}
+	return config, remaining, nil
+}
+
+// resetCertManagerOptions resets the enabled option of config if it is reset with "-" in the annotations.
+func resetCertManagerOptions(config *CertManager, annotations Annotations) {
+	if annotations[AnnotationCertManagerEnabled] == "-" {
+		config.Enabled = nil
+	}
+}
+
+// certManagerToAnnotations adds the cert-manager configuration to a copy of the annotations.
+func certManagerToAnnotations(config CertManager, annotations Annotations) map[string]string {
+	if config.Empty() {
+		return map[string]string(annotations)
+	}
+
+	result := make(map[string]string, len(annotations)+3)
+	for key, value := range annotations {
+		result[key] = value
+	}
+	if config.Enabled != nil {
+		result[AnnotationCertManagerEnabled] = strconv.FormatBool(*config.Enabled)
+	}
+	if config.IngressClusterIssuer != nil {
+		result[AnnotationCertManagerIngressClusterIssuer] = *config.IngressClusterIssuer
+	}
+	if config.IngressDNSNames != nil {
+		result[AnnotationCertManagerIngressDNSNames] = strings.Join(*config.IngressDNSNames, ",")
+	}
+	return result
+}
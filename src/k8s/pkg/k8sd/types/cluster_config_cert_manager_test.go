@@ -0,0 +1,156 @@
+package types_test
+
+import (
+	"testing"
+
+	apiv1 "github.com/canonical/k8s-snap-api/api/v1"
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/k8s/pkg/utils"
+	. "github.com/onsi/gomega"
+)
+
+func TestCertManagerAnnotations(t *testing.T) {
+	t.Run("FromUserFacing", func(t *testing.T) {
+		g := NewWithT(t)
+
+		config, err := types.ClusterConfigFromUserFacing(apiv1.UserFacingClusterConfig{
+			Annotations: map[string]string{
+				types.AnnotationCertManagerEnabled:              "true",
+				types.AnnotationCertManagerIngressClusterIssuer: "letsencrypt",
+				types.AnnotationCertManagerIngressDNSNames:      "example.com, *.example.com",
+				"key": "value",
+			},
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(config.CertManager).To(Equal(types.CertManager{
+			Enabled:              utils.Pointer(true),
+			IngressClusterIssuer: utils.Pointer("letsencrypt"),
+			IngressDNSNames:      utils.Pointer([]string{"example.com", "*.example.com"}),
+		}))
+		g.Expect(config.Annotations).To(Equal(types.Annotations{"key": "value"}))
+	})
+
+	t.Run("Reset", func(t *testing.T) {
+		g := NewWithT(t)
+
+		config, err := types.ClusterConfigFromUserFacing(apiv1.UserFacingClusterConfig{
+			Annotations: map[string]string{
+				types.AnnotationCertManagerEnabled:              "-",
+				types.AnnotationCertManagerIngressClusterIssuer: "-",
+				types.AnnotationCertManagerIngressDNSNames:      "-",
+			},
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(config.CertManager).To(Equal(types.CertManager{
+			IngressClusterIssuer: utils.Pointer(""),
+			IngressDNSNames:      utils.Pointer([]string{}),
+		}))
+		g.Expect(config.Annotations).To(Equal(types.Annotations{types.AnnotationCertManagerEnabled: "-"}))
+
+		existing := types.ClusterConfig{
+			CertManager: types.CertManager{Enabled: utils.Pointer(true)},
+		}
+		existing.SetDefaults()
+		merged, err := types.MergeClusterConfig(existing, config)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(merged.CertManager.Enabled).To(BeNil())
+		g.Expect(merged.Annotations).To(BeEmpty())
+	})
+
+	t.Run("InvalidEnabled", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := types.ClusterConfigFromUserFacing(apiv1.UserFacingClusterConfig{
+			Annotations: map[string]string{types.AnnotationCertManagerEnabled: "yes please"},
+		})
+		g.Expect(err).To(HaveOccurred())
+	})
+
+	t.Run("ToUserFacing", func(t *testing.T) {
+		g := NewWithT(t)
+
+		config := types.ClusterConfig{
+			CertManager: types.CertManager{
+				Enabled:              utils.Pointer(true),
+				IngressClusterIssuer: utils.Pointer("letsencrypt"),
+				IngressDNSNames:      utils.Pointer([]string{"example.com", "*.example.com"}),
+			},
+			Annotations: types.Annotations{"key": "value"},
+		}
+		g.Expect(config.ToUserFacing().Annotations).To(Equal(map[string]string{
+			types.AnnotationCertManagerEnabled:              "true",
+			types.AnnotationCertManagerIngressClusterIssuer: "letsencrypt",
+			types.AnnotationCertManagerIngressDNSNames:      "example.com,*.example.com",
+			"key": "value",
+		}))
+		g.Expect(config.Annotations).To(HaveLen(1))
+	})
+}
+
+func TestValidateCertManager(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		certManager types.CertManager
+		ingress     types.Ingress
+		expectErr   bool
+	}{
+		{
+			name:        "Enabled",
+			certManager: types.CertManager{Enabled: utils.Pointer(true)},
+		},
+		{
+			name: "IngressClusterIssuer",
+			certManager: types.CertManager{
+				Enabled:              utils.Pointer(true),
+				IngressClusterIssuer: utils.Pointer("letsencrypt"),
+				IngressDNSNames:      utils.Pointer([]string{"example.com"}),
+			},
+			ingress: types.Ingress{DefaultTLSSecret: utils.Pointer("ingress-tls")},
+		},
+		{
+			name: "IngressClusterIssuerWithoutCertManager",
+			certManager: types.CertManager{
+				Enabled:              utils.Pointer(false),
+				IngressClusterIssuer: utils.Pointer("letsencrypt"),
+				IngressDNSNames:      utils.Pointer([]string{"example.com"}),
+			},
+			ingress:   types.Ingress{DefaultTLSSecret: utils.Pointer("ingress-tls")},
+			expectErr: true,
+		},
+		{
+			name: "IngressClusterIssuerWithoutSecret",
+			certManager: types.CertManager{
+				Enabled:              utils.Pointer(true),
+				IngressClusterIssuer: utils.Pointer("letsencrypt"),
+				IngressDNSNames:      utils.Pointer([]string{"example.com"}),
+			},
+			expectErr: true,
+		},
+		{
+			name: "IngressClusterIssuerWithoutDNSNames",
+			certManager: types.CertManager{
+				Enabled:              utils.Pointer(true),
+				IngressClusterIssuer: utils.Pointer("letsencrypt"),
+			},
+			ingress:   types.Ingress{DefaultTLSSecret: utils.Pointer("ingress-tls")},
+			expectErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			config := types.ClusterConfig{
+				Network: types.Network{
+					PodCIDR:     utils.Pointer("10.1.0.0/16"),
+					ServiceCIDR: utils.Pointer("10.152.183.0/24"),
+				},
+				Ingress:     tc.ingress,
+				CertManager: tc.certManager,
+			}
+			if tc.expectErr {
+				g.Expect(config.Validate()).ToNot(Succeed())
+			} else {
+				g.Expect(config.Validate()).To(Succeed())
+			}
+		})
+	}
+}
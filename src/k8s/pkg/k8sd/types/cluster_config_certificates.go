@@ -1,5 +1,16 @@
 package types
 
+import (
+	"fmt"
+
+	"github.com/canonical/k8s/pkg/utils"
+	pkiutil "github.com/canonical/k8s/pkg/utils/pki"
+)
+
+// AnnotationCertificatesKeyAlgorithm is the algorithm of the private keys generated for the cluster certificates.
+// The key algorithm can only be set when bootstrapping the cluster, see pkiutil.KeyAlgorithms for the supported values.
+const AnnotationCertificatesKeyAlgorithm = "k8sd/v1alpha/certificates/key-algorithm"
+
 type Certificates struct {
 	CACert                     *string `json:"ca-crt,omitempty"`
 	CAKey                      *string `json:"ca-key,omitempty"`
@@ -14,6 +25,7 @@ type Certificates struct {
 	AdminClientKey             *string `json:"admin-client-key,omitempty"`
 	K8sdPublicKey              *string `json:"k8sd-public-key,omitempty"`
 	K8sdPrivateKey             *string `json:"k8sd-private-key,omitempty"`
+	KeyAlgorithm               *string `json:"key-algorithm,omitempty"`
 }
 
 func (c Certificates) GetCACert() string { return getField(c.CACert) }
@@ -47,6 +59,44 @@ func (c Certificates) GetAdminClientCert() string { return getField(c.AdminClien
 func (c Certificates) GetAdminClientKey() string  { return getField(c.AdminClientKey) }
 func (c Certificates) GetK8sdPublicKey() string   { return getField(c.K8sdPublicKey) }
 func (c Certificates) GetK8sdPrivateKey() string  { return getField(c.K8sdPrivateKey) }
+func (c Certificates) GetKeyAlgorithm() pkiutil.KeyAlgorithm {
+	return pkiutil.KeyAlgorithm(getField(c.KeyAlgorithm))
+}
 
 // Empty returns true if all Certificates fields are unset.
 func (c Certificates) Empty() bool { return c == Certificates{} }
+
+// keyAlgorithmFromAnnotations extracts the key algorithm of the cluster certificates from the annotations.
+// keyAlgorithmFromAnnotations returns the remaining annotations. The input annotations are not modified.
+func keyAlgorithmFromAnnotations(annotations Annotations) (*string, Annotations, error) {
+	value, ok := annotations.Get(AnnotationCertificatesKeyAlgorithm)
+	if !ok {
+		return nil, annotations, nil
+	}
+	algorithm, err := pkiutil.ParseKeyAlgorithm(value)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid value %q for annotation %q: %w", value, AnnotationCertificatesKeyAlgorithm, err)
+	}
+
+	remaining := make(Annotations, len(annotations)-1)
+	for key, value := range annotations {
+		if key != AnnotationCertificatesKeyAlgorithm {
+			remaining[key] = value
+		}
+	}
+	return utils.Pointer(string(algorithm)), remaining, nil
+}
+
+// keyAlgorithmToAnnotations adds the key algorithm of the cluster certificates to a copy of the annotations.
+func keyAlgorithmToAnnotations(keyAlgorithm *string, annotations Annotations) map[string]string {
+	if keyAlgorithm == nil {
+		return map[string]string(annotations)
+	}
+
+	result := make(map[string]string, len(annotations)+1)
+	for key, value := range annotations {
+		result[key] = value
+	}
+	result[AnnotationCertificatesKeyAlgorithm] = *keyAlgorithm
+	return result
+}
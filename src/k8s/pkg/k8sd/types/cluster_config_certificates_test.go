@@ -0,0 +1,61 @@
+package types_test
+
+import (
+	"testing"
+
+	apiv1 "github.com/canonical/k8s-snap-api/api/v1"
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/k8s/pkg/utils"
+	pkiutil "github.com/canonical/k8s/pkg/utils/pki"
+	. "github.com/onsi/gomega"
+)
+
+func TestKeyAlgorithmAnnotation(t *testing.T) {
+	t.Run("FromBootstrapConfig", func(t *testing.T) {
+		g := NewWithT(t)
+
+		config, err := types.ClusterConfigFromBootstrapConfig(apiv1.BootstrapConfig{
+			ClusterConfig: apiv1.UserFacingClusterConfig{
+				Annotations: map[string]string{
+					types.AnnotationCertificatesKeyAlgorithm: "ecdsa-p256",
+					"key":                                    "value",
+				},
+			},
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(config.Certificates.GetKeyAlgorithm()).To(Equal(pkiutil.KeyAlgorithmECDSAP256))
+		g.Expect(config.Annotations).To(Equal(types.Annotations{"key": "value"}))
+	})
+
+	t.Run("Default", func(t *testing.T) {
+		g := NewWithT(t)
+
+		config, err := types.ClusterConfigFromBootstrapConfig(apiv1.BootstrapConfig{})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(config.Certificates.KeyAlgorithm).To(BeNil())
+		g.Expect(config.Certificates.GetKeyAlgorithm()).To(BeEmpty())
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := types.ClusterConfigFromUserFacing(apiv1.UserFacingClusterConfig{
+			Annotations: map[string]string{types.AnnotationCertificatesKeyAlgorithm: "dsa-1024"},
+		})
+		g.Expect(err).To(HaveOccurred())
+	})
+
+	t.Run("ToUserFacing", func(t *testing.T) {
+		g := NewWithT(t)
+
+		config := types.ClusterConfig{
+			Certificates: types.Certificates{KeyAlgorithm: utils.Pointer("ed25519")},
+			Annotations:  types.Annotations{"key": "value"},
+		}
+		g.Expect(config.ToUserFacing().Annotations).To(Equal(map[string]string{
+			types.AnnotationCertificatesKeyAlgorithm: "ed25519",
+			"key":                                    "value",
+		}))
+		g.Expect(config.Annotations).To(HaveLen(1))
+	})
+}
@@ -0,0 +1,132 @@
+package types
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/canonical/k8s/pkg/utils"
+)
+
+const (
+	// AnnotationCiliumHubbleRelay enables or disables the Hubble relay, which provides cluster-wide flow visibility.
+	AnnotationCiliumHubbleRelay = "k8sd/v1alpha1/cilium/hubble-relay"
+	// AnnotationCiliumHubbleUI enables or disables the Hubble UI. The Hubble UI requires the Hubble relay.
+	AnnotationCiliumHubbleUI = "k8sd/v1alpha1/cilium/hubble-ui"
+	// AnnotationCiliumEncryption is the transparent encryption method for pod traffic. Only "wireguard" is supported.
+	AnnotationCiliumEncryption = "k8sd/v1alpha1/cilium/encryption"
+	// AnnotationCiliumKubeProxyReplacement enables or disables the Cilium kube-proxy replacement.
+	// If enabled, k8sd stops the kube-proxy service on all nodes.
+	AnnotationCiliumKubeProxyReplacement = "k8sd/v1alpha1/cilium/kube-proxy-replacement"
+	// AnnotationCiliumBandwidthManager enables or disables the Cilium bandwidth manager.
+	AnnotationCiliumBandwidthManager = "k8sd/v1alpha1/cilium/bandwidth-manager"
+
+	// CiliumEncryptionWireGuard is the WireGuard transparent encryption method.
+	CiliumEncryptionWireGuard = "wireguard"
+)
+
+// Cilium is the configuration of the Cilium network implementation. It is ignored by other network implementations.
+type Cilium struct {
+	HubbleRelay          *bool   `json:"hubble-relay,omitempty"`
+	HubbleUI             *bool   `json:"hubble-ui,omitempty"`
+	Encryption           *string `json:"encryption,omitempty"`
+	KubeProxyReplacement *bool   `json:"kube-proxy-replacement,omitempty"`
+	BandwidthManager     *bool   `json:"bandwidth-manager,omitempty"`
+}
+
+func (c Cilium) GetHubbleRelay() bool          { return getField(c.HubbleRelay) }
+func (c Cilium) GetHubbleUI() bool             { return getField(c.HubbleUI) }
+func (c Cilium) GetEncryption() string         { return getField(c.Encryption) }
+func (c Cilium) GetKubeProxyReplacement() bool { return getField(c.KubeProxyReplacement) }
+func (c Cilium) GetBandwidthManager() bool     { return getField(c.BandwidthManager) }
+func (c Cilium) Empty() bool                   { return c == Cilium{} }
+
+// ciliumBoolAnnotations maps the boolean Cilium annotations to the respective options.
+func ciliumBoolAnnotations(c *Cilium) map[string]**bool {
+	return map[string]**bool{
+		AnnotationCiliumHubbleRelay:          &c.HubbleRelay,
+		AnnotationCiliumHubbleUI:             &c.HubbleUI,
+		AnnotationCiliumKubeProxyReplacement: &c.KubeProxyReplacement,
+		AnnotationCiliumBandwidthManager:     &c.BandwidthManager,
+	}
+}
+
+// ciliumFromAnnotations extracts the Cilium configuration from the annotations.
+// ciliumFromAnnotations returns the remaining annotations. The input annotations are not modified.
+// An annotation value of "-" resets the respective option. Boolean options are reset to their default when merged
+// into an existing configuration, so their "-" annotations are kept in the remaining annotations.
+func ciliumFromAnnotations(annotations Annotations) (Cilium, Annotations, error) {
+	var config Cilium
+	var remaining Annotations
+	if annotations != nil {
+		remaining = make(Annotations, len(annotations))
+	}
+	boolFields := ciliumBoolAnnotations(&config)
+	for key, value := range annotations {
+		if field, ok := boolFields[key]; ok {
+			if value == "-" {
+				remaining[key] = value
+				continue
+			}
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				return Cilium{}, nil, fmt.Errorf("invalid value %q for annotation %q: %w", value, key, err)
+			}
+			*field = utils.Pointer(enabled)
+			continue
+		}
+
+		switch key {
+		case AnnotationCiliumEncryption:
+			if value == "-" {
+				value = ""
+			}
+			config.Encryption = utils.Pointer(value)
+		default:
+			remaining[key] = value
+		}
+	}
+	return config, remaining, nil
+}
+
+// ciliumToAnnotations adds the Cilium configuration to a copy of the annotations.
+func ciliumToAnnotations(config Cilium, annotations map[string]string) map[string]string {
+	if config.Empty() {
+		return annotations
+	}
+
+	result := make(map[string]string, len(annotations)+5)
+	for key, value := range annotations {
+		result[key] = value
+	}
+	for key, field := range ciliumBoolAnnotations(&config) {
+		if *field != nil {
+			result[key] = strconv.FormatBool(**field)
+		}
+	}
+	if config.Encryption != nil && *config.Encryption != "" {
+		result[AnnotationCiliumEncryption] = *config.Encryption
+	}
+	return result
+}
+
+// resetCiliumOptions resets the boolean Cilium options of config that are reset with "-" in the annotations.
+func resetCiliumOptions(config *Cilium, annotations Annotations) {
+	for key, field := range ciliumBoolAnnotations(config) {
+		if annotations[key] == "-" {
+			*field = nil
+		}
+	}
+}
+
+// validate checks that the Cilium options are supported and consistent.
+func (c Cilium) validate() error {
+	switch c.GetEncryption() {
+	case "", CiliumEncryptionWireGuard:
+	default:
+		return fmt.Errorf("unsupported encryption %q, only %q is supported", c.GetEncryption(), CiliumEncryptionWireGuard)
+	}
+	if c.GetHubbleUI() && !c.GetHubbleRelay() {
+		return fmt.Errorf("hubble UI requires the hubble relay to be enabled")
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package types_test
+
+import (
+	"testing"
+
+	apiv1 "github.com/canonical/k8s-snap-api/api/v1"
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/k8s/pkg/utils"
+	. "github.com/onsi/gomega"
+)
+
+func TestCiliumAnnotations(t *testing.T) {
+	t.Run("FromUserFacing", func(t *testing.T) {
+		g := NewWithT(t)
+
+		config, err := types.ClusterConfigFromUserFacing(apiv1.UserFacingClusterConfig{
+			Annotations: map[string]string{
+				types.AnnotationCiliumHubbleRelay:          "true",
+				types.AnnotationCiliumEncryption:           "wireguard",
+				types.AnnotationCiliumKubeProxyReplacement: "false",
+				"key": "value",
+			},
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(config.Network.Cilium).To(Equal(types.Cilium{
+			HubbleRelay:          utils.Pointer(true),
+			Encryption:           utils.Pointer("wireguard"),
+			KubeProxyReplacement: utils.Pointer(false),
+		}))
+		g.Expect(config.Annotations).To(Equal(types.Annotations{"key": "value"}))
+	})
+
+	t.Run("Reset", func(t *testing.T) {
+		g := NewWithT(t)
+
+		config, err := types.ClusterConfigFromUserFacing(apiv1.UserFacingClusterConfig{
+			Annotations: map[string]string{
+				types.AnnotationCiliumBandwidthManager: "-",
+				types.AnnotationCiliumEncryption:       "-",
+			},
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(config.Network.Cilium).To(Equal(types.Cilium{
+			Encryption: utils.Pointer(""),
+		}))
+		g.Expect(config.Annotations).To(Equal(types.Annotations{types.AnnotationCiliumBandwidthManager: "-"}))
+
+		existing := types.ClusterConfig{
+			Network: types.Network{
+				Cilium: types.Cilium{
+					BandwidthManager: utils.Pointer(true),
+					HubbleRelay:      utils.Pointer(true),
+					Encryption:       utils.Pointer("wireguard"),
+				},
+			},
+		}
+		existing.SetDefaults()
+		merged, err := types.MergeClusterConfig(existing, config)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(merged.Network.Cilium).To(Equal(types.Cilium{
+			HubbleRelay: utils.Pointer(true),
+			Encryption:  utils.Pointer(""),
+		}))
+		g.Expect(merged.Annotations).To(BeEmpty())
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := types.ClusterConfigFromUserFacing(apiv1.UserFacingClusterConfig{
+			Annotations: map[string]string{
+				types.AnnotationCiliumHubbleUI: "yes please",
+			},
+		})
+		g.Expect(err).To(HaveOccurred())
+	})
+
+	t.Run("ToUserFacing", func(t *testing.T) {
+		g := NewWithT(t)
+
+		config := types.ClusterConfig{
+			Network: types.Network{
+				Cilium: types.Cilium{
+					HubbleRelay:      utils.Pointer(true),
+					BandwidthManager: utils.Pointer(false),
+					Encryption:       utils.Pointer(""),
+				},
+			},
+			Annotations: types.Annotations{"key": "value"},
+		}
+		g.Expect(config.ToUserFacing().Annotations).To(Equal(map[string]string{
+			types.AnnotationCiliumHubbleRelay:      "true",
+			types.AnnotationCiliumBandwidthManager: "false",
+			"key":                                  "value",
+		}))
+		g.Expect(config.Annotations).To(HaveLen(1))
+	})
+}
+
+func TestValidateCilium(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		cilium    types.Cilium
+		expectErr bool
+	}{
+		{name: "Empty"},
+		{name: "WireGuard", cilium: types.Cilium{Encryption: utils.Pointer("wireguard")}},
+		{name: "IPsec", cilium: types.Cilium{Encryption: utils.Pointer("ipsec")}, expectErr: true},
+		{name: "HubbleUI", cilium: types.Cilium{HubbleRelay: utils.Pointer(true), HubbleUI: utils.Pointer(true)}},
+		{name: "HubbleUIWithoutRelay", cilium: types.Cilium{HubbleUI: utils.Pointer(true)}, expectErr: true},
+		{name: "KubeProxyReplacement", cilium: types.Cilium{KubeProxyReplacement: utils.Pointer(true)}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			config := types.ClusterConfig{Network: types.Network{Cilium: tc.cilium}}
+			config.SetDefaults()
+			err := config.Validate()
+			if tc.expectErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+		})
+	}
+}
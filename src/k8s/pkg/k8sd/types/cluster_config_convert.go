@@ -42,6 +42,26 @@ func ClusterConfigFromBootstrapConfig(b apiv1.BootstrapConfig) (ClusterConfig, e
 			Type:          utils.Pointer("k8s-dqlite"),
 			K8sDqlitePort: b.K8sDqlitePort,
 		}
+	case "etcd":
+		if len(b.DatastoreServers) > 0 {
+			return ClusterConfig{}, fmt.Errorf("datastore-servers needs datastore-type to be external, not %q", b.GetDatastoreType())
+		}
+		if b.GetDatastoreCACert() != "" {
+			return ClusterConfig{}, fmt.Errorf("datastore-ca-crt needs datastore-type to be external, not %q", b.GetDatastoreType())
+		}
+		if b.GetDatastoreClientCert() != "" {
+			return ClusterConfig{}, fmt.Errorf("datastore-client-crt needs datastore-type to be external, not %q", b.GetDatastoreType())
+		}
+		if b.GetDatastoreClientKey() != "" {
+			return ClusterConfig{}, fmt.Errorf("datastore-client-key needs datastore-type to be external, not %q", b.GetDatastoreType())
+		}
+		if b.GetK8sDqlitePort() != 0 {
+			return ClusterConfig{}, fmt.Errorf("k8s-dqlite-port needs datastore-type to be k8s-dqlite")
+		}
+
+		config.Datastore = Datastore{
+			Type: utils.Pointer("etcd"),
+		}
 	case "external":
 		if len(b.DatastoreServers) == 0 {
 			return ClusterConfig{}, fmt.Errorf("datastore type is external but no datastore servers were set")
@@ -74,21 +94,56 @@ func ClusterConfigFromBootstrapConfig(b apiv1.BootstrapConfig) (ClusterConfig, e
 }
 
 // ClusterConfigFromUserFacing converts UserFacingClusterConfig from public API into a ClusterConfig.
+// The user-facing cluster configuration is part of the stable API, so cert-manager, Cilium, authentication, the key
+// algorithm, the network provider and the values overrides are configured through annotations, which are converted here.
 func ClusterConfigFromUserFacing(u apiv1.UserFacingClusterConfig) (ClusterConfig, error) {
 	cidrs, ipRanges, err := loadBalancerCIDRsFromAPI(u.LoadBalancer.CIDRs)
 	if err != nil {
 		return ClusterConfig{}, fmt.Errorf("invalid load-balancer.cidrs: %w", err)
 	}
 
+	certManager, annotations, err := certManagerFromAnnotations(Annotations(u.Annotations))
+	if err != nil {
+		return ClusterConfig{}, fmt.Errorf("invalid cert-manager configuration: %w", err)
+	}
+
+	valuesOverrides, annotations, err := valuesOverridesFromAnnotations(annotations)
+	if err != nil {
+		return ClusterConfig{}, fmt.Errorf("invalid values overrides: %w", err)
+	}
+
+	keyAlgorithm, annotations, err := keyAlgorithmFromAnnotations(annotations)
+	if err != nil {
+		return ClusterConfig{}, fmt.Errorf("invalid certificates configuration: %w", err)
+	}
+
+	authentication, annotations := authenticationFromAnnotations(annotations)
+
+	cilium, annotations, err := ciliumFromAnnotations(annotations)
+	if err != nil {
+		return ClusterConfig{}, fmt.Errorf("invalid network configuration: %w", err)
+	}
+
+	networkProvider, migrationPodCIDR, annotations, err := networkProviderFromAnnotations(annotations)
+	if err != nil {
+		return ClusterConfig{}, fmt.Errorf("invalid network configuration: %w", err)
+	}
+
 	return ClusterConfig{
-		Annotations: Annotations(u.Annotations),
+		Annotations: annotations,
+		Certificates: Certificates{
+			KeyAlgorithm: keyAlgorithm,
+		},
 		Kubelet: Kubelet{
 			ClusterDNS:    u.DNS.ServiceIP,
 			ClusterDomain: u.DNS.ClusterDomain,
 			CloudProvider: u.CloudProvider,
 		},
 		Network: Network{
-			Enabled: u.Network.Enabled,
+			Enabled:          u.Network.Enabled,
+			Provider:         networkProvider,
+			MigrationPodCIDR: migrationPodCIDR,
+			Cilium:           cilium,
 		},
 		DNS: DNS{
 			Enabled:             u.DNS.Enabled,
@@ -123,6 +178,9 @@ func ClusterConfigFromUserFacing(u apiv1.UserFacingClusterConfig) (ClusterConfig
 		Gateway: Gateway{
 			Enabled: u.Gateway.Enabled,
 		},
+		CertManager:     certManager,
+		ValuesOverrides: valuesOverrides,
+		Authentication:  authentication,
 	}, nil
 }
 
@@ -167,6 +225,6 @@ func (c ClusterConfig) ToUserFacing() apiv1.UserFacingClusterConfig {
 			Enabled: c.Gateway.Enabled,
 		},
 		CloudProvider: c.Kubelet.CloudProvider,
-		Annotations:   map[string]string(c.Annotations),
+		Annotations:   networkProviderToAnnotations(c.Network, ciliumToAnnotations(c.Network.Cilium, authenticationToAnnotations(c.Authentication, keyAlgorithmToAnnotations(c.Certificates.KeyAlgorithm, valuesOverridesToAnnotations(c.ValuesOverrides, certManagerToAnnotations(c.CertManager, c.Annotations)))))),
 	}
 }
@@ -165,6 +165,20 @@ func TestClusterConfigFromBootstrapConfig(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Etcd",
+			bootstrap: apiv1.BootstrapConfig{
+				DatastoreType: utils.Pointer("etcd"),
+			},
+			expectConfig: types.ClusterConfig{
+				APIServer: types.APIServer{
+					AuthorizationMode: utils.Pointer("Node,RBAC"),
+				},
+				Datastore: types.Datastore{
+					Type: utils.Pointer("etcd"),
+				},
+			},
+		},
 		{
 			name: "ControlPlainTaints",
 			bootstrap: apiv1.BootstrapConfig{
@@ -233,6 +247,20 @@ func TestClusterConfigFromBootstrapConfig(t *testing.T) {
 					K8sDqlitePort:    utils.Pointer(18080),
 				},
 			},
+			{
+				name: "EtcdWithK8sDqlitePort",
+				bootstrap: apiv1.BootstrapConfig{
+					DatastoreType: utils.Pointer("etcd"),
+					K8sDqlitePort: utils.Pointer(18080),
+				},
+			},
+			{
+				name: "EtcdWithExternalServers",
+				bootstrap: apiv1.BootstrapConfig{
+					DatastoreType:    utils.Pointer("etcd"),
+					DatastoreServers: []string{"http://10.0.0.1:2379"},
+				},
+			},
 			{
 				name: "ExternalWithoutServers",
 				bootstrap: apiv1.BootstrapConfig{
@@ -17,6 +17,11 @@ type Datastore struct {
 	ExternalCACert     *string   `json:"external-ca-crt,omitempty"`
 	ExternalClientCert *string   `json:"external-client-crt,omitempty"`
 	ExternalClientKey  *string   `json:"external-client-key,omitempty"`
+
+	EtcdPort     *int    `json:"etcd-port,omitempty"`
+	EtcdPeerPort *int    `json:"etcd-peer-port,omitempty"`
+	EtcdCACert   *string `json:"etcd-ca-crt,omitempty"`
+	EtcdCAKey    *string `json:"etcd-ca-key,omitempty"`
 }
 
 func (c Datastore) GetType() string               { return getField(c.Type) }
@@ -27,6 +32,10 @@ func (c Datastore) GetExternalServers() []string  { return getField(c.ExternalSe
 func (c Datastore) GetExternalCACert() string     { return getField(c.ExternalCACert) }
 func (c Datastore) GetExternalClientCert() string { return getField(c.ExternalClientCert) }
 func (c Datastore) GetExternalClientKey() string  { return getField(c.ExternalClientKey) }
+func (c Datastore) GetEtcdPort() int              { return getField(c.EtcdPort) }
+func (c Datastore) GetEtcdPeerPort() int          { return getField(c.EtcdPeerPort) }
+func (c Datastore) GetEtcdCACert() string         { return getField(c.EtcdCACert) }
+func (c Datastore) GetEtcdCAKey() string          { return getField(c.EtcdCAKey) }
 func (c Datastore) Empty() bool                   { return c == Datastore{} }
 
 // DatastorePathsProvider is to avoid circular dependency for snap.Snap in Datastore.ToKubeAPIServerArguments().
@@ -69,6 +78,13 @@ func (c Datastore) ToKubeAPIServerArguments(p DatastorePathsProvider) (map[strin
 				deleteArgs = append(deleteArgs, loop.arg)
 			}
 		}
+	case "etcd":
+		// the local etcd member always listens on the loopback address
+		updateArgs["--etcd-servers"] = fmt.Sprintf("https://127.0.0.1:%d", c.GetEtcdPort())
+		// the certificates will be written by setup.EnsureEtcdPKI(), here we only set the paths
+		updateArgs["--etcd-cafile"] = filepath.Join(p.EtcdPKIDir(), "ca.crt")
+		updateArgs["--etcd-certfile"] = filepath.Join(p.EtcdPKIDir(), "client.crt")
+		updateArgs["--etcd-keyfile"] = filepath.Join(p.EtcdPKIDir(), "client.key")
 	}
 
 	return updateArgs, deleteArgs
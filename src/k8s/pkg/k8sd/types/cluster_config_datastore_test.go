@@ -68,6 +68,19 @@ func TestDatastoreToKubeAPIServerArguments(t *testing.T) {
 			},
 			expectDeleteArgs: []string{"--etcd-certfile", "--etcd-keyfile"},
 		},
+		{
+			name: "Etcd",
+			config: types.Datastore{
+				Type:     utils.Pointer("etcd"),
+				EtcdPort: utils.Pointer(2379),
+			},
+			expectUpdateArgs: map[string]string{
+				"--etcd-servers":  "https://127.0.0.1:2379",
+				"--etcd-cafile":   "/pki/etcd/ca.crt",
+				"--etcd-certfile": "/pki/etcd/client.crt",
+				"--etcd-keyfile":  "/pki/etcd/client.key",
+			},
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			g := NewWithT(t)
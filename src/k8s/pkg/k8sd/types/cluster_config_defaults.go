@@ -29,6 +29,14 @@ func (c *ClusterConfig) SetDefaults() {
 	if c.Datastore.GetK8sDqlitePort() == 0 {
 		c.Datastore.K8sDqlitePort = utils.Pointer(9000)
 	}
+	if c.Datastore.GetType() == "etcd" {
+		if c.Datastore.GetEtcdPort() == 0 {
+			c.Datastore.EtcdPort = utils.Pointer(2379)
+		}
+		if c.Datastore.GetEtcdPeerPort() == 0 {
+			c.Datastore.EtcdPeerPort = utils.Pointer(2380)
+		}
+	}
 	// kubelet
 	if c.Kubelet.GetClusterDomain() == "" {
 		c.Kubelet.ClusterDomain = utils.Pointer("cluster.local")
@@ -99,4 +107,14 @@ func (c *ClusterConfig) SetDefaults() {
 	if c.MetricsServer.Enabled == nil {
 		c.MetricsServer.Enabled = utils.Pointer(true)
 	}
+	// cert-manager
+	if c.CertManager.Enabled == nil {
+		c.CertManager.Enabled = utils.Pointer(false)
+	}
+	if c.CertManager.IngressClusterIssuer == nil {
+		c.CertManager.IngressClusterIssuer = utils.Pointer("")
+	}
+	if c.CertManager.IngressDNSNames == nil {
+		c.CertManager.IngressDNSNames = utils.Pointer([]string{})
+	}
 }
@@ -62,8 +62,26 @@ func TestSetDefaults(t *testing.T) {
 			DefaultTLSSecret:    utils.Pointer(""),
 			EnableProxyProtocol: utils.Pointer(false),
 		},
+		CertManager: types.CertManager{
+			Enabled:              utils.Pointer(false),
+			IngressClusterIssuer: utils.Pointer(""),
+			IngressDNSNames:      utils.Pointer([]string{}),
+		},
 	}
 
 	clusterConfig.SetDefaults()
 	g.Expect(clusterConfig).To(Equal(expectedConfig))
 }
+
+func TestSetDefaultsEtcd(t *testing.T) {
+	g := NewWithT(t)
+	clusterConfig := types.ClusterConfig{
+		Datastore: types.Datastore{
+			Type: utils.Pointer("etcd"),
+		},
+	}
+
+	clusterConfig.SetDefaults()
+	g.Expect(clusterConfig.Datastore.GetEtcdPort()).To(Equal(2379))
+	g.Expect(clusterConfig.Datastore.GetEtcdPeerPort()).To(Equal(2380))
+}
@@ -0,0 +1,95 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// ClusterConfigChange is a single difference between two cluster configurations.
+type ClusterConfigChange struct {
+	// Key is the dot-separated path of the option, e.g. "dns.cluster-domain".
+	Key string
+	// Old is the JSON encoded value of the option in the old configuration. Old is empty if the option was not set.
+	Old string
+	// New is the JSON encoded value of the option in the new configuration. New is empty if the option is not set.
+	New string
+}
+
+// DiffClusterConfig returns the user-facing options that differ between two cluster configurations, sorted by key.
+// Certificates, keys and other internal options are not compared.
+func DiffClusterConfig(old ClusterConfig, new ClusterConfig) ([]ClusterConfigChange, error) {
+	oldValues, err := flattenUserFacingClusterConfig(old)
+	if err != nil {
+		return nil, fmt.Errorf("failed to flatten old config: %w", err)
+	}
+	newValues, err := flattenUserFacingClusterConfig(new)
+	if err != nil {
+		return nil, fmt.Errorf("failed to flatten new config: %w", err)
+	}
+
+	var changes []ClusterConfigChange
+	for key, oldValue := range oldValues {
+		if newValue := newValues[key]; newValue != oldValue {
+			changes = append(changes, ClusterConfigChange{Key: key, Old: oldValue, New: newValue})
+		}
+	}
+	for key, newValue := range newValues {
+		if _, ok := oldValues[key]; !ok {
+			changes = append(changes, ClusterConfigChange{Key: key, New: newValue})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+	return changes, nil
+}
+
+func flattenUserFacingClusterConfig(c ClusterConfig) (map[string]string, error) {
+	type datastore struct {
+		Type    *string   `json:"type,omitempty"`
+		Servers *[]string `json:"servers,omitempty"`
+	}
+	b, err := json.Marshal(struct {
+		Config    any       `json:"config"`
+		Datastore datastore `json:"datastore"`
+	}{
+		Config:    c.ToUserFacing(),
+		Datastore: datastore{Type: c.Datastore.Type, Servers: c.Datastore.ExternalServers},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+	var m map[string]any
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("failed to decode config: %w", err)
+	}
+
+	result := make(map[string]string)
+	if config, ok := m["config"].(map[string]any); ok {
+		if err := flattenInto(result, "", config); err != nil {
+			return nil, err
+		}
+	}
+	if datastore, ok := m["datastore"].(map[string]any); ok {
+		if err := flattenInto(result, "datastore.", datastore); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+func flattenInto(result map[string]string, prefix string, m map[string]any) error {
+	for key, value := range m {
+		if nested, ok := value.(map[string]any); ok {
+			if err := flattenInto(result, prefix+key+".", nested); err != nil {
+				return err
+			}
+			continue
+		}
+		b, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("failed to encode %s%s: %w", prefix, key, err)
+		}
+		result[prefix+key] = string(b)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/k8s/pkg/utils"
+	. "github.com/onsi/gomega"
+)
+
+func TestDiffClusterConfig(t *testing.T) {
+	old := types.ClusterConfig{
+		DNS:     types.DNS{Enabled: utils.Pointer(true)},
+		Kubelet: types.Kubelet{ClusterDomain: utils.Pointer("cluster.local")},
+		Ingress: types.Ingress{Enabled: utils.Pointer(false)},
+		Certificates: types.Certificates{
+			CACert: utils.Pointer("CA CERT DATA"),
+		},
+		Datastore: types.Datastore{Type: utils.Pointer("k8s-dqlite")},
+	}
+	new := types.ClusterConfig{
+		DNS:     types.DNS{Enabled: utils.Pointer(true)},
+		Kubelet: types.Kubelet{ClusterDomain: utils.Pointer("cluster.example")},
+		Certificates: types.Certificates{
+			CACert: utils.Pointer("CA CERT NEW DATA"),
+		},
+		Datastore:   types.Datastore{Type: utils.Pointer("k8s-dqlite")},
+		Annotations: types.Annotations{"k8sd/v1alpha/test": "value"},
+	}
+
+	t.Run("Changes", func(t *testing.T) {
+		g := NewWithT(t)
+
+		changes, err := types.DiffClusterConfig(old, new)
+		g.Expect(err).To(Not(HaveOccurred()))
+		g.Expect(changes).To(Equal([]types.ClusterConfigChange{
+			{Key: "annotations.k8sd/v1alpha/test", New: `"value"`},
+			{Key: "dns.cluster-domain", Old: `"cluster.local"`, New: `"cluster.example"`},
+			{Key: "ingress.enabled", Old: "false"},
+		}))
+	})
+
+	t.Run("NoChanges", func(t *testing.T) {
+		g := NewWithT(t)
+
+		changes, err := types.DiffClusterConfig(old, old)
+		g.Expect(err).To(Not(HaveOccurred()))
+		g.Expect(changes).To(BeEmpty())
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"strconv"
 )
 
 type Kubelet struct {
@@ -15,12 +16,17 @@ type Kubelet struct {
 	ClusterDNS         *string   `json:"cluster-dns,omitempty"`
 	ClusterDomain      *string   `json:"cluster-domain,omitempty"`
 	ControlPlaneTaints *[]string `json:"control-plane-taints,omitempty"`
+
+	// KubeProxyEnabled is not configured by users. It is derived from the network configuration and distributed to
+	// the nodes with the kubelet configuration, so that k8sd can stop kube-proxy if it is replaced by the CNI.
+	KubeProxyEnabled *bool `json:"kube-proxy-enabled,omitempty"`
 }
 
 func (c Kubelet) GetCloudProvider() string        { return getField(c.CloudProvider) }
 func (c Kubelet) GetClusterDNS() string           { return getField(c.ClusterDNS) }
 func (c Kubelet) GetClusterDomain() string        { return getField(c.ClusterDomain) }
 func (c Kubelet) GetControlPlaneTaints() []string { return getField(c.ControlPlaneTaints) }
+func (c Kubelet) GetKubeProxyEnabled() bool       { return getField(c.KubeProxyEnabled) }
 func (c Kubelet) Empty() bool                     { return c == Kubelet{} }
 
 // hash returns a sha256 sum from the Kubelet configuration.
@@ -54,6 +60,9 @@ func (c Kubelet) ToConfigMap(key *rsa.PrivateKey) (map[string]string, error) {
 	if v := c.ClusterDomain; v != nil {
 		data["cluster-domain"] = *v
 	}
+	if v := c.KubeProxyEnabled; v != nil {
+		data["kube-proxy-enabled"] = strconv.FormatBool(*v)
+	}
 
 	if key != nil {
 		hash, err := c.hash()
@@ -88,6 +97,13 @@ func KubeletFromConfigMap(m map[string]string, key *rsa.PublicKey) (Kubelet, err
 	if v, ok := m["cluster-domain"]; ok {
 		c.ClusterDomain = &v
 	}
+	if v, ok := m["kube-proxy-enabled"]; ok {
+		enabled, err := strconv.ParseBool(v)
+		if err != nil {
+			return Kubelet{}, fmt.Errorf("invalid kube-proxy-enabled value %q: %w", v, err)
+		}
+		c.KubeProxyEnabled = &enabled
+	}
 
 	if key != nil {
 		hash, err := c.hash()
@@ -66,6 +66,15 @@ func TestKubelet(t *testing.T) {
 				CloudProvider: utils.Pointer("external"),
 			},
 		},
+		{
+			name: "KubeProxyDisabled",
+			configmap: map[string]string{
+				"kube-proxy-enabled": "false",
+			},
+			kubelet: types.Kubelet{
+				KubeProxyEnabled: utils.Pointer(false),
+			},
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Run("ToConfigMap", func(t *testing.T) {
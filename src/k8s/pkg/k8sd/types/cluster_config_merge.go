@@ -36,24 +36,43 @@ func MergeClusterConfig(existing ClusterConfig, new ClusterConfig) (ClusterConfi
 		{name: "admin client key", val: &config.Certificates.AdminClientKey, old: existing.Certificates.AdminClientKey, new: new.Certificates.AdminClientKey, allowChange: true},
 		{name: "k8sd public key", val: &config.Certificates.K8sdPublicKey, old: existing.Certificates.K8sdPublicKey, new: new.Certificates.K8sdPublicKey},
 		{name: "k8sd private key", val: &config.Certificates.K8sdPrivateKey, old: existing.Certificates.K8sdPrivateKey, new: new.Certificates.K8sdPrivateKey},
+		{name: "key algorithm", val: &config.Certificates.KeyAlgorithm, old: existing.Certificates.KeyAlgorithm, new: new.Certificates.KeyAlgorithm},
 		// datastore
-		{name: "datastore type", val: &config.Datastore.Type, old: existing.Datastore.Type, new: new.Datastore.Type},
+		// the datastore type changes from k8s-dqlite to external once the datastore contents are migrated
+		{name: "datastore type", val: &config.Datastore.Type, old: existing.Datastore.Type, new: new.Datastore.Type, allowChange: existing.Datastore.GetType() == "k8s-dqlite" && new.Datastore.GetType() == "external"},
 		{name: "k8s-dqlite certificate", val: &config.Datastore.K8sDqliteCert, old: existing.Datastore.K8sDqliteCert, new: new.Datastore.K8sDqliteCert},
 		{name: "k8s-dqlite key", val: &config.Datastore.K8sDqliteKey, old: existing.Datastore.K8sDqliteKey, new: new.Datastore.K8sDqliteKey},
+		{name: "etcd CA certificate", val: &config.Datastore.EtcdCACert, old: existing.Datastore.EtcdCACert, new: new.Datastore.EtcdCACert},
+		{name: "etcd CA key", val: &config.Datastore.EtcdCAKey, old: existing.Datastore.EtcdCAKey, new: new.Datastore.EtcdCAKey},
 		{name: "external datastore CA certificate", val: &config.Datastore.ExternalCACert, old: existing.Datastore.ExternalCACert, new: new.Datastore.ExternalCACert, allowChange: true},
 		{name: "external datastore client certificate", val: &config.Datastore.ExternalClientCert, old: existing.Datastore.ExternalClientCert, new: new.Datastore.ExternalClientCert, allowChange: true},
 		{name: "external datastore client key", val: &config.Datastore.ExternalClientKey, old: existing.Datastore.ExternalClientKey, new: new.Datastore.ExternalClientKey, allowChange: true},
 		// network
-		{name: "pod CIDR", val: &config.Network.PodCIDR, old: existing.Network.PodCIDR, new: new.Network.PodCIDR},
+		// the pod CIDR changes to the migration pod CIDR once the nodes are switched to another network provider
+		{name: "pod CIDR", val: &config.Network.PodCIDR, old: existing.Network.PodCIDR, new: new.Network.PodCIDR, allowChange: existing.Network.GetMigrationPodCIDR() != "" && new.Network.GetPodCIDR() == existing.Network.GetMigrationPodCIDR()},
 		{name: "service CIDR", val: &config.Network.ServiceCIDR, old: existing.Network.ServiceCIDR, new: new.Network.ServiceCIDR},
+		{name: "network provider", val: &config.Network.Provider, old: existing.Network.Provider, new: new.Network.Provider, allowChange: true},
+		{name: "network migration pod CIDR", val: &config.Network.MigrationPodCIDR, old: existing.Network.MigrationPodCIDR, new: new.Network.MigrationPodCIDR, allowChange: true},
+		{name: "cilium encryption", val: &config.Network.Cilium.Encryption, old: existing.Network.Cilium.Encryption, new: new.Network.Cilium.Encryption, allowChange: true},
 		// apiserver
 		{name: "kube-apiserver authorization mode", val: &config.APIServer.AuthorizationMode, old: existing.APIServer.AuthorizationMode, new: new.APIServer.AuthorizationMode, allowChange: true},
+		// authentication
+		{name: "OIDC issuer URL", val: &config.Authentication.OIDC.IssuerURL, old: existing.Authentication.OIDC.IssuerURL, new: new.Authentication.OIDC.IssuerURL, allowChange: true},
+		{name: "OIDC client ID", val: &config.Authentication.OIDC.ClientID, old: existing.Authentication.OIDC.ClientID, new: new.Authentication.OIDC.ClientID, allowChange: true},
+		{name: "OIDC username claim", val: &config.Authentication.OIDC.UsernameClaim, old: existing.Authentication.OIDC.UsernameClaim, new: new.Authentication.OIDC.UsernameClaim, allowChange: true},
+		{name: "OIDC username prefix", val: &config.Authentication.OIDC.UsernamePrefix, old: existing.Authentication.OIDC.UsernamePrefix, new: new.Authentication.OIDC.UsernamePrefix, allowChange: true},
+		{name: "OIDC groups claim", val: &config.Authentication.OIDC.GroupsClaim, old: existing.Authentication.OIDC.GroupsClaim, new: new.Authentication.OIDC.GroupsClaim, allowChange: true},
+		{name: "OIDC groups prefix", val: &config.Authentication.OIDC.GroupsPrefix, old: existing.Authentication.OIDC.GroupsPrefix, new: new.Authentication.OIDC.GroupsPrefix, allowChange: true},
+		{name: "OIDC CA certificate", val: &config.Authentication.OIDC.CACert, old: existing.Authentication.OIDC.CACert, new: new.Authentication.OIDC.CACert, allowChange: true},
+		{name: "authentication config", val: &config.Authentication.Config, old: existing.Authentication.Config, new: new.Authentication.Config, allowChange: true},
 		// kubelet
 		{name: "kubelet cluster DNS", val: &config.Kubelet.ClusterDNS, old: existing.Kubelet.ClusterDNS, new: new.Kubelet.ClusterDNS, allowChange: !boolFieldRemainedEnabled(existing.DNS.Enabled, new.DNS.Enabled)},
 		{name: "kubelet cluster domain", val: &config.Kubelet.ClusterDomain, old: existing.Kubelet.ClusterDomain, new: new.Kubelet.ClusterDomain, allowChange: true},
 		{name: "kubelet cloud provider", val: &config.Kubelet.CloudProvider, old: existing.Kubelet.CloudProvider, new: new.Kubelet.CloudProvider, allowChange: true},
 		// ingress
 		{name: "ingress default TLS secret", val: &config.Ingress.DefaultTLSSecret, old: existing.Ingress.DefaultTLSSecret, new: new.Ingress.DefaultTLSSecret, allowChange: true},
+		// cert-manager
+		{name: "cert-manager ingress cluster issuer", val: &config.CertManager.IngressClusterIssuer, old: existing.CertManager.IngressClusterIssuer, new: new.CertManager.IngressClusterIssuer, allowChange: true},
 		// load balancer
 		{name: "load balancer BGP peer address", val: &config.LoadBalancer.BGPPeerAddress, old: existing.LoadBalancer.BGPPeerAddress, new: new.LoadBalancer.BGPPeerAddress, allowChange: true},
 		// local storage
@@ -77,6 +96,7 @@ func MergeClusterConfig(existing ClusterConfig, new ClusterConfig) (ClusterConfi
 		{name: "external datastore servers", val: &config.Datastore.ExternalServers, old: existing.Datastore.ExternalServers, new: new.Datastore.ExternalServers, allowChange: true},
 		{name: "load balancer CIDRs", val: &config.LoadBalancer.CIDRs, old: existing.LoadBalancer.CIDRs, new: new.LoadBalancer.CIDRs, allowChange: true},
 		{name: "load balancer L2 interfaces", val: &config.LoadBalancer.L2Interfaces, old: existing.LoadBalancer.L2Interfaces, new: new.LoadBalancer.L2Interfaces, allowChange: true},
+		{name: "cert-manager ingress DNS names", val: &config.CertManager.IngressDNSNames, old: existing.CertManager.IngressDNSNames, new: new.CertManager.IngressDNSNames, allowChange: true},
 		{name: "control-plane register with taints", val: &config.Kubelet.ControlPlaneTaints, old: existing.Kubelet.ControlPlaneTaints, new: new.Kubelet.ControlPlaneTaints, allowChange: false},
 	} {
 		if *i.val, err = mergeSliceField(i.old, i.new, i.allowChange); err != nil {
@@ -101,6 +121,8 @@ func MergeClusterConfig(existing ClusterConfig, new ClusterConfig) (ClusterConfi
 		{name: "kube-apiserver secure port", val: &config.APIServer.SecurePort, old: existing.APIServer.SecurePort, new: new.APIServer.SecurePort},
 		// datastore
 		{name: "k8s-dqlite port", val: &config.Datastore.K8sDqlitePort, old: existing.Datastore.K8sDqlitePort, new: new.Datastore.K8sDqlitePort},
+		{name: "etcd port", val: &config.Datastore.EtcdPort, old: existing.Datastore.EtcdPort, new: new.Datastore.EtcdPort},
+		{name: "etcd peer port", val: &config.Datastore.EtcdPeerPort, old: existing.Datastore.EtcdPeerPort, new: new.Datastore.EtcdPeerPort},
 		// load-balancer
 		{name: "load balancer BGP local ASN", val: &config.LoadBalancer.BGPLocalASN, old: existing.LoadBalancer.BGPLocalASN, new: new.LoadBalancer.BGPLocalASN, allowChange: true},
 		{name: "load balancer BGP peer ASN", val: &config.LoadBalancer.BGPPeerASN, old: existing.LoadBalancer.BGPPeerASN, new: new.LoadBalancer.BGPPeerASN, allowChange: true},
@@ -121,6 +143,10 @@ func MergeClusterConfig(existing ClusterConfig, new ClusterConfig) (ClusterConfi
 	}{
 		// network
 		{name: "network enabled", val: &config.Network.Enabled, old: existing.Network.Enabled, new: new.Network.Enabled, allowChange: true},
+		{name: "cilium hubble relay", val: &config.Network.Cilium.HubbleRelay, old: existing.Network.Cilium.HubbleRelay, new: new.Network.Cilium.HubbleRelay, allowChange: true},
+		{name: "cilium hubble UI", val: &config.Network.Cilium.HubbleUI, old: existing.Network.Cilium.HubbleUI, new: new.Network.Cilium.HubbleUI, allowChange: true},
+		{name: "cilium kube-proxy replacement", val: &config.Network.Cilium.KubeProxyReplacement, old: existing.Network.Cilium.KubeProxyReplacement, new: new.Network.Cilium.KubeProxyReplacement, allowChange: true},
+		{name: "cilium bandwidth manager", val: &config.Network.Cilium.BandwidthManager, old: existing.Network.Cilium.BandwidthManager, new: new.Network.Cilium.BandwidthManager, allowChange: true},
 		// DNS
 		{name: "DNS enabled", val: &config.DNS.Enabled, old: existing.DNS.Enabled, new: new.DNS.Enabled, allowChange: true},
 		// gateway
@@ -137,15 +163,24 @@ func MergeClusterConfig(existing ClusterConfig, new ClusterConfig) (ClusterConfi
 		{name: "local storage default", val: &config.LocalStorage.Default, old: existing.LocalStorage.Default, new: new.LocalStorage.Default, allowChange: true},
 		// metrics-server
 		{name: "metrics server enabled", val: &config.MetricsServer.Enabled, old: existing.MetricsServer.Enabled, new: new.MetricsServer.Enabled, allowChange: true},
+		// cert-manager
+		{name: "cert-manager enabled", val: &config.CertManager.Enabled, old: existing.CertManager.Enabled, new: new.CertManager.Enabled, allowChange: true},
 	} {
 		if *i.val, err = mergeField(i.old, i.new, i.allowChange); err != nil {
 			return ClusterConfig{}, fmt.Errorf("prevented update of %s: %w", i.name, err)
 		}
 	}
 
+	// reset boolean options
+	resetCiliumOptions(&config.Network.Cilium, new.Annotations)
+	resetCertManagerOptions(&config.CertManager, new.Annotations)
+
 	// merge annotations
 	config.Annotations = mergeAnnotationsField(existing.Annotations, new.Annotations)
 
+	// merge values overrides
+	config.ValuesOverrides = mergeValuesOverridesField(existing.ValuesOverrides, new.ValuesOverrides)
+
 	if err := config.Validate(); err != nil {
 		return ClusterConfig{}, fmt.Errorf("updated cluster configuration is not valid: %w", err)
 	}
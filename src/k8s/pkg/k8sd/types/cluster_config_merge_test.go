@@ -86,10 +86,15 @@ func TestMergeClusterConfig(t *testing.T) {
 		generateMergeClusterConfigTestCases("Certificates/AdminClientKey", true, "v1", "v2", func(c *types.ClusterConfig, v any) { c.Certificates.AdminClientKey = utils.Pointer(v.(string)) }),
 		generateMergeClusterConfigTestCases("Certificates/K8sdPublicKey", false, "v1", "v2", func(c *types.ClusterConfig, v any) { c.Certificates.K8sdPublicKey = utils.Pointer(v.(string)) }),
 		generateMergeClusterConfigTestCases("Certificates/K8sdPrivateKey", false, "v1", "v2", func(c *types.ClusterConfig, v any) { c.Certificates.K8sdPrivateKey = utils.Pointer(v.(string)) }),
+		generateMergeClusterConfigTestCases("Certificates/KeyAlgorithm", false, "rsa-2048", "ecdsa-p256", func(c *types.ClusterConfig, v any) { c.Certificates.KeyAlgorithm = utils.Pointer(v.(string)) }),
 		generateMergeClusterConfigTestCases("Datastore/Type", false, "v1", "v2", func(c *types.ClusterConfig, v any) { c.Datastore.Type = utils.Pointer(v.(string)) }),
 		generateMergeClusterConfigTestCases("Datastore/K8sDqliteCert", false, "v1", "v2", func(c *types.ClusterConfig, v any) { c.Datastore.K8sDqliteCert = utils.Pointer(v.(string)) }),
 		generateMergeClusterConfigTestCases("Datastore/K8sDqliteKey", false, "v1", "v2", func(c *types.ClusterConfig, v any) { c.Datastore.K8sDqliteKey = utils.Pointer(v.(string)) }),
 		generateMergeClusterConfigTestCases("Datastore/K8sDqlitePort", false, 6443, 16443, func(c *types.ClusterConfig, v any) { c.Datastore.K8sDqlitePort = utils.Pointer(v.(int)) }),
+		generateMergeClusterConfigTestCases("Datastore/EtcdPort", false, 2379, 12379, func(c *types.ClusterConfig, v any) { c.Datastore.EtcdPort = utils.Pointer(v.(int)) }),
+		generateMergeClusterConfigTestCases("Datastore/EtcdPeerPort", false, 2380, 12380, func(c *types.ClusterConfig, v any) { c.Datastore.EtcdPeerPort = utils.Pointer(v.(int)) }),
+		generateMergeClusterConfigTestCases("Datastore/EtcdCACert", false, "v1", "v2", func(c *types.ClusterConfig, v any) { c.Datastore.EtcdCACert = utils.Pointer(v.(string)) }),
+		generateMergeClusterConfigTestCases("Datastore/EtcdCAKey", false, "v1", "v2", func(c *types.ClusterConfig, v any) { c.Datastore.EtcdCAKey = utils.Pointer(v.(string)) }),
 		generateMergeClusterConfigTestCases("Datastore/ExternalServers", true, []string{"localhost:123"}, []string{"localhost:123"}, func(c *types.ClusterConfig, v any) { c.Datastore.ExternalServers = utils.Pointer(v.([]string)) }),
 		generateMergeClusterConfigTestCases("Datastore/ExternalCACert", true, "v1", "v2", func(c *types.ClusterConfig, v any) { c.Datastore.ExternalCACert = utils.Pointer(v.(string)) }),
 		generateMergeClusterConfigTestCases("Datastore/ExternalClientCert", true, "v1", "v2", func(c *types.ClusterConfig, v any) { c.Datastore.ExternalClientCert = utils.Pointer(v.(string)) }),
@@ -98,6 +103,7 @@ func TestMergeClusterConfig(t *testing.T) {
 		generateMergeClusterConfigTestCases("Network/Disable", true, false, true, func(c *types.ClusterConfig, v any) { c.Network.Enabled = utils.Pointer(v.(bool)) }),
 		generateMergeClusterConfigTestCases("Network/PodCIDR", false, "10.1.0.0/16", "10.2.0.0/16", func(c *types.ClusterConfig, v any) { c.Network.PodCIDR = utils.Pointer(v.(string)) }),
 		generateMergeClusterConfigTestCases("Network/ServiceCIDR", false, "10.152.183.0/24", "10.152.184.0/24", func(c *types.ClusterConfig, v any) { c.Network.ServiceCIDR = utils.Pointer(v.(string)) }),
+		generateMergeClusterConfigTestCases("Network/Provider", true, "cilium", "calico", func(c *types.ClusterConfig, v any) { c.Network.Provider = utils.Pointer(v.(string)) }),
 		generateMergeClusterConfigTestCases("APIServer/SecurePort", false, 6443, 16443, func(c *types.ClusterConfig, v any) { c.APIServer.SecurePort = utils.Pointer(v.(int)) }),
 		generateMergeClusterConfigTestCases("APIServer/AuthorizationMode", true, "v1", "v2", func(c *types.ClusterConfig, v any) { c.APIServer.AuthorizationMode = utils.Pointer(v.(string)) }),
 		generateMergeClusterConfigTestCases("Kubelet/CloudProvider", true, "v1", "v2", func(c *types.ClusterConfig, v any) { c.Kubelet.CloudProvider = utils.Pointer(v.(string)) }),
@@ -289,6 +295,36 @@ func TestMergeClusterConfig_Scenarios(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Datastore/MigrateToExternal",
+			new: types.ClusterConfig{
+				Datastore: types.Datastore{
+					Type:            utils.Pointer("external"),
+					ExternalServers: utils.Pointer([]string{"https://10.0.0.1:2379"}),
+				},
+			},
+			expectMerged: types.ClusterConfig{
+				Datastore: types.Datastore{
+					Type:            utils.Pointer("external"),
+					ExternalServers: utils.Pointer([]string{"https://10.0.0.1:2379"}),
+				},
+			},
+		},
+		{
+			name: "Datastore/NoMigrateFromExternal",
+			old: types.ClusterConfig{
+				Datastore: types.Datastore{
+					Type:            utils.Pointer("external"),
+					ExternalServers: utils.Pointer([]string{"https://10.0.0.1:2379"}),
+				},
+			},
+			new: types.ClusterConfig{
+				Datastore: types.Datastore{
+					Type: utils.Pointer("k8s-dqlite"),
+				},
+			},
+			expectErr: true,
+		},
 		{
 			name: "LoadBalancer/DisableWithNetwork",
 			old: types.ClusterConfig{
@@ -1,12 +1,176 @@
 package types
 
+import (
+	"fmt"
+
+	"github.com/canonical/k8s/pkg/utils"
+)
+
+const (
+	// AnnotationNetworkProvider is the network implementation (CNI) of the cluster, "cilium" or "calico".
+	// The default network provider depends on the build, see NetworkImplementation.
+	// Changing the network provider of a running cluster migrates the nodes one by one.
+	AnnotationNetworkProvider = "k8sd/v1alpha/network/provider"
+
+	// AnnotationNetworkMigrationPodCIDR is the pod CIDR of the network provider that a running cluster is switched to.
+	// Both network providers run on all nodes while the nodes are switched, so their pod CIDRs must not overlap.
+	// The migration pod CIDR becomes the pod CIDR of the cluster once all nodes are switched.
+	AnnotationNetworkMigrationPodCIDR = "k8sd/v1alpha/network/migration-pod-cidr"
+
+	// NetworkProviderCilium is the Cilium network provider.
+	NetworkProviderCilium = "cilium"
+	// NetworkProviderCalico is the Calico network provider.
+	NetworkProviderCalico = "calico"
+
+	// NodeLabelNetworkProvider is the label of the nodes that are switched to a network provider.
+	// NodeLabelNetworkProvider is set by the network feature, which uses it to select the CNI configuration of the
+	// nodes while migrating.
+	NodeLabelNetworkProvider = "k8sd.io/network-provider"
+	// NodeAnnotationNetworkSwitch requests a node to remove the CNI configuration of the named network provider, so
+	// that new pods on the node use the network provider that the node was switched to.
+	// NodeAnnotationNetworkSwitch is removed by the node once the CNI configuration is removed.
+	NodeAnnotationNetworkSwitch = "k8sd.io/network-switch"
+	// NodeAnnotationNetworkMigration records the step of a node that is being switched to another network provider.
+	NodeAnnotationNetworkMigration = "k8sd.io/network-migration"
+	// NodeAnnotationNetworkCleanup requests a node to clean up the local state of the named network provider.
+	// NodeAnnotationNetworkCleanup is removed by the node once the cleanup is complete.
+	NodeAnnotationNetworkCleanup = "k8sd.io/network-cleanup"
+)
+
+// NetworkImplementation describes the network providers of the features implementation of k8sd.
+type NetworkImplementation struct {
+	// DefaultProvider is the network provider of clusters that do not set one.
+	DefaultProvider string
+	// IngressProvider is the network provider that implements ingress and gateway.
+	// IngressProvider is empty if ingress and gateway do not depend on the network provider.
+	IngressProvider string
+}
+
+// DefaultNetworkImplementation is the network implementation of the build.
+// DefaultNetworkImplementation is set by the features implementation, and defaults to Cilium.
+var DefaultNetworkImplementation = NetworkImplementation{
+	DefaultProvider: NetworkProviderCilium,
+	IngressProvider: NetworkProviderCilium,
+}
+
 type Network struct {
 	Enabled     *bool   `json:"enabled,omitempty"`
 	PodCIDR     *string `json:"pod-cidr,omitempty"`
 	ServiceCIDR *string `json:"service-cidr,omitempty"`
+	Provider    *string `json:"provider,omitempty"`
+
+	MigrationPodCIDR *string `json:"migration-pod-cidr,omitempty"`
+
+	Cilium Cilium `json:"cilium,omitempty"`
 }
 
 func (c Network) GetEnabled() bool       { return getField(c.Enabled) }
 func (c Network) GetPodCIDR() string     { return getField(c.PodCIDR) }
 func (c Network) GetServiceCIDR() string { return getField(c.ServiceCIDR) }
-func (c Network) Empty() bool            { return c == Network{} }
+func (c Network) GetProvider() string {
+	// clusters that were bootstrapped before the network provider could be configured use the default of the build
+	if v := getField(c.Provider); v != "" {
+		return v
+	}
+	return DefaultNetworkImplementation.DefaultProvider
+}
+func (c Network) GetMigrationPodCIDR() string { return getField(c.MigrationPodCIDR) }
+func (c Network) Empty() bool                 { return c == Network{} }
+
+// networkProviderFromAnnotations extracts the network provider and the migration pod CIDR from the annotations.
+// networkProviderFromAnnotations returns the remaining annotations. The input annotations are not modified.
+// The migration pod CIDR is unset with the value "-".
+func networkProviderFromAnnotations(annotations Annotations) (*string, *string, Annotations, error) {
+	var provider, migrationPodCIDR *string
+	if value, ok := annotations.Get(AnnotationNetworkProvider); ok {
+		if err := validateNetworkProvider(value); err != nil {
+			return nil, nil, nil, fmt.Errorf("invalid value %q for annotation %q: %w", value, AnnotationNetworkProvider, err)
+		}
+		provider = utils.Pointer(value)
+	}
+	if value, ok := annotations.Get(AnnotationNetworkMigrationPodCIDR); ok {
+		if value == "-" {
+			value = ""
+		} else if err := validateCIDRs(value); err != nil {
+			return nil, nil, nil, fmt.Errorf("invalid value %q for annotation %q: %w", value, AnnotationNetworkMigrationPodCIDR, err)
+		}
+		migrationPodCIDR = utils.Pointer(value)
+	}
+	if provider == nil && migrationPodCIDR == nil {
+		return nil, nil, annotations, nil
+	}
+
+	remaining := make(Annotations, len(annotations))
+	for key, value := range annotations {
+		if key != AnnotationNetworkProvider && key != AnnotationNetworkMigrationPodCIDR {
+			remaining[key] = value
+		}
+	}
+	return provider, migrationPodCIDR, remaining, nil
+}
+
+// networkProviderToAnnotations adds the network provider and the migration pod CIDR to a copy of the annotations.
+func networkProviderToAnnotations(network Network, annotations map[string]string) map[string]string {
+	if network.Provider == nil && network.GetMigrationPodCIDR() == "" {
+		return annotations
+	}
+
+	result := make(map[string]string, len(annotations)+2)
+	for key, value := range annotations {
+		result[key] = value
+	}
+	if network.Provider != nil {
+		result[AnnotationNetworkProvider] = *network.Provider
+	}
+	if v := network.GetMigrationPodCIDR(); v != "" {
+		result[AnnotationNetworkMigrationPodCIDR] = v
+	}
+	return result
+}
+
+// validateNetworkProvider checks that the network provider is supported.
+func validateNetworkProvider(provider string) error {
+	switch provider {
+	case NetworkProviderCilium, NetworkProviderCalico:
+		return nil
+	default:
+		return fmt.Errorf("unsupported network provider %q, must be one of %q or %q", provider, NetworkProviderCilium, NetworkProviderCalico)
+	}
+}
+
+// validateMigrationPodCIDR checks that the migration pod CIDR uses the IP families of the pod CIDR, and that it does
+// not overlap the pod CIDR or the service CIDR.
+func validateMigrationPodCIDR(migrationPodCIDR string, podCIDR string, serviceCIDR string) error {
+	if err := validateCIDRs(migrationPodCIDR); err != nil {
+		return err
+	}
+	migrationIPv4CIDR, migrationIPv6CIDR, err := utils.SplitCIDRStrings(migrationPodCIDR)
+	if err != nil {
+		return err
+	}
+	podIPv4CIDR, podIPv6CIDR, err := utils.SplitCIDRStrings(podCIDR)
+	if err != nil {
+		return fmt.Errorf("failed to parse pod CIDR: %w", err)
+	}
+	if (migrationIPv4CIDR == "") != (podIPv4CIDR == "") || (migrationIPv6CIDR == "") != (podIPv6CIDR == "") {
+		return fmt.Errorf("must use the IP families of the pod CIDR %q", podCIDR)
+	}
+
+	for _, cidr := range []string{podCIDR, serviceCIDR} {
+		ipv4CIDR, ipv6CIDR, err := utils.SplitCIDRStrings(cidr)
+		if err != nil {
+			return fmt.Errorf("failed to parse CIDR %q: %w", cidr, err)
+		}
+		for _, pair := range [][2]string{{migrationIPv4CIDR, ipv4CIDR}, {migrationIPv6CIDR, ipv6CIDR}} {
+			if pair[0] == "" || pair[1] == "" {
+				continue
+			}
+			if overlap, err := utils.CIDRsOverlap(pair[0], pair[1]); err != nil {
+				return fmt.Errorf("failed to check for overlap: %w", err)
+			} else if overlap {
+				return fmt.Errorf("overlaps %q", cidr)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+package types_test
+
+import (
+	"testing"
+
+	apiv1 "github.com/canonical/k8s-snap-api/api/v1"
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/k8s/pkg/utils"
+	. "github.com/onsi/gomega"
+)
+
+func TestNetworkProviderAnnotations(t *testing.T) {
+	t.Run("FromUserFacing", func(t *testing.T) {
+		g := NewWithT(t)
+
+		config, err := types.ClusterConfigFromUserFacing(apiv1.UserFacingClusterConfig{
+			Annotations: map[string]string{
+				types.AnnotationNetworkProvider: "calico",
+				"key":                           "value",
+			},
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(config.Network.Provider).To(Equal(utils.Pointer("calico")))
+		g.Expect(config.Network.GetProvider()).To(Equal(types.NetworkProviderCalico))
+		g.Expect(config.Annotations).To(Equal(types.Annotations{"key": "value"}))
+	})
+
+	t.Run("Default", func(t *testing.T) {
+		g := NewWithT(t)
+
+		config, err := types.ClusterConfigFromUserFacing(apiv1.UserFacingClusterConfig{})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(config.Network.Provider).To(BeNil())
+		g.Expect(config.Network.GetProvider()).To(Equal(types.NetworkProviderCilium))
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := types.ClusterConfigFromUserFacing(apiv1.UserFacingClusterConfig{
+			Annotations: map[string]string{
+				types.AnnotationNetworkProvider: "flannel",
+			},
+		})
+		g.Expect(err).To(HaveOccurred())
+	})
+
+	t.Run("ToUserFacing", func(t *testing.T) {
+		g := NewWithT(t)
+
+		config := types.ClusterConfig{
+			Network:     types.Network{Provider: utils.Pointer("calico")},
+			Annotations: types.Annotations{"key": "value"},
+		}
+		g.Expect(config.ToUserFacing().Annotations).To(Equal(map[string]string{
+			types.AnnotationNetworkProvider: "calico",
+			"key":                           "value",
+		}))
+		g.Expect(config.Annotations).To(HaveLen(1))
+	})
+}
+
+func TestValidateNetworkProvider(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		config    types.ClusterConfig
+		expectErr bool
+	}{
+		{name: "Default"},
+		{name: "Calico", config: types.ClusterConfig{Network: types.Network{Provider: utils.Pointer("calico")}}},
+		{name: "Unsupported", config: types.ClusterConfig{Network: types.Network{Provider: utils.Pointer("flannel")}}, expectErr: true},
+		{
+			name: "CalicoIngress",
+			config: types.ClusterConfig{
+				Network: types.Network{Enabled: utils.Pointer(true), Provider: utils.Pointer("calico")},
+				Ingress: types.Ingress{Enabled: utils.Pointer(true)},
+			},
+			expectErr: true,
+		},
+		{
+			name: "CalicoGateway",
+			config: types.ClusterConfig{
+				Network: types.Network{Enabled: utils.Pointer(true), Provider: utils.Pointer("calico")},
+				Gateway: types.Gateway{Enabled: utils.Pointer(true)},
+			},
+			expectErr: true,
+		},
+		{
+			name: "CalicoKubeProxyReplacement",
+			config: types.ClusterConfig{
+				Network: types.Network{Provider: utils.Pointer("calico"), Cilium: types.Cilium{KubeProxyReplacement: utils.Pointer(true)}},
+			},
+			expectErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			tc.config.SetDefaults()
+			err := tc.config.Validate()
+			if tc.expectErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestNetworkImplementation(t *testing.T) {
+	g := NewWithT(t)
+
+	// ingress and gateway are implemented independently of the network provider, e.g. by Contour
+	implementation := types.DefaultNetworkImplementation
+	types.DefaultNetworkImplementation = types.NetworkImplementation{DefaultProvider: types.NetworkProviderCalico}
+	defer func() { types.DefaultNetworkImplementation = implementation }()
+
+	g.Expect(types.Network{}.GetProvider()).To(Equal(types.NetworkProviderCalico))
+	g.Expect(types.Network{Provider: utils.Pointer("cilium")}.GetProvider()).To(Equal(types.NetworkProviderCilium))
+
+	config := types.ClusterConfig{
+		Network: types.Network{Enabled: utils.Pointer(true)},
+		Ingress: types.Ingress{Enabled: utils.Pointer(true)},
+		Gateway: types.Gateway{Enabled: utils.Pointer(true)},
+	}
+	config.SetDefaults()
+	g.Expect(config.Validate()).To(Succeed())
+
+	config.Network.Cilium.KubeProxyReplacement = utils.Pointer(true)
+	g.Expect(config.Validate()).To(MatchError(ContainSubstring("kube-proxy replacement requires")))
+}
+
+func TestNetworkMigrationPodCIDR(t *testing.T) {
+	t.Run("Annotations", func(t *testing.T) {
+		g := NewWithT(t)
+
+		config, err := types.ClusterConfigFromUserFacing(apiv1.UserFacingClusterConfig{
+			Annotations: map[string]string{types.AnnotationNetworkMigrationPodCIDR: "10.100.0.0/16"},
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(config.Network.MigrationPodCIDR).To(Equal(utils.Pointer("10.100.0.0/16")))
+		g.Expect(config.Annotations).To(BeEmpty())
+		g.Expect(config.ToUserFacing().Annotations).To(HaveKeyWithValue(types.AnnotationNetworkMigrationPodCIDR, "10.100.0.0/16"))
+
+		config, err = types.ClusterConfigFromUserFacing(apiv1.UserFacingClusterConfig{
+			Annotations: map[string]string{types.AnnotationNetworkMigrationPodCIDR: "-"},
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(config.Network.MigrationPodCIDR).To(Equal(utils.Pointer("")))
+		g.Expect(config.ToUserFacing().Annotations).ToNot(HaveKey(types.AnnotationNetworkMigrationPodCIDR))
+
+		_, err = types.ClusterConfigFromUserFacing(apiv1.UserFacingClusterConfig{
+			Annotations: map[string]string{types.AnnotationNetworkMigrationPodCIDR: "invalid"},
+		})
+		g.Expect(err).To(HaveOccurred())
+	})
+
+	t.Run("Validate", func(t *testing.T) {
+		for _, tc := range []struct {
+			name             string
+			podCIDR          string
+			migrationPodCIDR string
+			expectErr        bool
+		}{
+			{name: "Valid", podCIDR: "10.1.0.0/16", migrationPodCIDR: "10.100.0.0/16"},
+			{name: "DualStack", podCIDR: "10.1.0.0/16,fd01::/108", migrationPodCIDR: "10.100.0.0/16,fd02::/108"},
+			{name: "OverlapPodCIDR", podCIDR: "10.1.0.0/16", migrationPodCIDR: "10.1.128.0/17", expectErr: true},
+			{name: "OverlapServiceCIDR", podCIDR: "10.1.0.0/16", migrationPodCIDR: "10.152.0.0/16", expectErr: true},
+			{name: "MissingIPFamily", podCIDR: "10.1.0.0/16,fd01::/108", migrationPodCIDR: "10.100.0.0/16", expectErr: true},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				g := NewWithT(t)
+
+				config := types.ClusterConfig{
+					Network: types.Network{
+						PodCIDR:          utils.Pointer(tc.podCIDR),
+						ServiceCIDR:      utils.Pointer("10.152.183.0/24"),
+						MigrationPodCIDR: utils.Pointer(tc.migrationPodCIDR),
+					},
+				}
+				config.SetDefaults()
+				if tc.expectErr {
+					g.Expect(config.Validate()).ToNot(Succeed())
+				} else {
+					g.Expect(config.Validate()).To(Succeed())
+				}
+			})
+		}
+	})
+
+	t.Run("Merge", func(t *testing.T) {
+		g := NewWithT(t)
+
+		existing := types.ClusterConfig{Network: types.Network{PodCIDR: utils.Pointer("10.1.0.0/16"), ServiceCIDR: utils.Pointer("10.152.183.0/24")}}
+		existing.SetDefaults()
+
+		// the pod CIDR cannot change without a migration pod CIDR
+		_, err := types.MergeClusterConfig(existing, types.ClusterConfig{Network: types.Network{PodCIDR: utils.Pointer("10.100.0.0/16")}})
+		g.Expect(err).To(MatchError(ContainSubstring("prevented update of pod CIDR")))
+
+		migrating, err := types.MergeClusterConfig(existing, types.ClusterConfig{Network: types.Network{Provider: utils.Pointer("calico"), MigrationPodCIDR: utils.Pointer("10.100.0.0/16")}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = types.MergeClusterConfig(migrating, types.ClusterConfig{Network: types.Network{PodCIDR: utils.Pointer("10.200.0.0/16")}})
+		g.Expect(err).To(MatchError(ContainSubstring("prevented update of pod CIDR")))
+
+		// the pod CIDR changes to the migration pod CIDR once the migration is complete
+		completed, err := types.MergeClusterConfig(migrating, types.ClusterConfig{Network: types.Network{PodCIDR: utils.Pointer("10.100.0.0/16"), MigrationPodCIDR: utils.Pointer("")}})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(completed.Network.GetPodCIDR()).To(Equal("10.100.0.0/16"))
+		g.Expect(completed.Network.GetMigrationPodCIDR()).To(BeEmpty())
+	})
+}
@@ -0,0 +1,48 @@
+package types
+
+import (
+	"reflect"
+
+	apiv1 "github.com/canonical/k8s-snap-api/api/v1"
+)
+
+// PruneUserFacingClusterConfig returns a copy of config where all options that are not set are reset to their
+// default values. Options without a default value are left unset. Annotations that are set in existing but not in
+// config are marked for removal.
+// PruneUserFacingClusterConfig is used to apply a cluster configuration declaratively, where options that are
+// removed from the configuration file must also be removed from the cluster.
+func PruneUserFacingClusterConfig(config apiv1.UserFacingClusterConfig, existing Annotations) apiv1.UserFacingClusterConfig {
+	var defaults ClusterConfig
+	defaults.SetDefaults()
+
+	result := config
+	fillUnsetFields(reflect.ValueOf(&result).Elem(), reflect.ValueOf(defaults.ToUserFacing()))
+
+	annotations := make(map[string]string, len(config.Annotations)+len(existing))
+	for k := range existing {
+		annotations[k] = "-"
+	}
+	for k, v := range config.Annotations {
+		annotations[k] = v
+	}
+	if len(annotations) > 0 {
+		result.Annotations = annotations
+	}
+
+	return result
+}
+
+// fillUnsetFields recursively sets all nil pointer fields of dst to the respective field of src.
+func fillUnsetFields(dst reflect.Value, src reflect.Value) {
+	for i := 0; i < dst.NumField(); i++ {
+		field := dst.Field(i)
+		switch field.Kind() {
+		case reflect.Struct:
+			fillUnsetFields(field, src.Field(i))
+		case reflect.Pointer:
+			if field.IsNil() && !src.Field(i).IsNil() {
+				field.Set(src.Field(i))
+			}
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package types_test
+
+import (
+	"testing"
+
+	apiv1 "github.com/canonical/k8s-snap-api/api/v1"
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/k8s/pkg/utils"
+	. "github.com/onsi/gomega"
+)
+
+func TestPruneUserFacingClusterConfig(t *testing.T) {
+	g := NewWithT(t)
+
+	config := apiv1.UserFacingClusterConfig{
+		Network:     apiv1.NetworkConfig{Enabled: utils.Pointer(true)},
+		DNS:         apiv1.DNSConfig{Enabled: utils.Pointer(true), ServiceIP: utils.Pointer("10.152.183.10")},
+		Annotations: map[string]string{"k8sd/v1alpha/keep": "true"},
+	}
+	existing := types.Annotations{"k8sd/v1alpha/keep": "false", "k8sd/v1alpha/remove": "true"}
+
+	pruned := types.PruneUserFacingClusterConfig(config, existing)
+
+	// options in the config are kept
+	g.Expect(pruned.Network.GetEnabled()).To(BeTrue())
+	g.Expect(pruned.DNS.GetEnabled()).To(BeTrue())
+	g.Expect(pruned.DNS.GetServiceIP()).To(Equal("10.152.183.10"))
+
+	// options not in the config are reset to their defaults
+	g.Expect(pruned.Ingress.Enabled).To(Equal(utils.Pointer(false)))
+	g.Expect(pruned.DNS.ClusterDomain).To(Equal(utils.Pointer("cluster.local")))
+	g.Expect(pruned.LocalStorage.ReclaimPolicy).To(Equal(utils.Pointer("Delete")))
+	g.Expect(pruned.MetricsServer.Enabled).To(Equal(utils.Pointer(true)))
+
+	// options without a default are left unset
+	g.Expect(pruned.CloudProvider).To(BeNil())
+
+	g.Expect(pruned.Annotations).To(Equal(map[string]string{
+		"k8sd/v1alpha/keep":   "true",
+		"k8sd/v1alpha/remove": "-",
+	}))
+
+	// the input config is not modified
+	g.Expect(config.Ingress.Enabled).To(BeNil())
+}
@@ -0,0 +1,45 @@
+package types
+
+import (
+	"fmt"
+)
+
+// RevisionOptions returns the options of the cluster configuration that are recorded in the revision history.
+// These are the user-facing options and the datastore type and servers. Certificates, keys and other internal
+// options are not recorded, so that the revision history does not keep copies of the cluster secrets.
+func (c ClusterConfig) RevisionOptions() (ClusterConfig, error) {
+	config, err := ClusterConfigFromUserFacing(c.ToUserFacing())
+	if err != nil {
+		return ClusterConfig{}, fmt.Errorf("failed to convert user-facing cluster config: %w", err)
+	}
+	config.Datastore = Datastore{
+		Type:            c.Datastore.Type,
+		ExternalServers: c.Datastore.ExternalServers,
+	}
+	return config, nil
+}
+
+// RollbackClusterConfig returns the update that restores the options of a revision on top of the current cluster configuration.
+// Only the user-facing options that can be changed are restored. The datastore, the key algorithm and the options that
+// cannot change while their feature remains enabled keep their current values. The network provider also keeps its
+// current value, since it is changed through a migration that requires a new pod CIDR.
+// Options that are not set in the revision keep their current values.
+func RollbackClusterConfig(revision ClusterConfig, current ClusterConfig) (ClusterConfig, error) {
+	update, err := revision.RevisionOptions()
+	if err != nil {
+		return ClusterConfig{}, err
+	}
+
+	update.Datastore = Datastore{}
+	update.Certificates.KeyAlgorithm = nil
+	update.Network.Provider = nil
+	update.Network.MigrationPodCIDR = nil
+	if boolFieldRemainedEnabled(current.DNS.Enabled, update.DNS.Enabled) {
+		update.Kubelet.ClusterDNS = nil
+	}
+	if boolFieldRemainedEnabled(current.LocalStorage.Enabled, update.LocalStorage.Enabled) {
+		update.LocalStorage.LocalPath = nil
+		update.LocalStorage.ReclaimPolicy = nil
+	}
+	return update, nil
+}
@@ -0,0 +1,104 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/k8s/pkg/utils"
+	. "github.com/onsi/gomega"
+)
+
+func TestClusterConfigRevisionOptions(t *testing.T) {
+	g := NewWithT(t)
+
+	config := types.ClusterConfig{
+		Certificates: types.Certificates{
+			CACert:            utils.Pointer("CA CERT DATA"),
+			CAKey:             utils.Pointer("CA KEY DATA"),
+			ServiceAccountKey: utils.Pointer("SA KEY DATA"),
+			K8sdPrivateKey:    utils.Pointer("K8SD KEY DATA"),
+			KeyAlgorithm:      utils.Pointer("ecdsa-p256"),
+		},
+		Datastore: types.Datastore{
+			Type:              utils.Pointer("external"),
+			ExternalServers:   utils.Pointer([]string{"https://10.0.0.1:2379"}),
+			ExternalClientKey: utils.Pointer("CLIENT KEY DATA"),
+			EtcdCAKey:         utils.Pointer("ETCD CA KEY DATA"),
+		},
+		APIServer: types.APIServer{SecurePort: utils.Pointer(6443)},
+		DNS:       types.DNS{Enabled: utils.Pointer(true)},
+		Kubelet:   types.Kubelet{ClusterDomain: utils.Pointer("cluster.local")},
+	}
+
+	options, err := config.RevisionOptions()
+	g.Expect(err).To(Not(HaveOccurred()))
+	g.Expect(options.Certificates).To(Equal(types.Certificates{KeyAlgorithm: utils.Pointer("ecdsa-p256")}))
+	g.Expect(options.Datastore).To(Equal(types.Datastore{
+		Type:            utils.Pointer("external"),
+		ExternalServers: utils.Pointer([]string{"https://10.0.0.1:2379"}),
+	}))
+	g.Expect(options.APIServer).To(Equal(types.APIServer{}))
+	g.Expect(options.DNS.GetEnabled()).To(BeTrue())
+	g.Expect(options.Kubelet.GetClusterDomain()).To(Equal("cluster.local"))
+
+	// the recorded options are not changed by another round trip
+	changes, err := types.DiffClusterConfig(config, options)
+	g.Expect(err).To(Not(HaveOccurred()))
+	g.Expect(changes).To(BeEmpty())
+}
+
+func TestRollbackClusterConfig(t *testing.T) {
+	revision := types.ClusterConfig{
+		Certificates: types.Certificates{KeyAlgorithm: utils.Pointer("rsa-2048")},
+		Datastore:    types.Datastore{Type: utils.Pointer("k8s-dqlite")},
+		Network:      types.Network{Enabled: utils.Pointer(true), Provider: utils.Pointer("calico")},
+		DNS:          types.DNS{Enabled: utils.Pointer(true)},
+		Kubelet:      types.Kubelet{ClusterDNS: utils.Pointer("10.152.183.10"), ClusterDomain: utils.Pointer("cluster.local")},
+		Ingress:      types.Ingress{Enabled: utils.Pointer(false)},
+		LocalStorage: types.LocalStorage{Enabled: utils.Pointer(true), LocalPath: utils.Pointer("/old")},
+	}
+
+	t.Run("RestoresMutableOptions", func(t *testing.T) {
+		g := NewWithT(t)
+
+		current := types.ClusterConfig{
+			Certificates: types.Certificates{KeyAlgorithm: utils.Pointer("ecdsa-p256")},
+			Datastore:    types.Datastore{Type: utils.Pointer("external"), ExternalServers: utils.Pointer([]string{"https://10.0.0.1:2379"})},
+			Network:      types.Network{Enabled: utils.Pointer(true), Provider: utils.Pointer("cilium"), PodCIDR: utils.Pointer("10.1.0.0/16"), ServiceCIDR: utils.Pointer("10.152.183.0/24")},
+			DNS:          types.DNS{Enabled: utils.Pointer(true)},
+			Kubelet:      types.Kubelet{ClusterDNS: utils.Pointer("10.152.183.20"), ClusterDomain: utils.Pointer("cluster.example")},
+			Ingress:      types.Ingress{Enabled: utils.Pointer(true)},
+			LocalStorage: types.LocalStorage{Enabled: utils.Pointer(true), LocalPath: utils.Pointer("/new")},
+		}
+
+		update, err := types.RollbackClusterConfig(revision, current)
+		g.Expect(err).To(Not(HaveOccurred()))
+
+		merged, err := types.MergeClusterConfig(current, update)
+		g.Expect(err).To(Not(HaveOccurred()))
+		g.Expect(merged.Kubelet.GetClusterDomain()).To(Equal("cluster.local"))
+		g.Expect(merged.Ingress.GetEnabled()).To(BeFalse())
+
+		// immutable options keep their current values
+		g.Expect(merged.Certificates.KeyAlgorithm).To(Equal(utils.Pointer("ecdsa-p256")))
+		g.Expect(merged.Datastore.GetType()).To(Equal("external"))
+		g.Expect(merged.Network.GetProvider()).To(Equal("cilium"))
+		g.Expect(merged.Kubelet.GetClusterDNS()).To(Equal("10.152.183.20"))
+		g.Expect(merged.LocalStorage.GetLocalPath()).To(Equal("/new"))
+	})
+
+	t.Run("RestoresOptionsOfDisabledFeatures", func(t *testing.T) {
+		g := NewWithT(t)
+
+		current := types.ClusterConfig{
+			DNS:          types.DNS{Enabled: utils.Pointer(false)},
+			Kubelet:      types.Kubelet{ClusterDNS: utils.Pointer("10.152.183.20")},
+			LocalStorage: types.LocalStorage{Enabled: utils.Pointer(false), LocalPath: utils.Pointer("/new")},
+		}
+
+		update, err := types.RollbackClusterConfig(revision, current)
+		g.Expect(err).To(Not(HaveOccurred()))
+		g.Expect(update.Kubelet.GetClusterDNS()).To(Equal("10.152.183.10"))
+		g.Expect(update.LocalStorage.GetLocalPath()).To(Equal("/old"))
+	})
+}
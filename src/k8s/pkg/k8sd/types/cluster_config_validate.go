@@ -20,6 +20,51 @@ func validateCIDRs(cidrString string) error {
 			return fmt.Errorf("%q is not a valid CIDR: %w", cidr, err)
 		}
 	}
+	if _, _, err := utils.SplitCIDRStrings(cidrString); err != nil {
+		return err
+	}
+	return nil
+}
+
+// validateCIDRFamilies ensures that the service CIDR only uses IP families of the pod CIDR, e.g. that an IPv6-only
+// cluster does not have an IPv4 service CIDR. A dual-stack pod CIDR may be combined with a single-stack service CIDR.
+func validateCIDRFamilies(podCIDR string, serviceCIDR string) error {
+	podIPv4CIDR, podIPv6CIDR, err := utils.SplitCIDRStrings(podCIDR)
+	if err != nil {
+		return fmt.Errorf("failed to parse pod CIDR: %w", err)
+	}
+	svcIPv4CIDR, svcIPv6CIDR, err := utils.SplitCIDRStrings(serviceCIDR)
+	if err != nil {
+		return fmt.Errorf("failed to parse service CIDR: %w", err)
+	}
+
+	if svcIPv4CIDR != "" && podIPv4CIDR == "" {
+		return fmt.Errorf("IPv4 service CIDR %q requires an IPv4 pod CIDR", svcIPv4CIDR)
+	}
+	if svcIPv6CIDR != "" && podIPv6CIDR == "" {
+		return fmt.Errorf("IPv6 service CIDR %q requires an IPv6 pod CIDR", svcIPv6CIDR)
+	}
+	return nil
+}
+
+// validateClusterDNS ensures that the cluster DNS is a list of valid IP addresses, with at most one IP per IP family.
+func validateClusterDNS(clusterDNS string) error {
+	var hasIPv4, hasIPv6 bool
+	for _, v := range strings.Split(clusterDNS, ",") {
+		ip := net.ParseIP(v)
+		switch {
+		case ip == nil:
+			return fmt.Errorf("%q is not a valid IP address", v)
+		case ip.To4() != nil && hasIPv4:
+			return fmt.Errorf("must contain at most one IPv4 address")
+		case ip.To4() == nil && hasIPv6:
+			return fmt.Errorf("must contain at most one IPv6 address")
+		case ip.To4() != nil:
+			hasIPv4 = true
+		default:
+			hasIPv6 = true
+		}
+	}
 	return nil
 }
 
@@ -95,6 +140,9 @@ func (c *ClusterConfig) Validate() error {
 	if err := validateCIDROverlap(c.Network.GetPodCIDR(), c.Network.GetServiceCIDR()); err != nil {
 		return fmt.Errorf("invalid cidr configuration: %w", err)
 	}
+	if err := validateCIDRFamilies(c.Network.GetPodCIDR(), c.Network.GetServiceCIDR()); err != nil {
+		return fmt.Errorf("invalid cidr configuration: %w", err)
+	}
 	// Can't be an else-if, because default values could already be set.
 	if err := validateIPv6CIDRSize(c.Network.GetServiceCIDR()); err != nil {
 		return fmt.Errorf("invalid service CIDR: %w", err)
@@ -110,6 +158,50 @@ func (c *ClusterConfig) Validate() error {
 		}
 	}
 
+	// check: the network provider is supported and provides the enabled features
+	if err := validateNetworkProvider(c.Network.GetProvider()); err != nil {
+		return fmt.Errorf("invalid network provider: %w", err)
+	}
+	if v := c.Network.GetMigrationPodCIDR(); v != "" {
+		if err := validateMigrationPodCIDR(v, c.Network.GetPodCIDR(), c.Network.GetServiceCIDR()); err != nil {
+			return fmt.Errorf("invalid network migration pod CIDR: %w", err)
+		}
+	}
+	if provider := DefaultNetworkImplementation.IngressProvider; provider != "" && c.Network.GetProvider() != provider {
+		if c.Gateway.GetEnabled() {
+			return fmt.Errorf("gateway requires the %q network provider", provider)
+		}
+		if c.Ingress.GetEnabled() {
+			return fmt.Errorf("ingress requires the %q network provider", provider)
+		}
+	}
+	if c.Network.GetProvider() != NetworkProviderCilium && c.Network.Cilium.GetKubeProxyReplacement() {
+		return fmt.Errorf("kube-proxy replacement requires the %q network provider", NetworkProviderCilium)
+	}
+
+	// check: the default ingress TLS certificate is issued into the default TLS secret
+	if c.CertManager.GetIngressClusterIssuer() != "" {
+		if !c.CertManager.GetEnabled() {
+			return fmt.Errorf("cert-manager ingress cluster issuer requires cert-manager to be enabled")
+		}
+		if c.Ingress.GetDefaultTLSSecret() == "" {
+			return fmt.Errorf("cert-manager ingress cluster issuer requires ingress.default-tls-secret to be set")
+		}
+		if len(c.CertManager.GetIngressDNSNames()) == 0 {
+			return fmt.Errorf("cert-manager ingress cluster issuer requires at least one ingress DNS name")
+		}
+	}
+
+	// check: values overrides are valid objects
+	// NOTE: The overrides are not validated against the values schema of the charts, since the charts are only known to
+	// the feature implementations. Helm validates the merged values against the schema of each chart when the chart is
+	// applied, and a failed validation is reported in the status of the feature.
+	for name := range c.ValuesOverrides {
+		if _, err := c.ValuesOverrides.Get(name); err != nil {
+			return err
+		}
+	}
+
 	// check: load-balancer CIDRs
 	for _, cidr := range c.LoadBalancer.GetCIDRs() {
 		// Handle CIDR
@@ -162,10 +254,10 @@ func (c *ClusterConfig) Validate() error {
 		return fmt.Errorf("local-storage.local-path must be set when local-storage is enabled")
 	}
 
-	// check: ensure cluster DNS is a valid IP address
+	// check: ensure cluster DNS is a list of valid IP addresses, at most one per IP family
 	if v := c.Kubelet.GetClusterDNS(); v != "" {
-		if net.ParseIP(v) == nil {
-			return fmt.Errorf("dns.service-ip must be a valid IP address")
+		if err := validateClusterDNS(v); err != nil {
+			return fmt.Errorf("invalid dns.service-ip: %w", err)
 		}
 
 		// TODO: ensure dns.service-ip is part of new.Network.ServiceCIDR
@@ -178,5 +270,15 @@ func (c *ClusterConfig) Validate() error {
 		}
 	}
 
+	// check: cilium options are supported
+	if err := c.Network.Cilium.validate(); err != nil {
+		return fmt.Errorf("invalid cilium configuration: %w", err)
+	}
+
+	// check: authentication options are complete
+	if err := c.Authentication.validate(); err != nil {
+		return err
+	}
+
 	return nil
 }
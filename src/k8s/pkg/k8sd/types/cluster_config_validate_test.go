@@ -11,17 +11,23 @@ import (
 func TestValidateCIDR(t *testing.T) {
 	for _, tc := range []struct {
 		cidr         string
+		otherCIDR    string
 		expectPodErr bool
 		expectSvcErr bool
 	}{
-		{cidr: "192.168.0.0/16"},
-		{cidr: "2001:0db8::/108"},
-		{cidr: "10.2.0.0/16,2001:0db8::/108"},
-		{cidr: "", expectPodErr: true, expectSvcErr: true},
-		{cidr: "bananas", expectPodErr: true, expectSvcErr: true},
-		{cidr: "fd01::/108,fd02::/108,fd03::/108", expectPodErr: true, expectSvcErr: true},
-		{cidr: "10.1.0.0/32", expectPodErr: true, expectSvcErr: true},
-		{cidr: "2001:0db8::/32", expectSvcErr: true},
+		{cidr: "192.168.0.0/16", otherCIDR: "10.1.0.0/16"},
+		{cidr: "2001:0db8::/108", otherCIDR: "fd98::/108"},
+		{cidr: "10.2.0.0/16,2001:0db8::/108", otherCIDR: "10.1.0.0/16,fd98::/108"},
+		{cidr: "", otherCIDR: "10.1.0.0/16", expectPodErr: true, expectSvcErr: true},
+		{cidr: "bananas", otherCIDR: "10.1.0.0/16", expectPodErr: true, expectSvcErr: true},
+		{cidr: "fd01::/108,fd02::/108,fd03::/108", otherCIDR: "fd98::/108", expectPodErr: true, expectSvcErr: true},
+		{cidr: "10.2.0.0/16,10.3.0.0/16", otherCIDR: "10.1.0.0/16", expectPodErr: true, expectSvcErr: true},
+		{cidr: "fd01::/108,fd02::/108", otherCIDR: "fd98::/108", expectPodErr: true, expectSvcErr: true},
+		{cidr: "10.1.0.0/32", otherCIDR: "10.1.0.0/16", expectPodErr: true, expectSvcErr: true},
+		{cidr: "2001:0db8::/32", otherCIDR: "fd98::/108", expectSvcErr: true},
+		// the service CIDR may only use IP families of the pod CIDR
+		{cidr: "2001:0db8::/108", otherCIDR: "10.1.0.0/16", expectPodErr: true, expectSvcErr: true},
+		{cidr: "10.2.0.0/16", otherCIDR: "10.1.0.0/16,fd98::/108", expectPodErr: true},
 	} {
 		t.Run(tc.cidr, func(t *testing.T) {
 			t.Run("Pod", func(t *testing.T) {
@@ -29,7 +35,7 @@ func TestValidateCIDR(t *testing.T) {
 				config := types.ClusterConfig{
 					Network: types.Network{
 						PodCIDR:     utils.Pointer(tc.cidr),
-						ServiceCIDR: utils.Pointer("10.1.0.0/16"),
+						ServiceCIDR: utils.Pointer(tc.otherCIDR),
 					},
 				}
 				err := config.Validate()
@@ -43,7 +49,7 @@ func TestValidateCIDR(t *testing.T) {
 				g := NewWithT(t)
 				config := types.ClusterConfig{
 					Network: types.Network{
-						PodCIDR:     utils.Pointer("10.1.0.0/16"),
+						PodCIDR:     utils.Pointer(tc.otherCIDR),
 						ServiceCIDR: utils.Pointer(tc.cidr),
 					},
 				}
@@ -58,6 +64,42 @@ func TestValidateCIDR(t *testing.T) {
 	}
 }
 
+func TestValidateClusterDNS(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		serviceCIDR string
+		clusterDNS  string
+		expectErr   bool
+	}{
+		{name: "IPv4", serviceCIDR: "10.152.183.0/24", clusterDNS: "10.152.183.10"},
+		{name: "IPv6", serviceCIDR: "fd98::/108", clusterDNS: "fd98::a"},
+		{name: "DualStack", serviceCIDR: "10.152.183.0/24,fd98::/108", clusterDNS: "10.152.183.10,fd98::a"},
+		{name: "DualStackSingleIP", serviceCIDR: "10.152.183.0/24,fd98::/108", clusterDNS: "fd98::a"},
+		{name: "Invalid", serviceCIDR: "10.152.183.0/24", clusterDNS: "bananas", expectErr: true},
+		{name: "DuplicateIPv4", serviceCIDR: "10.152.183.0/24,fd98::/108", clusterDNS: "10.152.183.10,10.152.183.11", expectErr: true},
+		{name: "DuplicateIPv6", serviceCIDR: "10.152.183.0/24,fd98::/108", clusterDNS: "fd98::a,fd98::b", expectErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			config := types.ClusterConfig{
+				Network: types.Network{
+					PodCIDR:     utils.Pointer("10.1.0.0/16,fd01::/108"),
+					ServiceCIDR: utils.Pointer(tc.serviceCIDR),
+				},
+				Kubelet: types.Kubelet{
+					ClusterDNS: utils.Pointer(tc.clusterDNS),
+				},
+			}
+			err := config.Validate()
+			if tc.expectErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).To(Not(HaveOccurred()))
+			}
+		})
+	}
+}
+
 func TestValidateExternalServers(t *testing.T) {
 	for _, tc := range []struct {
 		name          string
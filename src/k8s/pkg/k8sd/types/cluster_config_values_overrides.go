@@ -0,0 +1,138 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	annotationValuesOverridesPrefix = "k8sd/v1alpha/features/"
+	annotationValuesOverridesSuffix = "/values-overrides"
+)
+
+// ValuesOverridesAnnotation returns the cluster config annotation that holds the Helm values overrides of a feature.
+// The annotation value is a YAML or JSON object that maps the names of the charts of the feature to the Helm values
+// that are deep-merged into the values computed for each chart.
+func ValuesOverridesAnnotation(name FeatureName) string {
+	return annotationValuesOverridesPrefix + string(name) + annotationValuesOverridesSuffix
+}
+
+// ValuesOverrides are the Helm values overrides of features, as JSON encoded objects keyed by feature name.
+// The overrides of a feature are keyed by chart name, so that they only apply to the chart they were written for.
+// A value of "-" removes the overrides of the feature when merged into an existing configuration.
+type ValuesOverrides map[FeatureName]string
+
+// Has returns true if values overrides are set for the given feature, including a removal of the overrides.
+func (v ValuesOverrides) Has(name FeatureName) bool {
+	_, ok := v[name]
+	return ok
+}
+
+// Get returns the Helm values overrides of the given feature keyed by chart name, or nil if the feature has no overrides.
+func (v ValuesOverrides) Get(name FeatureName) (map[string]map[string]any, error) {
+	value, ok := v[name]
+	if !ok || value == "-" {
+		return nil, nil
+	}
+	var overrides map[string]map[string]any
+	if err := json.Unmarshal([]byte(value), &overrides); err != nil {
+		return nil, fmt.Errorf("invalid values overrides for feature %q: %w", name, err)
+	}
+	return overrides, nil
+}
+
+// parseValuesOverrides parses a YAML or JSON object of Helm values keyed by chart name and returns it JSON encoded.
+func parseValuesOverrides(value string) (string, error) {
+	b, err := yaml.YAMLToJSON([]byte(value))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse YAML: %w", err)
+	}
+	var overrides map[string]map[string]any
+	if err := json.Unmarshal(b, &overrides); err != nil || overrides == nil {
+		return "", fmt.Errorf("values overrides must be an object that maps chart names to objects of Helm values")
+	}
+	for chart, values := range overrides {
+		if values == nil {
+			return "", fmt.Errorf("values overrides for chart %q must be an object", chart)
+		}
+	}
+	return string(b), nil
+}
+
+// valuesOverridesFromAnnotations extracts the Helm values overrides of features from the annotations.
+// valuesOverridesFromAnnotations returns the remaining annotations. The input annotations are not modified.
+func valuesOverridesFromAnnotations(annotations Annotations) (ValuesOverrides, Annotations, error) {
+	var overrides ValuesOverrides
+	var remaining Annotations
+	if annotations != nil {
+		remaining = make(Annotations, len(annotations))
+	}
+	for key, value := range annotations {
+		if !strings.HasPrefix(key, annotationValuesOverridesPrefix) || !strings.HasSuffix(key, annotationValuesOverridesSuffix) {
+			remaining[key] = value
+			continue
+		}
+		name := FeatureName(strings.TrimSuffix(strings.TrimPrefix(key, annotationValuesOverridesPrefix), annotationValuesOverridesSuffix))
+		if name == "" || strings.Contains(string(name), "/") {
+			remaining[key] = value
+			continue
+		}
+
+		if overrides == nil {
+			overrides = make(ValuesOverrides)
+		}
+		if value == "-" {
+			overrides[name] = "-"
+			continue
+		}
+		parsed, err := parseValuesOverrides(value)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid value for annotation %q: %w", key, err)
+		}
+		overrides[name] = parsed
+	}
+	return overrides, remaining, nil
+}
+
+// valuesOverridesToAnnotations adds the Helm values overrides of features to a copy of the annotations.
+func valuesOverridesToAnnotations(overrides ValuesOverrides, annotations map[string]string) map[string]string {
+	if len(overrides) == 0 {
+		return annotations
+	}
+
+	result := make(map[string]string, len(annotations)+len(overrides))
+	for key, value := range annotations {
+		result[key] = value
+	}
+	for name, value := range overrides {
+		result[ValuesOverridesAnnotation(name)] = value
+	}
+	return result
+}
+
+// mergeValuesOverridesField merges the values overrides of features.
+// Overrides of a feature replace the existing overrides of the feature. A value of "-" removes them.
+func mergeValuesOverridesField(old ValuesOverrides, new ValuesOverrides) ValuesOverrides {
+	if new == nil {
+		return old
+	}
+
+	m := make(ValuesOverrides, len(old)+len(new))
+	for k, v := range old {
+		m[k] = v
+	}
+	for k, v := range new {
+		if v == "-" {
+			delete(m, k)
+		} else {
+			m[k] = v
+		}
+	}
+	if len(m) == 0 {
+		return nil
+	}
+	return m
+}
@@ -0,0 +1,95 @@
+package types_test
+
+import (
+	"testing"
+
+	apiv1 "github.com/canonical/k8s-snap-api/api/v1"
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/k8s/pkg/utils"
+	. "github.com/onsi/gomega"
+)
+
+func TestValuesOverridesAnnotations(t *testing.T) {
+	t.Run("FromUserFacing", func(t *testing.T) {
+		g := NewWithT(t)
+
+		config, err := types.ClusterConfigFromUserFacing(apiv1.UserFacingClusterConfig{
+			Annotations: map[string]string{
+				types.ValuesOverridesAnnotation("ingress"): "ck-ingress:\n  controller:\n    replicas: 2\n",
+				types.ValuesOverridesAnnotation("dns"):     "-",
+				"key":                                      "value",
+			},
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(config.ValuesOverrides).To(Equal(types.ValuesOverrides{
+			"ingress": `{"ck-ingress":{"controller":{"replicas":2}}}`,
+			"dns":     "-",
+		}))
+		g.Expect(config.Annotations).To(Equal(types.Annotations{"key": "value"}))
+
+		overrides, err := config.ValuesOverrides.Get("ingress")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(overrides).To(Equal(map[string]map[string]any{"ck-ingress": {"controller": map[string]any{"replicas": float64(2)}}}))
+
+		overrides, err = config.ValuesOverrides.Get("dns")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(overrides).To(BeNil())
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		for _, value := range []string{"controller: [", "- a\n- b\n", "42", "ck-ingress: 2", "ck-ingress: null"} {
+			t.Run(value, func(t *testing.T) {
+				g := NewWithT(t)
+
+				_, err := types.ClusterConfigFromUserFacing(apiv1.UserFacingClusterConfig{
+					Annotations: map[string]string{types.ValuesOverridesAnnotation("ingress"): value},
+				})
+				g.Expect(err).To(HaveOccurred())
+			})
+		}
+	})
+
+	t.Run("ToUserFacing", func(t *testing.T) {
+		g := NewWithT(t)
+
+		config := types.ClusterConfig{
+			ValuesOverrides: types.ValuesOverrides{"ingress": `{"ck-ingress":{"controller":{"replicas":2}}}`},
+			Annotations:     types.Annotations{"key": "value"},
+		}
+		g.Expect(config.ToUserFacing().Annotations).To(Equal(map[string]string{
+			types.ValuesOverridesAnnotation("ingress"): `{"ck-ingress":{"controller":{"replicas":2}}}`,
+			"key": "value",
+		}))
+		g.Expect(config.Annotations).To(HaveLen(1))
+	})
+}
+
+func TestMergeValuesOverrides(t *testing.T) {
+	g := NewWithT(t)
+
+	existing := types.ClusterConfig{
+		Network: types.Network{
+			PodCIDR:     utils.Pointer("10.1.0.0/16"),
+			ServiceCIDR: utils.Pointer("10.152.183.0/24"),
+		},
+		ValuesOverrides: types.ValuesOverrides{
+			"ingress": `{"ck-ingress":{"controller":{"replicas":2}}}`,
+			"dns":     `{"ck-dns":{"replicas":3}}`,
+		},
+	}
+	new := types.ClusterConfig{
+		Network: existing.Network,
+		ValuesOverrides: types.ValuesOverrides{
+			"ingress": `{"ck-ingress":{"controller":{"replicas":4}}}`,
+			"dns":     "-",
+		},
+	}
+
+	merged, err := types.MergeClusterConfig(existing, new)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(merged.ValuesOverrides).To(Equal(types.ValuesOverrides{"ingress": `{"ck-ingress":{"controller":{"replicas":4}}}`}))
+
+	merged, err = types.MergeClusterConfig(existing, types.ClusterConfig{Network: existing.Network})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(merged.ValuesOverrides).To(Equal(existing.ValuesOverrides))
+}
@@ -10,6 +10,9 @@ import (
 type FeatureStatus struct {
 	// Enabled shows whether or not the deployment of manifests for a status was successful.
 	Enabled bool
+	// Waiting shows whether the reconciliation of the feature is blocked by the features it depends on, or by the
+	// features that depend on it when it is being disabled. The Message contains the feature that is waited for.
+	Waiting bool
 	// Message contains information about the status of a feature. It is only supposed to be human readable and informative and should not be programmatically parsed.
 	Message string
 	// Version shows the version of the deployed feature.
@@ -18,6 +21,8 @@ type FeatureStatus struct {
 	UpdatedAt time.Time
 }
 
+// ToAPI converts the FeatureStatus to the stable API type.
+// Waiting is not part of the stable API, it is exposed by the GetFeatureStatus RPC of v1alpha.
 func (f FeatureStatus) ToAPI() apiv1.FeatureStatus {
 	return apiv1.FeatureStatus{
 		Enabled:   f.Enabled,
@@ -35,3 +40,37 @@ func FeatureStatusFromAPI(apiFS apiv1.FeatureStatus) FeatureStatus {
 		UpdatedAt: apiFS.UpdatedAt,
 	}
 }
+
+// FeatureReconcileResult is the result of a reconcile attempt of a feature.
+type FeatureReconcileResult string
+
+const (
+	// FeatureReconcileEnabled means that the feature was deployed successfully.
+	FeatureReconcileEnabled FeatureReconcileResult = "enabled"
+	// FeatureReconcileDisabled means that the feature was removed successfully.
+	FeatureReconcileDisabled FeatureReconcileResult = "disabled"
+	// FeatureReconcileFailed means that the feature could not be deployed or removed.
+	FeatureReconcileFailed FeatureReconcileResult = "failed"
+	// FeatureReconcileWaiting means that the feature was not reconciled, because it waits for other features.
+	FeatureReconcileWaiting FeatureReconcileResult = "waiting"
+)
+
+// FeatureReconcile is a recorded reconcile attempt of a feature.
+type FeatureReconcile struct {
+	// StartedAt is the time the reconcile attempt started.
+	StartedAt time.Time
+	// FinishedAt is the time the reconcile attempt finished.
+	FinishedAt time.Time
+	// Result is the result of the reconcile attempt.
+	Result FeatureReconcileResult
+	// Error is the error of a failed reconcile attempt, or the reason a feature is waiting.
+	Error string
+	// Version is the version of the feature.
+	Version string
+	// ValuesHash is a hash of the values of the Helm charts applied during the reconcile attempt.
+	// ValuesHash is empty if no charts were applied.
+	ValuesHash string
+	// Values are the effective values of the Helm charts that were applied by a successful reconcile attempt of an
+	// enabled feature, keyed by chart name. Values are not part of the recorded history.
+	Values map[string]map[string]any
+}
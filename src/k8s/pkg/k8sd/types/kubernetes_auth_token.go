@@ -0,0 +1,22 @@
+package types
+
+import "time"
+
+// KubernetesAuthToken is a token that authenticates with kube-apiserver through the k8sd auth webhook.
+// Only a hash of the token is stored, so the token itself is only known when it is created.
+type KubernetesAuthToken struct {
+	// ID identifies the token, e.g. for revocation.
+	ID int64
+	// Username is the user that the token authenticates as.
+	Username string
+	// Groups are the groups that the token authenticates as.
+	Groups []string
+	// Expiry is the time the token expires. Zero if the token does not expire.
+	Expiry time.Time
+	// LastUsed is the time the token was last used to authenticate. Zero if the token was never used.
+	LastUsed time.Time
+	// IssuedAt is the time the token was issued for a scoped kubeconfig. Zero for other tokens.
+	IssuedAt time.Time
+	// IssuedBy is the identity that requested the scoped kubeconfig. Empty for other tokens.
+	IssuedBy string
+}
@@ -2,7 +2,10 @@ package proxy
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net"
+	"net/http"
 	"reflect"
 	"time"
 
@@ -19,44 +22,88 @@ type APIServerProxy struct {
 
 	// RefreshCh signals the proxy to update the list of known kube-apiserver endpoints. If the list
 	// of kube-apiserver endpoints have changed, the endpoints config file is updated, and the proxy
-	// will restart automatically.
+	// switches to the new endpoints without restarting.
 	RefreshCh <-chan time.Time
 
 	// Kubeconfig is the kubeconfig file to use to refresh the kube-apiserver endpoints.
+	// Kubeconfig is also used to authenticate the health checks of the kube-apiserver endpoints.
 	KubeconfigFile string
+
+	// HealthCheckInterval is the interval between health checks of the kube-apiserver endpoints.
+	// Endpoints are checked with a request to /readyz. Endpoints that fail the check do not receive new connections.
+	HealthCheckInterval time.Duration
+
+	// DialTimeout is the timeout for connecting to a kube-apiserver endpoint, and for each health check.
+	DialTimeout time.Duration
+
+	// DrainTimeout is how long connections to removed kube-apiserver endpoints are kept open.
+	DrainTimeout time.Duration
+
+	// StatusAddress is the address where the proxy serves its metrics (/metrics), status (/status) and health
+	// (/healthz). If empty, the status listener is disabled.
+	StatusAddress string
 }
 
 // Run starts the proxy.
 func (p *APIServerProxy) Run(ctx context.Context) error {
 	ctx = log.NewContext(ctx, log.FromContext(ctx).WithName("apiserver-proxy"))
 
-	for {
-		select {
-		case <-ctx.Done():
-			return nil
-		default:
-		}
+	cfg, err := loadEndpointsConfig(p.EndpointsConfigFile)
+	if err != nil {
+		return fmt.Errorf("failed to load endpoints configuration: %w", err)
+	}
+	srvs, err := parseEndpoints(cfg.Endpoints)
+	if err != nil {
+		return fmt.Errorf("invalid endpoints configuration: %w", err)
+	}
 
-		cfg, err := loadEndpointsConfig(p.EndpointsConfigFile)
-		if err != nil {
-			return fmt.Errorf("failed to load endpoints configuration: %w", err)
-		}
+	l, err := net.Listen("tcp", p.ListenAddress)
+	if err != nil {
+		return fmt.Errorf("failed to start listener: %w", err)
+	}
 
-		proxyCtx, cancel := context.WithCancel(ctx)
-		go p.startProxy(proxyCtx, cancel, cfg.Endpoints)
-		go p.watchForNewEndpoints(proxyCtx, cancel, cfg.Endpoints)
-		<-proxyCtx.Done()
+	tp := &tcpproxy{
+		Listener:        l,
+		MonitorInterval: p.HealthCheckInterval,
+		DialTimeout:     p.DialTimeout,
+		DrainTimeout:    p.DrainTimeout,
 	}
-}
+	if p.KubeconfigFile != "" {
+		tp.HealthCheck = readyzHealthCheck(p.KubeconfigFile)
+	}
+	tp.SetEndpoints(srvs)
+
+	log := log.FromContext(ctx).WithValues(
+		"controller", "proxy",
+		"address", p.ListenAddress,
+		"endpoints", cfg.Endpoints,
+	)
+	log.Info("Starting proxy")
 
-func (p *APIServerProxy) startProxy(ctx context.Context, cancel func(), endpoints []string) {
-	if err := startProxy(ctx, p.ListenAddress, endpoints); err != nil {
-		log.FromContext(ctx).Error(err, "Failed to start")
+	errCh := make(chan error, 2)
+	go func() { errCh <- tp.Run(ctx) }()
+	if p.StatusAddress != "" {
+		server := &http.Server{Addr: p.StatusAddress, Handler: statusHandler(tp)}
+		defer server.Close()
+		go func() {
+			if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				errCh <- fmt.Errorf("status listener failed: %w", err)
+			}
+		}()
+	}
+	go p.watchForNewEndpoints(ctx, tp, cfg.Endpoints)
+
+	select {
+	case <-ctx.Done():
+		tp.Stop()
+		return nil
+	case err := <-errCh:
+		tp.Stop()
+		return fmt.Errorf("proxy failed: %w", err)
 	}
-	cancel()
 }
 
-func (p *APIServerProxy) watchForNewEndpoints(ctx context.Context, cancel func(), endpoints []string) {
+func (p *APIServerProxy) watchForNewEndpoints(ctx context.Context, tp *tcpproxy, endpoints []string) {
 	log := log.FromContext(ctx).WithValues("controller", "watchendpoints")
 	if p.RefreshCh == nil {
 		return
@@ -80,16 +127,20 @@ func (p *APIServerProxy) watchForNewEndpoints(ctx context.Context, cancel func()
 		case len(newEndpoints) == len(endpoints) && reflect.DeepEqual(newEndpoints, endpoints):
 			continue
 		}
-		log = log.WithValues("endpoints", endpoints)
+		log := log.WithValues("endpoints", newEndpoints)
 		log.Info("Updating endpoints")
 
+		srvs, err := parseEndpoints(newEndpoints)
+		if err != nil {
+			log.Error(err, "Failed to parse new endpoints")
+			continue
+		}
 		if err := WriteEndpointsConfig(newEndpoints, p.EndpointsConfigFile); err != nil {
 			log.Error(err, "Failed to update configuration file with new endpoints")
 			continue
 		}
 
-		// cancel context in order to restart the proxy
-		cancel()
-		return
+		tp.SetEndpoints(srvs)
+		endpoints = newEndpoints
 	}
 }
@@ -0,0 +1,44 @@
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// readyzHealthCheck returns a health check that requests /readyz from kube-apiserver.
+// The kubeconfig file is only used for the CA and client credentials, and it is reloaded on every check to pick up
+// rotated certificates. Anonymous authentication is disabled, so the credentials are required.
+func readyzHealthCheck(kubeconfigFile string) func(ctx context.Context, addr string) error {
+	return func(ctx context.Context, addr string) error {
+		config, err := clientcmd.BuildConfigFromFlags("", kubeconfigFile)
+		if err != nil {
+			return fmt.Errorf("failed to load kubeconfig: %w", err)
+		}
+		tlsConfig, err := rest.TLSConfigFor(config)
+		if err != nil {
+			return fmt.Errorf("failed to configure TLS: %w", err)
+		}
+
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig, DisableKeepAlives: true}}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("https://%s/readyz", addr), nil)
+		if err != nil {
+			return fmt.Errorf("failed to create request: %w", err)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return fmt.Errorf("failed to request /readyz: %w", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+			return fmt.Errorf("/readyz returned status %d: %s", resp.StatusCode, body)
+		}
+		return nil
+	}
+}
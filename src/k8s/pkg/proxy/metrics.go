@@ -0,0 +1,109 @@
+package proxy
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	endpointActiveDesc = prometheus.NewDesc(
+		"k8s_apiserver_proxy_endpoint_active",
+		"Whether a kube-apiserver endpoint accepts new connections (1) or not (0).",
+		[]string{"endpoint"}, nil,
+	)
+	endpointDrainingDesc = prometheus.NewDesc(
+		"k8s_apiserver_proxy_endpoint_draining",
+		"Whether a removed kube-apiserver endpoint has open connections that are being drained (1) or not (0).",
+		[]string{"endpoint"}, nil,
+	)
+	endpointConnectionsDesc = prometheus.NewDesc(
+		"k8s_apiserver_proxy_endpoint_open_connections",
+		"Number of open connections to a kube-apiserver endpoint.",
+		[]string{"endpoint"}, nil,
+	)
+	endpointTransferredBytesDesc = prometheus.NewDesc(
+		"k8s_apiserver_proxy_endpoint_transferred_bytes_total",
+		"Bytes forwarded to (sent) and from (received) a kube-apiserver endpoint over closed connections.",
+		[]string{"endpoint", "direction"}, nil,
+	)
+	endpointDialErrorsDesc = prometheus.NewDesc(
+		"k8s_apiserver_proxy_endpoint_dial_errors_total",
+		"Failed connection attempts to a kube-apiserver endpoint.",
+		[]string{"endpoint"}, nil,
+	)
+	endpointDeactivationsDesc = prometheus.NewDesc(
+		"k8s_apiserver_proxy_endpoint_deactivations_total",
+		"Times a kube-apiserver endpoint was deactivated after a failed connection or health check.",
+		[]string{"endpoint"}, nil,
+	)
+	endpointRefreshesDesc = prometheus.NewDesc(
+		"k8s_apiserver_proxy_endpoint_refreshes_total",
+		"Updates of the list of kube-apiserver endpoints.",
+		nil, nil,
+	)
+)
+
+// statusCollector exports the state of the proxy as Prometheus metrics. The values are read on every scrape.
+type statusCollector struct {
+	tp *tcpproxy
+}
+
+func (c statusCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- endpointActiveDesc
+	ch <- endpointDrainingDesc
+	ch <- endpointConnectionsDesc
+	ch <- endpointTransferredBytesDesc
+	ch <- endpointDialErrorsDesc
+	ch <- endpointDeactivationsDesc
+	ch <- endpointRefreshesDesc
+}
+
+func (c statusCollector) Collect(ch chan<- prometheus.Metric) {
+	status := c.tp.Status()
+	for _, ep := range status.Endpoints {
+		active, draining := 0.0, 0.0
+		if ep.Active {
+			active = 1
+		}
+		if ep.Draining {
+			draining = 1
+		}
+		ch <- prometheus.MustNewConstMetric(endpointActiveDesc, prometheus.GaugeValue, active, ep.Address)
+		ch <- prometheus.MustNewConstMetric(endpointDrainingDesc, prometheus.GaugeValue, draining, ep.Address)
+		ch <- prometheus.MustNewConstMetric(endpointConnectionsDesc, prometheus.GaugeValue, float64(ep.Connections), ep.Address)
+		ch <- prometheus.MustNewConstMetric(endpointTransferredBytesDesc, prometheus.CounterValue, float64(ep.BytesSent), ep.Address, "sent")
+		ch <- prometheus.MustNewConstMetric(endpointTransferredBytesDesc, prometheus.CounterValue, float64(ep.BytesReceived), ep.Address, "received")
+		ch <- prometheus.MustNewConstMetric(endpointDialErrorsDesc, prometheus.CounterValue, float64(ep.DialErrors), ep.Address)
+		ch <- prometheus.MustNewConstMetric(endpointDeactivationsDesc, prometheus.CounterValue, float64(ep.Deactivations), ep.Address)
+	}
+	ch <- prometheus.MustNewConstMetric(endpointRefreshesDesc, prometheus.CounterValue, float64(status.Refreshes))
+}
+
+// statusHandler serves the state of the proxy:
+//   - /metrics serves the Prometheus metrics.
+//   - /status serves the Status as JSON.
+//   - /healthz succeeds if at least one endpoint is active.
+func statusHandler(tp *tcpproxy) http.Handler {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(statusCollector{tp: tp})
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	mux.HandleFunc("/status", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(tp.Status())
+	})
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		for _, ep := range tp.Status().Endpoints {
+			if ep.Active {
+				w.Write([]byte("ok"))
+				return
+			}
+		}
+		http.Error(w, "no active kube-apiserver endpoints", http.StatusServiceUnavailable)
+	})
+	return mux
+}
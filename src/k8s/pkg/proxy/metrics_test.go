@@ -0,0 +1,114 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestStatus(t *testing.T) {
+	g := NewWithT(t)
+
+	a, b := startBackend(t, "a"), startBackend(t, "b")
+	tp := &tcpproxy{
+		MonitorInterval: 10 * time.Millisecond,
+		HealthCheck: func(ctx context.Context, addr string) error {
+			if addr == fmt.Sprintf("%s:%d", b.Target, b.Port) {
+				return errors.New("not ready")
+			}
+			return nil
+		},
+	}
+	startProxy(t, tp, a, b)
+
+	g.Eventually(func() bool { return tp.Status().Endpoints[1].Active }, time.Second).Should(BeFalse())
+
+	conn, _ := connect(g, tp)
+	defer conn.Close()
+	_, err := conn.Write([]byte("hello"))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	addrA := fmt.Sprintf("%s:%d", a.Target, a.Port)
+	addrB := fmt.Sprintf("%s:%d", b.Target, b.Port)
+	g.Eventually(tp.Status, time.Second).Should(Equal(Status{
+		Endpoints: []EndpointStatus{
+			{Address: addrA, Active: true, Connections: 1},
+			{Address: addrB, Deactivations: 1},
+		},
+	}))
+
+	t.Run("Metrics", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(testutil.CollectAndCompare(statusCollector{tp: tp}, strings.NewReader(`
+# HELP k8s_apiserver_proxy_endpoint_active Whether a kube-apiserver endpoint accepts new connections (1) or not (0).
+# TYPE k8s_apiserver_proxy_endpoint_active gauge
+k8s_apiserver_proxy_endpoint_active{endpoint="`+addrA+`"} 1
+k8s_apiserver_proxy_endpoint_active{endpoint="`+addrB+`"} 0
+# HELP k8s_apiserver_proxy_endpoint_draining Whether a removed kube-apiserver endpoint has open connections that are being drained (1) or not (0).
+# TYPE k8s_apiserver_proxy_endpoint_draining gauge
+k8s_apiserver_proxy_endpoint_draining{endpoint="`+addrA+`"} 0
+k8s_apiserver_proxy_endpoint_draining{endpoint="`+addrB+`"} 0
+# HELP k8s_apiserver_proxy_endpoint_open_connections Number of open connections to a kube-apiserver endpoint.
+# TYPE k8s_apiserver_proxy_endpoint_open_connections gauge
+k8s_apiserver_proxy_endpoint_open_connections{endpoint="`+addrA+`"} 1
+k8s_apiserver_proxy_endpoint_open_connections{endpoint="`+addrB+`"} 0
+# HELP k8s_apiserver_proxy_endpoint_refreshes_total Updates of the list of kube-apiserver endpoints.
+# TYPE k8s_apiserver_proxy_endpoint_refreshes_total counter
+k8s_apiserver_proxy_endpoint_refreshes_total 0
+`), "k8s_apiserver_proxy_endpoint_active", "k8s_apiserver_proxy_endpoint_draining", "k8s_apiserver_proxy_endpoint_open_connections", "k8s_apiserver_proxy_endpoint_refreshes_total")).To(Succeed())
+	})
+
+	t.Run("Closed", func(t *testing.T) {
+		g := NewWithT(t)
+
+		// The forwarded bytes are counted when the connection is closed.
+		g.Expect(conn.Close()).To(Succeed())
+		g.Eventually(func() EndpointStatus { return tp.Status().Endpoints[0] }, time.Second).Should(Equal(
+			EndpointStatus{Address: addrA, Active: true, BytesSent: 5, BytesReceived: 1},
+		))
+	})
+
+	t.Run("Handler", func(t *testing.T) {
+		g := NewWithT(t)
+
+		server := httptest.NewServer(statusHandler(tp))
+		defer server.Close()
+
+		status, err := GetStatus(context.Background(), strings.TrimPrefix(server.URL, "http://"))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(status).To(Equal(tp.Status()))
+
+		resp, err := http.Get(server.URL + "/healthz")
+		g.Expect(err).ToNot(HaveOccurred())
+		resp.Body.Close()
+		g.Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+		resp, err = http.Get(server.URL + "/metrics")
+		g.Expect(err).ToNot(HaveOccurred())
+		resp.Body.Close()
+		g.Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+		tp.SetEndpoints(nil)
+		g.Expect(tp.Status().Refreshes).To(Equal(uint64(1)))
+
+		// The removed endpoints are reported until their connections are closed.
+		g.Expect(tp.Status().Endpoints).To(ConsistOf(
+			EndpointStatus{Address: addrA, Draining: true, BytesSent: 5, BytesReceived: 1},
+			EndpointStatus{Address: addrB, Draining: true, Deactivations: 1},
+		))
+
+		resp, err = http.Get(server.URL + "/healthz")
+		g.Expect(err).ToNot(HaveOccurred())
+		resp.Body.Close()
+		g.Expect(resp.StatusCode).To(Equal(http.StatusServiceUnavailable))
+	})
+}
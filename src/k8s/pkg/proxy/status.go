@@ -0,0 +1,94 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// EndpointStatus is the state of a kube-apiserver endpoint of the proxy.
+type EndpointStatus struct {
+	// Address is the address of the endpoint.
+	Address string `json:"address"`
+	// Active is true if the endpoint passed the last health check and accepts new connections.
+	Active bool `json:"active"`
+	// Draining is true if the endpoint was removed and its open connections are being drained.
+	Draining bool `json:"draining,omitempty"`
+	// Connections is the number of open connections to the endpoint.
+	Connections int `json:"connections"`
+	// BytesSent is the number of bytes forwarded to the endpoint. The bytes are counted when a connection is closed.
+	BytesSent uint64 `json:"bytes-sent"`
+	// BytesReceived is the number of bytes forwarded from the endpoint. The bytes are counted when a connection is closed.
+	BytesReceived uint64 `json:"bytes-received"`
+	// DialErrors is the number of failed connection attempts to the endpoint.
+	DialErrors uint64 `json:"dial-errors"`
+	// Deactivations is the number of times the endpoint was deactivated after a failed connection or health check.
+	Deactivations uint64 `json:"deactivations"`
+}
+
+// Status is the state of the proxy, as served on /status.
+type Status struct {
+	// Endpoints are the current kube-apiserver endpoints of the proxy, followed by the removed endpoints that are
+	// still draining.
+	Endpoints []EndpointStatus `json:"endpoints"`
+	// Refreshes is the number of times the list of endpoints was updated.
+	Refreshes uint64 `json:"refreshes"`
+}
+
+// Status returns the current state of the proxy.
+func (tp *tcpproxy) Status() Status {
+	tp.mu.Lock()
+	remotes, draining := tp.remotes, tp.draining
+	tp.mu.Unlock()
+
+	all := make([]*remote, 0, len(remotes)+len(draining))
+	addrs := make(map[string]struct{}, len(remotes))
+	for _, r := range remotes {
+		all = append(all, r)
+		addrs[r.addr] = struct{}{}
+	}
+	for _, r := range draining {
+		// NOTE: An endpoint that was added again while its connections are being closed is only reported once.
+		if _, ok := addrs[r.addr]; !ok {
+			all = append(all, r)
+		}
+	}
+
+	status := Status{Endpoints: make([]EndpointStatus, 0, len(all)), Refreshes: tp.refreshes.Load()}
+	for _, r := range all {
+		status.Endpoints = append(status.Endpoints, EndpointStatus{
+			Address:       r.addr,
+			Active:        r.isActive(),
+			Draining:      r.isDraining(),
+			Connections:   r.connections(),
+			BytesSent:     r.bytesSent.Load(),
+			BytesReceived: r.bytesReceived.Load(),
+			DialErrors:    r.dialErrors.Load(),
+			Deactivations: r.deactivations.Load(),
+		})
+	}
+	return status
+}
+
+// GetStatus retrieves the state of the proxy from its status listener.
+func GetStatus(ctx context.Context, address string) (Status, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("http://%s/status", address), nil)
+	if err != nil {
+		return Status{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return Status{}, fmt.Errorf("failed to request proxy status: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Status{}, fmt.Errorf("proxy status returned status %d", resp.StatusCode)
+	}
+	var status Status
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return Status{}, fmt.Errorf("failed to parse proxy status: %w", err)
+	}
+	return status, nil
+}
@@ -20,12 +20,13 @@
 package proxy
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"log"
-	"math/rand"
 	"net"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/canonical/k8s/pkg/utils"
@@ -36,65 +37,234 @@ type remote struct {
 	srv      *net.SRV
 	addr     string
 	inactive bool
+	// draining is set when the endpoint is removed. Draining endpoints do not accept new connections.
+	draining bool
+	// drainTimer closes the open connections of a draining endpoint.
+	drainTimer *time.Timer
+	conns      map[net.Conn]struct{}
+
+	// bytesSent and bytesReceived count the bytes forwarded to and from the endpoint over closed connections.
+	bytesSent     atomic.Uint64
+	bytesReceived atomic.Uint64
+	dialErrors    atomic.Uint64
+	deactivations atomic.Uint64
+}
+
+func newRemote(srv *net.SRV) *remote {
+	ip := net.ParseIP(srv.Target)
+	return &remote{
+		srv:   srv,
+		addr:  fmt.Sprintf("%s:%d", utils.ToIPString(ip), srv.Port),
+		conns: make(map[net.Conn]struct{}),
+	}
 }
 
 func (r *remote) inactivate() {
 	r.mu.Lock()
 	defer r.mu.Unlock()
+	if !r.inactive {
+		r.deactivations.Add(1)
+	}
 	r.inactive = true
 }
 
-func (r *remote) tryReactivate() error {
-	conn, err := net.Dial("tcp", r.addr)
-	if err != nil {
-		return err
-	}
-	conn.Close()
+// setActive updates the active state of the endpoint and returns true if it changed.
+func (r *remote) setActive(active bool) bool {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	r.inactive = false
-	return nil
+	changed := r.inactive == active
+	if changed && !active {
+		r.deactivations.Add(1)
+	}
+	r.inactive = !active
+	return changed
 }
 
 func (r *remote) isActive() bool {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	return !r.inactive
+	return !r.inactive && !r.draining
+}
+
+func (r *remote) isDraining() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.draining
+}
+
+func (r *remote) connections() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.conns)
+}
+
+// track records an open connection to the endpoint.
+// track returns false if the endpoint was drained in the meantime, in which case the connection must be closed.
+func (r *remote) track(conn net.Conn) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.conns == nil {
+		return false
+	}
+	r.conns[conn] = struct{}{}
+	return true
+}
+
+func (r *remote) untrack(conn net.Conn) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.conns, conn)
+}
+
+// drain stops accepting new connections to the endpoint. The open connections are closed after timeout,
+// then done is called.
+func (r *remote) drain(timeout time.Duration, done func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.draining = true
+	r.drainTimer = time.AfterFunc(timeout, func() {
+		if n := r.closeConnections(); n > 0 {
+			log.Printf("closed %d connections to removed endpoint %v\n", n, r.addr)
+		}
+		done()
+	})
+}
+
+// undrain accepts new connections to a draining endpoint again.
+// undrain returns false if the open connections of the endpoint are already being closed.
+func (r *remote) undrain() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.drainTimer == nil || !r.drainTimer.Stop() {
+		return false
+	}
+	r.draining, r.drainTimer = false, nil
+	return true
+}
+
+// closeConnections closes all open connections to the endpoint. No connections can be tracked afterwards.
+func (r *remote) closeConnections() int {
+	r.mu.Lock()
+	conns := r.conns
+	r.conns = nil
+	r.mu.Unlock()
+
+	for conn := range conns {
+		conn.Close()
+	}
+	return len(conns)
 }
 
 type tcpproxy struct {
-	Listener        net.Listener
-	Endpoints       []*net.SRV
+	Listener net.Listener
+	// MonitorInterval is the interval between health checks of the endpoints.
 	MonitorInterval time.Duration
+	// DialTimeout is the timeout for connecting to an endpoint, and for each health check.
+	DialTimeout time.Duration
+	// DrainTimeout is how long connections to removed endpoints are kept open.
+	DrainTimeout time.Duration
+	// HealthCheck checks whether an endpoint is ready to serve requests.
+	// If nil, the endpoints are checked by connecting to them.
+	HealthCheck func(ctx context.Context, addr string) error
+
+	// refreshes counts the updates of the list of endpoints.
+	refreshes atomic.Uint64
+
+	mu      sync.Mutex // guards the following fields
+	remotes []*remote
+	// draining are the removed endpoints whose open connections are not closed yet.
+	draining  []*remote
+	pickCount int // for round robin between endpoints with the same number of connections
+}
+
+// SetEndpoints updates the list of endpoints. It is safe to call SetEndpoints while the proxy is running.
+// Endpoints that are kept retain their state and connections. Removed endpoints are drained: they do not accept new
+// connections, and their open connections are closed after DrainTimeout.
+func (tp *tcpproxy) SetEndpoints(endpoints []*net.SRV) {
+	drainTimeout := tp.DrainTimeout
+	if drainTimeout == 0 {
+		drainTimeout = time.Minute
+	}
+
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+
+	if tp.remotes != nil {
+		tp.refreshes.Add(1)
+	}
 
-	donec chan struct{}
+	existing := make(map[string]*remote, len(tp.remotes))
+	for _, r := range tp.remotes {
+		existing[r.addr] = r
+	}
+	draining := make(map[string]*remote, len(tp.draining))
+	for _, r := range tp.draining {
+		draining[r.addr] = r
+	}
+
+	remotes := make([]*remote, 0, len(endpoints))
+	for _, srv := range endpoints {
+		r := newRemote(srv)
+		if old, ok := existing[r.addr]; ok {
+			old.srv = srv
+			r = old
+			delete(existing, r.addr)
+		} else if old, ok := draining[r.addr]; ok && old.undrain() {
+			log.Printf("endpoint %v was added again, stopped draining it\n", r.addr)
+			old.srv = srv
+			r = old
+			tp.draining = withoutRemote(tp.draining, r)
+		}
+		remotes = append(remotes, r)
+	}
+	tp.remotes = remotes
 
-	mu        sync.Mutex // guards the following fields
-	remotes   []*remote
-	pickCount int // for round robin
+	for _, r := range existing {
+		log.Printf("draining endpoint %v with %d open connections for %v\n", r.addr, r.connections(), drainTimeout)
+		r.drain(drainTimeout, func() {
+			tp.mu.Lock()
+			defer tp.mu.Unlock()
+			tp.draining = withoutRemote(tp.draining, r)
+		})
+		tp.draining = append(tp.draining, r)
+	}
+}
+
+// withoutRemote returns a copy of remotes without r. The slice is copied, as it may be read by Status.
+func withoutRemote(remotes []*remote, r *remote) []*remote {
+	result := make([]*remote, 0, len(remotes))
+	for _, other := range remotes {
+		if other != r {
+			result = append(result, other)
+		}
+	}
+	return result
 }
 
-func (tp *tcpproxy) Run() error {
-	tp.donec = make(chan struct{})
+// Run accepts connections until the listener is closed. Run stops the health checks when ctx is cancelled.
+func (tp *tcpproxy) Run(ctx context.Context) error {
 	if tp.MonitorInterval == 0 {
 		tp.MonitorInterval = 5 * time.Minute
 	}
-	for _, srv := range tp.Endpoints {
-		ip := net.ParseIP(srv.Target)
-		addr := fmt.Sprintf("%s:%d", utils.ToIPString(ip), srv.Port)
-		tp.remotes = append(tp.remotes, &remote{srv: srv, addr: addr})
+	if tp.DialTimeout == 0 {
+		tp.DialTimeout = 10 * time.Second
 	}
 
+	tp.mu.Lock()
 	eps := []string{}
-	for _, ep := range tp.Endpoints {
-		eps = append(eps, fmt.Sprintf("%s:%d", ep.Target, ep.Port))
+	for _, r := range tp.remotes {
+		eps = append(eps, r.addr)
 	}
+	tp.mu.Unlock()
 	log.Printf("ready to proxy client requests to %v\n", eps)
 
-	go tp.runMonitor()
+	go tp.runMonitor(ctx)
 	for {
 		in, err := tp.Listener.Accept()
 		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
 			return err
 		}
 
@@ -102,128 +272,144 @@ func (tp *tcpproxy) Run() error {
 	}
 }
 
-func (tp *tcpproxy) pick() *remote {
-	var weighted []*remote
-	var unweighted []*remote
+// pick returns the endpoint with the least open connections from the best priority class. Ties are broken in a
+// round-robin fashion. Endpoints in tried are skipped.
+// If no endpoint is active, pick falls back to the inactive endpoints, so that failing health checks do not take
+// down the proxy if the endpoints can still be reached.
+func (tp *tcpproxy) pick(tried map[*remote]struct{}) *remote {
+	var best, fallback *remote
+	var bestConns, fallbackConns int
 
-	bestPr := uint16(65535)
-	w := 0
-	// find best priority class
-	for _, r := range tp.remotes {
-		switch {
-		case !r.isActive():
-		case r.srv.Priority < bestPr:
-			bestPr = r.srv.Priority
-			w = 0
-			weighted = nil
-			unweighted = nil
-			fallthrough
-		case r.srv.Priority == bestPr:
-			if r.srv.Weight > 0 {
-				weighted = append(weighted, r)
-				w += int(r.srv.Weight)
-			} else {
-				unweighted = append(unweighted, r)
-			}
-		}
-	}
-	if weighted != nil {
-		if len(unweighted) > 0 && rand.Intn(100) == 1 {
-			// In the presence of records containing weights greater
-			// than 0, records with weight 0 should have a very small
-			// chance of being selected.
-			r := unweighted[tp.pickCount%len(unweighted)]
-			tp.pickCount++
-			return r
+	n := len(tp.remotes)
+	for i := 0; i < n; i++ {
+		r := tp.remotes[(tp.pickCount+i)%n]
+		if _, ok := tried[r]; ok || r.isDraining() {
+			continue
 		}
-		// choose a uniform random number between 0 and the sum computed
-		// (inclusive), and select the RR whose running sum value is the
-		// first in the selected order
-		choose := rand.Intn(w)
-		for i := 0; i < len(weighted); i++ {
-			choose -= int(weighted[i].srv.Weight)
-			if choose <= 0 {
-				return weighted[i]
+		conns := r.connections()
+		if !r.isActive() {
+			if fallback == nil || conns < fallbackConns {
+				fallback, fallbackConns = r, conns
 			}
+			continue
 		}
-	}
-	if unweighted != nil {
-		for i := 0; i < len(tp.remotes); i++ {
-			picked := tp.remotes[tp.pickCount%len(tp.remotes)]
-			tp.pickCount++
-			if picked.isActive() {
-				return picked
-			}
+		if best == nil || r.srv.Priority < best.srv.Priority || (r.srv.Priority == best.srv.Priority && conns < bestConns) {
+			best, bestConns = r, conns
 		}
 	}
-	return nil
+	tp.pickCount++
+
+	if best == nil {
+		return fallback
+	}
+	return best
 }
 
 func (tp *tcpproxy) serve(in net.Conn) {
 	var (
-		err error
-		out net.Conn
+		picked *remote
+		out    net.Conn
 	)
 
+	tried := make(map[*remote]struct{})
 	for {
 		tp.mu.Lock()
-		remote := tp.pick()
+		picked = tp.pick(tried)
 		tp.mu.Unlock()
-		if remote == nil {
+		if picked == nil {
 			break
 		}
-		// TODO: add timeout
-		out, err = net.Dial("tcp", remote.addr)
+		tried[picked] = struct{}{}
+
+		var err error
+		out, err = net.DialTimeout("tcp", picked.addr, tp.DialTimeout)
 		if err == nil {
 			break
 		}
-		remote.inactivate()
-		log.Printf("deactivated endpoint %v for interval %v, error was %q", remote.addr, tp.MonitorInterval, err)
+		picked.dialErrors.Add(1)
+		picked.inactivate()
+		log.Printf("deactivated endpoint %v until the next health check in %v, error was %q", picked.addr, tp.MonitorInterval, err)
 	}
 
 	if out == nil {
 		in.Close()
 		return
 	}
+	if !picked.track(out) {
+		out.Close()
+		in.Close()
+		return
+	}
+	defer picked.untrack(out)
 
+	// NOTE: The bytes are counted from the result of io.Copy, so that the connections are still spliced.
 	go func() {
-		io.Copy(in, out)
+		n, _ := io.Copy(in, out)
+		picked.bytesReceived.Add(uint64(n))
 		in.Close()
 		out.Close()
 	}()
 
-	io.Copy(out, in)
+	n, _ := io.Copy(out, in)
+	picked.bytesSent.Add(uint64(n))
 	out.Close()
 	in.Close()
 }
 
-func (tp *tcpproxy) runMonitor() {
+// check runs the health check against an endpoint.
+func (tp *tcpproxy) check(ctx context.Context, r *remote) error {
+	ctx, cancel := context.WithTimeout(ctx, tp.DialTimeout)
+	defer cancel()
+
+	if tp.HealthCheck != nil {
+		return tp.HealthCheck(ctx, r.addr)
+	}
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", r.addr)
+	if err != nil {
+		return err
+	}
+	conn.Close()
+	return nil
+}
+
+func (tp *tcpproxy) runMonitor(ctx context.Context) {
+	ticker := time.NewTicker(tp.MonitorInterval)
+	defer ticker.Stop()
+
 	for {
 		select {
-		case <-time.After(tp.MonitorInterval):
-			tp.mu.Lock()
-			for _, rem := range tp.remotes {
-				if rem.isActive() {
-					continue
-				}
-				go func(r *remote) {
-					if err := r.tryReactivate(); err != nil {
-						log.Printf("failed to activate endpoint %v (stay inactive for another interval %v)\n", r.addr, tp.MonitorInterval)
-					} else {
-						log.Printf("activated endpoint %v\n", r.addr)
-					}
-				}(rem)
-			}
-			tp.mu.Unlock()
-		case <-tp.donec:
+		case <-ticker.C:
+		case <-ctx.Done():
 			return
 		}
+
+		tp.mu.Lock()
+		remotes := tp.remotes
+		tp.mu.Unlock()
+
+		var wg sync.WaitGroup
+		for _, rem := range remotes {
+			wg.Add(1)
+			go func(r *remote) {
+				defer wg.Done()
+				err := tp.check(ctx, r)
+				if !r.setActive(err == nil) {
+					return
+				}
+				if err != nil {
+					log.Printf("deactivated endpoint %v after failed health check, error was %q\n", r.addr, err)
+				} else {
+					log.Printf("activated endpoint %v\n", r.addr)
+				}
+			}(rem)
+		}
+		wg.Wait()
 	}
 }
 
+// Stop closes the listener. Open connections are not closed.
 func (tp *tcpproxy) Stop() {
-	// graceful shutdown?
-	// shutdown current connections?
 	tp.Listener.Close()
-	close(tp.donec)
 }
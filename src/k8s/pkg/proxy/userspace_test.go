@@ -0,0 +1,210 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+// startBackend starts a TCP server that writes its name to every connection and keeps the connections open.
+func startBackend(t *testing.T, name string) *net.SRV {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to start backend: %v", err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				conn.Write([]byte(name))
+				io.Copy(io.Discard, conn)
+			}()
+		}
+	}()
+
+	return &net.SRV{Target: "127.0.0.1", Port: uint16(l.Addr().(*net.TCPAddr).Port)}
+}
+
+// startProxy starts a tcpproxy for the endpoints on a random local port.
+func startProxy(t *testing.T, tp *tcpproxy, endpoints ...*net.SRV) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to start listener: %v", err)
+	}
+	tp.Listener = l
+	tp.SetEndpoints(endpoints)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(func() {
+		cancel()
+		tp.Stop()
+	})
+	go tp.Run(ctx)
+}
+
+// connect opens a connection through the proxy and returns the connection and the name of the backend.
+func connect(g Gomega, tp *tcpproxy) (net.Conn, string) {
+	conn, err := net.Dial("tcp", tp.Listener.Addr().String())
+	g.Expect(err).ToNot(HaveOccurred())
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	b := make([]byte, 1)
+	_, err = io.ReadFull(conn, b)
+	g.Expect(err).ToNot(HaveOccurred())
+	return conn, string(b)
+}
+
+func TestTCPProxy(t *testing.T) {
+	t.Run("LeastConnections", func(t *testing.T) {
+		g := NewWithT(t)
+
+		tp := &tcpproxy{}
+		startProxy(t, tp, startBackend(t, "a"), startBackend(t, "b"))
+
+		counts := map[string]int{}
+		for i := 0; i < 6; i++ {
+			conn, name := connect(g, tp)
+			defer conn.Close()
+			counts[name]++
+		}
+		g.Expect(counts).To(Equal(map[string]int{"a": 3, "b": 3}))
+	})
+
+	t.Run("HealthCheck", func(t *testing.T) {
+		g := NewWithT(t)
+
+		a, b := startBackend(t, "a"), startBackend(t, "b")
+		tp := &tcpproxy{
+			MonitorInterval: 10 * time.Millisecond,
+			HealthCheck: func(ctx context.Context, addr string) error {
+				if addr == fmt.Sprintf("%s:%d", a.Target, a.Port) {
+					return errors.New("not ready")
+				}
+				return nil
+			},
+		}
+		startProxy(t, tp, a, b)
+
+		g.Eventually(func() bool {
+			tp.mu.Lock()
+			defer tp.mu.Unlock()
+			return tp.remotes[0].isActive()
+		}, time.Second).Should(BeFalse())
+
+		for i := 0; i < 3; i++ {
+			conn, name := connect(g, tp)
+			defer conn.Close()
+			g.Expect(name).To(Equal("b"))
+		}
+	})
+
+	t.Run("Fallback", func(t *testing.T) {
+		g := NewWithT(t)
+
+		tp := &tcpproxy{
+			MonitorInterval: 10 * time.Millisecond,
+			HealthCheck:     func(ctx context.Context, addr string) error { return errors.New("not ready") },
+		}
+		startProxy(t, tp, startBackend(t, "a"))
+
+		g.Eventually(func() bool {
+			tp.mu.Lock()
+			defer tp.mu.Unlock()
+			return tp.remotes[0].isActive()
+		}, time.Second).Should(BeFalse())
+
+		conn, name := connect(g, tp)
+		defer conn.Close()
+		g.Expect(name).To(Equal("a"))
+	})
+
+	t.Run("DialFailure", func(t *testing.T) {
+		g := NewWithT(t)
+
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		g.Expect(err).ToNot(HaveOccurred())
+		closed := &net.SRV{Target: "127.0.0.1", Port: uint16(l.Addr().(*net.TCPAddr).Port)}
+		l.Close()
+
+		tp := &tcpproxy{}
+		startProxy(t, tp, closed, startBackend(t, "b"))
+
+		for i := 0; i < 3; i++ {
+			conn, name := connect(g, tp)
+			defer conn.Close()
+			g.Expect(name).To(Equal("b"))
+		}
+
+		tp.mu.Lock()
+		defer tp.mu.Unlock()
+		g.Expect(tp.remotes[0].isActive()).To(BeFalse())
+	})
+
+	t.Run("Drain", func(t *testing.T) {
+		g := NewWithT(t)
+
+		a, b := startBackend(t, "a"), startBackend(t, "b")
+		tp := &tcpproxy{DrainTimeout: 200 * time.Millisecond}
+		startProxy(t, tp, a)
+
+		drained, name := connect(g, tp)
+		defer drained.Close()
+		g.Expect(name).To(Equal("a"))
+
+		tp.SetEndpoints([]*net.SRV{b})
+
+		conn, name := connect(g, tp)
+		defer conn.Close()
+		g.Expect(name).To(Equal("b"))
+
+		// The connection to the removed endpoint is kept open until the drain timeout.
+		drained.SetReadDeadline(time.Now().Add(50 * time.Millisecond))
+		_, err := drained.Read(make([]byte, 1))
+		var netErr net.Error
+		g.Expect(errors.As(err, &netErr) && netErr.Timeout()).To(BeTrue())
+
+		drained.SetReadDeadline(time.Now().Add(5 * time.Second))
+		_, err = drained.Read(make([]byte, 1))
+		g.Expect(err).To(MatchError(io.EOF))
+		g.Eventually(func() []*remote {
+			tp.mu.Lock()
+			defer tp.mu.Unlock()
+			return tp.draining
+		}, time.Second).Should(BeEmpty())
+	})
+
+	t.Run("AddedWhileDraining", func(t *testing.T) {
+		g := NewWithT(t)
+
+		a, b := startBackend(t, "a"), startBackend(t, "b")
+		tp := &tcpproxy{DrainTimeout: 200 * time.Millisecond}
+		startProxy(t, tp, a)
+
+		conn, name := connect(g, tp)
+		defer conn.Close()
+		g.Expect(name).To(Equal("a"))
+
+		tp.SetEndpoints([]*net.SRV{b})
+		tp.SetEndpoints([]*net.SRV{a, b})
+		g.Expect(tp.Status().Endpoints).To(HaveLen(2))
+		g.Expect(tp.Status().Endpoints[0].Draining).To(BeFalse())
+
+		// The connection to the endpoint that was added again is not closed.
+		conn.SetReadDeadline(time.Now().Add(500 * time.Millisecond))
+		_, err := conn.Read(make([]byte, 1))
+		var netErr net.Error
+		g.Expect(errors.As(err, &netErr) && netErr.Timeout()).To(BeTrue())
+	})
+}
@@ -58,6 +58,8 @@ type Snap interface {
 
 	K8sdStateDir() string      // /var/snap/k8s/common/var/lib/k8sd/state
 	K8sDqliteStateDir() string // /var/snap/k8s/common/var/lib/k8s-dqlite
+	EtcdStateDir() string      // /var/snap/k8s/common/var/lib/etcd
+	BackupDir() string         // /var/snap/k8s/common/var/lib/k8s-backups
 
 	ServiceArgumentsDir() string   // /var/snap/k8s/common/args
 	ServiceExtraConfigDir() string // /var/snap/k8s/common/args/conf.d
@@ -43,6 +43,8 @@ type Mock struct {
 	K8sInspectScriptPath        string
 	K8sdStateDir                string
 	K8sDqliteStateDir           string
+	EtcdStateDir                string
+	BackupDir                   string
 	ServiceArgumentsDir         string
 	ServiceExtraConfigDir       string
 	LockFilesDir                string
@@ -250,6 +252,14 @@ func (s *Snap) K8sDqliteStateDir() string {
 	return s.Mock.K8sDqliteStateDir
 }
 
+func (s *Snap) EtcdStateDir() string {
+	return s.Mock.EtcdStateDir
+}
+
+func (s *Snap) BackupDir() string {
+	return s.Mock.BackupDir
+}
+
 func (s *Snap) ServiceArgumentsDir() string {
 	return s.Mock.ServiceArgumentsDir
 }
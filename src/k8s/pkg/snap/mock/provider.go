@@ -1,6 +1,8 @@
 package mock
 
 import (
+	"github.com/canonical/k8s/pkg/k8sd/controllers/csrsigning"
+	"github.com/canonical/k8s/pkg/k8sd/types"
 	"github.com/canonical/k8s/pkg/snap"
 	"github.com/canonical/microcluster/v2/microcluster"
 )
@@ -9,7 +11,8 @@ type Provider struct {
 	MicroClusterFn                     func() *microcluster.MicroCluster
 	SnapFn                             func() snap.Snap
 	NotifyUpdateNodeConfigControllerFn func()
-	NotifyFeatureControllerFn          func(network, gateway, ingress, loadBalancer, localStorage, metricsServer, dns bool)
+	NotifyFeaturesFn                   func(names ...types.FeatureName)
+	SignersFn                          func() *csrsigning.SignerCache
 }
 
 func (p *Provider) MicroCluster() *microcluster.MicroCluster {
@@ -32,8 +35,15 @@ func (p *Provider) NotifyUpdateNodeConfigController() {
 	}
 }
 
-func (p *Provider) NotifyFeatureController(network, gateway, ingress, loadBalancer, localStorage, metricsServer, dns bool) {
-	if p.NotifyFeatureControllerFn != nil {
-		p.NotifyFeatureControllerFn(network, gateway, ingress, loadBalancer, localStorage, metricsServer, dns)
+func (p *Provider) NotifyFeatures(names ...types.FeatureName) {
+	if p.NotifyFeaturesFn != nil {
+		p.NotifyFeaturesFn(names...)
 	}
 }
+
+func (p *Provider) Signers() *csrsigning.SignerCache {
+	if p.SignersFn != nil {
+		return p.SignersFn()
+	}
+	return csrsigning.NewSignerCache()
+}
@@ -283,6 +283,14 @@ func (s *snap) K8sDqliteStateDir() string {
 	return filepath.Join(s.snapCommonDir, "var", "lib", "k8s-dqlite")
 }
 
+func (s *snap) EtcdStateDir() string {
+	return filepath.Join(s.snapCommonDir, "var", "lib", "etcd")
+}
+
+func (s *snap) BackupDir() string {
+	return filepath.Join(s.snapCommonDir, "var", "lib", "k8s-backups")
+}
+
 func (s *snap) ServiceArgumentsDir() string {
 	return filepath.Join(s.snapCommonDir, "args")
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/canonical/k8s/pkg/snap"
 	"github.com/canonical/k8s/pkg/utils"
@@ -36,3 +37,46 @@ func MarkAsWorkerNode(snap snap.Snap, mark bool) error {
 
 	return nil
 }
+
+// IsKubeProxyDisabled returns true if kube-proxy was stopped on the node, because it is replaced by the CNI.
+func IsKubeProxyDisabled(snap snap.Snap) (bool, error) {
+	return utils.FileExists(filepath.Join(snap.LockFilesDir(), "kube-proxy-disabled"))
+}
+
+// MarkKubeProxyDisabled marks kube-proxy as stopped on the node, because it is replaced by the CNI.
+func MarkKubeProxyDisabled(snap snap.Snap, mark bool) error {
+	fname := filepath.Join(snap.LockFilesDir(), "kube-proxy-disabled")
+
+	if mark {
+		if err := utils.WriteFile(fname, nil, 0o600); err != nil {
+			return fmt.Errorf("failed to mark kube-proxy as disabled: %w", err)
+		}
+	} else {
+		if err := os.Remove(fname); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to unmark kube-proxy as disabled: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// GetNetworkProvider returns the network provider that the node was switched to, or an empty string if the node
+// was never switched.
+func GetNetworkProvider(snap snap.Snap) (string, error) {
+	b, err := os.ReadFile(filepath.Join(snap.LockFilesDir(), "network-provider"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to read network provider: %w", err)
+	}
+	return strings.TrimSpace(string(b)), nil
+}
+
+// SetNetworkProvider records the network provider that the node was switched to.
+func SetNetworkProvider(snap snap.Snap, provider string) error {
+	if err := utils.WriteFile(filepath.Join(snap.LockFilesDir(), "network-provider"), []byte(provider), 0o600); err != nil {
+		return fmt.Errorf("failed to write network provider: %w", err)
+	}
+	return nil
+}
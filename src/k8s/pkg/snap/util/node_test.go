@@ -112,3 +112,52 @@ func TestMarkAsWorkerNode_ErrorCases(t *testing.T) {
 		g.Expect(err).To(HaveOccurred())
 	})
 }
+
+func TestMarkKubeProxyDisabled(t *testing.T) {
+	g := NewWithT(t)
+	mock := &mock.Snap{
+		Mock: mock.Mock{
+			LockFilesDir: t.TempDir(),
+		},
+	}
+
+	disabled, err := snaputil.IsKubeProxyDisabled(mock)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(disabled).To(BeFalse())
+
+	g.Expect(snaputil.MarkKubeProxyDisabled(mock, true)).To(Succeed())
+	disabled, err = snaputil.IsKubeProxyDisabled(mock)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(disabled).To(BeTrue())
+
+	g.Expect(snaputil.MarkKubeProxyDisabled(mock, false)).To(Succeed())
+	disabled, err = snaputil.IsKubeProxyDisabled(mock)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(disabled).To(BeFalse())
+
+	// unmarking is idempotent
+	g.Expect(snaputil.MarkKubeProxyDisabled(mock, false)).To(Succeed())
+}
+
+func TestNetworkProvider(t *testing.T) {
+	g := NewWithT(t)
+	mock := &mock.Snap{
+		Mock: mock.Mock{
+			LockFilesDir: t.TempDir(),
+		},
+	}
+
+	provider, err := snaputil.GetNetworkProvider(mock)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(provider).To(BeEmpty())
+
+	g.Expect(snaputil.SetNetworkProvider(mock, "calico")).To(Succeed())
+	provider, err = snaputil.GetNetworkProvider(mock)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(provider).To(Equal("calico"))
+
+	g.Expect(snaputil.SetNetworkProvider(mock, "cilium")).To(Succeed())
+	provider, err = snaputil.GetNetworkProvider(mock)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(provider).To(Equal("cilium"))
+}
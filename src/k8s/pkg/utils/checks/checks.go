@@ -34,6 +34,11 @@ func CheckK8sServicePorts(config types.ClusterConfig, serviceConfigs types.K8sSe
 		ports["kube-proxy-metrics"] = port
 	}
 
+	if isControlPlane && config.Datastore.GetType() == "etcd" {
+		ports["etcd"] = strconv.Itoa(config.Datastore.GetEtcdPort())
+		ports["etcd-peer"] = strconv.Itoa(config.Datastore.GetEtcdPeerPort())
+	}
+
 	if isControlPlane {
 		ports["kube-apiserver"] = strconv.Itoa(config.APIServer.GetSecurePort())
 		ports["kube-scheduler"] = serviceConfigs.GetKubeSchedulerPort()
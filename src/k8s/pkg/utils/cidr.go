@@ -209,6 +209,7 @@ func FindCIDRForIP(nodeIp net.IP) (cidr string, err error) {
 }
 
 // SplitCIDRStrings parses the given CIDR string and returns the respective IPv4 and IPv6 CIDRs.
+// SplitCIDRStrings returns an error if the CIDR string contains two CIDRs of the same IP family.
 func SplitCIDRStrings(cidrString string) (string, string, error) {
 	clusterCIDRs := strings.Split(cidrString, ",")
 	if v := len(clusterCIDRs); v != 1 && v != 2 {
@@ -225,14 +226,45 @@ func SplitCIDRStrings(cidrString string) (string, string, error) {
 		case err != nil:
 			return "", "", fmt.Errorf("failed to parse cidr: %w", err)
 		case parsed.IP.To4() != nil:
+			if ipv4CIDR != "" {
+				return "", "", fmt.Errorf("dual-stack CIDR list %q must contain one IPv4 and one IPv6 CIDR", cidrString)
+			}
 			ipv4CIDR = cidr
 		default:
+			if ipv6CIDR != "" {
+				return "", "", fmt.Errorf("dual-stack CIDR list %q must contain one IPv4 and one IPv6 CIDR", cidrString)
+			}
 			ipv6CIDR = cidr
 		}
 	}
 	return ipv4CIDR, ipv6CIDR, nil
 }
 
+// NodeIPsForCIDRs selects the node IPs that match the IP families of a "CIDR[,CIDR]" list, in the order of the list.
+// For example, the node IPs of an IPv6-only cluster only include the IPv6 address of the node, and the node IPs of a
+// dual-stack cluster with an IPv6 primary CIDR start with the IPv6 address of the node.
+// NodeIPsForCIDRs returns the node IPs unchanged if none of them matches the IP families, or if the CIDRs are invalid.
+func NodeIPsForCIDRs(nodeIPs []net.IP, cidrString string) []net.IP {
+	var result []net.IP
+	for _, cidr := range strings.Split(cidrString, ",") {
+		_, parsed, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nodeIPs
+		}
+		isIPv4 := parsed.IP.To4() != nil
+		for _, ip := range nodeIPs {
+			if (ip.To4() != nil) == isIPv4 {
+				result = append(result, ip)
+				break
+			}
+		}
+	}
+	if len(result) == 0 {
+		return nodeIPs
+	}
+	return result
+}
+
 // IsIPv4 returns true if the address is a valid IPv4 address, false otherwise.
 // The address may contain a port number.
 func IsIPv4(address string) bool {
@@ -167,6 +167,14 @@ func TestParseCIDRs(t *testing.T) {
 			expectedIPv6: "",
 			expectedErr:  true,
 		},
+		{
+			input:       "192.168.1.0/24,10.0.0.0/8",
+			expectedErr: true,
+		},
+		{
+			input:       "2001:db8::/32,fd00::/64",
+			expectedErr: true,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -255,3 +263,27 @@ func getInterfaceNameForIP(ip net.IP) (string, error) {
 
 	return "", errors.New("no interface found for the given IP")
 }
+
+func TestNodeIPsForCIDRs(t *testing.T) {
+	ipv4, ipv6 := net.ParseIP("10.0.0.2"), net.ParseIP("fd00::2")
+
+	for _, tc := range []struct {
+		name     string
+		nodeIPs  []net.IP
+		cidrs    string
+		expected []net.IP
+	}{
+		{name: "IPv4", nodeIPs: []net.IP{ipv4, ipv6}, cidrs: "10.1.0.0/16", expected: []net.IP{ipv4}},
+		{name: "IPv6", nodeIPs: []net.IP{ipv4, ipv6}, cidrs: "fd01::/108", expected: []net.IP{ipv6}},
+		{name: "DualStack", nodeIPs: []net.IP{ipv4, ipv6}, cidrs: "10.1.0.0/16,fd01::/108", expected: []net.IP{ipv4, ipv6}},
+		{name: "DualStackIPv6Primary", nodeIPs: []net.IP{ipv4, ipv6}, cidrs: "fd01::/108,10.1.0.0/16", expected: []net.IP{ipv6, ipv4}},
+		{name: "DualStackSingleNodeIP", nodeIPs: []net.IP{ipv4}, cidrs: "fd01::/108,10.1.0.0/16", expected: []net.IP{ipv4}},
+		{name: "NoMatch", nodeIPs: []net.IP{ipv4}, cidrs: "fd01::/108", expected: []net.IP{ipv4}},
+		{name: "InvalidCIDR", nodeIPs: []net.IP{ipv4, ipv6}, cidrs: "invalid", expected: []net.IP{ipv4, ipv6}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(utils.NodeIPsForCIDRs(tc.nodeIPs, tc.cidrs)).To(Equal(tc.expected))
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package pkiutil
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+)
+
+// KeyAlgorithm is the algorithm of generated private keys.
+type KeyAlgorithm string
+
+const (
+	KeyAlgorithmRSA2048   KeyAlgorithm = "rsa-2048"
+	KeyAlgorithmRSA4096   KeyAlgorithm = "rsa-4096"
+	KeyAlgorithmECDSAP256 KeyAlgorithm = "ecdsa-p256"
+	KeyAlgorithmECDSAP384 KeyAlgorithm = "ecdsa-p384"
+	KeyAlgorithmEd25519   KeyAlgorithm = "ed25519"
+
+	// DefaultKeyAlgorithm is used when no key algorithm is specified.
+	DefaultKeyAlgorithm = KeyAlgorithmRSA2048
+)
+
+// KeyAlgorithms is the list of supported key algorithms.
+var KeyAlgorithms = []KeyAlgorithm{KeyAlgorithmRSA2048, KeyAlgorithmRSA4096, KeyAlgorithmECDSAP256, KeyAlgorithmECDSAP384, KeyAlgorithmEd25519}
+
+// ParseKeyAlgorithm parses a key algorithm. An empty string is parsed as the DefaultKeyAlgorithm.
+func ParseKeyAlgorithm(s string) (KeyAlgorithm, error) {
+	if s == "" {
+		return DefaultKeyAlgorithm, nil
+	}
+	for _, algorithm := range KeyAlgorithms {
+		if string(algorithm) == s {
+			return algorithm, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported key algorithm %q, must be one of %v", s, KeyAlgorithms)
+}
+
+// GenerateKey generates a new private key with the specified algorithm.
+// GenerateKey uses the DefaultKeyAlgorithm if algorithm is empty.
+func GenerateKey(algorithm KeyAlgorithm) (crypto.Signer, error) {
+	switch algorithm {
+	case "", KeyAlgorithmRSA2048:
+		return rsa.GenerateKey(rand.Reader, 2048)
+	case KeyAlgorithmRSA4096:
+		return rsa.GenerateKey(rand.Reader, 4096)
+	case KeyAlgorithmECDSAP256:
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case KeyAlgorithmECDSAP384:
+		return ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	case KeyAlgorithmEd25519:
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		return key, err
+	default:
+		return nil, fmt.Errorf("unsupported key algorithm %q", algorithm)
+	}
+}
+
+// EncodePrivateKey encodes the private key to PEM.
+// RSA keys are encoded in PKCS#1 and ECDSA keys in SEC 1 format, while Ed25519 keys are encoded in PKCS#8 format.
+func EncodePrivateKey(key crypto.Signer) (string, error) {
+	var block *pem.Block
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		block = &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(k)}
+	case *ecdsa.PrivateKey:
+		b, err := x509.MarshalECPrivateKey(k)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal ECDSA private key: %w", err)
+		}
+		block = &pem.Block{Type: "EC PRIVATE KEY", Bytes: b}
+	case ed25519.PrivateKey:
+		b, err := x509.MarshalPKCS8PrivateKey(k)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal Ed25519 private key: %w", err)
+		}
+		block = &pem.Block{Type: "PRIVATE KEY", Bytes: b}
+	default:
+		return "", fmt.Errorf("unsupported private key type %T", key)
+	}
+
+	keyPEM := pem.EncodeToMemory(block)
+	if keyPEM == nil {
+		return "", fmt.Errorf("failed to encode private key PEM")
+	}
+	return string(keyPEM), nil
+}
+
+// LeafKeyUsage returns the key usage of a leaf certificate for the given public key, e.g. the public key of a
+// certificate signing request. Key encipherment is only possible with RSA keys.
+func LeafKeyUsage(pub crypto.PublicKey) x509.KeyUsage {
+	if _, ok := pub.(*rsa.PublicKey); ok {
+		return x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature
+	}
+	return x509.KeyUsageDigitalSignature
+}
@@ -0,0 +1,113 @@
+package pkiutil_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net"
+	"testing"
+	"time"
+
+	pkiutil "github.com/canonical/k8s/pkg/utils/pki"
+	. "github.com/onsi/gomega"
+)
+
+func TestParseKeyAlgorithm(t *testing.T) {
+	g := NewWithT(t)
+
+	algorithm, err := pkiutil.ParseKeyAlgorithm("")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(algorithm).To(Equal(pkiutil.DefaultKeyAlgorithm))
+
+	algorithm, err = pkiutil.ParseKeyAlgorithm("ecdsa-p256")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(algorithm).To(Equal(pkiutil.KeyAlgorithmECDSAP256))
+
+	_, err = pkiutil.ParseKeyAlgorithm("dsa-1024")
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestKeyAlgorithms(t *testing.T) {
+	for _, tc := range []struct {
+		algorithm pkiutil.KeyAlgorithm
+		keyType   any
+		keyUsage  x509.KeyUsage
+	}{
+		{algorithm: "", keyType: &rsa.PrivateKey{}, keyUsage: x509.KeyUsageKeyEncipherment | x509.KeyUsageDataEncipherment | x509.KeyUsageDigitalSignature},
+		{algorithm: pkiutil.KeyAlgorithmRSA2048, keyType: &rsa.PrivateKey{}, keyUsage: x509.KeyUsageKeyEncipherment | x509.KeyUsageDataEncipherment | x509.KeyUsageDigitalSignature},
+		{algorithm: pkiutil.KeyAlgorithmECDSAP256, keyType: &ecdsa.PrivateKey{}, keyUsage: x509.KeyUsageDigitalSignature},
+		{algorithm: pkiutil.KeyAlgorithmECDSAP384, keyType: &ecdsa.PrivateKey{}, keyUsage: x509.KeyUsageDigitalSignature},
+		{algorithm: pkiutil.KeyAlgorithmEd25519, keyType: ed25519.PrivateKey{}, keyUsage: x509.KeyUsageDigitalSignature},
+	} {
+		t.Run(string(tc.algorithm), func(t *testing.T) {
+			g := NewWithT(t)
+			notBefore := time.Now()
+
+			caPEM, caKeyPEM, err := pkiutil.GenerateSelfSignedCA(pkix.Name{CommonName: "test-ca"}, notBefore, notBefore.AddDate(1, 0, 0), tc.algorithm)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(pkiutil.CertCheck{AllowSelfSigned: true}.ValidateKeypair(caPEM, caKeyPEM)).To(Succeed())
+
+			caCert, caKey, err := pkiutil.LoadCertificate(caPEM, caKeyPEM)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(caKey).To(BeAssignableToTypeOf(tc.keyType))
+			g.Expect(caCert.KeyUsage).To(Equal(x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign))
+
+			t.Run("SignCertificate", func(t *testing.T) {
+				g := NewWithT(t)
+
+				template, err := pkiutil.GenerateCertificate(pkix.Name{CommonName: "test-cert"}, notBefore, notBefore.AddDate(1, 0, 0), false, []string{"node1"}, nil)
+				g.Expect(err).ToNot(HaveOccurred())
+				certPEM, keyPEM, err := pkiutil.SignCertificate(template, tc.algorithm, caCert, caKey)
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(pkiutil.CertCheck{CN: "test-cert", CaPEM: caPEM}.ValidateKeypair(certPEM, keyPEM)).To(Succeed())
+
+				cert, key, err := pkiutil.LoadCertificate(certPEM, keyPEM)
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(key).To(BeAssignableToTypeOf(tc.keyType))
+				g.Expect(cert.KeyUsage).To(Equal(tc.keyUsage))
+			})
+
+			t.Run("GenerateCSR", func(t *testing.T) {
+				g := NewWithT(t)
+
+				csrPEM, keyPEM, err := pkiutil.GenerateCSR(pkix.Name{CommonName: "test-csr"}, tc.algorithm, []string{"node1"}, []net.IP{net.ParseIP("10.0.0.1")})
+				g.Expect(err).ToNot(HaveOccurred())
+
+				csr, err := pkiutil.LoadCertificateRequest(csrPEM)
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(csr.CheckSignature()).To(Succeed())
+
+				key, err := pkiutil.LoadPrivateKey(keyPEM)
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(key).To(BeAssignableToTypeOf(tc.keyType))
+				g.Expect(pkiutil.LeafKeyUsage(csr.PublicKey)&x509.KeyUsageKeyEncipherment != 0).To(Equal(tc.keyUsage&x509.KeyUsageKeyEncipherment != 0))
+			})
+		})
+	}
+}
+
+func TestValidateKeypairMismatch(t *testing.T) {
+	g := NewWithT(t)
+	notBefore := time.Now()
+
+	certPEM, _, err := pkiutil.GenerateSelfSignedCA(pkix.Name{CommonName: "test-ca"}, notBefore, notBefore.AddDate(1, 0, 0), pkiutil.KeyAlgorithmECDSAP256)
+	g.Expect(err).ToNot(HaveOccurred())
+	_, keyPEM, err := pkiutil.GenerateSelfSignedCA(pkix.Name{CommonName: "test-ca"}, notBefore, notBefore.AddDate(1, 0, 0), pkiutil.KeyAlgorithmECDSAP256)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	g.Expect(pkiutil.CertCheck{AllowSelfSigned: true}.ValidateKeypair(certPEM, keyPEM)).ToNot(Succeed())
+}
+
+func TestLoadRSAPrivateKey(t *testing.T) {
+	g := NewWithT(t)
+
+	key, err := pkiutil.GenerateKey(pkiutil.KeyAlgorithmEd25519)
+	g.Expect(err).ToNot(HaveOccurred())
+	keyPEM, err := pkiutil.EncodePrivateKey(key)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	_, err = pkiutil.LoadRSAPrivateKey(keyPEM)
+	g.Expect(err).To(HaveOccurred())
+}
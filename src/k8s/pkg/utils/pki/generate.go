@@ -1,6 +1,7 @@
 package pkiutil
 
 import (
+	"crypto"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
@@ -9,6 +10,7 @@ import (
 	"fmt"
 	"math/big"
 	"net"
+	"slices"
 	"time"
 )
 
@@ -33,8 +35,8 @@ func GenerateCertificate(subject pkix.Name, notBefore, notAfter time.Time, ca bo
 		Subject:               subject,
 		NotBefore:             notBefore,
 		NotAfter:              notAfter,
-		IPAddresses:           ipSANs,
-		DNSNames:              dnsSANs,
+		IPAddresses:           uniqueIPs(ipSANs),
+		DNSNames:              uniqueDNSNames(dnsSANs),
 		BasicConstraintsValid: true,
 		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
 	}
@@ -49,48 +51,62 @@ func GenerateCertificate(subject pkix.Name, notBefore, notAfter time.Time, ca bo
 	return cert, nil
 }
 
-func GenerateSelfSignedCA(subject pkix.Name, notBefore time.Time, notAfter time.Time, bits int) (string, string, error) {
-	cert, err := GenerateCertificate(subject, notBefore, notAfter, true, nil, nil)
-	if err != nil {
-		return "", "", fmt.Errorf("failed to generate certificate: %w", err)
+// uniqueIPs returns the IP addresses without duplicates, keeping the first occurrence of each address.
+func uniqueIPs(ips []net.IP) []net.IP {
+	var result []net.IP
+	for _, ip := range ips {
+		if !slices.ContainsFunc(result, ip.Equal) {
+			result = append(result, ip)
+		}
 	}
+	return result
+}
 
-	key, err := rsa.GenerateKey(rand.Reader, bits)
-	if err != nil {
-		return "", "", fmt.Errorf("failed to generate RSA private key: %w", err)
+// uniqueDNSNames returns the DNS names without duplicates, keeping the first occurrence of each name.
+func uniqueDNSNames(names []string) []string {
+	var result []string
+	for _, name := range names {
+		if !slices.Contains(result, name) {
+			result = append(result, name)
+		}
 	}
-	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
-	if keyPEM == nil {
-		return "", "", fmt.Errorf("failed to encode private key PEM")
-	}
-	derBytes, err := x509.CreateCertificate(rand.Reader, cert, cert, &key.PublicKey, key)
+	return result
+}
+
+// GenerateSelfSignedCA generates a self-signed CA certificate and a private key with the specified key algorithm.
+func GenerateSelfSignedCA(subject pkix.Name, notBefore time.Time, notAfter time.Time, algorithm KeyAlgorithm) (string, string, error) {
+	cert, err := GenerateCertificate(subject, notBefore, notAfter, true, nil, nil)
 	if err != nil {
-		return "", "", fmt.Errorf("failed to self-sign certificate: %w", err)
-	}
-	crtPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: derBytes})
-	if crtPEM == nil {
-		return "", "", fmt.Errorf("failed to encode certificate PEM")
+		return "", "", fmt.Errorf("failed to generate certificate: %w", err)
 	}
 
-	return string(crtPEM), string(keyPEM), nil
+	return SignCertificate(cert, algorithm, cert, nil)
 }
 
-func SignCertificate(certificate *x509.Certificate, bits int, parent *x509.Certificate, pub any, priv any) (string, string, error) {
-	key, err := rsa.GenerateKey(rand.Reader, bits)
+// SignCertificate generates a private key with the specified key algorithm and signs the certificate for it.
+// The certificate is signed by the parent certificate and its private key priv. If priv is nil, the certificate
+// is self-signed with the generated key instead.
+// The key encipherment usages of the certificate are dropped if the generated key is not an RSA key.
+func SignCertificate(certificate *x509.Certificate, algorithm KeyAlgorithm, parent *x509.Certificate, priv crypto.Signer) (string, string, error) {
+	key, err := GenerateKey(algorithm)
 	if err != nil {
-		return "", "", fmt.Errorf("failed to generate RSA private key: %w", err)
+		return "", "", fmt.Errorf("failed to generate private key: %w", err)
 	}
-	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
-	if keyPEM == nil {
-		return "", "", fmt.Errorf("failed to encode private key PEM")
+	keyPEM, err := EncodePrivateKey(key)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to encode private key: %w", err)
 	}
 
-	if pub == nil && priv == nil {
+	if priv == nil {
 		priv = key
-		pub = &key.PublicKey
 	}
 
-	derBytes, err := x509.CreateCertificate(rand.Reader, certificate, parent, &key.PublicKey, priv)
+	template := *certificate
+	if _, ok := key.(*rsa.PrivateKey); !ok {
+		template.KeyUsage &^= x509.KeyUsageKeyEncipherment | x509.KeyUsageDataEncipherment
+	}
+
+	derBytes, err := x509.CreateCertificate(rand.Reader, &template, parent, key.Public(), priv)
 	if err != nil {
 		return "", "", fmt.Errorf("failed to sign certificate: %w", err)
 	}
@@ -99,7 +115,7 @@ func SignCertificate(certificate *x509.Certificate, bits int, parent *x509.Certi
 		return "", "", fmt.Errorf("failed to encode certificate PEM")
 	}
 
-	return string(crtPEM), string(keyPEM), nil
+	return string(crtPEM), keyPEM, nil
 }
 
 func GenerateRSAKey(bits int) (string, string, error) {
@@ -124,14 +140,15 @@ func GenerateRSAKey(bits int) (string, string, error) {
 }
 
 // GenerateCSR generates a certificate signing request (CSR) and private key for the given subject.
-func GenerateCSR(subject pkix.Name, bits int, dnsSANs []string, ipSANs []net.IP) (string, string, error) {
-	key, err := rsa.GenerateKey(rand.Reader, bits)
+// The private key is generated with the specified key algorithm.
+func GenerateCSR(subject pkix.Name, algorithm KeyAlgorithm, dnsSANs []string, ipSANs []net.IP) (string, string, error) {
+	key, err := GenerateKey(algorithm)
 	if err != nil {
-		return "", "", fmt.Errorf("failed to generate RSA private key: %w", err)
+		return "", "", fmt.Errorf("failed to generate private key: %w", err)
 	}
-	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
-	if keyPEM == nil {
-		return "", "", fmt.Errorf("failed to encode private key PEM")
+	keyPEM, err := EncodePrivateKey(key)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to encode private key: %w", err)
 	}
 
 	csrTemplate := &x509.CertificateRequest{
@@ -150,5 +167,5 @@ func GenerateCSR(subject pkix.Name, bits int, dnsSANs []string, ipSANs []net.IP)
 		return "", "", fmt.Errorf("failed to encode certificate request PEM")
 	}
 
-	return string(csrPEM), string(keyPEM), nil
+	return string(csrPEM), keyPEM, nil
 }
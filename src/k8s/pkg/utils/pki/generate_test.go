@@ -2,6 +2,7 @@ package pkiutil_test
 
 import (
 	"crypto/x509/pkix"
+	"net"
 	"testing"
 	"time"
 
@@ -11,7 +12,7 @@ import (
 
 func TestGenerateSelfSignedCA(t *testing.T) {
 	notBefore := time.Now()
-	cert, key, err := pkiutil.GenerateSelfSignedCA(pkix.Name{CommonName: "test-cert"}, notBefore, notBefore.AddDate(10, 0, 0), 2048)
+	cert, key, err := pkiutil.GenerateSelfSignedCA(pkix.Name{CommonName: "test-cert"}, notBefore, notBefore.AddDate(10, 0, 0), pkiutil.KeyAlgorithmRSA2048)
 
 	g := NewWithT(t)
 	g.Expect(err).To(Not(HaveOccurred()))
@@ -32,3 +33,20 @@ func TestGenerateSelfSignedCA(t *testing.T) {
 		g.Expect(key).To(BeNil())
 	})
 }
+
+func TestGenerateCertificate(t *testing.T) {
+	g := NewWithT(t)
+
+	notBefore := time.Now()
+	cert, err := pkiutil.GenerateCertificate(
+		pkix.Name{CommonName: "test-cert"},
+		notBefore,
+		notBefore.AddDate(1, 0, 0),
+		false,
+		[]string{"node1", "kubernetes", "node1"},
+		[]net.IP{net.ParseIP("10.0.0.1"), net.ParseIP("127.0.0.1"), net.ParseIP("10.0.0.1").To4()},
+	)
+	g.Expect(err).To(Not(HaveOccurred()))
+	g.Expect(cert.DNSNames).To(Equal([]string{"node1", "kubernetes"}))
+	g.Expect(cert.IPAddresses).To(HaveLen(2))
+}
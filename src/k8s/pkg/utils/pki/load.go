@@ -1,6 +1,7 @@
 package pkiutil
 
 import (
+	"crypto"
 	"crypto/rsa"
 	"crypto/x509"
 	"encoding/pem"
@@ -12,7 +13,7 @@ import (
 // LoadCertificate parses the PEM blocks and returns the certificate and private key.
 // LoadCertificate will fail if certPEM is not a valid certificate.
 // LoadCertificate will return a nil private key if keyPEM is empty, but will fail if it is not valid.
-func LoadCertificate(certPEM string, keyPEM string) (*x509.Certificate, *rsa.PrivateKey, error) {
+func LoadCertificate(certPEM string, keyPEM string) (*x509.Certificate, crypto.Signer, error) {
 	decodedCert, _ := pem.Decode([]byte(certPEM))
 	if decodedCert == nil {
 		return nil, nil, fmt.Errorf("failed to parse certificate PEM")
@@ -25,16 +26,43 @@ func LoadCertificate(certPEM string, keyPEM string) (*x509.Certificate, *rsa.Pri
 		return cert, nil, nil
 	}
 
-	key, err := LoadRSAPrivateKey(keyPEM)
+	key, err := LoadPrivateKey(keyPEM)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to load RSA private key: %w", err)
+		return nil, nil, fmt.Errorf("failed to load private key: %w", err)
 	}
 
 	return cert, key, nil
 }
 
-// LoadRSAPrivateKey parses the specified PEM block and return the rsa.PrivateKey.
-func LoadRSAPrivateKey(keyPEM string) (*rsa.PrivateKey, error) {
+// LoadCertificateChain parses all certificates of the PEM bundle, in the order they appear.
+// LoadCertificateChain will fail if chainPEM contains no certificates.
+func LoadCertificateChain(chainPEM string) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	rest := []byte(chainPEM)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse certificate %d: %w", len(certs), err)
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("no certificates found in PEM")
+	}
+	return certs, nil
+}
+
+// LoadPrivateKey parses the specified PEM block and returns the private key.
+// LoadPrivateKey supports RSA, ECDSA and Ed25519 keys in PKCS#1, SEC 1 and PKCS#8 format.
+func LoadPrivateKey(keyPEM string) (crypto.Signer, error) {
 	pb, _ := pem.Decode([]byte(keyPEM))
 	if pb == nil {
 		return nil, fmt.Errorf("failed to parse PEM block")
@@ -46,20 +74,39 @@ func LoadRSAPrivateKey(keyPEM string) (*rsa.PrivateKey, error) {
 			return nil, fmt.Errorf("failed to parse RSA private key: %w", err)
 		}
 		return key, nil
+	case "EC PRIVATE KEY":
+		key, err := x509.ParseECPrivateKey(pb.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse EC private key: %w", err)
+		}
+		return key, nil
 	case "PRIVATE KEY":
 		parsed, err := x509.ParsePKCS8PrivateKey(pb.Bytes)
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse private key: %w", err)
 		}
-		v, ok := parsed.(*rsa.PrivateKey)
+		key, ok := parsed.(crypto.Signer)
 		if !ok {
-			return nil, fmt.Errorf("not an RSA private key")
+			return nil, fmt.Errorf("unsupported private key type %T", parsed)
 		}
-		return v, nil
+		return key, nil
 	}
 	return nil, fmt.Errorf("unknown private key block type %q", pb.Type)
 }
 
+// LoadRSAPrivateKey parses the specified PEM block and return the rsa.PrivateKey.
+func LoadRSAPrivateKey(keyPEM string) (*rsa.PrivateKey, error) {
+	key, err := LoadPrivateKey(keyPEM)
+	if err != nil {
+		return nil, err
+	}
+	v, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("not an RSA private key")
+	}
+	return v, nil
+}
+
 // LoadRSAPublicKey parses the specified PEM block and return the rsa.PublicKey.
 func LoadRSAPublicKey(keyPEM string) (*rsa.PublicKey, error) {
 	pb, _ := pem.Decode([]byte(keyPEM))
@@ -100,7 +147,7 @@ func LoadCertificateRequest(csrPEM string) (*x509.CertificateRequest, error) {
 // loadCertificatePairFromDir reads the certificate and corresponding private
 // key files for the given certificate name from the specified directory. It
 // expects the files to be named "<name>.crt" and "<name>.key".
-func LoadCertificatePairFromDir(baseDir string, name string) (*x509.Certificate, *rsa.PrivateKey, error) {
+func LoadCertificatePairFromDir(baseDir string, name string) (*x509.Certificate, crypto.Signer, error) {
 	certBytes, err := os.ReadFile(filepath.Join(baseDir, fmt.Sprintf("%s.crt", name)))
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to read %s.crt: %w", name, err)
@@ -1,6 +1,8 @@
 package pkiutil
 
 import (
+	"bytes"
+	"crypto"
 	"crypto/x509"
 	"fmt"
 	"slices"
@@ -22,10 +24,15 @@ type CertCheck struct {
 }
 
 func (check CertCheck) ValidateKeypair(certPEM string, keyPEM string) error {
-	cert, _, err := LoadCertificate(certPEM, keyPEM)
+	cert, key, err := LoadCertificate(certPEM, keyPEM)
 	if err != nil {
 		return fmt.Errorf("failed to parse certificate: %w", err)
 	}
+	if key != nil {
+		if pub, ok := key.Public().(interface{ Equal(crypto.PublicKey) bool }); !ok || !pub.Equal(cert.PublicKey) {
+			return fmt.Errorf("private key does not match the certificate public key")
+		}
+	}
 
 	return check.ValidateCert(cert)
 }
@@ -80,3 +87,45 @@ func (check CertCheck) ValidateCert(cert *x509.Certificate) error {
 
 	return nil
 }
+
+// IsSelfSigned returns true if the certificate is signed by its own key.
+func IsSelfSigned(cert *x509.Certificate) bool {
+	return bytes.Equal(cert.RawIssuer, cert.RawSubject) && cert.CheckSignatureFrom(cert) == nil
+}
+
+// VerifyCertificateChain verifies the first certificate of the PEM bundle against the certificates that follow it.
+// If the first certificate is not self-signed (e.g. an intermediate CA), the bundle must contain its chain up to a
+// self-signed root certificate. Self-signed certificates are trusted as is.
+func VerifyCertificateChain(chainPEM string) error {
+	certs, err := LoadCertificateChain(chainPEM)
+	if err != nil {
+		return fmt.Errorf("failed to parse certificate chain: %w", err)
+	}
+	if IsSelfSigned(certs[0]) {
+		return nil
+	}
+
+	roots := x509.NewCertPool()
+	intermediates := x509.NewCertPool()
+	var hasRoot bool
+	for _, cert := range certs[1:] {
+		if IsSelfSigned(cert) {
+			roots.AddCert(cert)
+			hasRoot = true
+		} else {
+			intermediates.AddCert(cert)
+		}
+	}
+	if !hasRoot {
+		return fmt.Errorf("certificate %q is not self-signed and the chain does not include a root certificate", certs[0].Subject.CommonName)
+	}
+
+	if _, err := certs[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	}); err != nil {
+		return fmt.Errorf("failed to verify certificate chain: %w", err)
+	}
+	return nil
+}
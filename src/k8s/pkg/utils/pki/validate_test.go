@@ -0,0 +1,59 @@
+package pkiutil_test
+
+import (
+	"crypto/x509/pkix"
+	"testing"
+	"time"
+
+	pkiutil "github.com/canonical/k8s/pkg/utils/pki"
+	. "github.com/onsi/gomega"
+)
+
+func TestVerifyCertificateChain(t *testing.T) {
+	g := NewWithT(t)
+	notBefore := time.Now()
+	notAfter := notBefore.AddDate(1, 0, 0)
+
+	rootPEM, rootKeyPEM, err := pkiutil.GenerateSelfSignedCA(pkix.Name{CommonName: "root-ca"}, notBefore, notAfter, pkiutil.KeyAlgorithmRSA2048)
+	g.Expect(err).ToNot(HaveOccurred())
+	rootCert, rootKey, err := pkiutil.LoadCertificate(rootPEM, rootKeyPEM)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	template, err := pkiutil.GenerateCertificate(pkix.Name{CommonName: "intermediate-ca"}, notBefore, notAfter, true, nil, nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	intermediatePEM, _, err := pkiutil.SignCertificate(template, pkiutil.KeyAlgorithmECDSAP256, rootCert, rootKey)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	otherRootPEM, _, err := pkiutil.GenerateSelfSignedCA(pkix.Name{CommonName: "other-root-ca"}, notBefore, notAfter, pkiutil.KeyAlgorithmRSA2048)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	for _, tc := range []struct {
+		name      string
+		chainPEM  string
+		expectErr bool
+	}{
+		{name: "SelfSigned", chainPEM: rootPEM},
+		{name: "SelfSignedBundle", chainPEM: rootPEM + otherRootPEM},
+		{name: "Intermediate", chainPEM: intermediatePEM + rootPEM},
+		{name: "IntermediateWithoutRoot", chainPEM: intermediatePEM, expectErr: true},
+		{name: "IntermediateWrongRoot", chainPEM: intermediatePEM + otherRootPEM, expectErr: true},
+		{name: "Empty", chainPEM: "", expectErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			err := pkiutil.VerifyCertificateChain(tc.chainPEM)
+			if tc.expectErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+		})
+	}
+
+	certs, err := pkiutil.LoadCertificateChain(intermediatePEM + rootPEM)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(certs).To(HaveLen(2))
+	g.Expect(pkiutil.IsSelfSigned(certs[0])).To(BeFalse())
+	g.Expect(pkiutil.IsSelfSigned(certs[1])).To(BeTrue())
+}